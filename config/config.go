@@ -19,6 +19,14 @@ type (
 		CORS
 		AuthSettings
 		ApiKeys
+		Admin
+		Invitations
+		Reminders
+		Trash
+		Blobstore
+		Attachments
+		Idempotency
+		Outbox
 	}
 
 	App struct {
@@ -55,6 +63,7 @@ type (
 
 	Kafka struct {
 		Brokers string `  env:"KAFKA_BROKERS"`
+		GroupId string `  env:"KAFKA_GROUP_ID" env-default:"todo-plus"`
 	}
 
 	CORS struct {
@@ -62,16 +71,68 @@ type (
 	}
 
 	AuthSettings struct {
-		Salt       string        `  env:"AUTH_SALT"`
-		SigningKey string        `  env:"JWT_SIGNING_KEY"`
-		TokenTTL   time.Duration `  env:"TOKEN_TTL"`
+		Salt            string        `  env:"AUTH_SALT"`
+		HashAlgorithm   string        `  env:"HASH_ALGORITHM" env-default:"argon2id"`
+		BcryptCost      int           `  env:"BCRYPT_COST"    env-default:"12"`
+		SigningKey      string        `  env:"JWT_SIGNING_KEY"`
+		SigningMethod   string        `  env:"JWT_SIGNING_METHOD" env-default:"HS256"`
+		SigningKeys     string        `  env:"JWT_KEYS"`
+		ActiveKeyId     string        `  env:"JWT_ACTIVE_KEY_ID"`
+		TokenTTL        time.Duration `  env:"TOKEN_TTL"`
+		RefreshTokenTTL time.Duration `  env:"REFRESH_TOKEN_TTL" env-default:"720h"`
 	}
 
 	ApiKeys struct {
-		AppId         string `  env:"APP_ID"`
-		AppKey        string `  env:"APP_KEY"`
-		PrivateAppId  string `  env:"PRIVATE_APP_ID"`
-		PrivateAppKey string `  env:"PRIVATE_APP_KEY"`
+		AppId              string        `  env:"APP_ID"`
+		AppKey             string        `  env:"APP_KEY"`
+		PrivateAppId       string        `  env:"PRIVATE_APP_ID"`
+		PrivateAppKey      string        `  env:"PRIVATE_APP_KEY"`
+		KeyRotationOverlap time.Duration `  env:"APP_KEY_ROTATION_OVERLAP" env-default:"24h"`
+	}
+
+	Admin struct {
+		Name     string `  env:"ADMIN_NAME" env-default:"Administrator"`
+		Username string `  env:"ADMIN_USERNAME"`
+		Password string `  env:"ADMIN_PASSWORD"`
+	}
+
+	Invitations struct {
+		TTL time.Duration `  env:"INVITATION_TTL" env-default:"168h"`
+	}
+
+	Reminders struct {
+		ScanInterval time.Duration `  env:"REMINDER_SCAN_INTERVAL" env-default:"1m"`
+		BatchSize    int           `  env:"REMINDER_BATCH_SIZE"    env-default:"100"`
+	}
+
+	Trash struct {
+		Retention     time.Duration `  env:"TRASH_RETENTION"      env-default:"720h"`
+		PurgeInterval time.Duration `  env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+	}
+
+	Blobstore struct {
+		Driver      string `  env:"BLOBSTORE_DRIVER"     env-default:"local"`
+		LocalPath   string `  env:"BLOBSTORE_LOCAL_PATH" env-default:"./data/attachments"`
+		S3Endpoint  string `  env:"BLOBSTORE_S3_ENDPOINT"`
+		S3Region    string `  env:"BLOBSTORE_S3_REGION"  env-default:"us-east-1"`
+		S3Bucket    string `  env:"BLOBSTORE_S3_BUCKET"`
+		S3AccessKey string `  env:"BLOBSTORE_S3_ACCESS_KEY"`
+		S3SecretKey string `  env:"BLOBSTORE_S3_SECRET_KEY"`
+	}
+
+	Attachments struct {
+		MaxSize      int64    `  env:"ATTACHMENT_MAX_SIZE"      env-default:"10485760"`
+		AllowedTypes []string `  env:"ATTACHMENT_ALLOWED_TYPES" env-default:"image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain"`
+	}
+
+	Idempotency struct {
+		TTL time.Duration `  env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	}
+
+	Outbox struct {
+		RelayInterval time.Duration `  env:"OUTBOX_RELAY_INTERVAL" env-default:"1s"`
+		BatchSize     int           `  env:"OUTBOX_BATCH_SIZE"     env-default:"100"`
+		Retention     time.Duration `  env:"OUTBOX_RETENTION"      env-default:"168h"`
 	}
 )
 
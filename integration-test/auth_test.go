@@ -166,3 +166,65 @@ func TestSignIn(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestRefreshAndSignOut tests refresh token rotation, reuse detection and sign out
+func TestRefreshAndSignOut(t *testing.T) {
+	userName := faker.Username()
+	userPassword := faker.Password()
+
+	userId, err := createTestUser(t, faker.FirstName(), userName, userPassword)
+	require.NoError(t, err, "Error creating user")
+	defer func() {
+		err := deleteTestUser(userId)
+		require.NoError(t, err, "Failed to delete test user")
+	}()
+
+	headers := map[string]string{
+		"Content-Type":          "application/json",
+		middleware.HeaderAppID:  appId,
+		middleware.HeaderAppKey: appKey,
+	}
+
+	resp := sendRequest(t, "POST", signInUrl, headers, map[string]interface{}{
+		"username": userName,
+		"password": userPassword,
+	})
+	jsonResponse := parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusOK, "Signed in successfully", jsonResponse)
+
+	data := jsonResponse["data"].(map[string]interface{})
+	refreshToken := data["refresh_token"].(string)
+	require.NotEmpty(t, refreshToken, "Refresh token should not be empty")
+
+	resp = sendRequest(t, "POST", refreshUrl, headers, map[string]interface{}{"refresh_token": refreshToken})
+	jsonResponse = parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusOK, "Tokens refreshed successfully", jsonResponse)
+
+	data = jsonResponse["data"].(map[string]interface{})
+	accessToken := data["token"].(string)
+	rotatedRefreshToken := data["refresh_token"].(string)
+	assert.NotEqual(t, refreshToken, rotatedRefreshToken, "Refresh token should be rotated")
+
+	resp = sendRequest(t, "POST", refreshUrl, headers, map[string]interface{}{"refresh_token": refreshToken})
+	jsonResponse = parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusUnauthorized, "Invalid refresh token", jsonResponse)
+
+	resp = sendRequest(t, "POST", refreshUrl, headers, map[string]interface{}{"refresh_token": rotatedRefreshToken})
+	jsonResponse = parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusUnauthorized, "Invalid refresh token", jsonResponse)
+
+	authHeaders := map[string]string{
+		"Content-Type":                 "application/json",
+		middleware.HeaderAppID:         appId,
+		middleware.HeaderAppKey:        appKey,
+		middleware.AuthorizationHeader: "Bearer " + accessToken,
+	}
+
+	resp = sendRequest(t, "POST", signOutUrl, authHeaders, nil)
+	jsonResponse = parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusOK, "Signed out successfully", jsonResponse)
+
+	resp = sendRequest(t, "GET", listUrl, authHeaders, nil)
+	jsonResponse = parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusUnauthorized, "Unauthorized", jsonResponse)
+}
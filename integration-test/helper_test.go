@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/berikulyBeket/todo-plus/config"
@@ -29,9 +30,17 @@ var (
 	appKey        = "123123"
 	privateAppId  = "456456"
 	privateAppKey = "456456"
+	adminUsername = "admin"
+	adminPassword = "admin_password"
+
+	adminTokenOnce sync.Once
+	adminToken     string
+	adminTokenErr  error
 
 	signUpUrl            string
 	signInUrl            string
+	refreshUrl           string
+	signOutUrl           string
 	listUrl              string
 	listSearchUrl        string
 	itemUrl              string
@@ -51,6 +60,8 @@ func init() {
 
 	signUpUrl = baseUrl + "/v1/auth/sign-up"
 	signInUrl = baseUrl + "/v1/auth/sign-in"
+	refreshUrl = baseUrl + "/v1/auth/refresh"
+	signOutUrl = baseUrl + "/v1/auth/sign-out"
 
 	listUrl = baseUrl + "/v1/api/lists/"
 	listSearchUrl = listUrl + "search"
@@ -318,8 +329,14 @@ func deleteTestUser(userId int) error {
 		return fmt.Errorf("Error creating request: %v", err)
 	}
 
+	token, err := getAdminToken()
+	if err != nil {
+		return err
+	}
+
 	req.Header.Set(middleware.HeaderAppID, privateAppId)
 	req.Header.Set(middleware.HeaderAppKey, privateAppKey)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer "+token)
 
 	client := http.DefaultClient
 	resp, err := client.Do(req)
@@ -343,8 +360,14 @@ func deleteTestList(listId int) error {
 		return fmt.Errorf("Error creating request: %v", err)
 	}
 
+	token, err := getAdminToken()
+	if err != nil {
+		return err
+	}
+
 	req.Header.Set(middleware.HeaderAppID, privateAppId)
 	req.Header.Set(middleware.HeaderAppKey, privateAppKey)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer "+token)
 
 	client := http.DefaultClient
 	resp, err := client.Do(req)
@@ -368,8 +391,14 @@ func deleteTestItem(itemId int) error {
 		return fmt.Errorf("Error creating request: %v", err)
 	}
 
+	token, err := getAdminToken()
+	if err != nil {
+		return err
+	}
+
 	req.Header.Set(middleware.HeaderAppID, privateAppId)
 	req.Header.Set(middleware.HeaderAppKey, privateAppKey)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer "+token)
 
 	client := http.DefaultClient
 	resp, err := client.Do(req)
@@ -384,3 +413,63 @@ func deleteTestItem(itemId int) error {
 
 	return nil
 }
+
+// getAdminToken signs in as the admin seeded from ADMIN_USERNAME and ADMIN_PASSWORD, the token is shared by all tests
+func getAdminToken() (string, error) {
+	adminTokenOnce.Do(func() {
+		reqBytes, err := json.Marshal(map[string]string{
+			"username": adminUsername,
+			"password": adminPassword,
+		})
+		if err != nil {
+			adminTokenErr = err
+			return
+		}
+
+		req, err := http.NewRequest("POST", signInUrl, bytes.NewBuffer(reqBytes))
+		if err != nil {
+			adminTokenErr = fmt.Errorf("Error creating request: %v", err)
+			return
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.HeaderAppID, appId)
+		req.Header.Set(middleware.HeaderAppKey, appKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			adminTokenErr = fmt.Errorf("Error sending request: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			adminTokenErr = fmt.Errorf("Unexpected status code: %v", resp.StatusCode)
+			return
+		}
+
+		var jsonResponse struct {
+			Data entity.TokenPair `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&jsonResponse); err != nil {
+			adminTokenErr = fmt.Errorf("Error decoding response body: %v", err)
+			return
+		}
+
+		adminToken = jsonResponse.Data.AccessToken
+	})
+
+	return adminToken, adminTokenErr
+}
+
+// getAdminHeaders returns the headers of a private API request made by the admin
+func getAdminHeaders(t *testing.T) map[string]string {
+	token, err := getAdminToken()
+	require.NoError(t, err, "Failed to sign in as admin")
+
+	return map[string]string{
+		middleware.HeaderAppID:         privateAppId,
+		middleware.HeaderAppKey:        privateAppKey,
+		middleware.AuthorizationHeader: "Bearer " + token,
+	}
+}
@@ -576,10 +576,7 @@ func TestDeleteItemByAdmin(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			headers := map[string]string{
-				middleware.HeaderAppID:  privateAppId,
-				middleware.HeaderAppKey: privateAppKey,
-			}
+			headers := getAdminHeaders(t)
 
 			url := deleteItemByAdminUrl + "/" + testCase.itemId
 			resp := sendRequest(t, "DELETE", url, headers, nil)
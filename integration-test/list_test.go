@@ -488,10 +488,7 @@ func TestDeleteListByAdmin(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			headers := map[string]string{
-				middleware.HeaderAppID:  privateAppId,
-				middleware.HeaderAppKey: privateAppKey,
-			}
+			headers := getAdminHeaders(t)
 
 			url := deleteListByAdminUrl + "/" + testCase.listId
 			resp := sendRequest(t, "DELETE", url, headers, nil)
@@ -48,10 +48,7 @@ func TestDeleteUserByAdmin(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			headers := map[string]string{
-				middleware.HeaderAppID:  privateAppId,
-				middleware.HeaderAppKey: privateAppKey,
-			}
+			headers := getAdminHeaders(t)
 
 			url := deleteUserByAdminUrl + "/" + testCase.userId
 			resp := sendRequest(t, "DELETE", url, headers, nil)
@@ -62,3 +59,23 @@ func TestDeleteUserByAdmin(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestDeleteUserByRegularUser tests that a user without the admin role cannot use the private API
+func TestDeleteUserByRegularUser(t *testing.T) {
+	userId, token := setupTestUser(t)
+	defer func() {
+		err := deleteTestUser(userId)
+		require.NoError(t, err, "Failed to delete test user")
+	}()
+
+	headers := map[string]string{
+		middleware.HeaderAppID:         privateAppId,
+		middleware.HeaderAppKey:        privateAppKey,
+		middleware.AuthorizationHeader: "Bearer " + token,
+	}
+
+	url := deleteUserByAdminUrl + "/" + strconv.Itoa(userId)
+	resp := sendRequest(t, "DELETE", url, headers, nil)
+	jsonResponse := parseResponse(t, resp)
+	assertResponse(t, resp, http.StatusForbidden, "Forbidden", jsonResponse)
+}
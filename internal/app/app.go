@@ -10,6 +10,7 @@ import (
 	"github.com/berikulyBeket/todo-plus/internal/consumer"
 	handler "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/internal/scheduler"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
 	"github.com/berikulyBeket/todo-plus/pkg/database"
@@ -60,17 +61,64 @@ func Run(cfg *config.Config) {
 	cache := cache.New(masterCache, replicaCache)
 	messageBroker := messagebroker.New(kafkaClient, logger)
 	searchService := search.New(elasticClient)
-	hasher, tokenMaker := initAuthServices(cfg.AuthSettings)
-	appAuth := initAppAuthService(cfg.ApiKeys)
+	hasher, tokenMaker, err := initAuthServices(cfg.AuthSettings)
+	if err != nil {
+		logger.Errorf("failed to initialize auth services: %v", err)
+		return
+	}
+
+	blobStore, err := initBlobStore(cfg.Blobstore)
+	if err != nil {
+		logger.Errorf("failed to initialize blob store: %v", err)
+		return
+	}
 
 	repos := repository.NewRepository(db, cache, logger)
-	usecases := usecase.NewUseCase(repos, searchService, messageBroker.Producer, hasher, tokenMaker, logger)
-	handlers := handler.NewHandler(usecases, appAuth, logger, metrics)
+	usecases := usecase.NewUseCase(
+		repos,
+		searchService,
+		messageBroker.Producer,
+		hasher,
+		tokenMaker,
+		cfg.ApiKeys.KeyRotationOverlap,
+		cfg.Invitations.TTL,
+		blobStore,
+		cfg.Attachments.MaxSize,
+		cfg.Attachments.AllowedTypes,
+		cfg.Idempotency.TTL,
+		logger,
+	)
+	if err := initAppRegistry(cfg.ApiKeys, usecases.App); err != nil {
+		logger.Errorf("failed to initialize app registry: %v", err)
+		return
+	}
+	if err := initAdmin(cfg.Admin, usecases.Auth); err != nil {
+		logger.Errorf("failed to initialize admin user: %v", err)
+		return
+	}
+
+	handlers := handler.NewHandler(usecases, usecases.App, logger, metrics)
 
 	consumers := consumer.New(messageBroker.Consumer, usecases, logger)
-	if err := consumers.Init(); err != nil {
+	if err := consumers.Start(); err != nil {
 		logger.Errorf("failed to start consumers: %v", err)
 	}
+	defer consumers.Stop()
+
+	jobs := scheduler.New(
+		usecases,
+		cfg.Reminders.ScanInterval,
+		cfg.Reminders.BatchSize,
+		cfg.Trash.PurgeInterval,
+		cfg.Trash.Retention,
+		cfg.Outbox.RelayInterval,
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.Retention,
+		metrics,
+		logger,
+	)
+	jobs.Start()
+	defer jobs.Stop()
 
 	router := initRouter(cfg, logger, metrics)
 
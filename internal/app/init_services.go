@@ -11,7 +11,9 @@ import (
 
 	"github.com/berikulyBeket/todo-plus/config"
 	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/pkg/blobstore"
 
 	"github.com/berikulyBeket/todo-plus/pkg/hash"
 	"github.com/berikulyBeket/todo-plus/pkg/kafka"
@@ -92,12 +94,15 @@ func initElasticClient(config config.Elastic) (*elasticsearch.Client, *http.Tran
 	return elasticClient, transport, nil
 }
 
-// initKafkaClient initializes a Kafka client with the provided brokers
+// initKafkaClient initializes a Kafka client with the provided brokers and consumer group
 func initKafkaClient(config config.Kafka) (*kafka.KafkaClient, error) {
 	kafkaConfig := sarama.NewConfig()
 	kafkaConfig.Producer.Return.Successes = true
+	// A group without committed offsets starts from the oldest messages so that nothing published before it is skipped
+	kafkaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	kafkaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategySticky()}
 
-	kafkaClient, err := kafka.New(strings.Split(config.Brokers, ","), kafkaConfig)
+	kafkaClient, err := kafka.New(strings.Split(config.Brokers, ","), config.GroupId, kafkaConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -105,17 +110,75 @@ func initKafkaClient(config config.Kafka) (*kafka.KafkaClient, error) {
 	return kafkaClient, err
 }
 
-// initAppAuthService initializes the application authentication service with the provided API keys
-func initAppAuthService(config config.ApiKeys) appauth.Interface {
-	return appauth.New(config.AppId, config.AppKey, config.PrivateAppId, config.PrivateAppKey)
+// initBlobStore initializes the blob store of attachments, either on the local filesystem or in an S3 compatible storage
+func initBlobStore(config config.Blobstore) (blobstore.Interface, error) {
+	switch config.Driver {
+	case "local":
+		return blobstore.NewLocalStore(config.LocalPath)
+	case "s3":
+		return blobstore.NewS3Store(blobstore.S3Options{
+			Endpoint:  config.S3Endpoint,
+			Region:    config.S3Region,
+			Bucket:    config.S3Bucket,
+			AccessKey: config.S3AccessKey,
+			SecretKey: config.S3SecretKey,
+		}, &http.Client{Timeout: time.Minute}), nil
+	default:
+		return nil, fmt.Errorf("unsupported blob store driver %q", config.Driver)
+	}
+}
+
+// initAppRegistry seeds the app registry with the app credentials from the configuration,
+// apps that are already registered are left untouched so keys rotated through the admin API are kept
+func initAppRegistry(config config.ApiKeys, apps usecase.App) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if config.AppId != "" && config.AppKey != "" {
+		if err := apps.EnsureApp(ctx, config.AppId, config.AppKey, "default public app", []string{appauth.PublicAccess}); err != nil {
+			return err
+		}
+	}
+
+	if config.PrivateAppId != "" && config.PrivateAppKey != "" {
+		if err := apps.EnsureApp(ctx, config.PrivateAppId, config.PrivateAppKey, "default private app", []string{appauth.PrivateAccess}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initAdmin creates the admin user from the configuration when the username is not taken yet,
+// further roles are assigned by that admin through the private API
+func initAdmin(config config.Admin, auth usecase.Auth) error {
+	if config.Username == "" || config.Password == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return auth.EnsureAdmin(ctx, config.Name, config.Username, config.Password)
 }
 
 // initAuthServices initializes the hashing and token services
-func initAuthServices(config config.AuthSettings) (hash.Hasher, token.TokenMaker) {
-	hasher := hash.New(config.Salt)
-	tokenMaker := token.New(config.SigningKey, config.TokenTTL)
+func initAuthServices(config config.AuthSettings) (hash.Hasher, token.TokenMaker, error) {
+	hasher, err := hash.New(config.HashAlgorithm, config.BcryptCost, config.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config.SigningMethod == token.AlgorithmHS256 {
+		return hasher, token.New(config.SigningKey, config.TokenTTL, config.RefreshTokenTTL), nil
+	}
+
+	keySet, err := token.LoadKeySet(config.SigningMethod, config.ActiveKeyId, config.SigningKeys)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return hasher, tokenMaker
+	return hasher, token.NewWithKeySet(keySet, config.TokenTTL, config.RefreshTokenTTL), nil
 }
 
 // initRouter initializes a new Gin router with middleware for logging, CORS, and request metrics tracking
@@ -1,7 +1,9 @@
 package consumer
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
@@ -13,6 +15,8 @@ type Consumer struct {
 	usecases       *usecase.UseCase
 	brokerConsumer messagebroker.Consumer
 	logger         logger.Interface
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
 }
 
 // New creates a new Consumer instance
@@ -28,8 +32,39 @@ func New(
 	}
 }
 
-// Init subscribes the consumer to various topics
-func (c *Consumer) Init() error {
+// Start subscribes the consumer to various topics and consumes them in the background until Stop is called
+func (c *Consumer) Start() error {
+	if err := c.subscribe(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if err := c.brokerConsumer.Run(ctx); err != nil {
+			c.logger.Errorf("failed to consume topics: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops consuming and waits for the messages being handled, the offsets of the handled messages are committed
+func (c *Consumer) Stop() {
+	if c.cancel == nil {
+		return
+	}
+
+	c.cancel()
+	c.wg.Wait()
+}
+
+// subscribe registers the handlers of the topics. A failed message is handled again until it succeeds or runs
+// out of attempts, so the handlers log and skip malformed messages that no retry could handle
+func (c *Consumer) subscribe() error {
 	if err := c.brokerConsumer.Subscribe(messagebroker.ListCreatedTopic, c.handleListCreated); err != nil {
 		return fmt.Errorf("failed to subscribe to %s: %w", messagebroker.ListCreatedTopic, err)
 	}
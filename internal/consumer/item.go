@@ -8,14 +8,14 @@ import (
 )
 
 // handleItemCreated processes the item created event
-func (c *Consumer) handleItemCreated(msgBytes []byte) error {
+func (c *Consumer) handleItemCreated(ctx context.Context, msgBytes []byte) error {
 	var message entity.ItemCreatedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.Item.HandleCreated(context.Background(), message); err != nil {
+	if err := c.usecases.Item.HandleCreated(ctx, message); err != nil {
 		c.logger.Errorf("Error handling item created event: %v", err)
 		return err
 	}
@@ -24,14 +24,14 @@ func (c *Consumer) handleItemCreated(msgBytes []byte) error {
 }
 
 // handleItemUpdated processes the item updated event
-func (c *Consumer) handleItemUpdated(msgBytes []byte) error {
+func (c *Consumer) handleItemUpdated(ctx context.Context, msgBytes []byte) error {
 	var message entity.ItemUpdatedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.Item.HandleUpdated(context.Background(), message); err != nil {
+	if err := c.usecases.Item.HandleUpdated(ctx, message); err != nil {
 		c.logger.Errorf("Error handling item updated event: %v", err)
 		return err
 	}
@@ -40,14 +40,14 @@ func (c *Consumer) handleItemUpdated(msgBytes []byte) error {
 }
 
 // handleItemDeleted processes the item deleted event
-func (c *Consumer) handleItemDeleted(msgBytes []byte) error {
+func (c *Consumer) handleItemDeleted(ctx context.Context, msgBytes []byte) error {
 	var message entity.ItemDeletedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.Item.HandleDeleted(context.Background(), message); err != nil {
+	if err := c.usecases.Item.HandleDeleted(ctx, message); err != nil {
 		c.logger.Errorf("Error handling item deleted event: %v", err)
 		return err
 	}
@@ -8,14 +8,14 @@ import (
 )
 
 // handleListCreated processes the list created event
-func (c *Consumer) handleListCreated(msgBytes []byte) error {
+func (c *Consumer) handleListCreated(ctx context.Context, msgBytes []byte) error {
 	var message entity.ListCreatedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.List.HandleCreated(context.Background(), message); err != nil {
+	if err := c.usecases.List.HandleCreated(ctx, message); err != nil {
 		c.logger.Errorf("Error handling list created event: %v", err)
 		return err
 	}
@@ -24,14 +24,14 @@ func (c *Consumer) handleListCreated(msgBytes []byte) error {
 }
 
 // handleListUpdated processes the list updated event
-func (c *Consumer) handleListUpdated(msgBytes []byte) error {
+func (c *Consumer) handleListUpdated(ctx context.Context, msgBytes []byte) error {
 	var message entity.ListUpdatedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.List.HandleUpdated(context.Background(), message); err != nil {
+	if err := c.usecases.List.HandleUpdated(ctx, message); err != nil {
 		c.logger.Errorf("Error handling list updated event: %v", err)
 		return err
 	}
@@ -40,14 +40,14 @@ func (c *Consumer) handleListUpdated(msgBytes []byte) error {
 }
 
 // handleListDeleted processes the list deleted event
-func (c *Consumer) handleListDeleted(msgBytes []byte) error {
+func (c *Consumer) handleListDeleted(ctx context.Context, msgBytes []byte) error {
 	var message entity.ListDeletedEvent
 	if err := json.Unmarshal(msgBytes, &message); err != nil {
 		c.logger.Errorf("Failed to unmarshal message: %v", err)
-		return err
+		return nil
 	}
 
-	if err := c.usecases.List.HandleDeleted(context.Background(), message); err != nil {
+	if err := c.usecases.List.HandleDeleted(ctx, message); err != nil {
 		c.logger.Errorf("Error handling list deleted event: %v", err)
 		return err
 	}
@@ -0,0 +1,299 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createApp godoc
+// @Summary Register an app
+// @Description Register a new application, the generated app key is shown only once
+// @Tags apps
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.CreateAppInput true "App name and access types"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.CreatedApp} "App created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create app"
+// @Router /private/api/apps/ [post]
+func (h *Handler) CreateApp(c *gin.Context) {
+	var input entity.CreateAppInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	app, err := h.Usecases.App.Create(c.Request.Context(), input)
+	if err != nil {
+		if err == utils.ErrInvalidAccessType {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		h.Logger.Errorf("failed to create app: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create app", map[string]string{
+			"database": "Error during app creation",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"app_id": app.AppId,
+	}).Info("app registered successfully")
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "App created successfully", app)
+}
+
+// getAllApps godoc
+// @Summary Get all apps
+// @Description Retrieve every registered application, app keys are never returned
+// @Tags apps
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.App} "Apps retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve apps"
+// @Router /private/api/apps/ [get]
+func (h *Handler) GetAllApps(c *gin.Context) {
+	apps, err := h.Usecases.App.GetAll(c.Request.Context())
+	if err != nil {
+		h.Logger.Errorf("failed to retrieve apps: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve apps", map[string]string{
+			"database": "Error during app retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Apps retrieved successfully", apps)
+}
+
+// getAppByAppId godoc
+// @Summary Get an app
+// @Description Retrieve a registered application by its app id
+// @Tags apps
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "App ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.App} "App retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "App not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve app"
+// @Router /private/api/apps/{id} [get]
+func (h *Handler) GetAppByAppId(c *gin.Context) {
+	appId := c.Param("id")
+
+	app, err := h.Usecases.App.GetOneByAppId(c.Request.Context(), appId)
+	if err != nil {
+		if err == utils.ErrAppNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "App not found", map[string]string{
+				"appId": "The requested app does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"app_id": appId,
+		}).Errorf("failed to retrieve app: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve app", map[string]string{
+			"database": "Error during app retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "App retrieved successfully", app)
+}
+
+// updateApp godoc
+// @Summary Update an app
+// @Description Rename an application, change its access types or enable and disable it
+// @Tags apps
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "App ID"
+// @Param input body entity.UpdateAppInput true "Updated app data"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "App updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "App not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update app"
+// @Router /private/api/apps/{id} [put]
+func (h *Handler) UpdateApp(c *gin.Context) {
+	appId := c.Param("id")
+
+	var input entity.UpdateAppInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"app_id": appId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err := h.Usecases.App.UpdateOneByAppId(c.Request.Context(), appId, input)
+	if err != nil {
+		switch err {
+		case utils.ErrAppEmptyRequest, utils.ErrInvalidAccessType:
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+		case utils.ErrAppNotFound:
+			utils.NewErrorResponse(c, http.StatusNotFound, "App not found", map[string]string{
+				"appId": "The requested app does not exist",
+			})
+		default:
+			h.Logger.WithFields(map[string]interface{}{
+				"app_id": appId,
+			}).Errorf("failed to update app: %s", err)
+
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update app", map[string]string{
+				"database": "Error during app update",
+			})
+		}
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"app_id": appId,
+	}).Info("app updated successfully")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "App updated successfully", nil)
+}
+
+// rotateAppKey godoc
+// @Summary Rotate an app key
+// @Description Issue a new app key, the previous key keeps working for the overlap window
+// @Tags apps
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "App ID"
+// @Param input body entity.RotateAppKeyInput false "Overlap window of the previous key"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.CreatedApp} "App key rotated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "App not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to rotate app key"
+// @Router /private/api/apps/{id}/rotate-key [post]
+func (h *Handler) RotateAppKey(c *gin.Context) {
+	appId := c.Param("id")
+
+	var input entity.RotateAppKeyInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			h.Logger.WithFields(map[string]interface{}{
+				"app_id": appId,
+			}).Errorf("failed to bind JSON: %s", err)
+
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+				"body": "Invalid or malformed JSON",
+			})
+			return
+		}
+	}
+
+	app, err := h.Usecases.App.RotateKey(c.Request.Context(), appId, input)
+	if err != nil {
+		switch err {
+		case utils.ErrInvalidOverlap:
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+		case utils.ErrAppNotFound:
+			utils.NewErrorResponse(c, http.StatusNotFound, "App not found", map[string]string{
+				"appId": "The requested app does not exist",
+			})
+		default:
+			h.Logger.WithFields(map[string]interface{}{
+				"app_id": appId,
+			}).Errorf("failed to rotate app key: %s", err)
+
+			utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to rotate app key", map[string]string{
+				"database": "Error during app key rotation",
+			})
+		}
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"app_id": appId,
+	}).Info("app key rotated successfully")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "App key rotated successfully", app)
+}
+
+// deleteApp godoc
+// @Summary Delete an app
+// @Description Remove an application from the registry, its keys stop working immediately
+// @Tags apps
+// @Security BearerAuth
+// @Param id path string true "App ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "App deleted successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "App not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to delete app"
+// @Router /private/api/apps/{id} [delete]
+func (h *Handler) DeleteApp(c *gin.Context) {
+	appId := c.Param("id")
+
+	err := h.Usecases.App.DeleteOneByAppId(c.Request.Context(), appId)
+	if err != nil {
+		if err == utils.ErrAppNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "App not found", map[string]string{
+				"appId": "The requested app does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"app_id": appId,
+		}).Errorf("failed to delete app: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to delete app", map[string]string{
+			"database": "Error during app deletion",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"app_id": appId,
+	}).Info("app deleted successfully")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "App deleted successfully", nil)
+}
@@ -0,0 +1,279 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupAppRouter registers the app registry handlers
+func setupAppRouter(mockApp *MockApp) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		App: mockApp,
+	}
+
+	handler := v1.NewHandler(mockUseCase, mockApp, &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.POST("/private/api/apps/", handler.CreateApp)
+	r.PUT("/private/api/apps/:id", handler.UpdateApp)
+	r.POST("/private/api/apps/:id/rotate-key", handler.RotateAppKey)
+
+	return r
+}
+
+// TestHandler_CreateApp tests the CreateApp handler
+func TestHandler_CreateApp(t *testing.T) {
+	createdAt := time.Date(2024, 10, 21, 9, 0, 0, 0, time.UTC)
+	input := entity.CreateAppInput{Name: "cli", AccessTypes: []string{"public"}}
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockApp *MockApp)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"name": "cli", "access_types": ["public"]}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("Create", mock.Anything, input).Return(entity.CreatedApp{
+					App:    entity.App{Id: 3, AppId: "abc", Name: "cli", AccessTypes: input.AccessTypes, Enabled: true, KeyHash: "hash", CreatedAt: createdAt},
+					AppKey: "secret",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "App created successfully",
+				"data": {
+					"id": 3,
+					"app_id": "abc",
+					"name": "cli",
+					"access_types": ["public"],
+					"enabled": true,
+					"previous_key_expires_at": null,
+					"last_used_at": null,
+					"created_at": "2024-10-21T09:00:00Z",
+					"app_key": "secret"
+				}
+			}`,
+		},
+		{
+			name:           "Missing access types",
+			input:          `{"name": "cli"}`,
+			mockBehavior:   func(mockApp *MockApp) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:  "Internal server error",
+			input: `{"name": "cli", "access_types": ["public"]}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("Create", mock.Anything, input).Return(entity.CreatedApp{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create app",
+				"errors": {"database": "Error during app creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockApp := new(MockApp)
+			r := setupAppRouter(mockApp)
+
+			req := httptest.NewRequest("POST", "/private/api/apps/", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockApp)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_UpdateApp tests the UpdateApp handler
+func TestHandler_UpdateApp(t *testing.T) {
+	enabled := false
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockApp *MockApp)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Disable app",
+			input: `{"enabled": false}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("UpdateOneByAppId", mock.Anything, "abc", entity.UpdateAppInput{Enabled: &enabled}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "App updated successfully"}`,
+		},
+		{
+			name:  "Empty update",
+			input: `{}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("UpdateOneByAppId", mock.Anything, "abc", entity.UpdateAppInput{}).Return(utils.ErrAppEmptyRequest)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "app update structure has no values"}
+			}`,
+		},
+		{
+			name:  "Not found",
+			input: `{"enabled": false}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("UpdateOneByAppId", mock.Anything, "abc", entity.UpdateAppInput{Enabled: &enabled}).Return(utils.ErrAppNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "App not found",
+				"errors": {"appId": "The requested app does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockApp := new(MockApp)
+			r := setupAppRouter(mockApp)
+
+			req := httptest.NewRequest("PUT", "/private/api/apps/abc", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockApp)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RotateAppKey tests the RotateAppKey handler
+func TestHandler_RotateAppKey(t *testing.T) {
+	createdAt := time.Date(2024, 10, 21, 9, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2024, 10, 22, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockApp *MockApp)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"overlap": "24h"}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("RotateKey", mock.Anything, "abc", entity.RotateAppKeyInput{Overlap: "24h"}).Return(entity.CreatedApp{
+					App:    entity.App{Id: 3, AppId: "abc", Name: "cli", AccessTypes: []string{"public"}, Enabled: true, PreviousKeyExpiresAt: &expiresAt, CreatedAt: createdAt},
+					AppKey: "newSecret",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "App key rotated successfully",
+				"data": {
+					"id": 3,
+					"app_id": "abc",
+					"name": "cli",
+					"access_types": ["public"],
+					"enabled": true,
+					"previous_key_expires_at": "2024-10-22T09:00:00Z",
+					"last_used_at": null,
+					"created_at": "2024-10-21T09:00:00Z",
+					"app_key": "newSecret"
+				}
+			}`,
+		},
+		{
+			name:  "Invalid overlap",
+			input: `{"overlap": "tomorrow"}`,
+			mockBehavior: func(mockApp *MockApp) {
+				mockApp.On("RotateKey", mock.Anything, "abc", entity.RotateAppKeyInput{Overlap: "tomorrow"}).Return(entity.CreatedApp{}, utils.ErrInvalidOverlap)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "invalid key rotation overlap"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockApp := new(MockApp)
+			r := setupAppRouter(mockApp)
+
+			req := httptest.NewRequest("POST", "/private/api/apps/abc/rotate-key", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockApp)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockApp.AssertExpectations(t)
+		})
+	}
+}
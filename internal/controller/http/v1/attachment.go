@@ -0,0 +1,328 @@
+package v1
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room left in upload requests for the multipart boundaries and headers
+const multipartOverhead = 1 << 20
+
+// uploadAttachment godoc
+// @Summary Attach a file to an item
+// @Description Upload a file as multipart form data in the file field to an item of a list the user can edit.
+// @Description The size and the content type, detected from the content, are limited by the server configuration
+// @Tags attachments
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param file formData file true "File to attach"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.Attachment} "Attachment uploaded successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 413 {object} utils.ErrorResponse "File too large"
+// @Failure 415 {object} utils.ErrorResponse "File type not allowed"
+// @Failure 500 {object} utils.ErrorResponse "Failed to upload attachment"
+// @Router /api/items/{id}/attachments [post]
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.Usecases.Attachment.MaxSize()+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.attachmentErrorResponse(c, utils.ErrAttachmentTooLarge)
+			return
+		}
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"file": "A file must be uploaded in the file field of a multipart form",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to open uploaded file: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to upload attachment", map[string]string{
+			"file": "Error reading the uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	input := entity.UploadAttachmentInput{
+		Name:    fileHeader.Filename,
+		Size:    fileHeader.Size,
+		Content: file,
+	}
+
+	attachment, err := h.Usecases.Attachment.Upload(c.Request.Context(), userId, itemId, input)
+	if err != nil {
+		if h.attachmentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to upload attachment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to upload attachment", map[string]string{
+			"storage": "Error during attachment upload",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// getAttachments godoc
+// @Summary Get the attachments of an item
+// @Description Retrieve the files attached to an item of a list shared with the user in upload order
+// @Tags attachments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.Attachment} "Attachments retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve attachments"
+// @Router /api/items/{id}/attachments [get]
+func (h *Handler) GetAttachments(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	attachments, err := h.Usecases.Attachment.GetAll(c.Request.Context(), userId, itemId)
+	if err != nil {
+		if h.attachmentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to get attachments: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve attachments", map[string]string{
+			"database": "Error retrieving attachments",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// downloadAttachment godoc
+// @Summary Download an attachment
+// @Description Download the content of a file attached to an item of a list shared with the user
+// @Tags attachments
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param id path int true "Item ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {file} file "Attachment content"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item or attachment not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to download attachment"
+// @Router /api/items/{id}/attachments/{attachmentId} [get]
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, attachmentId, ok := parseAttachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.Usecases.Attachment.Download(c.Request.Context(), userId, itemId, attachmentId)
+	if err != nil {
+		if h.attachmentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":       userId,
+			"item_id":       itemId,
+			"attachment_id": attachmentId,
+		}).Errorf("failed to download attachment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to download attachment", map[string]string{
+			"storage": "Error during attachment download",
+		})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// deleteAttachment godoc
+// @Summary Delete an attachment
+// @Description Delete a file attached to an item of a list the user can edit
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path int true "Item ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Attachment deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item or attachment not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to delete attachment"
+// @Router /api/items/{id}/attachments/{attachmentId} [delete]
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, attachmentId, ok := parseAttachmentParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Attachment.DeleteOneById(c.Request.Context(), userId, itemId, attachmentId)
+	if err != nil {
+		if h.attachmentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":       userId,
+			"item_id":       itemId,
+			"attachment_id": attachmentId,
+		}).Errorf("failed to delete attachment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to delete attachment", map[string]string{
+			"database": "Error during attachment deletion",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Attachment deleted successfully", nil)
+}
+
+// attachmentErrorResponse writes the response of the known attachment errors and reports whether it did
+func (h *Handler) attachmentErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidAttachmentFileName, utils.ErrAttachmentEmpty:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrAttachmentTooLarge:
+		utils.NewErrorResponse(c, http.StatusRequestEntityTooLarge, "File too large", map[string]string{
+			"file": err.Error(),
+		})
+	case utils.ErrAttachmentTypeNotAllowed:
+		utils.NewErrorResponse(c, http.StatusUnsupportedMediaType, "File type not allowed", map[string]string{
+			"file": err.Error(),
+		})
+	case utils.ErrListPermissionDenied:
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+			"role": err.Error(),
+		})
+	case utils.ErrUserNotOwner, utils.ErrItemNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+			"itemId": "The requested item does not exist",
+		})
+	case utils.ErrAttachmentNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Attachment not found", map[string]string{
+			"attachmentId": "The requested attachment does not exist for this item",
+		})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// parseAttachmentParams parses the item and attachment path params, a 400 response is written when one is invalid
+func parseAttachmentParams(c *gin.Context) (int, int, bool) {
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	attachmentId, err := strconv.Atoi(c.Param("attachmentId"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid attachmentId param", map[string]string{
+			"param": "attachmentId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return itemId, attachmentId, true
+}
@@ -0,0 +1,456 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupAttachmentRouter registers the attachment handlers with an authenticated user
+func setupAttachmentRouter(mockAttachment *MockAttachment, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Attachment: mockAttachment,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/items/:id/attachments", handler.UploadAttachment)
+	r.GET("/api/items/:id/attachments", handler.GetAttachments)
+	r.GET("/api/items/:id/attachments/:attachmentId", handler.DownloadAttachment)
+	r.DELETE("/api/items/:id/attachments/:attachmentId", handler.DeleteAttachment)
+
+	return r
+}
+
+// multipartFile builds a multipart form body holding a file in the given field
+func multipartFile(field, fileName, content string) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	part, _ := writer.CreateFormFile(field, fileName)
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	return body, writer.FormDataContentType()
+}
+
+// TestHandler_UploadAttachment tests the UploadAttachment handler
+func TestHandler_UploadAttachment(t *testing.T) {
+	createdAt := time.Date(2024, 11, 25, 9, 0, 0, 0, time.UTC)
+	userId := 1
+
+	uploadInput := mock.MatchedBy(func(input entity.UploadAttachmentInput) bool {
+		return input.Name == "notes.txt" && input.Size == 5
+	})
+
+	testCases := []struct {
+		name           string
+		field          string
+		content        string
+		mockBehavior   func(mockAttachment *MockAttachment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			field:   "file",
+			content: "hello",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Upload", mock.Anything, 1, 5, uploadInput).Return(entity.Attachment{
+					Id:          3,
+					ItemId:      5,
+					UserId:      &userId,
+					Name:        "notes.txt",
+					ContentType: "text/plain",
+					Size:        5,
+					BlobKey:     "items/5/abc",
+					CreatedAt:   createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Attachment uploaded successfully",
+				"data": {
+					"id": 3,
+					"item_id": 5,
+					"user_id": 1,
+					"name": "notes.txt",
+					"content_type": "text/plain",
+					"size": 5,
+					"created_at": "2024-11-25T09:00:00Z"
+				}
+			}`,
+		},
+		{
+			name:           "Missing file field",
+			field:          "upload",
+			content:        "hello",
+			mockBehavior:   func(mockAttachment *MockAttachment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"file": "A file must be uploaded in the file field of a multipart form"}
+			}`,
+		},
+		{
+			name:           "Request body over the limit",
+			field:          "file",
+			content:        strings.Repeat("a", 2<<20),
+			mockBehavior:   func(mockAttachment *MockAttachment) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody: `{
+				"status": "error",
+				"message": "File too large",
+				"errors": {"file": "` + utils.ErrAttachmentTooLarge.Error() + `"}
+			}`,
+		},
+		{
+			name:    "Type not allowed",
+			field:   "file",
+			content: "hello",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Upload", mock.Anything, 1, 5, uploadInput).Return(entity.Attachment{}, utils.ErrAttachmentTypeNotAllowed)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody: `{
+				"status": "error",
+				"message": "File type not allowed",
+				"errors": {"file": "` + utils.ErrAttachmentTypeNotAllowed.Error() + `"}
+			}`,
+		},
+		{
+			name:    "Viewer cannot upload",
+			field:   "file",
+			content: "hello",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Upload", mock.Anything, 1, 5, uploadInput).Return(entity.Attachment{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "` + utils.ErrListPermissionDenied.Error() + `"}
+			}`,
+		},
+		{
+			name:    "Storage error",
+			field:   "file",
+			content: "hello",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Upload", mock.Anything, 1, 5, uploadInput).Return(entity.Attachment{}, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to upload attachment",
+				"errors": {"storage": "Error during attachment upload"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAttachment := new(MockAttachment)
+			r := setupAttachmentRouter(mockAttachment, 1)
+
+			body, contentType := multipartFile(testCase.field, "notes.txt", testCase.content)
+			req := httptest.NewRequest("POST", "/api/items/5/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAttachment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAttachment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetAttachments tests the GetAttachments handler
+func TestHandler_GetAttachments(t *testing.T) {
+	createdAt := time.Date(2024, 11, 25, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockAttachment *MockAttachment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/api/items/5/attachments",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("GetAll", mock.Anything, 1, 5).Return([]entity.Attachment{
+					{Id: 3, ItemId: 5, Name: "spec.pdf", ContentType: "application/pdf", Size: 4096, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Attachments retrieved successfully",
+				"data": [{
+					"id": 3,
+					"item_id": 5,
+					"user_id": null,
+					"name": "spec.pdf",
+					"content_type": "application/pdf",
+					"size": 4096,
+					"created_at": "2024-11-25T09:00:00Z"
+				}]
+			}`,
+		},
+		{
+			name:           "Invalid item ID",
+			url:            "/api/items/abc/attachments",
+			mockBehavior:   func(mockAttachment *MockAttachment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid itemId param",
+				"errors": {"param": "itemId must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Item not shared with the user",
+			url:  "/api/items/5/attachments",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("GetAll", mock.Anything, 1, 5).Return([]entity.Attachment{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAttachment := new(MockAttachment)
+			r := setupAttachmentRouter(mockAttachment, 1)
+
+			req := httptest.NewRequest("GET", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAttachment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAttachment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_DownloadAttachment tests the DownloadAttachment handler
+func TestHandler_DownloadAttachment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		mockAttachment := new(MockAttachment)
+		r := setupAttachmentRouter(mockAttachment, 1)
+
+		mockAttachment.On("Download", mock.Anything, 1, 5, 3).Return(
+			entity.Attachment{Id: 3, ItemId: 5, Name: "report 1.pdf", ContentType: "application/pdf", Size: 4},
+			io.NopCloser(strings.NewReader("%PDF")),
+			nil,
+		)
+
+		req := httptest.NewRequest("GET", "/api/items/5/attachments/3", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "%PDF", w.Body.String())
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="report 1.pdf"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+		mockAttachment.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockAttachment *MockAttachment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Invalid attachment ID",
+			url:            "/api/items/5/attachments/abc",
+			mockBehavior:   func(mockAttachment *MockAttachment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid attachmentId param",
+				"errors": {"param": "attachmentId must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Attachment not found",
+			url:  "/api/items/5/attachments/3",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Download", mock.Anything, 1, 5, 3).Return(entity.Attachment{}, nil, utils.ErrAttachmentNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Attachment not found",
+				"errors": {"attachmentId": "The requested attachment does not exist for this item"}
+			}`,
+		},
+		{
+			name: "Storage error",
+			url:  "/api/items/5/attachments/3",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("Download", mock.Anything, 1, 5, 3).Return(entity.Attachment{}, nil, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to download attachment",
+				"errors": {"storage": "Error during attachment download"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAttachment := new(MockAttachment)
+			r := setupAttachmentRouter(mockAttachment, 1)
+
+			req := httptest.NewRequest("GET", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAttachment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAttachment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_DeleteAttachment tests the DeleteAttachment handler
+func TestHandler_DeleteAttachment(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockAttachment *MockAttachment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("DeleteOneById", mock.Anything, 1, 5, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Attachment deleted successfully"
+			}`,
+		},
+		{
+			name: "Viewer cannot delete",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("DeleteOneById", mock.Anything, 1, 5, 3).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "` + utils.ErrListPermissionDenied.Error() + `"}
+			}`,
+		},
+		{
+			name: "Database error",
+			mockBehavior: func(mockAttachment *MockAttachment) {
+				mockAttachment.On("DeleteOneById", mock.Anything, 1, 5, 3).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to delete attachment",
+				"errors": {"database": "Error during attachment deletion"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAttachment := new(MockAttachment)
+			r := setupAttachmentRouter(mockAttachment, 1)
+
+			req := httptest.NewRequest("DELETE", "/api/items/5/attachments/3", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAttachment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAttachment.AssertExpectations(t)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
 	"github.com/berikulyBeket/todo-plus/utils"
 )
 
@@ -107,7 +108,7 @@ func (h *Handler) SignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.Usecases.Auth.GenerateToken(c.Request.Context(), user.Id)
+	tokens, err := h.Usecases.Auth.GenerateTokens(c.Request.Context(), user.Id, user.Role)
 	if err != nil {
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id":  user.Id,
@@ -126,7 +127,141 @@ func (h *Handler) SignIn(c *gin.Context) {
 		"username": input.Username,
 	}).Info("user signed in successfully")
 
-	utils.NewSuccessResponse(c, http.StatusOK, "Signed in successfully", map[string]interface{}{
-		"token": token,
-	})
+	utils.NewSuccessResponse(c, http.StatusOK, "Signed in successfully", tokens)
+}
+
+// refreshInput represents the expected input for refreshing tokens.
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshTokens handles access token renewal.
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body refreshInput true "Refresh token"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.TokenPair} "Tokens refreshed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Invalid refresh token"
+// @Failure 500 {object} utils.ErrorResponse "Failed to refresh tokens"
+// @Router /auth/refresh [post]
+func (h *Handler) RefreshTokens(c *gin.Context) {
+	var input refreshInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.Errorf("validation failed: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid request body", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	tokens, err := h.Usecases.Auth.RefreshTokens(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		if err == utils.ErrInvalidRefreshToken || err == utils.ErrRefreshTokenExpired || err == utils.ErrRefreshTokenReused {
+			h.Logger.Warn("refresh token rejected: %s", err)
+
+			utils.NewErrorResponse(c, http.StatusUnauthorized, "Invalid refresh token", map[string]string{
+				"refresh_token": err.Error(),
+			})
+			return
+		}
+
+		h.Logger.Errorf("failed to refresh tokens: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to refresh tokens", map[string]string{
+			"token": "Error during token refresh",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tokens refreshed successfully", tokens)
+}
+
+// signOutInput represents the optional input for signing out.
+type signOutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SignOut handles user sign out.
+// @Summary Sign out a user
+// @Description Revoke the current access token and the refresh token session
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body signOutInput false "Refresh token"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Signed out successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid request body"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Failed to sign out"
+// @Router /auth/sign-out [post]
+func (h *Handler) SignOut(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	accessToken, err := middleware.GetAccessToken(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get access token: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	var input signOutInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			h.Logger.WithFields(map[string]interface{}{
+				"user_id": userId,
+			}).Errorf("validation failed: %s", err)
+
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid request body", map[string]string{
+				"body": "Invalid or malformed JSON",
+			})
+			return
+		}
+	}
+
+	if err := h.Usecases.Auth.SignOut(c.Request.Context(), accessToken, input.RefreshToken); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to sign out: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to sign out", map[string]string{
+			"token": "Error during token revocation",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id": userId,
+	}).Info("user signed out successfully")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Signed out successfully", nil)
+}
+
+// GetJWKS publishes the public keys used to sign access tokens.
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens, identified by the kid header of the token
+// @Tags auth
+// @Produce json
+// @Success 200 {object} token.JWKS "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Usecases.Auth.GetJWKS())
 }
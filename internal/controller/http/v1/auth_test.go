@@ -13,9 +13,11 @@ import (
 
 	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
 	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
 	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
 	"github.com/berikulyBeket/todo-plus/utils"
 )
 
@@ -153,15 +155,15 @@ func TestHandler_SignIn(t *testing.T) {
 			}`,
 			mockBehavior: func(mockAuth *MockAuth) {
 				mockAuth.On("AuthenticateUser", mock.Anything, "testuser", "password").
-					Return(entity.User{Id: 1, Username: "testuser"}, nil)
-				mockAuth.On("GenerateToken", mock.Anything, 1).
-					Return("valid-token", nil)
+					Return(entity.User{Id: 1, Username: "testuser", Role: entity.RoleUser}, nil)
+				mockAuth.On("GenerateTokens", mock.Anything, 1, entity.RoleUser).
+					Return(entity.TokenPair{AccessToken: "valid-token", RefreshToken: "refresh-token"}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
 				"status": "ok",
 				"message": "Signed in successfully",
-				"data": {"token": "valid-token"}
+				"data": {"token": "valid-token", "refresh_token": "refresh-token"}
 			}`,
 		},
 		{
@@ -216,9 +218,9 @@ func TestHandler_SignIn(t *testing.T) {
 			}`,
 			mockBehavior: func(mockAuth *MockAuth) {
 				mockAuth.On("AuthenticateUser", mock.Anything, "testuser", "password").
-					Return(entity.User{Id: 1, Username: "testuser"}, nil)
-				mockAuth.On("GenerateToken", mock.Anything, 1).
-					Return("", errors.New("token generation error"))
+					Return(entity.User{Id: 1, Username: "testuser", Role: entity.RoleUser}, nil)
+				mockAuth.On("GenerateTokens", mock.Anything, 1, entity.RoleUser).
+					Return(entity.TokenPair{}, errors.New("token generation error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -267,3 +269,212 @@ func TestHandler_SignIn(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RefreshTokens(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockAuth *MockAuth)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"refresh_token": "old-refresh"}`,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("RefreshTokens", mock.Anything, "old-refresh").
+					Return(entity.TokenPair{AccessToken: "new-access", RefreshToken: "new-refresh"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Tokens refreshed successfully",
+				"data": {"token": "new-access", "refresh_token": "new-refresh"}
+			}`,
+		},
+		{
+			name:           "Missing refresh token",
+			input:          `{}`,
+			mockBehavior:   func(mockAuth *MockAuth) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid request body",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:  "Reused refresh token",
+			input: `{"refresh_token": "old-refresh"}`,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("RefreshTokens", mock.Anything, "old-refresh").
+					Return(entity.TokenPair{}, utils.ErrRefreshTokenReused)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid refresh token",
+				"errors": {"refresh_token": "refresh token reuse detected"}
+			}`,
+		},
+		{
+			name:  "Internal failure",
+			input: `{"refresh_token": "old-refresh"}`,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("RefreshTokens", mock.Anything, "old-refresh").
+					Return(entity.TokenPair{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to refresh tokens",
+				"errors": {"token": "Error during token refresh"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAuth := new(MockAuth)
+			appAuth := new(MockAppAuth)
+
+			mockUseCase := &usecase.UseCase{
+				Auth: mockAuth,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.POST("/auth/refresh", handler.RefreshTokens)
+
+			req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAuth)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAuth.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_SignOut(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockAuth *MockAuth)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success with refresh token",
+			input: `{"refresh_token": "refresh"}`,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("SignOut", mock.Anything, "access", "refresh").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Signed out successfully"}`,
+		},
+		{
+			name:  "Success without body",
+			input: ``,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("SignOut", mock.Anything, "access", "").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Signed out successfully"}`,
+		},
+		{
+			name:  "Revocation failure",
+			input: ``,
+			mockBehavior: func(mockAuth *MockAuth) {
+				mockAuth.On("SignOut", mock.Anything, "access", "").Return(errors.New("redis error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to sign out",
+				"errors": {"token": "Error during token revocation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockAuth := new(MockAuth)
+			appAuth := new(MockAppAuth)
+
+			mockUseCase := &usecase.UseCase{
+				Auth: mockAuth,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.POST("/auth/sign-out", func(c *gin.Context) {
+				c.Set(middleware.UserIdCtx, 1)
+				c.Set(middleware.AccessTokenCtx, "access")
+			}, handler.SignOut)
+
+			req := httptest.NewRequest("POST", "/auth/sign-out", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockAuth)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockAuth.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetJWKS(t *testing.T) {
+	mockAuth := new(MockAuth)
+	appAuth := new(MockAppAuth)
+
+	mockUseCase := &usecase.UseCase{
+		Auth: mockAuth,
+	}
+
+	handler := v1.NewHandler(mockUseCase, appAuth, &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := handler.RegisterRoutes(gin.New())
+
+	mockAuth.On("GetJWKS").Return(token.JWKS{Keys: []token.JWK{
+		{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "2024-10", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"keys": [
+			{"kty": "OKP", "use": "sig", "alg": "EdDSA", "kid": "2024-10", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+		]
+	}`, w.Body.String())
+
+	mockAuth.AssertExpectations(t)
+}
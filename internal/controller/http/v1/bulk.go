@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bulkItems godoc
+// @Summary Apply an operation to many items
+// @Description Update, delete or move up to 100 items at once. The operation is applied in a single transaction to the items
+// @Description of lists the user is an editor of, the result of every requested item is returned and items the user cannot
+// @Description change are reported as not_found or forbidden. Deleting or moving an item also affects its subtasks,
+// @Description moving needs list_id and the user must be an editor of that list
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.BulkItemsInput true "Operation and items"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.BulkItemsResult} "Bulk operation applied"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the target list"
+// @Failure 404 {object} utils.ErrorResponse "Target list not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to apply bulk operation"
+// @Router /api/items/bulk [post]
+func (h *Handler) BulkItems(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	var input entity.BulkItemsInput
+	if err := c.BindJSON(&input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	result, err := h.Usecases.Item.Bulk(c.Request.Context(), userId, input)
+	if err != nil {
+		switch err {
+		case utils.ErrInvalidBulkOperation, utils.ErrBulkItemsEmpty, utils.ErrTooManyBulkItems,
+			utils.ErrBulkListRequired, utils.ErrItemEmptyRequest:
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		case utils.ErrListPermissionDenied:
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		case utils.ErrUserNotOwner, utils.ErrListNotFound:
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":   userId,
+			"operation": input.Operation,
+		}).Errorf("failed to apply bulk operation: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to apply bulk operation", map[string]string{
+			"database": "Error during bulk operation",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Bulk operation applied", result)
+}
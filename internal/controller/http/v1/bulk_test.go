@@ -0,0 +1,191 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupBulkRouter registers the bulk item handler with an authenticated user
+func setupBulkRouter(mockItem *MockItem, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Item: mockItem,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/items/bulk", handler.BulkItems)
+
+	return r
+}
+
+// TestHandler_BulkItems tests the BulkItems handler
+func TestHandler_BulkItems(t *testing.T) {
+	input := entity.BulkItemsInput{Operation: entity.BulkOperationDelete, ItemIds: []int{1, 2}}
+
+	testCases := []struct {
+		name           string
+		userId         int
+		input          string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			input:  `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Bulk", mock.Anything, 1, input).Return(entity.BulkItemsResult{
+					Operation: entity.BulkOperationDelete,
+					Results: []entity.BulkItemResult{
+						{ItemId: 1, Status: entity.BulkItemStatusOk},
+						{ItemId: 2, Status: entity.BulkItemStatusForbidden, Error: utils.ErrListPermissionDenied.Error()},
+					},
+					AffectedIds: []int{1, 3},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Bulk operation applied",
+				"data": {
+					"operation": "delete",
+					"results": [
+						{"item_id": 1, "status": "ok"},
+						{"item_id": 2, "status": "forbidden", "error": "user does not have the required role on the list"}
+					],
+					"affected_ids": [1, 3]
+				}
+			}`,
+		},
+		{
+			name:           "Unauthorized",
+			userId:         0,
+			input:          `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: `{
+				"status": "error",
+				"message": "Unauthorized",
+				"errors": {"auth": "User authentication failed or user not logged in"}
+			}`,
+		},
+		{
+			name:           "Missing operation",
+			userId:         1,
+			input:          `{"item_ids": [1, 2]}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Unknown operation",
+			userId: 1,
+			input:  `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Bulk", mock.Anything, 1, input).Return(entity.BulkItemsResult{}, utils.ErrInvalidBulkOperation)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "operation must be update, delete or move"}
+			}`,
+		},
+		{
+			name:   "Viewer of the target list",
+			userId: 1,
+			input:  `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Bulk", mock.Anything, 1, input).Return(entity.BulkItemsResult{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:   "Target list not shared with the user",
+			userId: 1,
+			input:  `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Bulk", mock.Anything, 1, input).Return(entity.BulkItemsResult{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			input:  `{"operation": "delete", "item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Bulk", mock.Anything, 1, input).Return(entity.BulkItemsResult{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to apply bulk operation",
+				"errors": {"database": "Error during bulk operation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupBulkRouter(mockItem, testCase.userId)
+
+			req := httptest.NewRequest("POST", "/api/items/bulk", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
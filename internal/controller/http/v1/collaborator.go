@@ -0,0 +1,320 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getCollaborators godoc
+// @Summary Get list collaborators
+// @Description Retrieve the users a list is shared with and their roles
+// @Tags collaborators
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.ListCollaborator} "Collaborators retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve collaborators"
+// @Router /api/lists/{id}/collaborators [get]
+func (h *Handler) GetCollaborators(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	collaborators, err := h.Usecases.List.GetCollaborators(c.Request.Context(), userId, listId)
+	if err != nil {
+		if h.collaboratorErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to retrieve collaborators: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve collaborators", map[string]string{
+			"database": "Error during collaborators retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Collaborators retrieved successfully", collaborators)
+}
+
+// addCollaborator godoc
+// @Summary Share a list
+// @Description Share a list with another user as owner, editor or viewer, only owners can share a list
+// @Tags collaborators
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param input body entity.AddCollaboratorInput true "Username and role"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.ListCollaborator} "Collaborator added successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or user not found"
+// @Failure 409 {object} utils.ErrorResponse "User is already a collaborator"
+// @Failure 500 {object} utils.ErrorResponse "Failed to add collaborator"
+// @Router /api/lists/{id}/collaborators [post]
+func (h *Handler) AddCollaborator(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	var input entity.AddCollaboratorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	collaborator, err := h.Usecases.List.AddCollaborator(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.collaboratorErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to add collaborator: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to add collaborator", map[string]string{
+			"database": "Error during collaborator creation",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":         userId,
+		"list_id":         listId,
+		"collaborator_id": collaborator.UserId,
+		"role":            collaborator.Role,
+	}).Info("list shared successfully")
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Collaborator added successfully", collaborator)
+}
+
+// updateCollaborator godoc
+// @Summary Change a collaborator role
+// @Description Change the role of a user on a shared list, only owners can change roles and the last owner cannot be demoted
+// @Tags collaborators
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param userId path int true "Collaborator user ID"
+// @Param input body entity.UpdateCollaboratorInput true "Role"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Collaborator updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or collaborator not found"
+// @Failure 409 {object} utils.ErrorResponse "List must keep an owner"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update collaborator"
+// @Router /api/lists/{id}/collaborators/{userId} [put]
+func (h *Handler) UpdateCollaborator(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, collaboratorId, ok := parseCollaboratorParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.UpdateCollaboratorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.List.UpdateCollaborator(c.Request.Context(), userId, listId, collaboratorId, input)
+	if err != nil {
+		if h.collaboratorErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":         userId,
+			"list_id":         listId,
+			"collaborator_id": collaboratorId,
+		}).Errorf("failed to update collaborator: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update collaborator", map[string]string{
+			"database": "Error during collaborator update",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Collaborator updated successfully", nil)
+}
+
+// removeCollaborator godoc
+// @Summary Remove a collaborator
+// @Description Stop sharing a list with a user, owners can remove anyone and collaborators can remove themselves
+// @Tags collaborators
+// @Security BearerAuth
+// @Param id path int true "List ID"
+// @Param userId path int true "Collaborator user ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Collaborator removed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or collaborator not found"
+// @Failure 409 {object} utils.ErrorResponse "List must keep an owner"
+// @Failure 500 {object} utils.ErrorResponse "Failed to remove collaborator"
+// @Router /api/lists/{id}/collaborators/{userId} [delete]
+func (h *Handler) RemoveCollaborator(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, collaboratorId, ok := parseCollaboratorParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.List.RemoveCollaborator(c.Request.Context(), userId, listId, collaboratorId)
+	if err != nil {
+		if h.collaboratorErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":         userId,
+			"list_id":         listId,
+			"collaborator_id": collaboratorId,
+		}).Errorf("failed to remove collaborator: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to remove collaborator", map[string]string{
+			"database": "Error during collaborator removal",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Collaborator removed successfully", nil)
+}
+
+// parseCollaboratorParams parses the list and collaborator Ids from the path, responding with 400 when invalid
+func parseCollaboratorParams(c *gin.Context) (int, int, bool) {
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	collaboratorId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid userId param", map[string]string{
+			"param": "userId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return listId, collaboratorId, true
+}
+
+// collaboratorErrorResponse responds to the errors of the collaborator use cases that are caused by the request,
+// it reports whether a response was written
+func (h *Handler) collaboratorErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidListRole:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrUserNotOwner, utils.ErrListNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+			"listId": "The requested list does not exist",
+		})
+	case utils.ErrListPermissionDenied:
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+			"role": err.Error(),
+		})
+	case utils.ErrUserNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "User not found", map[string]string{
+			"username": "The requested user does not exist",
+		})
+	case utils.ErrCollaboratorNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Collaborator not found", map[string]string{
+			"userId": "The list is not shared with the requested user",
+		})
+	case utils.ErrCollaboratorExists, utils.ErrLastListOwner:
+		utils.NewErrorResponse(c, http.StatusConflict, "Conflict", map[string]string{
+			"collaborator": err.Error(),
+		})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,520 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestHandler_GetCollaborators tests the GetCollaborators handler
+func TestHandler_GetCollaborators(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userId         int
+		listId         string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			listId: "1",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetCollaborators", mock.Anything, 1, 1).Return([]entity.ListCollaborator{
+					{UserId: 1, Name: "Alice", Username: "alice", Role: entity.ListRoleOwner},
+					{UserId: 2, Name: "Bob", Username: "bob", Role: entity.ListRoleViewer},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Collaborators retrieved successfully",
+				"data": [
+					{"user_id": 1, "name": "Alice", "username": "alice", "role": "owner"},
+					{"user_id": 2, "name": "Bob", "username": "bob", "role": "viewer"}
+				]
+			}`,
+		},
+		{
+			name:           "Invalid listId parameter",
+			userId:         1,
+			listId:         "invalid",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "List not shared with user",
+			userId: 1,
+			listId: "1",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetCollaborators", mock.Anything, 1, 1).Return([]entity.ListCollaborator{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			listId: "1",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetCollaborators", mock.Anything, 1, 1).Return([]entity.ListCollaborator{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to retrieve collaborators",
+				"errors": {"database": "Error during collaborators retrieval"}
+			}`,
+		},
+		{
+			name:           "Unauthorized",
+			userId:         0,
+			listId:         "1",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: `{
+				"status": "error",
+				"message": "Unauthorized",
+				"errors": {"auth": "User authentication failed or user not logged in"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			appAuth := new(MockAppAuth)
+			noOpLogger := &logger.NoOpLogger{}
+			noOpMetrics := &metrics.NoOpMetrics{}
+
+			mockUseCase := &usecase.UseCase{
+				List: mockList,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, noOpLogger, noOpMetrics)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+
+			r.Use(func(c *gin.Context) {
+				if testCase.userId != 0 {
+					c.Set(middleware.UserIdCtx, testCase.userId)
+				}
+				c.Next()
+			})
+
+			r.GET("/api/lists/:id/collaborators", handler.GetCollaborators)
+
+			req := httptest.NewRequest("GET", "/api/lists/"+testCase.listId+"/collaborators", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_AddCollaborator tests the AddCollaborator handler
+func TestHandler_AddCollaborator(t *testing.T) {
+	input := entity.AddCollaboratorInput{Username: "bob", Role: entity.ListRoleEditor}
+
+	testCases := []struct {
+		name           string
+		userId         int
+		body           string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, input).
+					Return(entity.ListCollaborator{UserId: 2, Name: "Bob", Username: "bob", Role: entity.ListRoleEditor}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Collaborator added successfully",
+				"data": {"user_id": 2, "name": "Bob", "username": "bob", "role": "editor"}
+			}`,
+		},
+		{
+			name:           "Invalid input",
+			userId:         1,
+			body:           `{"username": "bob"}`,
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Unknown role",
+			userId: 1,
+			body:   `{"username": "bob", "role": "admin"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, entity.AddCollaboratorInput{Username: "bob", Role: "admin"}).
+					Return(entity.ListCollaborator{}, utils.ErrInvalidListRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "unknown list role"}
+			}`,
+		},
+		{
+			name:   "Not an owner",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, input).
+					Return(entity.ListCollaborator{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:   "User not found",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, input).
+					Return(entity.ListCollaborator{}, utils.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "User not found",
+				"errors": {"username": "The requested user does not exist"}
+			}`,
+		},
+		{
+			name:   "Already a collaborator",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, input).
+					Return(entity.ListCollaborator{}, utils.ErrCollaboratorExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{
+				"status": "error",
+				"message": "Conflict",
+				"errors": {"collaborator": "user is already a collaborator of the list"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("AddCollaborator", mock.Anything, 1, 1, input).
+					Return(entity.ListCollaborator{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to add collaborator",
+				"errors": {"database": "Error during collaborator creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			appAuth := new(MockAppAuth)
+			noOpLogger := &logger.NoOpLogger{}
+			noOpMetrics := &metrics.NoOpMetrics{}
+
+			mockUseCase := &usecase.UseCase{
+				List: mockList,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, noOpLogger, noOpMetrics)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+
+			r.Use(func(c *gin.Context) {
+				if testCase.userId != 0 {
+					c.Set(middleware.UserIdCtx, testCase.userId)
+				}
+				c.Next()
+			})
+
+			r.POST("/api/lists/:id/collaborators", handler.AddCollaborator)
+
+			req := httptest.NewRequest("POST", "/api/lists/1/collaborators", bytes.NewBufferString(testCase.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_UpdateCollaborator tests the UpdateCollaborator handler
+func TestHandler_UpdateCollaborator(t *testing.T) {
+	input := entity.UpdateCollaboratorInput{Role: entity.ListRoleViewer}
+
+	testCases := []struct {
+		name           string
+		userId         int
+		collaboratorId string
+		body           string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			userId:         1,
+			collaboratorId: "2",
+			body:           `{"role": "viewer"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("UpdateCollaborator", mock.Anything, 1, 1, 2, input).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Collaborator updated successfully"
+			}`,
+		},
+		{
+			name:           "Invalid userId parameter",
+			userId:         1,
+			collaboratorId: "invalid",
+			body:           `{"role": "viewer"}`,
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid userId param",
+				"errors": {"param": "userId must be a valid integer"}
+			}`,
+		},
+		{
+			name:           "Collaborator not found",
+			userId:         1,
+			collaboratorId: "2",
+			body:           `{"role": "viewer"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("UpdateCollaborator", mock.Anything, 1, 1, 2, input).Return(utils.ErrCollaboratorNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Collaborator not found",
+				"errors": {"userId": "The list is not shared with the requested user"}
+			}`,
+		},
+		{
+			name:           "Last owner",
+			userId:         1,
+			collaboratorId: "1",
+			body:           `{"role": "viewer"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("UpdateCollaborator", mock.Anything, 1, 1, 1, input).Return(utils.ErrLastListOwner)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{
+				"status": "error",
+				"message": "Conflict",
+				"errors": {"collaborator": "list must keep at least one owner"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			appAuth := new(MockAppAuth)
+			noOpLogger := &logger.NoOpLogger{}
+			noOpMetrics := &metrics.NoOpMetrics{}
+
+			mockUseCase := &usecase.UseCase{
+				List: mockList,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, noOpLogger, noOpMetrics)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+
+			r.Use(func(c *gin.Context) {
+				if testCase.userId != 0 {
+					c.Set(middleware.UserIdCtx, testCase.userId)
+				}
+				c.Next()
+			})
+
+			r.PUT("/api/lists/:id/collaborators/:userId", handler.UpdateCollaborator)
+
+			req := httptest.NewRequest("PUT", "/api/lists/1/collaborators/"+testCase.collaboratorId, bytes.NewBufferString(testCase.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RemoveCollaborator tests the RemoveCollaborator handler
+func TestHandler_RemoveCollaborator(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userId         int
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("RemoveCollaborator", mock.Anything, 1, 1, 2).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Collaborator removed successfully"
+			}`,
+		},
+		{
+			name:   "Not an owner",
+			userId: 1,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("RemoveCollaborator", mock.Anything, 1, 1, 2).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("RemoveCollaborator", mock.Anything, 1, 1, 2).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to remove collaborator",
+				"errors": {"database": "Error during collaborator removal"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			appAuth := new(MockAppAuth)
+			noOpLogger := &logger.NoOpLogger{}
+			noOpMetrics := &metrics.NoOpMetrics{}
+
+			mockUseCase := &usecase.UseCase{
+				List: mockList,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, noOpLogger, noOpMetrics)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+
+			r.Use(func(c *gin.Context) {
+				if testCase.userId != 0 {
+					c.Set(middleware.UserIdCtx, testCase.userId)
+				}
+				c.Next()
+			})
+
+			r.DELETE("/api/lists/:id/collaborators/:userId", handler.RemoveCollaborator)
+
+			req := httptest.NewRequest("DELETE", "/api/lists/1/collaborators/2", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createComment godoc
+// @Summary Comment on an item
+// @Description Add a comment to an item of a list the user can edit, a parent_id makes the comment a reply
+// @Description to another comment of the same item
+// @Tags comments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param input body entity.CreateCommentInput true "Comment body and optional parent comment"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.Comment} "Comment created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create comment"
+// @Router /api/items/{id}/comments [post]
+func (h *Handler) CreateComment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	var input entity.CreateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	comment, err := h.Usecases.Comment.Create(c.Request.Context(), userId, itemId, input)
+	if err != nil {
+		if h.commentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to create comment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create comment", map[string]string{
+			"database": "Error during comment creation",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Comment created successfully", comment)
+}
+
+// getComments godoc
+// @Summary Get the comments of an item
+// @Description Retrieve a page of the top level comments of an item from the oldest, or of the replies to a comment
+// @Description when parent_id is given. Pass the next_cursor of a page as after to get the following page
+// @Tags comments
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param parent_id query int false "Parent comment ID"
+// @Param after query int false "Cursor of the previous page"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.CommentPage} "Comments retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve comments"
+// @Router /api/items/{id}/comments [get]
+func (h *Handler) GetComments(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	query, err := parseCommentQuery(c)
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid params", map[string]string{
+			"param": err.Error(),
+		})
+		return
+	}
+
+	page, err := h.Usecases.Comment.GetAll(c.Request.Context(), userId, itemId, query)
+	if err != nil {
+		if h.commentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to get comments: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve comments", map[string]string{
+			"database": "Error retrieving comments",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Comments retrieved successfully", page)
+}
+
+// updateComment godoc
+// @Summary Edit a comment
+// @Description Replace the body of a comment, only the author of a comment can edit it
+// @Tags comments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param commentId path int true "Comment ID"
+// @Param input body entity.UpdateCommentInput true "New comment body"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Comment updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the author of the comment"
+// @Failure 404 {object} utils.ErrorResponse "Item or comment not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update comment"
+// @Router /api/items/{id}/comments/{commentId} [put]
+func (h *Handler) UpdateComment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, commentId, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.UpdateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":    userId,
+			"comment_id": commentId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.Comment.UpdateOneById(c.Request.Context(), userId, itemId, commentId, input)
+	if err != nil {
+		if h.commentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":    userId,
+			"item_id":    itemId,
+			"comment_id": commentId,
+		}).Errorf("failed to update comment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update comment", map[string]string{
+			"database": "Error during comment update",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Comment updated successfully", nil)
+}
+
+// deleteComment godoc
+// @Summary Delete a comment
+// @Description Delete a comment and its replies, a comment can be deleted by its author or by an owner of the list
+// @Tags comments
+// @Security BearerAuth
+// @Param id path int true "Item ID"
+// @Param commentId path int true "Comment ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Comment deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the author of the comment"
+// @Failure 404 {object} utils.ErrorResponse "Item or comment not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to delete comment"
+// @Router /api/items/{id}/comments/{commentId} [delete]
+func (h *Handler) DeleteComment(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, commentId, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Comment.DeleteOneById(c.Request.Context(), userId, itemId, commentId)
+	if err != nil {
+		if h.commentErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":    userId,
+			"item_id":    itemId,
+			"comment_id": commentId,
+		}).Errorf("failed to delete comment: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to delete comment", map[string]string{
+			"database": "Error during comment deletion",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Comment deleted successfully", nil)
+}
+
+// commentErrorResponse writes the response of the known comment errors and reports whether it did
+func (h *Handler) commentErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidCommentBody, utils.ErrInvalidCommentPageLimit, utils.ErrInvalidCommentCursor,
+		utils.ErrCommentParentNotFound:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrListPermissionDenied, utils.ErrCommentNotAuthor:
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+			"role": err.Error(),
+		})
+	case utils.ErrUserNotOwner, utils.ErrItemNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+			"itemId": "The requested item does not exist",
+		})
+	case utils.ErrCommentNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Comment not found", map[string]string{
+			"commentId": "The requested comment does not exist for this item",
+		})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// parseCommentParams parses the item and comment path params, a 400 response is written when one is invalid
+func parseCommentParams(c *gin.Context) (int, int, bool) {
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	commentId, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid commentId param", map[string]string{
+			"param": "commentId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return itemId, commentId, true
+}
+
+// parseCommentQuery parses the optional parent_id, after and limit query params of a comment page
+func parseCommentQuery(c *gin.Context) (entity.CommentQuery, error) {
+	var query entity.CommentQuery
+	var after, limit *int
+
+	if err := utils.ParseOptionalParamAsInt(c, "parent_id", &query.ParentId); err != nil {
+		return query, err
+	}
+	if err := utils.ParseOptionalParamAsInt(c, "after", &after); err != nil {
+		return query, err
+	}
+	if err := utils.ParseOptionalParamAsInt(c, "limit", &limit); err != nil {
+		return query, err
+	}
+
+	if after != nil {
+		query.After = *after
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	return query, nil
+}
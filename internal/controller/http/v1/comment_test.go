@@ -0,0 +1,414 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupCommentRouter registers the comment handlers with an authenticated user
+func setupCommentRouter(mockComment *MockComment, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Comment: mockComment,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/items/:id/comments", handler.CreateComment)
+	r.GET("/api/items/:id/comments", handler.GetComments)
+	r.PUT("/api/items/:id/comments/:commentId", handler.UpdateComment)
+	r.DELETE("/api/items/:id/comments/:commentId", handler.DeleteComment)
+
+	return r
+}
+
+// TestHandler_CreateComment tests the CreateComment handler
+func TestHandler_CreateComment(t *testing.T) {
+	createdAt := time.Date(2024, 11, 23, 9, 0, 0, 0, time.UTC)
+	parentId := 3
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockComment *MockComment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"body": "Done?", "parent_id": 3}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("Create", mock.Anything, 1, 5, entity.CreateCommentInput{Body: "Done?", ParentId: &parentId}).
+					Return(entity.Comment{
+						Id:        9,
+						ItemId:    5,
+						ParentId:  &parentId,
+						Author:    entity.CommentAuthor{Id: 1, Name: "Ann", Username: "ann"},
+						Body:      "Done?",
+						CreatedAt: createdAt,
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Comment created successfully",
+				"data": {
+					"id": 9,
+					"item_id": 5,
+					"parent_id": 3,
+					"author": {"id": 1, "name": "Ann", "username": "ann"},
+					"body": "Done?",
+					"reply_count": 0,
+					"created_at": "2024-11-23T09:00:00Z",
+					"updated_at": null
+				}
+			}`,
+		},
+		{
+			name:           "Missing body",
+			input:          `{}`,
+			mockBehavior:   func(mockComment *MockComment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:  "Parent of another item",
+			input: `{"body": "Done?", "parent_id": 3}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("Create", mock.Anything, 1, 5, mock.Anything).Return(entity.Comment{}, utils.ErrCommentParentNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "parent comment does not belong to the item"}
+			}`,
+		},
+		{
+			name:  "Viewer cannot comment",
+			input: `{"body": "Done?"}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("Create", mock.Anything, 1, 5, mock.Anything).Return(entity.Comment{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "` + utils.ErrListPermissionDenied.Error() + `"}
+			}`,
+		},
+		{
+			name:  "Database error",
+			input: `{"body": "Done?"}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("Create", mock.Anything, 1, 5, mock.Anything).Return(entity.Comment{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create comment",
+				"errors": {"database": "Error during comment creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockComment := new(MockComment)
+			r := setupCommentRouter(mockComment, 1)
+
+			req := httptest.NewRequest("POST", "/api/items/5/comments", bytes.NewBufferString(testCase.input))
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockComment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockComment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetComments tests the GetComments handler
+func TestHandler_GetComments(t *testing.T) {
+	parentId := 3
+
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockComment *MockComment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/api/items/5/comments?parent_id=3&after=4&limit=1",
+			mockBehavior: func(mockComment *MockComment) {
+				nextCursor := 6
+				mockComment.On("GetAll", mock.Anything, 1, 5, entity.CommentQuery{ParentId: &parentId, After: 4, Limit: 1}).
+					Return(entity.CommentPage{Comments: []entity.Comment{}, NextCursor: &nextCursor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Comments retrieved successfully",
+				"data": {"comments": [], "next_cursor": 6}
+			}`,
+		},
+		{
+			name:           "Invalid limit",
+			url:            "/api/items/5/comments?limit=abc",
+			mockBehavior:   func(mockComment *MockComment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid params",
+				"errors": {"param": "limit must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Limit out of bounds",
+			url:  "/api/items/5/comments?limit=500",
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("GetAll", mock.Anything, 1, 5, entity.CommentQuery{Limit: 500}).
+					Return(entity.CommentPage{}, utils.ErrInvalidCommentPageLimit)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "limit must be between 1 and 100"}
+			}`,
+		},
+		{
+			name: "Item not shared with the user",
+			url:  "/api/items/5/comments",
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("GetAll", mock.Anything, 1, 5, entity.CommentQuery{}).Return(entity.CommentPage{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockComment := new(MockComment)
+			r := setupCommentRouter(mockComment, 1)
+
+			req := httptest.NewRequest("GET", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockComment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockComment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_UpdateComment tests the UpdateComment handler
+func TestHandler_UpdateComment(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		input          string
+		mockBehavior   func(mockComment *MockComment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			url:   "/api/items/5/comments/9",
+			input: `{"body": "Edited"}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("UpdateOneById", mock.Anything, 1, 5, 9, entity.UpdateCommentInput{Body: "Edited"}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Comment updated successfully"
+			}`,
+		},
+		{
+			name:           "Invalid commentId",
+			url:            "/api/items/5/comments/abc",
+			input:          `{"body": "Edited"}`,
+			mockBehavior:   func(mockComment *MockComment) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid commentId param",
+				"errors": {"param": "commentId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "Not the author",
+			url:   "/api/items/5/comments/9",
+			input: `{"body": "Edited"}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("UpdateOneById", mock.Anything, 1, 5, 9, mock.Anything).Return(utils.ErrCommentNotAuthor)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "comment can only be changed by its author"}
+			}`,
+		},
+		{
+			name:  "Comment not found",
+			url:   "/api/items/5/comments/9",
+			input: `{"body": "Edited"}`,
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("UpdateOneById", mock.Anything, 1, 5, 9, mock.Anything).Return(utils.ErrCommentNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Comment not found",
+				"errors": {"commentId": "The requested comment does not exist for this item"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockComment := new(MockComment)
+			r := setupCommentRouter(mockComment, 1)
+
+			req := httptest.NewRequest("PUT", testCase.url, bytes.NewBufferString(testCase.input))
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockComment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockComment.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_DeleteComment tests the DeleteComment handler
+func TestHandler_DeleteComment(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockComment *MockComment)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("DeleteOneById", mock.Anything, 1, 5, 9).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Comment deleted successfully"
+			}`,
+		},
+		{
+			name: "Not the author",
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("DeleteOneById", mock.Anything, 1, 5, 9).Return(utils.ErrCommentNotAuthor)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "comment can only be changed by its author"}
+			}`,
+		},
+		{
+			name: "Database error",
+			mockBehavior: func(mockComment *MockComment) {
+				mockComment.On("DeleteOneById", mock.Anything, 1, 5, 9).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to delete comment",
+				"errors": {"database": "Error during comment deletion"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockComment := new(MockComment)
+			r := setupCommentRouter(mockComment, 1)
+
+			req := httptest.NewRequest("DELETE", "/api/items/5/comments/9", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockComment)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockComment.AssertExpectations(t)
+		})
+	}
+}
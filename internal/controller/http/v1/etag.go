@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag writes the version of a resource as its ETag header
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// notModified reports whether the If-None-Match header matches the version of a resource,
+// a 304 response with the ETag is written then. Weak entity tags match as well
+func notModified(c *gin.Context, version int) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || entityTagVersion(tag) == version {
+			setETag(c, version)
+			c.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIfMatch parses the version a write is conditioned on from the If-Match header, nil is returned without
+// the header or for "*". A 412 response is written when the header is not a single entity tag of a version
+func parseIfMatch(c *gin.Context) (*int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	version := entityTagVersion(header)
+	if version < 0 {
+		utils.NewErrorResponse(c, http.StatusPreconditionFailed, "Precondition failed", map[string]string{
+			"version": "If-Match must be the ETag of the resource",
+		})
+		return nil, false
+	}
+
+	return &version, true
+}
+
+// preconditionErrorResponse writes the response of a write whose If-Match version is stale and reports whether it did
+func preconditionErrorResponse(c *gin.Context, err error) bool {
+	if err != utils.ErrVersionMismatch {
+		return false
+	}
+
+	utils.NewErrorResponse(c, http.StatusPreconditionFailed, "Precondition failed", map[string]string{
+		"version": err.Error(),
+	})
+
+	return true
+}
+
+// entityTagVersion returns the version of a strong entity tag written by setETag, -1 is returned for other tags
+func entityTagVersion(tag string) int {
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return -1
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 0 {
+		return -1
+	}
+
+	return version
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 
+	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/middleware"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
@@ -46,6 +47,8 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) *gin.Engine {
 
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	router.GET("/.well-known/jwks.json", h.GetJWKS)
+
 	v1 := router.Group("/v1")
 	{
 		auth := v1.Group("/auth")
@@ -53,45 +56,134 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) *gin.Engine {
 		{
 			auth.POST("/sign-up", h.SignUp)
 			auth.POST("/sign-in", h.SignIn)
+			auth.POST("/refresh", h.RefreshTokens)
+			auth.POST("/sign-out", middleware.Authentication(h.Usecases.Auth, h.Logger), h.SignOut)
 		}
 
 		api := v1.Group("/api")
 		api.Use(middleware.AppAuth(appauth.PublicAccess, h.AppAuth))
 		api.Use(middleware.Authentication(h.Usecases.Auth, h.Logger))
 		{
-			lists := api.Group("/lists")
+			lists := api.Group("/lists", middleware.Scope(entity.ResourceLists, h.Logger))
 			{
-				lists.POST("/", h.CreateList)
+				lists.POST("/", middleware.Idempotency(h.Usecases.Idempotency, h.Logger), h.CreateList)
 				lists.GET("/", h.GetAllLists)
 				lists.GET("/:id", h.GetListById)
 				lists.PUT("/:id", h.UpdateList)
 				lists.DELETE("/:id", h.DeleteList)
+				lists.POST("/:id/move", h.MoveList)
+				lists.POST("/:id/clone", h.CloneList)
+				lists.POST("/:id/templates", h.CreateListTemplate)
+				lists.GET("/:id/history", h.GetListHistory)
+				lists.POST("/:id/history/:revisionId/revert", h.RevertList)
 				lists.GET("/search", h.SearchLists)
+				lists.GET("/:id/collaborators", h.GetCollaborators)
+				lists.POST("/:id/collaborators", h.AddCollaborator)
+				lists.PUT("/:id/collaborators/:userId", h.UpdateCollaborator)
+				lists.DELETE("/:id/collaborators/:userId", h.RemoveCollaborator)
+				lists.POST("/:id/invitations", h.CreateInvitation)
+				lists.GET("/:id/invitations", h.GetListInvitations)
+			}
 
-				items := lists.Group(":id/items")
-				{
-					items.POST("/", h.CreateItem)
-					items.GET("/", h.GetAllItems)
-				}
+			listItems := api.Group("/lists/:id/items", middleware.Scope(entity.ResourceItems, h.Logger))
+			{
+				listItems.POST("/", middleware.Idempotency(h.Usecases.Idempotency, h.Logger), h.CreateItem)
+				listItems.GET("/", h.GetAllItems)
+				listItems.POST("/move", h.MoveItemsToList)
+				listItems.POST("/copy", h.CopyItemsToList)
 			}
 
-			items := api.Group("/items")
+			items := api.Group("/items", middleware.Scope(entity.ResourceItems, h.Logger))
 			{
 				items.GET("/:id", h.GetItemById)
 				items.PUT("/:id", h.UpdateItem)
 				items.DELETE("/:id", h.DeleteItem)
 				items.GET("/search", h.SearchItems)
+				items.POST("/bulk", h.BulkItems)
+				items.POST("/:id/subtasks", h.CreateSubtask)
+				items.GET("/:id/subtasks", h.GetSubtasks)
+				items.PUT("/:id/parent", h.SetItemParent)
+				items.POST("/:id/move", h.MoveItem)
+				items.GET("/:id/history", h.GetItemHistory)
+				items.POST("/:id/history/:revisionId/revert", h.RevertItem)
+				items.GET("/:id/tags", h.GetItemTags)
+				items.POST("/:id/tags/:tagId", h.AttachItemTag)
+				items.DELETE("/:id/tags/:tagId", h.DetachItemTag)
+				items.POST("/:id/comments", h.CreateComment)
+				items.GET("/:id/comments", h.GetComments)
+				items.PUT("/:id/comments/:commentId", h.UpdateComment)
+				items.DELETE("/:id/comments/:commentId", h.DeleteComment)
+				items.POST("/:id/attachments", h.UploadAttachment)
+				items.GET("/:id/attachments", h.GetAttachments)
+				items.GET("/:id/attachments/:attachmentId", h.DownloadAttachment)
+				items.DELETE("/:id/attachments/:attachmentId", h.DeleteAttachment)
+			}
+
+			templates := api.Group("/templates", middleware.Scope(entity.ResourceLists, h.Logger))
+			{
+				templates.GET("/", h.GetListTemplates)
+				templates.GET("/:id", h.GetListTemplate)
+				templates.DELETE("/:id", h.DeleteListTemplate)
+				templates.POST("/:id/lists", h.CreateListFromTemplate)
+			}
+
+			tags := api.Group("/tags", middleware.Scope(entity.ResourceItems, h.Logger))
+			{
+				tags.POST("/", h.CreateTag)
+				tags.GET("/", h.GetAllTags)
+				tags.PUT("/:id", h.UpdateTag)
+				tags.DELETE("/:id", h.DeleteTag)
+			}
+
+			trashLists := api.Group("/trash/lists", middleware.Scope(entity.ResourceLists, h.Logger))
+			{
+				trashLists.GET("/", h.GetTrashedLists)
+				trashLists.POST("/:id/restore", h.RestoreList)
+			}
+
+			trashItems := api.Group("/trash/items", middleware.Scope(entity.ResourceItems, h.Logger))
+			{
+				trashItems.GET("/", h.GetTrashedItems)
+				trashItems.POST("/:id/restore", h.RestoreItem)
+			}
+
+			invitations := api.Group("/invitations", middleware.Scope(entity.ResourceLists, h.Logger))
+			{
+				invitations.GET("/", h.GetInvitations)
+				invitations.POST("/accept", h.AcceptInvitationLink)
+				invitations.POST("/:id/accept", h.AcceptInvitation)
+				invitations.POST("/:id/decline", h.DeclineInvitation)
+				invitations.DELETE("/:id", h.RevokeInvitation)
+			}
+
+			tokens := api.Group("/tokens", middleware.Scope(entity.ResourceTokens, h.Logger))
+			{
+				tokens.POST("/", h.CreatePersonalAccessToken)
+				tokens.GET("/", h.GetAllPersonalAccessTokens)
+				tokens.DELETE("/:id", h.RevokePersonalAccessToken)
 			}
 		}
 
 		private := v1.Group("/private")
 		private.Use(middleware.AppAuth(appauth.PrivateAccess, h.AppAuth))
+		private.Use(middleware.Authentication(h.Usecases.Auth, h.Logger))
 		{
 			api := private.Group("/api")
 			{
-				api.DELETE("/users/:id", h.DeleteUserByAdmin)
-				api.DELETE("/lists/:id", h.DeleteListByAdmin)
-				api.DELETE("/items/:id", h.DeleteItemByAdmin)
+				api.DELETE("/users/:id", middleware.Authorization(entity.PermissionDeleteUsers, h.Logger), h.DeleteUserByAdmin)
+				api.PUT("/users/:id/role", middleware.Authorization(entity.PermissionManageRoles, h.Logger), h.UpdateUserRole)
+				api.DELETE("/lists/:id", middleware.Authorization(entity.PermissionDeleteLists, h.Logger), h.DeleteListByAdmin)
+				api.DELETE("/items/:id", middleware.Authorization(entity.PermissionDeleteItems, h.Logger), h.DeleteItemByAdmin)
+
+				apps := api.Group("/apps", middleware.Authorization(entity.PermissionManageApps, h.Logger))
+				{
+					apps.POST("/", h.CreateApp)
+					apps.GET("/", h.GetAllApps)
+					apps.GET("/:id", h.GetAppByAppId)
+					apps.PUT("/:id", h.UpdateApp)
+					apps.POST("/:id/rotate-key", h.RotateAppKey)
+					apps.DELETE("/:id", h.DeleteApp)
+				}
 			}
 		}
 	}
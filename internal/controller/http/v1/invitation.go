@@ -0,0 +1,377 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createInvitation godoc
+// @Summary Invite a user to a list
+// @Description Invite a user to a list by username, or create a link invitation when no username is given. Only owners can invite, the link token is shown only once
+// @Tags invitations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param input body entity.CreateInvitationInput true "Optional username and role"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.CreatedInvitation} "Invitation created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or user not found"
+// @Failure 409 {object} utils.ErrorResponse "User is already a collaborator"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create invitation"
+// @Router /api/lists/{id}/invitations [post]
+func (h *Handler) CreateInvitation(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	var input entity.CreateInvitationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	invitation, err := h.Usecases.Invitation.Create(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.invitationErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to create invitation: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create invitation", map[string]string{
+			"database": "Error during invitation creation",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":       userId,
+		"list_id":       listId,
+		"invitation_id": invitation.Id,
+		"role":          invitation.Role,
+	}).Info("list invitation created")
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Invitation created successfully", invitation)
+}
+
+// getListInvitations godoc
+// @Summary Get pending invitations of a list
+// @Description Retrieve the invitations of a list that are still pending, only owners can see them
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.Invitation} "Invitations retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve invitations"
+// @Router /api/lists/{id}/invitations [get]
+func (h *Handler) GetListInvitations(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	invitations, err := h.Usecases.Invitation.GetAllForList(c.Request.Context(), userId, listId)
+	if err != nil {
+		if h.invitationErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to retrieve list invitations: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve invitations", map[string]string{
+			"database": "Error during invitations retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// getInvitations godoc
+// @Summary Get my pending invitations
+// @Description Retrieve the pending invitations addressed to the authenticated user
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.Invitation} "Invitations retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve invitations"
+// @Router /api/invitations/ [get]
+func (h *Handler) GetInvitations(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	invitations, err := h.Usecases.Invitation.GetAllForUser(c.Request.Context(), userId)
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to retrieve invitations: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve invitations", map[string]string{
+			"database": "Error during invitations retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// acceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Accept a pending invitation addressed to the authenticated user and join the list
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Invitation accepted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid invitationId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation is no longer pending"
+// @Failure 410 {object} utils.ErrorResponse "Invitation has expired"
+// @Failure 500 {object} utils.ErrorResponse "Failed to accept invitation"
+// @Router /api/invitations/{id}/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	h.respondToInvitation(c, "accept", "Invitation accepted successfully", h.Usecases.Invitation.Accept)
+}
+
+// declineInvitation godoc
+// @Summary Decline an invitation
+// @Description Decline a pending invitation addressed to the authenticated user
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Invitation declined successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid invitationId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation is no longer pending"
+// @Failure 410 {object} utils.ErrorResponse "Invitation has expired"
+// @Failure 500 {object} utils.ErrorResponse "Failed to decline invitation"
+// @Router /api/invitations/{id}/decline [post]
+func (h *Handler) DeclineInvitation(c *gin.Context) {
+	h.respondToInvitation(c, "decline", "Invitation declined successfully", h.Usecases.Invitation.Decline)
+}
+
+// revokeInvitation godoc
+// @Summary Revoke an invitation
+// @Description Withdraw a pending invitation, only owners of the list can revoke its invitations
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Invitation revoked successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid invitationId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation is no longer pending"
+// @Failure 410 {object} utils.ErrorResponse "Invitation has expired"
+// @Failure 500 {object} utils.ErrorResponse "Failed to revoke invitation"
+// @Router /api/invitations/{id} [delete]
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	h.respondToInvitation(c, "revoke", "Invitation revoked successfully", h.Usecases.Invitation.Revoke)
+}
+
+// acceptInvitationLink godoc
+// @Summary Accept a link invitation
+// @Description Redeem the token of a link invitation and join the list
+// @Tags invitations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.AcceptInvitationLinkInput true "Invitation token"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.Invitation} "Invitation accepted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Invitation not found"
+// @Failure 409 {object} utils.ErrorResponse "Invitation is no longer pending or user is already a collaborator"
+// @Failure 410 {object} utils.ErrorResponse "Invitation has expired"
+// @Failure 500 {object} utils.ErrorResponse "Failed to accept invitation"
+// @Router /api/invitations/accept [post]
+func (h *Handler) AcceptInvitationLink(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	var input entity.AcceptInvitationLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	invitation, err := h.Usecases.Invitation.AcceptLink(c.Request.Context(), userId, input)
+	if err != nil {
+		if h.invitationErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to accept invitation link: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to accept invitation", map[string]string{
+			"database": "Error during invitation acceptance",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":       userId,
+		"list_id":       invitation.ListId,
+		"invitation_id": invitation.Id,
+	}).Info("list invitation accepted")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Invitation accepted successfully", invitation)
+}
+
+// respondToInvitation runs an action of the authenticated user on the invitation identified by the id param
+func (h *Handler) respondToInvitation(c *gin.Context, action, successMessage string, respond func(ctx context.Context, userId, invitationId int) error) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	invitationId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid invitationId param", map[string]string{
+			"param": "invitationId must be a valid integer",
+		})
+		return
+	}
+
+	if err := respond(c.Request.Context(), userId, invitationId); err != nil {
+		if h.invitationErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":       userId,
+			"invitation_id": invitationId,
+		}).Errorf("failed to %s invitation: %s", action, err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to "+action+" invitation", map[string]string{
+			"database": "Error during invitation " + action,
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":       userId,
+		"invitation_id": invitationId,
+		"action":        action,
+	}).Info("list invitation updated")
+
+	utils.NewSuccessResponse(c, http.StatusOK, successMessage, nil)
+}
+
+// invitationErrorResponse responds to the errors of the invitation use cases that are caused by the request,
+// it reports whether a response was written
+func (h *Handler) invitationErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvitationNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Invitation not found", map[string]string{
+			"invitationId": "The requested invitation does not exist",
+		})
+	case utils.ErrInvitationNotPending:
+		utils.NewErrorResponse(c, http.StatusConflict, "Conflict", map[string]string{
+			"invitation": err.Error(),
+		})
+	case utils.ErrInvitationExpired:
+		utils.NewErrorResponse(c, http.StatusGone, "Invitation expired", map[string]string{
+			"invitation": err.Error(),
+		})
+	default:
+		return h.collaboratorErrorResponse(c, err)
+	}
+
+	return true
+}
@@ -0,0 +1,413 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupInvitationRouter registers a single invitation handler behind a fake authentication middleware
+func setupInvitationRouter(mockInvitation *MockInvitation, userId int, method, path string, handle func(*v1.Handler) gin.HandlerFunc) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Invitation: mockInvitation,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.Handle(method, path, handle(handler))
+
+	return r
+}
+
+// TestHandler_CreateInvitation tests the CreateInvitation handler
+func TestHandler_CreateInvitation(t *testing.T) {
+	expiresAt := time.Date(2024, 11, 6, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 10, 30, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		userId         int
+		body           string
+		mockBehavior   func(mockInvitation *MockInvitation)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Link invitation",
+			userId: 1,
+			body:   `{"role": "viewer"}`,
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Create", mock.Anything, 1, 3, entity.CreateInvitationInput{Role: entity.ListRoleViewer}).
+					Return(entity.CreatedInvitation{
+						Invitation: entity.Invitation{
+							Id:        5,
+							ListId:    3,
+							ListTitle: "Groceries",
+							InviterId: 1,
+							Role:      entity.ListRoleViewer,
+							Status:    entity.InvitationStatusPending,
+							ExpiresAt: expiresAt,
+							CreatedAt: createdAt,
+						},
+						Token: "tdi_secret",
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Invitation created successfully",
+				"data": {
+					"id": 5,
+					"list_id": 3,
+					"list_title": "Groceries",
+					"inviter_id": 1,
+					"invitee_id": null,
+					"role": "viewer",
+					"status": "pending",
+					"expires_at": "2024-11-06T09:00:00Z",
+					"created_at": "2024-10-30T09:00:00Z",
+					"token": "tdi_secret"
+				}
+			}`,
+		},
+		{
+			name:           "Invalid input",
+			userId:         1,
+			body:           `{"username": "bob"}`,
+			mockBehavior:   func(mockInvitation *MockInvitation) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Not an owner",
+			userId: 1,
+			body:   `{"username": "bob", "role": "editor"}`,
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Create", mock.Anything, 1, 3, entity.CreateInvitationInput{Username: "bob", Role: entity.ListRoleEditor}).
+					Return(entity.CreatedInvitation{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			body:   `{"role": "viewer"}`,
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Create", mock.Anything, 1, 3, entity.CreateInvitationInput{Role: entity.ListRoleViewer}).
+					Return(entity.CreatedInvitation{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create invitation",
+				"errors": {"database": "Error during invitation creation"}
+			}`,
+		},
+		{
+			name:           "Unauthorized",
+			userId:         0,
+			body:           `{"role": "viewer"}`,
+			mockBehavior:   func(mockInvitation *MockInvitation) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody: `{
+				"status": "error",
+				"message": "Unauthorized",
+				"errors": {"auth": "User authentication failed or user not logged in"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockInvitation := new(MockInvitation)
+			r := setupInvitationRouter(mockInvitation, testCase.userId, "POST", "/api/lists/:id/invitations", func(h *v1.Handler) gin.HandlerFunc {
+				return h.CreateInvitation
+			})
+
+			req := httptest.NewRequest("POST", "/api/lists/3/invitations", bytes.NewBufferString(testCase.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockInvitation)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockInvitation.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_AcceptInvitation tests the AcceptInvitation handler
+func TestHandler_AcceptInvitation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		invitationId   string
+		mockBehavior   func(mockInvitation *MockInvitation)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:         "Success",
+			invitationId: "5",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Accept", mock.Anything, 2, 5).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Invitation accepted successfully"
+			}`,
+		},
+		{
+			name:           "Invalid invitationId parameter",
+			invitationId:   "invalid",
+			mockBehavior:   func(mockInvitation *MockInvitation) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid invitationId param",
+				"errors": {"param": "invitationId must be a valid integer"}
+			}`,
+		},
+		{
+			name:         "Invitation not found",
+			invitationId: "5",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Accept", mock.Anything, 2, 5).Return(utils.ErrInvitationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invitation not found",
+				"errors": {"invitationId": "The requested invitation does not exist"}
+			}`,
+		},
+		{
+			name:         "Invitation expired",
+			invitationId: "5",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Accept", mock.Anything, 2, 5).Return(utils.ErrInvitationExpired)
+			},
+			expectedStatus: http.StatusGone,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invitation expired",
+				"errors": {"invitation": "invitation has expired"}
+			}`,
+		},
+		{
+			name:         "Already a collaborator",
+			invitationId: "5",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Accept", mock.Anything, 2, 5).Return(utils.ErrCollaboratorExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{
+				"status": "error",
+				"message": "Conflict",
+				"errors": {"collaborator": "user is already a collaborator of the list"}
+			}`,
+		},
+		{
+			name:         "Internal server error",
+			invitationId: "5",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Accept", mock.Anything, 2, 5).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to accept invitation",
+				"errors": {"database": "Error during invitation accept"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockInvitation := new(MockInvitation)
+			r := setupInvitationRouter(mockInvitation, 2, "POST", "/api/invitations/:id/accept", func(h *v1.Handler) gin.HandlerFunc {
+				return h.AcceptInvitation
+			})
+
+			req := httptest.NewRequest("POST", "/api/invitations/"+testCase.invitationId+"/accept", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockInvitation)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockInvitation.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RevokeInvitation tests the RevokeInvitation handler
+func TestHandler_RevokeInvitation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockInvitation *MockInvitation)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Revoke", mock.Anything, 2, 5).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Invitation revoked successfully"
+			}`,
+		},
+		{
+			name: "No longer pending",
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("Revoke", mock.Anything, 2, 5).Return(utils.ErrInvitationNotPending)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{
+				"status": "error",
+				"message": "Conflict",
+				"errors": {"invitation": "invitation is no longer pending"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockInvitation := new(MockInvitation)
+			r := setupInvitationRouter(mockInvitation, 2, "DELETE", "/api/invitations/:id", func(h *v1.Handler) gin.HandlerFunc {
+				return h.RevokeInvitation
+			})
+
+			req := httptest.NewRequest("DELETE", "/api/invitations/5", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockInvitation)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockInvitation.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_AcceptInvitationLink tests the AcceptInvitationLink handler
+func TestHandler_AcceptInvitationLink(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		mockBehavior   func(mockInvitation *MockInvitation)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Missing token",
+			body:           `{}`,
+			mockBehavior:   func(mockInvitation *MockInvitation) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name: "Unknown token",
+			body: `{"token": "tdi_unknown"}`,
+			mockBehavior: func(mockInvitation *MockInvitation) {
+				mockInvitation.On("AcceptLink", mock.Anything, 2, entity.AcceptInvitationLinkInput{Token: "tdi_unknown"}).
+					Return(entity.Invitation{}, utils.ErrInvitationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invitation not found",
+				"errors": {"invitationId": "The requested invitation does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockInvitation := new(MockInvitation)
+			r := setupInvitationRouter(mockInvitation, 2, "POST", "/api/invitations/accept", func(h *v1.Handler) gin.HandlerFunc {
+				return h.AcceptInvitationLink
+			})
+
+			req := httptest.NewRequest("POST", "/api/invitations/accept", bytes.NewBufferString(testCase.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockInvitation)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockInvitation.AssertExpectations(t)
+		})
+	}
+}
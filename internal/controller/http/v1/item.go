@@ -13,18 +13,26 @@ import (
 
 // createItem godoc
 // @Summary Create a new item
-// @Description Create a new item in a list for the authenticated user
+// @Description Create a new item in a list for the authenticated user, due_at and remind_at are optional RFC 3339 timestamps with a timezone offset.
+// @Description A recurrence with an iCalendar RRULE and an IANA timezone makes the item repeat, recurring items need a due date.
+// @Description A parent_id of an item of the same list makes the new item a subtask.
+// @Description A retry with the same Idempotency-Key and body replays the original response instead of creating another item
 // @Tags items
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "List ID"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Param input body entity.Item true "Item data"
 // @Success 201 {object} utils.SuccessResponse "Item created successfully"
+// @Header 201 {string} Idempotent-Replayed "true when the response is a replay"
 // @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 409 {object} utils.ErrorResponse "Request with the Idempotency-Key is still in progress"
+// @Failure 422 {object} utils.ErrorResponse "Idempotency-Key was used with a different request"
 // @Failure 500 {object} utils.ErrorResponse "Failed to create item"
 // @Router /api/lists/{id}/items/ [post]
 func (h *Handler) CreateItem(c *gin.Context) {
@@ -69,6 +77,20 @@ func (h *Handler) CreateItem(c *gin.Context) {
 
 	itemId, err := h.Usecases.Item.Create(c.Request.Context(), userId, listId, &input)
 	if err != nil {
+		if isItemValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id": userId,
 			"list_id": listId,
@@ -88,15 +110,23 @@ func (h *Handler) CreateItem(c *gin.Context) {
 
 // getAllItems godoc
 // @Summary Get all items in a list
-// @Description Retrieve all items in a specific list for the authenticated user
+// @Description Retrieve a page of the items in a specific list for the authenticated user, in the order of the list unless another sort is given.
+// @Description Optionally only the items with the done state or having every one of the given tags are returned, items without a due date
+// @Description come last when sorted by due date. Pass the next_cursor of a page as cursor with the same params to get the following page
 // @Tags items
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "List ID"
+// @Param tags query string false "Comma separated tag names"
+// @Param done query bool false "Only the items with this done state"
+// @Param limit query int false "Page size, 50 by default and at most 100"
+// @Param cursor query string false "Cursor of the previous page"
+// @Param sort query string false "Sort key" Enums(position, created, updated, title, due)
+// @Param order query string false "Sort order, asc by default" Enums(asc, desc)
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
-// @Success 200 {object} utils.SuccessResponse{data=[]entity.Item} "Items retrieved successfully"
-// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Success 200 {object} utils.PageResponse{data=[]entity.Item} "Items retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId or page params"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 500 {object} utils.ErrorResponse "Failed to retrieve items"
 // @Router /api/lists/{id}/items/ [get]
@@ -128,8 +158,29 @@ func (h *Handler) GetAllItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.Usecases.Item.GetAll(c.Request.Context(), userId, listId)
+	pageQuery, ok := parsePageQuery(c)
+	if !ok {
+		return
+	}
+
+	query := entity.ItemQuery{
+		PageQuery: pageQuery,
+		Tags:      utils.ParseOptionalParamAsList(c, "tags"),
+	}
+
+	if err := utils.ParseOptionalParamAsBool(c, "done", &query.Done); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid done param", map[string]string{
+			"param": "done must be a valid boolean",
+		})
+		return
+	}
+
+	page, err := h.Usecases.Item.GetAll(c.Request.Context(), userId, listId, query)
 	if err != nil {
+		if pageErrorResponse(c, err) {
+			return
+		}
+
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id": userId,
 			"list_id": listId,
@@ -140,19 +191,23 @@ func (h *Handler) GetAllItems(c *gin.Context) {
 		return
 	}
 
-	utils.NewSuccessResponse(c, http.StatusOK, "Items retrieved successfully", items)
+	utils.NewPageResponse(c, http.StatusOK, "Items retrieved successfully", page.Items, page.NextCursor)
 }
 
 // getItemById godoc
 // @Summary Get an item by ID
-// @Description Retrieve a specific item by ID for the authenticated user
+// @Description Retrieve a specific item by ID for the authenticated user, the ETag header holds the version of the item.
+// @Description With an If-None-Match of the current ETag nothing is returned
 // @Tags items
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "Item ID"
+// @Param If-None-Match header string false "ETag of a previously retrieved version"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse{data=entity.Item} "Item retrieved successfully"
+// @Header 200 {string} ETag "Version of the item"
+// @Success 304 "Item not modified"
 // @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 404 {object} utils.ErrorResponse "Item not found"
@@ -202,24 +257,36 @@ func (h *Handler) GetItemById(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, item.Version) {
+		return
+	}
+
+	setETag(c, item.Version)
 	utils.NewSuccessResponse(c, http.StatusOK, "Item retrieved successfully", item)
 }
 
 // updateItem godoc
 // @Summary Update an item
-// @Description Update a specific item by ID for the authenticated user
+// @Description Update a specific item by ID for the authenticated user, setting remind_at schedules a new reminder.
+// @Description For recurring items the scope "this" changes only this occurrence and "future" also changes the next occurrences,
+// @Description the recurrence can only be changed for future occurrences and an empty rule stops the item from repeating.
+// @Description Marking an occurrence as done creates the next one, and marking an item as done with cascade also completes all of its subtasks.
+// @Description With an If-Match the item is only updated while its ETag is still the same
 // @Tags items
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Item ID"
 // @Param input body entity.UpdateItemInput true "Updated item data"
+// @Param If-Match header string false "ETag the item must still have"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "Item updated successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid input or itemId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
 // @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 412 {object} utils.ErrorResponse "Item was modified since the If-Match ETag"
 // @Failure 500 {object} utils.ErrorResponse "Failed to update item"
 // @Router /api/items/{id} [put]
 func (h *Handler) UpdateItem(c *gin.Context) {
@@ -283,8 +350,31 @@ func (h *Handler) UpdateItem(c *gin.Context) {
 		return
 	}
 
-	err = h.Usecases.Item.UpdateOneById(c.Request.Context(), userId, listId, itemId, input)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Item.UpdateOneById(c.Request.Context(), userId, listId, itemId, input, version)
 	if err != nil {
+		if preconditionErrorResponse(c, err) {
+			return
+		}
+
+		if isItemValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
 		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
 			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
 				"itemId": "The requested item does not exist",
@@ -308,16 +398,19 @@ func (h *Handler) UpdateItem(c *gin.Context) {
 
 // deleteItem godoc
 // @Summary Delete an item
-// @Description Delete a specific item by ID for the authenticated user
+// @Description Delete a specific item by ID for the authenticated user, with an If-Match the item is only deleted while its ETag is still the same
 // @Tags items
 // @Security BearerAuth
 // @Param id path int true "Item ID"
+// @Param If-Match header string false "ETag the item must still have"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "Item deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
 // @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 412 {object} utils.ErrorResponse "Item was modified since the If-Match ETag"
 // @Failure 500 {object} utils.ErrorResponse "Failed to delete item"
 // @Router /api/items/{id} [delete]
 func (h *Handler) DeleteItem(c *gin.Context) {
@@ -353,8 +446,24 @@ func (h *Handler) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	err = h.Usecases.Item.DeleteOneById(c.Request.Context(), userId, listId, itemId)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Item.DeleteOneById(c.Request.Context(), userId, listId, itemId, version)
 	if err != nil {
+		if preconditionErrorResponse(c, err) {
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
 		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
 			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
 				"itemId": "The requested item does not exist",
@@ -382,11 +491,14 @@ func (h *Handler) DeleteItem(c *gin.Context) {
 // @Summary Delete an item by admin
 // @Description Delete a specific item by ID by admin
 // @Tags items
+// @Security BearerAuth
 // @Param id path int true "Item ID"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "Item deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
 // @Failure 404 {object} utils.ErrorResponse "Item not found"
 // @Failure 500 {object} utils.ErrorResponse "Failed to delete item"
 // @Router /private/api/items/{id} [delete]
@@ -428,14 +540,18 @@ func (h *Handler) DeleteItemByAdmin(c *gin.Context) {
 
 // searchItems godoc
 // @Summary Search items
-// @Description Search for items of the authenticated user by a query parameter
+// @Description Search for items of the authenticated user by a query parameter, optionally only the items having every one of the given tags.
+// @Description A page of the items is returned from the best match, pass the next_cursor of a page as cursor to get the following page
 // @Tags items
 // @Security BearerAuth
 // @Produce json
 // @Param query query string true "Search query"
+// @Param tags query string false "Comma separated tag names"
+// @Param limit query int false "Page size, 50 by default and at most 100"
+// @Param cursor query string false "Cursor of the previous page"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
-// @Success 200 {object} utils.SuccessResponse{data=[]entity.Item} "Items searched successfully"
+// @Success 200 {object} utils.PageResponse{data=[]entity.Item} "Items searched successfully"
 // @Failure 400 {object} utils.ErrorResponse "Empty query parameter"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 500 {object} utils.ErrorResponse "Failed to search items"
@@ -485,8 +601,19 @@ func (h *Handler) SearchItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.Usecases.Item.Search(c.Request.Context(), userId, listId, done, searchText)
+	tags := utils.ParseOptionalParamAsList(c, "tags")
+
+	query, ok := parsePageQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.Usecases.Item.Search(c.Request.Context(), userId, listId, done, tags, searchText, query)
 	if err != nil {
+		if pageErrorResponse(c, err) {
+			return
+		}
+
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id":     userId,
 			"search_text": searchText,
@@ -500,5 +627,16 @@ func (h *Handler) SearchItems(c *gin.Context) {
 
 	h.Metrics.IncrementSearchedItems()
 
-	utils.NewSuccessResponse(c, http.StatusOK, "Item searched successfully", items)
+	utils.NewPageResponse(c, http.StatusOK, "Item searched successfully", page.Items, page.NextCursor)
+}
+
+// isItemValidationError checks if the error is caused by an invalid recurrence or parent of an item
+func isItemValidationError(err error) bool {
+	switch err {
+	case utils.ErrInvalidRecurrence, utils.ErrInvalidTimezone, utils.ErrRecurrenceWithoutDueDate, utils.ErrRecurrenceChangeNeedsFuture,
+		utils.ErrItemParentNotInList, utils.ErrItemParentCycle:
+		return true
+	default:
+		return false
+	}
 }
@@ -69,6 +69,28 @@ func TestHandler_CreateItem(t *testing.T) {
 				"errors": {"param": "listId must be a valid integer"}
 			}`,
 		},
+		{
+			name:   "Recurrence without due date",
+			userId: 1,
+			listId: "2",
+			input: `{
+				"title": "New Item",
+				"recurrence": {"rule": "FREQ=WEEKLY;BYDAY=MO"}
+			}`,
+			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
+				mockItem.On("Create", mock.Anything, 1, 2, &entity.Item{
+					Title:      "New Item",
+					Recurrence: &entity.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO"},
+				}).Return(0, utils.ErrRecurrenceWithoutDueDate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "recurring items need a due date"}
+			}`,
+		},
 		{
 			name:           "Invalid JSON input",
 			userId:         1,
@@ -194,6 +216,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 		name           string
 		userId         int
 		listId         string
+		query          string
 		mockBehavior   func(mockList *MockList, mockItem *MockItem)
 		expectedStatus int
 		expectedBody   string
@@ -204,7 +227,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 			listId: "2",
 			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
 				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
-				mockItem.On("GetAll", mock.Anything, 1, 2).Return([]entity.Item{
+				mockItem.On("GetAll", mock.Anything, 1, 2, entity.ItemQuery{Tags: []string{}}).Return(entity.ItemPage{Items: []entity.Item{
 					{
 						Id:          1,
 						Title:       "Item 1",
@@ -217,7 +240,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 						Description: "Description 2",
 						Done:        true,
 					},
-				}, nil)
+				}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -236,7 +259,100 @@ func TestHandler_GetAllItems(t *testing.T) {
 						"description": "Description 2",
 						"done": true
 					}
-				]
+				],
+				"next_cursor": null
+			}`,
+		},
+		{
+			name:   "Success with tags",
+			userId: 1,
+			listId: "2",
+			query:  "?tags=work,%20urgent,,work",
+			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
+				mockItem.On("GetAll", mock.Anything, 1, 2, entity.ItemQuery{Tags: []string{"work", "urgent"}}).Return(entity.ItemPage{Items: []entity.Item{
+					{
+						Id:    2,
+						Title: "Item 2",
+						Done:  true,
+					},
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Items retrieved successfully",
+				"data": [
+					{
+						"id": 2,
+						"title": "Item 2",
+						"description": "",
+						"done": true
+					}
+				],
+				"next_cursor": null
+			}`,
+		},
+		{
+			name:   "Done items sorted by due date",
+			userId: 1,
+			listId: "2",
+			query:  "?done=true&sort=due&order=desc&limit=1",
+			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
+				done := true
+				nextCursor := "abc"
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
+				mockItem.On("GetAll", mock.Anything, 1, 2, entity.ItemQuery{
+					PageQuery: entity.PageQuery{Limit: 1, Sort: "due", Order: "desc"},
+					Done:      &done,
+					Tags:      []string{},
+				}).Return(entity.ItemPage{Items: []entity.Item{{Id: 3, Title: "Item 3", Done: true}}, NextCursor: &nextCursor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Items retrieved successfully",
+				"data": [
+					{
+						"id": 3,
+						"title": "Item 3",
+						"description": "",
+						"done": true
+					}
+				],
+				"next_cursor": "abc"
+			}`,
+		},
+		{
+			name:   "Invalid done parameter",
+			userId: 1,
+			listId: "2",
+			query:  "?done=maybe",
+			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid done param",
+				"errors": {"param": "done must be a valid boolean"}
+			}`,
+		},
+		{
+			name:   "Invalid cursor",
+			userId: 1,
+			listId: "2",
+			query:  "?cursor=abc",
+			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
+				mockItem.On("GetAll", mock.Anything, 1, 2, entity.ItemQuery{PageQuery: entity.PageQuery{Cursor: "abc"}, Tags: []string{}}).
+					Return(entity.ItemPage{}, utils.ErrInvalidPageCursor)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "cursor is invalid or was issued for another sort"}
 			}`,
 		},
 		{
@@ -271,7 +387,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 			listId: "2",
 			mockBehavior: func(mockList *MockList, mockItem *MockItem) {
 				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2}, nil)
-				mockItem.On("GetAll", mock.Anything, 1, 2).Return([]entity.Item{}, errors.New("db error"))
+				mockItem.On("GetAll", mock.Anything, 1, 2, entity.ItemQuery{Tags: []string{}}).Return(entity.ItemPage{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -326,7 +442,7 @@ func TestHandler_GetAllItems(t *testing.T) {
 
 			r.GET("/api/lists/:id/items", handler.GetAllItems)
 
-			req := httptest.NewRequest("GET", "/api/lists/"+testCase.listId+"/items", nil)
+			req := httptest.NewRequest("GET", "/api/lists/"+testCase.listId+"/items"+testCase.query, nil)
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockList, mockItem)
@@ -348,8 +464,10 @@ func TestHandler_GetItemById(t *testing.T) {
 		name           string
 		userId         int
 		itemId         string
+		ifNoneMatch    string
 		mockBehavior   func(mockItem *MockItem)
 		expectedStatus int
+		expectedETag   string
 		expectedBody   string
 	}{
 		{
@@ -362,9 +480,11 @@ func TestHandler_GetItemById(t *testing.T) {
 					Title:       "Item 1",
 					Description: "Description 1",
 					Done:        false,
+					Version:     3,
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
 			expectedBody: `{
 				"status": "ok",
 				"message": "Item retrieved successfully",
@@ -372,7 +492,41 @@ func TestHandler_GetItemById(t *testing.T) {
 					"id": 2,
 					"title": "Item 1",
 					"description": "Description 1",
-					"done": false
+					"done": false,
+					"version": 3
+				}
+			}`,
+		},
+		{
+			name:        "Not modified",
+			userId:      1,
+			itemId:      "2",
+			ifNoneMatch: `W/"2", "3"`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("GetOneById", mock.Anything, 1, 2).Return(entity.Item{Id: 2, Title: "Item 1", Version: 3}, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:        "Modified since If-None-Match",
+			userId:      1,
+			itemId:      "2",
+			ifNoneMatch: `"2"`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("GetOneById", mock.Anything, 1, 2).Return(entity.Item{Id: 2, Title: "Item 1", Version: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Item retrieved successfully",
+				"data": {
+					"id": 2,
+					"title": "Item 1",
+					"description": "",
+					"done": false,
+					"version": 3
 				}
 			}`,
 		},
@@ -474,6 +628,9 @@ func TestHandler_GetItemById(t *testing.T) {
 			r.GET("/api/items/:id", handler.GetItemById)
 
 			req := httptest.NewRequest("GET", "/api/items/"+testCase.itemId, nil)
+			if testCase.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", testCase.ifNoneMatch)
+			}
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockItem)
@@ -481,7 +638,12 @@ func TestHandler_GetItemById(t *testing.T) {
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, testCase.expectedStatus, w.Code)
-			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+			if testCase.expectedBody == "" {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+			}
 
 			mockItem.AssertExpectations(t)
 		})
@@ -513,7 +675,8 @@ func TestHandler_UpdateItem(t *testing.T) {
 				mockItem.On("UpdateOneById", mock.Anything, 1, 3, 2, entity.UpdateItemInput{
 					Title:       &title,
 					Description: &description,
-				}).Return(nil)
+					Scope:       entity.ItemEditScopeThis,
+				}, (*int)(nil)).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -549,6 +712,39 @@ func TestHandler_UpdateItem(t *testing.T) {
 				"errors": {"param": "listId must be a valid integer"}
 			}`,
 		},
+		{
+			name:           "Invalid scope",
+			userId:         1,
+			listId:         "3",
+			itemId:         "2",
+			input:          `{"title": "Updated Title", "scope": "past"}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "scope must be this or future"}
+			}`,
+		},
+		{
+			name:   "Recurrence change of a single occurrence",
+			userId: 1,
+			listId: "3",
+			itemId: "2",
+			input:  `{"recurrence": {"rule": "FREQ=DAILY"}}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("UpdateOneById", mock.Anything, 1, 3, 2, entity.UpdateItemInput{
+					Recurrence: &entity.Recurrence{Rule: "FREQ=DAILY", Timezone: entity.DefaultRecurrenceTimezone},
+					Scope:      entity.ItemEditScopeThis,
+				}, (*int)(nil)).Return(utils.ErrRecurrenceChangeNeedsFuture)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "the recurrence of a series can only be changed for all future occurrences"}
+			}`,
+		},
 		{
 			name:           "Invalid JSON input",
 			userId:         1,
@@ -572,7 +768,8 @@ func TestHandler_UpdateItem(t *testing.T) {
 			mockBehavior: func(mockItem *MockItem) {
 				mockItem.On("UpdateOneById", mock.Anything, 1, 3, 2, entity.UpdateItemInput{
 					Title: &title,
-				}).Return(utils.ErrUserNotOwner)
+					Scope: entity.ItemEditScopeThis,
+				}, (*int)(nil)).Return(utils.ErrUserNotOwner)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -590,7 +787,8 @@ func TestHandler_UpdateItem(t *testing.T) {
 			mockBehavior: func(mockItem *MockItem) {
 				mockItem.On("UpdateOneById", mock.Anything, 1, 3, 2, entity.UpdateItemInput{
 					Title: &title,
-				}).Return(utils.ErrItemNotFound)
+					Scope: entity.ItemEditScopeThis,
+				}, (*int)(nil)).Return(utils.ErrItemNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -608,7 +806,8 @@ func TestHandler_UpdateItem(t *testing.T) {
 			mockBehavior: func(mockItem *MockItem) {
 				mockItem.On("UpdateOneById", mock.Anything, 1, 3, 2, entity.UpdateItemInput{
 					Title: &title,
-				}).Return(errors.New("db error"))
+					Scope: entity.ItemEditScopeThis,
+				}, (*int)(nil)).Return(errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -685,6 +884,7 @@ func TestHandler_DeleteItem(t *testing.T) {
 		userId         int
 		listId         string
 		itemId         string
+		ifMatch        string
 		mockBehavior   func(mockItem *MockItem)
 		expectedStatus int
 		expectedBody   string
@@ -695,7 +895,7 @@ func TestHandler_DeleteItem(t *testing.T) {
 			listId: "3",
 			itemId: "2",
 			mockBehavior: func(mockItem *MockItem) {
-				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2).Return(nil)
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, (*int)(nil)).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -735,7 +935,7 @@ func TestHandler_DeleteItem(t *testing.T) {
 			listId: "3",
 			itemId: "2",
 			mockBehavior: func(mockItem *MockItem) {
-				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2).Return(utils.ErrUserNotOwner)
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, (*int)(nil)).Return(utils.ErrUserNotOwner)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -744,13 +944,28 @@ func TestHandler_DeleteItem(t *testing.T) {
 				"errors": {"itemId": "The requested item does not exist"}
 			}`,
 		},
+		{
+			name:   "Insufficient role",
+			userId: 1,
+			listId: "3",
+			itemId: "2",
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, (*int)(nil)).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
 		{
 			name:   "Item not found",
 			userId: 1,
 			listId: "3",
 			itemId: "2",
 			mockBehavior: func(mockItem *MockItem) {
-				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2).Return(utils.ErrItemNotFound)
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, (*int)(nil)).Return(utils.ErrItemNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -765,7 +980,7 @@ func TestHandler_DeleteItem(t *testing.T) {
 			listId: "3",
 			itemId: "2",
 			mockBehavior: func(mockItem *MockItem) {
-				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2).Return(errors.New("db error"))
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, (*int)(nil)).Return(errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -774,6 +989,53 @@ func TestHandler_DeleteItem(t *testing.T) {
 				"errors": {"database": "Error during item deletion"}
 			}`,
 		},
+		{
+			name:    "If-Match version matches",
+			userId:  1,
+			listId:  "3",
+			itemId:  "2",
+			ifMatch: `"4"`,
+			mockBehavior: func(mockItem *MockItem) {
+				version := 4
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, &version).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Item deleted successfully"
+			}`,
+		},
+		{
+			name:    "If-Match version mismatch",
+			userId:  1,
+			listId:  "3",
+			itemId:  "2",
+			ifMatch: `"4"`,
+			mockBehavior: func(mockItem *MockItem) {
+				version := 4
+				mockItem.On("DeleteOneById", mock.Anything, 1, 3, 2, &version).Return(utils.ErrVersionMismatch)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody: `{
+				"status": "error",
+				"message": "Precondition failed",
+				"errors": {"version": "resource was modified since the version given in If-Match"}
+			}`,
+		},
+		{
+			name:           "Invalid If-Match",
+			userId:         1,
+			listId:         "3",
+			itemId:         "2",
+			ifMatch:        `W/"4"`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody: `{
+				"status": "error",
+				"message": "Precondition failed",
+				"errors": {"version": "If-Match must be the ETag of the resource"}
+			}`,
+		},
 		{
 			name:           "Unauthorized",
 			userId:         0,
@@ -819,6 +1081,9 @@ func TestHandler_DeleteItem(t *testing.T) {
 			r.DELETE("/api/items/:id", handler.DeleteItem)
 
 			req := httptest.NewRequest("DELETE", "/api/items/"+testCase.itemId+"?list_id="+testCase.listId, nil)
+			if testCase.ifMatch != "" {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockItem)
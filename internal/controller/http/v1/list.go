@@ -13,17 +13,22 @@ import (
 
 // createList godoc
 // @Summary Create a new list
-// @Description Create a new list for the authenticated user
+// @Description Create a new list for the authenticated user. A retry with the same Idempotency-Key and body replays
+// @Description the original response instead of creating another list
 // @Tags lists
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param input body entity.List true "List data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 201 {object} utils.SuccessResponse "List created successfully"
+// @Header 201 {string} Idempotent-Replayed "true when the response is a replay"
 // @Failure 400 {object} utils.ErrorResponse "Invalid input"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Request with the Idempotency-Key is still in progress"
+// @Failure 422 {object} utils.ErrorResponse "Idempotency-Key was used with a different request"
 // @Failure 500 {object} utils.ErrorResponse "Failed to create list"
 // @Router /api/lists/ [post]
 func (h *Handler) CreateList(c *gin.Context) {
@@ -69,13 +74,19 @@ func (h *Handler) CreateList(c *gin.Context) {
 
 // getAllLists godoc
 // @Summary Get all lists
-// @Description Retrieve all lists for the authenticated user
+// @Description Retrieve a page of the lists of the authenticated user, in the order chosen by the user unless another sort is given.
+// @Description Pass the next_cursor of a page as cursor with the same sort and order to get the following page
 // @Tags lists
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Page size, 50 by default and at most 100"
+// @Param cursor query string false "Cursor of the previous page"
+// @Param sort query string false "Sort key" Enums(position, created, updated, title)
+// @Param order query string false "Sort order, asc by default" Enums(asc, desc)
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
-// @Success 200 {object} utils.SuccessResponse{data=[]entity.List} "Lists retrieved successfully"
+// @Success 200 {object} utils.PageResponse{data=[]entity.List} "Lists retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid page params"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 500 {object} utils.ErrorResponse "Failed to retrieve lists"
 // @Router /api/lists/ [get]
@@ -89,8 +100,17 @@ func (h *Handler) GetAllLists(c *gin.Context) {
 		return
 	}
 
-	lists, err := h.Usecases.List.GetAll(c.Request.Context(), userId)
+	query, ok := parsePageQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.Usecases.List.GetAll(c.Request.Context(), userId, query)
 	if err != nil {
+		if pageErrorResponse(c, err) {
+			return
+		}
+
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id": userId,
 		}).Errorf("failed to retrieve lists: %s", err)
@@ -101,19 +121,23 @@ func (h *Handler) GetAllLists(c *gin.Context) {
 		return
 	}
 
-	utils.NewSuccessResponse(c, http.StatusOK, "Lists retrieved successfully", lists)
+	utils.NewPageResponse(c, http.StatusOK, "Lists retrieved successfully", page.Lists, page.NextCursor)
 }
 
 // getListById godoc
 // @Summary Get a list by ID
-// @Description Retrieve a specific list by ID for the authenticated user
+// @Description Retrieve a specific list by ID for the authenticated user, the ETag header holds the version of the list.
+// @Description With an If-None-Match of the current ETag nothing is returned
 // @Tags lists
 // @Security BearerAuth
 // @Produce json
 // @Param id path int true "List ID"
+// @Param If-None-Match header string false "ETag of a previously retrieved version"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse{data=entity.List} "List retrieved successfully"
+// @Header 200 {string} ETag "Version of the list"
+// @Success 304 "List not modified"
 // @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 404 {object} utils.ErrorResponse "List not found"
@@ -161,24 +185,32 @@ func (h *Handler) GetListById(c *gin.Context) {
 		return
 	}
 
+	if notModified(c, list.Version) {
+		return
+	}
+
+	setETag(c, list.Version)
 	utils.NewSuccessResponse(c, http.StatusOK, "List retrieved successfully", list)
 }
 
 // updateList godoc
 // @Summary Update a list
-// @Description Update a specific list by ID for the authenticated user
+// @Description Update a specific list by ID for the authenticated user, with an If-Match the list is only updated while its ETag is still the same
 // @Tags lists
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "List ID"
 // @Param input body entity.UpdateListInput true "Updated list data"
+// @Param If-Match header string false "ETag the list must still have"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "List updated successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
 // @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 412 {object} utils.ErrorResponse "List was modified since the If-Match ETag"
 // @Failure 500 {object} utils.ErrorResponse "Failed to update list"
 // @Router /api/lists/{id} [put]
 func (h *Handler) UpdateList(c *gin.Context) {
@@ -230,8 +262,24 @@ func (h *Handler) UpdateList(c *gin.Context) {
 		return
 	}
 
-	err = h.Usecases.List.UpdateOneById(c.Request.Context(), userId, listId, newTodoInput)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.List.UpdateOneById(c.Request.Context(), userId, listId, newTodoInput, version)
 	if err != nil {
+		if preconditionErrorResponse(c, err) {
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
 		if err == utils.ErrUserNotOwner || err == utils.ErrListNotFound {
 			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
 				"listId": "The requested list does not exist",
@@ -255,16 +303,19 @@ func (h *Handler) UpdateList(c *gin.Context) {
 
 // deleteList godoc
 // @Summary Delete a list
-// @Description Delete a specific list by Id for the authenticated user
+// @Description Delete a specific list by Id for the authenticated user, with an If-Match the list is only deleted while its ETag is still the same
 // @Tags lists
 // @Security BearerAuth
 // @Param id path int true "List ID"
+// @Param If-Match header string false "ETag the list must still have"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "List deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
 // @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 412 {object} utils.ErrorResponse "List was modified since the If-Match ETag"
 // @Failure 500 {object} utils.ErrorResponse "Failed to delete list"
 // @Router /api/lists/{id} [delete]
 func (h *Handler) DeleteList(c *gin.Context) {
@@ -289,8 +340,24 @@ func (h *Handler) DeleteList(c *gin.Context) {
 		return
 	}
 
-	err = h.Usecases.List.DeleteOneById(c.Request.Context(), userId, listId)
+	version, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.List.DeleteOneById(c.Request.Context(), userId, listId, version)
 	if err != nil {
+		if preconditionErrorResponse(c, err) {
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
 		if err == utils.ErrUserNotOwner || err == utils.ErrListNotFound {
 			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
 				"listId": "The requested list does not exist",
@@ -318,11 +385,14 @@ func (h *Handler) DeleteList(c *gin.Context) {
 // @Summary Delete a list by admin
 // @Description Delete a specific list by Id by admin
 // @Tags lists
+// @Security BearerAuth
 // @Param id path int true "List ID"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "List deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
 // @Failure 404 {object} utils.ErrorResponse "List not found"
 // @Failure 500 {object} utils.ErrorResponse "Failed to delete list"
 // @Router /private/api/lists/{id} [delete]
@@ -363,14 +433,17 @@ func (h *Handler) DeleteListByAdmin(c *gin.Context) {
 
 // searchLists godoc
 // @Summary Search lists
-// @Description Search for lists of the authenticated user by a query parameter
+// @Description Search for lists of the authenticated user by a query parameter, a page of the lists is returned from the best match.
+// @Description Pass the next_cursor of a page as cursor to get the following page
 // @Tags lists
 // @Security BearerAuth
 // @Produce json
 // @Param query query string true "Search query"
+// @Param limit query int false "Page size, 50 by default and at most 100"
+// @Param cursor query string false "Cursor of the previous page"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
-// @Success 200 {object} utils.SuccessResponse{data=[]entity.List} "Lists searched successfully"
+// @Success 200 {object} utils.PageResponse{data=[]entity.List} "Lists searched successfully"
 // @Failure 400 {object} utils.ErrorResponse "Empty query parameter"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
 // @Failure 500 {object} utils.ErrorResponse "Failed to search lists"
@@ -396,8 +469,17 @@ func (h *Handler) SearchLists(c *gin.Context) {
 		return
 	}
 
-	lists, err := h.Usecases.List.Search(c.Request.Context(), userId, searchText)
+	query, ok := parsePageQuery(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.Usecases.List.Search(c.Request.Context(), userId, searchText, query)
 	if err != nil {
+		if pageErrorResponse(c, err) {
+			return
+		}
+
 		h.Logger.WithFields(map[string]interface{}{
 			"user_id":     userId,
 			"search_text": searchText,
@@ -411,5 +493,5 @@ func (h *Handler) SearchLists(c *gin.Context) {
 
 	h.Metrics.IncrementSearchedLists()
 
-	utils.NewSuccessResponse(c, http.StatusOK, "Lists searched successfully", lists)
+	utils.NewPageResponse(c, http.StatusOK, "Lists searched successfully", page.Lists, page.NextCursor)
 }
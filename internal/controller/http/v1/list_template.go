@@ -0,0 +1,370 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cloneList godoc
+// @Summary Clone a list
+// @Description Copy a list shared with the user together with its items into a new list owned by the user.
+// @Description The items keep their subtask structure and done state unless reset_done is set, and start outside of any series and without tags
+// @Tags lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param input body entity.CloneListInput false "Clone options"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.ListCopy} "List cloned successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to clone list"
+// @Router /api/lists/{id}/clone [post]
+func (h *Handler) CloneList(c *gin.Context) {
+	userId, listId, ok := h.bindListTemplateParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.CloneListInput
+	if !h.bindOptionalJSON(c, &input) {
+		return
+	}
+
+	listCopy, err := h.Usecases.List.Clone(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.listTemplateErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to clone list: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to clone list", map[string]string{
+			"database": "Error during list cloning",
+		})
+		return
+	}
+
+	h.incrementCreatedListCopy(&listCopy)
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "List cloned successfully", listCopy)
+}
+
+// createListTemplate godoc
+// @Summary Save a list as a template
+// @Description Save a list shared with the user as a reusable template of the user. The template keeps the items
+// @Description and their subtask structure, and the title and description of the list unless they are given
+// @Tags templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param input body entity.CreateListTemplateInput false "Template data"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.ListTemplate} "Template created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create template"
+// @Router /api/lists/{id}/templates [post]
+func (h *Handler) CreateListTemplate(c *gin.Context) {
+	userId, listId, ok := h.bindListTemplateParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.CreateListTemplateInput
+	if !h.bindOptionalJSON(c, &input) {
+		return
+	}
+
+	template, err := h.Usecases.List.CreateTemplate(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.listTemplateErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to create list template: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create template", map[string]string{
+			"database": "Error during template creation",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Template created successfully", template)
+}
+
+// getListTemplates godoc
+// @Summary Get all templates
+// @Description Retrieve the list templates of the authenticated user with the number of their items, the most recent first
+// @Tags templates
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.ListTemplate} "Templates retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve templates"
+// @Router /api/templates/ [get]
+func (h *Handler) GetListTemplates(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	templates, err := h.Usecases.List.GetTemplates(c.Request.Context(), userId)
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to retrieve list templates: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve templates", map[string]string{
+			"database": "Error during template retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Templates retrieved successfully", templates)
+}
+
+// getListTemplate godoc
+// @Summary Get a template by ID
+// @Description Retrieve a list template of the authenticated user with its items
+// @Tags templates
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.ListTemplate} "Template retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid templateId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Template not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve template"
+// @Router /api/templates/{id} [get]
+func (h *Handler) GetListTemplate(c *gin.Context) {
+	userId, templateId, ok := h.bindTemplateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.Usecases.List.GetTemplate(c.Request.Context(), userId, templateId)
+	if err != nil {
+		if h.listTemplateErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":     userId,
+			"template_id": templateId,
+		}).Errorf("failed to retrieve list template: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve template", map[string]string{
+			"database": "Error during template retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Template retrieved successfully", template)
+}
+
+// deleteListTemplate godoc
+// @Summary Delete a template
+// @Description Delete a list template of the authenticated user, lists created from the template are kept
+// @Tags templates
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Template deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid templateId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Template not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to delete template"
+// @Router /api/templates/{id} [delete]
+func (h *Handler) DeleteListTemplate(c *gin.Context) {
+	userId, templateId, ok := h.bindTemplateParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.Usecases.List.DeleteTemplate(c.Request.Context(), userId, templateId)
+	if err != nil {
+		if h.listTemplateErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":     userId,
+			"template_id": templateId,
+		}).Errorf("failed to delete list template: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to delete template", map[string]string{
+			"database": "Error during template deletion",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Template deleted successfully", nil)
+}
+
+// createListFromTemplate godoc
+// @Summary Create a list from a template
+// @Description Create a new list owned by the authenticated user from one of their templates, the items start undone
+// @Tags templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param input body entity.UseListTemplateInput false "List data"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.ListCopy} "List created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or templateId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Template not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create list"
+// @Router /api/templates/{id}/lists [post]
+func (h *Handler) CreateListFromTemplate(c *gin.Context) {
+	userId, templateId, ok := h.bindTemplateParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.UseListTemplateInput
+	if !h.bindOptionalJSON(c, &input) {
+		return
+	}
+
+	listCopy, err := h.Usecases.List.CreateFromTemplate(c.Request.Context(), userId, templateId, input)
+	if err != nil {
+		if h.listTemplateErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":     userId,
+			"template_id": templateId,
+		}).Errorf("failed to create list from template: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create list", map[string]string{
+			"database": "Error during list creation",
+		})
+		return
+	}
+
+	h.incrementCreatedListCopy(&listCopy)
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "List created successfully", listCopy)
+}
+
+// bindListTemplateParams reads the user and the list of a clone or template request, responding with an error when invalid
+func (h *Handler) bindListTemplateParams(c *gin.Context) (int, int, bool) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return 0, 0, false
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return userId, listId, true
+}
+
+// bindTemplateParams reads the user and the template of a template request, responding with an error when invalid
+func (h *Handler) bindTemplateParams(c *gin.Context) (int, int, bool) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return 0, 0, false
+	}
+
+	templateId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid templateId param", map[string]string{
+			"param": "templateId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return userId, templateId, true
+}
+
+// bindOptionalJSON binds the JSON body of a request when there is one, responding with an error when it is malformed
+func (h *Handler) bindOptionalJSON(c *gin.Context, input interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+
+	if err := c.ShouldBindJSON(input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return false
+	}
+
+	return true
+}
+
+// incrementCreatedListCopy counts a list created from another list or a template together with its items
+func (h *Handler) incrementCreatedListCopy(listCopy *entity.ListCopy) {
+	h.Metrics.IncrementCreatedLists()
+	for range listCopy.Items {
+		h.Metrics.IncrementCreatedItems()
+	}
+}
+
+// listTemplateErrorResponse writes the response of the known clone and template errors and reports whether it did
+func (h *Handler) listTemplateErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidListTitle:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrUserNotOwner, utils.ErrListNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+			"listId": "The requested list does not exist",
+		})
+	case utils.ErrListTemplateNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Template not found", map[string]string{
+			"templateId": "The requested template does not exist",
+		})
+	default:
+		return false
+	}
+
+	return true
+}
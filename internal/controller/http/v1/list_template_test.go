@@ -0,0 +1,481 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupListTemplateRouter registers the clone and template handlers with an authenticated user
+func setupListTemplateRouter(mockList *MockList, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		List: mockList,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/lists/:id/clone", handler.CloneList)
+	r.POST("/api/lists/:id/templates", handler.CreateListTemplate)
+	r.GET("/api/templates", handler.GetListTemplates)
+	r.GET("/api/templates/:id", handler.GetListTemplate)
+	r.DELETE("/api/templates/:id", handler.DeleteListTemplate)
+	r.POST("/api/templates/:id/lists", handler.CreateListFromTemplate)
+
+	return r
+}
+
+// TestHandler_CloneList tests the CloneList handler
+func TestHandler_CloneList(t *testing.T) {
+	title := "Release 2.0"
+	parentId := 20
+
+	testCases := []struct {
+		name           string
+		url            string
+		input          string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			url:   "/api/lists/3/clone",
+			input: `{"title": "Release 2.0", "reset_done": true}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Clone", mock.Anything, 1, 3, entity.CloneListInput{Title: &title, ResetDone: true}).Return(entity.ListCopy{
+					List: entity.List{Id: 9, Title: "Release 2.0", Description: "Checklist"},
+					Items: []entity.Item{
+						{Id: 20, Title: "Build"},
+						{Id: 21, Title: "Tag", ParentId: &parentId},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "List cloned successfully",
+				"data": {
+					"list": {"id": 9, "title": "Release 2.0", "description": "Checklist"},
+					"items": [
+						{"id": 20, "title": "Build", "description": "", "done": false},
+						{"id": 21, "title": "Tag", "description": "", "done": false, "parent_id": 20}
+					]
+				}
+			}`,
+		},
+		{
+			name:  "Without a body",
+			url:   "/api/lists/3/clone",
+			input: "",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Clone", mock.Anything, 1, 3, entity.CloneListInput{}).Return(entity.ListCopy{
+					List:  entity.List{Id: 9, Title: "Release"},
+					Items: []entity.Item{},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "List cloned successfully",
+				"data": {"list": {"id": 9, "title": "Release", "description": ""}, "items": []}
+			}`,
+		},
+		{
+			name:           "Invalid list ID",
+			url:            "/api/lists/abc/clone",
+			input:          "",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "Blank title",
+			url:   "/api/lists/3/clone",
+			input: `{"title": " "}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Clone", mock.Anything, 1, 3, mock.Anything).Return(entity.ListCopy{}, utils.ErrInvalidListTitle)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "` + utils.ErrInvalidListTitle.Error() + `"}
+			}`,
+		},
+		{
+			name:  "List not shared with the user",
+			url:   "/api/lists/3/clone",
+			input: "",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Clone", mock.Anything, 1, 3, entity.CloneListInput{}).Return(entity.ListCopy{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+		{
+			name:  "Database error",
+			url:   "/api/lists/3/clone",
+			input: "",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Clone", mock.Anything, 1, 3, entity.CloneListInput{}).Return(entity.ListCopy{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to clone list",
+				"errors": {"database": "Error during list cloning"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupListTemplateRouter(mockList, 1)
+
+			req := httptest.NewRequest("POST", testCase.url, bytes.NewBufferString(testCase.input))
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_CreateListTemplate tests the CreateListTemplate handler
+func TestHandler_CreateListTemplate(t *testing.T) {
+	createdAt := time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC)
+	title := "Release checklist"
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"title": "Release checklist"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("CreateTemplate", mock.Anything, 1, 3, entity.CreateListTemplateInput{Title: &title}).Return(entity.ListTemplate{
+					Id:          2,
+					UserId:      1,
+					Title:       "Release checklist",
+					Description: "Steps",
+					ItemCount:   1,
+					Items:       []entity.ListTemplateItem{{Id: 30, Title: "Build"}},
+					CreatedAt:   createdAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Template created successfully",
+				"data": {
+					"id": 2,
+					"title": "Release checklist",
+					"description": "Steps",
+					"item_count": 1,
+					"items": [{"id": 30, "title": "Build", "description": ""}],
+					"created_at": "2024-11-28T09:00:00Z"
+				}
+			}`,
+		},
+		{
+			name:           "Malformed JSON",
+			input:          `{"title": `,
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:  "List in the trash",
+			input: "",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("CreateTemplate", mock.Anything, 1, 3, entity.CreateListTemplateInput{}).Return(entity.ListTemplate{}, utils.ErrListNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupListTemplateRouter(mockList, 1)
+
+			req := httptest.NewRequest("POST", "/api/lists/3/templates", bytes.NewBufferString(testCase.input))
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetListTemplates tests the GetListTemplates handler
+func TestHandler_GetListTemplates(t *testing.T) {
+	mockList := new(MockList)
+	r := setupListTemplateRouter(mockList, 1)
+
+	createdAt := time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC)
+	mockList.On("GetTemplates", mock.Anything, 1).Return([]entity.ListTemplate{
+		{Id: 2, UserId: 1, Title: "Release checklist", ItemCount: 5, CreatedAt: createdAt},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/templates", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ok",
+		"message": "Templates retrieved successfully",
+		"data": [{"id": 2, "title": "Release checklist", "description": "", "item_count": 5, "created_at": "2024-11-28T09:00:00Z"}]
+	}`, w.Body.String())
+
+	mockList.AssertExpectations(t)
+}
+
+// TestHandler_GetListTemplate tests the GetListTemplate handler
+func TestHandler_GetListTemplate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Invalid template ID",
+			url:            "/api/templates/abc",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid templateId param",
+				"errors": {"param": "templateId must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Template of another user",
+			url:  "/api/templates/2",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetTemplate", mock.Anything, 1, 2).Return(entity.ListTemplate{}, utils.ErrListTemplateNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Template not found",
+				"errors": {"templateId": "The requested template does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupListTemplateRouter(mockList, 1)
+
+			req := httptest.NewRequest("GET", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_DeleteListTemplate tests the DeleteListTemplate handler
+func TestHandler_DeleteListTemplate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		deleteErr      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Template deleted successfully"
+			}`,
+		},
+		{
+			name:           "Database error",
+			deleteErr:      errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to delete template",
+				"errors": {"database": "Error during template deletion"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupListTemplateRouter(mockList, 1)
+
+			mockList.On("DeleteTemplate", mock.Anything, 1, 2).Return(testCase.deleteErr)
+
+			req := httptest.NewRequest("DELETE", "/api/templates/2", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_CreateListFromTemplate tests the CreateListFromTemplate handler
+func TestHandler_CreateListFromTemplate(t *testing.T) {
+	title := "Onboarding Ann"
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"title": "Onboarding Ann"}`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("CreateFromTemplate", mock.Anything, 1, 2, entity.UseListTemplateInput{Title: &title}).Return(entity.ListCopy{
+					List:  entity.List{Id: 9, Title: "Onboarding Ann"},
+					Items: []entity.Item{{Id: 20, Title: "Laptop"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "List created successfully",
+				"data": {
+					"list": {"id": 9, "title": "Onboarding Ann", "description": ""},
+					"items": [{"id": 20, "title": "Laptop", "description": "", "done": false}]
+				}
+			}`,
+		},
+		{
+			name:  "Template of another user",
+			input: "",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("CreateFromTemplate", mock.Anything, 1, 2, entity.UseListTemplateInput{}).Return(entity.ListCopy{}, utils.ErrListTemplateNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Template not found",
+				"errors": {"templateId": "The requested template does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupListTemplateRouter(mockList, 1)
+
+			req := httptest.NewRequest("POST", "/api/templates/2/lists", bytes.NewBufferString(testCase.input))
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
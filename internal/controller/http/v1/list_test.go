@@ -151,6 +151,7 @@ func TestHandler_GetAllLists(t *testing.T) {
 	testCases := []struct {
 		name           string
 		userId         int
+		query          string
 		mockBehavior   func(mockList *MockList)
 		expectedStatus int
 		expectedBody   string
@@ -159,11 +160,11 @@ func TestHandler_GetAllLists(t *testing.T) {
 			name:   "Success",
 			userId: 1,
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("GetAll", mock.Anything, 1).
-					Return([]entity.List{
+				mockList.On("GetAll", mock.Anything, 1, entity.PageQuery{}).
+					Return(entity.ListPage{Lists: []entity.List{
 						{Id: 1, Title: "Groceries", Description: "Weekly groceries list"},
 						{Id: 2, Title: "Work", Description: "Work tasks for the week"},
-					}, nil)
+					}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -172,21 +173,67 @@ func TestHandler_GetAllLists(t *testing.T) {
 				"data": [
 					{"id": 1, "title": "Groceries", "description": "Weekly groceries list"},
 					{"id": 2, "title": "Work", "description": "Work tasks for the week"}
-				]
+				],
+				"next_cursor": null
+			}`,
+		},
+		{
+			name:   "Next page",
+			userId: 1,
+			query:  "?limit=1&cursor=abc&sort=title&order=desc",
+			mockBehavior: func(mockList *MockList) {
+				nextCursor := "def"
+				mockList.On("GetAll", mock.Anything, 1, entity.PageQuery{Limit: 1, Cursor: "abc", Sort: "title", Order: "desc"}).
+					Return(entity.ListPage{Lists: []entity.List{{Id: 2, Title: "Work"}}, NextCursor: &nextCursor}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Lists retrieved successfully",
+				"data": [{"id": 2, "title": "Work", "description": ""}],
+				"next_cursor": "def"
+			}`,
+		},
+		{
+			name:           "Invalid limit",
+			userId:         1,
+			query:          "?limit=ten",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid limit param",
+				"errors": {"param": "limit must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "Unsupported sort",
+			userId: 1,
+			query:  "?sort=due",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetAll", mock.Anything, 1, entity.PageQuery{Sort: "due"}).
+					Return(entity.ListPage{}, utils.ErrInvalidSort)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "sort is not supported by this collection"}
 			}`,
 		},
 		{
 			name:   "No lists found",
 			userId: 1,
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("GetAll", mock.Anything, 1).
-					Return([]entity.List{}, nil)
+				mockList.On("GetAll", mock.Anything, 1, entity.PageQuery{}).
+					Return(entity.ListPage{Lists: []entity.List{}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
 				"status": "ok",
 				"message": "Lists retrieved successfully",
-				"data": []
+				"data": [],
+				"next_cursor": null
 			}`,
 		},
 		{
@@ -204,8 +251,8 @@ func TestHandler_GetAllLists(t *testing.T) {
 			name:   "Internal server error",
 			userId: 1,
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("GetAll", mock.Anything, 1).
-					Return([]entity.List{}, errors.New("db error"))
+				mockList.On("GetAll", mock.Anything, 1, entity.PageQuery{}).
+					Return(entity.ListPage{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -245,7 +292,7 @@ func TestHandler_GetAllLists(t *testing.T) {
 
 			r.GET("/api/lists/", handler.GetAllLists)
 
-			req := httptest.NewRequest("GET", "/api/lists/", nil)
+			req := httptest.NewRequest("GET", "/api/lists/"+testCase.query, nil)
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockList)
@@ -266,8 +313,10 @@ func TestHandler_GetListById(t *testing.T) {
 		name           string
 		userId         int
 		listId         string
+		ifNoneMatch    string
 		mockBehavior   func(mockList *MockList)
 		expectedStatus int
+		expectedETag   string
 		expectedBody   string
 	}{
 		{
@@ -279,19 +328,33 @@ func TestHandler_GetListById(t *testing.T) {
 					Id:          2,
 					Title:       "Groceries",
 					Description: "Weekly groceries list",
+					Version:     5,
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
 			expectedBody: `{
 				"status": "ok",
 				"message": "List retrieved successfully",
 				"data": {
 					"id": 2,
 					"title": "Groceries",
-					"description": "Weekly groceries list"
+					"description": "Weekly groceries list",
+					"version": 5
 				}
 			}`,
 		},
+		{
+			name:        "Not modified",
+			userId:      1,
+			listId:      "2",
+			ifNoneMatch: `"5"`,
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("GetOneById", mock.Anything, 1, 2).Return(entity.List{Id: 2, Title: "Groceries", Version: 5}, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"5"`,
+		},
 		{
 			name:           "Invalid listId parameter",
 			userId:         1,
@@ -390,6 +453,9 @@ func TestHandler_GetListById(t *testing.T) {
 			r.GET("/api/lists/:id", handler.GetListById)
 
 			req := httptest.NewRequest("GET", "/api/lists/"+testCase.listId, nil)
+			if testCase.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", testCase.ifNoneMatch)
+			}
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockList)
@@ -397,7 +463,12 @@ func TestHandler_GetListById(t *testing.T) {
 			r.ServeHTTP(w, req)
 
 			assert.Equal(t, testCase.expectedStatus, w.Code)
-			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+			assert.Equal(t, testCase.expectedETag, w.Header().Get("ETag"))
+			if testCase.expectedBody == "" {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+			}
 
 			mockList.AssertExpectations(t)
 		})
@@ -414,6 +485,7 @@ func TestHandler_UpdateList(t *testing.T) {
 		userId         int
 		listId         string
 		input          string
+		ifMatch        string
 		mockBehavior   func(mockList *MockList)
 		expectedStatus int
 		expectedBody   string
@@ -430,7 +502,7 @@ func TestHandler_UpdateList(t *testing.T) {
 				mockList.On("UpdateOneById", mock.Anything, 1, 2, entity.UpdateListInput{
 					Title:       &title,
 					Description: &description,
-				}).Return(nil)
+				}, (*int)(nil)).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -438,6 +510,27 @@ func TestHandler_UpdateList(t *testing.T) {
 				"message": "List updated successfully"
 			}`,
 		},
+		{
+			name:   "If-Match version mismatch",
+			userId: 1,
+			listId: "2",
+			input: `{
+				"title": "Updated title"
+			}`,
+			ifMatch: `"5"`,
+			mockBehavior: func(mockList *MockList) {
+				version := 5
+				mockList.On("UpdateOneById", mock.Anything, 1, 2, entity.UpdateListInput{
+					Title: &title,
+				}, &version).Return(utils.ErrVersionMismatch)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody: `{
+				"status": "error",
+				"message": "Precondition failed",
+				"errors": {"version": "resource was modified since the version given in If-Match"}
+			}`,
+		},
 		{
 			name:   "Invalid listId parameter",
 			userId: 1,
@@ -489,7 +582,7 @@ func TestHandler_UpdateList(t *testing.T) {
 			mockBehavior: func(mockList *MockList) {
 				mockList.On("UpdateOneById", mock.Anything, 1, 2, entity.UpdateListInput{
 					Title: &title,
-				}).Return(utils.ErrUserNotOwner)
+				}, (*int)(nil)).Return(utils.ErrUserNotOwner)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -508,7 +601,7 @@ func TestHandler_UpdateList(t *testing.T) {
 			mockBehavior: func(mockList *MockList) {
 				mockList.On("UpdateOneById", mock.Anything, 1, 2, entity.UpdateListInput{
 					Title: &title,
-				}).Return(utils.ErrListNotFound)
+				}, (*int)(nil)).Return(utils.ErrListNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -527,7 +620,7 @@ func TestHandler_UpdateList(t *testing.T) {
 			mockBehavior: func(mockList *MockList) {
 				mockList.On("UpdateOneById", mock.Anything, 1, 2, entity.UpdateListInput{
 					Title: &title,
-				}).Return(errors.New("db error"))
+				}, (*int)(nil)).Return(errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -584,6 +677,9 @@ func TestHandler_UpdateList(t *testing.T) {
 
 			req := httptest.NewRequest("PUT", "/api/lists/"+testCase.listId, bytes.NewBufferString(testCase.input))
 			req.Header.Set("Content-Type", "application/json")
+			if testCase.ifMatch != "" {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
 			w := httptest.NewRecorder()
 
 			testCase.mockBehavior(mockList)
@@ -613,7 +709,7 @@ func TestHandler_DeleteList(t *testing.T) {
 			userId: 1,
 			listId: "2",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("DeleteOneById", mock.Anything, 1, 2).Return(nil)
+				mockList.On("DeleteOneById", mock.Anything, 1, 2, (*int)(nil)).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -638,7 +734,7 @@ func TestHandler_DeleteList(t *testing.T) {
 			userId: 1,
 			listId: "2",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("DeleteOneById", mock.Anything, 1, 2).Return(utils.ErrUserNotOwner)
+				mockList.On("DeleteOneById", mock.Anything, 1, 2, (*int)(nil)).Return(utils.ErrUserNotOwner)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -652,7 +748,7 @@ func TestHandler_DeleteList(t *testing.T) {
 			userId: 1,
 			listId: "2",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("DeleteOneById", mock.Anything, 1, 2).Return(utils.ErrListNotFound)
+				mockList.On("DeleteOneById", mock.Anything, 1, 2, (*int)(nil)).Return(utils.ErrListNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody: `{
@@ -666,7 +762,7 @@ func TestHandler_DeleteList(t *testing.T) {
 			userId: 1,
 			listId: "2",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("DeleteOneById", mock.Anything, 1, 2).Return(errors.New("db error"))
+				mockList.On("DeleteOneById", mock.Anything, 1, 2, (*int)(nil)).Return(errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -846,11 +942,12 @@ func TestHandler_SearchLists(t *testing.T) {
 			userId:     1,
 			searchText: "coffee",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("Search", mock.Anything, 1, "coffee").
-					Return([]entity.List{
+				nextCursor := "eyJzIjoicmVsZXZhbmNlOmRlc2MifQ"
+				mockList.On("Search", mock.Anything, 1, "coffee", entity.PageQuery{}).
+					Return(entity.ListPage{Lists: []entity.List{
 						{Id: 1, Title: "Coffee Enthusiast Checklist", Description: "A list of tasks and items for coffee lovers"},
 						{Id: 2, Title: "Buy Coffee Beans", Description: "Purchase a fresh batch of Arabica beans from the local roaster"},
-					}, nil)
+					}, NextCursor: &nextCursor}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: `{
@@ -859,7 +956,8 @@ func TestHandler_SearchLists(t *testing.T) {
 				"data": [
 					{"id": 1, "title": "Coffee Enthusiast Checklist", "description": "A list of tasks and items for coffee lovers"},
 					{"id": 2, "title": "Buy Coffee Beans", "description": "Purchase a fresh batch of Arabica beans from the local roaster"}
-				]
+				],
+				"next_cursor": "eyJzIjoicmVsZXZhbmNlOmRlc2MifQ"
 			}`,
 		},
 		{
@@ -891,8 +989,8 @@ func TestHandler_SearchLists(t *testing.T) {
 			userId:     1,
 			searchText: "coffee",
 			mockBehavior: func(mockList *MockList) {
-				mockList.On("Search", mock.Anything, 1, "coffee").
-					Return([]entity.List{}, errors.New("db error"))
+				mockList.On("Search", mock.Anything, 1, "coffee", entity.PageQuery{}).
+					Return(entity.ListPage{}, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody: `{
@@ -2,8 +2,11 @@ package v1_test
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -19,22 +22,75 @@ func (m *MockAuth) CreateUser(ctx context.Context, user entity.User) (int, error
 	return args.Int(0), args.Error(1)
 }
 
+// EnsureAdmin mocks seeding the admin user
+func (m *MockAuth) EnsureAdmin(ctx context.Context, name, username, password string) error {
+	args := m.Called(ctx, name, username, password)
+	return args.Error(0)
+}
+
 // AuthenticateUser mocks the authentication of a user
 func (m *MockAuth) AuthenticateUser(ctx context.Context, username, password string) (entity.User, error) {
 	args := m.Called(ctx, username, password)
 	return args.Get(0).(entity.User), args.Error(1)
 }
 
-// GenerateToken mocks the generation of a token for a user
-func (m *MockAuth) GenerateToken(ctx context.Context, userId int) (string, error) {
-	args := m.Called(ctx, userId)
-	return args.String(0), args.Error(1)
+// GenerateTokens mocks the generation of a token pair for a user
+func (m *MockAuth) GenerateTokens(ctx context.Context, userId int, role string) (entity.TokenPair, error) {
+	args := m.Called(ctx, userId, role)
+	return args.Get(0).(entity.TokenPair), args.Error(1)
+}
+
+// RefreshTokens mocks exchanging a refresh token for a new token pair
+func (m *MockAuth) RefreshTokens(ctx context.Context, refreshToken string) (entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(entity.TokenPair), args.Error(1)
+}
+
+// SignOut mocks revoking the tokens of a session
+func (m *MockAuth) SignOut(ctx context.Context, accessToken, refreshToken string) error {
+	args := m.Called(ctx, accessToken, refreshToken)
+	return args.Error(0)
 }
 
-// ParseToken mocks the parsing of a token to extract user ID
-func (m *MockAuth) ParseToken(ctx context.Context, token string) (int, error) {
+// ParsePersonalAccessToken mocks the parsing of a personal access token
+func (m *MockAuth) ParsePersonalAccessToken(ctx context.Context, token string) (int, []string, error) {
 	args := m.Called(ctx, token)
-	return args.Int(0), args.Error(1)
+	return args.Int(0), args.Get(1).([]string), args.Error(2)
+}
+
+// GetJWKS mocks returning the public signing keys
+func (m *MockAuth) GetJWKS() token.JWKS {
+	args := m.Called()
+	return args.Get(0).(token.JWKS)
+}
+
+// ParseToken mocks the parsing of a token to extract user ID and role
+func (m *MockAuth) ParseToken(ctx context.Context, token string) (int, string, error) {
+	args := m.Called(ctx, token)
+	return args.Int(0), args.String(1), args.Error(2)
+}
+
+// MockPersonalAccessToken is a mock implementation of the PersonalAccessToken interface
+type MockPersonalAccessToken struct {
+	mock.Mock
+}
+
+// Create mocks minting a personal access token
+func (m *MockPersonalAccessToken) Create(ctx context.Context, userId int, input entity.CreatePersonalAccessTokenInput) (entity.CreatedPersonalAccessToken, error) {
+	args := m.Called(ctx, userId, input)
+	return args.Get(0).(entity.CreatedPersonalAccessToken), args.Error(1)
+}
+
+// GetAll mocks listing the personal access tokens of a user
+func (m *MockPersonalAccessToken) GetAll(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.PersonalAccessToken), args.Error(1)
+}
+
+// Revoke mocks revoking a personal access token
+func (m *MockPersonalAccessToken) Revoke(ctx context.Context, userId, tokenId int) error {
+	args := m.Called(ctx, userId, tokenId)
+	return args.Error(0)
 }
 
 // MockAppAuth is a mock implementation of the AppAuth interface
@@ -48,6 +104,53 @@ func (m *MockAppAuth) Validate(appId, appKey, accessType string) bool {
 	return args.Bool(0)
 }
 
+// MockApp is a mock implementation of the App interface
+type MockApp struct {
+	MockAppAuth
+}
+
+// Create mocks registering an app
+func (m *MockApp) Create(ctx context.Context, input entity.CreateAppInput) (entity.CreatedApp, error) {
+	args := m.Called(ctx, input)
+	return args.Get(0).(entity.CreatedApp), args.Error(1)
+}
+
+// EnsureApp mocks seeding an app with known credentials
+func (m *MockApp) EnsureApp(ctx context.Context, appId, appKey, name string, accessTypes []string) error {
+	args := m.Called(ctx, appId, appKey, name, accessTypes)
+	return args.Error(0)
+}
+
+// GetAll mocks listing every app
+func (m *MockApp) GetAll(ctx context.Context) ([]entity.App, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.App), args.Error(1)
+}
+
+// GetOneByAppId mocks retrieving an app by its app id
+func (m *MockApp) GetOneByAppId(ctx context.Context, appId string) (entity.App, error) {
+	args := m.Called(ctx, appId)
+	return args.Get(0).(entity.App), args.Error(1)
+}
+
+// UpdateOneByAppId mocks updating an app
+func (m *MockApp) UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error {
+	args := m.Called(ctx, appId, input)
+	return args.Error(0)
+}
+
+// RotateKey mocks rotating the key of an app
+func (m *MockApp) RotateKey(ctx context.Context, appId string, input entity.RotateAppKeyInput) (entity.CreatedApp, error) {
+	args := m.Called(ctx, appId, input)
+	return args.Get(0).(entity.CreatedApp), args.Error(1)
+}
+
+// DeleteOneByAppId mocks deleting an app
+func (m *MockApp) DeleteOneByAppId(ctx context.Context, appId string) error {
+	args := m.Called(ctx, appId)
+	return args.Error(0)
+}
+
 // MockList is a mock implementation of the List interface
 type MockList struct {
 	mock.Mock
@@ -59,10 +162,10 @@ func (m *MockList) Create(ctx context.Context, userId int, list *entity.List) (i
 	return args.Int(0), args.Error(1)
 }
 
-// GetAll mocks retrieving all lists for a user
-func (m *MockList) GetAll(ctx context.Context, userId int) ([]entity.List, error) {
-	args := m.Called(ctx, userId)
-	return args.Get(0).([]entity.List), args.Error(1)
+// GetAll mocks retrieving a page of the lists of a user
+func (m *MockList) GetAll(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error) {
+	args := m.Called(ctx, userId, query)
+	return args.Get(0).(entity.ListPage), args.Error(1)
 }
 
 // GetOneById mocks retrieving a specific list by ID
@@ -72,14 +175,14 @@ func (m *MockList) GetOneById(ctx context.Context, userId, listId int) (entity.L
 }
 
 // UpdateOneById mocks updating a specific list by ID
-func (m *MockList) UpdateOneById(ctx context.Context, userId, listId int, input entity.UpdateListInput) error {
-	args := m.Called(ctx, userId, listId, input)
+func (m *MockList) UpdateOneById(ctx context.Context, userId, listId int, input entity.UpdateListInput, version *int) error {
+	args := m.Called(ctx, userId, listId, input, version)
 	return args.Error(0)
 }
 
 // DeleteOneById mocks deleting a specific list by ID
-func (m *MockList) DeleteOneById(ctx context.Context, userId, listId int) error {
-	args := m.Called(ctx, userId, listId)
+func (m *MockList) DeleteOneById(ctx context.Context, userId, listId int, version *int) error {
+	args := m.Called(ctx, userId, listId, version)
 	return args.Error(0)
 }
 
@@ -89,10 +192,76 @@ func (m *MockList) DeleteOneByAdmin(ctx context.Context, listId int) error {
 	return args.Error(0)
 }
 
-// Search mocks searching lists by a given text
-func (m *MockList) Search(ctx context.Context, userId int, searchText string) ([]entity.List, error) {
-	args := m.Called(ctx, userId, searchText)
-	return args.Get(0).([]entity.List), args.Error(1)
+// GetCollaborators mocks retrieving the users a list is shared with
+func (m *MockList) GetCollaborators(ctx context.Context, userId, listId int) ([]entity.ListCollaborator, error) {
+	args := m.Called(ctx, userId, listId)
+	return args.Get(0).([]entity.ListCollaborator), args.Error(1)
+}
+
+// AddCollaborator mocks sharing a list with a user
+func (m *MockList) AddCollaborator(ctx context.Context, userId, listId int, input entity.AddCollaboratorInput) (entity.ListCollaborator, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.ListCollaborator), args.Error(1)
+}
+
+// UpdateCollaborator mocks changing the role of a collaborator
+func (m *MockList) UpdateCollaborator(ctx context.Context, userId, listId, collaboratorId int, input entity.UpdateCollaboratorInput) error {
+	args := m.Called(ctx, userId, listId, collaboratorId, input)
+	return args.Error(0)
+}
+
+// RemoveCollaborator mocks removing a collaborator from a list
+func (m *MockList) RemoveCollaborator(ctx context.Context, userId, listId, collaboratorId int) error {
+	args := m.Called(ctx, userId, listId, collaboratorId)
+	return args.Error(0)
+}
+
+// Move mocks moving a list among the lists of the user
+func (m *MockList) Move(ctx context.Context, userId, listId int, input entity.MoveInput) error {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Error(0)
+}
+
+// Clone mocks copying a list with its items into a new list
+func (m *MockList) Clone(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.ListCopy), args.Error(1)
+}
+
+// CreateTemplate mocks saving a list as a template
+func (m *MockList) CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.ListTemplate), args.Error(1)
+}
+
+// GetTemplates mocks retrieving the templates of the user
+func (m *MockList) GetTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.ListTemplate), args.Error(1)
+}
+
+// GetTemplate mocks retrieving a template of the user
+func (m *MockList) GetTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error) {
+	args := m.Called(ctx, userId, templateId)
+	return args.Get(0).(entity.ListTemplate), args.Error(1)
+}
+
+// DeleteTemplate mocks deleting a template of the user
+func (m *MockList) DeleteTemplate(ctx context.Context, userId, templateId int) error {
+	args := m.Called(ctx, userId, templateId)
+	return args.Error(0)
+}
+
+// CreateFromTemplate mocks creating a list from a template
+func (m *MockList) CreateFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error) {
+	args := m.Called(ctx, userId, templateId, input)
+	return args.Get(0).(entity.ListCopy), args.Error(1)
+}
+
+// Search mocks searching a page of lists by a given text
+func (m *MockList) Search(ctx context.Context, userId int, searchText string, query entity.PageQuery) (entity.ListPage, error) {
+	args := m.Called(ctx, userId, searchText, query)
+	return args.Get(0).(entity.ListPage), args.Error(1)
 }
 
 // HandleCreated mocks handling a "list created" event
@@ -124,10 +293,10 @@ func (m *MockItem) Create(ctx context.Context, userId, listId int, item *entity.
 	return args.Int(0), args.Error(1)
 }
 
-// GetAll mocks retrieving all items for a list
-func (m *MockItem) GetAll(ctx context.Context, userId, listId int) ([]entity.Item, error) {
-	args := m.Called(ctx, userId, listId)
-	return args.Get(0).([]entity.Item), args.Error(1)
+// GetAll mocks retrieving a page of the items of a list
+func (m *MockItem) GetAll(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error) {
+	args := m.Called(ctx, userId, listId, query)
+	return args.Get(0).(entity.ItemPage), args.Error(1)
 }
 
 // GetOneById mocks retrieving a specific item by ID
@@ -137,14 +306,14 @@ func (m *MockItem) GetOneById(ctx context.Context, userId, itemId int) (entity.I
 }
 
 // UpdateOneById mocks updating a specific item by ID
-func (m *MockItem) UpdateOneById(ctx context.Context, userId, listId, itemId int, input entity.UpdateItemInput) error {
-	args := m.Called(ctx, userId, listId, itemId, input)
+func (m *MockItem) UpdateOneById(ctx context.Context, userId, listId, itemId int, input entity.UpdateItemInput, version *int) error {
+	args := m.Called(ctx, userId, listId, itemId, input, version)
 	return args.Error(0)
 }
 
 // DeleteOneById mocks deleting a specific item by ID
-func (m *MockItem) DeleteOneById(ctx context.Context, userId, listId, itemId int) error {
-	args := m.Called(ctx, userId, listId, itemId)
+func (m *MockItem) DeleteOneById(ctx context.Context, userId, listId, itemId int, version *int) error {
+	args := m.Called(ctx, userId, listId, itemId, version)
 	return args.Error(0)
 }
 
@@ -154,10 +323,52 @@ func (m *MockItem) DeleteOneByAdmin(ctx context.Context, itemId int) error {
 	return args.Error(0)
 }
 
-// Search mocks searching items in a list by given parameters
-func (m *MockItem) Search(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]entity.Item, error) {
-	args := m.Called(ctx, userId, listId, done, searchText)
-	return args.Get(0).([]entity.Item), args.Error(1)
+// CreateSubtask mocks the creation of a subtask of an item
+func (m *MockItem) CreateSubtask(ctx context.Context, userId, listId, parentId int, item *entity.Item) (int, error) {
+	args := m.Called(ctx, userId, listId, parentId, item)
+	return args.Int(0), args.Error(1)
+}
+
+// GetSubtasks mocks retrieving the subtasks of an item
+func (m *MockItem) GetSubtasks(ctx context.Context, userId, itemId int) (entity.Subtasks, error) {
+	args := m.Called(ctx, userId, itemId)
+	return args.Get(0).(entity.Subtasks), args.Error(1)
+}
+
+// SetParent mocks moving an item under another item
+func (m *MockItem) SetParent(ctx context.Context, userId, listId, itemId int, input entity.SetItemParentInput) error {
+	args := m.Called(ctx, userId, listId, itemId, input)
+	return args.Error(0)
+}
+
+// Move mocks moving an item within its list
+func (m *MockItem) Move(ctx context.Context, userId, listId, itemId int, input entity.MoveInput) error {
+	args := m.Called(ctx, userId, listId, itemId, input)
+	return args.Error(0)
+}
+
+// MoveToList mocks moving items to another list
+func (m *MockItem) MoveToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) (entity.MovedItems, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.MovedItems), args.Error(1)
+}
+
+// CopyToList mocks copying items to another list
+func (m *MockItem) CopyToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) ([]entity.ItemCopy, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).([]entity.ItemCopy), args.Error(1)
+}
+
+// Bulk mocks applying an operation to many items
+func (m *MockItem) Bulk(ctx context.Context, userId int, input entity.BulkItemsInput) (entity.BulkItemsResult, error) {
+	args := m.Called(ctx, userId, input)
+	return args.Get(0).(entity.BulkItemsResult), args.Error(1)
+}
+
+// Search mocks searching a page of items by given parameters
+func (m *MockItem) Search(ctx context.Context, userId int, listId *int, done *bool, tags []string, searchText string, query entity.PageQuery) (entity.ItemPage, error) {
+	args := m.Called(ctx, userId, listId, done, tags, searchText, query)
+	return args.Get(0).(entity.ItemPage), args.Error(1)
 }
 
 // HandleCreated mocks handling an "item created" event
@@ -178,6 +389,59 @@ func (m *MockItem) HandleDeleted(ctx context.Context, message entity.ItemDeleted
 	return args.Error(0)
 }
 
+// DispatchDueReminders mocks dispatching the due reminders
+func (m *MockItem) DispatchDueReminders(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// MockTag is a mock implementation of the Tag interface
+type MockTag struct {
+	mock.Mock
+}
+
+// Create mocks creating a tag
+func (m *MockTag) Create(ctx context.Context, userId int, input entity.CreateTagInput) (entity.Tag, error) {
+	args := m.Called(ctx, userId, input)
+	return args.Get(0).(entity.Tag), args.Error(1)
+}
+
+// GetAll mocks retrieving the tags of a user
+func (m *MockTag) GetAll(ctx context.Context, userId int) ([]entity.Tag, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+// UpdateOneById mocks updating a tag
+func (m *MockTag) UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error {
+	args := m.Called(ctx, userId, tagId, input)
+	return args.Error(0)
+}
+
+// DeleteOneById mocks deleting a tag
+func (m *MockTag) DeleteOneById(ctx context.Context, userId, tagId int) error {
+	args := m.Called(ctx, userId, tagId)
+	return args.Error(0)
+}
+
+// GetAllForItem mocks retrieving the tags of a user attached to an item
+func (m *MockTag) GetAllForItem(ctx context.Context, userId, itemId int) ([]entity.Tag, error) {
+	args := m.Called(ctx, userId, itemId)
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+// AttachToItem mocks attaching a tag to an item
+func (m *MockTag) AttachToItem(ctx context.Context, userId, listId, itemId, tagId int) error {
+	args := m.Called(ctx, userId, listId, itemId, tagId)
+	return args.Error(0)
+}
+
+// DetachFromItem mocks detaching a tag from an item
+func (m *MockTag) DetachFromItem(ctx context.Context, userId, listId, itemId, tagId int) error {
+	args := m.Called(ctx, userId, listId, itemId, tagId)
+	return args.Error(0)
+}
+
 // MockUser is a mock implementation of the User interface
 type MockUser struct {
 	mock.Mock
@@ -188,3 +452,192 @@ func (m *MockUser) DeleteOneByAdmin(ctx context.Context, userId int) error {
 	args := m.Called(ctx, userId)
 	return args.Error(0)
 }
+
+// UpdateRole mocks assigning a role to a user
+func (m *MockUser) UpdateRole(ctx context.Context, userId int, input entity.UpdateUserRoleInput) error {
+	args := m.Called(ctx, userId, input)
+	return args.Error(0)
+}
+
+// MockInvitation is a mock implementation of the Invitation interface
+type MockInvitation struct {
+	mock.Mock
+}
+
+// Create mocks inviting a user to a list
+func (m *MockInvitation) Create(ctx context.Context, userId, listId int, input entity.CreateInvitationInput) (entity.CreatedInvitation, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.CreatedInvitation), args.Error(1)
+}
+
+// GetAllForList mocks retrieving the pending invitations of a list
+func (m *MockInvitation) GetAllForList(ctx context.Context, userId, listId int) ([]entity.Invitation, error) {
+	args := m.Called(ctx, userId, listId)
+	return args.Get(0).([]entity.Invitation), args.Error(1)
+}
+
+// GetAllForUser mocks retrieving the pending invitations addressed to a user
+func (m *MockInvitation) GetAllForUser(ctx context.Context, userId int) ([]entity.Invitation, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.Invitation), args.Error(1)
+}
+
+// Accept mocks accepting an invitation
+func (m *MockInvitation) Accept(ctx context.Context, userId, invitationId int) error {
+	args := m.Called(ctx, userId, invitationId)
+	return args.Error(0)
+}
+
+// AcceptLink mocks redeeming a link invitation
+func (m *MockInvitation) AcceptLink(ctx context.Context, userId int, input entity.AcceptInvitationLinkInput) (entity.Invitation, error) {
+	args := m.Called(ctx, userId, input)
+	return args.Get(0).(entity.Invitation), args.Error(1)
+}
+
+// Decline mocks declining an invitation
+func (m *MockInvitation) Decline(ctx context.Context, userId, invitationId int) error {
+	args := m.Called(ctx, userId, invitationId)
+	return args.Error(0)
+}
+
+// Revoke mocks revoking an invitation
+func (m *MockInvitation) Revoke(ctx context.Context, userId, invitationId int) error {
+	args := m.Called(ctx, userId, invitationId)
+	return args.Error(0)
+}
+
+// MockTrash is a mock implementation of the Trash interface
+type MockTrash struct {
+	mock.Mock
+}
+
+// GetLists mocks retrieving the trashed lists of a user
+func (m *MockTrash) GetLists(ctx context.Context, userId int) ([]entity.TrashedList, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.TrashedList), args.Error(1)
+}
+
+// GetItems mocks retrieving the trashed items of a list
+func (m *MockTrash) GetItems(ctx context.Context, userId, listId int) ([]entity.TrashedItem, error) {
+	args := m.Called(ctx, userId, listId)
+	return args.Get(0).([]entity.TrashedItem), args.Error(1)
+}
+
+// RestoreList mocks restoring a trashed list
+func (m *MockTrash) RestoreList(ctx context.Context, userId, listId int) error {
+	args := m.Called(ctx, userId, listId)
+	return args.Error(0)
+}
+
+// RestoreItem mocks restoring a trashed item
+func (m *MockTrash) RestoreItem(ctx context.Context, userId, listId, itemId int) error {
+	args := m.Called(ctx, userId, listId, itemId)
+	return args.Error(0)
+}
+
+// Purge mocks purging the trash
+func (m *MockTrash) Purge(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(entity.PurgedTrash), args.Error(1)
+}
+
+// MockRevision is a mock implementation of the Revision interface
+type MockRevision struct {
+	mock.Mock
+}
+
+// GetItemHistory mocks retrieving the revisions of an item
+func (m *MockRevision) GetItemHistory(ctx context.Context, userId, itemId int) ([]entity.ItemRevision, error) {
+	args := m.Called(ctx, userId, itemId)
+	return args.Get(0).([]entity.ItemRevision), args.Error(1)
+}
+
+// GetListHistory mocks retrieving the revisions of a list
+func (m *MockRevision) GetListHistory(ctx context.Context, userId, listId int) ([]entity.ListRevision, error) {
+	args := m.Called(ctx, userId, listId)
+	return args.Get(0).([]entity.ListRevision), args.Error(1)
+}
+
+// RevertItem mocks reverting an item to a revision
+func (m *MockRevision) RevertItem(ctx context.Context, userId, listId, itemId, revisionId int) error {
+	args := m.Called(ctx, userId, listId, itemId, revisionId)
+	return args.Error(0)
+}
+
+// RevertList mocks reverting a list to a revision
+func (m *MockRevision) RevertList(ctx context.Context, userId, listId, revisionId int) error {
+	args := m.Called(ctx, userId, listId, revisionId)
+	return args.Error(0)
+}
+
+// MockComment is a mock implementation of the Comment interface
+type MockComment struct {
+	mock.Mock
+}
+
+// Create mocks commenting on an item
+func (m *MockComment) Create(ctx context.Context, userId, itemId int, input entity.CreateCommentInput) (entity.Comment, error) {
+	args := m.Called(ctx, userId, itemId, input)
+	return args.Get(0).(entity.Comment), args.Error(1)
+}
+
+// GetAll mocks retrieving a page of the comments of an item
+func (m *MockComment) GetAll(ctx context.Context, userId, itemId int, query entity.CommentQuery) (entity.CommentPage, error) {
+	args := m.Called(ctx, userId, itemId, query)
+	return args.Get(0).(entity.CommentPage), args.Error(1)
+}
+
+// UpdateOneById mocks editing a comment
+func (m *MockComment) UpdateOneById(ctx context.Context, userId, itemId, commentId int, input entity.UpdateCommentInput) error {
+	args := m.Called(ctx, userId, itemId, commentId, input)
+	return args.Error(0)
+}
+
+// DeleteOneById mocks deleting a comment
+func (m *MockComment) DeleteOneById(ctx context.Context, userId, itemId, commentId int) error {
+	args := m.Called(ctx, userId, itemId, commentId)
+	return args.Error(0)
+}
+
+// MockAttachment is a mock implementation of the Attachment interface
+type MockAttachment struct {
+	mock.Mock
+}
+
+// Upload mocks attaching a file to an item
+func (m *MockAttachment) Upload(ctx context.Context, userId, itemId int, input entity.UploadAttachmentInput) (entity.Attachment, error) {
+	args := m.Called(ctx, userId, itemId, input)
+	return args.Get(0).(entity.Attachment), args.Error(1)
+}
+
+// GetAll mocks retrieving the attachments of an item
+func (m *MockAttachment) GetAll(ctx context.Context, userId, itemId int) ([]entity.Attachment, error) {
+	args := m.Called(ctx, userId, itemId)
+	return args.Get(0).([]entity.Attachment), args.Error(1)
+}
+
+// Download mocks retrieving an attachment with its content
+func (m *MockAttachment) Download(ctx context.Context, userId, itemId, attachmentId int) (entity.Attachment, io.ReadCloser, error) {
+	args := m.Called(ctx, userId, itemId, attachmentId)
+	if args.Get(1) == nil {
+		return args.Get(0).(entity.Attachment), nil, args.Error(2)
+	}
+	return args.Get(0).(entity.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+// DeleteOneById mocks deleting an attachment
+func (m *MockAttachment) DeleteOneById(ctx context.Context, userId, itemId, attachmentId int) error {
+	args := m.Called(ctx, userId, itemId, attachmentId)
+	return args.Error(0)
+}
+
+// PurgeDetached mocks deleting the blobs of detached attachments
+func (m *MockAttachment) PurgeDetached(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// MaxSize mocks the upload size limit with 1 KiB
+func (m *MockAttachment) MaxSize() int64 {
+	return 1024
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageQuery parses the optional limit, cursor, sort and order query params of a page,
+// a 400 response is written when one is invalid
+func parsePageQuery(c *gin.Context) (entity.PageQuery, bool) {
+	var limit *int
+	if err := utils.ParseOptionalParamAsInt(c, "limit", &limit); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid limit param", map[string]string{
+			"param": "limit must be a valid integer",
+		})
+		return entity.PageQuery{}, false
+	}
+
+	query := entity.PageQuery{
+		Cursor: c.Query("cursor"),
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+
+	return query, true
+}
+
+// pageErrorResponse writes the response of the errors of an invalid page query and reports whether it did
+func pageErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidPageLimit, utils.ErrInvalidPageCursor, utils.ErrInvalidSort, utils.ErrInvalidSortOrder:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	default:
+		return false
+	}
+
+	return true
+}
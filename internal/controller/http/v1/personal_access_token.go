@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createPersonalAccessToken godoc
+// @Summary Create a personal access token
+// @Description Mint a named personal access token with scopes, the token is shown only once
+// @Tags tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.CreatePersonalAccessTokenInput true "Token name, scopes and optional expiration"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.CreatedPersonalAccessToken} "Token created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create token"
+// @Router /api/tokens/ [post]
+func (h *Handler) CreatePersonalAccessToken(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	var input entity.CreatePersonalAccessTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	accessToken, err := h.Usecases.PersonalAccessToken.Create(c.Request.Context(), userId, input)
+	if err != nil {
+		if err == utils.ErrInvalidScope || err == utils.ErrInvalidExpiration {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to create personal access token: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create token", map[string]string{
+			"database": "Error during token creation",
+		})
+		return
+	}
+
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":  userId,
+		"token_id": accessToken.Id,
+	}).Info("personal access token created")
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Token created successfully", accessToken)
+}
+
+// getAllPersonalAccessTokens godoc
+// @Summary Get all personal access tokens
+// @Description Retrieve the active personal access tokens of the authenticated user, token values are never returned
+// @Tags tokens
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.PersonalAccessToken} "Tokens retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve tokens"
+// @Router /api/tokens/ [get]
+func (h *Handler) GetAllPersonalAccessTokens(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	accessTokens, err := h.Usecases.PersonalAccessToken.GetAll(c.Request.Context(), userId)
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to retrieve personal access tokens: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tokens", map[string]string{
+			"database": "Error during token retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tokens retrieved successfully", accessTokens)
+}
+
+// revokePersonalAccessToken godoc
+// @Summary Revoke a personal access token
+// @Description Revoke a personal access token of the authenticated user, it stops working immediately
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path int true "Token ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Token revoked successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid tokenId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "Token not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to revoke token"
+// @Router /api/tokens/{id} [delete]
+func (h *Handler) RevokePersonalAccessToken(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	tokenId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("invalid tokenId parameter: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid tokenId param", map[string]string{
+			"param": "tokenId must be a valid integer",
+		})
+		return
+	}
+
+	err = h.Usecases.PersonalAccessToken.Revoke(c.Request.Context(), userId, tokenId)
+	if err != nil {
+		if err == utils.ErrPersonalAccessTokenNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Token not found", map[string]string{
+				"tokenId": "The requested token does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":  userId,
+			"token_id": tokenId,
+		}).Errorf("failed to revoke personal access token: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to revoke token", map[string]string{
+			"database": "Error during token revocation",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Token revoked successfully", nil)
+}
@@ -0,0 +1,220 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupPersonalAccessTokenRouter registers the personal access token handlers with an authenticated user
+func setupPersonalAccessTokenRouter(mockPat *MockPersonalAccessToken, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		PersonalAccessToken: mockPat,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/tokens/", handler.CreatePersonalAccessToken)
+	r.GET("/api/tokens/", handler.GetAllPersonalAccessTokens)
+	r.DELETE("/api/tokens/:id", handler.RevokePersonalAccessToken)
+
+	return r
+}
+
+// TestHandler_CreatePersonalAccessToken tests the CreatePersonalAccessToken handler
+func TestHandler_CreatePersonalAccessToken(t *testing.T) {
+	createdAt := time.Date(2024, 10, 18, 9, 0, 0, 0, time.UTC)
+	input := entity.CreatePersonalAccessTokenInput{Name: "ci", Scopes: []string{entity.ScopeItemsWrite}}
+
+	testCases := []struct {
+		name           string
+		userId         int
+		input          string
+		mockBehavior   func(mockPat *MockPersonalAccessToken)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			input:  `{"name": "ci", "scopes": ["items:write"]}`,
+			mockBehavior: func(mockPat *MockPersonalAccessToken) {
+				mockPat.On("Create", mock.Anything, 1, input).Return(entity.CreatedPersonalAccessToken{
+					PersonalAccessToken: entity.PersonalAccessToken{Id: 7, Name: "ci", Scopes: input.Scopes, CreatedAt: createdAt},
+					Token:               "tdp_secret",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Token created successfully",
+				"data": {
+					"id": 7,
+					"name": "ci",
+					"scopes": ["items:write"],
+					"expires_at": null,
+					"last_used_at": null,
+					"created_at": "2024-10-18T09:00:00Z",
+					"token": "tdp_secret"
+				}
+			}`,
+		},
+		{
+			name:           "Missing scopes",
+			userId:         1,
+			input:          `{"name": "ci"}`,
+			mockBehavior:   func(mockPat *MockPersonalAccessToken) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Unknown scope",
+			userId: 1,
+			input:  `{"name": "ci", "scopes": ["items:write"]}`,
+			mockBehavior: func(mockPat *MockPersonalAccessToken) {
+				mockPat.On("Create", mock.Anything, 1, input).Return(entity.CreatedPersonalAccessToken{}, utils.ErrInvalidScope)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "unknown scope"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			input:  `{"name": "ci", "scopes": ["items:write"]}`,
+			mockBehavior: func(mockPat *MockPersonalAccessToken) {
+				mockPat.On("Create", mock.Anything, 1, input).Return(entity.CreatedPersonalAccessToken{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create token",
+				"errors": {"database": "Error during token creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockPat := new(MockPersonalAccessToken)
+			r := setupPersonalAccessTokenRouter(mockPat, testCase.userId)
+
+			req := httptest.NewRequest("POST", "/api/tokens/", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockPat)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockPat.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RevokePersonalAccessToken tests the RevokePersonalAccessToken handler
+func TestHandler_RevokePersonalAccessToken(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tokenId        string
+		mockBehavior   func(mockPat *MockPersonalAccessToken)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Success",
+			tokenId: "7",
+			mockBehavior: func(mockPat *MockPersonalAccessToken) {
+				mockPat.On("Revoke", mock.Anything, 1, 7).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Token revoked successfully"}`,
+		},
+		{
+			name:           "Invalid token id",
+			tokenId:        "abc",
+			mockBehavior:   func(mockPat *MockPersonalAccessToken) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid tokenId param",
+				"errors": {"param": "tokenId must be a valid integer"}
+			}`,
+		},
+		{
+			name:    "Not found",
+			tokenId: "7",
+			mockBehavior: func(mockPat *MockPersonalAccessToken) {
+				mockPat.On("Revoke", mock.Anything, 1, 7).Return(utils.ErrPersonalAccessTokenNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Token not found",
+				"errors": {"tokenId": "The requested token does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockPat := new(MockPersonalAccessToken)
+			r := setupPersonalAccessTokenRouter(mockPat, 1)
+
+			req := httptest.NewRequest("DELETE", "/api/tokens/"+testCase.tokenId, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockPat)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockPat.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// moveItem godoc
+// @Summary Move an item within its list
+// @Description Place an item after the item after_id and/or before the item before_id of the same list,
+// @Description the order is shared by every collaborator of the list
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param list_id query int true "List ID"
+// @Param input body entity.MoveInput true "Anchors"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Item moved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input, params or anchors"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to move item"
+// @Router /api/items/{id}/move [post]
+func (h *Handler) MoveItem(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, listId, ok := parseItemAndListParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.MoveInput
+	if err := c.BindJSON(&input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.Item.Move(c.Request.Context(), userId, listId, itemId, input)
+	if err != nil {
+		if isMoveValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+			"list_id": listId,
+		}).Errorf("failed to move item: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to move item", map[string]string{
+			"database": "Error during item move",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Item moved successfully", nil)
+}
+
+// moveList godoc
+// @Summary Move a list among the lists of the user
+// @Description Place a list after the list after_id and/or before the list before_id,
+// @Description the order of the lists is personal to each user
+// @Tags lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param input body entity.MoveInput true "Anchors"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "List moved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input, listId param or anchors"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to move list"
+// @Router /api/lists/{id}/move [post]
+func (h *Handler) MoveList(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	var input entity.MoveInput
+	if err := c.BindJSON(&input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.List.Move(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if isMoveValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrListNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to move list: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to move list", map[string]string{
+			"database": "Error during list move",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "List moved successfully", nil)
+}
+
+// isMoveValidationError reports whether err is caused by the anchors of a move
+func isMoveValidationError(err error) bool {
+	switch err {
+	case utils.ErrMoveAnchorRequired, utils.ErrMoveAnchorIsSelf, utils.ErrMoveAnchorNotFound, utils.ErrMoveAnchorsOrder:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,233 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupPositionRouter registers the move handlers with an authenticated user
+func setupPositionRouter(mockItem *MockItem, mockList *MockList, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Item: mockItem,
+		List: mockList,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/items/:id/move", handler.MoveItem)
+	r.POST("/api/lists/:id/move", handler.MoveList)
+
+	return r
+}
+
+// TestHandler_MoveItem tests the MoveItem handler
+func TestHandler_MoveItem(t *testing.T) {
+	afterId, beforeId := 4, 6
+
+	testCases := []struct {
+		name           string
+		url            string
+		input          string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			url:   "/api/items/5/move?list_id=2",
+			input: `{"after_id": 4, "before_id": 6}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Move", mock.Anything, 1, 2, 5, entity.MoveInput{AfterId: &afterId, BeforeId: &beforeId}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Item moved successfully"}`,
+		},
+		{
+			name:           "Missing listId",
+			url:            "/api/items/5/move",
+			input:          `{"after_id": 4}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "Missing anchor",
+			url:   "/api/items/5/move?list_id=2",
+			input: `{}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Move", mock.Anything, 1, 2, 5, entity.MoveInput{}).Return(utils.ErrMoveAnchorRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "after_id or before_id is required"}
+			}`,
+		},
+		{
+			name:  "Insufficient role",
+			url:   "/api/items/5/move?list_id=2",
+			input: `{"after_id": 4}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Move", mock.Anything, 1, 2, 5, mock.Anything).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:  "Internal server error",
+			url:   "/api/items/5/move?list_id=2",
+			input: `{"after_id": 4}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("Move", mock.Anything, 1, 2, 5, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to move item",
+				"errors": {"database": "Error during item move"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupPositionRouter(mockItem, new(MockList), 1)
+
+			req := httptest.NewRequest("POST", testCase.url, bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_MoveList tests the MoveList handler
+func TestHandler_MoveList(t *testing.T) {
+	beforeId := 4
+
+	testCases := []struct {
+		name           string
+		listId         string
+		mockBehavior   func(mockList *MockList)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			listId: "2",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Move", mock.Anything, 1, 2, entity.MoveInput{BeforeId: &beforeId}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "List moved successfully"}`,
+		},
+		{
+			name:           "Invalid listId",
+			listId:         "abc",
+			mockBehavior:   func(mockList *MockList) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "Anchor not shared with the user",
+			listId: "2",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Move", mock.Anything, 1, 2, mock.Anything).Return(utils.ErrMoveAnchorNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "the anchors must belong to the same order as the moved row"}
+			}`,
+		},
+		{
+			name:   "List not found",
+			listId: "2",
+			mockBehavior: func(mockList *MockList) {
+				mockList.On("Move", mock.Anything, 1, 2, mock.Anything).Return(utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockList := new(MockList)
+			r := setupPositionRouter(new(MockItem), mockList, 1)
+
+			req := httptest.NewRequest("POST", "/api/lists/"+testCase.listId+"/move", bytes.NewBufferString(`{"before_id": 4}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockList)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockList.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getItemHistory godoc
+// @Summary Get the revision history of an item
+// @Description Retrieve every recorded update of an item with the user who made it, the state of the item before and
+// @Description after the update and the changed fields, the most recent first
+// @Tags items
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.ItemRevision} "Item history retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve item history"
+// @Router /api/items/{id}/history [get]
+func (h *Handler) GetItemHistory(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	revisions, err := h.Usecases.Revision.GetItemHistory(c.Request.Context(), userId, itemId)
+	if err != nil {
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to get item history: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve item history", map[string]string{
+			"database": "Error retrieving item history",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Item history retrieved successfully", revisions)
+}
+
+// revertItem godoc
+// @Summary Revert an item to a revision
+// @Description Bring every tracked field of an item back to its value right after the revision,
+// @Description the revert is recorded as a new revision
+// @Tags items
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param revisionId path int true "Revision ID"
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Item reverted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item or revision not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to revert item"
+// @Router /api/items/{id}/history/{revisionId}/revert [post]
+func (h *Handler) RevertItem(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, listId, ok := parseItemAndListParams(c)
+	if !ok {
+		return
+	}
+
+	revisionId, ok := parseRevisionParam(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Revision.RevertItem(c.Request.Context(), userId, listId, itemId, revisionId)
+	if err != nil {
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item does not exist",
+			})
+			return
+		}
+
+		if err == utils.ErrRevisionNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Revision not found", map[string]string{
+				"revisionId": "The requested revision does not exist for this item",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":     userId,
+			"item_id":     itemId,
+			"list_id":     listId,
+			"revision_id": revisionId,
+		}).Errorf("failed to revert item: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to revert item", map[string]string{
+			"database": "Error during item revert",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Item reverted successfully", nil)
+}
+
+// getListHistory godoc
+// @Summary Get the revision history of a list
+// @Description Retrieve every recorded update of a list with the user who made it, the state of the list before and
+// @Description after the update and the changed fields, the most recent first
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.ListRevision} "List history retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve list history"
+// @Router /api/lists/{id}/history [get]
+func (h *Handler) GetListHistory(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	revisions, err := h.Usecases.Revision.GetListHistory(c.Request.Context(), userId, listId)
+	if err != nil {
+		if err == utils.ErrUserNotOwner || err == utils.ErrListNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to get list history: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve list history", map[string]string{
+			"database": "Error retrieving list history",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "List history retrieved successfully", revisions)
+}
+
+// revertList godoc
+// @Summary Revert a list to a revision
+// @Description Bring the title and description of a list back to their values right after the revision,
+// @Description the revert is recorded as a new revision. Only owners can revert a list
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param revisionId path int true "Revision ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "List reverted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or revision not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to revert list"
+// @Router /api/lists/{id}/history/{revisionId}/revert [post]
+func (h *Handler) RevertList(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	revisionId, ok := parseRevisionParam(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Revision.RevertList(c.Request.Context(), userId, listId, revisionId)
+	if err != nil {
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrListNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		if err == utils.ErrRevisionNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Revision not found", map[string]string{
+				"revisionId": "The requested revision does not exist for this list",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":     userId,
+			"list_id":     listId,
+			"revision_id": revisionId,
+		}).Errorf("failed to revert list: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to revert list", map[string]string{
+			"database": "Error during list revert",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "List reverted successfully", nil)
+}
+
+// parseRevisionParam parses the revisionId path param, a 400 response is written when it is invalid
+func parseRevisionParam(c *gin.Context) (int, bool) {
+	revisionId, err := strconv.Atoi(c.Param("revisionId"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid revisionId param", map[string]string{
+			"param": "revisionId must be a valid integer",
+		})
+		return 0, false
+	}
+
+	return revisionId, true
+}
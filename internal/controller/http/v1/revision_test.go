@@ -0,0 +1,316 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupRevisionRouter registers the history and revert handlers with an authenticated user
+func setupRevisionRouter(mockRevision *MockRevision, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Revision: mockRevision,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.GET("/api/items/:id/history", handler.GetItemHistory)
+	r.POST("/api/items/:id/history/:revisionId/revert", handler.RevertItem)
+	r.GET("/api/lists/:id/history", handler.GetListHistory)
+	r.POST("/api/lists/:id/history/:revisionId/revert", handler.RevertList)
+
+	return r
+}
+
+// TestHandler_GetItemHistory tests the GetItemHistory handler
+func TestHandler_GetItemHistory(t *testing.T) {
+	createdAt := time.Date(2024, 11, 20, 9, 0, 0, 0, time.UTC)
+	userId := 1
+
+	testCases := []struct {
+		name           string
+		itemId         string
+		mockBehavior   func(mockRevision *MockRevision)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			itemId: "5",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("GetItemHistory", mock.Anything, 1, 5).Return([]entity.ItemRevision{
+					{
+						Id:        3,
+						ItemId:    5,
+						UserId:    &userId,
+						CreatedAt: createdAt,
+						Before:    entity.ItemState{Title: "Milk", ListId: 2},
+						After:     entity.ItemState{Title: "Milk", Done: true, ListId: 2},
+						Changes:   []entity.FieldChange{{Field: "done", Before: false, After: true}},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Item history retrieved successfully",
+				"data": [{
+					"id": 3,
+					"item_id": 5,
+					"user_id": 1,
+					"created_at": "2024-11-20T09:00:00Z",
+					"before": {"title": "Milk", "description": "", "done": false, "due_at": null, "remind_at": null, "list_id": 2, "parent_id": null, "recurrence": null},
+					"after": {"title": "Milk", "description": "", "done": true, "due_at": null, "remind_at": null, "list_id": 2, "parent_id": null, "recurrence": null},
+					"changes": [{"field": "done", "before": false, "after": true}]
+				}]
+			}`,
+		},
+		{
+			name:           "Invalid itemId",
+			itemId:         "abc",
+			mockBehavior:   func(mockRevision *MockRevision) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid itemId param",
+				"errors": {"param": "itemId must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "Item not shared with the user",
+			itemId: "5",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("GetItemHistory", mock.Anything, 1, 5).Return([]entity.ItemRevision{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRevision := new(MockRevision)
+			r := setupRevisionRouter(mockRevision, 1)
+
+			req := httptest.NewRequest("GET", "/api/items/"+testCase.itemId+"/history", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockRevision)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockRevision.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RevertItem tests the RevertItem handler
+func TestHandler_RevertItem(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockRevision *MockRevision)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/api/items/5/history/3/revert?list_id=2",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertItem", mock.Anything, 1, 2, 5, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Item reverted successfully"
+			}`,
+		},
+		{
+			name:           "Invalid revisionId",
+			url:            "/api/items/5/history/abc/revert?list_id=2",
+			mockBehavior:   func(mockRevision *MockRevision) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid revisionId param",
+				"errors": {"param": "revisionId must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Insufficient role",
+			url:  "/api/items/5/history/3/revert?list_id=2",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertItem", mock.Anything, 1, 2, 5, 3).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name: "Revision not found",
+			url:  "/api/items/5/history/3/revert?list_id=2",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertItem", mock.Anything, 1, 2, 5, 3).Return(utils.ErrRevisionNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Revision not found",
+				"errors": {"revisionId": "The requested revision does not exist for this item"}
+			}`,
+		},
+		{
+			name: "Internal server error",
+			url:  "/api/items/5/history/3/revert?list_id=2",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertItem", mock.Anything, 1, 2, 5, 3).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to revert item",
+				"errors": {"database": "Error during item revert"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRevision := new(MockRevision)
+			r := setupRevisionRouter(mockRevision, 1)
+
+			req := httptest.NewRequest("POST", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockRevision)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockRevision.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetListHistory tests the GetListHistory handler
+func TestHandler_GetListHistory(t *testing.T) {
+	mockRevision := new(MockRevision)
+	r := setupRevisionRouter(mockRevision, 1)
+
+	mockRevision.On("GetListHistory", mock.Anything, 1, 2).Return([]entity.ListRevision{}, nil)
+
+	req := httptest.NewRequest("GET", "/api/lists/2/history", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "ok",
+		"message": "List history retrieved successfully",
+		"data": []
+	}`, w.Body.String())
+
+	mockRevision.AssertExpectations(t)
+}
+
+// TestHandler_RevertList tests the RevertList handler
+func TestHandler_RevertList(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockRevision *MockRevision)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertList", mock.Anything, 1, 2, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "List reverted successfully"
+			}`,
+		},
+		{
+			name: "List not found",
+			mockBehavior: func(mockRevision *MockRevision) {
+				mockRevision.On("RevertList", mock.Anything, 1, 2, 3).Return(utils.ErrListNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRevision := new(MockRevision)
+			r := setupRevisionRouter(mockRevision, 1)
+
+			req := httptest.NewRequest("POST", "/api/lists/2/history/3/revert", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockRevision)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockRevision.AssertExpectations(t)
+		})
+	}
+}
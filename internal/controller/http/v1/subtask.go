@@ -0,0 +1,256 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createSubtask godoc
+// @Summary Create a subtask
+// @Description Create a new item as a subtask of an item, the subtask is created in the list of its parent
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Parent item ID"
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Param input body entity.Item true "Subtask data"
+// @Success 201 {object} utils.SuccessResponse "Subtask created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create subtask"
+// @Router /api/items/{id}/subtasks [post]
+func (h *Handler) CreateSubtask(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	parentId, listId, ok := parseItemAndListParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.Item
+	if err := c.BindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":   userId,
+			"parent_id": parentId,
+		}).Errorf("failed to bind JSON: %s", err)
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	itemId, err := h.Usecases.Item.CreateSubtask(c.Request.Context(), userId, listId, parentId, &input)
+	if err != nil {
+		if isItemValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id":   userId,
+			"list_id":   listId,
+			"parent_id": parentId,
+		}).Errorf("failed to create subtask: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create subtask", map[string]string{
+			"database": "Error during subtask creation",
+		})
+		return
+	}
+
+	h.Metrics.IncrementCreatedItems()
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Subtask created successfully", map[string]interface{}{
+		"id": itemId,
+	})
+}
+
+// getSubtasks godoc
+// @Summary Get the subtasks of an item
+// @Description Retrieve the direct subtasks of an item together with the progress computed from them
+// @Tags items
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.Subtasks} "Subtasks retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve subtasks"
+// @Router /api/items/{id}/subtasks [get]
+func (h *Handler) GetSubtasks(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	subtasks, err := h.Usecases.Item.GetSubtasks(c.Request.Context(), userId, itemId)
+	if err != nil {
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to retrieve subtasks: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve subtasks", map[string]string{
+			"database": "Error during subtask retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Subtasks retrieved successfully", subtasks)
+}
+
+// setItemParent godoc
+// @Summary Set the parent of an item
+// @Description Move an item under another item of the same list, a null parent_id makes it a top-level item.
+// @Description An item cannot become a subtask of itself or of one of its subtasks
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param list_id query int true "List ID"
+// @Param input body entity.SetItemParentInput true "New parent"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Item parent updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input, params or parent"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update item parent"
+// @Router /api/items/{id}/parent [put]
+func (h *Handler) SetItemParent(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, listId, ok := parseItemAndListParams(c)
+	if !ok {
+		return
+	}
+
+	var input entity.SetItemParentInput
+	if err := c.BindJSON(&input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.Item.SetParent(c.Request.Context(), userId, listId, itemId, input)
+	if err != nil {
+		if isItemValidationError(err) {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+				"validation": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+			"list_id": listId,
+		}).Errorf("failed to update item parent: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update item parent", map[string]string{
+			"database": "Error during item parent update",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Item parent updated successfully", nil)
+}
+
+// parseItemAndListParams parses the item Id from the path and the list Id from the query,
+// responding with 400 when invalid
+func parseItemAndListParams(c *gin.Context) (int, int, bool) {
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	listId, err := utils.ParseRequiredParamAsInt(c, "list_id")
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return 0, 0, false
+	}
+
+	return itemId, listId, true
+}
@@ -0,0 +1,292 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupSubtaskRouter registers the subtask handlers with an authenticated user
+func setupSubtaskRouter(mockItem *MockItem, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Item: mockItem,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/items/:id/subtasks", handler.CreateSubtask)
+	r.GET("/api/items/:id/subtasks", handler.GetSubtasks)
+	r.PUT("/api/items/:id/parent", handler.SetItemParent)
+
+	return r
+}
+
+// TestHandler_CreateSubtask tests the CreateSubtask handler
+func TestHandler_CreateSubtask(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		input          string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			url:   "/api/items/4/subtasks?list_id=2",
+			input: `{"title": "Subtask"}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("CreateSubtask", mock.Anything, 1, 2, 4, &entity.Item{Title: "Subtask"}).Return(5, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"status": "ok", "message": "Subtask created successfully", "data": {"id": 5}}`,
+		},
+		{
+			name:           "Missing listId",
+			url:            "/api/items/4/subtasks",
+			input:          `{"title": "Subtask"}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "Parent in another list",
+			url:   "/api/items/4/subtasks?list_id=2",
+			input: `{"title": "Subtask"}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("CreateSubtask", mock.Anything, 1, 2, 4, mock.Anything).Return(0, utils.ErrItemParentNotInList)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "the parent item must belong to the same list"}
+			}`,
+		},
+		{
+			name:  "Internal server error",
+			url:   "/api/items/4/subtasks?list_id=2",
+			input: `{"title": "Subtask"}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("CreateSubtask", mock.Anything, 1, 2, 4, mock.Anything).Return(0, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create subtask",
+				"errors": {"database": "Error during subtask creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupSubtaskRouter(mockItem, 1)
+
+			req := httptest.NewRequest("POST", testCase.url, bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetSubtasks tests the GetSubtasks handler
+func TestHandler_GetSubtasks(t *testing.T) {
+	parentId := 4
+
+	testCases := []struct {
+		name           string
+		itemId         string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			itemId: "4",
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("GetSubtasks", mock.Anything, 1, 4).Return(entity.Subtasks{
+					ParentId: 4,
+					Progress: entity.ItemProgress{Total: 2, Done: 1, Percent: 50},
+					Items: []entity.Item{
+						{Id: 5, Title: "Subtask 1", Done: true, ParentId: &parentId},
+						{Id: 6, Title: "Subtask 2", ParentId: &parentId},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Subtasks retrieved successfully",
+				"data": {
+					"parent_id": 4,
+					"progress": {"total": 2, "done": 1, "percent": 50},
+					"items": [
+						{"id": 5, "title": "Subtask 1", "description": "", "done": true, "parent_id": 4},
+						{"id": 6, "title": "Subtask 2", "description": "", "done": false, "parent_id": 4}
+					]
+				}
+			}`,
+		},
+		{
+			name:   "Item not found",
+			itemId: "4",
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("GetSubtasks", mock.Anything, 1, 4).Return(entity.Subtasks{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupSubtaskRouter(mockItem, 1)
+
+			req := httptest.NewRequest("GET", "/api/items/"+testCase.itemId+"/subtasks", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_SetItemParent tests the SetItemParent handler
+func TestHandler_SetItemParent(t *testing.T) {
+	parentId := 4
+
+	testCases := []struct {
+		name           string
+		input          string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			input: `{"parent_id": 4}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("SetParent", mock.Anything, 1, 2, 5, entity.SetItemParentInput{ParentId: &parentId}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Item parent updated successfully"}`,
+		},
+		{
+			name:  "Top level",
+			input: `{"parent_id": null}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("SetParent", mock.Anything, 1, 2, 5, entity.SetItemParentInput{}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Item parent updated successfully"}`,
+		},
+		{
+			name:  "Cycle",
+			input: `{"parent_id": 4}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("SetParent", mock.Anything, 1, 2, 5, mock.Anything).Return(utils.ErrItemParentCycle)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "an item cannot be a subtask of itself or of one of its subtasks"}
+			}`,
+		},
+		{
+			name:  "Insufficient role",
+			input: `{"parent_id": 4}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("SetParent", mock.Anything, 1, 2, 5, mock.Anything).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupSubtaskRouter(mockItem, 1)
+
+			req := httptest.NewRequest("PUT", "/api/items/5/parent?list_id=2", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
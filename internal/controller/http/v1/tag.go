@@ -0,0 +1,445 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createTag godoc
+// @Summary Create a tag
+// @Description Create a tag of the authenticated user, tag names are unique per user and color is an optional hex color
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param input body entity.CreateTagInput true "Tag name and color"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=entity.Tag} "Tag created successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrorResponse "Tag already exists"
+// @Failure 500 {object} utils.ErrorResponse "Failed to create tag"
+// @Router /api/tags/ [post]
+func (h *Handler) CreateTag(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	var input entity.CreateTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	tag, err := h.Usecases.Tag.Create(c.Request.Context(), userId, input)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to create tag: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to create tag", map[string]string{
+			"database": "Error during tag creation",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// getAllTags godoc
+// @Summary Get all tags
+// @Description Retrieve the tags of the authenticated user
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.Tag} "Tags retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve tags"
+// @Router /api/tags/ [get]
+func (h *Handler) GetAllTags(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	tags, err := h.Usecases.Tag.GetAll(c.Request.Context(), userId)
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to retrieve tags: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tags", map[string]string{
+			"database": "Error during tag retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tags retrieved successfully", tags)
+}
+
+// updateTag godoc
+// @Summary Update a tag
+// @Description Rename or recolor a tag of the authenticated user
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param input body entity.UpdateTagInput true "Updated tag data"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Tag updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or tagId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Tag not found"
+// @Failure 409 {object} utils.ErrorResponse "Tag already exists"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update tag"
+// @Router /api/tags/{id} [put]
+func (h *Handler) UpdateTag(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	tagId, ok := parseTagParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input entity.UpdateTagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"tag_id":  tagId,
+		}).Errorf("failed to bind JSON: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.Tag.UpdateOneById(c.Request.Context(), userId, tagId, input)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"tag_id":  tagId,
+		}).Errorf("failed to update tag: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update tag", map[string]string{
+			"database": "Error during tag update",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tag updated successfully", nil)
+}
+
+// deleteTag godoc
+// @Summary Delete a tag
+// @Description Delete a tag of the authenticated user and detach it from every item
+// @Tags tags
+// @Security BearerAuth
+// @Param id path int true "Tag ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Tag deleted successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid tagId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Tag not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to delete tag"
+// @Router /api/tags/{id} [delete]
+func (h *Handler) DeleteTag(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	tagId, ok := parseTagParam(c, "id")
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Tag.DeleteOneById(c.Request.Context(), userId, tagId)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"tag_id":  tagId,
+		}).Errorf("failed to delete tag: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to delete tag", map[string]string{
+			"database": "Error during tag deletion",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tag deleted successfully", nil)
+}
+
+// getItemTags godoc
+// @Summary Get the tags of an item
+// @Description Retrieve the tags of the authenticated user attached to an item
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.Tag} "Tags retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid itemId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve tags"
+// @Router /api/items/{id}/tags [get]
+func (h *Handler) GetItemTags(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return
+	}
+
+	tags, err := h.Usecases.Tag.GetAllForItem(c.Request.Context(), userId, itemId)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+		}).Errorf("failed to retrieve item tags: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tags", map[string]string{
+			"database": "Error during tag retrieval",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tags retrieved successfully", tags)
+}
+
+// attachItemTag godoc
+// @Summary Attach a tag to an item
+// @Description Attach a tag of the authenticated user to an item of a list shared with them, attaching a tag twice has no effect
+// @Tags tags
+// @Security BearerAuth
+// @Param id path int true "Item ID"
+// @Param tagId path int true "Tag ID"
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Tag attached successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item or tag not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to attach tag"
+// @Router /api/items/{id}/tags/{tagId} [post]
+func (h *Handler) AttachItemTag(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, itemId, tagId, ok := parseItemTagParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Tag.AttachToItem(c.Request.Context(), userId, listId, itemId, tagId)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+			"tag_id":  tagId,
+		}).Errorf("failed to attach tag: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to attach tag", map[string]string{
+			"database": "Error during tag attachment",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tag attached successfully", nil)
+}
+
+// detachItemTag godoc
+// @Summary Detach a tag from an item
+// @Description Detach a tag of the authenticated user from an item of a list shared with them
+// @Tags tags
+// @Security BearerAuth
+// @Param id path int true "Item ID"
+// @Param tagId path int true "Tag ID"
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Tag detached successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid params"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Item or tag not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to detach tag"
+// @Router /api/items/{id}/tags/{tagId} [delete]
+func (h *Handler) DetachItemTag(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, itemId, tagId, ok := parseItemTagParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Tag.DetachFromItem(c.Request.Context(), userId, listId, itemId, tagId)
+	if err != nil {
+		if h.tagErrorResponse(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+			"tag_id":  tagId,
+		}).Errorf("failed to detach tag: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to detach tag", map[string]string{
+			"database": "Error during tag detachment",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Tag detached successfully", nil)
+}
+
+// parseTagParam parses a tag Id from the path, responding with 400 when invalid
+func parseTagParam(c *gin.Context, key string) (int, bool) {
+	tagId, err := strconv.Atoi(c.Param(key))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid tagId param", map[string]string{
+			"param": "tagId must be a valid integer",
+		})
+		return 0, false
+	}
+
+	return tagId, true
+}
+
+// parseItemTagParams parses the list Id from the query and the item and tag Ids from the path,
+// responding with 400 when invalid
+func parseItemTagParams(c *gin.Context) (int, int, int, bool) {
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid itemId param", map[string]string{
+			"param": "itemId must be a valid integer",
+		})
+		return 0, 0, 0, false
+	}
+
+	tagId, ok := parseTagParam(c, "tagId")
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	listId, err := utils.ParseRequiredParamAsInt(c, "list_id")
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return 0, 0, 0, false
+	}
+
+	return listId, itemId, tagId, true
+}
+
+// tagErrorResponse responds to the errors of the tag use cases that are caused by the request,
+// it reports whether a response was written
+func (h *Handler) tagErrorResponse(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrInvalidTagName, utils.ErrInvalidTagColor, utils.ErrTagEmptyRequest:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrTagNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Tag not found", map[string]string{
+			"tagId": "The requested tag does not exist",
+		})
+	case utils.ErrUserNotOwner, utils.ErrItemNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+			"itemId": "The requested item does not exist",
+		})
+	case utils.ErrTagExists:
+		utils.NewErrorResponse(c, http.StatusConflict, "Conflict", map[string]string{
+			"tag": err.Error(),
+		})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,304 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupTagRouter registers the tag handlers with an authenticated user
+func setupTagRouter(mockTag *MockTag, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Tag: mockTag,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/tags/", handler.CreateTag)
+	r.DELETE("/api/tags/:id", handler.DeleteTag)
+	r.POST("/api/items/:id/tags/:tagId", handler.AttachItemTag)
+
+	return r
+}
+
+// TestHandler_CreateTag tests the CreateTag handler
+func TestHandler_CreateTag(t *testing.T) {
+	createdAt := time.Date(2024, 11, 8, 9, 0, 0, 0, time.UTC)
+	input := entity.CreateTagInput{Name: "work"}
+
+	testCases := []struct {
+		name           string
+		userId         int
+		input          string
+		mockBehavior   func(mockTag *MockTag)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			input:  `{"name": "work"}`,
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("Create", mock.Anything, 1, input).Return(entity.Tag{Id: 3, UserId: 1, Name: "work", CreatedAt: createdAt}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Tag created successfully",
+				"data": {"id": 3, "name": "work", "color": null, "created_at": "2024-11-08T09:00:00Z"}
+			}`,
+		},
+		{
+			name:           "Missing name",
+			userId:         1,
+			input:          `{"color": "#ff8800"}`,
+			mockBehavior:   func(mockTag *MockTag) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Invalid name",
+			userId: 1,
+			input:  `{"name": "work"}`,
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("Create", mock.Anything, 1, input).Return(entity.Tag{}, utils.ErrInvalidTagName)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "tag name must have 1 to 64 characters and no commas"}
+			}`,
+		},
+		{
+			name:   "Tag exists",
+			userId: 1,
+			input:  `{"name": "work"}`,
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("Create", mock.Anything, 1, input).Return(entity.Tag{}, utils.ErrTagExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{
+				"status": "error",
+				"message": "Conflict",
+				"errors": {"tag": "tag with this name already exists"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			userId: 1,
+			input:  `{"name": "work"}`,
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("Create", mock.Anything, 1, input).Return(entity.Tag{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to create tag",
+				"errors": {"database": "Error during tag creation"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTag := new(MockTag)
+			r := setupTagRouter(mockTag, testCase.userId)
+
+			req := httptest.NewRequest("POST", "/api/tags/", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTag)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTag.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_DeleteTag tests the DeleteTag handler
+func TestHandler_DeleteTag(t *testing.T) {
+	testCases := []struct {
+		name           string
+		tagId          string
+		mockBehavior   func(mockTag *MockTag)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			tagId: "3",
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("DeleteOneById", mock.Anything, 1, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Tag deleted successfully"}`,
+		},
+		{
+			name:           "Invalid tagId",
+			tagId:          "abc",
+			mockBehavior:   func(mockTag *MockTag) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid tagId param",
+				"errors": {"param": "tagId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "Tag not found",
+			tagId: "3",
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("DeleteOneById", mock.Anything, 1, 3).Return(utils.ErrTagNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Tag not found",
+				"errors": {"tagId": "The requested tag does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTag := new(MockTag)
+			r := setupTagRouter(mockTag, 1)
+
+			req := httptest.NewRequest("DELETE", "/api/tags/"+testCase.tagId, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTag)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTag.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_AttachItemTag tests the AttachItemTag handler
+func TestHandler_AttachItemTag(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		mockBehavior   func(mockTag *MockTag)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			url:  "/api/items/5/tags/3?list_id=2",
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("AttachToItem", mock.Anything, 1, 2, 5, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": "ok", "message": "Tag attached successfully"}`,
+		},
+		{
+			name:           "Missing listId",
+			url:            "/api/items/5/tags/3",
+			mockBehavior:   func(mockTag *MockTag) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name: "Item not shared with the user",
+			url:  "/api/items/5/tags/3?list_id=2",
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("AttachToItem", mock.Anything, 1, 2, 5, 3).Return(utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item does not exist"}
+			}`,
+		},
+		{
+			name: "Tag not found",
+			url:  "/api/items/5/tags/3?list_id=2",
+			mockBehavior: func(mockTag *MockTag) {
+				mockTag.On("AttachToItem", mock.Anything, 1, 2, 5, 3).Return(utils.ErrTagNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Tag not found",
+				"errors": {"tagId": "The requested tag does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTag := new(MockTag)
+			r := setupTagRouter(mockTag, 1)
+
+			req := httptest.NewRequest("POST", testCase.url, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTag)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTag.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// moveItemsToList godoc
+// @Summary Move items to a list
+// @Description Move up to 100 items, together with their subtasks, to the end of the list.
+// @Description The user must be an editor of the list and of the lists holding the items
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Target list ID"
+// @Param input body entity.TransferItemsInput true "Items to move"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=entity.MovedItems} "Items moved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on a list"
+// @Failure 404 {object} utils.ErrorResponse "List or item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to move items"
+// @Router /api/lists/{id}/items/move [post]
+func (h *Handler) MoveItemsToList(c *gin.Context) {
+	userId, listId, input, ok := h.bindTransferRequest(c)
+	if !ok {
+		return
+	}
+
+	moved, err := h.Usecases.Item.MoveToList(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.respondTransferError(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to move items: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to move items", map[string]string{
+			"database": "Error during item move",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Items moved successfully", moved)
+}
+
+// copyItemsToList godoc
+// @Summary Copy items to a list
+// @Description Copy up to 100 items, together with their subtasks, to the end of the list. The copies start undone,
+// @Description outside of any series and without tags. The user must be an editor of the list
+// @Tags items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Target list ID"
+// @Param input body entity.TransferItemsInput true "Items to copy"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 201 {object} utils.SuccessResponse{data=[]entity.ItemCopy} "Items copied successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input or listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "List or item not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to copy items"
+// @Router /api/lists/{id}/items/copy [post]
+func (h *Handler) CopyItemsToList(c *gin.Context) {
+	userId, listId, input, ok := h.bindTransferRequest(c)
+	if !ok {
+		return
+	}
+
+	copies, err := h.Usecases.Item.CopyToList(c.Request.Context(), userId, listId, input)
+	if err != nil {
+		if h.respondTransferError(c, err) {
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to copy items: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to copy items", map[string]string{
+			"database": "Error during item copy",
+		})
+		return
+	}
+
+	for range copies {
+		h.Metrics.IncrementCreatedItems()
+	}
+
+	utils.NewSuccessResponse(c, http.StatusCreated, "Items copied successfully", copies)
+}
+
+// bindTransferRequest reads the user, the target list and the items of a move or copy, responding with an error when invalid
+func (h *Handler) bindTransferRequest(c *gin.Context) (int, int, entity.TransferItemsInput, bool) {
+	var input entity.TransferItemsInput
+
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return 0, 0, input, false
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return 0, 0, input, false
+	}
+
+	if err := c.BindJSON(&input); err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return 0, 0, input, false
+	}
+
+	return userId, listId, input, true
+}
+
+// respondTransferError responds to the expected errors of a move or copy and reports whether it did
+func (h *Handler) respondTransferError(c *gin.Context, err error) bool {
+	switch err {
+	case utils.ErrTransferItemsEmpty, utils.ErrTooManyTransferItems:
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Validation failed", map[string]string{
+			"validation": err.Error(),
+		})
+	case utils.ErrListPermissionDenied:
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+			"role": err.Error(),
+		})
+	case utils.ErrUserNotOwner, utils.ErrItemNotFound:
+		utils.NewErrorResponse(c, http.StatusNotFound, "List or item not found", map[string]string{
+			"id": "The requested list or one of the items does not exist",
+		})
+	default:
+		return false
+	}
+
+	return true
+}
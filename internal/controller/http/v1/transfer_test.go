@@ -0,0 +1,229 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupTransferRouter registers the move and copy handlers with an authenticated user
+func setupTransferRouter(mockItem *MockItem, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Item: mockItem,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.POST("/api/lists/:id/items/move", handler.MoveItemsToList)
+	r.POST("/api/lists/:id/items/copy", handler.CopyItemsToList)
+
+	return r
+}
+
+// TestHandler_MoveItemsToList tests the MoveItemsToList handler
+func TestHandler_MoveItemsToList(t *testing.T) {
+	input := entity.TransferItemsInput{ItemIds: []int{1, 2}}
+
+	testCases := []struct {
+		name           string
+		listId         string
+		input          string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			listId: "5",
+			input:  `{"item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("MoveToList", mock.Anything, 1, 5, input).Return(entity.MovedItems{ListId: 5, ItemIds: []int{1, 2, 7}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Items moved successfully",
+				"data": {"list_id": 5, "item_ids": [1, 2, 7]}
+			}`,
+		},
+		{
+			name:           "Missing item_ids",
+			listId:         "5",
+			input:          `{}`,
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Too many items",
+			listId: "5",
+			input:  `{"item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("MoveToList", mock.Anything, 1, 5, input).Return(entity.MovedItems{}, utils.ErrTooManyTransferItems)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Validation failed",
+				"errors": {"validation": "at most 100 items can be moved or copied at once"}
+			}`,
+		},
+		{
+			name:   "Insufficient role",
+			listId: "5",
+			input:  `{"item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("MoveToList", mock.Anything, 1, 5, input).Return(entity.MovedItems{}, utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name:   "Item not shared with the user",
+			listId: "5",
+			input:  `{"item_ids": [1, 2]}`,
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("MoveToList", mock.Anything, 1, 5, input).Return(entity.MovedItems{}, utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List or item not found",
+				"errors": {"id": "The requested list or one of the items does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupTransferRouter(mockItem, 1)
+
+			req := httptest.NewRequest("POST", "/api/lists/"+testCase.listId+"/items/move", bytes.NewBufferString(testCase.input))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_CopyItemsToList tests the CopyItemsToList handler
+func TestHandler_CopyItemsToList(t *testing.T) {
+	input := entity.TransferItemsInput{ItemIds: []int{1}}
+
+	testCases := []struct {
+		name           string
+		listId         string
+		mockBehavior   func(mockItem *MockItem)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			listId: "5",
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("CopyToList", mock.Anything, 1, 5, input).Return([]entity.ItemCopy{
+					{SourceId: 1, Item: entity.Item{Id: 20, Title: "Item"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Items copied successfully",
+				"data": [{"source_id": 1, "item": {"id": 20, "title": "Item", "description": "", "done": false}}]
+			}`,
+		},
+		{
+			name:           "Invalid listId",
+			listId:         "abc",
+			mockBehavior:   func(mockItem *MockItem) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			listId: "5",
+			mockBehavior: func(mockItem *MockItem) {
+				mockItem.On("CopyToList", mock.Anything, 1, 5, input).Return([]entity.ItemCopy(nil), errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to copy items",
+				"errors": {"database": "Error during item copy"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItem := new(MockItem)
+			r := setupTransferRouter(mockItem, 1)
+
+			req := httptest.NewRequest("POST", "/api/lists/"+testCase.listId+"/items/copy", bytes.NewBufferString(`{"item_ids": [1]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockItem)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockItem.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getTrashedLists godoc
+// @Summary Get the lists in the trash
+// @Description Retrieve the deleted lists owned by the authenticated user, the most recently deleted first.
+// @Description Lists are purged for good once the retention period has passed
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.TrashedList} "Trashed lists retrieved successfully"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve trashed lists"
+// @Router /api/trash/lists/ [get]
+func (h *Handler) GetTrashedLists(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	lists, err := h.Usecases.Trash.GetLists(c.Request.Context(), userId)
+	if err != nil {
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to get trashed lists: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve trashed lists", map[string]string{
+			"database": "Error retrieving trashed lists",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Trashed lists retrieved successfully", lists)
+}
+
+// restoreList godoc
+// @Summary Restore a list from the trash
+// @Description Take a deleted list out of the trash together with its items, only owners can restore a list
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "List restored successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found in the trash"
+// @Failure 500 {object} utils.ErrorResponse "Failed to restore list"
+// @Router /api/trash/lists/{id}/restore [post]
+func (h *Handler) RestoreList(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	err = h.Usecases.Trash.RestoreList(c.Request.Context(), userId, listId)
+	if err != nil {
+		if err == utils.ErrListNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list is not in the trash",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to restore list: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to restore list", map[string]string{
+			"database": "Error during list restore",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "List restored successfully", nil)
+}
+
+// getTrashedItems godoc
+// @Summary Get the items of a list in the trash
+// @Description Retrieve the deleted items of a list shared with the authenticated user, the most recently deleted first.
+// @Description Items are purged for good once the retention period has passed
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse{data=[]entity.TrashedItem} "Trashed items retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "List not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to retrieve trashed items"
+// @Router /api/trash/items/ [get]
+func (h *Handler) GetTrashedItems(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	listId, err := utils.ParseRequiredParamAsInt(c, "list_id")
+	if err != nil {
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid listId param", map[string]string{
+			"param": "listId must be a valid integer",
+		})
+		return
+	}
+
+	items, err := h.Usecases.Trash.GetItems(c.Request.Context(), userId, listId)
+	if err != nil {
+		if err == utils.ErrUserNotOwner {
+			utils.NewErrorResponse(c, http.StatusNotFound, "List not found", map[string]string{
+				"listId": "The requested list does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"list_id": listId,
+		}).Errorf("failed to get trashed items: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve trashed items", map[string]string{
+			"database": "Error retrieving trashed items",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Trashed items retrieved successfully", items)
+}
+
+// restoreItem godoc
+// @Summary Restore an item from the trash
+// @Description Take a deleted item out of the trash together with the subtasks deleted along with it.
+// @Description An item whose parent is still in the trash is restored as a top-level item
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param list_id query int true "List ID"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Item restored successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid itemId or listId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Insufficient role on the list"
+// @Failure 404 {object} utils.ErrorResponse "Item not found in the trash"
+// @Failure 500 {object} utils.ErrorResponse "Failed to restore item"
+// @Router /api/trash/items/{id}/restore [post]
+func (h *Handler) RestoreItem(c *gin.Context) {
+	userId, err := middleware.GetUserId(c)
+	if err != nil {
+		h.Logger.Errorf("failed to get user ID: %s", err)
+		utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+			"auth": "User authentication failed or user not logged in",
+		})
+		return
+	}
+
+	itemId, listId, ok := parseItemAndListParams(c)
+	if !ok {
+		return
+	}
+
+	err = h.Usecases.Trash.RestoreItem(c.Request.Context(), userId, listId, itemId)
+	if err != nil {
+		if err == utils.ErrListPermissionDenied {
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotOwner || err == utils.ErrItemNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "Item not found", map[string]string{
+				"itemId": "The requested item is not in the trash",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+			"item_id": itemId,
+			"list_id": listId,
+		}).Errorf("failed to restore item: %s", err)
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to restore item", map[string]string{
+			"database": "Error during item restore",
+		})
+		return
+	}
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Item restored successfully", nil)
+}
@@ -0,0 +1,338 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupTrashRouter registers the trash handlers with an authenticated user
+func setupTrashRouter(mockTrash *MockTrash, userId int) *gin.Engine {
+	mockUseCase := &usecase.UseCase{
+		Trash: mockTrash,
+	}
+
+	handler := v1.NewHandler(mockUseCase, new(MockAppAuth), &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.Use(func(c *gin.Context) {
+		if userId != 0 {
+			c.Set(middleware.UserIdCtx, userId)
+		}
+		c.Next()
+	})
+
+	r.GET("/api/trash/lists/", handler.GetTrashedLists)
+	r.POST("/api/trash/lists/:id/restore", handler.RestoreList)
+	r.GET("/api/trash/items/", handler.GetTrashedItems)
+	r.POST("/api/trash/items/:id/restore", handler.RestoreItem)
+
+	return r
+}
+
+// TestHandler_GetTrashedLists tests the GetTrashedLists handler
+func TestHandler_GetTrashedLists(t *testing.T) {
+	deletedAt := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockTrash *MockTrash)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("GetLists", mock.Anything, 1).Return([]entity.TrashedList{
+					{List: entity.List{Id: 3, Title: "Groceries"}, DeletedAt: deletedAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Trashed lists retrieved successfully",
+				"data": [{"id": 3, "title": "Groceries", "description": "", "deleted_at": "2024-11-17T09:00:00Z"}]
+			}`,
+		},
+		{
+			name: "Internal server error",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("GetLists", mock.Anything, 1).Return([]entity.TrashedList(nil), errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to retrieve trashed lists",
+				"errors": {"database": "Error retrieving trashed lists"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTrash := new(MockTrash)
+			r := setupTrashRouter(mockTrash, 1)
+
+			req := httptest.NewRequest("GET", "/api/trash/lists/", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTrash)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTrash.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RestoreList tests the RestoreList handler
+func TestHandler_RestoreList(t *testing.T) {
+	testCases := []struct {
+		name           string
+		listId         string
+		mockBehavior   func(mockTrash *MockTrash)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			listId: "3",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreList", mock.Anything, 1, 3).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "List restored successfully"
+			}`,
+		},
+		{
+			name:           "Invalid listId",
+			listId:         "abc",
+			mockBehavior:   func(mockTrash *MockTrash) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:   "List not in the trash",
+			listId: "3",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreList", mock.Anything, 1, 3).Return(utils.ErrListNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list is not in the trash"}
+			}`,
+		},
+		{
+			name:   "Internal server error",
+			listId: "3",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreList", mock.Anything, 1, 3).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": "error",
+				"message": "Failed to restore list",
+				"errors": {"database": "Error during list restore"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTrash := new(MockTrash)
+			r := setupTrashRouter(mockTrash, 1)
+
+			req := httptest.NewRequest("POST", "/api/trash/lists/"+testCase.listId+"/restore", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTrash)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTrash.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_GetTrashedItems tests the GetTrashedItems handler
+func TestHandler_GetTrashedItems(t *testing.T) {
+	deletedAt := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		mockBehavior   func(mockTrash *MockTrash)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			query: "?list_id=3",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("GetItems", mock.Anything, 1, 3).Return([]entity.TrashedItem{
+					{Item: entity.Item{Id: 5, Title: "Milk"}, ListId: 3, DeletedAt: deletedAt},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Trashed items retrieved successfully",
+				"data": [{"id": 5, "title": "Milk", "description": "", "done": false, "list_id": 3, "deleted_at": "2024-11-17T09:00:00Z"}]
+			}`,
+		},
+		{
+			name:           "Missing list_id",
+			query:          "",
+			mockBehavior:   func(mockTrash *MockTrash) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid listId param",
+				"errors": {"param": "listId must be a valid integer"}
+			}`,
+		},
+		{
+			name:  "List not shared with the user",
+			query: "?list_id=3",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("GetItems", mock.Anything, 1, 3).Return([]entity.TrashedItem(nil), utils.ErrUserNotOwner)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "List not found",
+				"errors": {"listId": "The requested list does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTrash := new(MockTrash)
+			r := setupTrashRouter(mockTrash, 1)
+
+			req := httptest.NewRequest("GET", "/api/trash/items/"+testCase.query, nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTrash)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTrash.AssertExpectations(t)
+		})
+	}
+}
+
+// TestHandler_RestoreItem tests the RestoreItem handler
+func TestHandler_RestoreItem(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockTrash *MockTrash)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreItem", mock.Anything, 1, 3, 5).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Item restored successfully"
+			}`,
+		},
+		{
+			name: "Insufficient role",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreItem", mock.Anything, 1, 3, 5).Return(utils.ErrListPermissionDenied)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"status": "error",
+				"message": "Forbidden",
+				"errors": {"role": "user does not have the required role on the list"}
+			}`,
+		},
+		{
+			name: "Item not in the trash",
+			mockBehavior: func(mockTrash *MockTrash) {
+				mockTrash.On("RestoreItem", mock.Anything, 1, 3, 5).Return(utils.ErrItemNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "Item not found",
+				"errors": {"itemId": "The requested item is not in the trash"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockTrash := new(MockTrash)
+			r := setupTrashRouter(mockTrash, 1)
+
+			req := httptest.NewRequest("POST", "/api/trash/items/5/restore?list_id=3", nil)
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockTrash)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockTrash.AssertExpectations(t)
+		})
+	}
+}
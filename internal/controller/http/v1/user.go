@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/gin-gonic/gin"
@@ -13,11 +15,14 @@ import (
 // @Summary Delete a user
 // @Description Deletes a user by their userId
 // @Tags user
+// @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param appId header string true "Application ID"
 // @Param appKey header string true "Application Key"
 // @Success 200 {object} utils.SuccessResponse "User deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Invalid userId param"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
 // @Failure 404 {object} utils.ErrorResponse "User not found"
 // @Failure 500 {object} utils.ErrorResponse "Failed to delete user"
 // @Router /private/api/users/{id} [delete]
@@ -51,10 +56,87 @@ func (h *Handler) DeleteUserByAdmin(c *gin.Context) {
 		return
 	}
 
+	adminId, _ := middleware.GetUserId(c)
 	h.Logger.WithFields(map[string]interface{}{
-		"user_id": userId,
+		"user_id":  userId,
+		"admin_id": adminId,
 	}).Info("user deleted successfully")
 	h.Metrics.IncrementDeletedUsers()
 
 	utils.NewSuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
+
+// UpdateUserRole godoc
+// @Summary Assign a role to a user
+// @Description Assigns the user, support or admin role to a user, the role is applied to tokens issued after the change
+// @Tags user
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param input body entity.UpdateUserRoleInput true "Role"
+// @Param appId header string true "Application ID"
+// @Param appKey header string true "Application Key"
+// @Success 200 {object} utils.SuccessResponse "Role updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Invalid input"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "User not found"
+// @Failure 500 {object} utils.ErrorResponse "Failed to update role"
+// @Router /private/api/users/{id}/role [put]
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.Logger.Errorf("invalid userId parameter: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid userId param", map[string]string{
+			"param": "userId must be a valid integer",
+		})
+		return
+	}
+
+	var input entity.UpdateUserRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.Logger.Errorf("validation failed: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+			"body": "Invalid or malformed JSON",
+		})
+		return
+	}
+
+	err = h.Usecases.User.UpdateRole(c.Request.Context(), userId, input)
+	if err != nil {
+		if err == utils.ErrInvalidRole {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+				"role": err.Error(),
+			})
+			return
+		}
+
+		if err == utils.ErrUserNotFound {
+			utils.NewErrorResponse(c, http.StatusNotFound, "User not found", map[string]string{
+				"userId": "The requested user does not exist",
+			})
+			return
+		}
+
+		h.Logger.WithFields(map[string]interface{}{
+			"user_id": userId,
+		}).Errorf("failed to update user role: %s", err)
+
+		utils.NewErrorResponse(c, http.StatusInternalServerError, "Failed to update role", map[string]string{
+			"database": "Error during role update",
+		})
+		return
+	}
+
+	adminId, _ := middleware.GetUserId(c)
+	h.Logger.WithFields(map[string]interface{}{
+		"user_id":  userId,
+		"role":     input.Role,
+		"admin_id": adminId,
+	}).Info("user role updated successfully")
+
+	utils.NewSuccessResponse(c, http.StatusOK, "Role updated successfully", nil)
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	v1 "github.com/berikulyBeket/todo-plus/internal/controller/http/v1"
+	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
 	"github.com/berikulyBeket/todo-plus/pkg/metrics"
@@ -114,3 +116,104 @@ func TestHandler_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+// TestHandler_UpdateUserRole tests the UpdateUserRole handler
+func TestHandler_UpdateUserRole(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userId         string
+		body           string
+		mockBehavior   func(mockUser *MockUser)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Success",
+			userId: "2",
+			body:   `{"role": "support"}`,
+			mockBehavior: func(mockUser *MockUser) {
+				mockUser.On("UpdateRole", mock.Anything, 2, entity.UpdateUserRoleInput{Role: "support"}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"status": "ok",
+				"message": "Role updated successfully"
+			}`,
+		},
+		{
+			name:           "Missing role",
+			userId:         "2",
+			body:           `{}`,
+			mockBehavior:   func(mockUser *MockUser) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"body": "Invalid or malformed JSON"}
+			}`,
+		},
+		{
+			name:   "Unknown role",
+			userId: "2",
+			body:   `{"role": "superuser"}`,
+			mockBehavior: func(mockUser *MockUser) {
+				mockUser.On("UpdateRole", mock.Anything, 2, entity.UpdateUserRoleInput{Role: "superuser"}).Return(utils.ErrInvalidRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"status": "error",
+				"message": "Invalid input",
+				"errors": {"role": "unknown role"}
+			}`,
+		},
+		{
+			name:   "User not found",
+			userId: "2",
+			body:   `{"role": "admin"}`,
+			mockBehavior: func(mockUser *MockUser) {
+				mockUser.On("UpdateRole", mock.Anything, 2, entity.UpdateUserRoleInput{Role: "admin"}).Return(utils.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": "error",
+				"message": "User not found",
+				"errors": {"userId": "The requested user does not exist"}
+			}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockUser := new(MockUser)
+			appAuth := new(MockAppAuth)
+
+			mockUseCase := &usecase.UseCase{
+				User: mockUser,
+			}
+
+			handler := v1.NewHandler(mockUseCase, appAuth, &logger.NoOpLogger{}, &metrics.NoOpMetrics{})
+
+			gin.SetMode(gin.TestMode)
+			r := gin.Default()
+
+			r.PUT("/private/api/users/:id/role", handler.UpdateUserRole)
+
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/private/api/users/%s/role", testCase.userId), strings.NewReader(testCase.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testCase.mockBehavior(mockUser)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatus, w.Code)
+			assert.JSONEq(t, testCase.expectedBody, w.Body.String())
+
+			mockUser.AssertExpectations(t)
+		})
+	}
+}
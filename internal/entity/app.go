@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"time"
+
+	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// App represents an application registered to call the API, only the hashes of its keys are stored
+type App struct {
+	Id                   int        `json:"id"`
+	AppId                string     `json:"app_id"`
+	Name                 string     `json:"name"`
+	AccessTypes          []string   `json:"access_types"`
+	Enabled              bool       `json:"enabled"`
+	KeyHash              string     `json:"-"`
+	PreviousKeyHash      *string    `json:"-"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at"`
+	LastUsedAt           *time.Time `json:"last_used_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// HasAccess checks if the app is allowed the given access type
+func (a App) HasAccess(accessType string) bool {
+	for _, t := range a.AccessTypes {
+		if t == accessType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreatedApp is returned when an app is created or its key rotated, it is the only time the raw key is available
+type CreatedApp struct {
+	App
+	AppKey string `json:"app_key"`
+}
+
+// CreateAppInput represents the input for registering an app
+type CreateAppInput struct {
+	Name        string   `json:"name" binding:"required,min=1,max=100"`
+	AccessTypes []string `json:"access_types" binding:"required,min=1"`
+}
+
+// Validate checks that every access type is supported
+func (i CreateAppInput) Validate() error {
+	return validateAccessTypes(i.AccessTypes)
+}
+
+// UpdateAppInput represents the input for updating an app
+type UpdateAppInput struct {
+	Name        *string   `json:"name"`
+	AccessTypes *[]string `json:"access_types"`
+	Enabled     *bool     `json:"enabled"`
+}
+
+// Validate checks if the update input has at least one valid field
+func (i UpdateAppInput) Validate() error {
+	if i.Name == nil && i.AccessTypes == nil && i.Enabled == nil {
+		return utils.ErrAppEmptyRequest
+	}
+
+	if i.AccessTypes != nil {
+		return validateAccessTypes(*i.AccessTypes)
+	}
+
+	return nil
+}
+
+// RotateAppKeyInput represents the input for rotating an app key, the previous key
+// keeps working during the overlap window, e.g. "24h"
+type RotateAppKeyInput struct {
+	Overlap string `json:"overlap"`
+}
+
+// validateAccessTypes checks that access types are known and not empty
+func validateAccessTypes(accessTypes []string) error {
+	if len(accessTypes) == 0 {
+		return utils.ErrInvalidAccessType
+	}
+
+	for _, accessType := range accessTypes {
+		if accessType != appauth.PublicAccess && accessType != appauth.PrivateAccess {
+			return utils.ErrInvalidAccessType
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// MaxAttachmentNameLength is the longest file name an attachment can have
+const MaxAttachmentNameLength = 255
+
+// Attachment represents a file attached to an item, the content is kept in the blob store under BlobKey.
+// A user Id is missing once the uploader is deleted
+type Attachment struct {
+	Id          int       `json:"id"`
+	ItemId      int       `json:"item_id"`
+	UserId      *int      `json:"user_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	BlobKey     string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UploadAttachmentInput represents a file uploaded to an item, the content type is detected from the content
+type UploadAttachmentInput struct {
+	Name    string
+	Size    int64
+	Content io.Reader
+}
+
+// Validate keeps the base of the file name and checks its length
+func (i *UploadAttachmentInput) Validate() error {
+	i.Name = strings.TrimSpace(path.Base(strings.ReplaceAll(i.Name, "\\", "/")))
+
+	if i.Name == "" || i.Name == "." || i.Name == "/" || len(i.Name) > MaxAttachmentNameLength {
+		return utils.ErrInvalidAttachmentFileName
+	}
+
+	if i.Size <= 0 {
+		return utils.ErrAttachmentEmpty
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// MaxBulkItems bounds the number of items changed by a single bulk operation
+const MaxBulkItems = 100
+
+const (
+	BulkOperationUpdate = "update"
+	BulkOperationDelete = "delete"
+	BulkOperationMove   = "move"
+)
+
+const (
+	BulkItemStatusOk        = "ok"
+	BulkItemStatusNotFound  = "not_found"
+	BulkItemStatusForbidden = "forbidden"
+)
+
+// BulkItemsInput represents an operation applied to many items at once. Updates need the fields to set,
+// moves need the list the items are moved to
+type BulkItemsInput struct {
+	Operation string          `json:"operation" binding:"required" enums:"update,delete,move"`
+	ItemIds   []int           `json:"item_ids" binding:"required"`
+	Update    *BulkItemUpdate `json:"update"`
+	ListId    *int            `json:"list_id"`
+}
+
+// BulkItemUpdate represents the fields set on every item of a bulk update
+type BulkItemUpdate struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	Done        *bool      `json:"done"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+}
+
+// Validate checks the operation and its arguments, and drops repeated item Ids keeping the first occurrence
+func (i *BulkItemsInput) Validate() error {
+	switch i.Operation {
+	case BulkOperationUpdate:
+		if i.Update == nil || !i.UpdateInput().HasItemFields() {
+			return utils.ErrItemEmptyRequest
+		}
+	case BulkOperationDelete:
+	case BulkOperationMove:
+		if i.ListId == nil {
+			return utils.ErrBulkListRequired
+		}
+	default:
+		return utils.ErrInvalidBulkOperation
+	}
+
+	itemIds := make([]int, 0, len(i.ItemIds))
+	seen := make(map[int]bool, len(i.ItemIds))
+	for _, itemId := range i.ItemIds {
+		if !seen[itemId] {
+			seen[itemId] = true
+			itemIds = append(itemIds, itemId)
+		}
+	}
+	i.ItemIds = itemIds
+
+	if len(i.ItemIds) == 0 {
+		return utils.ErrBulkItemsEmpty
+	}
+
+	if len(i.ItemIds) > MaxBulkItems {
+		return utils.ErrTooManyBulkItems
+	}
+
+	return nil
+}
+
+// UpdateInput returns the fields of a bulk update as the update input of a single item
+func (i BulkItemsInput) UpdateInput() UpdateItemInput {
+	if i.Update == nil {
+		return UpdateItemInput{}
+	}
+
+	return UpdateItemInput{
+		Title:       i.Update.Title,
+		Description: i.Update.Description,
+		Done:        i.Update.Done,
+		DueAt:       i.Update.DueAt,
+		RemindAt:    i.Update.RemindAt,
+		Scope:       ItemEditScopeThis,
+	}
+}
+
+// ItemAccess represents the role of a user on the list holding an item
+type ItemAccess struct {
+	ItemId int
+	ListId int
+	Role   string
+}
+
+// BulkItemResult represents the outcome of a bulk operation for one of the requested items
+type BulkItemResult struct {
+	ItemId int    `json:"item_id"`
+	Status string `json:"status" enums:"ok,not_found,forbidden"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkItemsResult represents the outcome of a bulk operation, the affected Ids include the subtasks
+// deleted or moved along with the requested items
+type BulkItemsResult struct {
+	Operation   string           `json:"operation"`
+	Results     []BulkItemResult `json:"results"`
+	AffectedIds []int            `json:"affected_ids"`
+}
@@ -0,0 +1,70 @@
+package entity
+
+import "github.com/berikulyBeket/todo-plus/utils"
+
+// Roles a user can have on a list, ordered from the least to the most privileged.
+// Viewers can read the list and its items, editors can also modify items and owners can
+// additionally update, delete and share the list
+const (
+	ListRoleViewer = "viewer"
+	ListRoleEditor = "editor"
+	ListRoleOwner  = "owner"
+)
+
+// ListRoles lists every role a collaborator can be given
+var ListRoles = []string{ListRoleViewer, ListRoleEditor, ListRoleOwner}
+
+// listRoleRanks orders list roles so a role grants everything the lower ranked roles do
+var listRoleRanks = map[string]int{
+	ListRoleViewer: 1,
+	ListRoleEditor: 2,
+	ListRoleOwner:  3,
+}
+
+// ListRoleAllows checks if the role grants at least the access of the required role
+func ListRoleAllows(role, required string) bool {
+	rank, ok := listRoleRanks[role]
+	if !ok {
+		return false
+	}
+
+	return rank >= listRoleRanks[required]
+}
+
+// ListCollaborator represents a user the list is shared with and their role on it
+type ListCollaborator struct {
+	UserId   int    `json:"user_id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// AddCollaboratorInput represents the input for sharing a list with a user
+type AddCollaboratorInput struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+// Validate checks that the role is one of the list roles
+func (i AddCollaboratorInput) Validate() error {
+	return validateListRole(i.Role)
+}
+
+// UpdateCollaboratorInput represents the input for changing the role of a collaborator
+type UpdateCollaboratorInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Validate checks that the role is one of the list roles
+func (i UpdateCollaboratorInput) Validate() error {
+	return validateListRole(i.Role)
+}
+
+// validateListRole checks that the role is a known list role
+func validateListRole(role string) error {
+	if _, ok := listRoleRanks[role]; !ok {
+		return utils.ErrInvalidListRole
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// MaxCommentLength is the longest body a comment can have
+const MaxCommentLength = 5000
+
+// Bounds of the number of comments in a page
+const (
+	DefaultCommentPageLimit = 20
+	MaxCommentPageLimit     = 100
+)
+
+// Comment represents a message about an item, comments with a parent are replies to another comment of the item
+type Comment struct {
+	Id         int           `json:"id"`
+	ItemId     int           `json:"item_id"`
+	ParentId   *int          `json:"parent_id,omitempty"`
+	Author     CommentAuthor `json:"author"`
+	Body       string        `json:"body"`
+	ReplyCount int           `json:"reply_count"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  *time.Time    `json:"updated_at"`
+}
+
+// CommentAuthor represents the user who wrote a comment
+type CommentAuthor struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// CommentCreatedEvent represents the event data when a comment is created
+type CommentCreatedEvent struct {
+	UserId  int     `json:"user_id"`
+	Comment Comment `json:"comment"`
+}
+
+// CreateCommentInput represents the input for creating a comment, a parent makes the comment a reply
+type CreateCommentInput struct {
+	Body     string `json:"body" binding:"required"`
+	ParentId *int   `json:"parent_id"`
+}
+
+// Validate trims the body and checks its length
+func (i *CreateCommentInput) Validate() error {
+	i.Body = strings.TrimSpace(i.Body)
+
+	return validateCommentBody(i.Body)
+}
+
+// UpdateCommentInput represents the input for editing a comment
+type UpdateCommentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// Validate trims the body and checks its length
+func (i *UpdateCommentInput) Validate() error {
+	i.Body = strings.TrimSpace(i.Body)
+
+	return validateCommentBody(i.Body)
+}
+
+// CommentQuery selects a page of the comments of an item. Without a parent the top level comments are selected,
+// otherwise the replies to the parent. Comments are ordered from the oldest and After is the Id of the last
+// comment of the previous page
+type CommentQuery struct {
+	ParentId *int
+	After    int
+	Limit    int
+}
+
+// Validate applies the default limit and checks the bounds of the query
+func (q *CommentQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = DefaultCommentPageLimit
+	}
+
+	if q.Limit < 0 || q.Limit > MaxCommentPageLimit {
+		return utils.ErrInvalidCommentPageLimit
+	}
+
+	if q.After < 0 {
+		return utils.ErrInvalidCommentCursor
+	}
+
+	return nil
+}
+
+// CommentPage represents a page of comments, NextCursor is missing on the last page
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	NextCursor *int      `json:"next_cursor"`
+}
+
+// validateCommentBody checks that a comment body is neither empty nor too long
+func validateCommentBody(body string) error {
+	if body == "" || len(body) > MaxCommentLength {
+		return utils.ErrInvalidCommentBody
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package entity
+
+// IdempotentRequest represents a request made with an Idempotency-Key. The fingerprint identifies the request
+// the key was first used with, the response is only set once that request has completed
+type IdempotentRequest struct {
+	Fingerprint string              `json:"fingerprint"`
+	Response    *IdempotentResponse `json:"response,omitempty"`
+}
+
+// IdempotentResponse represents the stored response of an idempotent request that is replayed on retries
+type IdempotentResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Completed reports whether the response of the request is stored
+func (r IdempotentRequest) Completed() bool {
+	return r.Response != nil
+}
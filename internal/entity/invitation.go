@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Invitation statuses, a pending invitation past its expiration is reported as expired
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// Invitation represents an offer to join a list with a role. It is either addressed to a user
+// or carried by a link token, only the hash of a link token is stored
+type Invitation struct {
+	Id        int       `json:"id"`
+	ListId    int       `json:"list_id"`
+	ListTitle string    `json:"list_title"`
+	InviterId int       `json:"inviter_id"`
+	InviteeId *int      `json:"invitee_id"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	TokenHash *string   `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsLink reports whether the invitation is redeemed with a link token instead of being addressed to a user
+func (i Invitation) IsLink() bool {
+	return i.InviteeId == nil
+}
+
+// CreatedInvitation is returned once on creation, for link invitations it is the only time the raw token is available
+type CreatedInvitation struct {
+	Invitation
+	Token string `json:"token,omitempty"`
+}
+
+// CreateInvitationInput represents the input for inviting a user to a list, an empty username creates a link invitation
+type CreateInvitationInput struct {
+	Username string `json:"username"`
+	Role     string `json:"role" binding:"required"`
+}
+
+// Validate checks that the offered role exists
+func (i CreateInvitationInput) Validate() error {
+	return validateListRole(i.Role)
+}
+
+// AcceptInvitationLinkInput represents the input for redeeming a link invitation
+type AcceptInvitationLinkInput struct {
+	Token string `json:"token" binding:"required"`
+}
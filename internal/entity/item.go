@@ -1,13 +1,27 @@
 package entity
 
-import "github.com/berikulyBeket/todo-plus/utils"
+import (
+	"time"
 
-// Item represents a task or item in a to-do list
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// Item represents a task or item in a to-do list, due and reminder times are timezone-aware.
+// Recurring items belong to a series and the next occurrence is created when an occurrence is done.
+// Items with a parent are subtasks of another item of the same list. Version changes with every write to the item
+// and is only filled in for single items
 type Item struct {
-	Id          int    `json:"id"`
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	Done        bool   `json:"done"`
+	Id          int         `json:"id"`
+	Title       string      `json:"title" binding:"required"`
+	Description string      `json:"description"`
+	Done        bool        `json:"done"`
+	DueAt       *time.Time  `json:"due_at,omitempty"`
+	RemindAt    *time.Time  `json:"remind_at,omitempty"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	SeriesId    *int        `json:"series_id,omitempty"`
+	Occurrence  *int        `json:"occurrence,omitempty"`
+	ParentId    *int        `json:"parent_id,omitempty"`
+	Version     int         `json:"version,omitempty"`
 }
 
 // ItemCreatedEvent represents the event data when an item is created
@@ -29,18 +43,55 @@ type ItemDeletedEvent struct {
 	ItemId int `json:"item_id"`
 }
 
+// ItemReminderDueEvent represents the event data when the reminder of an item is due
+type ItemReminderDueEvent struct {
+	ListId   int        `json:"list_id"`
+	ItemId   int        `json:"item_id"`
+	Title    string     `json:"title"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+	RemindAt time.Time  `json:"remind_at"`
+}
+
 // UpdateItemInput represents the input for updating an item
 type UpdateItemInput struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Done        *bool   `json:"done"`
+	Title       *string     `json:"title"`
+	Description *string     `json:"description"`
+	Done        *bool       `json:"done"`
+	DueAt       *time.Time  `json:"due_at"`
+	RemindAt    *time.Time  `json:"remind_at"`
+	Recurrence  *Recurrence `json:"recurrence"`
+	Scope       string      `json:"scope" enums:"this,future"`
+	Cascade     bool        `json:"cascade"`
 }
 
-// Validate checks if the update input has at least one valid field
-func (i UpdateItemInput) Validate() error {
-	if i.Title == nil && i.Description == nil && i.Done == nil {
+// Validate checks if the update input has at least one valid field, a known scope and a valid recurrence.
+// A recurrence with an empty rule stops the item from repeating
+func (i *UpdateItemInput) Validate() error {
+	if !i.HasItemFields() && i.Recurrence == nil {
 		return utils.ErrItemEmptyRequest
 	}
 
+	switch i.Scope {
+	case "":
+		i.Scope = ItemEditScopeThis
+	case ItemEditScopeThis, ItemEditScopeFuture:
+	default:
+		return utils.ErrInvalidItemEditScope
+	}
+
+	if i.Recurrence != nil && i.Recurrence.Rule != "" {
+		return i.Recurrence.Validate()
+	}
+
 	return nil
 }
+
+// HasItemFields checks if the input changes any column of the item itself
+func (i UpdateItemInput) HasItemFields() bool {
+	return i.Title != nil || i.Description != nil || i.Done != nil || i.DueAt != nil || i.RemindAt != nil
+}
+
+// HasSeriesFields checks if the input changes any field kept by the series of the item
+func (i UpdateItemInput) HasSeriesFields() bool {
+	return i.Title != nil || i.Description != nil || i.Recurrence != nil || i.DueAt != nil
+}
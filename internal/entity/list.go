@@ -2,11 +2,12 @@ package entity
 
 import "github.com/berikulyBeket/todo-plus/utils"
 
-// List represents a to-do list
+// List represents a to-do list, Version changes with every write to the list and is only filled in for single lists
 type List struct {
 	Id          int    `json:"id"`
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
+	Version     int    `json:"version,omitempty"`
 }
 
 // ListCreatedEvent represents the event data when a list is created
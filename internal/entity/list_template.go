@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// MaxListTitleLength is the longest title a list or a list template can have
+const MaxListTitleLength = 255
+
+// ListTemplate represents a reusable copy of a list that new lists can be created from, templates are private to their user.
+// Items are only filled in when a single template is retrieved
+type ListTemplate struct {
+	Id          int                `json:"id"`
+	UserId      int                `json:"-"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	ItemCount   int                `json:"item_count"`
+	Items       []ListTemplateItem `json:"items,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// ListTemplateItem represents an item of a list template, items with a parent become subtasks of the parent
+type ListTemplateItem struct {
+	Id          int    `json:"id"`
+	ParentId    *int   `json:"parent_id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ListCopy represents a list created from another list or from a template together with its items
+type ListCopy struct {
+	List  List   `json:"list"`
+	Items []Item `json:"items"`
+}
+
+// CloneListInput represents the options for cloning a list, the clone keeps the title of the original
+// unless a title is given and keeps the done state of the items unless ResetDone is set
+type CloneListInput struct {
+	Title     *string `json:"title"`
+	ResetDone bool    `json:"reset_done"`
+}
+
+// Validate trims and checks the title of the clone
+func (i *CloneListInput) Validate() error {
+	return validateListTitle(&i.Title)
+}
+
+// CreateListTemplateInput represents the input for saving a list as a template, the template keeps the title
+// and description of the list unless they are given
+type CreateListTemplateInput struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
+
+// Validate trims and checks the title of the template
+func (i *CreateListTemplateInput) Validate() error {
+	return validateListTitle(&i.Title)
+}
+
+// UseListTemplateInput represents the input for creating a list from a template, the list keeps the title
+// of the template unless a title is given
+type UseListTemplateInput struct {
+	Title *string `json:"title"`
+}
+
+// Validate trims and checks the title of the new list
+func (i *UseListTemplateInput) Validate() error {
+	return validateListTitle(&i.Title)
+}
+
+// validateListTitle trims an optional title and checks that it is not empty or too long
+func validateListTitle(title **string) error {
+	if *title == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(**title)
+	if trimmed == "" || utf8.RuneCountInString(trimmed) > MaxListTitleLength {
+		return utils.ErrInvalidListTitle
+	}
+
+	*title = &trimmed
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// OutboxEvent represents a domain event recorded in the outbox in the transaction of the change it describes,
+// the payload is the JSON encoded event published to the topic
+type OutboxEvent struct {
+	Id       int    `json:"id"`
+	Topic    string `json:"topic"`
+	Payload  []byte `json:"payload"`
+	Attempts int    `json:"attempts"`
+}
+
+// OutboxBacklog represents the events of the outbox waiting to be published
+type OutboxBacklog struct {
+	Pending   int           `json:"pending"`
+	OldestAge time.Duration `json:"oldest_age"`
+}
@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// Sort keys of collections, position is the order chosen by the users and relevance the order of search results
+const (
+	SortPosition  = "position"
+	SortCreated   = "created"
+	SortUpdated   = "updated"
+	SortTitle     = "title"
+	SortDue       = "due"
+	SortRelevance = "relevance"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Sort keys supported by each collection, the first key is the default one
+var (
+	ListSorts   = []string{SortPosition, SortCreated, SortUpdated, SortTitle}
+	ItemSorts   = []string{SortPosition, SortCreated, SortUpdated, SortTitle, SortDue}
+	SearchSorts = []string{SortRelevance}
+)
+
+// PageQuery selects a page of a collection in the given sort order. Cursor is the next_cursor of the previous page,
+// a cursor is only valid for the sort and order it was issued for
+type PageQuery struct {
+	Limit  int
+	Cursor string
+	Sort   string
+	Order  string
+}
+
+// PageCursor is the position of the last row of a page, Key is the sort value of the row and Id breaks ties.
+// Clients only see the cursor encoded as an opaque string
+type PageCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	Id   int    `json:"i"`
+}
+
+// Validate applies the defaults of the query and checks the limit, the sort against the supported sorts and the cursor.
+// Relevance is sorted from the best match by default and the other sorts in ascending order
+func (q *PageQuery) Validate(sorts []string) error {
+	if q.Limit == 0 {
+		q.Limit = DefaultPageLimit
+	}
+
+	if q.Limit < 0 || q.Limit > MaxPageLimit {
+		return utils.ErrInvalidPageLimit
+	}
+
+	if q.Sort == "" {
+		q.Sort = sorts[0]
+	}
+
+	if !slices.Contains(sorts, q.Sort) {
+		return utils.ErrInvalidSort
+	}
+
+	switch q.Order {
+	case "":
+		q.Order = SortOrderAsc
+		if q.Sort == SortRelevance {
+			q.Order = SortOrderDesc
+		}
+	case SortOrderAsc, SortOrderDesc:
+	default:
+		return utils.ErrInvalidSortOrder
+	}
+
+	_, err := q.After()
+
+	return err
+}
+
+// After decodes the cursor of the query, nil is returned for the first page
+func (q PageQuery) After() (*PageCursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, utils.ErrInvalidPageCursor
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != q.sortKey() {
+		return nil, utils.ErrInvalidPageCursor
+	}
+
+	return &cursor, nil
+}
+
+// NextCursor encodes the cursor of the page ending at the row with the sort value key and the Id
+func (q PageQuery) NextCursor(key string, id int) *string {
+	data, _ := json.Marshal(PageCursor{Sort: q.sortKey(), Key: key, Id: id})
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+
+	return &cursor
+}
+
+// CacheKey identifies the page selected by the query in cache keys
+func (q PageQuery) CacheKey() string {
+	return fmt.Sprintf("%s:%d:%s", q.sortKey(), q.Limit, q.Cursor)
+}
+
+// Descending reports whether the rows are sorted in descending order
+func (q PageQuery) Descending() bool {
+	return q.Order == SortOrderDesc
+}
+
+// sortKey combines the sort and the order the cursors of the query are bound to
+func (q PageQuery) sortKey() string {
+	return q.Sort + ":" + q.Order
+}
+
+// ItemQuery selects a page of the items of a list, optionally only the items with the done state
+// or having every one of the tags of the user
+type ItemQuery struct {
+	PageQuery
+	Done *bool
+	Tags []string
+}
+
+// CacheKey identifies the page selected by the query in cache keys, pages filtered by tags are not cached
+func (q ItemQuery) CacheKey() string {
+	if q.Done == nil {
+		return q.PageQuery.CacheKey()
+	}
+
+	return fmt.Sprintf("done=%t:%s", *q.Done, q.PageQuery.CacheKey())
+}
+
+// ItemPage represents a page of items, NextCursor is missing on the last page
+type ItemPage struct {
+	Items      []Item  `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ListPage represents a page of lists, NextCursor is missing on the last page
+type ListPage struct {
+	Lists      []List  `json:"lists"`
+	NextCursor *string `json:"next_cursor"`
+}
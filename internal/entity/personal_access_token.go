@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// Resources guarded by personal access token scopes, a scope is "<resource>:read" or "<resource>:write".
+// Tokens can never be granted a scope on the tokens resource, so they cannot mint or revoke other tokens
+const (
+	ResourceLists  = "lists"
+	ResourceItems  = "items"
+	ResourceTokens = "tokens"
+)
+
+// Scopes that can be granted to a personal access token
+const (
+	ScopeListsRead  = "lists:read"
+	ScopeListsWrite = "lists:write"
+	ScopeItemsRead  = "items:read"
+	ScopeItemsWrite = "items:write"
+)
+
+// Scopes lists every scope a personal access token can be granted
+var Scopes = []string{ScopeListsRead, ScopeListsWrite, ScopeItemsRead, ScopeItemsWrite}
+
+// PersonalAccessToken represents a named long-lived token minted by a user, only the hash of the token is stored
+type PersonalAccessToken struct {
+	Id         int        `json:"id"`
+	UserId     int        `json:"-"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope checks if the token was granted the given scope
+func (t PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreatedPersonalAccessToken is returned once on creation, it is the only time the raw token is available
+type CreatedPersonalAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// CreatePersonalAccessTokenInput represents the input for creating a personal access token
+type CreatePersonalAccessTokenInput struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Validate checks that every requested scope exists and the expiration is in the future
+func (i CreatePersonalAccessTokenInput) Validate() error {
+	for _, scope := range i.Scopes {
+		if !isKnownScope(scope) {
+			return utils.ErrInvalidScope
+		}
+	}
+
+	if i.ExpiresAt != nil && !i.ExpiresAt.After(time.Now()) {
+		return utils.ErrInvalidExpiration
+	}
+
+	return nil
+}
+
+// isKnownScope checks if the scope is one of the supported scopes
+func isKnownScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
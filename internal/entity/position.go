@@ -0,0 +1,23 @@
+package entity
+
+import "github.com/berikulyBeket/todo-plus/utils"
+
+// MoveInput represents the input for moving an item within its list or a list within the lists of the user.
+// AfterId is the row the moved row should follow and BeforeId the row it should precede, at least one is required
+type MoveInput struct {
+	AfterId  *int `json:"after_id"`
+	BeforeId *int `json:"before_id"`
+}
+
+// Validate checks that the input has an anchor and that the moved row is not one of its own anchors
+func (i MoveInput) Validate(id int) error {
+	if i.AfterId == nil && i.BeforeId == nil {
+		return utils.ErrMoveAnchorRequired
+	}
+
+	if (i.AfterId != nil && *i.AfterId == id) || (i.BeforeId != nil && *i.BeforeId == id) {
+		return utils.ErrMoveAnchorIsSelf
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/pkg/rrule"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// Scopes of an update of a recurring item. Updates of this occurrence only change the item, updates of
+// all future occurrences also change the series the next occurrences are generated from
+const (
+	ItemEditScopeThis   = "this"
+	ItemEditScopeFuture = "future"
+)
+
+// DefaultRecurrenceTimezone is used when a recurrence has no timezone
+const DefaultRecurrenceTimezone = "UTC"
+
+// Recurrence describes how an item repeats, Rule is an iCalendar RRULE and occurrences are computed
+// in the wall clock time of the IANA Timezone
+type Recurrence struct {
+	Rule     string `json:"rule"`
+	Timezone string `json:"timezone"`
+}
+
+// Validate checks that the rule is supported and the timezone is known, an empty timezone defaults to UTC
+func (r *Recurrence) Validate() error {
+	if _, err := rrule.Parse(r.Rule); err != nil {
+		return utils.ErrInvalidRecurrence
+	}
+
+	if r.Timezone == "" {
+		r.Timezone = DefaultRecurrenceTimezone
+	}
+
+	if _, err := time.LoadLocation(r.Timezone); err != nil {
+		return utils.ErrInvalidTimezone
+	}
+
+	return nil
+}
+
+// Parse returns the rule and the location of a validated recurrence
+func (r Recurrence) Parse() (rrule.Rule, *time.Location, error) {
+	rule, err := rrule.Parse(r.Rule)
+	if err != nil {
+		return rrule.Rule{}, nil, err
+	}
+
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return rrule.Rule{}, nil, err
+	}
+
+	return rule, loc, nil
+}
+
+// ItemSeries is the template the occurrences of a recurring item are generated from
+type ItemSeries struct {
+	Id          int
+	Recurrence  Recurrence
+	Title       string
+	Description string
+	StartsAt    time.Time
+}
+
+// Changes an update can make to the series of an item
+const (
+	ItemSeriesStart  = "start"
+	ItemSeriesUpdate = "update"
+	ItemSeriesStop   = "stop"
+)
+
+// ItemSeriesChange describes the change an update makes to the series of an item, it is applied in the transaction
+// of the update. Start makes First the first occurrence of a new series, Update applies the fields of Input to the
+// series SeriesId and Stop detaches the item from its series
+type ItemSeriesChange struct {
+	Action   string
+	First    Item
+	SeriesId int
+	Input    UpdateItemInput
+}
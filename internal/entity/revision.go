@@ -0,0 +1,126 @@
+package entity
+
+import "time"
+
+// ItemState holds the fields of an item tracked by its revisions. Reverting a revision restores the content of the
+// item, the list, parent and recurrence are only recorded so that moves and series changes show in the history
+type ItemState struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Done        bool        `json:"done"`
+	DueAt       *time.Time  `json:"due_at"`
+	RemindAt    *time.Time  `json:"remind_at"`
+	ListId      int         `json:"list_id"`
+	ParentId    *int        `json:"parent_id"`
+	Recurrence  *Recurrence `json:"recurrence"`
+}
+
+// ListState holds the fields of a list tracked by its revisions
+type ListState struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// FieldChange represents the value of a single field before and after a revision
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ItemRevision represents a recorded update of an item, Before and After hold the whole state of the item around
+// the update and Changes only the fields that differ. A user Id is missing once the user is deleted
+type ItemRevision struct {
+	Id        int           `json:"id"`
+	ItemId    int           `json:"item_id"`
+	UserId    *int          `json:"user_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Before    ItemState     `json:"before"`
+	After     ItemState     `json:"after"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// ListRevision represents a recorded update of a list, Before and After hold the whole state of the list around
+// the update and Changes only the fields that differ. A user Id is missing once the user is deleted
+type ListRevision struct {
+	Id        int           `json:"id"`
+	ListId    int           `json:"list_id"`
+	UserId    *int          `json:"user_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Before    ListState     `json:"before"`
+	After     ListState     `json:"after"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// Diff returns the fields of the item that differ in after
+func (s ItemState) Diff(after ItemState) []FieldChange {
+	changes := []FieldChange{}
+
+	if s.Title != after.Title {
+		changes = append(changes, FieldChange{"title", s.Title, after.Title})
+	}
+	if s.Description != after.Description {
+		changes = append(changes, FieldChange{"description", s.Description, after.Description})
+	}
+	if s.Done != after.Done {
+		changes = append(changes, FieldChange{"done", s.Done, after.Done})
+	}
+	if !sameTime(s.DueAt, after.DueAt) {
+		changes = append(changes, FieldChange{"due_at", s.DueAt, after.DueAt})
+	}
+	if !sameTime(s.RemindAt, after.RemindAt) {
+		changes = append(changes, FieldChange{"remind_at", s.RemindAt, after.RemindAt})
+	}
+	if s.ListId != after.ListId {
+		changes = append(changes, FieldChange{"list_id", s.ListId, after.ListId})
+	}
+	if !sameId(s.ParentId, after.ParentId) {
+		changes = append(changes, FieldChange{"parent_id", s.ParentId, after.ParentId})
+	}
+	if !sameRecurrence(s.Recurrence, after.Recurrence) {
+		changes = append(changes, FieldChange{"recurrence", s.Recurrence, after.Recurrence})
+	}
+
+	return changes
+}
+
+// Diff returns the fields of the list that differ in after
+func (s ListState) Diff(after ListState) []FieldChange {
+	changes := []FieldChange{}
+
+	if s.Title != after.Title {
+		changes = append(changes, FieldChange{"title", s.Title, after.Title})
+	}
+	if s.Description != after.Description {
+		changes = append(changes, FieldChange{"description", s.Description, after.Description})
+	}
+
+	return changes
+}
+
+// sameTime reports whether two optional times are both unset or the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// sameId reports whether two optional Ids are both unset or equal
+func sameId(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// sameRecurrence reports whether two optional recurrences are both unset or equal
+func sameRecurrence(a, b *Recurrence) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,61 @@
+package entity
+
+import "github.com/berikulyBeket/todo-plus/utils"
+
+// Roles a user can be assigned, every new user starts with RoleUser
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+// Roles lists every role a user can be assigned
+var Roles = []string{RoleUser, RoleSupport, RoleAdmin}
+
+// Permissions checked by the authorization middleware on admin routes
+const (
+	PermissionDeleteUsers = "users:delete"
+	PermissionManageRoles = "roles:manage"
+	PermissionDeleteLists = "lists:delete"
+	PermissionDeleteItems = "items:delete"
+	PermissionManageApps  = "apps:manage"
+)
+
+// rolePermissions maps a role to the permissions it grants, RoleUser has no admin permissions
+var rolePermissions = map[string][]string{
+	RoleSupport: {PermissionDeleteLists, PermissionDeleteItems},
+	RoleAdmin: {
+		PermissionDeleteUsers,
+		PermissionManageRoles,
+		PermissionDeleteLists,
+		PermissionDeleteItems,
+		PermissionManageApps,
+	},
+}
+
+// HasPermission checks if the role grants the given permission
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateUserRoleInput represents the input for assigning a role to a user
+type UpdateUserRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Validate checks that the role is one of the known roles
+func (i UpdateUserRoleInput) Validate() error {
+	for _, role := range Roles {
+		if role == i.Role {
+			return nil
+		}
+	}
+
+	return utils.ErrInvalidRole
+}
@@ -0,0 +1,38 @@
+package entity
+
+// ItemProgress summarizes how many of the subtasks of an item are done
+type ItemProgress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
+}
+
+// NewItemProgress computes the progress of an item from its subtasks, an item without subtasks has no progress
+func NewItemProgress(subtasks []Item) ItemProgress {
+	progress := ItemProgress{Total: len(subtasks)}
+
+	for _, subtask := range subtasks {
+		if subtask.Done {
+			progress.Done++
+		}
+	}
+
+	if progress.Total > 0 {
+		progress.Percent = progress.Done * 100 / progress.Total
+	}
+
+	return progress
+}
+
+// Subtasks represents the direct subtasks of an item together with the progress computed from them
+type Subtasks struct {
+	ParentId int          `json:"parent_id"`
+	Progress ItemProgress `json:"progress"`
+	Items    []Item       `json:"items"`
+}
+
+// SetItemParentInput represents the input for moving an item under another item of the same list,
+// a null parent makes the item a top-level item again
+type SetItemParentInput struct {
+	ParentId *int `json:"parent_id"`
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// MaxTagNameLength is the longest name a tag can have
+const MaxTagNameLength = 64
+
+// tagColorPattern matches hex colors such as #ff8800
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Tag represents a label a user can attach to items of any list shared with them, tags are private to their user
+type Tag struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"-"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateTagInput represents the input for creating a tag
+type CreateTagInput struct {
+	Name  string  `json:"name" binding:"required"`
+	Color *string `json:"color"`
+}
+
+// Validate trims the name and checks the name and color of the tag
+func (i *CreateTagInput) Validate() error {
+	i.Name = strings.TrimSpace(i.Name)
+
+	if err := validateTagName(i.Name); err != nil {
+		return err
+	}
+
+	return validateTagColor(i.Color)
+}
+
+// UpdateTagInput represents the input for updating a tag
+type UpdateTagInput struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// Validate checks if the update input has at least one valid field
+func (i *UpdateTagInput) Validate() error {
+	if i.Name == nil && i.Color == nil {
+		return utils.ErrTagEmptyRequest
+	}
+
+	if i.Name != nil {
+		name := strings.TrimSpace(*i.Name)
+		if err := validateTagName(name); err != nil {
+			return err
+		}
+		i.Name = &name
+	}
+
+	return validateTagColor(i.Color)
+}
+
+// validateTagName checks that a tag name is not empty, not too long and has no commas,
+// which separate tags in query parameters
+func validateTagName(name string) error {
+	if name == "" || len(name) > MaxTagNameLength || strings.Contains(name, ",") {
+		return utils.ErrInvalidTagName
+	}
+
+	return nil
+}
+
+// validateTagColor checks that an optional tag color is a hex color
+func validateTagColor(color *string) error {
+	if color != nil && !tagColorPattern.MatchString(*color) {
+		return utils.ErrInvalidTagColor
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// RefreshToken represents a persisted refresh token, only the hash of the token is stored
+type RefreshToken struct {
+	Id        int
+	UserId    int
+	FamilyId  string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// TokenPair represents an access token together with the refresh token used to renew it
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "github.com/berikulyBeket/todo-plus/utils"
+
+// MaxTransferItems bounds the number of items moved or copied to another list at once
+const MaxTransferItems = 100
+
+// TransferItemsInput represents the items to move or copy to another list
+type TransferItemsInput struct {
+	ItemIds []int `json:"item_ids" binding:"required"`
+}
+
+// Validate checks that between one and MaxTransferItems items are given
+func (i TransferItemsInput) Validate() error {
+	if len(i.ItemIds) == 0 {
+		return utils.ErrTransferItemsEmpty
+	}
+
+	if len(i.ItemIds) > MaxTransferItems {
+		return utils.ErrTooManyTransferItems
+	}
+
+	return nil
+}
+
+// MovedItems represents the items moved to a list, including the subtasks moved along with their parents
+type MovedItems struct {
+	ListId  int   `json:"list_id"`
+	ItemIds []int `json:"item_ids"`
+}
+
+// ItemCopy pairs an item with the copy made of it
+type ItemCopy struct {
+	SourceId int  `json:"source_id"`
+	Item     Item `json:"item"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// TrashedList represents a deleted list that its owners can still restore until it is purged
+type TrashedList struct {
+	List
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashedItem represents a deleted item that the editors of its list can still restore until it is purged
+type TrashedItem struct {
+	Item
+	ListId    int       `json:"list_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// PurgedTrash represents the lists and items removed for good from the trash,
+// the items of a purged list are purged along with it
+type PurgedTrash struct {
+	ListIds []int `json:"list_ids"`
+	ItemIds []int `json:"item_ids"`
+}
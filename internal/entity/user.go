@@ -6,4 +6,5 @@ type User struct {
 	Name     string `json:"name" binding:"required,min=2,max=50"`
 	Username string `json:"username" binding:"required,min=3,max=20"`
 	Password string `json:"password" binding:"required,min=8,max=100"`
+	Role     string `json:"-"`
 }
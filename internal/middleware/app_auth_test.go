@@ -1,17 +1,118 @@
 package middleware_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
+// fakeAppRepo is an in-memory app registry implementing repository.App
+type fakeAppRepo struct {
+	mu   sync.Mutex
+	apps map[string]entity.App
+}
+
+// newFakeAppRepo creates an empty in-memory app registry
+func newFakeAppRepo() *fakeAppRepo {
+	return &fakeAppRepo{apps: map[string]entity.App{}}
+}
+
+// Create registers an app
+func (r *fakeAppRepo) Create(ctx context.Context, app *entity.App) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app.Id = len(r.apps) + 1
+	r.apps[app.AppId] = *app
+	return app.Id, nil
+}
+
+// CreateIfNotExists registers an app unless its app id is taken
+func (r *fakeAppRepo) CreateIfNotExists(ctx context.Context, app *entity.App) error {
+	r.mu.Lock()
+	_, exists := r.apps[app.AppId]
+	r.mu.Unlock()
+
+	if exists {
+		return nil
+	}
+
+	_, err := r.Create(ctx, app)
+	return err
+}
+
+// GetAll returns every registered app
+func (r *fakeAppRepo) GetAll(ctx context.Context) ([]entity.App, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	apps := []entity.App{}
+	for _, app := range r.apps {
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// GetOneByAppId returns an app by its app id
+func (r *fakeAppRepo) GetOneByAppId(ctx context.Context, appId string) (entity.App, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[appId]
+	if !ok {
+		return entity.App{}, utils.ErrAppNotFound
+	}
+	return app, nil
+}
+
+// UpdateOneByAppId is not used by the middleware
+func (r *fakeAppRepo) UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error {
+	return nil
+}
+
+// RotateKey is not used by the middleware
+func (r *fakeAppRepo) RotateKey(ctx context.Context, appId, newKeyHash string, previousKeyExpiresAt time.Time) error {
+	return nil
+}
+
+// DeleteOneByAppId removes an app
+func (r *fakeAppRepo) DeleteOneByAppId(ctx context.Context, appId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.apps, appId)
+	return nil
+}
+
+// UpdateLastUsed records the usage of an app
+func (r *fakeAppRepo) UpdateLastUsed(ctx context.Context, appId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	app, ok := r.apps[appId]
+	if !ok {
+		return utils.ErrAppNotFound
+	}
+
+	now := time.Now()
+	app.LastUsedAt = &now
+	r.apps[appId] = app
+	return nil
+}
+
 // Helper function to create the test router
 func setupRouter(appAuth appauth.Interface, accessType string) *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -32,7 +133,9 @@ func TestAppAuth(t *testing.T) {
 	privateAppId := "privateAppId"
 	privateAppKey := "privateAppKey"
 
-	appAuthInstance := appauth.New(publicAppId, publicAppKey, privateAppId, privateAppKey)
+	var appAuthInstance usecase.App = usecase.NewAppUseCase(newFakeAppRepo(), time.Hour, &logger.NoOpLogger{})
+	assert.NoError(t, appAuthInstance.EnsureApp(context.Background(), publicAppId, publicAppKey, "public", []string{appauth.PublicAccess}))
+	assert.NoError(t, appAuthInstance.EnsureApp(context.Background(), privateAppId, privateAppKey, "private", []string{appauth.PrivateAccess}))
 
 	testCases := []struct {
 		name         string
@@ -66,6 +169,14 @@ func TestAppAuth(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: `{"message":"success"}`,
 		},
+		{
+			name:         "Public Credentials On Private Access",
+			accessType:   appauth.PrivateAccess,
+			appId:        publicAppId,
+			appKey:       publicAppKey,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"error":"Unauthorized: Invalid appId or appKey"}`,
+		},
 		{
 			name:         "Empty Credentials",
 			accessType:   appauth.PublicAccess,
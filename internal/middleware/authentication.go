@@ -7,6 +7,7 @@ import (
 
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,9 +16,14 @@ import (
 const (
 	AuthorizationHeader = "Authorization"
 	UserIdCtx           = "userId"
+	AccessTokenCtx      = "accessToken"
+	ScopesCtx           = "scopes"
+	RoleCtx             = "role"
 )
 
-// Authentication is a middleware function that handles authentication by parsing a JWT token from the Authorization header
+// Authentication is a middleware function that handles authentication by parsing a JWT token or
+// a personal access token from the Authorization header. JWT tokens store the user's role in the context,
+// personal access tokens store their scopes and never carry a role
 func Authentication(authUseCase usecase.Auth, logger logger.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		header := c.GetHeader(AuthorizationHeader)
@@ -38,7 +44,23 @@ func Authentication(authUseCase usecase.Auth, logger logger.Interface) gin.Handl
 			return
 		}
 
-		userId, err := authUseCase.ParseToken(c.Request.Context(), headerParts[1])
+		if token.IsPersonalAccessToken(headerParts[1]) {
+			userId, scopes, err := authUseCase.ParsePersonalAccessToken(c.Request.Context(), headerParts[1])
+			if err != nil {
+				logger.Error("invalid personal access token")
+				utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
+					"token": err.Error(),
+				})
+				return
+			}
+
+			c.Set(UserIdCtx, userId)
+			c.Set(ScopesCtx, scopes)
+			c.Next()
+			return
+		}
+
+		userId, role, err := authUseCase.ParseToken(c.Request.Context(), headerParts[1])
 		if err != nil {
 			logger.Error("invalid auth header")
 			utils.NewErrorResponse(c, http.StatusUnauthorized, "Unauthorized", map[string]string{
@@ -48,6 +70,8 @@ func Authentication(authUseCase usecase.Auth, logger logger.Interface) gin.Handl
 		}
 
 		c.Set(UserIdCtx, userId)
+		c.Set(RoleCtx, role)
+		c.Set(AccessTokenCtx, headerParts[1])
 		c.Next()
 	}
 }
@@ -66,3 +90,18 @@ func GetUserId(c *gin.Context) (int, error) {
 
 	return userIdInt, nil
 }
+
+// GetAccessToken retrieves the raw access token of the authenticated request from the context
+func GetAccessToken(c *gin.Context) (string, error) {
+	accessToken, ok := c.Get(AccessTokenCtx)
+	if !ok {
+		return "", errors.New("access token not found")
+	}
+
+	accessTokenStr, ok := accessToken.(string)
+	if !ok {
+		return "", errors.New("invalid access token")
+	}
+
+	return accessTokenStr, nil
+}
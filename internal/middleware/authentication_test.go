@@ -11,6 +11,8 @@ import (
 	"github.com/berikulyBeket/todo-plus/internal/middleware"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -28,22 +30,52 @@ func (m *mockAuthUseCase) CreateUser(ctx context.Context, user entity.User) (int
 	return args.Int(0), args.Error(1)
 }
 
+// EnsureAdmin mocks seeding the admin user
+func (m *mockAuthUseCase) EnsureAdmin(ctx context.Context, name, username, password string) error {
+	args := m.Called(ctx, name, username, password)
+	return args.Error(0)
+}
+
 // AuthenticateUser mocks the authentication of a user with username and password
 func (m *mockAuthUseCase) AuthenticateUser(ctx context.Context, username, password string) (entity.User, error) {
 	args := m.Called(ctx, username, password)
 	return args.Get(0).(entity.User), args.Error(1)
 }
 
-// GenerateToken mocks the generation of a JWT token for a user
-func (m *mockAuthUseCase) GenerateToken(ctx context.Context, userId int) (string, error) {
-	args := m.Called(ctx, userId)
-	return args.String(0), args.Error(1)
+// GenerateTokens mocks the generation of a token pair for a user
+func (m *mockAuthUseCase) GenerateTokens(ctx context.Context, userId int, role string) (entity.TokenPair, error) {
+	args := m.Called(ctx, userId, role)
+	return args.Get(0).(entity.TokenPair), args.Error(1)
+}
+
+// RefreshTokens mocks exchanging a refresh token for a new token pair
+func (m *mockAuthUseCase) RefreshTokens(ctx context.Context, refreshToken string) (entity.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(entity.TokenPair), args.Error(1)
+}
+
+// SignOut mocks revoking the tokens of a session
+func (m *mockAuthUseCase) SignOut(ctx context.Context, accessToken, refreshToken string) error {
+	args := m.Called(ctx, accessToken, refreshToken)
+	return args.Error(0)
+}
+
+// GetJWKS mocks returning the public signing keys
+func (m *mockAuthUseCase) GetJWKS() token.JWKS {
+	args := m.Called()
+	return args.Get(0).(token.JWKS)
 }
 
-// ParseToken mocks the parsing of a JWT token to extract the user ID
-func (m *mockAuthUseCase) ParseToken(ctx context.Context, token string) (int, error) {
+// ParseToken mocks the parsing of a JWT token to extract the user ID and role
+func (m *mockAuthUseCase) ParseToken(ctx context.Context, token string) (int, string, error) {
 	args := m.Called(ctx, token)
-	return args.Int(0), args.Error(1)
+	return args.Int(0), args.String(1), args.Error(2)
+}
+
+// ParsePersonalAccessToken mocks the parsing of a personal access token to extract the user ID and scopes
+func (m *mockAuthUseCase) ParsePersonalAccessToken(ctx context.Context, token string) (int, []string, error) {
+	args := m.Called(ctx, token)
+	return args.Int(0), args.Get(1).([]string), args.Error(2)
 }
 
 // setupAuthRouter sets up the router with the authentication middleware
@@ -59,6 +91,16 @@ func setupAuthRouter(authUseCase usecase.Auth, logger logger.Interface) *gin.Eng
 			return
 		}
 
+		if scopes, ok := c.Get(middleware.ScopesCtx); ok {
+			c.JSON(http.StatusOK, gin.H{"userId": userId, "scopes": scopes})
+			return
+		}
+
+		if role := c.GetString(middleware.RoleCtx); role != "" {
+			c.JSON(http.StatusOK, gin.H{"userId": userId, "role": role})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{"userId": userId})
 	})
 
@@ -82,9 +124,27 @@ func TestAuthentication(t *testing.T) {
 			parseTokenResp: 1,
 			parseTokenErr:  nil,
 			expectedCode:   http.StatusOK,
-			expectedBody:   `{"userId":1}`,
+			expectedBody:   `{"userId":1,"role":"admin"}`,
+			mockAuth: func(mockAuth *mockAuthUseCase) {
+				mockAuth.On("ParseToken", mock.Anything, "validToken").Return(1, "admin", nil)
+			},
+		},
+		{
+			name:         "Valid Personal Access Token",
+			authHeader:   "Bearer tdp_validToken",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"userId":1,"scopes":["lists:read"]}`,
+			mockAuth: func(mockAuth *mockAuthUseCase) {
+				mockAuth.On("ParsePersonalAccessToken", mock.Anything, "tdp_validToken").Return(1, []string{"lists:read"}, nil)
+			},
+		},
+		{
+			name:         "Revoked Personal Access Token",
+			authHeader:   "Bearer tdp_revokedToken",
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"errors":{"token":"token has been revoked"}, "message":"Unauthorized", "status":"error"}`,
 			mockAuth: func(mockAuth *mockAuthUseCase) {
-				mockAuth.On("ParseToken", mock.Anything, "validToken").Return(1, nil)
+				mockAuth.On("ParsePersonalAccessToken", mock.Anything, "tdp_revokedToken").Return(0, []string(nil), utils.ErrTokenRevoked)
 			},
 		},
 		{
@@ -109,7 +169,7 @@ func TestAuthentication(t *testing.T) {
 			expectedCode:   http.StatusUnauthorized,
 			expectedBody:   `{"errors":{"token":"invalid token"}, "message":"Unauthorized", "status":"error"}`,
 			mockAuth: func(mockAuth *mockAuthUseCase) {
-				mockAuth.On("ParseToken", mock.Anything, "invalidToken").Return(0, errors.New("invalid token"))
+				mockAuth.On("ParseToken", mock.Anything, "invalidToken").Return(0, "", errors.New("invalid token"))
 			},
 		},
 	}
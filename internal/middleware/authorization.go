@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorization is a middleware that requires the authenticated user's role to grant the permission.
+// It must run after Authentication, requests without a role such as personal access tokens are rejected.
+// Every authorized request is logged with the acting user so admin actions can be attributed
+func Authorization(permission string, logger logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString(RoleCtx)
+
+		if !entity.HasPermission(role, permission) {
+			logger.WithFields(map[string]interface{}{
+				"user_id":    c.GetInt(UserIdCtx),
+				"role":       role,
+				"permission": permission,
+				"path":       c.FullPath(),
+			}).Warn("user is missing a required permission")
+
+			utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+				"permission": "user is not allowed to " + permission,
+			})
+			return
+		}
+
+		c.Next()
+
+		logger.WithFields(map[string]interface{}{
+			"admin_id":   c.GetInt(UserIdCtx),
+			"role":       role,
+			"permission": permission,
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+		}).Info("admin action performed")
+	}
+}
@@ -0,0 +1,86 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAuthorizationRouter sets up the router with the authorization middleware for deleting users
+func setupAuthorizationRouter(role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIdCtx, 1)
+		if role != "" {
+			c.Set(middleware.RoleCtx, role)
+		}
+	})
+	router.Use(middleware.Authorization(entity.PermissionDeleteUsers, &logger.NoOpLogger{}))
+
+	router.DELETE("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+// TestAuthorization tests the Authorization middleware
+func TestAuthorization(t *testing.T) {
+	forbiddenBody := `{"errors":{"permission":"user is not allowed to users:delete"}, "message":"Forbidden", "status":"error"}`
+
+	testCases := []struct {
+		name         string
+		role         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Admin is allowed",
+			role:         entity.RoleAdmin,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Support lacks the permission",
+			role:         entity.RoleSupport,
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbiddenBody,
+		},
+		{
+			name:         "Regular user is rejected",
+			role:         entity.RoleUser,
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbiddenBody,
+		},
+		{
+			name:         "Request without a role is rejected",
+			role:         "",
+			expectedCode: http.StatusForbidden,
+			expectedBody: forbiddenBody,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := setupAuthorizationRouter(testCase.role)
+			req, _ := http.NewRequest(http.MethodDelete, "/users/2", nil)
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			if testCase.expectedBody != "" {
+				assert.JSONEq(t, testCase.expectedBody, resp.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Idempotency is a middleware that makes requests with an Idempotency-Key header safe to retry. The first request
+// with a key is handled and its response is stored, retries with the same key and body replay that response.
+// Reusing a key with a different request is rejected with 422 and a retry while the first request is still
+// being handled with 409. Server errors are not stored so that the request can be retried.
+// It has to run after the Authentication middleware since keys are scoped to the user
+func Idempotency(idempotencyUseCase usecase.Idempotency, logger logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userId, err := GetUserId(c)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid input", map[string]string{
+				"body": "Request body could not be read",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(c.Request, body)
+
+		response, err := idempotencyUseCase.Begin(c.Request.Context(), userId, key, fingerprint)
+		if err != nil {
+			switch err {
+			case utils.ErrInvalidIdempotencyKey:
+				utils.NewErrorResponse(c, http.StatusBadRequest, "Invalid Idempotency-Key", map[string]string{
+					"idempotency_key": err.Error(),
+				})
+			case utils.ErrIdempotencyKeyReused:
+				utils.NewErrorResponse(c, http.StatusUnprocessableEntity, "Idempotency-Key reused", map[string]string{
+					"idempotency_key": err.Error(),
+				})
+			case utils.ErrIdempotencyKeyInProgress:
+				utils.NewErrorResponse(c, http.StatusConflict, "Request in progress", map[string]string{
+					"idempotency_key": err.Error(),
+				})
+			default:
+				logger.Warn("idempotency is unavailable, handling the request without it: %v", err)
+				c.Next()
+			}
+			return
+		}
+
+		if response != nil {
+			c.Header(HeaderIdempotentReplayed, "true")
+			c.Data(response.StatusCode, response.ContentType, response.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// The client may be gone after a flaky request, the outcome is stored regardless so that its retry sees it
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := idempotencyUseCase.Abort(ctx, userId, key); err != nil {
+				logger.Errorf("failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		err = idempotencyUseCase.Complete(ctx, userId, key, fingerprint, entity.IdempotentResponse{
+			StatusCode:  recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			logger.Errorf("failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handlers
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write writes the data to the response and keeps a copy of it
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes the string to the response and keeps a copy of it
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
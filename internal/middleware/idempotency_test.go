@@ -0,0 +1,220 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockIdempotencyUseCase mocks the Idempotency use case
+type mockIdempotencyUseCase struct {
+	mock.Mock
+}
+
+// Begin mocks reserving an idempotency key for a request
+func (m *mockIdempotencyUseCase) Begin(ctx context.Context, userId int, key, fingerprint string) (*entity.IdempotentResponse, error) {
+	args := m.Called(ctx, userId, key, fingerprint)
+	response, _ := args.Get(0).(*entity.IdempotentResponse)
+	return response, args.Error(1)
+}
+
+// Complete mocks storing the response of a request
+func (m *mockIdempotencyUseCase) Complete(ctx context.Context, userId int, key, fingerprint string, response entity.IdempotentResponse) error {
+	args := m.Called(ctx, userId, key, fingerprint, response)
+	return args.Error(0)
+}
+
+// Abort mocks releasing an idempotency key
+func (m *mockIdempotencyUseCase) Abort(ctx context.Context, userId int, key string) error {
+	args := m.Called(ctx, userId, key)
+	return args.Error(0)
+}
+
+// setupIdempotencyRouter sets up the router with the idempotency middleware for an authenticated user,
+// the handler echoes the request body with the given status and counts its calls
+func setupIdempotencyRouter(idempotencyUseCase *mockIdempotencyUseCase, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIdCtx, 1)
+	})
+	router.Use(middleware.Idempotency(idempotencyUseCase, &logger.NoOpLogger{}))
+
+	router.POST("/lists", func(c *gin.Context) {
+		*calls++
+		var body map[string]interface{}
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(status, body)
+	})
+
+	return router
+}
+
+// TestIdempotency tests the Idempotency middleware
+func TestIdempotency(t *testing.T) {
+	storedResponse := &entity.IdempotentResponse{
+		StatusCode:  http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Body:        []byte(`{"id":7}`),
+	}
+
+	testCases := []struct {
+		name             string
+		key              string
+		handlerStatus    int
+		mockBehavior     func(m *mockIdempotencyUseCase)
+		expectedCode     int
+		expectedBody     string
+		expectedCalls    int
+		expectedReplayed string
+	}{
+		{
+			name:          "Request without key is handled",
+			key:           "",
+			handlerStatus: http.StatusCreated,
+			mockBehavior:  func(m *mockIdempotencyUseCase) {},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  `{"title":"Groceries"}`,
+			expectedCalls: 1,
+		},
+		{
+			name:          "First request is handled and stored",
+			key:           "key",
+			handlerStatus: http.StatusCreated,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(nil, nil)
+				m.On("Complete", mock.Anything, 1, "key", mock.Anything, entity.IdempotentResponse{
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json; charset=utf-8",
+					Body:        []byte(`{"title":"Groceries"}`),
+				}).Return(nil)
+			},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  `{"title":"Groceries"}`,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Retry replays the stored response",
+			key:           "key",
+			handlerStatus: http.StatusCreated,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(storedResponse, nil)
+			},
+			expectedCode:     http.StatusCreated,
+			expectedBody:     `{"id":7}`,
+			expectedCalls:    0,
+			expectedReplayed: "true",
+		},
+		{
+			name:          "Key reused with another body",
+			key:           "key",
+			handlerStatus: http.StatusCreated,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(nil, utils.ErrIdempotencyKeyReused)
+			},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedBody:  `{"status":"error","message":"Idempotency-Key reused","errors":{"idempotency_key":"Idempotency-Key was already used with a different request"}}`,
+			expectedCalls: 0,
+		},
+		{
+			name:          "Request still in progress",
+			key:           "key",
+			handlerStatus: http.StatusCreated,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(nil, utils.ErrIdempotencyKeyInProgress)
+			},
+			expectedCode:  http.StatusConflict,
+			expectedBody:  `{"status":"error","message":"Request in progress","errors":{"idempotency_key":"a request with this Idempotency-Key is still being processed"}}`,
+			expectedCalls: 0,
+		},
+		{
+			name:          "Server error releases the key",
+			key:           "key",
+			handlerStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(nil, nil)
+				m.On("Abort", mock.Anything, 1, "key").Return(nil)
+			},
+			expectedCode:  http.StatusInternalServerError,
+			expectedBody:  `{"title":"Groceries"}`,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Unavailable store handles the request",
+			key:           "key",
+			handlerStatus: http.StatusCreated,
+			mockBehavior: func(m *mockIdempotencyUseCase) {
+				m.On("Begin", mock.Anything, 1, "key", mock.Anything).Return(nil, errors.New("redis error"))
+			},
+			expectedCode:  http.StatusCreated,
+			expectedBody:  `{"title":"Groceries"}`,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyUseCase := new(mockIdempotencyUseCase)
+			testCase.mockBehavior(idempotencyUseCase)
+
+			calls := 0
+			router := setupIdempotencyRouter(idempotencyUseCase, testCase.handlerStatus, &calls)
+
+			req, _ := http.NewRequest(http.MethodPost, "/lists", bytes.NewBufferString(`{"title":"Groceries"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if testCase.key != "" {
+				req.Header.Set(middleware.HeaderIdempotencyKey, testCase.key)
+			}
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			assert.JSONEq(t, testCase.expectedBody, resp.Body.String())
+			assert.Equal(t, testCase.expectedCalls, calls)
+			assert.Equal(t, testCase.expectedReplayed, resp.Header().Get(middleware.HeaderIdempotentReplayed))
+			idempotencyUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+// TestIdempotencyFingerprint tests that the same body gives the same fingerprint and another body a different one
+func TestIdempotencyFingerprint(t *testing.T) {
+	idempotencyUseCase := new(mockIdempotencyUseCase)
+
+	var fingerprints []string
+	idempotencyUseCase.On("Begin", mock.Anything, 1, "key", mock.Anything).
+		Run(func(args mock.Arguments) {
+			fingerprints = append(fingerprints, args.String(3))
+		}).
+		Return(nil, utils.ErrIdempotencyKeyInProgress)
+
+	calls := 0
+	router := setupIdempotencyRouter(idempotencyUseCase, http.StatusCreated, &calls)
+
+	for _, body := range []string{`{"title":"Groceries"}`, `{"title":"Groceries"}`, `{"title":"Chores"}`} {
+		req, _ := http.NewRequest(http.MethodPost, "/lists", bytes.NewBufferString(body))
+		req.Header.Set(middleware.HeaderIdempotencyKey, "key")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Len(t, fingerprints, 3)
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope is a middleware that enforces personal access token scopes for a route group.
+// Safe methods require the "<resource>:read" scope and every other method "<resource>:write".
+// Requests authenticated with a JWT carry no scopes in the context and are not restricted
+func Scope(resource string, logger logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(ScopesCtx)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		scopes, _ := value.([]string)
+		required := requiredScope(resource, c.Request.Method)
+
+		for _, scope := range scopes {
+			if scope == required {
+				c.Next()
+				return
+			}
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"scope": required,
+			"path":  c.FullPath(),
+		}).Warn("personal access token is missing a required scope")
+
+		utils.NewErrorResponse(c, http.StatusForbidden, "Forbidden", map[string]string{
+			"scope": fmt.Sprintf("token is missing the %s scope", required),
+		})
+	}
+}
+
+// requiredScope maps the request method to the read or write scope of a resource
+func requiredScope(resource, method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return resource + ":read"
+	default:
+		return resource + ":write"
+	}
+}
@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/middleware"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupScopeRouter sets up the router with the scope middleware for the lists resource
+func setupScopeRouter(scopes []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if scopes != nil {
+			c.Set(middleware.ScopesCtx, scopes)
+		}
+	})
+	router.Use(middleware.Scope("lists", &logger.NoOpLogger{}))
+
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/lists", handler)
+	router.POST("/lists", handler)
+
+	return router
+}
+
+// TestScope tests the Scope middleware
+func TestScope(t *testing.T) {
+	testCases := []struct {
+		name         string
+		scopes       []string
+		method       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "Session token is not restricted",
+			scopes:       nil,
+			method:       http.MethodPost,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Read scope allows GET",
+			scopes:       []string{"lists:read"},
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Read scope rejects POST",
+			scopes:       []string{"lists:read"},
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"errors":{"scope":"token is missing the lists:write scope"}, "message":"Forbidden", "status":"error"}`,
+		},
+		{
+			name:         "Scope of another resource is rejected",
+			scopes:       []string{"items:read", "items:write"},
+			method:       http.MethodGet,
+			expectedCode: http.StatusForbidden,
+			expectedBody: `{"errors":{"scope":"token is missing the lists:read scope"}, "message":"Forbidden", "status":"error"}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := setupScopeRouter(testCase.scopes)
+			req, _ := http.NewRequest(testCase.method, "/lists", nil)
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			if testCase.expectedBody != "" {
+				assert.JSONEq(t, testCase.expectedBody, resp.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/lib/pq"
+)
+
+// AppRepo handles persistence of registered applications
+type AppRepo struct {
+	db     *database.Database
+	cache  *cache.Cache
+	logger logger.Interface
+}
+
+// appCacheEntry is the cached form of an app, key hashes are hidden from JSON on the entity
+type appCacheEntry struct {
+	entity.App
+	KeyHash         string  `json:"key_hash"`
+	PreviousKeyHash *string `json:"previous_key_hash"`
+}
+
+// NewAppRepo creates a new instance of AppRepo
+func NewAppRepo(db *database.Database, cache *cache.Cache, logger logger.Interface) *AppRepo {
+	return &AppRepo{db, cache, logger}
+}
+
+// Create registers a new app and fills in its Id and creation time
+func (r *AppRepo) Create(ctx context.Context, app *entity.App) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (app_id, name, key_hash, access_types, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`, AppsTable)
+
+	err := r.db.Querier.QueryRow(query, app.AppId, app.Name, app.KeyHash, pq.Array(app.AccessTypes), app.Enabled).
+		Scan(&app.Id, &app.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return app.Id, nil
+}
+
+// CreateIfNotExists registers an app unless an app with the same app id already exists
+func (r *AppRepo) CreateIfNotExists(ctx context.Context, app *entity.App) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (app_id, name, key_hash, access_types, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (app_id) DO NOTHING`, AppsTable)
+
+	_, err := r.db.Executer.Exec(query, app.AppId, app.Name, app.KeyHash, pq.Array(app.AccessTypes), app.Enabled)
+
+	return err
+}
+
+// GetAll retrieves every registered app
+func (r *AppRepo) GetAll(ctx context.Context) ([]entity.App, error) {
+	apps := []entity.App{}
+
+	query := fmt.Sprintf(`
+		SELECT id, app_id, name, key_hash, previous_key_hash, previous_key_expires_at, access_types, enabled, last_used_at, created_at
+		FROM %s
+		ORDER BY id`, AppsTable)
+
+	rows, err := r.db.Querier.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
+
+// GetOneByAppId retrieves an app by its public app id, it is read on every request so results are cached
+func (r *AppRepo) GetOneByAppId(ctx context.Context, appId string) (entity.App, error) {
+	var cached appCacheEntry
+
+	appCacheKey := fmt.Sprintf(cacheKeyAppByAppId.Pattern, appId)
+	if exists, err := r.cache.Replica.Get(ctx, appCacheKey, &cached); err != nil {
+		r.logger.Errorf("cache error for key %s: %v", appCacheKey, err)
+	} else if exists {
+		app := cached.App
+		app.KeyHash = cached.KeyHash
+		app.PreviousKeyHash = cached.PreviousKeyHash
+		return app, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, app_id, name, key_hash, previous_key_hash, previous_key_expires_at, access_types, enabled, last_used_at, created_at
+		FROM %s
+		WHERE app_id = $1`, AppsTable)
+
+	app, err := scanApp(r.db.Querier.QueryRow(query, appId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return app, utils.ErrAppNotFound
+		}
+
+		return app, err
+	}
+
+	cached = appCacheEntry{App: app, KeyHash: app.KeyHash, PreviousKeyHash: app.PreviousKeyHash}
+	if err := r.cache.Master.Set(ctx, appCacheKey, cached, cacheKeyAppByAppId.TTL); err != nil {
+		r.logger.Errorf("failed to set cache for key %s: %v", appCacheKey, err)
+	}
+
+	return app, nil
+}
+
+// UpdateOneByAppId updates an app by its app id based on the provided input
+func (r *AppRepo) UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error {
+	query := fmt.Sprintf("UPDATE %s SET ", AppsTable)
+	args := []interface{}{}
+
+	setClauses := []string{}
+	argIndex := 1
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *input.Name)
+		argIndex++
+	}
+	if input.AccessTypes != nil {
+		setClauses = append(setClauses, fmt.Sprintf("access_types = $%d", argIndex))
+		args = append(args, pq.Array(*input.AccessTypes))
+		argIndex++
+	}
+	if input.Enabled != nil {
+		setClauses = append(setClauses, fmt.Sprintf("enabled = $%d", argIndex))
+		args = append(args, *input.Enabled)
+		argIndex++
+	}
+
+	if len(setClauses) == 0 {
+		return utils.ErrAppEmptyRequest
+	}
+
+	query += strings.Join(setClauses, ", ")
+
+	query += fmt.Sprintf(" WHERE app_id = $%d", argIndex)
+
+	args = append(args, appId)
+
+	return r.execAndInvalidate(ctx, appId, query, args...)
+}
+
+// RotateKey replaces the key of an app, the current key becomes the previous key and stays valid until previousKeyExpiresAt
+func (r *AppRepo) RotateKey(ctx context.Context, appId, newKeyHash string, previousKeyExpiresAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET previous_key_hash = key_hash, previous_key_expires_at = $1, key_hash = $2
+		WHERE app_id = $3`, AppsTable)
+
+	return r.execAndInvalidate(ctx, appId, query, previousKeyExpiresAt, newKeyHash, appId)
+}
+
+// DeleteOneByAppId deletes an app by its app id
+func (r *AppRepo) DeleteOneByAppId(ctx context.Context, appId string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE app_id = $1", AppsTable)
+
+	return r.execAndInvalidate(ctx, appId, query, appId)
+}
+
+// UpdateLastUsed records the time the app was last used, writes are throttled to once a minute per app
+func (r *AppRepo) UpdateLastUsed(ctx context.Context, appId string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET last_used_at = NOW()
+		WHERE app_id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, AppsTable)
+
+	_, err := r.db.Executer.Exec(query, appId)
+
+	return err
+}
+
+// execAndInvalidate runs a statement affecting a single app and drops its cache entry so changes apply immediately
+func (r *AppRepo) execAndInvalidate(ctx context.Context, appId, query string, args ...interface{}) error {
+	result, err := r.db.Executer.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrAppNotFound
+	}
+
+	appCacheKey := fmt.Sprintf(cacheKeyAppByAppId.Pattern, appId)
+	if err := r.cache.Master.Delete(ctx, appCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", appCacheKey, err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanApp scans a single app row
+func scanApp(row rowScanner) (entity.App, error) {
+	var app entity.App
+
+	err := row.Scan(
+		&app.Id,
+		&app.AppId,
+		&app.Name,
+		&app.KeyHash,
+		&app.PreviousKeyHash,
+		&app.PreviousKeyExpiresAt,
+		pq.Array(&app.AccessTypes),
+		&app.Enabled,
+		&app.LastUsedAt,
+		&app.CreatedAt,
+	)
+
+	return app, err
+}
@@ -0,0 +1,186 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	queryGetAppByAppId = fmt.Sprintf("SELECT id, app_id, name, key_hash, previous_key_hash, previous_key_expires_at, access_types, enabled, last_used_at, created_at FROM %s WHERE app_id = \\$1", repository.AppsTable)
+	queryRotateAppKey  = fmt.Sprintf("UPDATE %s SET previous_key_hash = key_hash, previous_key_expires_at = \\$1, key_hash = \\$2 WHERE app_id = \\$3", repository.AppsTable)
+	queryDisableApp    = fmt.Sprintf("UPDATE %s SET enabled = \\$1 WHERE app_id = \\$2", repository.AppsTable)
+)
+
+// setupAppRepoTest initializes the database and repository for AppRepo tests
+func setupAppRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.AppRepo, *MockCache) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mockCache := new(MockCache)
+	appRepo := repository.NewAppRepo(database.New(sqlxDB), cache.New(mockCache, mockCache), &logger.NoOpLogger{})
+
+	return sqlxDB, mock, appRepo, mockCache
+}
+
+// TestGetAppByAppId tests retrieving an app by its app id
+func TestGetAppByAppId(t *testing.T) {
+	createdAt := time.Now()
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedApp entity.App
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetAppByAppId).
+					WithArgs("app").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "app_id", "name", "key_hash", "previous_key_hash", "previous_key_expires_at", "access_types", "enabled", "last_used_at", "created_at"}).
+						AddRow(1, "app", "web", "hash", nil, nil, "{public}", true, nil, createdAt))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, "app_by_app_id:app", mock.Anything).Return(false, nil)
+				mockCache.On("Set", mock.Anything, "app_by_app_id:app", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedApp: entity.App{
+				Id:          1,
+				AppId:       "app",
+				Name:        "web",
+				KeyHash:     "hash",
+				AccessTypes: []string{"public"},
+				Enabled:     true,
+				CreatedAt:   createdAt,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetAppByAppId).WithArgs("app").WillReturnError(sql.ErrNoRows)
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, "app_by_app_id:app", mock.Anything).Return(false, nil)
+			},
+			expectedApp: entity.App{},
+			expectedErr: utils.ErrAppNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, appRepo, mockCache := setupAppRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			app, err := appRepo.GetOneByAppId(context.Background(), "app")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedApp, app)
+
+			assertExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRotateAppKey tests replacing an app key while keeping the previous one
+func TestRotateAppKey(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRotateAppKey).
+					WithArgs(expiresAt, "newHash", "app").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "app_by_app_id:app").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRotateAppKey).
+					WithArgs(expiresAt, "newHash", "app").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrAppNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, appRepo, mockCache := setupAppRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			err := appRepo.RotateKey(context.Background(), "app", "newHash", expiresAt)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateAppByAppId tests disabling an app
+func TestUpdateAppByAppId(t *testing.T) {
+	sqlxDB, sqlMock, appRepo, mockCache := setupAppRepoTest(t)
+	defer sqlxDB.Close()
+
+	enabled := false
+
+	sqlMock.ExpectExec(queryDisableApp).
+		WithArgs(false, "app").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockCache.On("Delete", mock.Anything, "app_by_app_id:app").Return(nil)
+
+	err := appRepo.UpdateOneByAppId(context.Background(), "app", entity.UpdateAppInput{Enabled: &enabled})
+
+	assert.NoError(t, err)
+
+	assertExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
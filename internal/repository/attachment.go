@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// AttachmentRepo handles persistence of the attachments of items. When an attachment is deleted or its item is
+// purged the row is detached from the item instead, detached rows are removed once their blob is deleted
+type AttachmentRepo struct {
+	db *database.Database
+}
+
+// NewAttachmentRepo creates a new instance of AttachmentRepo
+func NewAttachmentRepo(db *database.Database) *AttachmentRepo {
+	return &AttachmentRepo{db}
+}
+
+// Create stores a new attachment and fills in its Id and creation time
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, user_id, name, content_type, size, blob_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`, AttachmentsTable)
+
+	err := r.db.Querier.QueryRow(
+		query,
+		attachment.ItemId,
+		attachment.UserId,
+		attachment.Name,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.BlobKey,
+	).Scan(&attachment.Id, &attachment.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return attachment.Id, nil
+}
+
+// GetAllByItemId retrieves the attachments of an item in upload order
+func (r *AttachmentRepo) GetAllByItemId(ctx context.Context, itemId int) ([]entity.Attachment, error) {
+	attachments := []entity.Attachment{}
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, user_id, name, content_type, size, blob_key, created_at
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY id`, AttachmentsTable)
+
+	rows, err := r.db.Querier.Query(query, itemId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attachment entity.Attachment
+		if err := scanAttachment(rows, &attachment); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetOneById retrieves an attachment of an item, ErrAttachmentNotFound is returned when it belongs to another item
+func (r *AttachmentRepo) GetOneById(ctx context.Context, itemId, attachmentId int) (entity.Attachment, error) {
+	var attachment entity.Attachment
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, user_id, name, content_type, size, blob_key, created_at
+		FROM %s
+		WHERE id = $1 AND item_id = $2`, AttachmentsTable)
+
+	if err := scanAttachment(r.db.Querier.QueryRow(query, attachmentId, itemId), &attachment); err != nil {
+		if err == sql.ErrNoRows {
+			return attachment, utils.ErrAttachmentNotFound
+		}
+
+		return attachment, err
+	}
+
+	return attachment, nil
+}
+
+// Detach takes an attachment off its item and returns its blob key, the attachment is no longer visible
+// and its row stays until DeleteDetached is called once the blob is deleted
+func (r *AttachmentRepo) Detach(ctx context.Context, itemId, attachmentId int) (string, error) {
+	query := fmt.Sprintf("UPDATE %s SET item_id = NULL WHERE id = $1 AND item_id = $2 RETURNING blob_key", AttachmentsTable)
+
+	var blobKey string
+
+	err := r.db.Querier.QueryRow(query, attachmentId, itemId).Scan(&blobKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", utils.ErrAttachmentNotFound
+		}
+
+		return "", err
+	}
+
+	return blobKey, nil
+}
+
+// GetDetached retrieves up to limit attachments that were deleted or whose item was purged, only their Id
+// and blob key are filled in
+func (r *AttachmentRepo) GetDetached(ctx context.Context, limit int) ([]entity.Attachment, error) {
+	attachments := []entity.Attachment{}
+
+	query := fmt.Sprintf("SELECT id, blob_key FROM %s WHERE item_id IS NULL ORDER BY id LIMIT $1", AttachmentsTable)
+
+	rows, err := r.db.Querier.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attachment entity.Attachment
+		if err := rows.Scan(&attachment.Id, &attachment.BlobKey); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// DeleteDetached removes the row of a detached attachment
+func (r *AttachmentRepo) DeleteDetached(ctx context.Context, attachmentId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND item_id IS NULL", AttachmentsTable)
+
+	_, err := r.db.Executer.Exec(query, attachmentId)
+	return err
+}
+
+// scanAttachment scans a row into an attachment
+func scanAttachment(row rowScanner, attachment *entity.Attachment) error {
+	return row.Scan(
+		&attachment.Id,
+		&attachment.ItemId,
+		&attachment.UserId,
+		&attachment.Name,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.BlobKey,
+		&attachment.CreatedAt,
+	)
+}
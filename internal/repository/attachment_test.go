@@ -0,0 +1,226 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	queryCreateAttachment       = fmt.Sprintf("INSERT INTO %s \\(item_id, user_id, name, content_type, size, blob_key\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) RETURNING id, created_at", repository.AttachmentsTable)
+	queryGetItemAttachments     = fmt.Sprintf("SELECT id, item_id, user_id, name, content_type, size, blob_key, created_at FROM %s WHERE item_id = \\$1 ORDER BY id", repository.AttachmentsTable)
+	queryGetAttachment          = fmt.Sprintf("SELECT id, item_id, user_id, name, content_type, size, blob_key, created_at FROM %s WHERE id = \\$1 AND item_id = \\$2", repository.AttachmentsTable)
+	queryDetachAttachment       = fmt.Sprintf("UPDATE %s SET item_id = NULL WHERE id = \\$1 AND item_id = \\$2 RETURNING blob_key", repository.AttachmentsTable)
+	queryGetDetachedAttachments = fmt.Sprintf("SELECT id, blob_key FROM %s WHERE item_id IS NULL ORDER BY id LIMIT \\$1", repository.AttachmentsTable)
+	queryDeleteDetachedAttach   = fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND item_id IS NULL", repository.AttachmentsTable)
+	attachmentColumns           = []string{"id", "item_id", "user_id", "name", "content_type", "size", "blob_key", "created_at"}
+)
+
+// setupAttachmentRepoTest initializes the database and repository for AttachmentRepo tests
+func setupAttachmentRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.AttachmentRepo) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	return sqlxDB, mock, repository.NewAttachmentRepo(database.New(sqlxDB))
+}
+
+// TestCreateAttachment tests storing a new attachment
+func TestCreateAttachment(t *testing.T) {
+	sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+	defer sqlxDB.Close()
+
+	userId := 1
+	createdAt := time.Date(2024, 11, 25, 9, 0, 0, 0, time.UTC)
+	attachment := entity.Attachment{
+		ItemId:      5,
+		UserId:      &userId,
+		Name:        "screenshot.png",
+		ContentType: "image/png",
+		Size:        2048,
+		BlobKey:     "items/5/abc",
+	}
+
+	mock.ExpectQuery(queryCreateAttachment).WithArgs(5, &userId, "screenshot.png", "image/png", int64(2048), "items/5/abc").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+
+	attachmentId, err := attachmentRepo.Create(context.Background(), &attachment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attachmentId)
+	assert.Equal(t, createdAt, attachment.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAllAttachmentsByItemId tests retrieving the attachments of an item
+func TestGetAllAttachmentsByItemId(t *testing.T) {
+	sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+	defer sqlxDB.Close()
+
+	createdAt := time.Date(2024, 11, 25, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(queryGetItemAttachments).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows(attachmentColumns).
+			AddRow(3, 5, 1, "screenshot.png", "image/png", 2048, "items/5/abc", createdAt).
+			AddRow(4, 5, nil, "spec.pdf", "application/pdf", 4096, "items/5/def", createdAt))
+
+	attachments, err := attachmentRepo.GetAllByItemId(context.Background(), 5)
+
+	userId := 1
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Attachment{
+		{Id: 3, ItemId: 5, UserId: &userId, Name: "screenshot.png", ContentType: "image/png", Size: 2048, BlobKey: "items/5/abc", CreatedAt: createdAt},
+		{Id: 4, ItemId: 5, Name: "spec.pdf", ContentType: "application/pdf", Size: 4096, BlobKey: "items/5/def", CreatedAt: createdAt},
+	}, attachments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetAttachmentById tests retrieving a single attachment of an item
+func TestGetAttachmentById(t *testing.T) {
+	createdAt := time.Date(2024, 11, 25, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedKey string
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetAttachment).WithArgs(3, 5).
+					WillReturnRows(sqlmock.NewRows(attachmentColumns).
+						AddRow(3, 5, 1, "screenshot.png", "image/png", 2048, "items/5/abc", createdAt))
+			},
+			expectedKey: "items/5/abc",
+		},
+		{
+			name: "Attachment of another item",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetAttachment).WithArgs(3, 5).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: utils.ErrAttachmentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			attachment, err := attachmentRepo.GetOneById(context.Background(), 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedKey, attachment.BlobKey)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDetachAttachment tests taking an attachment off its item
+func TestDetachAttachment(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedKey string
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryDetachAttachment).WithArgs(3, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"blob_key"}).AddRow("items/5/abc"))
+			},
+			expectedKey: "items/5/abc",
+		},
+		{
+			name: "Attachment not found",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryDetachAttachment).WithArgs(3, 5).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: utils.ErrAttachmentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			blobKey, err := attachmentRepo.Detach(context.Background(), 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedKey, blobKey)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetDetachedAttachments tests retrieving the attachments left without an item
+func TestGetDetachedAttachments(t *testing.T) {
+	sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetDetachedAttachments).WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "blob_key"}).AddRow(3, "items/5/abc").AddRow(8, "items/6/def"))
+
+	attachments, err := attachmentRepo.GetDetached(context.Background(), 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Attachment{{Id: 3, BlobKey: "items/5/abc"}, {Id: 8, BlobKey: "items/6/def"}}, attachments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteDetachedAttachment tests removing the row of a detached attachment
+func TestDeleteDetachedAttachment(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryDeleteDetachedAttach).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Database error",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryDeleteDetachedAttach).WithArgs(3).WillReturnError(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, attachmentRepo := setupAttachmentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := attachmentRepo.DeleteDetached(context.Background(), 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
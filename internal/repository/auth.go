@@ -20,13 +20,13 @@ func NewAuthRepo(db *database.Database) *AuthRepo {
 	return &AuthRepo{db}
 }
 
-// CreateUser inserts a new user into the database
+// CreateUser inserts a new user with the given role into the database
 func (r *AuthRepo) CreateUser(ctx context.Context, user entity.User) (int, error) {
 	var id int
 
-	query := fmt.Sprintf("INSERT INTO %s (name, username, password_hash) VALUES ($1, $2, $3) RETURNING id", UsersTable)
+	query := fmt.Sprintf("INSERT INTO %s (name, username, password_hash, role) VALUES ($1, $2, $3, $4) RETURNING id", UsersTable)
 
-	err := r.db.Querier.QueryRow(query, user.Name, user.Username, user.Password).Scan(&id)
+	err := r.db.Querier.QueryRow(query, user.Name, user.Username, user.Password, user.Role).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -34,16 +34,16 @@ func (r *AuthRepo) CreateUser(ctx context.Context, user entity.User) (int, error
 	return id, nil
 }
 
-// GetUser fetches the user by username and password
-func (r *AuthRepo) GetUserByCredentials(ctx context.Context, username, password string) (entity.User, error) {
+// GetUserByUsername fetches the user along with the stored password hash by username
+func (r *AuthRepo) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
 	var user entity.User
 
 	query := fmt.Sprintf(`
-		SELECT id
+		SELECT id, name, username, password_hash, role
 		FROM %s
-		WHERE username = $1 AND password_hash = $2`, UsersTable)
+		WHERE username = $1`, UsersTable)
 
-	err := r.db.Querier.QueryRow(query, username, password).Scan(&user.Id)
+	err := r.db.Querier.QueryRow(query, username).Scan(&user.Id, &user.Name, &user.Username, &user.Password, &user.Role)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return user, utils.ErrUserNotFound
@@ -54,3 +54,42 @@ func (r *AuthRepo) GetUserByCredentials(ctx context.Context, username, password
 
 	return user, nil
 }
+
+// GetUserRole fetches the current role of the user
+func (r *AuthRepo) GetUserRole(ctx context.Context, userId int) (string, error) {
+	var role string
+
+	query := fmt.Sprintf("SELECT role FROM %s WHERE id = $1", UsersTable)
+
+	err := r.db.Querier.QueryRow(query, userId).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", utils.ErrUserNotFound
+		}
+
+		return "", err
+	}
+
+	return role, nil
+}
+
+// UpdatePasswordHash replaces the stored password hash of the user
+func (r *AuthRepo) UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash = $1 WHERE id = $2", UsersTable)
+
+	result, err := r.db.Executer.Exec(query, passwordHash, userId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrUserNotFound
+	}
+
+	return nil
+}
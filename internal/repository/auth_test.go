@@ -17,8 +17,10 @@ import (
 )
 
 var (
-	createUserQuery = fmt.Sprintf(`INSERT INTO %s \(name, username, password_hash\) VALUES \(\$1, \$2, \$3\) RETURNING id`, repository.UsersTable)
-	getUserQuery    = fmt.Sprintf(`SELECT id FROM %s WHERE username = \$1 AND password_hash = \$2`, repository.UsersTable)
+	createUserQuery  = fmt.Sprintf(`INSERT INTO %s \(name, username, password_hash, role\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`, repository.UsersTable)
+	getUserQuery     = fmt.Sprintf(`SELECT id, name, username, password_hash, role FROM %s WHERE username = \$1`, repository.UsersTable)
+	getUserRoleQuery = fmt.Sprintf(`SELECT role FROM %s WHERE id = \$1`, repository.UsersTable)
+	updateHashQuery  = fmt.Sprintf(`UPDATE %s SET password_hash = \$1 WHERE id = \$2`, repository.UsersTable)
 )
 
 // Helper function to set up the mock database, sqlmock, and repository
@@ -52,10 +54,10 @@ func TestCreateUser(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword"},
+			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword", Role: entity.RoleUser},
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(createUserQuery).
-					WithArgs("JohnDoe", "johndoe", "hashedPassword").
+					WithArgs("JohnDoe", "johndoe", "hashedPassword", entity.RoleUser).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 			},
 			expectedId:  1,
@@ -63,10 +65,10 @@ func TestCreateUser(t *testing.T) {
 		},
 		{
 			name: "SQLConnectionFailure",
-			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword"},
+			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword", Role: entity.RoleUser},
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(createUserQuery).
-					WithArgs("JohnDoe", "johndoe", "hashedPassword").
+					WithArgs("JohnDoe", "johndoe", "hashedPassword", entity.RoleUser).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expectedId:  0,
@@ -74,10 +76,10 @@ func TestCreateUser(t *testing.T) {
 		},
 		{
 			name: "UsernameAlreadyExists",
-			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword"},
+			user: entity.User{Name: "JohnDoe", Username: "johndoe", Password: "hashedPassword", Role: entity.RoleUser},
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(createUserQuery).
-					WithArgs("JohnDoe", "johndoe", "hashedPassword").
+					WithArgs("JohnDoe", "johndoe", "hashedPassword", entity.RoleUser).
 					WillReturnError(sqlmock.ErrCancelled)
 			},
 			expectedId:  0,
@@ -88,7 +90,7 @@ func TestCreateUser(t *testing.T) {
 			user: entity.User{Name: "", Username: "", Password: ""},
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(createUserQuery).
-					WithArgs("", "", "").
+					WithArgs("", "", "", "").
 					WillReturnError(sql.ErrNoRows)
 			},
 			expectedId:  0,
@@ -117,50 +119,176 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
-// TestCreateUser tests the getting user
-func TestGetUser(t *testing.T) {
+// TestGetUserByUsername tests the getting user by username
+func TestGetUserByUsername(t *testing.T) {
 	testCases := []struct {
-		name        string
-		username    string
-		password    string
-		mockQuery   func(sqlmock.Sqlmock)
-		expectedId  int
-		expectedErr error
+		name         string
+		username     string
+		mockQuery    func(sqlmock.Sqlmock)
+		expectedUser entity.User
+		expectedErr  error
 	}{
 		{
 			name:     "Success",
 			username: "johndoe",
-			password: "hashedPassword",
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(getUserQuery).
-					WithArgs("johndoe", "hashedPassword").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WithArgs("johndoe").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "username", "password_hash", "role"}).AddRow(1, "John", "johndoe", "hashedPassword", "admin"))
 			},
-			expectedId:  1,
-			expectedErr: nil,
+			expectedUser: entity.User{Id: 1, Name: "John", Username: "johndoe", Password: "hashedPassword", Role: entity.RoleAdmin},
+			expectedErr:  nil,
 		},
 		{
 			name:     "UserNotFound",
 			username: "johndoe",
-			password: "wrongPassword",
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(getUserQuery).
-					WithArgs("johndoe", "wrongPassword").
+					WithArgs("johndoe").
 					WillReturnError(sql.ErrNoRows)
 			},
-			expectedId:  0,
-			expectedErr: utils.ErrUserNotFound,
+			expectedUser: entity.User{},
+			expectedErr:  utils.ErrUserNotFound,
 		},
 		{
 			name:     "SQLConnectionError",
 			username: "johndoe",
-			password: "hashedPassword",
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(getUserQuery).
-					WithArgs("johndoe", "hashedPassword").
+					WithArgs("johndoe").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedUser: entity.User{},
+			expectedErr:  sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, dbRepo := setupAuthRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			user, err := dbRepo.GetUserByUsername(context.Background(), testCase.username)
+
+			assert.Equal(t, testCase.expectedUser, user)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetUserRole tests fetching the current role of a user
+func TestGetUserRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userId       int
+		mockQuery    func(sqlmock.Sqlmock)
+		expectedRole string
+		expectedErr  error
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getUserRoleQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("support"))
+			},
+			expectedRole: entity.RoleSupport,
+			expectedErr:  nil,
+		},
+		{
+			name:   "UserNotFound",
+			userId: 2,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getUserRoleQuery).
+					WithArgs(2).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedRole: "",
+			expectedErr:  utils.ErrUserNotFound,
+		},
+		{
+			name:   "SQLConnectionError",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getUserRoleQuery).
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedRole: "",
+			expectedErr:  sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, dbRepo := setupAuthRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			role, err := dbRepo.GetUserRole(context.Background(), testCase.userId)
+
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestUpdatePasswordHash tests replacing the stored password hash
+func TestUpdatePasswordHash(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userId      int
+		hash        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			hash:   "newHash",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(updateHashQuery).
+					WithArgs("newHash", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "UserNotFound",
+			userId: 2,
+			hash:   "newHash",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(updateHashQuery).
+					WithArgs("newHash", 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrUserNotFound,
+		},
+		{
+			name:   "SQLConnectionError",
+			userId: 1,
+			hash:   "newHash",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(updateHashQuery).
+					WithArgs("newHash", 1).
 					WillReturnError(sql.ErrConnDone)
 			},
-			expectedId:  0,
 			expectedErr: sql.ErrConnDone,
 		},
 	}
@@ -176,9 +304,8 @@ func TestGetUser(t *testing.T) {
 
 			testCase.mockQuery(mock)
 
-			user, err := dbRepo.GetUserByCredentials(context.Background(), testCase.username, testCase.password)
+			err := dbRepo.UpdatePasswordHash(context.Background(), testCase.userId, testCase.hash)
 
-			assert.Equal(t, testCase.expectedId, user.Id)
 			assert.Equal(t, testCase.expectedErr, err)
 
 			assertExpectations(t, mock)
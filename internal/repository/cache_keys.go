@@ -4,10 +4,23 @@ import "time"
 
 // Cache key patterns for various entities
 const (
-	patternListById  = "list_by_id:%d"
-	patternUserLists = "user_lists:%d"
-	patternItemById  = "item_by_id:%d"
-	patternListItems = "list_items:%d"
+	patternListById   = "list_by_id:%d"
+	patternUserLists  = "user_lists:%d"
+	patternItemById   = "item_by_id:%d"
+	patternListItems  = "list_items:%d"
+	patternAppByAppId = "app_by_app_id:%s"
+
+	// Pages of collections are stored under the generation kept at the key of the collection, by the Id of the owner
+	// of the collection, the generation and the query of the page
+	patternListItemsPage = "list_items:%d:%d:%s"
+	patternUserListsPage = "user_lists:%d:%d:%s"
+
+	// patternRevokedToken stores revoked access token ids, entries live for the remaining token lifetime
+	patternRevokedToken = "revoked_token:%s"
+
+	// patternIdempotencyKey stores idempotent requests by the Id of the user and the Idempotency-Key,
+	// entries live for the configured idempotency window
+	patternIdempotencyKey = "idempotency_key:%d:%s"
 )
 
 // cacheKey represents a cache key with a pattern and TTL (time to live)
@@ -34,4 +47,16 @@ var (
 		Pattern: patternListItems,
 		TTL:     time.Minute * 5,
 	}
+	cacheKeyListItemsPage = cacheKey{
+		Pattern: patternListItemsPage,
+		TTL:     time.Minute * 5,
+	}
+	cacheKeyUserListsPage = cacheKey{
+		Pattern: patternUserListsPage,
+		TTL:     time.Minute * 5,
+	}
+	cacheKeyAppByAppId = cacheKey{
+		Pattern: patternAppByAppId,
+		TTL:     time.Minute * 5,
+	}
 )
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// commentColumns selects a comment with its author and the number of its replies,
+// the comments table is aliased as c and the users table as u
+var commentColumns = fmt.Sprintf(`
+	c.id, c.item_id, c.parent_id, u.id, u.name, u.username, c.body,
+	(SELECT COUNT(*) FROM %s r WHERE r.parent_id = c.id), c.created_at, c.updated_at`, CommentsTable)
+
+// CommentRepo handles persistence of the comments of items
+type CommentRepo struct {
+	db *database.Database
+}
+
+// NewCommentRepo creates a new instance of CommentRepo
+func NewCommentRepo(db *database.Database) *CommentRepo {
+	return &CommentRepo{db}
+}
+
+// Create stores a new comment, fills in its Id and creation time and records a created event with the comment and its author.
+// ErrCommentParentNotFound is returned when the parent is not a comment of the same item
+func (r *CommentRepo) Create(ctx context.Context, comment *entity.Comment) (int, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, parent_id, user_id, body)
+		SELECT $1, $2, $3, $4
+		WHERE $2::int IS NULL OR EXISTS (SELECT 1 FROM %s WHERE id = $2 AND item_id = $1)
+		RETURNING id, created_at`, CommentsTable, CommentsTable)
+
+	err = tx.QueryRow(query, comment.ItemId, comment.ParentId, comment.Author.Id, comment.Body).
+		Scan(&comment.Id, &comment.CreatedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return 0, utils.ErrCommentParentNotFound
+		}
+
+		return 0, err
+	}
+
+	query = fmt.Sprintf(`
+		SELECT %s
+		FROM %s c
+		JOIN %s u ON u.id = c.user_id
+		WHERE c.id = $1`, commentColumns, CommentsTable, UsersTable)
+
+	var created entity.Comment
+	if err := scanComment(tx.QueryRow(query, comment.Id), &created); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := insertCommentCreatedEvent(tx, comment.Author.Id, created); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return comment.Id, nil
+}
+
+// GetOneById retrieves a comment of an item with its author, ErrCommentNotFound is returned
+// when it belongs to another item
+func (r *CommentRepo) GetOneById(ctx context.Context, itemId, commentId int) (entity.Comment, error) {
+	var comment entity.Comment
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s c
+		JOIN %s u ON u.id = c.user_id
+		WHERE c.id = $1 AND c.item_id = $2`, commentColumns, CommentsTable, UsersTable)
+
+	if err := scanComment(r.db.Querier.QueryRow(query, commentId, itemId), &comment); err != nil {
+		if err == sql.ErrNoRows {
+			return comment, utils.ErrCommentNotFound
+		}
+
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// GetPage retrieves a page of the comments of an item from the oldest, either the top level comments
+// or the replies to the parent of the query. A row past the limit is read to tell whether a next page exists
+func (r *CommentRepo) GetPage(ctx context.Context, itemId int, commentQuery entity.CommentQuery) (entity.CommentPage, error) {
+	page := entity.CommentPage{Comments: []entity.Comment{}}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s c
+		JOIN %s u ON u.id = c.user_id
+		WHERE c.item_id = $1 AND c.parent_id IS NOT DISTINCT FROM $2 AND c.id > $3
+		ORDER BY c.id
+		LIMIT $4`, commentColumns, CommentsTable, UsersTable)
+
+	rows, err := r.db.Querier.Query(query, itemId, commentQuery.ParentId, commentQuery.After, commentQuery.Limit+1)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment entity.Comment
+		if err := scanComment(rows, &comment); err != nil {
+			return page, err
+		}
+		page.Comments = append(page.Comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	if len(page.Comments) > commentQuery.Limit {
+		page.Comments = page.Comments[:commentQuery.Limit]
+		nextCursor := page.Comments[commentQuery.Limit-1].Id
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
+// UpdateBody replaces the body of a comment of an item and marks it as edited
+func (r *CommentRepo) UpdateBody(ctx context.Context, itemId, commentId int, body string) error {
+	query := fmt.Sprintf("UPDATE %s SET body = $1, updated_at = NOW() WHERE id = $2 AND item_id = $3", CommentsTable)
+
+	result, err := r.db.Executer.Exec(query, body, commentId, itemId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// DeleteOneById deletes a comment of an item, its replies are deleted with it
+func (r *CommentRepo) DeleteOneById(ctx context.Context, itemId, commentId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND item_id = $2", CommentsTable)
+
+	result, err := r.db.Executer.Exec(query, commentId, itemId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// scanComment scans a row selected with commentColumns into a comment
+func scanComment(row rowScanner, comment *entity.Comment) error {
+	return row.Scan(
+		&comment.Id,
+		&comment.ItemId,
+		&comment.ParentId,
+		&comment.Author.Id,
+		&comment.Author.Name,
+		&comment.Author.Username,
+		&comment.Body,
+		&comment.ReplyCount,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+}
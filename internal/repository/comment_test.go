@@ -0,0 +1,302 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	queryCreateComment    = fmt.Sprintf("INSERT INTO %s \\(item_id, parent_id, user_id, body\\) SELECT \\$1, \\$2, \\$3, \\$4 WHERE \\$2::int IS NULL OR EXISTS", repository.CommentsTable)
+	queryGetComment       = fmt.Sprintf("FROM %s c JOIN %s u ON u.id = c.user_id WHERE c.id = \\$1 AND c.item_id = \\$2", repository.CommentsTable, repository.UsersTable)
+	queryGetNewComment    = fmt.Sprintf("FROM %s c JOIN %s u ON u.id = c.user_id WHERE c.id = \\$1$", repository.CommentsTable, repository.UsersTable)
+	queryGetCommentPage   = fmt.Sprintf("FROM %s c JOIN %s u ON u.id = c.user_id WHERE c.item_id = \\$1 AND c.parent_id IS NOT DISTINCT FROM \\$2 AND c.id > \\$3 ORDER BY c.id LIMIT \\$4", repository.CommentsTable, repository.UsersTable)
+	queryUpdateComment    = fmt.Sprintf("UPDATE %s SET body = \\$1, updated_at = NOW\\(\\) WHERE id = \\$2 AND item_id = \\$3", repository.CommentsTable)
+	queryDeleteComment    = fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND item_id = \\$2", repository.CommentsTable)
+	commentColumnsForTest = []string{"id", "item_id", "parent_id", "user_id", "name", "username", "body", "reply_count", "created_at", "updated_at"}
+)
+
+// setupCommentRepoTest initializes the database and repository for CommentRepo tests
+func setupCommentRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.CommentRepo) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	return sqlxDB, mock, repository.NewCommentRepo(database.New(sqlxDB))
+}
+
+// TestCreateComment tests creating comments and replies
+func TestCreateComment(t *testing.T) {
+	parentId := 3
+	createdAt := time.Date(2024, 11, 23, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		parentId    *int
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedId  int
+		expectedErr error
+	}{
+		{
+			name: "Top level comment",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryCreateComment).WithArgs(5, nil, 1, "Done?").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(9, createdAt))
+				mock.ExpectQuery(queryGetNewComment).WithArgs(9).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest).AddRow(9, 5, nil, 1, "Ann", "ann", "Done?", 0, createdAt, nil))
+				expectOutboxEvents(mock, messagebroker.CommentCreatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			expectedId: 9,
+		},
+		{
+			name:     "Reply",
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryCreateComment).WithArgs(5, &parentId, 1, "Done?").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, createdAt))
+				mock.ExpectQuery(queryGetNewComment).WithArgs(10).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest).AddRow(10, 5, parentId, 1, "Ann", "ann", "Done?", 0, createdAt, nil))
+				expectOutboxEvents(mock, messagebroker.CommentCreatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			expectedId: 10,
+		},
+		{
+			name:     "Parent of another item",
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryCreateComment).WithArgs(5, &parentId, 1, "Done?").WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: utils.ErrCommentParentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, commentRepo := setupCommentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			comment := entity.Comment{ItemId: 5, ParentId: testCase.parentId, Author: entity.CommentAuthor{Id: 1}, Body: "Done?"}
+			commentId, err := commentRepo.Create(context.Background(), &comment)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedId, commentId)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, createdAt, comment.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetCommentById tests retrieving a comment of an item with its author
+func TestGetCommentById(t *testing.T) {
+	createdAt := time.Date(2024, 11, 23, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		mockQuery       func(sqlmock.Sqlmock)
+		expectedComment entity.Comment
+		expectedErr     error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetComment).WithArgs(9, 5).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest).AddRow(9, 5, nil, 1, "Ann", "ann", "Done?", 2, createdAt, nil))
+			},
+			expectedComment: entity.Comment{
+				Id:         9,
+				ItemId:     5,
+				Author:     entity.CommentAuthor{Id: 1, Name: "Ann", Username: "ann"},
+				Body:       "Done?",
+				ReplyCount: 2,
+				CreatedAt:  createdAt,
+			},
+		},
+		{
+			name: "Comment of another item",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetComment).WithArgs(9, 5).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: utils.ErrCommentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, commentRepo := setupCommentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			comment, err := commentRepo.GetOneById(context.Background(), 5, 9)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedComment, comment)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetCommentPage tests paging through the comments of an item
+func TestGetCommentPage(t *testing.T) {
+	createdAt := time.Date(2024, 11, 23, 9, 0, 0, 0, time.UTC)
+	parentId := 3
+
+	testCases := []struct {
+		name           string
+		query          entity.CommentQuery
+		mockQuery      func(sqlmock.Sqlmock)
+		expectedIds    []int
+		expectedCursor *int
+	}{
+		{
+			name:  "Page with a next page",
+			query: entity.CommentQuery{After: 1, Limit: 2},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetCommentPage).WithArgs(5, nil, 1, 3).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest).
+						AddRow(2, 5, nil, 1, "Ann", "ann", "First", 0, createdAt, nil).
+						AddRow(4, 5, nil, 2, "Bob", "bob", "Second", 1, createdAt, nil).
+						AddRow(6, 5, nil, 1, "Ann", "ann", "Third", 0, createdAt, nil))
+			},
+			expectedIds:    []int{2, 4},
+			expectedCursor: func() *int { cursor := 4; return &cursor }(),
+		},
+		{
+			name:  "Last page of replies",
+			query: entity.CommentQuery{ParentId: &parentId, Limit: 2},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetCommentPage).WithArgs(5, &parentId, 0, 3).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest).
+						AddRow(7, 5, 3, 2, "Bob", "bob", "Reply", 0, createdAt, createdAt))
+			},
+			expectedIds: []int{7},
+		},
+		{
+			name:  "No comments",
+			query: entity.CommentQuery{Limit: 20},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetCommentPage).WithArgs(5, nil, 0, 21).
+					WillReturnRows(sqlmock.NewRows(commentColumnsForTest))
+			},
+			expectedIds: []int{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, commentRepo := setupCommentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			page, err := commentRepo.GetPage(context.Background(), 5, testCase.query)
+
+			ids := []int{}
+			for _, comment := range page.Comments {
+				ids = append(ids, comment.Id)
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedIds, ids)
+			assert.Equal(t, testCase.expectedCursor, page.NextCursor)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestUpdateCommentBody tests editing the body of a comment
+func TestUpdateCommentBody(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateComment).WithArgs("Edited", 9, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Comment not found",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateComment).WithArgs("Edited", 9, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrCommentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, commentRepo := setupCommentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := commentRepo.UpdateBody(context.Background(), 5, 9, "Edited")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestDeleteCommentById tests deleting a comment of an item
+func TestDeleteCommentById(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryDeleteComment).WithArgs(9, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Comment not found",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryDeleteComment).WithArgs(9, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrCommentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, commentRepo := setupCommentRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := commentRepo.DeleteOneById(context.Background(), 5, 9)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
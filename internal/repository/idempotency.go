@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+)
+
+// IdempotencyRepo handles idempotent requests stored in the cache. The master is always used so that
+// a retry sees the request it repeats regardless of replication lag
+type IdempotencyRepo struct {
+	cache *cache.Cache
+}
+
+// NewIdempotencyRepo creates a new instance of IdempotencyRepo
+func NewIdempotencyRepo(cache *cache.Cache) *IdempotencyRepo {
+	return &IdempotencyRepo{cache}
+}
+
+// Reserve stores the request under the key of the user unless the key is already taken and reports whether it did
+func (r *IdempotencyRepo) Reserve(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) (bool, error) {
+	idempotencyCacheKey := fmt.Sprintf(patternIdempotencyKey, userId, key)
+
+	return r.cache.Master.SetNX(ctx, idempotencyCacheKey, request, ttl)
+}
+
+// Get retrieves the request stored under the key of the user and reports whether there is one
+func (r *IdempotencyRepo) Get(ctx context.Context, userId int, key string) (entity.IdempotentRequest, bool, error) {
+	idempotencyCacheKey := fmt.Sprintf(patternIdempotencyKey, userId, key)
+
+	var request entity.IdempotentRequest
+	found, err := r.cache.Master.Get(ctx, idempotencyCacheKey, &request)
+
+	return request, found, err
+}
+
+// Save replaces the request stored under the key of the user, it is used to store the response once it is known
+func (r *IdempotencyRepo) Save(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) error {
+	idempotencyCacheKey := fmt.Sprintf(patternIdempotencyKey, userId, key)
+
+	return r.cache.Master.Set(ctx, idempotencyCacheKey, request, ttl)
+}
+
+// Release removes the request stored under the key of the user so that the key can be used again
+func (r *IdempotencyRepo) Release(ctx context.Context, userId int, key string) error {
+	idempotencyCacheKey := fmt.Sprintf(patternIdempotencyKey, userId, key)
+
+	return r.cache.Master.Delete(ctx, idempotencyCacheKey)
+}
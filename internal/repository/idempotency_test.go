@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupIdempotencyRepoTest initializes the cache and repository for IdempotencyRepo tests
+func setupIdempotencyRepoTest() (*repository.IdempotencyRepo, *MockCache) {
+	mockCache := new(MockCache)
+	caches := cache.New(mockCache, mockCache)
+
+	return repository.NewIdempotencyRepo(caches), mockCache
+}
+
+// TestReserveIdempotencyKey tests reserving an idempotency key of a user
+func TestReserveIdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		name             string
+		reserved         bool
+		cacheErr         error
+		expectedReserved bool
+		expectedErr      error
+	}{
+		{
+			name:             "Free key is reserved",
+			reserved:         true,
+			expectedReserved: true,
+		},
+		{
+			name:             "Taken key is not reserved",
+			reserved:         false,
+			expectedReserved: false,
+		},
+		{
+			name:        "Cache failure",
+			cacheErr:    errors.New("redis error"),
+			expectedErr: errors.New("redis error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			idempotencyRepo, mockCache := setupIdempotencyRepoTest()
+
+			request := entity.IdempotentRequest{Fingerprint: "fp"}
+			mockCache.On("SetNX", mock.Anything, "idempotency_key:1:key", request, time.Minute).Return(testCase.reserved, testCase.cacheErr)
+
+			reserved, err := idempotencyRepo.Reserve(context.Background(), 1, "key", request, time.Minute)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedReserved, reserved)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetIdempotentRequest tests retrieving the request stored under an idempotency key
+func TestGetIdempotentRequest(t *testing.T) {
+	idempotencyRepo, mockCache := setupIdempotencyRepoTest()
+
+	mockCache.On("Get", mock.Anything, "idempotency_key:1:key", mock.Anything).
+		Run(func(args mock.Arguments) {
+			request := args.Get(2).(*entity.IdempotentRequest)
+			request.Fingerprint = "fp"
+			request.Response = &entity.IdempotentResponse{StatusCode: 201}
+		}).
+		Return(true, nil)
+
+	request, found, err := idempotencyRepo.Get(context.Background(), 1, "key")
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, entity.IdempotentRequest{Fingerprint: "fp", Response: &entity.IdempotentResponse{StatusCode: 201}}, request)
+	mockCache.AssertExpectations(t)
+}
+
+// TestSaveAndReleaseIdempotencyKey tests storing the response under an idempotency key and releasing the key
+func TestSaveAndReleaseIdempotencyKey(t *testing.T) {
+	idempotencyRepo, mockCache := setupIdempotencyRepoTest()
+
+	request := entity.IdempotentRequest{Fingerprint: "fp", Response: &entity.IdempotentResponse{StatusCode: 201}}
+	mockCache.On("Set", mock.Anything, "idempotency_key:1:key", request, 24*time.Hour).Return(nil)
+	mockCache.On("Delete", mock.Anything, "idempotency_key:1:other").Return(nil)
+
+	assert.NoError(t, idempotencyRepo.Save(context.Background(), 1, "key", request, 24*time.Hour))
+	assert.NoError(t, idempotencyRepo.Release(context.Background(), 1, "other"))
+	mockCache.AssertExpectations(t)
+}
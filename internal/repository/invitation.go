@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// invitationColumns selects an invitation with the title of its list, pending invitations past
+// their expiration are reported with the expired status
+const invitationColumns = `
+	i.id, i.list_id, l.title, i.inviter_id, i.invitee_id, i.role,
+	CASE WHEN i.status = 'pending' AND i.expires_at <= NOW() THEN 'expired' ELSE i.status END,
+	i.token_hash, i.expires_at, i.created_at`
+
+// InvitationRepo handles persistence of list invitations
+type InvitationRepo struct {
+	db     *database.Database
+	cache  *cache.Cache
+	logger logger.Interface
+}
+
+// NewInvitationRepo creates a new instance of InvitationRepo
+func NewInvitationRepo(db *database.Database, cache *cache.Cache, logger logger.Interface) *InvitationRepo {
+	return &InvitationRepo{db, cache, logger}
+}
+
+// Create stores a new pending invitation and fills in its Id, status and creation time
+func (r *InvitationRepo) Create(ctx context.Context, invitation *entity.Invitation) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (list_id, inviter_id, invitee_id, role, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at`, ListInvitationsTable)
+
+	err := r.db.Querier.QueryRow(
+		query,
+		invitation.ListId,
+		invitation.InviterId,
+		invitation.InviteeId,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.ExpiresAt,
+	).Scan(&invitation.Id, &invitation.Status, &invitation.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return invitation.Id, nil
+}
+
+// GetOneById retrieves an invitation by its Id
+func (r *InvitationRepo) GetOneById(ctx context.Context, invitationId int) (entity.Invitation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s l ON l.id = i.list_id
+		WHERE i.id = $1`, invitationColumns, ListInvitationsTable, ListsTable)
+
+	return r.getOne(query, invitationId)
+}
+
+// GetByTokenHash retrieves a link invitation by the hash of its token
+func (r *InvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s l ON l.id = i.list_id
+		WHERE i.token_hash = $1`, invitationColumns, ListInvitationsTable, ListsTable)
+
+	return r.getOne(query, tokenHash)
+}
+
+// GetPendingByListId retrieves the pending invitations of a list that have not expired
+func (r *InvitationRepo) GetPendingByListId(ctx context.Context, listId int) ([]entity.Invitation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s l ON l.id = i.list_id
+		WHERE i.list_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+		ORDER BY i.id`, invitationColumns, ListInvitationsTable, ListsTable)
+
+	return r.getMany(query, listId)
+}
+
+// GetPendingByInviteeId retrieves the pending invitations addressed to a user that have not expired
+func (r *InvitationRepo) GetPendingByInviteeId(ctx context.Context, userId int) ([]entity.Invitation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s l ON l.id = i.list_id
+		WHERE i.invitee_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+		ORDER BY i.id`, invitationColumns, ListInvitationsTable, ListsTable)
+
+	return r.getMany(query, userId)
+}
+
+// Accept marks a pending invitation as accepted by the user and shares the list with them
+func (r *InvitationRepo) Accept(ctx context.Context, invitationId, userId int) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = 'accepted', invitee_id = $1, responded_at = NOW()
+		WHERE id = $2 AND status = 'pending' AND expires_at > NOW()
+		RETURNING list_id, role`, ListInvitationsTable)
+
+	var listId int
+	var role string
+	err = tx.QueryRow(query, userId, invitationId).Scan(&listId, &role)
+	if err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return utils.ErrInvitationNotPending
+		}
+
+		return err
+	}
+
+	position, err := userListsOrder.appendPosition(tx, userId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (user_id, list_id, role, position)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, list_id) DO NOTHING`, UsersListsTable)
+
+	result, err := tx.Exec(query, userId, listId, role, position)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return utils.ErrCollaboratorExists
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, userId)
+	if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
+	}
+
+	return nil
+}
+
+// UpdateStatus moves a pending invitation to a final status, used to decline and revoke invitations
+func (r *InvitationRepo) UpdateStatus(ctx context.Context, invitationId int, status string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET status = $1, responded_at = NOW()
+		WHERE id = $2 AND status = 'pending'`, ListInvitationsTable)
+
+	result, err := r.db.Executer.Exec(query, status, invitationId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrInvitationNotPending
+	}
+
+	return nil
+}
+
+// getOne runs a query returning a single invitation
+func (r *InvitationRepo) getOne(query string, args ...interface{}) (entity.Invitation, error) {
+	var invitation entity.Invitation
+
+	err := scanInvitation(r.db.Querier.QueryRow(query, args...), &invitation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return invitation, utils.ErrInvitationNotFound
+		}
+
+		return invitation, err
+	}
+
+	return invitation, nil
+}
+
+// getMany runs a query returning a list of invitations
+func (r *InvitationRepo) getMany(query string, args ...interface{}) ([]entity.Invitation, error) {
+	invitations := []entity.Invitation{}
+
+	rows, err := r.db.Querier.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invitation entity.Invitation
+		if err := scanInvitation(rows, &invitation); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// scanInvitation scans a row selected with invitationColumns
+func scanInvitation(row rowScanner, invitation *entity.Invitation) error {
+	return row.Scan(
+		&invitation.Id,
+		&invitation.ListId,
+		&invitation.ListTitle,
+		&invitation.InviterId,
+		&invitation.InviteeId,
+		&invitation.Role,
+		&invitation.Status,
+		&invitation.TokenHash,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+	)
+}
@@ -0,0 +1,276 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	queryGetInvitationById    = fmt.Sprintf("SELECT .+ FROM %s i JOIN %s l ON l.id = i.list_id WHERE i.id = \\$1", repository.ListInvitationsTable, repository.ListsTable)
+	queryAcceptInvitation     = fmt.Sprintf("UPDATE %s SET status = 'accepted', invitee_id = \\$1, responded_at = NOW\\(\\) WHERE id = \\$2 AND status = 'pending' AND expires_at > NOW\\(\\) RETURNING list_id, role", repository.ListInvitationsTable)
+	queryJoinInvitedList      = fmt.Sprintf("INSERT INTO %s \\(user_id, list_id, role, position\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) ON CONFLICT \\(user_id, list_id\\) DO NOTHING", repository.UsersListsTable)
+	queryUpdateInvitationById = fmt.Sprintf("UPDATE %s SET status = \\$1, responded_at = NOW\\(\\) WHERE id = \\$2 AND status = 'pending'", repository.ListInvitationsTable)
+
+	invitationRows = []string{"id", "list_id", "title", "inviter_id", "invitee_id", "role", "status", "token_hash", "expires_at", "created_at"}
+)
+
+// setupInvitationRepoTest initializes the database and repository for InvitationRepo tests
+func setupInvitationRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.InvitationRepo, *MockCache) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mockCache := new(MockCache)
+	invitationRepo := repository.NewInvitationRepo(database.New(sqlxDB), cache.New(mockCache, mockCache), &logger.NoOpLogger{})
+
+	return sqlxDB, mock, invitationRepo, mockCache
+}
+
+// TestGetInvitationById tests retrieving an invitation by its Id
+func TestGetInvitationById(t *testing.T) {
+	inviteeId := 2
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+
+	testCases := []struct {
+		name               string
+		mockQuery          func(sqlmock.Sqlmock)
+		expectedInvitation entity.Invitation
+		expectedErr        error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetInvitationById).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(invitationRows).
+						AddRow(1, 3, "Groceries", 1, inviteeId, "editor", "pending", nil, expiresAt, createdAt))
+			},
+			expectedInvitation: entity.Invitation{
+				Id:        1,
+				ListId:    3,
+				ListTitle: "Groceries",
+				InviterId: 1,
+				InviteeId: &inviteeId,
+				Role:      entity.ListRoleEditor,
+				Status:    entity.InvitationStatusPending,
+				ExpiresAt: expiresAt,
+				CreatedAt: createdAt,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "InvitationNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetInvitationById).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedInvitation: entity.Invitation{},
+			expectedErr:        utils.ErrInvitationNotFound,
+		},
+		{
+			name: "DatabaseError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetInvitationById).
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedInvitation: entity.Invitation{},
+			expectedErr:        sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, invitationRepo, _ := setupInvitationRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+
+			invitation, err := invitationRepo.GetOneById(context.Background(), 1)
+
+			assert.Equal(t, testCase.expectedInvitation, invitation)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestAcceptInvitation tests accepting an invitation and joining its list
+func TestAcceptInvitation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryAcceptInvitation).
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "role"}).AddRow(3, "viewer"))
+				expectAppendPosition(sqlMock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 2, nil)
+				sqlMock.ExpectExec(queryJoinInvitedList).
+					WithArgs(2, 3, "viewer", "a0").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "user_lists:2").
+					Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "InvitationNotPending",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryAcceptInvitation).
+					WithArgs(2, 1).
+					WillReturnError(sql.ErrNoRows)
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrInvitationNotPending,
+		},
+		{
+			name: "AlreadyCollaborator",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryAcceptInvitation).
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "role"}).AddRow(3, "viewer"))
+				expectAppendPosition(sqlMock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 2, nil)
+				sqlMock.ExpectExec(queryJoinInvitedList).
+					WithArgs(2, 3, "viewer", "a0").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrCollaboratorExists,
+		},
+		{
+			name: "TransactionBeginFailure",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, invitationRepo, mockCache := setupInvitationRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			err := invitationRepo.Accept(context.Background(), 1, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateInvitationStatus tests declining and revoking pending invitations
+func TestUpdateInvitationStatus(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:   "Declined",
+			status: entity.InvitationStatusDeclined,
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectExec(queryUpdateInvitationById).
+					WithArgs("declined", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "Revoked",
+			status: entity.InvitationStatusRevoked,
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectExec(queryUpdateInvitationById).
+					WithArgs("revoked", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "InvitationNotPending",
+			status: entity.InvitationStatusDeclined,
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectExec(queryUpdateInvitationById).
+					WithArgs("declined", 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrInvitationNotPending,
+		},
+		{
+			name:   "DatabaseError",
+			status: entity.InvitationStatusDeclined,
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectExec(queryUpdateInvitationById).
+					WithArgs("declined", 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, invitationRepo, _ := setupInvitationRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+
+			err := invitationRepo.UpdateStatus(context.Background(), 1, testCase.status)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
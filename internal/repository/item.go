@@ -5,14 +5,22 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
 	"github.com/berikulyBeket/todo-plus/pkg/database"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/rank"
 	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// itemColumns selects an item aliased as i together with the recurrence of its series aliased as s
+const itemColumns = `i.id, i.title, i.description, i.done, i.due_at, i.remind_at, i.series_id, i.occurrence, i.parent_id, s.rule, s.timezone`
+
 // ItemRepo handles item-related operations with database and cache management
 type ItemRepo struct {
 	db     *database.Database
@@ -29,25 +37,41 @@ func NewItemRepo(db *database.Database, cache *cache.Cache, logger logger.Interf
 	}
 }
 
-// CreateListItem creates a new item in the list and stores it in the database
-func (r *ItemRepo) CreateListItem(ctx context.Context, listId int, item *entity.Item) (int, error) {
+// CreateListItem creates a new item in the list and records a created event made by the user,
+// the parent of a subtask must belong to the same list
+func (r *ItemRepo) CreateListItem(ctx context.Context, userId, listId int, item *entity.Item) (int, error) {
 	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	var itemId int
-	createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description) VALUES ($1, $2) RETURNING id`, ItemsTable)
+	if item.ParentId != nil {
+		if err := ensureItemInList(tx, *item.ParentId, listId); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if item.Recurrence != nil {
+		seriesId, err := insertSeries(tx, item)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+
+		occurrence := 1
+		item.SeriesId = &seriesId
+		item.Occurrence = &occurrence
+	}
 
-	err = tx.QueryRow(createItemQuery, item.Title, item.Description).Scan(&itemId)
+	itemId, err := insertListItem(tx, listId, item, "")
 	if err != nil {
 		_ = tx.Rollback()
 		return 0, err
 	}
 
-	createListItemsQuery := fmt.Sprintf(`INSERT INTO %s (list_id, item_id) VALUES ($1, $2)`, ListsItemsTable)
-
-	if _, err := tx.Exec(createListItemsQuery, listId, itemId); err != nil {
+	item.Id = itemId
+	if err := insertItemCreatedEvent(tx, userId, listId, *item); err != nil {
 		_ = tx.Rollback()
 		return 0, err
 	}
@@ -56,7 +80,6 @@ func (r *ItemRepo) CreateListItem(ctx context.Context, listId int, item *entity.
 		return 0, err
 	}
 
-	item.Id = itemId
 	itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
 	listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
 
@@ -70,49 +93,98 @@ func (r *ItemRepo) CreateListItem(ctx context.Context, listId int, item *entity.
 	return itemId, nil
 }
 
-// GetAllListItems retrieves all items for a specific list from the database
-func (r *ItemRepo) GetAllListItems(ctx context.Context, listId int) ([]entity.Item, error) {
-	items := []entity.Item{}
+// GetListItemsPage retrieves a page of the items of a list in the sort order of the query, optionally only the items
+// with the done state or having every one of the given tags of the user. Items in the trash are left out.
+// Pages filtered by tags are not cached
+func (r *ItemRepo) GetListItemsPage(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error) {
+	page := entity.ItemPage{Items: []entity.Item{}}
 
-	cacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
-	if exists, err := r.cache.Replica.Get(ctx, cacheKey, &items); err != nil {
-		r.logger.Errorf("cache error for key %s: %v", cacheKey, err)
-	} else if exists {
-		return items, nil
+	cacheKey := ""
+	if len(query.Tags) == 0 {
+		listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+		cacheKey = pageCacheKey(ctx, r.cache, r.logger, listItemsCacheKey, cacheKeyListItemsPage, listId, query.CacheKey())
 	}
 
-	query := fmt.Sprintf(`
-		SELECT i.id, i.title, i.description, i.done
+	if cacheKey != "" {
+		if exists, err := r.cache.Replica.Get(ctx, cacheKey, &page); err != nil {
+			r.logger.Errorf("cache error for key %s: %v", cacheKey, err)
+		} else if exists {
+			return page, nil
+		}
+	}
+
+	sortExpr := itemSortExpr(query.PageQuery)
+	args := []interface{}{listId}
+	filters := ""
+
+	if query.Done != nil {
+		args = append(args, *query.Done)
+		filters += fmt.Sprintf(" AND i.done = $%d", len(args))
+	}
+
+	if len(query.Tags) > 0 {
+		args = append(args, userId, pq.Array(query.Tags), len(query.Tags))
+		filters += fmt.Sprintf(` AND i.id IN (
+			SELECT it.item_id
+			FROM %s it
+			JOIN %s t ON t.id = it.tag_id
+			WHERE t.user_id = $%d AND t.name = ANY($%d)
+			GROUP BY it.item_id
+			HAVING COUNT(*) = $%d
+		)`, ItemTagsTable, TagsTable, len(args)-2, len(args)-1, len(args))
+	}
+
+	keyset, orderBy, keysetArgs, err := pageClause(query.PageQuery, sortExpr, "i.id", len(args)+1)
+	if err != nil {
+		return entity.ItemPage{}, err
+	}
+	args = append(append(args, keysetArgs...), query.Limit+1)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT %s, (%s)::text
 		FROM %s i
 		JOIN %s li ON i.id = li.item_id
-		WHERE li.list_id = $1`, ItemsTable, ListsItemsTable)
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE li.list_id = $1 AND i.deleted_at IS NULL%s%s
+		ORDER BY %s
+		LIMIT $%d`, itemColumns, sortExpr, ItemsTable, ListsItemsTable, ItemSeriesTable, filters, keyset, orderBy, len(args))
 
-	rows, err := r.db.Querier.Query(query, listId)
+	rows, err := r.db.Querier.Query(sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return entity.ItemPage{}, err
 	}
 	defer rows.Close()
 
+	sortKeys := []string{}
 	for rows.Next() {
 		var item entity.Item
-		if err := rows.Scan(&item.Id, &item.Title, &item.Description, &item.Done); err != nil {
-			return nil, err
+		var sortKey string
+		if err := scanItem(rows, &item, &sortKey); err != nil {
+			return entity.ItemPage{}, err
 		}
-		items = append(items, item)
+		page.Items = append(page.Items, item)
+		sortKeys = append(sortKeys, sortKey)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return entity.ItemPage{}, err
 	}
 
-	if err := r.cache.Master.Set(ctx, cacheKey, items, cacheKeyListItems.TTL); err != nil {
-		r.logger.Errorf("failed to set cache for key %s: %v", cacheKey, err)
+	if len(page.Items) > query.Limit {
+		page.Items = page.Items[:query.Limit]
+		page.NextCursor = query.NextCursor(sortKeys[query.Limit-1], page.Items[query.Limit-1].Id)
 	}
 
-	return items, nil
+	if cacheKey != "" {
+		if err := r.cache.Master.Set(ctx, cacheKey, page, cacheKeyListItemsPage.TTL); err != nil {
+			r.logger.Errorf("failed to set cache for key %s: %v", cacheKey, err)
+		}
+	}
+
+	return page, nil
 }
 
-// GetOneById retrieves a specific item by its ID
+// GetOneById retrieves a specific item by its ID together with its version, ErrItemNotFound is returned for items in the trash
 func (r *ItemRepo) GetOneById(ctx context.Context, itemId int) (entity.Item, error) {
 	var item entity.Item
 
@@ -123,9 +195,12 @@ func (r *ItemRepo) GetOneById(ctx context.Context, itemId int) (entity.Item, err
 		return item, nil
 	}
 
-	query := fmt.Sprintf("SELECT id, title, description, done FROM %s WHERE id = $1", ItemsTable)
+	query := fmt.Sprintf(
+		"SELECT %s, i.version FROM %s i LEFT JOIN %s s ON s.id = i.series_id WHERE i.id = $1 AND i.deleted_at IS NULL",
+		itemColumns, ItemsTable, ItemSeriesTable,
+	)
 
-	err := r.db.Querier.QueryRow(query, itemId).Scan(&item.Id, &item.Title, &item.Description, &item.Done)
+	err := scanItem(r.db.Querier.QueryRow(query, itemId), &item, &item.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return item, utils.ErrItemNotFound
@@ -140,7 +215,8 @@ func (r *ItemRepo) GetOneById(ctx context.Context, itemId int) (entity.Item, err
 	return item, nil
 }
 
-// GetManyByIds retrieves multiple items based on a list of item IDs
+// GetManyByIds retrieves multiple items based on a list of item IDs, items in the trash or in a list in the trash
+// are left out
 func (r *ItemRepo) GetManyByIds(ctx context.Context, itemIds []int) ([]entity.Item, error) {
 	items := []entity.Item{}
 
@@ -148,7 +224,15 @@ func (r *ItemRepo) GetManyByIds(ctx context.Context, itemIds []int) ([]entity.It
 		return []entity.Item{}, nil
 	}
 
-	query := fmt.Sprintf("SELECT id, title, description, done FROM %s WHERE id IN (%s)", ItemsTable, utils.CreatePlaceholders(len(itemIds)))
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		JOIN %s l ON l.id = li.list_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE i.id IN (%s) AND i.deleted_at IS NULL AND l.deleted_at IS NULL`,
+		itemColumns, ItemsTable, ListsItemsTable, ListsTable, ItemSeriesTable, utils.CreatePlaceholders(len(itemIds)),
+	)
 
 	rows, err := r.db.Querier.Query(query, utils.ConvertToInterfaceSlice(itemIds)...)
 	if err != nil {
@@ -158,7 +242,7 @@ func (r *ItemRepo) GetManyByIds(ctx context.Context, itemIds []int) ([]entity.It
 
 	for rows.Next() {
 		var item entity.Item
-		if err := rows.Scan(&item.Id, &item.Title, &item.Description, &item.Done); err != nil {
+		if err := scanItem(rows, &item); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -167,116 +251,1554 @@ func (r *ItemRepo) GetManyByIds(ctx context.Context, itemIds []int) ([]entity.It
 	return items, nil
 }
 
-// UpdateOneById updates an item's details by its ID
-func (r *ItemRepo) UpdateOneById(ctx context.Context, listId *int, itemId int, input entity.UpdateItemInput) error {
-	query := fmt.Sprintf("UPDATE %s SET ", ItemsTable)
-	args := []interface{}{}
+// UpdateOneById updates an item's details by its ID together with the change of its series, if any, in a single
+// transaction and records the change as a revision made by the user. With a version the item is only updated while
+// it still has that version, ErrVersionMismatch is returned otherwise and neither the item nor its series is changed
+func (r *ItemRepo) UpdateOneById(ctx context.Context, userId int, listId *int, itemId int, input entity.UpdateItemInput, series *entity.ItemSeriesChange, version *int) error {
+	setClause, args := itemSetClause(input)
+	if setClause == "" && series == nil {
+		return utils.ErrItemEmptyRequest
+	}
 
-	setClauses := []string{}
-	argIndex := 1
+	return r.update(ctx, userId, *listId, itemId, version, series, setClause, args)
+}
 
-	if input.Title != nil {
-		setClauses = append(setClauses, fmt.Sprintf("title = $%d", argIndex))
-		args = append(args, *input.Title)
-		argIndex++
+// UpdateManyByIds updates the details of many items in a single transaction and records a revision made by the user
+// for every changed item and an updated event for every updated item. The Ids of the updated items are returned,
+// items in the trash are left untouched
+func (r *ItemRepo) UpdateManyByIds(ctx context.Context, userId int, itemIds []int, input entity.UpdateItemInput) ([]int, error) {
+	setClause, args := itemSetClause(input)
+	if setClause == "" {
+		return nil, utils.ErrItemEmptyRequest
 	}
-	if input.Description != nil {
-		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argIndex))
-		args = append(args, *input.Description)
-		argIndex++
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
-	if input.Done != nil {
-		setClauses = append(setClauses, fmt.Sprintf("done = $%d", argIndex))
-		args = append(args, *input.Done)
-		argIndex++
+
+	before, err := lockItemStates(tx, itemIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	if len(setClauses) == 0 {
-		return utils.ErrItemEmptyRequest
+	if len(before) == 0 {
+		_ = tx.Rollback()
+		return []int{}, nil
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s i SET %s
+		FROM %s li
+		WHERE li.item_id = i.id AND i.id = ANY($%d) AND i.deleted_at IS NULL
+		RETURNING i.id, li.list_id`, ItemsTable, setClause, ListsItemsTable, len(args)+1)
+
+	rows, err := tx.Query(query, append(args, pq.Array(itemIds))...)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	query += strings.Join(setClauses, ", ")
+	updatedIds := []int{}
+	listIds := map[int]int{}
+	for rows.Next() {
+		var itemId, listId int
+		if err := rows.Scan(&itemId, &listId); err != nil {
+			rows.Close()
+			_ = tx.Rollback()
+			return nil, err
+		}
+		updatedIds = append(updatedIds, itemId)
+		listIds[itemId] = listId
+	}
+	rows.Close()
 
-	query += fmt.Sprintf(" WHERE id = $%d", argIndex)
-	args = append(args, itemId)
+	if err := rows.Err(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
 
-	result, err := r.db.Executer.Exec(query, args...)
+	after, err := lockItemStates(tx, updatedIds)
 	if err != nil {
-		return err
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if _, err := insertItemRevisions(tx, userId, before, after); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	for _, itemId := range updatedIds {
+		if err := insertItemUpdatedEvent(tx, userId, listIds[itemId], itemId); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateItems(ctx, listIds)
+
+	return updatedIds, nil
+}
+
+// RevertOneById sets the content of an item back to the state and records the revert as a new revision made by the
+// user, the list, parent and recurrence of the item are left as they are. The reminder is only sent again when the
+// revert changes the reminder time
+func (r *ItemRepo) RevertOneById(ctx context.Context, userId, listId, itemId int, state entity.ItemState) error {
+	setClause := `title = $1, description = $2, done = $3, due_at = $4, remind_at = $5,
+		reminder_sent_at = CASE WHEN remind_at IS DISTINCT FROM $5 THEN NULL ELSE reminder_sent_at END`
+
+	args := []interface{}{state.Title, state.Description, state.Done, state.DueAt, state.RemindAt}
+
+	return r.update(ctx, userId, listId, itemId, nil, nil, setClause, args)
+}
+
+// DeleteOneById moves an item to the trash together with its subtasks, records a deleted event for each of them and
+// returns the Ids of the trashed items. The subtasks share the deletion time of the item so that they are restored along with it.
+// With a version the item is only trashed while it still has that version, ErrVersionMismatch is returned otherwise
+func (r *ItemRepo) DeleteOneById(ctx context.Context, itemId int, version *int) ([]int, error) {
+	args := []interface{}{itemId}
+	condition := ""
+	if version != nil {
+		args = append(args, *version)
+		condition = " AND version = $2"
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE trashed AS (
+			SELECT id FROM %s WHERE id = $1 AND deleted_at IS NULL%s
+			UNION
+			SELECT i.id FROM %s i JOIN trashed t ON i.parent_id = t.id WHERE i.deleted_at IS NULL
+		)
+		UPDATE %s i SET deleted_at = NOW()
+		FROM %s li
+		WHERE li.item_id = i.id AND i.id IN (SELECT id FROM trashed)
+		RETURNING i.id, li.list_id`, ItemsTable, condition, ItemsTable, ItemsTable, ListsItemsTable)
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return utils.ErrItemNotFound
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
-	listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, *listId)
+	trashedIds := []int{}
+	listId := 0
+	for rows.Next() {
+		var trashedId int
+		if err := rows.Scan(&trashedId, &listId); err != nil {
+			rows.Close()
+			_ = tx.Rollback()
+			return nil, err
+		}
+		trashedIds = append(trashedIds, trashedId)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
 
-	if err := r.cache.Master.Delete(ctx, itemCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", itemCacheKey, err)
+	if len(trashedIds) == 0 {
+		_ = tx.Rollback()
+		if version != nil {
+			return nil, versionConflict(r.db.Querier, ItemsTable, itemId, utils.ErrItemNotFound)
+		}
+		return nil, utils.ErrItemNotFound
 	}
-	if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+
+	if err := insertItemsDeletedEvents(tx, trashedIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, trashedId := range trashedIds {
+		r.invalidateItem(ctx, listId, trashedId)
+	}
+
+	return trashedIds, nil
 }
 
-// DeleteOneById deletes an item by its ID from the database
-func (r *ItemRepo) DeleteOneById(ctx context.Context, listId *int, itemId int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", ItemsTable)
+// DeleteManyByIds moves many items to the trash together with their subtasks in a single statement, records a deleted
+// event for each of them and returns the Ids of the trashed items. The trashed items share their deletion time so that
+// subtasks are restored along with them
+func (r *ItemRepo) DeleteManyByIds(ctx context.Context, itemIds []int) ([]int, error) {
+	query := fmt.Sprintf(`
+		WITH RECURSIVE trashed AS (
+			SELECT id FROM %s WHERE id = ANY($1) AND deleted_at IS NULL
+			UNION
+			SELECT i.id FROM %s i JOIN trashed t ON i.parent_id = t.id WHERE i.deleted_at IS NULL
+		)
+		UPDATE %s i SET deleted_at = NOW()
+		FROM %s li
+		WHERE li.item_id = i.id AND i.id IN (SELECT id FROM trashed)
+		RETURNING i.id, li.list_id`, ItemsTable, ItemsTable, ItemsTable, ListsItemsTable)
 
-	result, err := r.db.Executer.Exec(query, itemId)
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rows, err := tx.Query(query, pq.Array(itemIds))
 	if err != nil {
-		return err
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return utils.ErrItemNotFound
+	trashedIds := []int{}
+	listIds := map[int]int{}
+	for rows.Next() {
+		var trashedId, listId int
+		if err := rows.Scan(&trashedId, &listId); err != nil {
+			rows.Close()
+			_ = tx.Rollback()
+			return nil, err
+		}
+		trashedIds = append(trashedIds, trashedId)
+		listIds[trashedId] = listId
 	}
+	rows.Close()
 
-	itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
-	if err := r.cache.Master.Delete(ctx, itemCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", itemCacheKey, err)
+	if err := rows.Err(); err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
-	if listId != nil {
-		listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, *listId)
-		if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
-			r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+
+	if err := insertItemsDeletedEvents(tx, trashedIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateItems(ctx, listIds)
+
+	return trashedIds, nil
+}
+
+// GetUserItemRole returns the role of the user on the list holding the item, ErrUserNotOwner is returned when
+// the list is not shared with the user or when the item or its list is in the trash
+func (r *ItemRepo) GetUserItemRole(ctx context.Context, userId, itemId int) (string, error) {
+	query := fmt.Sprintf(`
+		SELECT ul.role
+		FROM %s li
+		JOIN %s ul ON li.list_id = ul.list_id
+		JOIN %s i ON i.id = li.item_id
+		JOIN %s l ON l.id = li.list_id
+		WHERE ul.user_id = $1 AND li.item_id = $2 AND i.deleted_at IS NULL AND l.deleted_at IS NULL`,
+		ListsItemsTable, UsersListsTable, ItemsTable, ListsTable)
+
+	var role string
+
+	err := r.db.Querier.QueryRow(query, userId, itemId).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", utils.ErrUserNotOwner
 		}
+
+		return "", err
 	}
 
-	return nil
+	return role, nil
+}
+
+// GetUserItemsAccess returns the roles of the user on the lists holding the items with a single query,
+// items whose list is not shared with the user or that are in the trash are left out
+func (r *ItemRepo) GetUserItemsAccess(ctx context.Context, userId int, itemIds []int) ([]entity.ItemAccess, error) {
+	query := fmt.Sprintf(`
+		SELECT li.item_id, li.list_id, ul.role
+		FROM %s li
+		JOIN %s ul ON li.list_id = ul.list_id
+		JOIN %s i ON i.id = li.item_id
+		JOIN %s l ON l.id = li.list_id
+		WHERE ul.user_id = $1 AND li.item_id = ANY($2) AND i.deleted_at IS NULL AND l.deleted_at IS NULL`,
+		ListsItemsTable, UsersListsTable, ItemsTable, ListsTable)
+
+	rows, err := r.db.Querier.Query(query, userId, pq.Array(itemIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accesses := []entity.ItemAccess{}
+	for rows.Next() {
+		var access entity.ItemAccess
+		if err := rows.Scan(&access.ItemId, &access.ListId, &access.Role); err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, access)
+	}
+
+	return accesses, rows.Err()
 }
 
-// IsUserOwnerOfItem checks if a user is the owner of a specific item
-func (r *ItemRepo) IsUserOwnerOfItem(ctx context.Context, userId, itemId int) error {
+// ClaimDueReminders marks up to limit unsent reminders of open items that are due as sent and returns them,
+// items in the trash or in a list in the trash are not reminded of. A reminder due event is recorded for every
+// claimed reminder in the same transaction, so a reminder is sent if and only if it is claimed.
+// Rows claimed by a concurrent scan are skipped, so every reminder is claimed by a single caller
+func (r *ItemRepo) ClaimDueReminders(ctx context.Context, limit int) ([]entity.ItemReminderDueEvent, error) {
 	query := fmt.Sprintf(`
-		SELECT 1
+		UPDATE %s i SET reminder_sent_at = NOW()
 		FROM %s li
-		JOIN %s ul 
-		ON li.list_id = ul.list_id 
-		WHERE ul.user_id = $1 AND li.item_id = $2`, ListsItemsTable, UsersListsTable)
+		WHERE li.item_id = i.id AND i.id IN (
+			SELECT ri.id FROM %s ri
+			WHERE ri.remind_at <= NOW() AND ri.reminder_sent_at IS NULL AND ri.done = FALSE AND ri.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM %s rli JOIN %s rl ON rl.id = rli.list_id
+				WHERE rli.item_id = ri.id AND rl.deleted_at IS NOT NULL
+			)
+			ORDER BY ri.remind_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING li.list_id, i.id, i.title, i.due_at, i.remind_at`, ItemsTable, ListsItemsTable, ItemsTable, ListsItemsTable, ListsTable)
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := scanReminders(tx, query, limit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	for _, reminder := range reminders {
+		if err := insertItemReminderDueEvent(tx, reminder); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+// scanReminders runs a query returning the list, id, title, due and remind times of items as reminders
+func scanReminders(querier database.Querier, query string, args ...interface{}) ([]entity.ItemReminderDueEvent, error) {
+	rows, err := querier.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []entity.ItemReminderDueEvent{}
+	for rows.Next() {
+		var reminder entity.ItemReminderDueEvent
+		if err := rows.Scan(&reminder.ListId, &reminder.ItemId, &reminder.Title, &reminder.DueAt, &reminder.RemindAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
 
-	var exists int
+// GetSeries retrieves the series the occurrences of a recurring item are generated from
+func (r *ItemRepo) GetSeries(ctx context.Context, seriesId int) (entity.ItemSeries, error) {
+	var series entity.ItemSeries
 
-	err := r.db.Querier.QueryRow(query, userId, itemId).Scan(&exists)
+	query := fmt.Sprintf("SELECT id, rule, timezone, title, description, starts_at FROM %s WHERE id = $1", ItemSeriesTable)
+
+	err := r.db.Querier.QueryRow(query, seriesId).Scan(
+		&series.Id,
+		&series.Recurrence.Rule,
+		&series.Recurrence.Timezone,
+		&series.Title,
+		&series.Description,
+		&series.StartsAt,
+	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return utils.ErrUserNotOwner
+			return series, utils.ErrItemNotFound
 		}
+		return series, err
+	}
 
-		return err
+	return series, nil
+}
+
+// CreateNextOccurrence creates the occurrence following the previous item of a series in the list of the previous item,
+// records a created event made by the user and returns the Ids of the new item and the list. An occurrence is created
+// only once, a zero item Id is returned when it already exists
+func (r *ItemRepo) CreateNextOccurrence(ctx context.Context, userId, previousItemId int, item *entity.Item) (int, int, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	listId, err := getItemListId(tx, previousItemId)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, 0, err
+	}
+
+	itemId, err := insertListItem(tx, listId, item, "ON CONFLICT (series_id, occurrence) DO NOTHING")
+	if err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return 0, listId, nil
+		}
+		return 0, 0, err
+	}
+
+	item.Id = itemId
+	if err := insertItemCreatedEvent(tx, userId, listId, *item); err != nil {
+		_ = tx.Rollback()
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+	if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+	}
+
+	return itemId, listId, nil
+}
+
+// GetSubtasks retrieves the direct subtasks of an item in the order of the list, subtasks in the trash are left out
+func (r *ItemRepo) GetSubtasks(ctx context.Context, parentId int) ([]entity.Item, error) {
+	items := []entity.Item{}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE i.parent_id = $1 AND i.deleted_at IS NULL
+		ORDER BY li.position, i.id`, itemColumns, ItemsTable, ListsItemsTable, ItemSeriesTable)
+
+	rows, err := r.db.Querier.Query(query, parentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.Item
+		if err := scanItem(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetParent makes an item a subtask of another item of the list, or a top-level item when parentId is nil.
+// The item and its parent must both belong to the list, ErrItemNotFound is returned when the item does not.
+// Moves that would make an item a subtask of itself or of one of its subtasks are rejected, the moves within
+// a list are serialized so that two concurrent moves cannot build a cycle together. A revision and an updated event made
+// by the user are recorded
+func (r *ItemRepo) SetParent(ctx context.Context, userId, listId, itemId int, parentId *int) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureListItem(tx, itemId, listId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if parentId != nil {
+		if err := listItemsOrder.lock(tx, listId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := ensureItemInList(tx, *parentId, listId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		cycleQuery := fmt.Sprintf(`
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM %s WHERE id = $1
+				UNION
+				SELECT i.id, i.parent_id FROM %s i JOIN ancestors a ON i.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`, ItemsTable, ItemsTable)
+
+		var cycle bool
+		if err := tx.QueryRow(cycleQuery, *parentId, itemId).Scan(&cycle); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if cycle {
+			_ = tx.Rollback()
+			return utils.ErrItemParentCycle
+		}
+	}
+
+	before, err := lockItemStates(tx, []int{itemId})
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET parent_id = $1 WHERE id = $2", ItemsTable)
+
+	result, err := tx.Exec(query, parentId, itemId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return utils.ErrItemNotFound
+	}
+
+	after, err := lockItemStates(tx, []int{itemId})
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := insertItemRevisions(tx, userId, before, after); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := insertItemUpdatedEvent(tx, userId, listId, itemId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateItem(ctx, listId, itemId)
+
+	return nil
+}
+
+// CompleteSubtasks marks every open subtask of an item as done, including the subtasks of subtasks, records a revision
+// and an updated event made by the user for each of them and returns the Ids of the completed subtasks.
+// ErrItemNotFound is returned when the item does not belong to the list, subtasks in the trash are left untouched
+func (r *ItemRepo) CompleteSubtasks(ctx context.Context, userId, listId, itemId int) ([]int, error) {
+	query := fmt.Sprintf(`
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM %s WHERE parent_id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT i.id FROM %s i JOIN descendants d ON i.parent_id = d.id WHERE i.deleted_at IS NULL
+		)
+		SELECT id FROM descendants`, ItemsTable, ItemsTable)
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureListItem(tx, itemId, listId); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	subtaskIds, err := queryIds(tx, query, itemId)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	before, err := lockItemStates(tx, subtaskIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET done = TRUE WHERE id = ANY($1) AND done = FALSE AND deleted_at IS NULL RETURNING id", ItemsTable)
+
+	completedIds, err := queryIds(tx, query, pq.Array(subtaskIds))
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	after, err := lockItemStates(tx, completedIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := insertItemRevisions(tx, userId, before, after); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := insertItemsUpdatedEvents(tx, userId, listId, completedIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, completedId := range completedIds {
+		r.invalidateItem(ctx, listId, completedId)
+	}
+
+	return completedIds, nil
+}
+
+// MoveItem places an item of the list between the anchors given in the input and bumps the version of the item
+func (r *ItemRepo) MoveItem(ctx context.Context, listId, itemId int, input entity.MoveInput) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := listItemsOrder.move(tx, listId, itemId, input); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := bumpVersions(tx, ItemsTable, []int{itemId}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateItem(ctx, listId, itemId)
+
+	return nil
+}
+
+// MoveToList moves items to the end of the list, subtasks move along with their parents and the moved items keep
+// their order. Moved items whose parent stays in another list become top-level items. The version is bumped, a revision
+// and an updated event made by the user are recorded and the Id is returned for every moved item, items already in the
+// list are left untouched
+func (r *ItemRepo) MoveToList(ctx context.Context, userId, listId int, itemIds []int) ([]int, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := listItemsOrder.lock(tx, listId); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	movedIds, sourceListIds, err := getSubtreeListIds(tx, itemIds, listId)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if len(movedIds) == 0 {
+		_ = tx.Rollback()
+		return movedIds, nil
+	}
+
+	before, err := lockItemStates(tx, movedIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	position, err := listItemsOrder.lastPosition(tx, listId)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET list_id = $1, position = $2 WHERE item_id = $3", ListsItemsTable)
+	for _, itemId := range movedIds {
+		if position, err = rank.Between(position, ""); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+
+		if _, err := tx.Exec(query, listId, position, itemId); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	query = fmt.Sprintf(`
+		UPDATE %s i SET parent_id = NULL
+		FROM %s pli
+		WHERE i.id = ANY($1) AND pli.item_id = i.parent_id AND pli.list_id <> $2`, ItemsTable, ListsItemsTable)
+
+	if _, err := tx.Exec(query, pq.Array(movedIds), listId); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := bumpVersions(tx, ItemsTable, movedIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	after, err := lockItemStates(tx, movedIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := insertItemRevisions(tx, userId, before, after); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := insertItemsUpdatedEvents(tx, userId, listId, movedIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, itemId := range movedIds {
+		r.invalidateItem(ctx, listId, itemId)
+	}
+	for _, sourceListId := range sourceListIds {
+		listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, sourceListId)
+		if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+		}
+	}
+
+	return movedIds, nil
+}
+
+// CopyToList copies items to the end of the list together with their subtasks. The copies keep the content, due dates
+// and subtask structure of the originals but start undone, outside of any series and without tags.
+// A created event made by the user is recorded for every copy, subtasks in the trash are not copied
+func (r *ItemRepo) CopyToList(ctx context.Context, userId, listId int, itemIds []int) ([]entity.ItemCopy, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE copied AS (
+			SELECT id FROM %s WHERE id = ANY($1) AND deleted_at IS NULL
+			UNION
+			SELECT i.id FROM %s i JOIN copied c ON i.parent_id = c.id WHERE i.deleted_at IS NULL
+		)
+		SELECT %s
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE i.id IN (SELECT id FROM copied)
+		ORDER BY li.list_id, li.position, i.id`, ItemsTable, ItemsTable, itemColumns, ItemsTable, ListsItemsTable, ItemSeriesTable)
+
+	originals, err := queryItems(tx, query, pq.Array(itemIds))
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	copies, err := copyItems(tx, listId, originals)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	items := make([]entity.Item, 0, len(copies))
+	for _, itemCopy := range copies {
+		items = append(items, itemCopy.Item)
+	}
+
+	if err := insertItemsCreatedEvents(tx, userId, listId, items); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+	if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+	}
+
+	return copies, nil
+}
+
+// GetTrashedListItems retrieves the items of the list that are in the trash, the most recently deleted first
+func (r *ItemRepo) GetTrashedListItems(ctx context.Context, listId int) ([]entity.TrashedItem, error) {
+	items := []entity.TrashedItem{}
+
+	query := fmt.Sprintf(`
+		SELECT %s, li.list_id, i.deleted_at
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE li.list_id = $1 AND i.deleted_at IS NOT NULL
+		ORDER BY i.deleted_at DESC, li.position, i.id`, itemColumns, ItemsTable, ListsItemsTable, ItemSeriesTable)
+
+	rows, err := r.db.Querier.Query(query, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.TrashedItem
+		if err := scanItem(rows, &item.Item, &item.ListId, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// RestoreItem takes an item of the list out of the trash together with the subtasks trashed along with it, records an
+// updated event made by the user for each of them so that they are indexed again, and returns the Ids of the restored
+// items. A restored item whose parent is still in the trash becomes a top-level item
+func (r *ItemRepo) RestoreItem(ctx context.Context, userId, listId, itemId int) ([]int, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE restored AS (
+			SELECT i.id, i.deleted_at
+			FROM %s i
+			JOIN %s li ON i.id = li.item_id
+			WHERE i.id = $1 AND li.list_id = $2 AND i.deleted_at IS NOT NULL
+			UNION
+			SELECT i.id, i.deleted_at FROM %s i JOIN restored r ON i.parent_id = r.id WHERE i.deleted_at = r.deleted_at
+		)
+		UPDATE %s SET deleted_at = NULL
+		WHERE id IN (SELECT id FROM restored)
+		RETURNING id`, ItemsTable, ListsItemsTable, ItemsTable, ItemsTable)
+
+	restoredIds, err := queryIds(tx, query, itemId, listId)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if len(restoredIds) == 0 {
+		_ = tx.Rollback()
+		return nil, utils.ErrItemNotFound
+	}
+
+	query = fmt.Sprintf(`
+		UPDATE %s i SET parent_id = NULL
+		FROM %s p
+		WHERE i.id = $1 AND p.id = i.parent_id AND p.deleted_at IS NOT NULL`, ItemsTable, ItemsTable)
+
+	if _, err := tx.Exec(query, itemId); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := insertItemsUpdatedEvents(tx, userId, listId, restoredIds); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, restoredId := range restoredIds {
+		r.invalidateItem(ctx, listId, restoredId)
+	}
+
+	return restoredIds, nil
+}
+
+// PurgeTrash permanently deletes the items that were moved to the trash before the given time
+// and returns the Ids of the purged items
+func (r *ItemRepo) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1 RETURNING id", ItemsTable)
+
+	return queryIds(r.db.Querier, query, deletedBefore)
+}
+
+// invalidateItem removes an item and the items of its list from the cache
+func (r *ItemRepo) invalidateItem(ctx context.Context, listId, itemId int) {
+	invalidateItemCache(ctx, r.cache, r.logger, listId, itemId)
+}
+
+// invalidateItemCache removes an item and the items of its list from the cache, failures are only logged
+func invalidateItemCache(ctx context.Context, cache *cache.Cache, logger logger.Interface, listId, itemId int) {
+	itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
+	listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+
+	if err := cache.Master.Delete(ctx, itemCacheKey); err != nil {
+		logger.Errorf("failed to invalidate cache for key %s: %v", itemCacheKey, err)
+	}
+	if err := cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+		logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+	}
+}
+
+// invalidateItems removes items and the items of their lists from the cache, every list is invalidated once.
+// The items are given with the Ids of their lists
+func (r *ItemRepo) invalidateItems(ctx context.Context, listIds map[int]int) {
+	invalidated := map[int]bool{}
+
+	for itemId, listId := range listIds {
+		itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
+		if err := r.cache.Master.Delete(ctx, itemCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", itemCacheKey, err)
+		}
+
+		if invalidated[listId] {
+			continue
+		}
+		invalidated[listId] = true
+
+		listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+		if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+		}
+	}
+}
+
+// update applies the change of the series and the set clause to an item in a transaction and records the states of
+// the item around it as a revision made by the user together with an updated event, the Id of the item is bound after
+// the arguments of the clause. No revision is recorded when the update leaves the item unchanged, with a version the
+// item must still have it before anything is changed. ErrItemNotFound is returned when the item does not belong to the
+// list, so that the event and the cache follow the list holding the item. The other occurrences of an updated series get a revision and
+// an updated event as well when their recurrence changes
+func (r *ItemRepo) update(ctx context.Context, userId, listId, itemId int, version *int, series *entity.ItemSeriesChange, setClause string, args []interface{}) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if version != nil {
+		if err := lockVersion(tx, ItemsTable, itemId, *version); err != nil {
+			_ = tx.Rollback()
+			if err == sql.ErrNoRows {
+				return utils.ErrItemNotFound
+			}
+			return err
+		}
+	}
+
+	itemIds := []int{itemId}
+	if series != nil && series.Action == entity.ItemSeriesUpdate {
+		query := fmt.Sprintf("SELECT id FROM %s WHERE series_id = $1 AND id <> $2 ORDER BY id", ItemsTable)
+
+		occurrenceIds, err := queryIds(tx, query, series.SeriesId, itemId)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		itemIds = append(itemIds, occurrenceIds...)
+	}
+
+	before, err := lockItemStates(tx, itemIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if state, ok := before[itemId]; !ok || state.ListId != listId {
+		_ = tx.Rollback()
+		return utils.ErrItemNotFound
+	}
+
+	occurrences := map[int]int{}
+	if series != nil {
+		if occurrences, err = applySeriesChange(tx, itemId, series); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if setClause != "" {
+		query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", ItemsTable, setClause, len(args)+1)
+
+		if _, err := tx.Exec(query, append(args, itemId)...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	after, err := lockItemStates(tx, itemIds)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	changedIds, err := insertItemRevisions(tx, userId, before, after)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := insertItemUpdatedEvent(tx, userId, listId, itemId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, changedId := range changedIds {
+		if changedId == itemId {
+			continue
+		}
+
+		if err := insertItemUpdatedEvent(tx, userId, after[changedId].ListId, changedId); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	delete(occurrences, itemId)
+	r.invalidateItem(ctx, listId, itemId)
+	r.invalidateItems(ctx, occurrences)
+
+	return nil
+}
+
+// applySeriesChange starts, updates or stops the series of an item and bumps the versions of the items it changes.
+// Updating a series changes every occurrence, their Ids are returned with the Ids of their lists
+func applySeriesChange(tx *sqlx.Tx, itemId int, change *entity.ItemSeriesChange) (map[int]int, error) {
+	switch change.Action {
+	case entity.ItemSeriesStart:
+		return map[int]int{}, startSeries(tx, itemId, &change.First)
+	case entity.ItemSeriesStop:
+		return map[int]int{}, stopSeries(tx, itemId)
+	default:
+		return updateSeries(tx, change.SeriesId, change.Input)
+	}
+}
+
+// startSeries makes an item the first occurrence of a new series built from the item and its recurrence
+func startSeries(tx *sqlx.Tx, itemId int, item *entity.Item) error {
+	seriesId, err := insertSeries(tx, item)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET series_id = $1, occurrence = 1, version = version + 1 WHERE id = $2", ItemsTable)
+
+	result, err := tx.Exec(query, seriesId, itemId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// updateSeries applies the title, description and recurrence of the input to a series, so that they are used by
+// the occurrences generated from now on. The versions of the occurrences are bumped as their recurrence changes
+// and their Ids are returned with the Ids of their lists
+func updateSeries(tx *sqlx.Tx, seriesId int, input entity.UpdateItemInput) (map[int]int, error) {
+	args := []interface{}{}
+
+	setClauses := []string{}
+	argIndex := 1
+
+	if input.Title != nil {
+		setClauses = append(setClauses, fmt.Sprintf("title = $%d", argIndex))
+		args = append(args, *input.Title)
+		argIndex++
+	}
+	if input.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *input.Description)
+		argIndex++
+	}
+	if input.Recurrence != nil {
+		setClauses = append(setClauses, fmt.Sprintf("rule = $%d, timezone = $%d", argIndex, argIndex+1))
+		args = append(args, input.Recurrence.Rule, input.Recurrence.Timezone)
+		argIndex += 2
+	}
+	if input.DueAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("starts_at = $%d", argIndex))
+		args = append(args, *input.DueAt)
+		argIndex++
+	}
+
+	if len(setClauses) == 0 {
+		return map[int]int{}, nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", ItemSeriesTable, strings.Join(setClauses, ", "), argIndex)
+
+	result, err := tx.Exec(query, append(args, seriesId)...)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, utils.ErrItemNotFound
+	}
+
+	return bumpSeriesVersions(tx, seriesId)
+}
+
+// stopSeries detaches an item from its series so that no further occurrences are generated after it
+func stopSeries(tx *sqlx.Tx, itemId int) error {
+	query := fmt.Sprintf("UPDATE %s SET series_id = NULL, occurrence = NULL, version = version + 1 WHERE id = $1", ItemsTable)
+
+	result, err := tx.Exec(query, itemId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// itemSortExpr returns the expression items are sorted by for the sort of the query,
+// items without a due date come last in both orders
+func itemSortExpr(query entity.PageQuery) string {
+	switch query.Sort {
+	case entity.SortCreated:
+		return "i.created_at"
+	case entity.SortUpdated:
+		return "i.updated_at"
+	case entity.SortTitle:
+		return "i.title"
+	case entity.SortDue:
+		if query.Descending() {
+			return "COALESCE(i.due_at, '-infinity')"
+		}
+		return "COALESCE(i.due_at, 'infinity')"
+	default:
+		return "li.position"
+	}
+}
+
+// itemSetClause builds the set clause and its arguments for the fields of the update input, the clause is empty
+// when the input changes no field. A new reminder time clears the sent mark of the reminder
+func itemSetClause(input entity.UpdateItemInput) (string, []interface{}) {
+	args := []interface{}{}
+
+	setClauses := []string{}
+	argIndex := 1
+
+	if input.Title != nil {
+		setClauses = append(setClauses, fmt.Sprintf("title = $%d", argIndex))
+		args = append(args, *input.Title)
+		argIndex++
+	}
+	if input.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *input.Description)
+		argIndex++
+	}
+	if input.Done != nil {
+		setClauses = append(setClauses, fmt.Sprintf("done = $%d", argIndex))
+		args = append(args, *input.Done)
+		argIndex++
+	}
+	if input.DueAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("due_at = $%d", argIndex))
+		args = append(args, *input.DueAt)
+		argIndex++
+	}
+	if input.RemindAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("remind_at = $%d, reminder_sent_at = NULL", argIndex))
+		args = append(args, *input.RemindAt)
+	}
+
+	return strings.Join(setClauses, ", "), args
+}
+
+// insertSeries stores the series of a recurring item, starting at the due date of the item
+func insertSeries(tx *sqlx.Tx, item *entity.Item) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (rule, timezone, title, description, starts_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`, ItemSeriesTable)
+
+	var seriesId int
+	err := tx.QueryRow(
+		query,
+		item.Recurrence.Rule,
+		item.Recurrence.Timezone,
+		item.Title,
+		item.Description,
+		item.DueAt,
+	).Scan(&seriesId)
+
+	return seriesId, err
+}
+
+// getItemListId retrieves the Id of the list holding an item, ErrItemNotFound is returned for items in the trash
+func getItemListId(tx *sqlx.Tx, itemId int) (int, error) {
+	var listId int
+	query := fmt.Sprintf(`
+		SELECT li.list_id
+		FROM %s li
+		JOIN %s i ON i.id = li.item_id
+		WHERE li.item_id = $1 AND i.deleted_at IS NULL`, ListsItemsTable, ItemsTable)
+
+	if err := tx.QueryRow(query, itemId).Scan(&listId); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, utils.ErrItemNotFound
+		}
+		return 0, err
+	}
+
+	return listId, nil
+}
+
+// ensureListItem returns ErrItemNotFound when the item is in the trash or does not belong to the list,
+// so that an item is only changed through the list holding it
+func ensureListItem(tx *sqlx.Tx, itemId, listId int) error {
+	itemListId, err := getItemListId(tx, itemId)
+	if err != nil {
+		return err
+	}
+
+	if itemListId != listId {
+		return utils.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// ensureItemInList returns ErrItemParentNotInList when the item does not belong to the list
+func ensureItemInList(tx *sqlx.Tx, itemId, listId int) error {
+	itemListId, err := getItemListId(tx, itemId)
+	if err != nil {
+		if err == utils.ErrItemNotFound {
+			return utils.ErrItemParentNotInList
+		}
+		return err
+	}
+
+	if itemListId != listId {
+		return utils.ErrItemParentNotInList
+	}
+
+	return nil
+}
+
+// getSubtreeListIds retrieves the items and all of their subtasks that are not in the list yet, in the order
+// of their lists, together with the Ids of the lists holding them
+func getSubtreeListIds(tx *sqlx.Tx, itemIds []int, listId int) ([]int, []int, error) {
+	query := fmt.Sprintf(`
+		WITH RECURSIVE moved AS (
+			SELECT id FROM %s WHERE id = ANY($1)
+			UNION
+			SELECT i.id FROM %s i JOIN moved m ON i.parent_id = m.id
+		)
+		SELECT li.item_id, li.list_id
+		FROM %s li
+		JOIN moved m ON m.id = li.item_id
+		WHERE li.list_id <> $2
+		ORDER BY li.list_id, li.position`, ItemsTable, ItemsTable, ListsItemsTable)
+
+	rows, err := tx.Query(query, pq.Array(itemIds), listId)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	subtreeIds, listIds := []int{}, []int{}
+	for rows.Next() {
+		var itemId, itemListId int
+		if err := rows.Scan(&itemId, &itemListId); err != nil {
+			return nil, nil, err
+		}
+
+		subtreeIds = append(subtreeIds, itemId)
+		if len(listIds) == 0 || listIds[len(listIds)-1] != itemListId {
+			listIds = append(listIds, itemListId)
+		}
+	}
+
+	return subtreeIds, listIds, rows.Err()
+}
+
+// queryIds retrieves the Ids selected by the query
+func queryIds(querier database.Querier, query string, args ...interface{}) ([]int, error) {
+	rows, err := querier.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// bumpSeriesVersions bumps the versions of the occurrences of a series and returns the Ids of their lists by item Id
+func bumpSeriesVersions(tx *sqlx.Tx, seriesId int) (map[int]int, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s i SET version = i.version + 1
+		FROM %s li
+		WHERE i.series_id = $1 AND li.item_id = i.id
+		RETURNING i.id, li.list_id`, ItemsTable, ListsItemsTable)
+
+	rows, err := tx.Query(query, seriesId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listIds := map[int]int{}
+	for rows.Next() {
+		var itemId, listId int
+		if err := rows.Scan(&itemId, &listId); err != nil {
+			return nil, err
+		}
+		listIds[itemId] = listId
+	}
+
+	return listIds, rows.Err()
+}
+
+// queryItems retrieves the items selected with itemColumns by the query
+func queryItems(tx *sqlx.Tx, query string, args ...interface{}) ([]entity.Item, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.Item{}
+	for rows.Next() {
+		var item entity.Item
+		if err := scanItem(rows, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// copyItems inserts a copy of every item at the end of the list, parents are copied before their subtasks
+// so that the copied subtasks point to the copied parents
+func copyItems(tx *sqlx.Tx, listId int, originals []entity.Item) ([]entity.ItemCopy, error) {
+	originalIds := make(map[int]bool, len(originals))
+	for _, original := range originals {
+		originalIds[original.Id] = true
+	}
+
+	copyIds := make(map[int]int, len(originals))
+	copies := make([]entity.ItemCopy, 0, len(originals))
+
+	for len(copies) < len(originals) {
+		progressed := false
+
+		for _, original := range originals {
+			if _, ok := copyIds[original.Id]; ok {
+				continue
+			}
+
+			var parentId *int
+			if original.ParentId != nil && originalIds[*original.ParentId] {
+				copyParentId, ok := copyIds[*original.ParentId]
+				if !ok {
+					continue
+				}
+				parentId = &copyParentId
+			}
+
+			item := entity.Item{
+				Title:       original.Title,
+				Description: original.Description,
+				DueAt:       original.DueAt,
+				RemindAt:    original.RemindAt,
+				ParentId:    parentId,
+			}
+
+			itemId, err := insertListItem(tx, listId, &item, "")
+			if err != nil {
+				return nil, err
+			}
+
+			item.Id = itemId
+			copyIds[original.Id] = itemId
+			copies = append(copies, entity.ItemCopy{SourceId: original.Id, Item: item})
+			progressed = true
+		}
+
+		if !progressed {
+			return nil, utils.ErrItemParentCycle
+		}
+	}
+
+	return copies, nil
+}
+
+// insertListItem stores an item and links it to the end of the list, conflict is an optional ON CONFLICT clause for the item
+func insertListItem(tx *sqlx.Tx, listId int, item *entity.Item, conflict string) (int, error) {
+	var itemId int
+	createItemQuery := fmt.Sprintf(`
+		INSERT INTO %s (title, description, due_at, remind_at, series_id, occurrence, parent_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) %s
+		RETURNING id, version`, ItemsTable, conflict)
+
+	err := tx.QueryRow(
+		createItemQuery,
+		item.Title,
+		item.Description,
+		item.DueAt,
+		item.RemindAt,
+		item.SeriesId,
+		item.Occurrence,
+		item.ParentId,
+	).Scan(&itemId, &item.Version)
+	if err != nil {
+		return 0, err
+	}
+
+	position, err := listItemsOrder.appendPosition(tx, listId)
+	if err != nil {
+		return 0, err
+	}
+
+	createListItemsQuery := fmt.Sprintf(`INSERT INTO %s (list_id, item_id, position) VALUES ($1, $2, $3)`, ListsItemsTable)
+
+	if _, err := tx.Exec(createListItemsQuery, listId, itemId, position); err != nil {
+		return 0, err
+	}
+
+	return itemId, nil
+}
+
+// lockItemStates locks the items and retrieves the fields tracked by their revisions by item Id,
+// items in the trash are left out
+func lockItemStates(tx *sqlx.Tx, itemIds []int) (map[int]entity.ItemState, error) {
+	query := fmt.Sprintf(`
+		SELECT i.id, i.title, i.description, i.done, i.due_at, i.remind_at, li.list_id, i.parent_id, s.rule, s.timezone
+		FROM %s i
+		JOIN %s li ON li.item_id = i.id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE i.id = ANY($1) AND i.deleted_at IS NULL
+		ORDER BY i.id
+		FOR UPDATE OF i`, ItemsTable, ListsItemsTable, ItemSeriesTable)
+
+	rows, err := tx.Query(query, pq.Array(itemIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[int]entity.ItemState, len(itemIds))
+	for rows.Next() {
+		var itemId int
+		var rule, timezone *string
+		var state entity.ItemState
+
+		err := rows.Scan(&itemId, &state.Title, &state.Description, &state.Done, &state.DueAt, &state.RemindAt,
+			&state.ListId, &state.ParentId, &rule, &timezone)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule != nil && timezone != nil {
+			state.Recurrence = &entity.Recurrence{Rule: *rule, Timezone: *timezone}
+		}
+		states[itemId] = state
+	}
+
+	return states, rows.Err()
+}
+
+// scanItem scans a row selected with itemColumns, the columns selected after itemColumns are scanned into extra
+func scanItem(row rowScanner, item *entity.Item, extra ...interface{}) error {
+	var rule, timezone *string
+
+	dest := []interface{}{
+		&item.Id,
+		&item.Title,
+		&item.Description,
+		&item.Done,
+		&item.DueAt,
+		&item.RemindAt,
+		&item.SeriesId,
+		&item.Occurrence,
+		&item.ParentId,
+		&rule,
+		&timezone,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	if rule != nil && timezone != nil {
+		item.Recurrence = &entity.Recurrence{Rule: *rule, Timezone: *timezone}
 	}
 
 	return nil
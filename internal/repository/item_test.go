@@ -4,31 +4,81 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
 	"github.com/berikulyBeket/todo-plus/pkg/database"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+const itemColumns = "i.id, i.title, i.description, i.done, i.due_at, i.remind_at, i.series_id, i.occurrence, i.parent_id, s.rule, s.timezone"
+
+var itemRowColumns = []string{"id", "title", "description", "done", "due_at", "remind_at", "series_id", "occurrence", "parent_id", "rule", "timezone"}
+
+var itemPageRowColumns = append(append([]string{}, itemRowColumns...), "sort_key")
+
+var itemVersionRowColumns = append(append([]string{}, itemRowColumns...), "version")
+
+var itemStateRowColumns = []string{"id", "title", "description", "done", "due_at", "remind_at", "list_id", "parent_id", "rule", "timezone"}
+
 var (
-	queryCreateItem          = fmt.Sprintf("INSERT INTO %s \\(title, description\\) VALUES \\(\\$1, \\$2\\) RETURNING id", repository.ItemsTable)
-	qyeryCreateListsItems    = fmt.Sprintf("INSERT INTO %s \\(list_id, item_id\\) VALUES \\(\\$1, \\$2\\)", repository.ListsItemsTable)
-	queryGetAllItems         = fmt.Sprintf("SELECT i.id, i.title, i.description, i.done FROM %s i JOIN %s li ON i.id = li.item_id WHERE li.list_id = \\$1", repository.ItemsTable, repository.ListsItemsTable)
-	queryGetItemById         = fmt.Sprintf("SELECT id, title, description, done FROM %s WHERE id = \\$1", repository.ItemsTable)
-	queryGetManyItemsByIds   = fmt.Sprintf("SELECT id, title, description, done FROM %s WHERE id IN \\(\\$1, \\$2\\)", repository.ItemsTable)
+	queryCreateItem          = fmt.Sprintf("INSERT INTO %s \\(title, description, due_at, remind_at, series_id, occurrence, parent_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) RETURNING id, version", repository.ItemsTable)
+	qyeryCreateListsItems    = fmt.Sprintf("INSERT INTO %s \\(list_id, item_id, position\\) VALUES \\(\\$1, \\$2, \\$3\\)", repository.ListsItemsTable)
+	queryGetAllItems         = fmt.Sprintf("SELECT %s FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE li.list_id = \\$1 AND i.deleted_at IS NULL ORDER BY li.position, i.id", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryGetItemsPage        = fmt.Sprintf("SELECT %s, \\(li.position\\)::text FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE li.list_id = \\$1 AND i.deleted_at IS NULL ORDER BY li.position ASC, i.id ASC LIMIT \\$2", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryGetDoneItemsPage    = fmt.Sprintf("SELECT %s, \\(COALESCE\\(i.due_at, '-infinity'\\)\\)::text FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE li.list_id = \\$1 AND i.deleted_at IS NULL AND i.done = \\$2 AND \\(COALESCE\\(i.due_at, '-infinity'\\), i.id\\) < \\(\\$3, \\$4\\) ORDER BY COALESCE\\(i.due_at, '-infinity'\\) DESC, i.id DESC LIMIT \\$5", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryGetItemById         = fmt.Sprintf("SELECT %s, i.version FROM %s i LEFT JOIN %s s ON s.id = i.series_id WHERE i.id = \\$1 AND i.deleted_at IS NULL", itemColumns, repository.ItemsTable, repository.ItemSeriesTable)
+	queryGetManyItemsByIds   = fmt.Sprintf("SELECT %s FROM %s i JOIN %s li ON i.id = li.item_id JOIN %s l ON l.id = li.list_id LEFT JOIN %s s ON s.id = i.series_id WHERE i.id IN \\(\\$1, \\$2\\) AND i.deleted_at IS NULL AND l.deleted_at IS NULL", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ListsTable, repository.ItemSeriesTable)
 	queryUpdateItemById      = fmt.Sprintf("UPDATE %s SET title = \\$1, description = \\$2, done = \\$3 WHERE id = \\$4", repository.ItemsTable)
 	queryUpdateTitleItemById = fmt.Sprintf("UPDATE %s SET title = \\$1 WHERE id = \\$2", repository.ItemsTable)
-	queryDeleteItemById      = fmt.Sprintf("DELETE FROM %s WHERE id = \\$1", repository.ItemsTable)
-	queryIsUserOwnerOfItem   = fmt.Sprintf("SELECT 1 FROM %s li JOIN %s ul ON li.list_id = ul.list_id WHERE ul.user_id = \\$1 AND li.item_id = \\$2", repository.ListsItemsTable, repository.UsersListsTable)
+	queryTrashItem           = fmt.Sprintf("WITH RECURSIVE trashed AS \\( SELECT id FROM %s WHERE id = \\$1 AND deleted_at IS NULL UNION SELECT i.id FROM %s i JOIN trashed t ON i.parent_id = t.id WHERE i.deleted_at IS NULL \\) UPDATE %s i SET deleted_at = NOW\\(\\) FROM %s li WHERE li.item_id = i.id AND i.id IN \\(SELECT id FROM trashed\\) RETURNING i.id, li.list_id", repository.ItemsTable, repository.ItemsTable, repository.ItemsTable, repository.ListsItemsTable)
+	queryUpdateDueItemById   = fmt.Sprintf("UPDATE %s SET due_at = \\$1, remind_at = \\$2, reminder_sent_at = NULL WHERE id = \\$3", repository.ItemsTable)
+	queryClaimDueReminders   = fmt.Sprintf("UPDATE %s i SET reminder_sent_at = NOW\\(\\) FROM %s li WHERE li.item_id = i.id AND i.id IN \\( SELECT ri.id FROM %s ri WHERE ri.remind_at <= NOW\\(\\) AND ri.reminder_sent_at IS NULL AND ri.done = FALSE AND ri.deleted_at IS NULL AND NOT EXISTS \\( SELECT 1 FROM %s rli JOIN %s rl ON rl.id = rli.list_id WHERE rli.item_id = ri.id AND rl.deleted_at IS NOT NULL \\) ORDER BY ri.remind_at LIMIT \\$1 FOR UPDATE SKIP LOCKED \\) RETURNING li.list_id, i.id, i.title, i.due_at, i.remind_at", repository.ItemsTable, repository.ListsItemsTable, repository.ItemsTable, repository.ListsItemsTable, repository.ListsTable)
+	queryCreateSeries        = fmt.Sprintf("INSERT INTO %s \\(rule, timezone, title, description, starts_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id", repository.ItemSeriesTable)
+	queryStartSeries         = fmt.Sprintf("UPDATE %s SET series_id = \\$1, occurrence = 1, version = version \\+ 1 WHERE id = \\$2", repository.ItemsTable)
+	queryUpdateSeriesTitle   = fmt.Sprintf("UPDATE %s SET title = \\$1 WHERE id = \\$2", repository.ItemSeriesTable)
+	queryUpdateSeriesRule    = fmt.Sprintf("UPDATE %s SET rule = \\$1, timezone = \\$2, starts_at = \\$3 WHERE id = \\$4", repository.ItemSeriesTable)
+	queryBumpSeriesVersions  = fmt.Sprintf("UPDATE %s i SET version = i.version \\+ 1 FROM %s li WHERE i.series_id = \\$1 AND li.item_id = i.id RETURNING i.id, li.list_id", repository.ItemsTable, repository.ListsItemsTable)
+	queryStopSeries          = fmt.Sprintf("UPDATE %s SET series_id = NULL, occurrence = NULL, version = version \\+ 1 WHERE id = \\$1", repository.ItemsTable)
+	queryGetItemListId       = fmt.Sprintf("SELECT li.list_id FROM %s li JOIN %s i ON i.id = li.item_id WHERE li.item_id = \\$1 AND i.deleted_at IS NULL", repository.ListsItemsTable, repository.ItemsTable)
+	queryCreateOccurrence    = fmt.Sprintf("INSERT INTO %s \\(title, description, due_at, remind_at, series_id, occurrence, parent_id\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7\\) ON CONFLICT \\(series_id, occurrence\\) DO NOTHING RETURNING id, version", repository.ItemsTable)
+	queryGetItemsByTags      = fmt.Sprintf("SELECT %s, \\(li.position\\)::text FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE li.list_id = \\$1 AND i.deleted_at IS NULL AND i.id IN \\( SELECT it.item_id FROM %s it JOIN %s t ON t.id = it.tag_id WHERE t.user_id = \\$2 AND t.name = ANY\\(\\$3\\) GROUP BY it.item_id HAVING COUNT\\(\\*\\) = \\$4 \\) ORDER BY li.position ASC, i.id ASC LIMIT \\$5", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable, repository.ItemTagsTable, repository.TagsTable)
+	queryGetSubtasks         = fmt.Sprintf("SELECT %s FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE i.parent_id = \\$1 AND i.deleted_at IS NULL ORDER BY li.position, i.id", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryFindParentCycle     = fmt.Sprintf("WITH RECURSIVE ancestors AS \\( SELECT id, parent_id FROM %s WHERE id = \\$1 UNION SELECT i.id, i.parent_id FROM %s i JOIN ancestors a ON i.id = a.parent_id \\) SELECT EXISTS \\(SELECT 1 FROM ancestors WHERE id = \\$2\\)", repository.ItemsTable, repository.ItemsTable)
+	querySetItemParent       = fmt.Sprintf("UPDATE %s SET parent_id = \\$1 WHERE id = \\$2", repository.ItemsTable)
+	querySelectSubtaskIds    = fmt.Sprintf("WITH RECURSIVE descendants AS \\( SELECT id FROM %s WHERE parent_id = \\$1 AND deleted_at IS NULL UNION SELECT i.id FROM %s i JOIN descendants d ON i.parent_id = d.id WHERE i.deleted_at IS NULL \\) SELECT id FROM descendants", repository.ItemsTable, repository.ItemsTable)
+	queryCompleteSubtasks    = fmt.Sprintf("UPDATE %s SET done = TRUE WHERE id = ANY\\(\\$1\\) AND done = FALSE AND deleted_at IS NULL RETURNING id", repository.ItemsTable)
+	queryGetUserItemRole     = fmt.Sprintf("SELECT ul.role FROM %s li JOIN %s ul ON li.list_id = ul.list_id JOIN %s i ON i.id = li.item_id JOIN %s l ON l.id = li.list_id WHERE ul.user_id = \\$1 AND li.item_id = \\$2 AND i.deleted_at IS NULL AND l.deleted_at IS NULL", repository.ListsItemsTable, repository.UsersListsTable, repository.ItemsTable, repository.ListsTable)
+	queryGetMovedSubtrees    = fmt.Sprintf("WITH RECURSIVE moved AS \\( SELECT id FROM %s WHERE id = ANY\\(\\$1\\) UNION SELECT i.id FROM %s i JOIN moved m ON i.parent_id = m.id \\) SELECT li.item_id, li.list_id FROM %s li JOIN moved m ON m.id = li.item_id WHERE li.list_id <> \\$2 ORDER BY li.list_id, li.position", repository.ItemsTable, repository.ItemsTable, repository.ListsItemsTable)
+	queryMoveListItem        = fmt.Sprintf("UPDATE %s SET list_id = \\$1, position = \\$2 WHERE item_id = \\$3", repository.ListsItemsTable)
+	queryDetachMovedItems    = fmt.Sprintf("UPDATE %s i SET parent_id = NULL FROM %s pli WHERE i.id = ANY\\(\\$1\\) AND pli.item_id = i.parent_id AND pli.list_id <> \\$2", repository.ItemsTable, repository.ListsItemsTable)
+	queryGetCopiedSubtrees   = fmt.Sprintf("WITH RECURSIVE copied AS \\( SELECT id FROM %s WHERE id = ANY\\(\\$1\\) AND deleted_at IS NULL UNION SELECT i.id FROM %s i JOIN copied c ON i.parent_id = c.id WHERE i.deleted_at IS NULL \\) SELECT %s FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE i.id IN \\(SELECT id FROM copied\\) ORDER BY li.list_id, li.position, i.id", repository.ItemsTable, repository.ItemsTable, itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryLockItemVersion     = fmt.Sprintf("SELECT version FROM %s WHERE id = \\$1 AND deleted_at IS NULL FOR UPDATE", repository.ItemsTable)
+	queryItemExists          = fmt.Sprintf("SELECT EXISTS \\(SELECT 1 FROM %s WHERE id = \\$1 AND deleted_at IS NULL\\)", repository.ItemsTable)
+	queryTrashItemVersion    = fmt.Sprintf("WITH RECURSIVE trashed AS \\( SELECT id FROM %s WHERE id = \\$1 AND deleted_at IS NULL AND version = \\$2 UNION", repository.ItemsTable)
+	queryLockItemStates      = fmt.Sprintf("SELECT i.id, i.title, i.description, i.done, i.due_at, i.remind_at, li.list_id, i.parent_id, s.rule, s.timezone FROM %s i JOIN %s li ON li.item_id = i.id LEFT JOIN %s s ON s.id = i.series_id WHERE i.id = ANY\\(\\$1\\) AND i.deleted_at IS NULL ORDER BY i.id FOR UPDATE OF i", repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	querySeriesOccurrences   = fmt.Sprintf("SELECT id FROM %s WHERE series_id = \\$1 AND id <> \\$2 ORDER BY id", repository.ItemsTable)
+	queryRevertItem          = fmt.Sprintf("UPDATE %s SET title = \\$1, description = \\$2, done = \\$3, due_at = \\$4, remind_at = \\$5, reminder_sent_at = CASE WHEN remind_at IS DISTINCT FROM \\$5 THEN NULL ELSE reminder_sent_at END WHERE id = \\$6", repository.ItemsTable)
+	queryInsertItemRevision  = fmt.Sprintf("INSERT INTO %s \\(item_id, user_id, before, after\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)", repository.ItemRevisionsTable)
+	queryGetTrashedItems     = fmt.Sprintf("SELECT %s, li.list_id, i.deleted_at FROM %s i JOIN %s li ON i.id = li.item_id LEFT JOIN %s s ON s.id = i.series_id WHERE li.list_id = \\$1 AND i.deleted_at IS NOT NULL ORDER BY i.deleted_at DESC, li.position, i.id", itemColumns, repository.ItemsTable, repository.ListsItemsTable, repository.ItemSeriesTable)
+	queryRestoreItem         = fmt.Sprintf("WITH RECURSIVE restored AS \\( SELECT i.id, i.deleted_at FROM %s i JOIN %s li ON i.id = li.item_id WHERE i.id = \\$1 AND li.list_id = \\$2 AND i.deleted_at IS NOT NULL UNION SELECT i.id, i.deleted_at FROM %s i JOIN restored r ON i.parent_id = r.id WHERE i.deleted_at = r.deleted_at \\) UPDATE %s SET deleted_at = NULL WHERE id IN \\(SELECT id FROM restored\\) RETURNING id", repository.ItemsTable, repository.ListsItemsTable, repository.ItemsTable, repository.ItemsTable)
+	queryDetachRestoredItem  = fmt.Sprintf("UPDATE %s i SET parent_id = NULL FROM %s p WHERE i.id = \\$1 AND p.id = i.parent_id AND p.deleted_at IS NOT NULL", repository.ItemsTable, repository.ItemsTable)
+	queryPurgeItems          = fmt.Sprintf("DELETE FROM %s WHERE deleted_at < \\$1 RETURNING id", repository.ItemsTable)
+	queryGetUserItemsAccess  = fmt.Sprintf("SELECT li.item_id, li.list_id, ul.role FROM %s li JOIN %s ul ON li.list_id = ul.list_id JOIN %s i ON i.id = li.item_id JOIN %s l ON l.id = li.list_id WHERE ul.user_id = \\$1 AND li.item_id = ANY\\(\\$2\\) AND i.deleted_at IS NULL AND l.deleted_at IS NULL", repository.ListsItemsTable, repository.UsersListsTable, repository.ItemsTable, repository.ListsTable)
+	queryBulkUpdateItems     = fmt.Sprintf("UPDATE %s i SET done = \\$1 FROM %s li WHERE li.item_id = i.id AND i.id = ANY\\(\\$2\\) AND i.deleted_at IS NULL RETURNING i.id, li.list_id", repository.ItemsTable, repository.ListsItemsTable)
+	queryBulkTrashItems      = fmt.Sprintf("WITH RECURSIVE trashed AS \\( SELECT id FROM %s WHERE id = ANY\\(\\$1\\) AND deleted_at IS NULL UNION SELECT i.id FROM %s i JOIN trashed t ON i.parent_id = t.id WHERE i.deleted_at IS NULL \\) UPDATE %s i SET deleted_at = NOW\\(\\) FROM %s li WHERE li.item_id = i.id AND i.id IN \\(SELECT id FROM trashed\\) RETURNING i.id, li.list_id", repository.ItemsTable, repository.ItemsTable, repository.ItemsTable, repository.ListsItemsTable)
 )
 
 // setupItemRepoTest initializes the database and repository for ItemRepo tests
@@ -56,6 +106,8 @@ func assertItemRepoExpectations(t *testing.T, mock sqlmock.Sqlmock) {
 
 // TestCreateItem tests the creation of an item in the repository
 func TestCreateItem(t *testing.T) {
+	dueAt := time.Date(2024, 10, 21, 9, 0, 0, 0, time.UTC)
+
 	testCases := []struct {
 		name        string
 		listId      int
@@ -73,12 +125,48 @@ func TestCreateItem(t *testing.T) {
 				mock.ExpectBegin()
 
 				createItemQuery := queryCreateItem
-				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description", nil, nil, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
+
+				createListItemsQuery := qyeryCreateListsItems
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 1, nil)
+				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1, "a0").WillReturnResult(sqlmock.NewResult(1, 1))
+
+				expectOutboxEvents(mock, messagebroker.ItemCreatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+				mockCache.On("Delete", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedId:  1,
+			expectedErr: nil,
+		},
+		{
+			name:   "Success_Recurring",
+			listId: 1,
+			item: entity.Item{
+				Title:      "New Item",
+				DueAt:      &dueAt,
+				Recurrence: &entity.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "Europe/Berlin"},
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(queryCreateSeries).WithArgs("FREQ=WEEKLY", "Europe/Berlin", "New Item", "", dueAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+				createItemQuery := queryCreateItem
+				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "", dueAt, nil, 7, 1, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
 
 				createListItemsQuery := qyeryCreateListsItems
-				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 1, nil)
+				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1, "a0").WillReturnResult(sqlmock.NewResult(1, 1))
 
+				expectOutboxEvents(mock, messagebroker.ItemCreatedTopic, 1)
 				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
@@ -109,7 +197,7 @@ func TestCreateItem(t *testing.T) {
 				mock.ExpectBegin()
 
 				createItemQuery := queryCreateItem
-				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description").
+				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description", nil, nil, nil, nil, nil).
 					WillReturnError(sql.ErrConnDone)
 
 				mock.ExpectRollback()
@@ -126,11 +214,12 @@ func TestCreateItem(t *testing.T) {
 				mock.ExpectBegin()
 
 				createItemQuery := queryCreateItem
-				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description", nil, nil, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
 
 				createListItemsQuery := qyeryCreateListsItems
-				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1).WillReturnError(sql.ErrConnDone)
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 1, nil)
+				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1, "a0").WillReturnError(sql.ErrConnDone)
 
 				mock.ExpectRollback()
 			},
@@ -146,12 +235,14 @@ func TestCreateItem(t *testing.T) {
 				mock.ExpectBegin()
 
 				createItemQuery := queryCreateItem
-				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(createItemQuery).WithArgs("New Item", "Item description", nil, nil, nil, nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
 
 				createListItemsQuery := qyeryCreateListsItems
-				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 1, nil)
+				mock.ExpectExec(createListItemsQuery).WithArgs(1, 1, "a0").WillReturnResult(sqlmock.NewResult(1, 1))
 
+				expectOutboxEvents(mock, messagebroker.ItemCreatedTopic, 1)
 				mock.ExpectCommit().WillReturnError(sql.ErrTxDone)
 			},
 			mockCache:   func(mockCache *MockCache) {},
@@ -170,7 +261,7 @@ func TestCreateItem(t *testing.T) {
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			itemId, err := itemRepo.CreateListItem(context.Background(), testCase.listId, &testCase.item)
+			itemId, err := itemRepo.CreateListItem(context.Background(), 1, testCase.listId, &testCase.item)
 
 			assert.Equal(t, testCase.expectedId, itemId)
 			assert.Equal(t, testCase.expectedErr, err)
@@ -181,54 +272,98 @@ func TestCreateItem(t *testing.T) {
 	}
 }
 
-// TestGetAllItems tests retrieving all items from the repository
-func TestGetAllItems(t *testing.T) {
+// TestGetListItemsPage tests retrieving a page of the items of a list from the repository
+func TestGetListItemsPage(t *testing.T) {
+	done := true
+	firstPage := entity.PageQuery{Limit: 2, Sort: entity.SortPosition, Order: entity.SortOrderAsc}
+	duePage := entity.PageQuery{Limit: 1, Sort: entity.SortDue, Order: entity.SortOrderDesc}
+	duePage.Cursor = *duePage.NextCursor("2024-11-05 18:00:00+00", 7)
+
 	testCases := []struct {
-		name          string
-		listId        int
-		mockQuery     func(mock sqlmock.Sqlmock)
-		mockCache     func(*MockCache)
-		expectedItems []entity.Item
-		expectedErr   error
+		name         string
+		query        entity.ItemQuery
+		mockQuery    func(mock sqlmock.Sqlmock)
+		mockCache    func(*MockCache)
+		expectedPage entity.ItemPage
+		expectedErr  error
 	}{
 		{
-			name:   "Success",
-			listId: 1,
+			name:  "LastPage",
+			query: entity.ItemQuery{PageQuery: firstPage},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryGetAllItems
-				mock.ExpectQuery(query).
-					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done"}).
-						AddRow(1, "Item 1", "Description 1", false).
-						AddRow(2, "Item 2", "Description 2", true))
+				mock.ExpectQuery(queryGetItemsPage).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows(itemPageRowColumns).
+						AddRow(1, "Item 1", "Description 1", false, nil, nil, nil, nil, nil, nil, nil, "1").
+						AddRow(2, "Item 2", "Description 2", true, nil, nil, nil, nil, nil, nil, nil, "2"))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
 					Return(false, nil)
-				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				mockCache.On("Set", mock.Anything, "list_items:1", mock.Anything, mock.Anything).
+					Return(nil)
+				mockCache.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "list_items:1:") && strings.HasSuffix(key, ":position:asc:2:")
+				}), mock.Anything, mock.Anything).
 					Return(nil)
 			},
-			expectedItems: []entity.Item{
+			expectedPage: entity.ItemPage{Items: []entity.Item{
 				{Id: 1, Title: "Item 1", Description: "Description 1", Done: false},
 				{Id: 2, Title: "Item 2", Description: "Description 2", Done: true},
+			}},
+			expectedErr: nil,
+		},
+		{
+			name:  "NextPage",
+			query: entity.ItemQuery{PageQuery: duePage, Done: &done},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetDoneItemsPage).
+					WithArgs(1, true, "2024-11-05 18:00:00+00", 7, 2).
+					WillReturnRows(sqlmock.NewRows(itemPageRowColumns).
+						AddRow(5, "Item 5", "", true, nil, nil, nil, nil, nil, nil, nil, "2024-11-04 18:00:00+00").
+						AddRow(3, "Item 3", "", true, nil, nil, nil, nil, nil, nil, nil, "2024-11-03 18:00:00+00"))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
+					Return(false, nil)
+				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedPage: entity.ItemPage{
+				Items:      []entity.Item{{Id: 5, Title: "Item 5", Done: true}},
+				NextCursor: duePage.NextCursor("2024-11-04 18:00:00+00", 5),
 			},
 			expectedErr: nil,
 		},
 		{
-			name:   "QueryError",
-			listId: 1,
+			name:      "InvalidCursor",
+			query:     entity.ItemQuery{PageQuery: entity.PageQuery{Limit: 2, Sort: entity.SortTitle, Order: entity.SortOrderAsc, Cursor: duePage.Cursor}},
+			mockQuery: func(mock sqlmock.Sqlmock) {},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
+					Return(false, nil)
+				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedPage: entity.ItemPage{},
+			expectedErr:  utils.ErrInvalidPageCursor,
+		},
+		{
+			name:  "QueryError",
+			query: entity.ItemQuery{PageQuery: firstPage},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryGetAllItems
-				mock.ExpectQuery(query).
-					WithArgs(1).
+				mock.ExpectQuery(queryGetItemsPage).
+					WithArgs(1, 3).
 					WillReturnError(sql.ErrConnDone)
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
 					Return(false, nil)
+				mockCache.On("Set", mock.Anything, "list_items:1", mock.Anything, mock.Anything).
+					Return(nil)
 			},
-			expectedItems: nil,
-			expectedErr:   sql.ErrConnDone,
+			expectedPage: entity.ItemPage{},
+			expectedErr:  sql.ErrConnDone,
 		},
 	}
 
@@ -242,20 +377,24 @@ func TestGetAllItems(t *testing.T) {
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			items, err := itemRepo.GetAllListItems(context.Background(), testCase.listId)
+			page, err := itemRepo.GetListItemsPage(context.Background(), 2, 1, testCase.query)
 
-			assert.Equal(t, testCase.expectedItems, items)
+			assert.Equal(t, testCase.expectedPage, page)
 			assert.Equal(t, testCase.expectedErr, err)
 
 			assertItemRepoExpectations(t, mock)
 			mockCache.AssertExpectations(t)
-			mockCache.AssertExpectations(t)
 		})
 	}
 }
 
 // TestGetItemById tests retrieving an item by its ID from the repository
 func TestGetItemById(t *testing.T) {
+	dueAt := time.Date(2024, 11, 5, 18, 0, 0, 0, time.FixedZone("UTC+5", 5*60*60))
+	remindAt := dueAt.Add(-time.Hour)
+	seriesId := 5
+	occurrence := 2
+
 	testCases := []struct {
 		name         string
 		itemId       int
@@ -271,8 +410,27 @@ func TestGetItemById(t *testing.T) {
 				query := queryGetItemById
 				mock.ExpectQuery(query).
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done"}).
-						AddRow(1, "Item 1", "Description 1", false))
+					WillReturnRows(sqlmock.NewRows(itemVersionRowColumns).
+						AddRow(1, "Item 1", "Description 1", false, nil, nil, nil, nil, nil, nil, nil, 3))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
+					Return(false, nil)
+				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedItem: entity.Item{Id: 1, Title: "Item 1", Description: "Description 1", Done: false, Version: 3},
+			expectedErr:  nil,
+		},
+		{
+			name:   "Success_WithDueDate",
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryGetItemById
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(itemVersionRowColumns).
+						AddRow(1, "Item 1", "Description 1", false, dueAt, remindAt, nil, nil, nil, nil, nil, 1))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
@@ -280,9 +438,37 @@ func TestGetItemById(t *testing.T) {
 				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil)
 			},
-			expectedItem: entity.Item{Id: 1, Title: "Item 1", Description: "Description 1", Done: false},
+			expectedItem: entity.Item{Id: 1, Title: "Item 1", Description: "Description 1", DueAt: &dueAt, RemindAt: &remindAt, Version: 1},
 			expectedErr:  nil,
 		},
+		{
+			name:   "Success_Recurring",
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryGetItemById
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(itemVersionRowColumns).
+						AddRow(1, "Item 1", "Description 1", false, dueAt, nil, 5, 2, nil, "FREQ=DAILY", "Asia/Almaty", 1))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
+					Return(false, nil)
+				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedItem: entity.Item{
+				Id:          1,
+				Title:       "Item 1",
+				Description: "Description 1",
+				DueAt:       &dueAt,
+				Recurrence:  &entity.Recurrence{Rule: "FREQ=DAILY", Timezone: "Asia/Almaty"},
+				SeriesId:    &seriesId,
+				Occurrence:  &occurrence,
+				Version:     1,
+			},
+			expectedErr: nil,
+		},
 		{
 			name:   "ItemNotFound",
 			itemId: 999,
@@ -353,9 +539,9 @@ func TestItemRepo_GetManyByIds(t *testing.T) {
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(queryGetManyItemsByIds).
 					WithArgs(1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done"}).
-						AddRow(1, "Item 1", "Description 1", false).
-						AddRow(2, "Item 2", "Description 2", true))
+					WillReturnRows(sqlmock.NewRows(itemRowColumns).
+						AddRow(1, "Item 1", "Description 1", false, nil, nil, nil, nil, nil, nil, nil).
+						AddRow(2, "Item 2", "Description 2", true, nil, nil, nil, nil, nil, nil, nil))
 			},
 			expectedItems: []entity.Item{
 				{Id: 1, Title: "Item 1", Description: "Description 1", Done: false},
@@ -387,8 +573,8 @@ func TestItemRepo_GetManyByIds(t *testing.T) {
 			mockQuery: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(queryGetManyItemsByIds).
 					WithArgs(1, 2).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done"}).
-						AddRow("invalid", "Item 1", "Description 1", false))
+					WillReturnRows(sqlmock.NewRows(itemRowColumns).
+						AddRow("invalid", "Item 1", "Description 1", false, nil, nil, nil, nil, nil, nil, nil))
 			},
 			expectedItems: nil,
 			expectedErr:   fmt.Errorf("sql: Scan error"),
@@ -427,18 +613,29 @@ func TestUpdateItemById(t *testing.T) {
 	title := "Updated Title"
 	description := "Updated Description"
 	doneFlag := true
+	dueAt := time.Date(2024, 11, 5, 18, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+
+	version := 4
+
+	weekly := entity.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "UTC"}
+	first := entity.Item{Title: "Water the plants", DueAt: &dueAt, Recurrence: &weekly}
 
 	testCases := []struct {
 		name        string
+		userId      int
 		listId      int
 		itemId      int
 		updateInput entity.UpdateItemInput
+		series      *entity.ItemSeriesChange
+		version     *int
 		mockQuery   func(mock sqlmock.Sqlmock)
 		mockCache   func(*MockCache)
 		expectedErr error
 	}{
 		{
 			name:   "Success_AllFieldsUpdated",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
 			updateInput: entity.UpdateItemInput{
@@ -447,29 +644,83 @@ func TestUpdateItemById(t *testing.T) {
 				Done:        &doneFlag,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateItemById).
 					WithArgs("Updated Title", "Updated Description", true, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Updated Title", "Updated Description", true, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2,
+						[]byte(`{"title":"Title","description":"","done":false,"due_at":null,"remind_at":null,"list_id":3,"parent_id":null,"recurrence":null}`),
+						[]byte(`{"title":"Updated Title","description":"Updated Description","done":true,"due_at":null,"remind_at":null,"list_id":3,"parent_id":null,"recurrence":null}`)).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
-				mockCache.On("Delete", mock.Anything, mock.Anything).
-					Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
 			},
 			expectedErr: nil,
 		},
 		{
-			name:   "Success_TitleOnly",
+			name:   "Success_Unchanged",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
 			updateInput: entity.UpdateItemInput{
 				Title: &title,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateTitleItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Updated Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateTitleItemById).
 					WithArgs("Updated Title", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Updated Title", "", false, nil, nil, 3, nil, nil, nil))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "Success_DueDateAndReminder",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				DueAt:    &dueAt,
+				RemindAt: &remindAt,
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateDueItemById).
+					WithArgs(dueAt, remindAt, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, dueAt, remindAt, 3, nil, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -479,6 +730,7 @@ func TestUpdateItemById(t *testing.T) {
 		},
 		{
 			name:        "NoFieldsToUpdate",
+			userId:      2,
 			listId:      3,
 			itemId:      1,
 			updateInput: entity.UpdateItemInput{},
@@ -486,63 +738,88 @@ func TestUpdateItemById(t *testing.T) {
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: utils.ErrItemEmptyRequest,
 		},
+		{
+			name:   "ItemNotFound",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:   "ItemInAnotherList",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 7, nil, nil, nil))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
 		{
 			name:   "DatabaseError",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
 			updateInput: entity.UpdateItemInput{
 				Title: &title,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateTitleItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateTitleItemById).
 					WithArgs("Updated Title", 1).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: sql.ErrConnDone,
 		},
-	}
-
-	for _, testCase := range testCases {
-		testCase := testCase
-
-		t.Run(testCase.name, func(t *testing.T) {
-			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
-			defer sqlxDB.Close()
-
-			testCase.mockQuery(mock)
-			testCase.mockCache(mockCache)
-
-			err := itemRepo.UpdateOneById(context.Background(), &testCase.listId, testCase.itemId, testCase.updateInput)
-
-			assert.Equal(t, testCase.expectedErr, err)
-
-			assertItemRepoExpectations(t, mock)
-			mockCache.AssertExpectations(t)
-		})
-	}
-}
-
-// TestDeleteOneById tests deleting an item by its ID in the repository
-func TestDeleteOneById(t *testing.T) {
-	testCases := []struct {
-		name        string
-		itemId      int
-		listId      int
-		mockQuery   func(mock sqlmock.Sqlmock)
-		mockCache   func(*MockCache)
-		expectedErr error
-	}{
 		{
-			name:   "Success",
+			name:   "Success_VersionMatches",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			version: &version,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemVersion).
 					WithArgs(1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Updated Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateTitleItemById).
+					WithArgs("Updated Title", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Updated Title", "", false, nil, nil, 3, nil, nil, nil))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -551,103 +828,481 @@ func TestDeleteOneById(t *testing.T) {
 			expectedErr: nil,
 		},
 		{
-			name:   "Item not found",
+			name:   "VersionMismatch",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			version: &version,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemVersion).
 					WithArgs(1).
-					WillReturnError(utils.ErrItemNotFound)
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+				mock.ExpectRollback()
 			},
-			mockCache: func(mockCache *MockCache) {
-				mockCache.On("Delete", mock.Anything, mock.Anything).
-					Return(nil)
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrVersionMismatch,
+		},
+		{
+			name:   "VersionItemNotFound",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemVersion).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
+			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: utils.ErrItemNotFound,
 		},
 		{
-			name:   "QueryError",
+			name:   "Success_StartSeries",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			series: &entity.ItemSeriesChange{Action: entity.ItemSeriesStart, First: first},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Water the plants", "", false, dueAt, nil, 3, nil, nil, nil))
+				mock.ExpectQuery(queryCreateSeries).
+					WithArgs("FREQ=WEEKLY", "UTC", "Water the plants", "", dueAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectExec(queryStartSeries).
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Water the plants", "", false, dueAt, nil, 3, nil, "FREQ=WEEKLY", "UTC"))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2,
+						[]byte(`{"title":"Water the plants","description":"","done":false,"due_at":"2024-11-05T18:00:00Z","remind_at":null,"list_id":3,"parent_id":null,"recurrence":null}`),
+						[]byte(`{"title":"Water the plants","description":"","done":false,"due_at":"2024-11-05T18:00:00Z","remind_at":null,"list_id":3,"parent_id":null,"recurrence":{"rule":"FREQ=WEEKLY","timezone":"UTC"}}`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "Success_UpdateSeriesUnderVersion",
+			userId: 2,
 			listId: 3,
 			itemId: 1,
+			updateInput: entity.UpdateItemInput{
+				Title: &title,
+			},
+			series:  &entity.ItemSeriesChange{Action: entity.ItemSeriesUpdate, SeriesId: 5, Input: entity.UpdateItemInput{Title: &title}},
+			version: &version,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteItemById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemVersion).
 					WithArgs(1).
-					WillReturnError(sql.ErrConnDone)
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+				mock.ExpectQuery(querySeriesOccurrences).
+					WithArgs(5, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Title", "", true, nil, nil, 3, nil, "FREQ=WEEKLY", "UTC").
+						AddRow(2, "Title", "", false, nil, nil, 4, nil, "FREQ=WEEKLY", "UTC"))
+				mock.ExpectExec(queryUpdateSeriesTitle).
+					WithArgs(title, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryBumpSeriesVersions).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(1, 3).AddRow(2, 4))
+				mock.ExpectExec(queryUpdateTitleItemById).
+					WithArgs("Updated Title", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Updated Title", "", true, nil, nil, 3, nil, "FREQ=WEEKLY", "UTC").
+						AddRow(2, "Title", "", false, nil, nil, 4, nil, "FREQ=WEEKLY", "UTC"))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
-				mockCache.On("Delete", mock.Anything, mock.Anything).
-					Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:4").Return(nil)
 			},
-			expectedErr: sql.ErrConnDone,
+			expectedErr: nil,
 		},
-	}
-
-	for _, testCase := range testCases {
-		testCase := testCase
-
-		t.Run(testCase.name, func(t *testing.T) {
-			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
-			defer sqlxDB.Close()
-
+		{
+			name:   "Success_UpdateSeriesRecurrence",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			series: &entity.ItemSeriesChange{
+				Action:   entity.ItemSeriesUpdate,
+				SeriesId: 5,
+				Input:    entity.UpdateItemInput{Recurrence: &entity.Recurrence{Rule: "FREQ=DAILY", Timezone: "UTC"}, DueAt: &dueAt},
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySeriesOccurrences).
+					WithArgs(5, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Title", "", true, dueAt, nil, 3, nil, "FREQ=WEEKLY", "UTC").
+						AddRow(2, "Title", "", false, nil, nil, 4, nil, "FREQ=WEEKLY", "UTC"))
+				mock.ExpectExec(queryUpdateSeriesRule).
+					WithArgs("FREQ=DAILY", "UTC", dueAt, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryBumpSeriesVersions).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(1, 3).AddRow(2, 4))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Title", "", true, dueAt, nil, 3, nil, "FREQ=DAILY", "UTC").
+						AddRow(2, "Title", "", false, nil, nil, 4, nil, "FREQ=DAILY", "UTC"))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(2, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 2)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "SeriesVersionMismatch",
+			userId:  2,
+			listId:  3,
+			itemId:  1,
+			series:  &entity.ItemSeriesChange{Action: entity.ItemSeriesUpdate, SeriesId: 5, Input: entity.UpdateItemInput{Title: &title}},
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemVersion).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrVersionMismatch,
+		},
+		{
+			name:   "SeriesNotFound",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			series: &entity.ItemSeriesChange{Action: entity.ItemSeriesUpdate, SeriesId: 5, Input: entity.UpdateItemInput{Title: &title}},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySeriesOccurrences).
+					WithArgs(5, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryUpdateSeriesTitle).
+					WithArgs(title, 5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:   "Success_StopSeries",
+			userId: 2,
+			listId: 3,
+			itemId: 1,
+			series: &entity.ItemSeriesChange{Action: entity.ItemSeriesStop},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, "FREQ=WEEKLY", "UTC"))
+				mock.ExpectExec(queryStopSeries).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Title", "", false, nil, nil, 3, nil, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			err := itemRepo.DeleteOneById(context.Background(), &testCase.listId, testCase.itemId)
+			err := itemRepo.UpdateOneById(context.Background(), testCase.userId, &testCase.listId, testCase.itemId, testCase.updateInput, testCase.series, testCase.version)
 
 			assert.Equal(t, testCase.expectedErr, err)
 
 			assertItemRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
 		})
 	}
 }
 
-// TestIsUserOwnerOfItem tests whether a user is the owner of an item in the repository
-func TestIsUserOwnerOfItem(t *testing.T) {
+// TestRevertItemById tests setting an item back to the state of a revision
+func TestRevertItemById(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	dueAt := time.Date(2024, 11, 5, 18, 0, 0, 0, time.UTC)
+	state := entity.ItemState{Title: "Old Title", DueAt: &dueAt}
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryLockItemStates).
+		WithArgs(pq.Array([]int{1})).
+		WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "New Title", "", true, nil, nil, 3, nil, nil, nil))
+	sqlMock.ExpectExec(queryRevertItem).
+		WithArgs("Old Title", "", false, &dueAt, nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectQuery(queryLockItemStates).
+		WithArgs(pq.Array([]int{1})).
+		WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Old Title", "", false, dueAt, nil, 3, nil, nil, nil))
+	sqlMock.ExpectExec(queryInsertItemRevision).
+		WithArgs(1, 2,
+			[]byte(`{"title":"New Title","description":"","done":true,"due_at":null,"remind_at":null,"list_id":3,"parent_id":null,"recurrence":null}`),
+			[]byte(`{"title":"Old Title","description":"","done":false,"due_at":"2024-11-05T18:00:00Z","remind_at":null,"list_id":3,"parent_id":null,"recurrence":null}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutboxEvents(sqlMock, messagebroker.ItemUpdatedTopic, 1)
+	sqlMock.ExpectCommit()
+
+	mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+	mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+
+	err := itemRepo.RevertOneById(context.Background(), 2, 3, 1, state)
+
+	assert.NoError(t, err)
+	assertItemRepoExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
+
+// TestDeleteOneById tests deleting an item by its ID in the repository
+func TestDeleteOneById(t *testing.T) {
+	version := 2
+
 	testCases := []struct {
-		name        string
-		userId      int
-		itemId      int
-		mockQuery   func(sqlmock.Sqlmock)
-		mockCache   func(*MockCache)
-		expectedErr error
+		name               string
+		itemId             int
+		version            *int
+		mockQuery          func(mock sqlmock.Sqlmock)
+		mockCache          func(*MockCache)
+		expectedTrashedIds []int
+		expectedErr        error
+	}{
+		{
+			name:   "Success",
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := queryTrashItem
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(1, 3).AddRow(2, 3))
+				expectOutboxEvents(mock, messagebroker.ItemDeletedTopic, 2)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedTrashedIds: []int{1, 2},
+			expectedErr:        nil,
+		},
+		{
+			name:   "Item not found",
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := queryTrashItem
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:   "QueryError",
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				query := queryTrashItem
+				mock.ExpectQuery(query).
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+		{
+			name:    "Success_VersionMatches",
+			itemId:  1,
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryTrashItemVersion).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(1, 3))
+				expectOutboxEvents(mock, messagebroker.ItemDeletedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedTrashedIds: []int{1},
+			expectedErr:        nil,
+		},
+		{
+			name:    "VersionMismatch",
+			itemId:  1,
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryTrashItemVersion).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}))
+				mock.ExpectRollback()
+				mock.ExpectQuery(queryItemExists).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrVersionMismatch,
+		},
+		{
+			name:    "VersionItemNotFound",
+			itemId:  1,
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryTrashItemVersion).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}))
+				mock.ExpectRollback()
+				mock.ExpectQuery(queryItemExists).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			trashedIds, err := itemRepo.DeleteOneById(context.Background(), testCase.itemId, testCase.version)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedTrashedIds, trashedIds)
+
+			assertItemRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetUserItemRole tests retrieving the role of a user on the list holding an item
+func TestGetUserItemRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		userId       int
+		itemId       int
+		mockQuery    func(sqlmock.Sqlmock)
+		expectedRole string
+		expectedErr  error
 	}{
 		{
 			name:   "Success",
 			userId: 1,
 			itemId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfItem
+				query := queryGetUserItemRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
-					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
 			},
-			mockCache:   func(mockCache *MockCache) {},
-			expectedErr: nil,
+			expectedRole: entity.ListRoleEditor,
+			expectedErr:  nil,
 		},
 		{
-			name:   "Item not belongs to user",
+			name:   "Item not shared with user",
 			userId: 1,
 			itemId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfItem
+				query := queryGetUserItemRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
-					WillReturnError(utils.ErrUserNotOwner)
+					WillReturnError(sql.ErrNoRows)
 			},
-			mockCache:   func(mockCache *MockCache) {},
-			expectedErr: utils.ErrUserNotOwner,
+			expectedRole: "",
+			expectedErr:  utils.ErrUserNotOwner,
 		},
 		{
 			name:   "DatabaseError",
 			userId: 1,
 			itemId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfItem
+				query := queryGetUserItemRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
 					WillReturnError(sql.ErrConnDone)
 			},
-			mockCache:   func(mockCache *MockCache) {},
-			expectedErr: sql.ErrConnDone,
+			expectedRole: "",
+			expectedErr:  sql.ErrConnDone,
 		},
 	}
 
@@ -657,17 +1312,1131 @@ func TestIsUserOwnerOfItem(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
+			sqlxDB, mock, itemRepo, _ := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			role, err := itemRepo.GetUserItemRole(context.Background(), testCase.userId, testCase.itemId)
+
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetUserItemsAccess tests retrieving the roles of a user on the lists holding many items with a single query
+func TestGetUserItemsAccess(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, _ := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectQuery(queryGetUserItemsAccess).
+		WithArgs(1, pq.Array([]int{4, 5, 6})).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "list_id", "role"}).
+			AddRow(4, 2, entity.ListRoleOwner).
+			AddRow(5, 3, entity.ListRoleViewer))
+
+	accesses, err := itemRepo.GetUserItemsAccess(context.Background(), 1, []int{4, 5, 6})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ItemAccess{
+		{ItemId: 4, ListId: 2, Role: entity.ListRoleOwner},
+		{ItemId: 5, ListId: 3, Role: entity.ListRoleViewer},
+	}, accesses)
+	assertItemRepoExpectations(t, sqlMock)
+}
+
+// TestUpdateManyItemsByIds tests updating many items in a single transaction
+func TestUpdateManyItemsByIds(t *testing.T) {
+	done := true
+	input := entity.UpdateItemInput{Done: &done}
+
+	updatedColumns := []string{"id", "list_id"}
+
+	testCases := []struct {
+		name               string
+		mockQuery          func(mock sqlmock.Sqlmock)
+		mockCache          func(*MockCache)
+		expectedUpdatedIds []int
+		expectedErr        error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2, 3})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "First", "", false, nil, nil, 7, nil, nil, nil).
+						AddRow(2, "Second", "", true, nil, nil, 7, nil, nil, nil))
+				mock.ExpectQuery(queryBulkUpdateItems).
+					WithArgs(true, pq.Array([]int{1, 2, 3})).
+					WillReturnRows(sqlmock.NewRows(updatedColumns).AddRow(1, 7).AddRow(2, 7))
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "First", "", true, nil, nil, 7, nil, nil, nil).
+						AddRow(2, "Second", "", true, nil, nil, 7, nil, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 9,
+						[]byte(`{"title":"First","description":"","done":false,"due_at":null,"remind_at":null,"list_id":7,"parent_id":null,"recurrence":null}`),
+						[]byte(`{"title":"First","description":"","done":true,"due_at":null,"remind_at":null,"list_id":7,"parent_id":null,"recurrence":null}`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 2)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil).Once()
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil).Once()
+				mockCache.On("Delete", mock.Anything, "list_items:7").Return(nil).Once()
+			},
+			expectedUpdatedIds: []int{1, 2},
+			expectedErr:        nil,
+		},
+		{
+			name: "Success_NothingToUpdate",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2, 3})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns))
+				mock.ExpectRollback()
+			},
+			mockCache:          func(mockCache *MockCache) {},
+			expectedUpdatedIds: []int{},
+			expectedErr:        nil,
+		},
+		{
+			name: "UpdateError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryLockItemStates).
+					WithArgs(pq.Array([]int{1, 2, 3})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "First", "", false, nil, nil, 7, nil, nil, nil))
+				mock.ExpectQuery(queryBulkUpdateItems).
+					WithArgs(true, pq.Array([]int{1, 2, 3})).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
 			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
 			defer sqlxDB.Close()
 
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			err := itemRepo.IsUserOwnerOfItem(context.Background(), testCase.userId, testCase.itemId)
+			updatedIds, err := itemRepo.UpdateManyByIds(context.Background(), 9, []int{1, 2, 3}, input)
 
 			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedUpdatedIds, updatedIds)
 
 			assertItemRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
 		})
 	}
 }
+
+// TestDeleteManyItemsByIds tests moving many items to the trash with a single statement
+func TestDeleteManyItemsByIds(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryBulkTrashItems).
+		WithArgs(pq.Array([]int{1, 4})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(1, 3).AddRow(2, 3).AddRow(4, 5))
+	expectOutboxEvents(sqlMock, messagebroker.ItemDeletedTopic, 3)
+	sqlMock.ExpectCommit()
+
+	mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, "item_by_id:4").Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, "list_items:5").Return(nil).Once()
+
+	trashedIds, err := itemRepo.DeleteManyByIds(context.Background(), []int{1, 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4}, trashedIds)
+	assertItemRepoExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
+
+// TestClaimDueReminders tests claiming the due reminders of items together with their reminder due events
+func TestClaimDueReminders(t *testing.T) {
+	dueAt := time.Date(2024, 11, 5, 18, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+
+	testCases := []struct {
+		name              string
+		limit             int
+		mockQuery         func(sqlmock.Sqlmock)
+		expectedReminders []entity.ItemReminderDueEvent
+		expectedErr       error
+	}{
+		{
+			name:  "Success",
+			limit: 10,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryClaimDueReminders
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "id", "title", "due_at", "remind_at"}).
+						AddRow(3, 1, "Item 1", dueAt, remindAt).
+						AddRow(3, 2, "Item 2", nil, remindAt))
+				expectOutboxEvents(mock, messagebroker.ItemReminderDueTopic, 2)
+				mock.ExpectCommit()
+			},
+			expectedReminders: []entity.ItemReminderDueEvent{
+				{ListId: 3, ItemId: 1, Title: "Item 1", DueAt: &dueAt, RemindAt: remindAt},
+				{ListId: 3, ItemId: 2, Title: "Item 2", DueAt: nil, RemindAt: remindAt},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "NoDueReminders",
+			limit: 10,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryClaimDueReminders
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "id", "title", "due_at", "remind_at"}))
+				mock.ExpectCommit()
+			},
+			expectedReminders: []entity.ItemReminderDueEvent{},
+			expectedErr:       nil,
+		},
+		{
+			name:  "DatabaseError",
+			limit: 10,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryClaimDueReminders
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(10).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedReminders: nil,
+			expectedErr:       sql.ErrConnDone,
+		},
+		{
+			name:  "OutboxError",
+			limit: 10,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				query := queryClaimDueReminders
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs(10).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "id", "title", "due_at", "remind_at"}).
+						AddRow(3, 1, "Item 1", dueAt, remindAt))
+				mock.ExpectExec(queryInsertOutboxEvent).WithArgs(messagebroker.ItemReminderDueTopic, sqlmock.AnyArg()).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedReminders: nil,
+			expectedErr:       sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, itemRepo, _ := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			reminders, err := itemRepo.ClaimDueReminders(context.Background(), testCase.limit)
+
+			assert.Equal(t, testCase.expectedReminders, reminders)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestCreateNextOccurrence tests creating the next occurrence of a series
+func TestCreateNextOccurrence(t *testing.T) {
+	dueAt := time.Date(2024, 10, 28, 9, 0, 0, 0, time.UTC)
+	seriesId := 5
+	occurrence := 2
+
+	testCases := []struct {
+		name           string
+		mockQuery      func(sqlmock.Sqlmock)
+		mockCache      func(*MockCache)
+		expectedItemId int
+		expectedListId int
+		expectedErr    error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(3))
+				mock.ExpectQuery(queryCreateOccurrence).WithArgs("Item 1", "", dueAt, nil, 5, 2, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(2, 1))
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 3, nil)
+				mock.ExpectExec(qyeryCreateListsItems).WithArgs(3, 2, "a0").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemCreatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedItemId: 2,
+			expectedListId: 3,
+			expectedErr:    nil,
+		},
+		{
+			name: "AlreadyCreated",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(3))
+				mock.ExpectQuery(queryCreateOccurrence).WithArgs("Item 1", "", dueAt, nil, 5, 2, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}))
+				mock.ExpectRollback()
+			},
+			mockCache:      func(mockCache *MockCache) {},
+			expectedItemId: 0,
+			expectedListId: 3,
+			expectedErr:    nil,
+		},
+		{
+			name: "PreviousItemNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:      func(mockCache *MockCache) {},
+			expectedItemId: 0,
+			expectedListId: 0,
+			expectedErr:    utils.ErrItemNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			item := entity.Item{Title: "Item 1", DueAt: &dueAt, SeriesId: &seriesId, Occurrence: &occurrence}
+			itemId, listId, err := itemRepo.CreateNextOccurrence(context.Background(), 1, 1, &item)
+
+			assert.Equal(t, testCase.expectedItemId, itemId)
+			assert.Equal(t, testCase.expectedListId, listId)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetListItemsPageByTags tests retrieving a page of the items of a list having all of the given tags
+func TestGetListItemsPageByTags(t *testing.T) {
+	tags := []string{"work", "urgent"}
+	query := entity.ItemQuery{PageQuery: entity.PageQuery{Limit: 50, Sort: entity.SortPosition, Order: entity.SortOrderAsc}, Tags: tags}
+
+	testCases := []struct {
+		name         string
+		mockQuery    func(sqlmock.Sqlmock)
+		expectedPage entity.ItemPage
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemsByTags).WithArgs(2, 1, pq.Array(tags), 2, 51).
+					WillReturnRows(sqlmock.NewRows(itemPageRowColumns).AddRow(3, "Item 3", "", false, nil, nil, nil, nil, nil, nil, nil, "1"))
+			},
+			expectedPage: entity.ItemPage{Items: []entity.Item{{Id: 3, Title: "Item 3"}}},
+			expectedErr:  nil,
+		},
+		{
+			name: "NoMatches",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemsByTags).WithArgs(2, 1, pq.Array(tags), 2, 51).
+					WillReturnRows(sqlmock.NewRows(itemPageRowColumns))
+			},
+			expectedPage: entity.ItemPage{Items: []entity.Item{}},
+			expectedErr:  nil,
+		},
+		{
+			name: "SQLConnectionFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemsByTags).WithArgs(2, 1, pq.Array(tags), 2, 51).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedPage: entity.ItemPage{},
+			expectedErr:  sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, itemRepo, _ := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+
+			page, err := itemRepo.GetListItemsPage(context.Background(), 1, 2, query)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedPage, page)
+
+			assertItemRepoExpectations(t, sqlMock)
+		})
+	}
+}
+
+// TestCreateSubtask tests creating an item as a subtask of an item of the same list
+func TestCreateSubtask(t *testing.T) {
+	parentId := 4
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedId  int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectQuery(queryCreateItem).WithArgs("Subtask", "", nil, nil, nil, nil, parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(5, 1))
+				expectAppendPosition(mock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 1, nil)
+				mock.ExpectExec(qyeryCreateListsItems).WithArgs(1, 5, "a0").WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemCreatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Set", mock.Anything, "item_by_id:5", mock.Anything, mock.Anything).Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedId:  5,
+			expectedErr: nil,
+		},
+		{
+			name: "ParentInAnotherList",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(2))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedId:  0,
+			expectedErr: utils.ErrItemParentNotInList,
+		},
+		{
+			name: "ParentNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedId:  0,
+			expectedErr: utils.ErrItemParentNotInList,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			item := entity.Item{Title: "Subtask", ParentId: &parentId}
+			itemId, err := itemRepo.CreateListItem(context.Background(), 1, 1, &item)
+
+			assert.Equal(t, testCase.expectedId, itemId)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetSubtasks tests retrieving the direct subtasks of an item
+func TestGetSubtasks(t *testing.T) {
+	sqlxDB, mock, itemRepo, _ := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetSubtasks).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow(5, "Subtask 1", "", true, nil, nil, nil, nil, 4, nil, nil).
+			AddRow(6, "Subtask 2", "", false, nil, nil, nil, nil, 4, nil, nil))
+
+	items, err := itemRepo.GetSubtasks(context.Background(), 4)
+
+	parentId := 4
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Item{
+		{Id: 5, Title: "Subtask 1", Done: true, ParentId: &parentId},
+		{Id: 6, Title: "Subtask 2", ParentId: &parentId},
+	}, items)
+	assertItemRepoExpectations(t, mock)
+}
+
+// TestSetItemParent tests moving an item under another item, the revision of the move and the cycle prevention
+func TestSetItemParent(t *testing.T) {
+	parentId := 4
+
+	testCases := []struct {
+		name        string
+		listId      int
+		parentId    *int
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name:     "Success",
+			listId:   1,
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectQuery(queryFindParentCycle).WithArgs(parentId, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(2, "Subtask", "", false, nil, nil, 1, nil, nil, nil))
+				mock.ExpectExec(querySetItemParent).WithArgs(parentId, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(2, "Subtask", "", false, nil, nil, 1, parentId, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).
+					WithArgs(2, 1,
+						[]byte(`{"title":"Subtask","description":"","done":false,"due_at":null,"remind_at":null,"list_id":1,"parent_id":null,"recurrence":null}`),
+						[]byte(`{"title":"Subtask","description":"","done":false,"due_at":null,"remind_at":null,"list_id":1,"parent_id":4,"recurrence":null}`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "TopLevel",
+			listId:   1,
+			parentId: nil,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(2, "Subtask", "", false, nil, nil, 1, parentId, nil, nil))
+				mock.ExpectExec(querySetItemParent).WithArgs(nil, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(2, "Subtask", "", false, nil, nil, 1, nil, nil, nil))
+				mock.ExpectExec(queryInsertItemRevision).WithArgs(2, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "Cycle",
+			listId:   1,
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectQuery(queryFindParentCycle).WithArgs(parentId, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemParentCycle,
+		},
+		{
+			name:     "ParentInAnotherList",
+			listId:   1,
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(queryGetItemListId).WithArgs(parentId).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(7))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemParentNotInList,
+		},
+		{
+			name:     "ItemInAnotherList",
+			listId:   7,
+			parentId: &parentId,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:     "ItemInTrash",
+			listId:   1,
+			parentId: nil,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:     "ItemNotFound",
+			listId:   1,
+			parentId: nil,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetItemListId).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+				mock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{2})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(2, "Subtask", "", false, nil, nil, 1, nil, nil, nil))
+				mock.ExpectExec(querySetItemParent).WithArgs(nil, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			err := itemRepo.SetParent(context.Background(), 1, testCase.listId, 2, testCase.parentId)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCompleteSubtasks tests completing every open subtask of an item, a revision is recorded for every completed subtask
+func TestCompleteSubtasks(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryGetItemListId).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1))
+	sqlMock.ExpectQuery(querySelectSubtaskIds).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6).AddRow(8))
+	sqlMock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{5, 6, 8})).
+		WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+			AddRow(5, "First", "", false, nil, nil, 1, 4, nil, nil).
+			AddRow(6, "Second", "", true, nil, nil, 1, 4, nil, nil).
+			AddRow(8, "Third", "", false, nil, nil, 1, 5, nil, nil))
+	sqlMock.ExpectQuery(queryCompleteSubtasks).WithArgs(pq.Array([]int{5, 6, 8})).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(8))
+	sqlMock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{5, 8})).
+		WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+			AddRow(5, "First", "", true, nil, nil, 1, 4, nil, nil).
+			AddRow(8, "Third", "", true, nil, nil, 1, 5, nil, nil))
+	sqlMock.ExpectExec(queryInsertItemRevision).
+		WithArgs(5, 1,
+			[]byte(`{"title":"First","description":"","done":false,"due_at":null,"remind_at":null,"list_id":1,"parent_id":4,"recurrence":null}`),
+			[]byte(`{"title":"First","description":"","done":true,"due_at":null,"remind_at":null,"list_id":1,"parent_id":4,"recurrence":null}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectExec(queryInsertItemRevision).WithArgs(8, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	expectOutboxEvents(sqlMock, messagebroker.ItemUpdatedTopic, 2)
+	sqlMock.ExpectCommit()
+	mockCache.On("Delete", mock.Anything, "item_by_id:5").Return(nil)
+	mockCache.On("Delete", mock.Anything, "item_by_id:8").Return(nil)
+	mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+
+	completedIds, err := itemRepo.CompleteSubtasks(context.Background(), 1, 1, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 8}, completedIds)
+	assertItemRepoExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
+
+// TestCompleteSubtasksItemInAnotherList tests that the subtasks are only completed through the list holding the item
+func TestCompleteSubtasksItemInAnotherList(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, _ := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryGetItemListId).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(7))
+	sqlMock.ExpectRollback()
+
+	completedIds, err := itemRepo.CompleteSubtasks(context.Background(), 1, 1, 4)
+
+	assert.Equal(t, utils.ErrItemNotFound, err)
+	assert.Nil(t, completedIds)
+	assertItemRepoExpectations(t, sqlMock)
+}
+
+// TestMoveItem tests moving an item between anchors of its list, the version of a moved item changes
+func TestMoveItem(t *testing.T) {
+	afterId, beforeId := 3, 4
+
+	queryAnchor := queryAnchorPosition(repository.ListsItemsTable, "list_id", "item_id")
+	queryNext := queryNeighbourPosition("MIN", repository.ListsItemsTable, "list_id", "item_id", ">")
+	queryPrevious := queryNeighbourPosition("MAX", repository.ListsItemsTable, "list_id", "item_id", "<")
+	queryUpdate := queryUpdatePosition(repository.ListsItemsTable, "list_id", "item_id")
+
+	testCases := []struct {
+		name        string
+		input       entity.MoveInput
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name:  "AfterAnchor",
+			input: entity.MoveInput{AfterId: &afterId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, afterId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a1"))
+				sqlMock.ExpectQuery(queryNext).WithArgs(1, 2, "a1").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow("a2"))
+				sqlMock.ExpectExec(queryUpdate).WithArgs("a1V", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(sqlMock, repository.ItemsTable, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "BeforeFirstItem",
+			input: entity.MoveInput{BeforeId: &beforeId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, beforeId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a0"))
+				sqlMock.ExpectQuery(queryPrevious).WithArgs(1, 2, "a0").WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+				sqlMock.ExpectExec(queryUpdate).WithArgs("Zz", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(sqlMock, repository.ItemsTable, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "BetweenAnchors",
+			input: entity.MoveInput{AfterId: &afterId, BeforeId: &beforeId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, afterId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a1"))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, beforeId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a2"))
+				sqlMock.ExpectExec(queryUpdate).WithArgs("a1V", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(sqlMock, repository.ItemsTable, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "AnchorsOutOfOrder",
+			input: entity.MoveInput{AfterId: &afterId, BeforeId: &beforeId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, afterId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a2"))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, beforeId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a1"))
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrMoveAnchorsOrder,
+		},
+		{
+			name:  "AnchorInAnotherList",
+			input: entity.MoveInput{AfterId: &afterId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, afterId).WillReturnError(sql.ErrNoRows)
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrMoveAnchorNotFound,
+		},
+		{
+			name:  "ItemNotInList",
+			input: entity.MoveInput{AfterId: &afterId},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryAnchor).WithArgs(1, afterId).WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a1"))
+				sqlMock.ExpectQuery(queryNext).WithArgs(1, 2, "a1").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(nil))
+				sqlMock.ExpectExec(queryUpdate).WithArgs("a2", 1, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			err := itemRepo.MoveItem(context.Background(), 1, 2, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertItemRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestMoveItemsToList tests moving items with their subtasks to another list, the versions of the moved items change
+// and a revision records the move of each of them
+func TestMoveItemsToList(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mockQuery     func(sqlmock.Sqlmock)
+		mockCache     func(*MockCache)
+		expectedIds   []int
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryGetMovedSubtrees).WithArgs(pq.Array([]int{1}), 5).
+					WillReturnRows(sqlmock.NewRows([]string{"item_id", "list_id"}).AddRow(1, 2).AddRow(7, 2))
+				sqlMock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{1, 7})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Parent", "", false, nil, nil, 2, nil, nil, nil).
+						AddRow(7, "Subtask", "", false, nil, nil, 2, 1, nil, nil))
+				sqlMock.ExpectQuery(queryLastPosition(repository.ListsItemsTable, "list_id")).WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("a3"))
+				sqlMock.ExpectExec(queryMoveListItem).WithArgs(5, "a4", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectExec(queryMoveListItem).WithArgs(5, "a5", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectExec(queryDetachMovedItems).WithArgs(pq.Array([]int{1, 7}), 5).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectBumpVersions(sqlMock, repository.ItemsTable, 1, 7)
+				sqlMock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{1, 7})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).
+						AddRow(1, "Parent", "", false, nil, nil, 5, nil, nil, nil).
+						AddRow(7, "Subtask", "", false, nil, nil, 5, 1, nil, nil))
+				sqlMock.ExpectExec(queryInsertItemRevision).
+					WithArgs(1, 1,
+						[]byte(`{"title":"Parent","description":"","done":false,"due_at":null,"remind_at":null,"list_id":2,"parent_id":null,"recurrence":null}`),
+						[]byte(`{"title":"Parent","description":"","done":false,"due_at":null,"remind_at":null,"list_id":5,"parent_id":null,"recurrence":null}`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				sqlMock.ExpectExec(queryInsertItemRevision).WithArgs(7, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				expectOutboxEvents(sqlMock, messagebroker.ItemUpdatedTopic, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:7").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:5").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:2").Return(nil)
+			},
+			expectedIds:   []int{1, 7},
+			expectedError: nil,
+		},
+		{
+			name: "AlreadyInList",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryGetMovedSubtrees).WithArgs(pq.Array([]int{1}), 5).
+					WillReturnRows(sqlmock.NewRows([]string{"item_id", "list_id"}))
+				sqlMock.ExpectRollback()
+			},
+			mockCache:     func(mockCache *MockCache) {},
+			expectedIds:   []int{},
+			expectedError: nil,
+		},
+		{
+			name: "UpdateFailure",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpaceListItems, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectQuery(queryGetMovedSubtrees).WithArgs(pq.Array([]int{1}), 5).
+					WillReturnRows(sqlmock.NewRows([]string{"item_id", "list_id"}).AddRow(1, 2))
+				sqlMock.ExpectQuery(queryLockItemStates).WithArgs(pq.Array([]int{1})).
+					WillReturnRows(sqlmock.NewRows(itemStateRowColumns).AddRow(1, "Parent", "", false, nil, nil, 2, nil, nil, nil))
+				sqlMock.ExpectQuery(queryLastPosition(repository.ListsItemsTable, "list_id")).WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+				sqlMock.ExpectExec(queryMoveListItem).WithArgs(5, "a0", 1).WillReturnError(sql.ErrConnDone)
+				sqlMock.ExpectRollback()
+			},
+			mockCache:     func(mockCache *MockCache) {},
+			expectedIds:   nil,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			movedIds, err := itemRepo.MoveToList(context.Background(), 1, 5, []int{1})
+
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedIds, movedIds)
+
+			assertItemRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCopyItemsToList tests copying an item with its subtask to another list
+func TestCopyItemsToList(t *testing.T) {
+	sqlxDB, sqlMock, itemRepo, mockCache := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	dueAt := time.Date(2024, 11, 14, 9, 0, 0, 0, time.UTC)
+	parentId := 1
+	copyParentId := 20
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryGetCopiedSubtrees).WithArgs(pq.Array([]int{1})).
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow(7, "Subtask", "", true, nil, nil, nil, nil, parentId, nil, nil).
+			AddRow(1, "Item", "Description", false, dueAt, nil, 3, 2, nil, "FREQ=DAILY", "UTC"))
+
+	sqlMock.ExpectQuery(queryCreateItem).WithArgs("Item", "Description", dueAt, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(20, 1))
+	expectAppendPosition(sqlMock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 5, "a3")
+	sqlMock.ExpectExec(qyeryCreateListsItems).WithArgs(5, 20, "a4").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	sqlMock.ExpectQuery(queryCreateItem).WithArgs("Subtask", "", nil, nil, nil, nil, copyParentId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(21, 1))
+	expectAppendPosition(sqlMock, lockSpaceListItems, repository.ListsItemsTable, "list_id", 5, "a4")
+	sqlMock.ExpectExec(qyeryCreateListsItems).WithArgs(5, 21, "a5").WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutboxEvents(sqlMock, messagebroker.ItemCreatedTopic, 2)
+	sqlMock.ExpectCommit()
+
+	mockCache.On("Delete", mock.Anything, "list_items:5").Return(nil)
+
+	copies, err := itemRepo.CopyToList(context.Background(), 1, 5, []int{1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ItemCopy{
+		{SourceId: 1, Item: entity.Item{Id: 20, Title: "Item", Description: "Description", DueAt: &dueAt, Version: 1}},
+		{SourceId: 7, Item: entity.Item{Id: 21, Title: "Subtask", ParentId: &copyParentId, Version: 1}},
+	}, copies)
+
+	assertItemRepoExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
+
+// TestGetTrashedListItems tests retrieving the trashed items of a list
+func TestGetTrashedListItems(t *testing.T) {
+	sqlxDB, mock, itemRepo, _ := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	deletedAt := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(queryGetTrashedItems).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(append(itemRowColumns, "list_id", "deleted_at")).
+			AddRow(5, "Trashed item", "", false, nil, nil, nil, nil, nil, nil, nil, 3, deletedAt))
+
+	items, err := itemRepo.GetTrashedListItems(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.TrashedItem{
+		{Item: entity.Item{Id: 5, Title: "Trashed item"}, ListId: 3, DeletedAt: deletedAt},
+	}, items)
+	assertItemRepoExpectations(t, mock)
+}
+
+// TestRestoreItem tests restoring a trashed item along with the subtasks trashed with it
+func TestRestoreItem(t *testing.T) {
+	testCases := []struct {
+		name                string
+		listId              int
+		itemId              int
+		mockQuery           func(mock sqlmock.Sqlmock)
+		mockCache           func(*MockCache)
+		expectedRestoredIds []int
+		expectedErr         error
+	}{
+		{
+			name:   "Success",
+			listId: 3,
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryRestoreItem).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(queryDetachRestoredItem).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 2)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:1").Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:3").Return(nil)
+			},
+			expectedRestoredIds: []int{1, 2},
+			expectedErr:         nil,
+		},
+		{
+			name:   "NotInTrash",
+			listId: 3,
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryRestoreItem).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrItemNotFound,
+		},
+		{
+			name:   "DetachError",
+			listId: 3,
+			itemId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryRestoreItem).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(queryDetachRestoredItem).
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, itemRepo, mockCache := setupItemRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			restoredIds, err := itemRepo.RestoreItem(context.Background(), 1, testCase.listId, testCase.itemId)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedRestoredIds, restoredIds)
+
+			assertItemRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPurgeItemTrash tests permanently deleting the items trashed before a point in time
+func TestPurgeItemTrash(t *testing.T) {
+	sqlxDB, mock, itemRepo, _ := setupItemRepoTest(t)
+	defer sqlxDB.Close()
+
+	deletedBefore := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(queryPurgeItems).WithArgs(deletedBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(8))
+
+	purgedIds, err := itemRepo.PurgeTrash(context.Background(), deletedBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{7, 8}, purgedIds)
+	assertItemRepoExpectations(t, mock)
+}
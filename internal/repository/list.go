@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
 	"github.com/berikulyBeket/todo-plus/pkg/database"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
 	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ListRepo struct {
@@ -28,97 +32,257 @@ func NewListRepo(
 	return &ListRepo{db, cache, logger}
 }
 
-// CreateUserList creates a new list and links it to the user
+// CreateUserList creates a new list, links it to the user as its owner and records a created event
 func (r *ListRepo) CreateUserList(ctx context.Context, userId int, list *entity.List) (int, error) {
 	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	var listId int
+	if err := insertUserList(tx, userId, list); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
 
-	query := fmt.Sprintf(`
-		INSERT INTO %s (title, description)
-		VALUES ($1, $2)
-		RETURNING id`, ListsTable)
+	if err := insertListCreatedEvent(tx, userId, *list); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	r.cacheCreatedList(ctx, userId, list)
+
+	return list.Id, nil
+}
 
-	err = tx.QueryRow(query, list.Title, list.Description).Scan(&listId)
+// CloneUserList creates a copy of a list owned by the user together with its items and their subtask structure.
+// The copied items keep their done state unless the input resets it, and start outside of any series and without tags.
+// Items in the trash are not copied. Created events are recorded for the list and every copied item
+func (r *ListRepo) CloneUserList(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
+		return entity.ListCopy{}, err
+	}
+
+	query := fmt.Sprintf("SELECT title, description FROM %s WHERE id = $1 AND deleted_at IS NULL", ListsTable)
+
+	var list entity.List
+	if err := tx.QueryRow(query, listId).Scan(&list.Title, &list.Description); err != nil {
 		_ = tx.Rollback()
-		return 0, err
+		if err == sql.ErrNoRows {
+			return entity.ListCopy{}, utils.ErrListNotFound
+		}
+		return entity.ListCopy{}, err
+	}
+
+	if input.Title != nil {
+		list.Title = *input.Title
+	}
+
+	if err := insertUserList(tx, userId, &list); err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
 	}
 
 	query = fmt.Sprintf(`
-		INSERT INTO %s (user_id, list_id)
-		VALUES ($1, $2)`, UsersListsTable)
+		SELECT %s
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE li.list_id = $1 AND i.deleted_at IS NULL
+		ORDER BY li.position, i.id`, itemColumns, ItemsTable, ListsItemsTable, ItemSeriesTable)
 
-	_, err = tx.Exec(query, userId, listId)
+	originals, err := queryItems(tx, query, listId)
 	if err != nil {
 		_ = tx.Rollback()
-		return 0, err
+		return entity.ListCopy{}, err
+	}
+
+	copies, err := copyItems(tx, list.Id, originals)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
+	}
+
+	done := make(map[int]bool, len(originals))
+	for _, original := range originals {
+		done[original.Id] = original.Done
+	}
+
+	doneIds := []int{}
+	items := make([]entity.Item, 0, len(copies))
+	for _, itemCopy := range copies {
+		if !input.ResetDone && done[itemCopy.SourceId] {
+			itemCopy.Item.Done = true
+			doneIds = append(doneIds, itemCopy.Item.Id)
+		}
+		items = append(items, itemCopy.Item)
+	}
+
+	if len(doneIds) > 0 {
+		query = fmt.Sprintf("UPDATE %s SET done = TRUE WHERE id = ANY($1)", ItemsTable)
+
+		if _, err := tx.Exec(query, pq.Array(doneIds)); err != nil {
+			_ = tx.Rollback()
+			return entity.ListCopy{}, err
+		}
+	}
+
+	if err := insertListCopyEvents(tx, userId, list, items); err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, err
+		return entity.ListCopy{}, err
 	}
 
-	list.Id = listId
-	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
-	userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, userId)
+	r.cacheCreatedList(ctx, userId, &list)
 
-	if err := r.cache.Master.Set(ctx, listCacheKey, list, cacheKeyListById.TTL); err != nil {
-		r.logger.Errorf("failed to set cache for key %s: %v", listCacheKey, err)
+	return entity.ListCopy{List: list, Items: items}, nil
+}
+
+// CreateUserListFromTemplate creates a new list owned by the user from one of their templates, the items of the
+// template are added to the list undone and keep their subtask structure. Created events are recorded for the list
+// and every item, ErrListTemplateNotFound is returned when the user has no such template
+func (r *ListRepo) CreateUserListFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error) {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.ListCopy{}, err
 	}
-	if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
+
+	query := fmt.Sprintf("SELECT title, description FROM %s WHERE id = $1 AND user_id = $2", ListTemplatesTable)
+
+	var list entity.List
+	if err := tx.QueryRow(query, templateId, userId).Scan(&list.Title, &list.Description); err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return entity.ListCopy{}, utils.ErrListTemplateNotFound
+		}
+		return entity.ListCopy{}, err
 	}
 
-	return listId, nil
+	if input.Title != nil {
+		list.Title = *input.Title
+	}
+
+	if err := insertUserList(tx, userId, &list); err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
+	}
+
+	templateItems, err := queryTemplateItems(tx, templateId)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
+	}
+
+	originals := make([]entity.Item, 0, len(templateItems))
+	for _, templateItem := range templateItems {
+		originals = append(originals, entity.Item{
+			Id:          templateItem.Id,
+			Title:       templateItem.Title,
+			Description: templateItem.Description,
+			ParentId:    templateItem.ParentId,
+		})
+	}
+
+	copies, err := copyItems(tx, list.Id, originals)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
+	}
+
+	items := make([]entity.Item, 0, len(copies))
+	for _, itemCopy := range copies {
+		items = append(items, itemCopy.Item)
+	}
+
+	if err := insertListCopyEvents(tx, userId, list, items); err != nil {
+		_ = tx.Rollback()
+		return entity.ListCopy{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.ListCopy{}, err
+	}
+
+	r.cacheCreatedList(ctx, userId, &list)
+
+	return entity.ListCopy{List: list, Items: items}, nil
 }
 
-// GetAllUserLists retrieves all lists associated with a user
-func (r *ListRepo) GetAllUserLists(ctx context.Context, userId int) ([]entity.List, error) {
-	lists := []entity.List{}
+// GetUserListsPage retrieves a page of the lists associated with a user, including the lists shared with them,
+// in the sort order of the query. Lists in the trash are left out
+func (r *ListRepo) GetUserListsPage(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error) {
+	page := entity.ListPage{Lists: []entity.List{}}
 
 	userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, userId)
-	if exists, err := r.cache.Replica.Get(ctx, userListsCacheKey, &lists); err != nil {
-		r.logger.Errorf("cache error for key %s: %v", userListsCacheKey, err)
-	} else if exists {
-		return lists, nil
+	cacheKey := pageCacheKey(ctx, r.cache, r.logger, userListsCacheKey, cacheKeyUserListsPage, userId, query.CacheKey())
+
+	if cacheKey != "" {
+		if exists, err := r.cache.Replica.Get(ctx, cacheKey, &page); err != nil {
+			r.logger.Errorf("cache error for key %s: %v", cacheKey, err)
+		} else if exists {
+			return page, nil
+		}
 	}
 
-	query := fmt.Sprintf(`
-		SELECT l.id, l.title, l.description
+	sortExpr := listSortExpr(query)
+
+	keyset, orderBy, keysetArgs, err := pageClause(query, sortExpr, "l.id", 2)
+	if err != nil {
+		return entity.ListPage{}, err
+	}
+	args := append(append([]interface{}{userId}, keysetArgs...), query.Limit+1)
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT l.id, l.title, l.description, (%s)::text
 		FROM %s l
 		JOIN %s ul ON l.id = ul.list_id
-		WHERE ul.user_id = $1`, ListsTable, UsersListsTable)
+		WHERE ul.user_id = $1 AND l.deleted_at IS NULL%s
+		ORDER BY %s
+		LIMIT $%d`, sortExpr, ListsTable, UsersListsTable, keyset, orderBy, len(args))
 
-	rows, err := r.db.Querier.Query(query, userId)
+	rows, err := r.db.Querier.Query(sqlQuery, args...)
 	if err != nil {
-		return nil, err
+		return entity.ListPage{}, err
 	}
 	defer rows.Close()
 
+	sortKeys := []string{}
 	for rows.Next() {
 		var list entity.List
-		if err := rows.Scan(&list.Id, &list.Title, &list.Description); err != nil {
-			return nil, err
+		var sortKey string
+		if err := rows.Scan(&list.Id, &list.Title, &list.Description, &sortKey); err != nil {
+			return entity.ListPage{}, err
 		}
-		lists = append(lists, list)
+		page.Lists = append(page.Lists, list)
+		sortKeys = append(sortKeys, sortKey)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return entity.ListPage{}, err
 	}
 
-	if err := r.cache.Master.Set(ctx, userListsCacheKey, lists, cacheKeyUserLists.TTL); err != nil {
-		r.logger.Errorf("failed to set cache for key %s: %v", userListsCacheKey, err)
+	if len(page.Lists) > query.Limit {
+		page.Lists = page.Lists[:query.Limit]
+		page.NextCursor = query.NextCursor(sortKeys[query.Limit-1], page.Lists[query.Limit-1].Id)
 	}
 
-	return lists, nil
+	if cacheKey != "" {
+		if err := r.cache.Master.Set(ctx, cacheKey, page, cacheKeyUserListsPage.TTL); err != nil {
+			r.logger.Errorf("failed to set cache for key %s: %v", cacheKey, err)
+		}
+	}
+
+	return page, nil
 }
 
-// GetOneById retrieves a single list by its Id
+// GetOneById retrieves a single list by its Id together with its version, ErrListNotFound is returned for lists in the trash
 func (r *ListRepo) GetOneById(ctx context.Context, listId int) (entity.List, error) {
 	var list entity.List
 
@@ -129,9 +293,9 @@ func (r *ListRepo) GetOneById(ctx context.Context, listId int) (entity.List, err
 		return list, nil
 	}
 
-	query := fmt.Sprintf("SELECT id, title, description FROM %s WHERE id = $1", ListsTable)
+	query := fmt.Sprintf("SELECT id, title, description, version FROM %s WHERE id = $1 AND deleted_at IS NULL", ListsTable)
 
-	err := r.db.Querier.QueryRow(query, listId).Scan(&list.Id, &list.Title, &list.Description)
+	err := r.db.Querier.QueryRow(query, listId).Scan(&list.Id, &list.Title, &list.Description, &list.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return list, utils.ErrListNotFound
@@ -147,7 +311,7 @@ func (r *ListRepo) GetOneById(ctx context.Context, listId int) (entity.List, err
 	return list, nil
 }
 
-// GetManyByIds retrieves multiple lists by their Ids
+// GetManyByIds retrieves multiple lists by their Ids, lists in the trash are left out
 func (r *ListRepo) GetManyByIds(ctx context.Context, listIds []int) ([]entity.List, error) {
 	lists := []entity.List{}
 
@@ -155,7 +319,10 @@ func (r *ListRepo) GetManyByIds(ctx context.Context, listIds []int) ([]entity.Li
 		return []entity.List{}, nil
 	}
 
-	query := fmt.Sprintf("SELECT id, title, description FROM %s WHERE id IN (%s)", ListsTable, utils.CreatePlaceholders(len(listIds)))
+	query := fmt.Sprintf(
+		"SELECT id, title, description FROM %s WHERE id IN (%s) AND deleted_at IS NULL",
+		ListsTable, utils.CreatePlaceholders(len(listIds)),
+	)
 	rows, err := r.db.Querier.Query(query, utils.ConvertToInterfaceSlice(listIds)...)
 	if err != nil {
 		return nil, err
@@ -173,9 +340,9 @@ func (r *ListRepo) GetManyByIds(ctx context.Context, listIds []int) ([]entity.Li
 	return lists, nil
 }
 
-// UpdateOneById updates a list by its ID based on the provided input
-func (r *ListRepo) UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput) error {
-	query := fmt.Sprintf("UPDATE %s SET ", ListsTable)
+// UpdateOneById updates a list by its ID based on the provided input and records the change as a revision made by the user.
+// With a version the list is only updated while it still has that version, ErrVersionMismatch is returned otherwise
+func (r *ListRepo) UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput, version *int) error {
 	args := []interface{}{}
 
 	setClauses := []string{}
@@ -188,51 +355,209 @@ func (r *ListRepo) UpdateOneById(ctx context.Context, userId *int, listId int, n
 	if newTodoInput.Description != nil {
 		setClauses = append(setClauses, fmt.Sprintf("description = $%d", argIndex))
 		args = append(args, *newTodoInput.Description)
-		argIndex++
 	}
 
 	if len(setClauses) == 0 {
 		return utils.ErrItemEmptyRequest
 	}
 
-	query += strings.Join(setClauses, ", ")
+	return r.update(ctx, userId, listId, version, strings.Join(setClauses, ", "), args)
+}
+
+// RevertOneById sets every tracked field of a list back to the state and records the revert as a new revision
+// made by the user
+func (r *ListRepo) RevertOneById(ctx context.Context, userId *int, listId int, state entity.ListState) error {
+	return r.update(ctx, userId, listId, nil, "title = $1, description = $2", []interface{}{state.Title, state.Description})
+}
+
+// DeleteOneById moves a list to the trash and records a deleted event, the list and its items are hidden until the list
+// is restored or purged. With a version the list is only trashed while it still has that version, ErrVersionMismatch
+// is returned otherwise
+func (r *ListRepo) DeleteOneById(ctx context.Context, userId *int, listId int, version *int) error {
+	memberIds, err := r.getMemberIds(listId)
+	if err != nil {
+		return err
+	}
 
-	query += fmt.Sprintf(" WHERE id = $%d", argIndex)
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-	args = append(args, listId)
+	args := []interface{}{listId}
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL", ListsTable)
+	if version != nil {
+		args = append(args, *version)
+		query += " AND version = $2"
+	}
 
-	result, err := r.db.Executer.Exec(query, args...)
+	result, err := tx.Exec(query, args...)
 	if err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
+		_ = tx.Rollback()
 		return err
 	}
 
 	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		if version != nil {
+			return versionConflict(r.db.Querier, ListsTable, listId, utils.ErrListNotFound)
+		}
 		return utils.ErrListNotFound
 	}
 
-	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
-	userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, *userId)
+	if err := insertListDeletedEvent(tx, listId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
 	if err := r.cache.Master.Delete(ctx, listCacheKey); err != nil {
 		r.logger.Errorf("failed to invalidate cache for key %s: %v", listCacheKey, err)
 	}
-	if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
-	}
+	r.invalidateUserLists(ctx, memberIds...)
 
 	return nil
 }
 
-// DeleteOneById deletes a list by its Id
-func (r *ListRepo) DeleteOneById(ctx context.Context, userId *int, listId int) error {
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", ListsTable)
+// GetUserListRole returns the role of the user on a list, ErrUserNotOwner is returned when the list is not shared
+// with the user or is in the trash
+func (r *ListRepo) GetUserListRole(ctx context.Context, userId, listId int) (string, error) {
+	query := fmt.Sprintf(`
+		SELECT ul.role
+		FROM %s ul
+		JOIN %s l ON l.id = ul.list_id
+		WHERE ul.user_id = $1 AND ul.list_id = $2 AND l.deleted_at IS NULL`, UsersListsTable, ListsTable)
+
+	var role string
+	err := r.db.Querier.QueryRow(query, userId, listId).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", utils.ErrUserNotOwner
+		}
+
+		return "", err
+	}
+
+	return role, nil
+}
+
+// GetUserListIds retrieves the ids of the lists shared with the user that are not in the trash
+func (r *ListRepo) GetUserListIds(ctx context.Context, userId int) ([]int, error) {
+	query := fmt.Sprintf(`
+		SELECT ul.list_id
+		FROM %s ul
+		JOIN %s l ON l.id = ul.list_id
+		WHERE ul.user_id = $1 AND l.deleted_at IS NULL
+		ORDER BY ul.list_id`, UsersListsTable, ListsTable)
+
+	return queryIds(r.db.Querier, query, userId)
+}
+
+// GetCollaborators retrieves every user the list is shared with, owners first
+func (r *ListRepo) GetCollaborators(ctx context.Context, listId int) ([]entity.ListCollaborator, error) {
+	collaborators := []entity.ListCollaborator{}
+
+	query := fmt.Sprintf(`
+		SELECT u.id, u.name, u.username, ul.role
+		FROM %s ul
+		JOIN %s u ON u.id = ul.user_id
+		WHERE ul.list_id = $1
+		ORDER BY CASE ul.role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, u.username`, UsersListsTable, UsersTable)
+
+	rows, err := r.db.Querier.Query(query, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var collaborator entity.ListCollaborator
+		if err := rows.Scan(&collaborator.UserId, &collaborator.Name, &collaborator.Username, &collaborator.Role); err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, collaborator)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return collaborators, nil
+}
+
+// AddCollaborator shares a list with the user identified by username
+func (r *ListRepo) AddCollaborator(ctx context.Context, listId int, username, role string) (entity.ListCollaborator, error) {
+	collaborator := entity.ListCollaborator{Username: username, Role: role}
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.ListCollaborator{}, err
+	}
+
+	query := fmt.Sprintf("SELECT id, name FROM %s WHERE username = $1", UsersTable)
+
+	err = tx.QueryRow(query, username).Scan(&collaborator.UserId, &collaborator.Name)
+	if err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return entity.ListCollaborator{}, utils.ErrUserNotFound
+		}
+
+		return entity.ListCollaborator{}, err
+	}
+
+	position, err := userListsOrder.appendPosition(tx, collaborator.UserId)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCollaborator{}, err
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (user_id, list_id, role, position)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, list_id) DO NOTHING`, UsersListsTable)
+
+	result, err := tx.Exec(query, collaborator.UserId, listId, role, position)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCollaborator{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListCollaborator{}, err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return entity.ListCollaborator{}, utils.ErrCollaboratorExists
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.ListCollaborator{}, err
+	}
+
+	r.invalidateUserLists(ctx, collaborator.UserId)
+
+	return collaborator, nil
+}
+
+// UpdateCollaboratorRole changes the role of a user on a list
+func (r *ListRepo) UpdateCollaboratorRole(ctx context.Context, listId, userId int, role string) error {
+	query := fmt.Sprintf("UPDATE %s SET role = $1 WHERE list_id = $2 AND user_id = $3", UsersListsTable)
 
-	result, err := r.db.Executer.Exec(query, listId)
+	result, err := r.db.Executer.Exec(query, role, listId, userId)
 	if err != nil {
 		return err
 	}
@@ -243,36 +568,594 @@ func (r *ListRepo) DeleteOneById(ctx context.Context, userId *int, listId int) e
 	}
 
 	if rowsAffected == 0 {
-		return utils.ErrListNotFound
+		return utils.ErrCollaboratorNotFound
 	}
 
-	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
-	if err := r.cache.Master.Delete(ctx, listCacheKey); err != nil {
-		r.logger.Errorf("failed to invalidate cache for key %s: %v", listCacheKey, err)
+	return nil
+}
+
+// RemoveCollaborator stops sharing a list with the user
+func (r *ListRepo) RemoveCollaborator(ctx context.Context, listId, userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE list_id = $1 AND user_id = $2", UsersListsTable)
+
+	result, err := r.db.Executer.Exec(query, listId, userId)
+	if err != nil {
+		return err
 	}
-	if userId != nil {
-		userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, *userId)
-		if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
-			r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
-		}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrCollaboratorNotFound
 	}
 
+	r.invalidateUserLists(ctx, userId)
+
 	return nil
 }
 
-// IsUserOwnerOfList checks if a user is the owner of a specific list
-func (r *ListRepo) IsUserOwnerOfList(ctx context.Context, userId, listId int) error {
-	query := fmt.Sprintf("SELECT 1 FROM %s WHERE user_id = $1 AND list_id = $2", UsersListsTable)
-
-	var exists int
-	err := r.db.Querier.QueryRow(query, userId, listId).Scan(&exists)
+// MoveUserList places a list between the anchors given in the input, the order of the lists is personal to each user.
+// The version of the list is bumped, so the lists of every member are dropped from the cache
+func (r *ListRepo) MoveUserList(ctx context.Context, userId, listId int, input entity.MoveInput) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return utils.ErrUserNotOwner
-		}
+		return err
+	}
+
+	if err := userListsOrder.move(tx, userId, listId, input); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := bumpVersions(tx, ListsTable, []int{listId}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
 		return err
 	}
 
+	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
+	if err := r.cache.Master.Delete(ctx, listCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", listCacheKey, err)
+	}
+
+	memberIds, err := r.getMemberIds(listId)
+	if err != nil {
+		r.logger.Errorf("failed to get members of list %d: %v", listId, err)
+		memberIds = []int{userId}
+	}
+	r.invalidateUserLists(ctx, memberIds...)
+
 	return nil
 }
+
+// GetTrashedUserLists retrieves the lists in the trash that the user owns, the most recently deleted first
+func (r *ListRepo) GetTrashedUserLists(ctx context.Context, userId int) ([]entity.TrashedList, error) {
+	lists := []entity.TrashedList{}
+
+	query := fmt.Sprintf(`
+		SELECT l.id, l.title, l.description, l.deleted_at
+		FROM %s l
+		JOIN %s ul ON l.id = ul.list_id
+		WHERE ul.user_id = $1 AND ul.role = $2 AND l.deleted_at IS NOT NULL
+		ORDER BY l.deleted_at DESC, l.id`, ListsTable, UsersListsTable)
+
+	rows, err := r.db.Querier.Query(query, userId, entity.ListRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var list entity.TrashedList
+		if err := rows.Scan(&list.Id, &list.Title, &list.Description, &list.DeletedAt); err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// RestoreUserList takes a list the user owns out of the trash and records an updated event so that the list is
+// indexed again, ErrListNotFound is returned when the user owns no such list in the trash
+func (r *ListRepo) RestoreUserList(ctx context.Context, userId, listId int) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s l SET deleted_at = NULL
+		FROM %s ul
+		WHERE l.id = $1 AND l.deleted_at IS NOT NULL AND ul.list_id = l.id AND ul.user_id = $2 AND ul.role = $3`,
+		ListsTable, UsersListsTable)
+
+	result, err := tx.Exec(query, listId, userId, entity.ListRoleOwner)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return utils.ErrListNotFound
+	}
+
+	if err := insertListUpdatedEvent(tx, userId, listId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	memberIds, err := r.getMemberIds(listId)
+	if err != nil {
+		r.logger.Errorf("failed to get members of list %d: %v", listId, err)
+		memberIds = []int{userId}
+	}
+	r.invalidateUserLists(ctx, memberIds...)
+
+	return nil
+}
+
+// PurgeTrash permanently deletes the lists that were moved to the trash before the given time together with
+// all of their items, and returns the Ids of the purged lists and items. Deleted events are recorded for the purged items
+func (r *ListRepo) PurgeTrash(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error) {
+	purged := entity.PurgedTrash{ListIds: []int{}, ItemIds: []int{}}
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return purged, err
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE id IN (
+			SELECT li.item_id
+			FROM %s li
+			JOIN %s l ON l.id = li.list_id
+			WHERE l.deleted_at < $1
+		)
+		RETURNING id`, ItemsTable, ListsItemsTable, ListsTable)
+
+	if purged.ItemIds, err = queryIds(tx, query, deletedBefore); err != nil {
+		_ = tx.Rollback()
+		return entity.PurgedTrash{}, err
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1 RETURNING id", ListsTable)
+
+	if purged.ListIds, err = queryIds(tx, query, deletedBefore); err != nil {
+		_ = tx.Rollback()
+		return entity.PurgedTrash{}, err
+	}
+
+	if err := insertItemsDeletedEvents(tx, purged.ItemIds); err != nil {
+		_ = tx.Rollback()
+		return entity.PurgedTrash{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.PurgedTrash{}, err
+	}
+
+	for _, itemId := range purged.ItemIds {
+		itemCacheKey := fmt.Sprintf(cacheKeyItemById.Pattern, itemId)
+		if err := r.cache.Master.Delete(ctx, itemCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", itemCacheKey, err)
+		}
+	}
+	for _, listId := range purged.ListIds {
+		listItemsCacheKey := fmt.Sprintf(cacheKeyListItems.Pattern, listId)
+		if err := r.cache.Master.Delete(ctx, listItemsCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", listItemsCacheKey, err)
+		}
+	}
+
+	return purged, nil
+}
+
+// CreateTemplate saves a list as a template of the user, the template keeps the title, description and subtask
+// structure of the items of the list. Items in the trash are left out
+func (r *ListRepo) CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error) {
+	template := entity.ListTemplate{UserId: userId}
+
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.ListTemplate{}, err
+	}
+
+	query := fmt.Sprintf("SELECT title, description FROM %s WHERE id = $1 AND deleted_at IS NULL", ListsTable)
+
+	if err := tx.QueryRow(query, listId).Scan(&template.Title, &template.Description); err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return entity.ListTemplate{}, utils.ErrListNotFound
+		}
+		return entity.ListTemplate{}, err
+	}
+
+	if input.Title != nil {
+		template.Title = *input.Title
+	}
+	if input.Description != nil {
+		template.Description = *input.Description
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (user_id, title, description)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`, ListTemplatesTable)
+
+	err = tx.QueryRow(query, userId, template.Title, template.Description).Scan(&template.Id, &template.CreatedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListTemplate{}, err
+	}
+
+	query = fmt.Sprintf(`
+		SELECT %s
+		FROM %s i
+		JOIN %s li ON i.id = li.item_id
+		LEFT JOIN %s s ON s.id = i.series_id
+		WHERE li.list_id = $1 AND i.deleted_at IS NULL
+		ORDER BY li.position, i.id`, itemColumns, ItemsTable, ListsItemsTable, ItemSeriesTable)
+
+	originals, err := queryItems(tx, query, listId)
+	if err != nil {
+		_ = tx.Rollback()
+		return entity.ListTemplate{}, err
+	}
+
+	if template.Items, err = insertTemplateItems(tx, template.Id, originals); err != nil {
+		_ = tx.Rollback()
+		return entity.ListTemplate{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.ListTemplate{}, err
+	}
+
+	template.ItemCount = len(template.Items)
+
+	return template, nil
+}
+
+// GetUserTemplates retrieves the templates of the user with the number of their items, the most recent first
+func (r *ListRepo) GetUserTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error) {
+	templates := []entity.ListTemplate{}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.description, COUNT(ti.id), t.created_at
+		FROM %s t
+		LEFT JOIN %s ti ON ti.template_id = t.id
+		WHERE t.user_id = $1
+		GROUP BY t.id
+		ORDER BY t.id DESC`, ListTemplatesTable, ListTemplateItemsTable)
+
+	rows, err := r.db.Querier.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template := entity.ListTemplate{UserId: userId}
+		if err := rows.Scan(&template.Id, &template.Title, &template.Description, &template.ItemCount, &template.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetUserTemplate retrieves a template of the user with its items, ErrListTemplateNotFound is returned
+// when the user has no such template
+func (r *ListRepo) GetUserTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error) {
+	template := entity.ListTemplate{UserId: userId}
+
+	query := fmt.Sprintf("SELECT id, title, description, created_at FROM %s WHERE id = $1 AND user_id = $2", ListTemplatesTable)
+
+	err := r.db.Querier.QueryRow(query, templateId, userId).Scan(&template.Id, &template.Title, &template.Description, &template.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.ListTemplate{}, utils.ErrListTemplateNotFound
+		}
+
+		return entity.ListTemplate{}, err
+	}
+
+	if template.Items, err = queryTemplateItems(r.db.Querier, templateId); err != nil {
+		return entity.ListTemplate{}, err
+	}
+
+	template.ItemCount = len(template.Items)
+
+	return template, nil
+}
+
+// DeleteUserTemplate deletes a template of the user together with its items, lists created from the template are kept
+func (r *ListRepo) DeleteUserTemplate(ctx context.Context, userId, templateId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", ListTemplatesTable)
+
+	result, err := r.db.Executer.Exec(query, templateId, userId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrListTemplateNotFound
+	}
+
+	return nil
+}
+
+// getMemberIds retrieves the Ids of every user the list is shared with
+func (r *ListRepo) getMemberIds(listId int) ([]int, error) {
+	memberIds := []int{}
+
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = $1", UsersListsTable)
+
+	rows, err := r.db.Querier.Query(query, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId int
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		memberIds = append(memberIds, userId)
+	}
+
+	return memberIds, rows.Err()
+}
+
+// update applies the set clause to a list in a transaction and records the states of the list around it as a
+// revision made by the user together with an updated event, the Id of the list is bound after the arguments of the
+// clause. No revision is recorded when the update leaves the list unchanged, with a version the list must still have it
+func (r *ListRepo) update(ctx context.Context, userId *int, listId int, version *int, setClause string, args []interface{}) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if version != nil {
+		if err := lockVersion(tx, ListsTable, listId, *version); err != nil {
+			_ = tx.Rollback()
+			if err == sql.ErrNoRows {
+				return utils.ErrListNotFound
+			}
+			return err
+		}
+	}
+
+	query := fmt.Sprintf("SELECT title, description FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", ListsTable)
+
+	var before entity.ListState
+	if err := tx.QueryRow(query, listId).Scan(&before.Title, &before.Description); err != nil {
+		_ = tx.Rollback()
+		if err == sql.ErrNoRows {
+			return utils.ErrListNotFound
+		}
+		return err
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d RETURNING title, description", ListsTable, setClause, len(args)+1)
+
+	var after entity.ListState
+	if err := tx.QueryRow(query, append(args, listId)...).Scan(&after.Title, &after.Description); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if len(before.Diff(after)) > 0 {
+		if err := insertRevision(tx, ListRevisionsTable, "list_id", listId, userId, before, after); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	// Updates made on behalf of no user are recorded with a zero user Id
+	actorId := 0
+	if userId != nil {
+		actorId = *userId
+	}
+
+	if err := insertListUpdatedEvent(tx, actorId, listId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, listId)
+	if err := r.cache.Master.Delete(ctx, listCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", listCacheKey, err)
+	}
+
+	memberIds, err := r.getMemberIds(listId)
+	if err != nil {
+		r.logger.Errorf("failed to get members of list %d: %v", listId, err)
+		memberIds = []int{*userId}
+	}
+	r.invalidateUserLists(ctx, memberIds...)
+
+	return nil
+}
+
+// cacheCreatedList caches a list created by the user and drops the cached lists of the user, failures are only logged
+func (r *ListRepo) cacheCreatedList(ctx context.Context, userId int, list *entity.List) {
+	listCacheKey := fmt.Sprintf(cacheKeyListById.Pattern, list.Id)
+	userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, userId)
+
+	if err := r.cache.Master.Set(ctx, listCacheKey, list, cacheKeyListById.TTL); err != nil {
+		r.logger.Errorf("failed to set cache for key %s: %v", listCacheKey, err)
+	}
+	if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
+		r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
+	}
+}
+
+// invalidateUserLists drops the cached lists of the given users, failures are only logged
+func (r *ListRepo) invalidateUserLists(ctx context.Context, userIds ...int) {
+	for _, userId := range userIds {
+		userListsCacheKey := fmt.Sprintf(cacheKeyUserLists.Pattern, userId)
+		if err := r.cache.Master.Delete(ctx, userListsCacheKey); err != nil {
+			r.logger.Errorf("failed to invalidate cache for key %s: %v", userListsCacheKey, err)
+		}
+	}
+}
+
+// insertUserList stores a list and links it to the end of the lists of the user as its owner, the Id of the list is filled in
+func insertUserList(tx *sqlx.Tx, userId int, list *entity.List) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (title, description)
+		VALUES ($1, $2)
+		RETURNING id, version`, ListsTable)
+
+	if err := tx.QueryRow(query, list.Title, list.Description).Scan(&list.Id, &list.Version); err != nil {
+		return err
+	}
+
+	position, err := userListsOrder.appendPosition(tx, userId)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (user_id, list_id, role, position)
+		VALUES ($1, $2, $3, $4)`, UsersListsTable)
+
+	_, err = tx.Exec(query, userId, list.Id, entity.ListRoleOwner, position)
+	return err
+}
+
+// insertTemplateItems stores a template item for every item in the given order, parents are stored before their
+// subtasks so that the stored subtasks point to the stored parents
+func insertTemplateItems(tx *sqlx.Tx, templateId int, originals []entity.Item) ([]entity.ListTemplateItem, error) {
+	originalIds := make(map[int]bool, len(originals))
+	for _, original := range originals {
+		originalIds[original.Id] = true
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (template_id, parent_id, title, description, position)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`, ListTemplateItemsTable)
+
+	templateItemIds := make(map[int]int, len(originals))
+	templateItems := make([]entity.ListTemplateItem, 0, len(originals))
+
+	for len(templateItems) < len(originals) {
+		progressed := false
+
+		for _, original := range originals {
+			if _, ok := templateItemIds[original.Id]; ok {
+				continue
+			}
+
+			var parentId *int
+			if original.ParentId != nil && originalIds[*original.ParentId] {
+				templateParentId, ok := templateItemIds[*original.ParentId]
+				if !ok {
+					continue
+				}
+				parentId = &templateParentId
+			}
+
+			templateItem := entity.ListTemplateItem{
+				ParentId:    parentId,
+				Title:       original.Title,
+				Description: original.Description,
+			}
+
+			err := tx.QueryRow(query, templateId, parentId, original.Title, original.Description, len(templateItems)).Scan(&templateItem.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			templateItemIds[original.Id] = templateItem.Id
+			templateItems = append(templateItems, templateItem)
+			progressed = true
+		}
+
+		if !progressed {
+			return nil, utils.ErrItemParentCycle
+		}
+	}
+
+	return templateItems, nil
+}
+
+// queryTemplateItems retrieves the items of a template in their order
+func queryTemplateItems(querier database.Querier, templateId int) ([]entity.ListTemplateItem, error) {
+	query := fmt.Sprintf(`
+		SELECT id, parent_id, title, description
+		FROM %s
+		WHERE template_id = $1
+		ORDER BY position`, ListTemplateItemsTable)
+
+	rows, err := querier.Query(query, templateId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templateItems := []entity.ListTemplateItem{}
+	for rows.Next() {
+		var templateItem entity.ListTemplateItem
+		if err := rows.Scan(&templateItem.Id, &templateItem.ParentId, &templateItem.Title, &templateItem.Description); err != nil {
+			return nil, err
+		}
+		templateItems = append(templateItems, templateItem)
+	}
+
+	return templateItems, rows.Err()
+}
+
+// listSortExpr returns the expression lists are sorted by for the sort of the query
+func listSortExpr(query entity.PageQuery) string {
+	switch query.Sort {
+	case entity.SortCreated:
+		return "l.created_at"
+	case entity.SortUpdated:
+		return "l.updated_at"
+	case entity.SortTitle:
+		return "l.title"
+	default:
+		return "ul.position"
+	}
+}
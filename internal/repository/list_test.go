@@ -3,33 +3,63 @@ package repository_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
 	"github.com/berikulyBeket/todo-plus/pkg/database"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 var (
-	queryInsertList                = fmt.Sprintf("INSERT INTO %s \\(title, description\\) VALUES \\(\\$1, \\$2\\) RETURNING id", repository.ListsTable)
-	queryLinkUser                  = fmt.Sprintf("INSERT INTO %s \\(user_id, list_id\\) VALUES \\(\\$1, \\$2\\)", repository.UsersListsTable)
-	queryGetAllLists               = fmt.Sprintf("SELECT l.id, l.title, l.description FROM %s l JOIN %s ul ON l.id = ul.list_id WHERE ul.user_id = \\$1", repository.ListsTable, repository.UsersListsTable)
-	queryGetListById               = fmt.Sprintf("SELECT id, title, description FROM %s WHERE id = \\$1", repository.ListsTable)
-	queryGetManyListsByIds         = fmt.Sprintf("SELECT id, title, description FROM %s WHERE id IN \\(\\$1, \\$2\\)", repository.ListsTable)
-	queryUpdateTitleListById       = fmt.Sprintf("UPDATE %s SET title = \\$1 WHERE id = \\$2", repository.ListsTable)
-	queryUpdateDescriptionListById = fmt.Sprintf("UPDATE %s SET description = \\$1 WHERE id = \\$2", repository.ListsTable)
-	queryUpdateListById            = fmt.Sprintf("UPDATE %s SET title = \\$1, description = \\$2 WHERE id = \\$3", repository.ListsTable)
-	queryDeleteListById            = fmt.Sprintf("DELETE FROM %s WHERE id = \\$1", repository.ListsTable)
-	queryIsUserOwnerOfList         = fmt.Sprintf("SELECT 1 FROM %s WHERE user_id = \\$1 AND list_id = \\$2", repository.UsersListsTable)
+	queryInsertList                = fmt.Sprintf("INSERT INTO %s \\(title, description\\) VALUES \\(\\$1, \\$2\\) RETURNING id, version", repository.ListsTable)
+	queryLinkUser                  = fmt.Sprintf("INSERT INTO %s \\(user_id, list_id, role, position\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)", repository.UsersListsTable)
+	queryGetListsPage              = fmt.Sprintf("SELECT l.id, l.title, l.description, \\(ul.position\\)::text FROM %s l JOIN %s ul ON l.id = ul.list_id WHERE ul.user_id = \\$1 AND l.deleted_at IS NULL ORDER BY ul.position ASC, l.id ASC LIMIT \\$2", repository.ListsTable, repository.UsersListsTable)
+	queryGetListsPageAfter         = fmt.Sprintf("SELECT l.id, l.title, l.description, \\(l.title\\)::text FROM %s l JOIN %s ul ON l.id = ul.list_id WHERE ul.user_id = \\$1 AND l.deleted_at IS NULL AND \\(l.title, l.id\\) < \\(\\$2, \\$3\\) ORDER BY l.title DESC, l.id DESC LIMIT \\$4", repository.ListsTable, repository.UsersListsTable)
+	queryGetListById               = fmt.Sprintf("SELECT id, title, description, version FROM %s WHERE id = \\$1 AND deleted_at IS NULL", repository.ListsTable)
+	queryGetManyListsByIds         = fmt.Sprintf("SELECT id, title, description FROM %s WHERE id IN \\(\\$1, \\$2\\) AND deleted_at IS NULL", repository.ListsTable)
+	queryUpdateTitleListById       = fmt.Sprintf("UPDATE %s SET title = \\$1 WHERE id = \\$2 RETURNING title, description", repository.ListsTable)
+	queryUpdateDescriptionListById = fmt.Sprintf("UPDATE %s SET description = \\$1 WHERE id = \\$2 RETURNING title, description", repository.ListsTable)
+	queryUpdateListById            = fmt.Sprintf("UPDATE %s SET title = \\$1, description = \\$2 WHERE id = \\$3 RETURNING title, description", repository.ListsTable)
+	queryTrashListById             = fmt.Sprintf("UPDATE %s SET deleted_at = NOW\\(\\) WHERE id = \\$1 AND deleted_at IS NULL", repository.ListsTable)
+	queryTrashListByIdVersion      = fmt.Sprintf("UPDATE %s SET deleted_at = NOW\\(\\) WHERE id = \\$1 AND deleted_at IS NULL AND version = \\$2", repository.ListsTable)
+	queryListExists                = fmt.Sprintf("SELECT EXISTS \\(SELECT 1 FROM %s WHERE id = \\$1 AND deleted_at IS NULL\\)", repository.ListsTable)
+	queryGetUserListRole           = fmt.Sprintf("SELECT ul.role FROM %s ul JOIN %s l ON l.id = ul.list_id WHERE ul.user_id = \\$1 AND ul.list_id = \\$2 AND l.deleted_at IS NULL", repository.UsersListsTable, repository.ListsTable)
+	queryGetListMemberIds          = fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = \\$1", repository.UsersListsTable)
+	queryGetUserListIds            = fmt.Sprintf("SELECT ul.list_id FROM %s ul JOIN %s l ON l.id = ul.list_id WHERE ul.user_id = \\$1 AND l.deleted_at IS NULL ORDER BY ul.list_id", repository.UsersListsTable, repository.ListsTable)
+	queryGetCollaborators          = fmt.Sprintf("SELECT u.id, u.name, u.username, ul.role FROM %s ul JOIN %s u ON u.id = ul.user_id WHERE ul.list_id = \\$1", repository.UsersListsTable, repository.UsersTable)
+	queryGetUserByUsernameForShare = fmt.Sprintf("SELECT id, name FROM %s WHERE username = \\$1", repository.UsersTable)
+	queryAddCollaborator           = fmt.Sprintf("INSERT INTO %s \\(user_id, list_id, role, position\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) ON CONFLICT \\(user_id, list_id\\) DO NOTHING", repository.UsersListsTable)
+	queryUpdateCollaboratorRole    = fmt.Sprintf("UPDATE %s SET role = \\$1 WHERE list_id = \\$2 AND user_id = \\$3", repository.UsersListsTable)
+	queryRemoveCollaborator        = fmt.Sprintf("DELETE FROM %s WHERE list_id = \\$1 AND user_id = \\$2", repository.UsersListsTable)
+	querySelectListState           = fmt.Sprintf("SELECT title, description FROM %s WHERE id = \\$1 AND deleted_at IS NULL FOR UPDATE", repository.ListsTable)
+	queryInsertListRevision        = fmt.Sprintf("INSERT INTO %s \\(list_id, user_id, before, after\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\)", repository.ListRevisionsTable)
+	queryGetTrashedLists           = fmt.Sprintf("SELECT l.id, l.title, l.description, l.deleted_at FROM %s l JOIN %s ul ON l.id = ul.list_id WHERE ul.user_id = \\$1 AND ul.role = \\$2 AND l.deleted_at IS NOT NULL ORDER BY l.deleted_at DESC, l.id", repository.ListsTable, repository.UsersListsTable)
+	queryRestoreList               = fmt.Sprintf("UPDATE %s l SET deleted_at = NULL FROM %s ul WHERE l.id = \\$1 AND l.deleted_at IS NOT NULL AND ul.list_id = l.id AND ul.user_id = \\$2 AND ul.role = \\$3", repository.ListsTable, repository.UsersListsTable)
+	queryPurgeListItems            = fmt.Sprintf("DELETE FROM %s WHERE id IN \\( SELECT li.item_id FROM %s li JOIN %s l ON l.id = li.list_id WHERE l.deleted_at < \\$1 \\) RETURNING id", repository.ItemsTable, repository.ListsItemsTable, repository.ListsTable)
+	queryPurgeLists                = fmt.Sprintf("DELETE FROM %s WHERE deleted_at < \\$1 RETURNING id", repository.ListsTable)
+	queryGetSourceList             = fmt.Sprintf("SELECT title, description FROM %s WHERE id = \\$1 AND deleted_at IS NULL", repository.ListsTable)
+	queryMarkCopiesDone            = fmt.Sprintf("UPDATE %s SET done = TRUE WHERE id = ANY\\(\\$1\\)", repository.ItemsTable)
+	queryGetSourceTemplate         = fmt.Sprintf("SELECT title, description FROM %s WHERE id = \\$1 AND user_id = \\$2", repository.ListTemplatesTable)
+	queryInsertTemplate            = fmt.Sprintf("INSERT INTO %s \\(user_id, title, description\\) VALUES \\(\\$1, \\$2, \\$3\\) RETURNING id, created_at", repository.ListTemplatesTable)
+	queryInsertTemplateItem        = fmt.Sprintf("INSERT INTO %s \\(template_id, parent_id, title, description, position\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id", repository.ListTemplateItemsTable)
+	queryGetTemplateItems          = fmt.Sprintf("SELECT id, parent_id, title, description FROM %s WHERE template_id = \\$1 ORDER BY position", repository.ListTemplateItemsTable)
+	queryGetUserTemplates          = fmt.Sprintf("SELECT t.id, t.title, t.description, COUNT\\(ti.id\\), t.created_at FROM %s t LEFT JOIN %s ti ON ti.template_id = t.id WHERE t.user_id = \\$1 GROUP BY t.id ORDER BY t.id DESC", repository.ListTemplatesTable, repository.ListTemplateItemsTable)
+	queryGetUserTemplate           = fmt.Sprintf("SELECT id, title, description, created_at FROM %s WHERE id = \\$1 AND user_id = \\$2", repository.ListTemplatesTable)
+	queryDeleteUserTemplate        = fmt.Sprintf("DELETE FROM %s WHERE id = \\$1 AND user_id = \\$2", repository.ListTemplatesTable)
 )
 
 // Helper function to set up the mock database, sqlmock, and repository
@@ -72,12 +102,14 @@ func TestCreateList(t *testing.T) {
 				mock.ExpectBegin()
 				mock.ExpectQuery(queryInsertList).
 					WithArgs("Grocery List", "A list of groceries").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
 
+				expectAppendPosition(mock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 123, nil)
 				mock.ExpectExec(queryLinkUser).
-					WithArgs(123, 1).
+					WithArgs(123, 1, "owner", "a0").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				expectOutboxEvents(mock, messagebroker.ListCreatedTopic, 1)
 				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
@@ -110,10 +142,11 @@ func TestCreateList(t *testing.T) {
 				mock.ExpectBegin()
 				mock.ExpectQuery(queryInsertList).
 					WithArgs("Grocery List", "A list of groceries").
-					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(1, 1))
 
+				expectAppendPosition(mock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 123, nil)
 				mock.ExpectExec(queryLinkUser).
-					WithArgs(123, 1).
+					WithArgs(123, 1, "owner", "a0").
 					WillReturnError(sql.ErrTxDone)
 				mock.ExpectRollback()
 			},
@@ -156,23 +189,29 @@ func TestCreateList(t *testing.T) {
 	}
 }
 
-// TestGetAll tests retrieving all user lists
-func TestGetAll(t *testing.T) {
+// TestGetUserListsPage tests retrieving a page of the user lists
+func TestGetUserListsPage(t *testing.T) {
+	firstPage := entity.PageQuery{Limit: 2, Sort: entity.SortPosition, Order: entity.SortOrderAsc}
+	titlePage := entity.PageQuery{Limit: 1, Sort: entity.SortTitle, Order: entity.SortOrderDesc}
+	titlePage.Cursor = *titlePage.NextCursor("Work Tasks", 2)
+
 	testCases := []struct {
-		name          string
-		mockQuery     func(sqlmock.Sqlmock)
-		mockCache     func(*MockCache)
-		expectedLists []entity.List
-		expectedErr   error
+		name         string
+		query        entity.PageQuery
+		mockQuery    func(sqlmock.Sqlmock)
+		mockCache    func(*MockCache)
+		expectedPage entity.ListPage
+		expectedErr  error
 	}{
 		{
-			name: "Success",
+			name:  "Success",
+			query: firstPage,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(queryGetAllLists).
-					WithArgs(123).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).
-						AddRow(1, "Grocery List", "A list of groceries").
-						AddRow(2, "Work Tasks", "Tasks to complete at work"))
+				mock.ExpectQuery(queryGetListsPage).
+					WithArgs(123, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "sort_key"}).
+						AddRow(1, "Grocery List", "A list of groceries", "1").
+						AddRow(2, "Work Tasks", "Tasks to complete at work", "2"))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
@@ -180,18 +219,45 @@ func TestGetAll(t *testing.T) {
 				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil)
 			},
-			expectedLists: []entity.List{
+			expectedPage: entity.ListPage{Lists: []entity.List{
 				{Id: 1, Title: "Grocery List", Description: "A list of groceries"},
 				{Id: 2, Title: "Work Tasks", Description: "Tasks to complete at work"},
+			}},
+			expectedErr: nil,
+		},
+		{
+			name:  "NextPage",
+			query: titlePage,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetListsPageAfter).
+					WithArgs(123, "Work Tasks", 2, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "sort_key"}).
+						AddRow(4, "Reading", "", "Reading").
+						AddRow(1, "Grocery List", "A list of groceries", "Grocery List"))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
+					Return(false, nil)
+				mockCache.On("Set", mock.Anything, "user_lists:123", mock.Anything, mock.Anything).
+					Return(nil)
+				mockCache.On("Set", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "user_lists:123:") && strings.HasSuffix(key, ":title:desc:1:"+titlePage.Cursor)
+				}), mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedPage: entity.ListPage{
+				Lists:      []entity.List{{Id: 4, Title: "Reading"}},
+				NextCursor: titlePage.NextCursor("Reading", 4),
 			},
 			expectedErr: nil,
 		},
 		{
-			name: "NoLists",
+			name:  "NoLists",
+			query: firstPage,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(queryGetAllLists).
-					WithArgs(123).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}))
+				mock.ExpectQuery(queryGetListsPage).
+					WithArgs(123, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "sort_key"}))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
@@ -199,55 +265,64 @@ func TestGetAll(t *testing.T) {
 				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil)
 			},
-			expectedLists: []entity.List{},
-			expectedErr:   nil,
+			expectedPage: entity.ListPage{Lists: []entity.List{}},
+			expectedErr:  nil,
 		},
 		{
-			name: "QueryError",
+			name:  "QueryError",
+			query: firstPage,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(queryGetAllLists).
-					WithArgs(123).
+				mock.ExpectQuery(queryGetListsPage).
+					WithArgs(123, 3).
 					WillReturnError(sql.ErrConnDone)
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
 					Return(false, nil)
+				mockCache.On("Set", mock.Anything, "user_lists:123", mock.Anything, mock.Anything).
+					Return(nil)
 			},
-			expectedLists: nil,
-			expectedErr:   sql.ErrConnDone,
+			expectedPage: entity.ListPage{},
+			expectedErr:  sql.ErrConnDone,
 		},
 		{
-			name: "ScanError",
+			name:  "ScanError",
+			query: firstPage,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(queryGetAllLists).
-					WithArgs(123).
+				mock.ExpectQuery(queryGetListsPage).
+					WithArgs(123, 3).
 					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).
 						AddRow("Grocery List", "A list of groceries")) // Simulate scan error
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
 					Return(false, nil)
+				mockCache.On("Set", mock.Anything, "user_lists:123", mock.Anything, mock.Anything).
+					Return(nil)
 			},
-			expectedLists: nil,
-			expectedErr:   fmt.Errorf("sql: expected 2 destination arguments in Scan, not 3"),
+			expectedPage: entity.ListPage{},
+			expectedErr:  fmt.Errorf("sql: expected 2 destination arguments in Scan, not 4"),
 		},
 		{
-			name: "RowsError",
+			name:  "RowsError",
+			query: firstPage,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "sort_key"}).
 					RowError(0, sql.ErrConnDone).
-					AddRow(1, "Grocery List", "A list of groceries")
+					AddRow(1, "Grocery List", "A list of groceries", "1")
 
-				mock.ExpectQuery(queryGetAllLists).
-					WithArgs(123).
+				mock.ExpectQuery(queryGetListsPage).
+					WithArgs(123, 3).
 					WillReturnRows(rows)
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
 					Return(false, nil)
+				mockCache.On("Set", mock.Anything, "user_lists:123", mock.Anything, mock.Anything).
+					Return(nil)
 			},
-			expectedLists: nil,
-			expectedErr:   sql.ErrConnDone,
+			expectedPage: entity.ListPage{},
+			expectedErr:  sql.ErrConnDone,
 		},
 	}
 
@@ -263,9 +338,9 @@ func TestGetAll(t *testing.T) {
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			lists, err := listRepo.GetAllUserLists(context.Background(), 123)
+			page, err := listRepo.GetUserListsPage(context.Background(), 123, testCase.query)
 
-			assert.Equal(t, testCase.expectedLists, lists)
+			assert.Equal(t, testCase.expectedPage, page)
 			assert.Equal(t, testCase.expectedErr, err)
 
 			assertListRepoExpectations(t, mock)
@@ -291,7 +366,7 @@ func TestGetOneById(t *testing.T) {
 				query := queryGetListById
 				mock.ExpectQuery(query).
 					WithArgs(1).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).AddRow(1, "Grocery List", "A list of groceries"))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "version"}).AddRow(1, "Grocery List", "A list of groceries", 2))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Get", mock.Anything, mock.Anything, mock.Anything).
@@ -299,7 +374,7 @@ func TestGetOneById(t *testing.T) {
 				mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return(nil)
 			},
-			expectedList: entity.List{Id: 1, Title: "Grocery List", Description: "A list of groceries"},
+			expectedList: entity.List{Id: 1, Title: "Grocery List", Description: "A list of groceries", Version: 2},
 			expectedErr:  nil,
 		},
 		{
@@ -344,7 +419,7 @@ func TestGetOneById(t *testing.T) {
 					Return(false, nil)
 			},
 			expectedList: entity.List{},
-			expectedErr:  fmt.Errorf("sql: expected 2 destination arguments in Scan, not 3"),
+			expectedErr:  fmt.Errorf("sql: expected 2 destination arguments in Scan, not 4"),
 		},
 	}
 
@@ -477,10 +552,22 @@ func TestUpdateOneById(t *testing.T) {
 				Description: &description,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateListById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySelectListState).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Title", "Description"))
+				mock.ExpectQuery(queryUpdateListById).
 					WithArgs("Updated Title", "Updated Description", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Updated Title", "Updated Description"))
+				mock.ExpectExec(queryInsertListRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ListUpdatedTopic, 1)
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -496,10 +583,22 @@ func TestUpdateOneById(t *testing.T) {
 				Title: &title,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateTitleListById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySelectListState).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Title", "Description"))
+				mock.ExpectQuery(queryUpdateTitleListById).
 					WithArgs("Updated Title", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Updated Title", "Description"))
+				mock.ExpectExec(queryInsertListRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ListUpdatedTopic, 1)
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -515,10 +614,22 @@ func TestUpdateOneById(t *testing.T) {
 				Description: &description,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateDescriptionListById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySelectListState).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Title", "Description"))
+				mock.ExpectQuery(queryUpdateDescriptionListById).
 					WithArgs("Updated Description", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Title", "Updated Description"))
+				mock.ExpectExec(queryInsertListRevision).
+					WithArgs(1, 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ListUpdatedTopic, 1)
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -535,6 +646,23 @@ func TestUpdateOneById(t *testing.T) {
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: utils.ErrItemEmptyRequest,
 		},
+		{
+			name:   "ListNotFound",
+			userId: 2,
+			listId: 1,
+			updateInput: entity.UpdateListInput{
+				Title: &title,
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySelectListState).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrListNotFound,
+		},
 		{
 			name:   "DatabaseError",
 			userId: 2,
@@ -544,10 +672,14 @@ func TestUpdateOneById(t *testing.T) {
 				Description: &description,
 			},
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryUpdateListById
-				mock.ExpectExec(query).
+				mock.ExpectBegin()
+				mock.ExpectQuery(querySelectListState).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Title", "Description"))
+				mock.ExpectQuery(queryUpdateListById).
 					WithArgs("Updated Title", "Updated Description", 1).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: sql.ErrConnDone,
@@ -566,7 +698,7 @@ func TestUpdateOneById(t *testing.T) {
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			err := listRepo.UpdateOneById(context.Background(), &testCase.userId, testCase.listId, testCase.updateInput)
+			err := listRepo.UpdateOneById(context.Background(), &testCase.userId, testCase.listId, testCase.updateInput, nil)
 
 			assert.Equal(t, testCase.expectedErr, err)
 
@@ -578,10 +710,13 @@ func TestUpdateOneById(t *testing.T) {
 
 // TestDeleteListById tests deleting a list by its Id
 func TestDeleteListById(t *testing.T) {
+	version := 3
+
 	testCases := []struct {
 		name        string
 		userId      int
 		listId      int
+		version     *int
 		mockQuery   func(sqlmock.Sqlmock)
 		mockCache   func(*MockCache)
 		expectedErr error
@@ -591,10 +726,17 @@ func TestDeleteListById(t *testing.T) {
 			userId: 2,
 			listId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteListById
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+
+				mock.ExpectBegin()
+				query := queryTrashListById
 				mock.ExpectExec(query).
 					WithArgs(1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ListDeletedTopic, 1)
+				mock.ExpectCommit()
 			},
 			mockCache: func(mockCache *MockCache) {
 				mockCache.On("Delete", mock.Anything, mock.Anything).
@@ -607,10 +749,16 @@ func TestDeleteListById(t *testing.T) {
 			userId: 2,
 			listId: 999,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteListById
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(999).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+				mock.ExpectBegin()
+				query := queryTrashListById
 				mock.ExpectExec(query).
 					WithArgs(999).
 					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectRollback()
 			},
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: utils.ErrListNotFound,
@@ -620,14 +768,77 @@ func TestDeleteListById(t *testing.T) {
 			userId: 2,
 			listId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryDeleteListById
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+				mock.ExpectBegin()
+				query := queryTrashListById
 				mock.ExpectExec(query).
 					WithArgs(1).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+		{
+			name:   "MembersQueryError",
+			userId: 2,
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
 			},
 			mockCache:   func(mockCache *MockCache) {},
 			expectedErr: sql.ErrConnDone,
 		},
+		{
+			name:    "Success_VersionMatches",
+			userId:  2,
+			listId:  1,
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+				mock.ExpectBegin()
+				mock.ExpectExec(queryTrashListByIdVersion).
+					WithArgs(1, 3).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectOutboxEvents(mock, messagebroker.ListDeletedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "VersionMismatch",
+			userId:  2,
+			listId:  1,
+			version: &version,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+				mock.ExpectBegin()
+				mock.ExpectExec(queryTrashListByIdVersion).
+					WithArgs(1, 3).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectRollback()
+				mock.ExpectQuery(queryListExists).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrVersionMismatch,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -642,7 +853,7 @@ func TestDeleteListById(t *testing.T) {
 			testCase.mockQuery(mock)
 			testCase.mockCache(mockCache)
 
-			err := listRepo.DeleteOneById(context.Background(), &testCase.userId, testCase.listId)
+			err := listRepo.DeleteOneById(context.Background(), &testCase.userId, testCase.listId, testCase.version)
 
 			assert.Equal(t, testCase.expectedErr, err)
 
@@ -652,46 +863,112 @@ func TestDeleteListById(t *testing.T) {
 	}
 }
 
-// TestIsUserOwnerOfList tests if a user is the owner of a specific list
-func TestIsUserOwnerOfList(t *testing.T) {
+// TestGetUserListRole tests retrieving the role of a user on a list
+func TestGetUserListRole(t *testing.T) {
 	testCases := []struct {
-		name        string
-		userId      int
-		listId      int
-		mockQuery   func(sqlmock.Sqlmock)
-		expectedErr error
+		name         string
+		userId       int
+		listId       int
+		mockQuery    func(sqlmock.Sqlmock)
+		expectedRole string
+		expectedErr  error
 	}{
 		{
 			name:   "Success",
 			userId: 1,
 			listId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfList
+				query := queryGetUserListRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
-					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
 			},
-			expectedErr: nil,
+			expectedRole: entity.ListRoleViewer,
+			expectedErr:  nil,
 		},
 		{
-			name:   "List not belongs to user",
+			name:   "List not shared with user",
 			userId: 1,
 			listId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfList
+				query := queryGetUserListRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
-					WillReturnError(utils.ErrUserNotOwner)
+					WillReturnError(sql.ErrNoRows)
 			},
-			expectedErr: utils.ErrUserNotOwner,
+			expectedRole: "",
+			expectedErr:  utils.ErrUserNotOwner,
 		},
 		{
 			name:   "DatabaseError",
 			userId: 1,
 			listId: 1,
 			mockQuery: func(mock sqlmock.Sqlmock) {
-				query := queryIsUserOwnerOfList
+				query := queryGetUserListRole
 				mock.ExpectQuery(query).WithArgs(1, 1).
 					WillReturnError(sql.ErrConnDone)
 			},
+			expectedRole: "",
+			expectedErr:  sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			role, err := listRepo.GetUserListRole(context.Background(), testCase.userId, testCase.listId)
+
+			assert.Equal(t, testCase.expectedRole, role)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetUserListIds tests retrieving the ids of the lists shared with a user
+func TestGetUserListIds(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userId      int
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedIds []int
+		expectedErr error
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetUserListIds).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(1).AddRow(4))
+			},
+			expectedIds: []int{1, 4},
+			expectedErr: nil,
+		},
+		{
+			name:   "No lists",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetUserListIds).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id"}))
+			},
+			expectedIds: []int{},
+			expectedErr: nil,
+		},
+		{
+			name:   "DatabaseError",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetUserListIds).WithArgs(1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedIds: nil,
 			expectedErr: sql.ErrConnDone,
 		},
 	}
@@ -707,11 +984,1005 @@ func TestIsUserOwnerOfList(t *testing.T) {
 
 			testCase.mockQuery(mock)
 
-			err := listRepo.IsUserOwnerOfList(context.Background(), testCase.userId, testCase.listId)
+			ids, err := listRepo.GetUserListIds(context.Background(), testCase.userId)
+
+			assert.Equal(t, testCase.expectedIds, ids)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetCollaborators tests retrieving the users a list is shared with
+func TestGetCollaborators(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		listId                int
+		mockQuery             func(sqlmock.Sqlmock)
+		expectedCollaborators []entity.ListCollaborator
+		expectedErr           error
+	}{
+		{
+			name:   "Success",
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "username", "role"}).
+					AddRow(1, "Alice", "alice", "owner").
+					AddRow(2, "Bob", "bob", "viewer")
+				mock.ExpectQuery(queryGetCollaborators).WithArgs(1).WillReturnRows(rows)
+			},
+			expectedCollaborators: []entity.ListCollaborator{
+				{UserId: 1, Name: "Alice", Username: "alice", Role: entity.ListRoleOwner},
+				{UserId: 2, Name: "Bob", Username: "bob", Role: entity.ListRoleViewer},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "NoCollaborators",
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name", "username", "role"})
+				mock.ExpectQuery(queryGetCollaborators).WithArgs(1).WillReturnRows(rows)
+			},
+			expectedCollaborators: []entity.ListCollaborator{},
+			expectedErr:           nil,
+		},
+		{
+			name:   "DatabaseError",
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetCollaborators).WithArgs(1).WillReturnError(sql.ErrConnDone)
+			},
+			expectedCollaborators: nil,
+			expectedErr:           sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			collaborators, err := listRepo.GetCollaborators(context.Background(), testCase.listId)
 
+			assert.Equal(t, testCase.expectedCollaborators, collaborators)
 			assert.Equal(t, testCase.expectedErr, err)
 
 			assertListRepoExpectations(t, mock)
 		})
 	}
 }
+
+// TestAddCollaborator tests sharing a list with another user
+func TestAddCollaborator(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		mockQuery            func(sqlmock.Sqlmock)
+		mockCache            func(*MockCache)
+		expectedCollaborator entity.ListCollaborator
+		expectedErr          error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetUserByUsernameForShare).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Bob"))
+				expectAppendPosition(mock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 2, "a0")
+				mock.ExpectExec(queryAddCollaborator).
+					WithArgs(2, 1, "editor", "a1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "user_lists:2").
+					Return(nil)
+			},
+			expectedCollaborator: entity.ListCollaborator{UserId: 2, Name: "Bob", Username: "bob", Role: entity.ListRoleEditor},
+			expectedErr:          nil,
+		},
+		{
+			name: "UserNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetUserByUsernameForShare).
+					WithArgs("bob").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:            func(mockCache *MockCache) {},
+			expectedCollaborator: entity.ListCollaborator{},
+			expectedErr:          utils.ErrUserNotFound,
+		},
+		{
+			name: "AlreadyCollaborator",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryGetUserByUsernameForShare).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Bob"))
+				expectAppendPosition(mock, lockSpaceUserLists, repository.UsersListsTable, "user_id", 2, "a0")
+				mock.ExpectExec(queryAddCollaborator).
+					WithArgs(2, 1, "editor", "a1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache:            func(mockCache *MockCache) {},
+			expectedCollaborator: entity.ListCollaborator{},
+			expectedErr:          utils.ErrCollaboratorExists,
+		},
+		{
+			name: "TransactionBeginFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+			},
+			mockCache:            func(mockCache *MockCache) {},
+			expectedCollaborator: entity.ListCollaborator{},
+			expectedErr:          sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			collaborator, err := listRepo.AddCollaborator(context.Background(), 1, "bob", entity.ListRoleEditor)
+
+			assert.Equal(t, testCase.expectedCollaborator, collaborator)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateCollaboratorRole tests changing the role of a user on a list
+func TestUpdateCollaboratorRole(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateCollaboratorRole).
+					WithArgs("viewer", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "CollaboratorNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateCollaboratorRole).
+					WithArgs("viewer", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrCollaboratorNotFound,
+		},
+		{
+			name: "DatabaseError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateCollaboratorRole).
+					WithArgs("viewer", 1, 2).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := listRepo.UpdateCollaboratorRole(context.Background(), 1, 2, entity.ListRoleViewer)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestRemoveCollaborator tests removing a user from a shared list
+func TestRemoveCollaborator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRemoveCollaborator).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "user_lists:2").
+					Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "CollaboratorNotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRemoveCollaborator).
+					WithArgs(1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrCollaboratorNotFound,
+		},
+		{
+			name: "DatabaseError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRemoveCollaborator).
+					WithArgs(1, 2).
+					WillReturnError(sql.ErrConnDone)
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			err := listRepo.RemoveCollaborator(context.Background(), 1, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestMoveUserList tests moving a list among the lists of a user, the version of a moved list changes
+func TestMoveUserList(t *testing.T) {
+	afterId := 3
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryLockOrder).WithArgs(lockSpaceUserLists, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(queryAnchorPosition(repository.UsersListsTable, "user_id", "list_id")).
+					WithArgs(1, afterId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("a0"))
+				mock.ExpectQuery(queryNeighbourPosition("MIN", repository.UsersListsTable, "user_id", "list_id", ">")).
+					WithArgs(1, 2, "a0").
+					WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(nil))
+				mock.ExpectExec(queryUpdatePosition(repository.UsersListsTable, "user_id", "list_id")).
+					WithArgs("a1", 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(mock, repository.ListsTable, 2)
+				mock.ExpectCommit()
+				mock.ExpectQuery(queryGetListMemberIds).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(4))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "list_by_id:2").
+					Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:1").
+					Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:4").
+					Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "AnchorNotSharedWithUser",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryLockOrder).WithArgs(lockSpaceUserLists, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(queryAnchorPosition(repository.UsersListsTable, "user_id", "list_id")).
+					WithArgs(1, afterId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrMoveAnchorNotFound,
+		},
+		{
+			name: "TransactionBeginFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			err := listRepo.MoveUserList(context.Background(), 1, 2, entity.MoveInput{AfterId: &afterId})
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetTrashedUserLists tests retrieving the trashed lists owned by a user
+func TestGetTrashedUserLists(t *testing.T) {
+	deletedAt := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		userId        int
+		mockQuery     func(sqlmock.Sqlmock)
+		expectedLists []entity.TrashedList
+		expectedErr   error
+	}{
+		{
+			name:   "Success",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetTrashedLists).
+					WithArgs(1, entity.ListRoleOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "deleted_at"}).
+						AddRow(1, "Test List", "Test Description", deletedAt))
+			},
+			expectedLists: []entity.TrashedList{
+				{List: entity.List{Id: 1, Title: "Test List", Description: "Test Description"}, DeletedAt: deletedAt},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "EmptyTrash",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetTrashedLists).
+					WithArgs(1, entity.ListRoleOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "deleted_at"}))
+			},
+			expectedLists: []entity.TrashedList{},
+			expectedErr:   nil,
+		},
+		{
+			name:   "DatabaseError",
+			userId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetTrashedLists).
+					WithArgs(1, entity.ListRoleOwner).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedLists: nil,
+			expectedErr:   sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			lists, err := listRepo.GetTrashedUserLists(context.Background(), testCase.userId)
+
+			assert.Equal(t, testCase.expectedLists, lists)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+		})
+	}
+}
+
+// TestRestoreUserList tests restoring a trashed list by its owner
+func TestRestoreUserList(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userId      int
+		listId      int
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name:   "Success",
+			userId: 2,
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRestoreList).
+					WithArgs(1, 2, entity.ListRoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectOutboxEvents(mock, messagebroker.ListUpdatedTopic, 1)
+				mock.ExpectCommit()
+
+				mock.ExpectQuery(queryGetListMemberIds).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "user_lists:2").Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:3").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "NotInTrash",
+			userId: 2,
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRestoreList).
+					WithArgs(1, 2, entity.ListRoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrListNotFound,
+		},
+		{
+			name:   "DatabaseError",
+			userId: 2,
+			listId: 1,
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRestoreList).
+					WithArgs(1, 2, entity.ListRoleOwner).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			err := listRepo.RestoreUserList(context.Background(), testCase.userId, testCase.listId)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPurgeListTrash tests permanently deleting the lists trashed before a point in time along with their items
+func TestPurgeListTrash(t *testing.T) {
+	deletedBefore := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		mockQuery      func(sqlmock.Sqlmock)
+		mockCache      func(*MockCache)
+		expectedPurged entity.PurgedTrash
+		expectedErr    error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryPurgeListItems).
+					WithArgs(deletedBefore).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+				mock.ExpectQuery(queryPurgeLists).
+					WithArgs(deletedBefore).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectOutboxEvents(mock, messagebroker.ItemDeletedTopic, 2)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:4").Return(nil)
+				mockCache.On("Delete", mock.Anything, "item_by_id:5").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:1").Return(nil)
+			},
+			expectedPurged: entity.PurgedTrash{ListIds: []int{1}, ItemIds: []int{4, 5}},
+			expectedErr:    nil,
+		},
+		{
+			name: "ItemsQueryError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryPurgeListItems).
+					WithArgs(deletedBefore).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:      func(mockCache *MockCache) {},
+			expectedPurged: entity.PurgedTrash{},
+			expectedErr:    sql.ErrConnDone,
+		},
+		{
+			name: "ListsQueryError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(queryPurgeListItems).
+					WithArgs(deletedBefore).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(queryPurgeLists).
+					WithArgs(deletedBefore).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:      func(mockCache *MockCache) {},
+			expectedPurged: entity.PurgedTrash{},
+			expectedErr:    sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+			testCase.mockCache(mockCache)
+
+			purged, err := listRepo.PurgeTrash(context.Background(), deletedBefore)
+
+			assert.Equal(t, testCase.expectedPurged, purged)
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertListRepoExpectations(t, mock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRevertListById tests setting a list back to the state of a revision
+func TestRevertListById(t *testing.T) {
+	sqlxDB, sqlMock, listRepo, mockCache := setupListRepoTest(t)
+	defer sqlxDB.Close()
+
+	userId := 2
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(querySelectListState).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("New Title", "Description"))
+	sqlMock.ExpectQuery(queryUpdateListById).
+		WithArgs("Old Title", "Description", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Old Title", "Description"))
+	sqlMock.ExpectExec(queryInsertListRevision).
+		WithArgs(1, 2,
+			[]byte(`{"title":"New Title","description":"Description"}`),
+			[]byte(`{"title":"Old Title","description":"Description"}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectOutboxEvents(sqlMock, messagebroker.ListUpdatedTopic, 1)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectQuery(queryGetListMemberIds).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+	mockCache.On("Delete", mock.Anything, "list_by_id:1").Return(nil)
+	mockCache.On("Delete", mock.Anything, "user_lists:2").Return(nil)
+
+	err := listRepo.RevertOneById(context.Background(), &userId, 1, entity.ListState{Title: "Old Title", Description: "Description"})
+
+	assert.NoError(t, err)
+	assertListRepoExpectations(t, sqlMock)
+	mockCache.AssertExpectations(t)
+}
+
+// expectInsertUserList expects a list to be stored as the first list of the user
+func expectInsertUserList(sqlMock sqlmock.Sqlmock, userId, listId int, title, description string) {
+	sqlMock.ExpectQuery(queryInsertList).
+		WithArgs(title, description).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(listId, 1))
+	expectAppendPosition(sqlMock, lockSpaceUserLists, repository.UsersListsTable, "user_id", userId, nil)
+	sqlMock.ExpectExec(queryLinkUser).
+		WithArgs(userId, listId, "owner", "a0").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectInsertListItem expects an item to be stored after the last position of a list
+func expectInsertListItem(sqlMock sqlmock.Sqlmock, listId, itemId int, last interface{}, position string, args ...driver.Value) {
+	sqlMock.ExpectQuery(queryCreateItem).WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(itemId, 1))
+	expectAppendPosition(sqlMock, lockSpaceListItems, repository.ListsItemsTable, "list_id", listId, last)
+	sqlMock.ExpectExec(qyeryCreateListsItems).WithArgs(listId, itemId, position).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// TestCloneUserList tests copying a list with its items into a new list of the user
+func TestCloneUserList(t *testing.T) {
+	title := "Release 2.0"
+	parentId := 1
+	copyParentId := 20
+
+	testCases := []struct {
+		name         string
+		input        entity.CloneListInput
+		mockQuery    func(sqlmock.Sqlmock)
+		mockCache    func(*MockCache)
+		expectedCopy entity.ListCopy
+		expectedErr  error
+	}{
+		{
+			name:  "Keeps done state",
+			input: entity.CloneListInput{Title: &title},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryGetSourceList).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Release", "Checklist"))
+				expectInsertUserList(sqlMock, 123, 9, "Release 2.0", "Checklist")
+				sqlMock.ExpectQuery(queryGetAllItems).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(itemRowColumns).
+						AddRow(1, "Build", "", true, nil, nil, nil, nil, nil, nil, nil).
+						AddRow(7, "Tag", "", false, nil, nil, nil, nil, parentId, nil, nil))
+				expectInsertListItem(sqlMock, 9, 20, nil, "a0", "Build", "", nil, nil, nil, nil, nil)
+				expectInsertListItem(sqlMock, 9, 21, "a0", "a1", "Tag", "", nil, nil, nil, nil, copyParentId)
+				sqlMock.ExpectExec(queryMarkCopiesDone).WithArgs(pq.Array([]int{20})).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectOutboxEvents(sqlMock, messagebroker.ListCreatedTopic, 1)
+				expectOutboxEvents(sqlMock, messagebroker.ItemCreatedTopic, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Set", mock.Anything, "list_by_id:9", mock.Anything, mock.Anything).Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:123").Return(nil)
+			},
+			expectedCopy: entity.ListCopy{
+				List: entity.List{Id: 9, Title: "Release 2.0", Description: "Checklist", Version: 1},
+				Items: []entity.Item{
+					{Id: 20, Title: "Build", Done: true, Version: 1},
+					{Id: 21, Title: "Tag", ParentId: &copyParentId, Version: 1},
+				},
+			},
+		},
+		{
+			name:  "Resets done state",
+			input: entity.CloneListInput{ResetDone: true},
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryGetSourceList).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Release", "Checklist"))
+				expectInsertUserList(sqlMock, 123, 9, "Release", "Checklist")
+				sqlMock.ExpectQuery(queryGetAllItems).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows(itemRowColumns).
+						AddRow(1, "Build", "", true, nil, nil, nil, nil, nil, nil, nil))
+				expectInsertListItem(sqlMock, 9, 20, nil, "a0", "Build", "", nil, nil, nil, nil, nil)
+				expectOutboxEvents(sqlMock, messagebroker.ListCreatedTopic, 1)
+				expectOutboxEvents(sqlMock, messagebroker.ItemCreatedTopic, 1)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Set", mock.Anything, "list_by_id:9", mock.Anything, mock.Anything).Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:123").Return(nil)
+			},
+			expectedCopy: entity.ListCopy{
+				List:  entity.List{Id: 9, Title: "Release", Description: "Checklist", Version: 1},
+				Items: []entity.Item{{Id: 20, Title: "Build", Version: 1}},
+			},
+		},
+		{
+			name: "List in the trash",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryGetSourceList).WithArgs(3).WillReturnError(sql.ErrNoRows)
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrListNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			listCopy, err := listRepo.CloneUserList(context.Background(), 123, 3, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedCopy, listCopy)
+
+			assertListRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateUserListFromTemplate tests creating a list of the user from a template
+func TestCreateUserListFromTemplate(t *testing.T) {
+	templateParentId := 4
+	copyParentId := 20
+
+	testCases := []struct {
+		name         string
+		mockQuery    func(sqlmock.Sqlmock)
+		mockCache    func(*MockCache)
+		expectedCopy entity.ListCopy
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryGetSourceTemplate).WithArgs(2, 123).
+					WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Onboarding", "First week"))
+				expectInsertUserList(sqlMock, 123, 9, "Onboarding", "First week")
+				sqlMock.ExpectQuery(queryGetTemplateItems).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "title", "description"}).
+						AddRow(4, nil, "Laptop", "").
+						AddRow(5, templateParentId, "Accounts", "Mail and chat"))
+				expectInsertListItem(sqlMock, 9, 20, nil, "a0", "Laptop", "", nil, nil, nil, nil, nil)
+				expectInsertListItem(sqlMock, 9, 21, "a0", "a1", "Accounts", "Mail and chat", nil, nil, nil, nil, copyParentId)
+				expectOutboxEvents(sqlMock, messagebroker.ListCreatedTopic, 1)
+				expectOutboxEvents(sqlMock, messagebroker.ItemCreatedTopic, 2)
+				sqlMock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Set", mock.Anything, "list_by_id:9", mock.Anything, mock.Anything).Return(nil)
+				mockCache.On("Delete", mock.Anything, "user_lists:123").Return(nil)
+			},
+			expectedCopy: entity.ListCopy{
+				List: entity.List{Id: 9, Title: "Onboarding", Description: "First week", Version: 1},
+				Items: []entity.Item{
+					{Id: 20, Title: "Laptop", Version: 1},
+					{Id: 21, Title: "Accounts", Description: "Mail and chat", ParentId: &copyParentId, Version: 1},
+				},
+			},
+		},
+		{
+			name: "Template of another user",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(queryGetSourceTemplate).WithArgs(2, 123).WillReturnError(sql.ErrNoRows)
+				sqlMock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrListTemplateNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, listRepo, mockCache := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			listCopy, err := listRepo.CreateUserListFromTemplate(context.Background(), 123, 2, entity.UseListTemplateInput{})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedCopy, listCopy)
+
+			assertListRepoExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateListTemplate tests saving a list with a subtask as a template, the subtask listed before its parent
+// is stored after it
+func TestCreateListTemplate(t *testing.T) {
+	sqlxDB, sqlMock, listRepo, _ := setupListRepoTest(t)
+	defer sqlxDB.Close()
+
+	createdAt := time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC)
+	title := "Release checklist"
+	parentId := 1
+	templateParentId := 30
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery(queryGetSourceList).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "description"}).AddRow("Release 1.0", "Steps"))
+	sqlMock.ExpectQuery(queryInsertTemplate).WithArgs(123, "Release checklist", "Steps").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(2, createdAt))
+	sqlMock.ExpectQuery(queryGetAllItems).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow(7, "Tag", "", true, nil, nil, nil, nil, parentId, nil, nil).
+			AddRow(1, "Build", "Run CI", true, nil, nil, nil, nil, nil, nil, nil))
+	sqlMock.ExpectQuery(queryInsertTemplateItem).WithArgs(2, nil, "Build", "Run CI", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+	sqlMock.ExpectQuery(queryInsertTemplateItem).WithArgs(2, templateParentId, "Tag", "", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(31))
+	sqlMock.ExpectCommit()
+
+	template, err := listRepo.CreateTemplate(context.Background(), 123, 3, entity.CreateListTemplateInput{Title: &title})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ListTemplate{
+		Id:          2,
+		UserId:      123,
+		Title:       "Release checklist",
+		Description: "Steps",
+		ItemCount:   2,
+		Items: []entity.ListTemplateItem{
+			{Id: 30, Title: "Build", Description: "Run CI"},
+			{Id: 31, ParentId: &templateParentId, Title: "Tag"},
+		},
+		CreatedAt: createdAt,
+	}, template)
+	assertListRepoExpectations(t, sqlMock)
+}
+
+// TestGetUserTemplates tests retrieving the templates of a user
+func TestGetUserTemplates(t *testing.T) {
+	sqlxDB, sqlMock, listRepo, _ := setupListRepoTest(t)
+	defer sqlxDB.Close()
+
+	createdAt := time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC)
+
+	sqlMock.ExpectQuery(queryGetUserTemplates).WithArgs(123).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "count", "created_at"}).
+			AddRow(2, "Release checklist", "Steps", 5, createdAt))
+
+	templates, err := listRepo.GetUserTemplates(context.Background(), 123)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ListTemplate{
+		{Id: 2, UserId: 123, Title: "Release checklist", Description: "Steps", ItemCount: 5, CreatedAt: createdAt},
+	}, templates)
+	assertListRepoExpectations(t, sqlMock)
+}
+
+// TestGetUserTemplate tests retrieving a template of a user with its items
+func TestGetUserTemplate(t *testing.T) {
+	createdAt := time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		mockQuery        func(sqlmock.Sqlmock)
+		expectedTemplate entity.ListTemplate
+		expectedErr      error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(queryGetUserTemplate).WithArgs(2, 123).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "created_at"}).
+						AddRow(2, "Release checklist", "Steps", createdAt))
+				sqlMock.ExpectQuery(queryGetTemplateItems).WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "title", "description"}).
+						AddRow(30, nil, "Build", "Run CI"))
+			},
+			expectedTemplate: entity.ListTemplate{
+				Id:          2,
+				UserId:      123,
+				Title:       "Release checklist",
+				Description: "Steps",
+				ItemCount:   1,
+				Items:       []entity.ListTemplateItem{{Id: 30, Title: "Build", Description: "Run CI"}},
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name: "Template of another user",
+			mockQuery: func(sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectQuery(queryGetUserTemplate).WithArgs(2, 123).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: utils.ErrListTemplateNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+
+			template, err := listRepo.GetUserTemplate(context.Background(), 123, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedTemplate, template)
+			assertListRepoExpectations(t, sqlMock)
+		})
+	}
+}
+
+// TestDeleteUserTemplate tests deleting a template of a user
+func TestDeleteUserTemplate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{name: "Success", rowsAffected: 1},
+		{name: "Template of another user", rowsAffected: 0, expectedErr: utils.ErrListTemplateNotFound},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, listRepo, _ := setupListRepoTest(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectExec(queryDeleteUserTemplate).WithArgs(2, 123).
+				WillReturnResult(sqlmock.NewResult(0, testCase.rowsAffected))
+
+			err := listRepo.DeleteUserTemplate(context.Background(), 123, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assertListRepoExpectations(t, sqlMock)
+		})
+	}
+}
@@ -26,6 +26,12 @@ func (m *MockCache) Set(ctx context.Context, key string, value interface{}, ttl
 	return args.Error(0)
 }
 
+// SetNX simulates setting a value in the cache only if the key is absent
+func (m *MockCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, key, value, ttl)
+	return args.Get(0).(bool), args.Error(1)
+}
+
 // Delete simulates deleting a key from the cache
 func (m *MockCache) Delete(ctx context.Context, key string) error {
 	args := m.Called(ctx, key)
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// OutboxRepo handles the events of the outbox, events are written by the other repositories
+// in the transaction of the change they describe and read back here to be published
+type OutboxRepo struct {
+	db *database.Database
+}
+
+// NewOutboxRepo creates a new instance of OutboxRepo
+func NewOutboxRepo(db *database.Database) *OutboxRepo {
+	return &OutboxRepo{db}
+}
+
+// ClaimPending leases up to limit pending events that are available, oldest first, for the lease duration.
+// Rows claimed by a concurrent relay are skipped, an event whose relay stops before marking it sent
+// becomes available again once its lease runs out
+func (r *OutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET available_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM %s
+			WHERE sent_at IS NULL AND available_at <= NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, topic, payload, attempts`, OutboxEventsTable, OutboxEventsTable)
+
+	rows, err := r.db.Querier.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []entity.OutboxEvent{}
+	for rows.Next() {
+		var event entity.OutboxEvent
+		if err := rows.Scan(&event.Id, &event.Topic, &event.Payload, &event.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(events, func(i, j int) bool { return events[i].Id < events[j].Id })
+
+	return events, nil
+}
+
+// MarkSent marks the events as published so that they are not claimed again
+func (r *OutboxRepo) MarkSent(ctx context.Context, eventIds []int) error {
+	query := fmt.Sprintf("UPDATE %s SET sent_at = NOW() WHERE id = ANY($1)", OutboxEventsTable)
+
+	_, err := r.db.Executer.Exec(query, pq.Array(eventIds))
+
+	return err
+}
+
+// MarkFailed records a failed attempt to publish an event and makes it available again after the delay
+func (r *OutboxRepo) MarkFailed(ctx context.Context, eventId int, lastError string, delay time.Duration) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET attempts = attempts + 1, last_error = $2, available_at = NOW() + make_interval(secs => $3)
+		WHERE id = $1`, OutboxEventsTable)
+
+	_, err := r.db.Executer.Exec(query, eventId, lastError, delay.Seconds())
+
+	return err
+}
+
+// Release makes claimed events that were not attempted available again after the delay
+func (r *OutboxRepo) Release(ctx context.Context, eventIds []int, delay time.Duration) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET available_at = NOW() + make_interval(secs => $2)
+		WHERE id = ANY($1) AND sent_at IS NULL`, OutboxEventsTable)
+
+	_, err := r.db.Executer.Exec(query, pq.Array(eventIds), delay.Seconds())
+
+	return err
+}
+
+// GetBacklog retrieves the number of pending events and the age of the oldest of them
+func (r *OutboxRepo) GetBacklog(ctx context.Context) (entity.OutboxBacklog, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)
+		FROM %s
+		WHERE sent_at IS NULL`, OutboxEventsTable)
+
+	var backlog entity.OutboxBacklog
+	var oldestAge float64
+	if err := r.db.Querier.QueryRow(query).Scan(&backlog.Pending, &oldestAge); err != nil {
+		return entity.OutboxBacklog{}, err
+	}
+
+	backlog.OldestAge = time.Duration(oldestAge * float64(time.Second))
+
+	return backlog, nil
+}
+
+// PurgeSent permanently deletes the events sent before the given time and returns their number
+func (r *OutboxRepo) PurgeSent(ctx context.Context, sentBefore time.Time) (int, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE sent_at < $1", OutboxEventsTable)
+
+	result, err := r.db.Executer.Exec(query, sentBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(purged), nil
+}
+
+// insertOutboxEvent records an event in the outbox in the transaction of the change it describes,
+// so that it is published if and only if the change is committed
+func insertOutboxEvent(tx *sqlx.Tx, topic string, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (topic, payload) VALUES ($1, $2)", OutboxEventsTable)
+
+	_, err = tx.Exec(query, topic, payload)
+	return err
+}
+
+// insertListCreatedEvent records that the user created the list
+func insertListCreatedEvent(tx *sqlx.Tx, userId int, list entity.List) error {
+	return insertOutboxEvent(tx, messagebroker.ListCreatedTopic, entity.ListCreatedEvent{UserId: userId, List: list})
+}
+
+// insertListUpdatedEvent records that the user updated the list
+func insertListUpdatedEvent(tx *sqlx.Tx, userId, listId int) error {
+	return insertOutboxEvent(tx, messagebroker.ListUpdatedTopic, entity.ListUpdatedEvent{UserId: userId, ListId: listId})
+}
+
+// insertListDeletedEvent records that the list was deleted
+func insertListDeletedEvent(tx *sqlx.Tx, listId int) error {
+	return insertOutboxEvent(tx, messagebroker.ListDeletedTopic, entity.ListDeletedEvent{ListId: listId})
+}
+
+// insertListCopyEvents records that the user created the list together with its items
+func insertListCopyEvents(tx *sqlx.Tx, userId int, list entity.List, items []entity.Item) error {
+	if err := insertListCreatedEvent(tx, userId, list); err != nil {
+		return err
+	}
+
+	return insertItemsCreatedEvents(tx, userId, list.Id, items)
+}
+
+// insertItemCreatedEvent records that the user created the item in the list
+func insertItemCreatedEvent(tx *sqlx.Tx, userId, listId int, item entity.Item) error {
+	return insertOutboxEvent(tx, messagebroker.ItemCreatedTopic, entity.ItemCreatedEvent{UserId: userId, ListId: listId, Item: item})
+}
+
+// insertItemsCreatedEvents records that the user created the items in the list
+func insertItemsCreatedEvents(tx *sqlx.Tx, userId, listId int, items []entity.Item) error {
+	for _, item := range items {
+		if err := insertItemCreatedEvent(tx, userId, listId, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertItemUpdatedEvent records that the user updated the item of the list
+func insertItemUpdatedEvent(tx *sqlx.Tx, userId, listId, itemId int) error {
+	return insertOutboxEvent(tx, messagebroker.ItemUpdatedTopic, entity.ItemUpdatedEvent{UserId: userId, ListId: listId, ItemId: itemId})
+}
+
+// insertItemsUpdatedEvents records that the user updated the items of the list
+func insertItemsUpdatedEvents(tx *sqlx.Tx, userId, listId int, itemIds []int) error {
+	for _, itemId := range itemIds {
+		if err := insertItemUpdatedEvent(tx, userId, listId, itemId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertItemsDeletedEvents records that the items were deleted
+func insertItemsDeletedEvents(tx *sqlx.Tx, itemIds []int) error {
+	for _, itemId := range itemIds {
+		if err := insertOutboxEvent(tx, messagebroker.ItemDeletedTopic, entity.ItemDeletedEvent{ItemId: itemId}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertItemReminderDueEvent records that the reminder of the item is due
+func insertItemReminderDueEvent(tx *sqlx.Tx, reminder entity.ItemReminderDueEvent) error {
+	return insertOutboxEvent(tx, messagebroker.ItemReminderDueTopic, reminder)
+}
+
+// insertCommentCreatedEvent records that the user created the comment
+func insertCommentCreatedEvent(tx *sqlx.Tx, userId int, comment entity.Comment) error {
+	return insertOutboxEvent(tx, messagebroker.CommentCreatedTopic, entity.CommentCreatedEvent{UserId: userId, Comment: comment})
+}
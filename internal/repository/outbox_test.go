@@ -0,0 +1,149 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	queryInsertOutboxEvent = fmt.Sprintf("INSERT INTO %s \\(topic, payload\\) VALUES \\(\\$1, \\$2\\)", repository.OutboxEventsTable)
+	queryClaimOutboxEvents = fmt.Sprintf("UPDATE %s SET available_at = NOW\\(\\) \\+ make_interval\\(secs => \\$2\\) WHERE id IN \\( SELECT id FROM %s WHERE sent_at IS NULL AND available_at <= NOW\\(\\) ORDER BY id LIMIT \\$1 FOR UPDATE SKIP LOCKED \\) RETURNING id, topic, payload, attempts", repository.OutboxEventsTable, repository.OutboxEventsTable)
+	queryMarkOutboxSent    = fmt.Sprintf("UPDATE %s SET sent_at = NOW\\(\\) WHERE id = ANY\\(\\$1\\)", repository.OutboxEventsTable)
+	queryMarkOutboxFailed  = fmt.Sprintf("UPDATE %s SET attempts = attempts \\+ 1, last_error = \\$2, available_at = NOW\\(\\) \\+ make_interval\\(secs => \\$3\\) WHERE id = \\$1", repository.OutboxEventsTable)
+	queryReleaseOutbox     = fmt.Sprintf("UPDATE %s SET available_at = NOW\\(\\) \\+ make_interval\\(secs => \\$2\\) WHERE id = ANY\\(\\$1\\) AND sent_at IS NULL", repository.OutboxEventsTable)
+	queryGetOutboxBacklog  = fmt.Sprintf("SELECT COUNT\\(\\*\\), COALESCE\\(EXTRACT\\(EPOCH FROM NOW\\(\\) - MIN\\(created_at\\)\\), 0\\) FROM %s WHERE sent_at IS NULL", repository.OutboxEventsTable)
+	queryPurgeOutboxSent   = fmt.Sprintf("DELETE FROM %s WHERE sent_at < \\$1", repository.OutboxEventsTable)
+)
+
+// expectOutboxEvents expects count events of the topic to be recorded in the outbox
+func expectOutboxEvents(sqlMock sqlmock.Sqlmock, topic string, count int) {
+	for i := 0; i < count; i++ {
+		sqlMock.ExpectExec(queryInsertOutboxEvent).WithArgs(topic, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+}
+
+// setupOutboxRepoTest initializes the database and repository for OutboxRepo tests
+func setupOutboxRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.OutboxRepo) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	return sqlxDB, mock, repository.NewOutboxRepo(database.New(sqlxDB))
+}
+
+// TestClaimPendingOutboxEvents tests leasing the pending events of the outbox
+func TestClaimPendingOutboxEvents(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expected    []entity.OutboxEvent
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryClaimOutboxEvents).WithArgs(10, float64(60)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts"}).
+						AddRow(5, "item_updated", []byte(`{"item_id":2}`), 1).
+						AddRow(3, "item_created", []byte(`{"item_id":1}`), 0))
+			},
+			expected: []entity.OutboxEvent{
+				{Id: 3, Topic: "item_created", Payload: []byte(`{"item_id":1}`), Attempts: 0},
+				{Id: 5, Topic: "item_updated", Payload: []byte(`{"item_id":2}`), Attempts: 1},
+			},
+		},
+		{
+			name: "Nothing pending",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryClaimOutboxEvents).WithArgs(10, float64(60)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts"}))
+			},
+			expected: []entity.OutboxEvent{},
+		},
+		{
+			name: "DatabaseError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryClaimOutboxEvents).WithArgs(10, float64(60)).WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			sqlxDB, mock, outboxRepo := setupOutboxRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			events, err := outboxRepo.ClaimPending(context.Background(), 10, time.Minute)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expected, events)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestMarkOutboxEvents tests recording the outcome of publishing the events
+func TestMarkOutboxEvents(t *testing.T) {
+	sqlxDB, mock, outboxRepo := setupOutboxRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectExec(queryMarkOutboxSent).WithArgs(pq.Array([]int{3, 4})).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(queryMarkOutboxFailed).WithArgs(5, "broker unavailable", float64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(queryReleaseOutbox).WithArgs(pq.Array([]int{6, 7}), float64(2)).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, outboxRepo.MarkSent(context.Background(), []int{3, 4}))
+	assert.NoError(t, outboxRepo.MarkFailed(context.Background(), 5, "broker unavailable", 2*time.Second))
+	assert.NoError(t, outboxRepo.Release(context.Background(), []int{6, 7}, 2*time.Second))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetOutboxBacklog tests retrieving the size and age of the backlog of the outbox
+func TestGetOutboxBacklog(t *testing.T) {
+	sqlxDB, mock, outboxRepo := setupOutboxRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetOutboxBacklog).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "age"}).AddRow(4, 1.5))
+
+	backlog, err := outboxRepo.GetBacklog(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OutboxBacklog{Pending: 4, OldestAge: 1500 * time.Millisecond}, backlog)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPurgeSentOutboxEvents tests deleting the events sent before the retention cutoff
+func TestPurgeSentOutboxEvents(t *testing.T) {
+	sqlxDB, mock, outboxRepo := setupOutboxRepoTest(t)
+	defer sqlxDB.Close()
+
+	sentBefore := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(queryPurgeOutboxSent).WithArgs(sentBefore).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := outboxRepo.PurgeSent(context.Background(), sentBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+)
+
+// pageCacheKey returns the key a page of a collection is cached under for the current generation of the collection.
+// The generation is kept at the key of the collection, so deleting that key invalidates every cached page at once.
+// An empty key is returned when the generation cannot be read or stored, the page is not cached then
+func pageCacheKey(ctx context.Context, c *cache.Cache, log logger.Interface, collectionKey string, page cacheKey, ownerId int, query string) string {
+	var generation int64
+
+	exists, err := c.Replica.Get(ctx, collectionKey, &generation)
+	if err != nil {
+		log.Errorf("cache error for key %s: %v", collectionKey, err)
+		return ""
+	}
+
+	if !exists {
+		generation = time.Now().UnixNano()
+		if err := c.Master.Set(ctx, collectionKey, generation, page.TTL); err != nil {
+			log.Errorf("failed to set cache for key %s: %v", collectionKey, err)
+			return ""
+		}
+	}
+
+	return fmt.Sprintf(page.Pattern, ownerId, generation, query)
+}
+
+// pageClause returns the keyset condition, the ordering and the condition arguments of a page sorted by the expression
+// with the Id column breaking ties. The arguments are numbered from argIndex and the condition is empty on the first page
+func pageClause(query entity.PageQuery, sortExpr, idColumn string, argIndex int) (string, string, []interface{}, error) {
+	after, err := query.After()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	direction, operator := "ASC", ">"
+	if query.Descending() {
+		direction, operator = "DESC", "<"
+	}
+
+	orderBy := fmt.Sprintf("%s %s, %s %s", sortExpr, direction, idColumn, direction)
+
+	if after == nil {
+		return "", orderBy, nil, nil
+	}
+
+	condition := fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", sortExpr, idColumn, operator, argIndex, argIndex+1)
+
+	return condition, orderBy, []interface{}{after.Key, after.Id}, nil
+}
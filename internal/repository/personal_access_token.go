@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/lib/pq"
+)
+
+// PersonalAccessTokenRepo handles persistence of personal access tokens
+type PersonalAccessTokenRepo struct {
+	db *database.Database
+}
+
+// NewPersonalAccessTokenRepo creates a new instance of PersonalAccessTokenRepo
+func NewPersonalAccessTokenRepo(db *database.Database) *PersonalAccessTokenRepo {
+	return &PersonalAccessTokenRepo{db}
+}
+
+// Create stores a new personal access token and fills in its Id and creation time
+func (r *PersonalAccessTokenRepo) Create(ctx context.Context, accessToken *entity.PersonalAccessToken) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, name, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`, PersonalAccessTokensTable)
+
+	err := r.db.Querier.QueryRow(
+		query,
+		accessToken.UserId,
+		accessToken.Name,
+		accessToken.TokenHash,
+		pq.Array(accessToken.Scopes),
+		accessToken.ExpiresAt,
+	).Scan(&accessToken.Id, &accessToken.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return accessToken.Id, nil
+}
+
+// GetByHash retrieves a personal access token by the hash of its value
+func (r *PersonalAccessTokenRepo) GetByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	var accessToken entity.PersonalAccessToken
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, name, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM %s
+		WHERE token_hash = $1`, PersonalAccessTokensTable)
+
+	err := r.db.Querier.QueryRow(query, tokenHash).Scan(
+		&accessToken.Id,
+		&accessToken.UserId,
+		&accessToken.Name,
+		pq.Array(&accessToken.Scopes),
+		&accessToken.ExpiresAt,
+		&accessToken.LastUsedAt,
+		&accessToken.RevokedAt,
+		&accessToken.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accessToken, utils.ErrPersonalAccessTokenNotFound
+		}
+
+		return accessToken, err
+	}
+
+	return accessToken, nil
+}
+
+// GetAllByUserId retrieves the personal access tokens of a user that have not been revoked
+func (r *PersonalAccessTokenRepo) GetAllByUserId(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	accessTokens := []entity.PersonalAccessToken{}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at
+		FROM %s
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id`, PersonalAccessTokensTable)
+
+	rows, err := r.db.Querier.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accessToken entity.PersonalAccessToken
+		err := rows.Scan(
+			&accessToken.Id,
+			&accessToken.UserId,
+			&accessToken.Name,
+			pq.Array(&accessToken.Scopes),
+			&accessToken.ExpiresAt,
+			&accessToken.LastUsedAt,
+			&accessToken.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		accessTokens = append(accessTokens, accessToken)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accessTokens, nil
+}
+
+// Revoke revokes a personal access token owned by the user
+func (r *PersonalAccessTokenRepo) Revoke(ctx context.Context, userId, tokenId int) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`, PersonalAccessTokensTable)
+
+	result, err := r.db.Executer.Exec(query, tokenId, userId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records the time the token was last used, writes are throttled to once a minute per token
+func (r *PersonalAccessTokenRepo) UpdateLastUsed(ctx context.Context, tokenId int) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, PersonalAccessTokensTable)
+
+	_, err := r.db.Executer.Exec(query, tokenId)
+
+	return err
+}
@@ -0,0 +1,229 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	queryCreatePersonalAccessToken    = fmt.Sprintf("INSERT INTO %s \\(user_id, name, token_hash, scopes, expires_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\) RETURNING id, created_at", repository.PersonalAccessTokensTable)
+	queryGetPersonalAccessTokenByHash = fmt.Sprintf("SELECT id, user_id, name, scopes, expires_at, last_used_at, revoked_at, created_at FROM %s WHERE token_hash = \\$1", repository.PersonalAccessTokensTable)
+	queryGetUserPersonalAccessTokens  = fmt.Sprintf("SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at FROM %s WHERE user_id = \\$1 AND revoked_at IS NULL ORDER BY id", repository.PersonalAccessTokensTable)
+	queryRevokePersonalAccessToken    = fmt.Sprintf("UPDATE %s SET revoked_at = NOW\\(\\) WHERE id = \\$1 AND user_id = \\$2 AND revoked_at IS NULL", repository.PersonalAccessTokensTable)
+)
+
+// setupPersonalAccessTokenRepoTest initializes the database and repository for PersonalAccessTokenRepo tests
+func setupPersonalAccessTokenRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.PersonalAccessTokenRepo) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	return sqlxDB, mock, repository.NewPersonalAccessTokenRepo(database.New(sqlxDB))
+}
+
+// TestCreatePersonalAccessToken tests storing a personal access token
+func TestCreatePersonalAccessToken(t *testing.T) {
+	createdAt := time.Now()
+	scopes := []string{entity.ScopeListsRead, entity.ScopeItemsWrite}
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedId  int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreatePersonalAccessToken).
+					WithArgs(1, "ci", "hash", pq.Array(scopes), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+			},
+			expectedId:  5,
+			expectedErr: nil,
+		},
+		{
+			name: "SQLConnectionFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreatePersonalAccessToken).
+					WithArgs(1, "ci", "hash", pq.Array(scopes), nil).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedId:  0,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, patRepo := setupPersonalAccessTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			accessToken := &entity.PersonalAccessToken{UserId: 1, Name: "ci", TokenHash: "hash", Scopes: scopes}
+			id, err := patRepo.Create(context.Background(), accessToken)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedId, id)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetPersonalAccessTokenByHash tests retrieving a personal access token by its hash
+func TestGetPersonalAccessTokenByHash(t *testing.T) {
+	createdAt := time.Now()
+
+	testCases := []struct {
+		name          string
+		mockQuery     func(sqlmock.Sqlmock)
+		expectedToken entity.PersonalAccessToken
+		expectedErr   error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetPersonalAccessTokenByHash).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}).
+						AddRow(5, 1, "ci", "{lists:read,items:write}", nil, nil, nil, createdAt))
+			},
+			expectedToken: entity.PersonalAccessToken{
+				Id:        5,
+				UserId:    1,
+				Name:      "ci",
+				Scopes:    []string{entity.ScopeListsRead, entity.ScopeItemsWrite},
+				CreatedAt: createdAt,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetPersonalAccessTokenByHash).
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedToken: entity.PersonalAccessToken{},
+			expectedErr:   utils.ErrPersonalAccessTokenNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, patRepo := setupPersonalAccessTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			accessToken, err := patRepo.GetByHash(context.Background(), "hash")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedToken, accessToken)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetAllPersonalAccessTokensByUserId tests listing the active personal access tokens of a user
+func TestGetAllPersonalAccessTokensByUserId(t *testing.T) {
+	createdAt := time.Now()
+
+	sqlxDB, mock, patRepo := setupPersonalAccessTokenRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetUserPersonalAccessTokens).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes", "expires_at", "last_used_at", "created_at"}).
+			AddRow(5, 1, "ci", "{lists:read}", nil, createdAt, createdAt))
+
+	accessTokens, err := patRepo.GetAllByUserId(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.PersonalAccessToken{
+		{Id: 5, UserId: 1, Name: "ci", Scopes: []string{entity.ScopeListsRead}, LastUsedAt: &createdAt, CreatedAt: createdAt},
+	}, accessTokens)
+
+	assertExpectations(t, mock)
+}
+
+// TestRevokePersonalAccessToken tests revoking a personal access token
+func TestRevokePersonalAccessToken(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRevokePersonalAccessToken).
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRevokePersonalAccessToken).
+					WithArgs(5, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrPersonalAccessTokenNotFound,
+		},
+		{
+			name: "SQLConnectionFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryRevokePersonalAccessToken).
+					WithArgs(5, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, patRepo := setupPersonalAccessTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := patRepo.Revoke(context.Background(), 1, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/rank"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Namespaces of the transaction level advisory locks taken on an order
+const (
+	lockSpaceListItems = 1
+	lockSpaceUserLists = 2
+)
+
+// order describes rows kept in a manual order by their position column, such as the items of a list or the
+// lists of a user. Positions are lexicographic ranks, moving a row only changes the position of that row
+type order struct {
+	table       string
+	scopeColumn string
+	keyColumn   string
+	lockSpace   int
+	notFound    error
+}
+
+var (
+	listItemsOrder = order{ListsItemsTable, "list_id", "item_id", lockSpaceListItems, utils.ErrItemNotFound}
+	userListsOrder = order{UsersListsTable, "user_id", "list_id", lockSpaceUserLists, utils.ErrListNotFound}
+)
+
+// lock serializes the transactions changing the rows of the scope until tx ends, so that two rows never
+// get the same position
+func (o order) lock(tx *sqlx.Tx, scopeId int) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock($1, $2)", o.lockSpace, scopeId)
+	return err
+}
+
+// appendPosition locks the scope and returns a position following every row of it
+func (o order) appendPosition(tx *sqlx.Tx, scopeId int) (string, error) {
+	if err := o.lock(tx, scopeId); err != nil {
+		return "", err
+	}
+
+	last, err := o.lastPosition(tx, scopeId)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.Between(last, "")
+}
+
+// lastPosition retrieves the position of the last row of the scope, empty for an empty scope
+func (o order) lastPosition(tx *sqlx.Tx, scopeId int) (string, error) {
+	query := fmt.Sprintf("SELECT MAX(position) FROM %s WHERE %s = $1", o.table, o.scopeColumn)
+
+	var last sql.NullString
+	if err := tx.QueryRow(query, scopeId).Scan(&last); err != nil {
+		return "", err
+	}
+
+	return last.String, nil
+}
+
+// move places a row of the scope between its anchors, a single anchor places the row right next to it
+func (o order) move(tx *sqlx.Tx, scopeId, keyId int, input entity.MoveInput) error {
+	if err := o.lock(tx, scopeId); err != nil {
+		return err
+	}
+
+	var after, before string
+	var err error
+
+	if input.AfterId != nil {
+		if after, err = o.position(tx, scopeId, *input.AfterId); err != nil {
+			return err
+		}
+	}
+	if input.BeforeId != nil {
+		if before, err = o.position(tx, scopeId, *input.BeforeId); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case input.BeforeId == nil:
+		before, err = o.neighbour(tx, "MIN", ">", scopeId, keyId, after)
+	case input.AfterId == nil:
+		after, err = o.neighbour(tx, "MAX", "<", scopeId, keyId, before)
+	case after >= before:
+		err = utils.ErrMoveAnchorsOrder
+	}
+	if err != nil {
+		return err
+	}
+
+	position, err := rank.Between(after, before)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET position = $1 WHERE %s = $2 AND %s = $3", o.table, o.scopeColumn, o.keyColumn)
+
+	result, err := tx.Exec(query, position, scopeId, keyId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return o.notFound
+	}
+
+	return nil
+}
+
+// position retrieves the position of an anchor, ErrMoveAnchorNotFound is returned when it is not part of the scope
+func (o order) position(tx *sqlx.Tx, scopeId, keyId int) (string, error) {
+	query := fmt.Sprintf("SELECT position FROM %s WHERE %s = $1 AND %s = $2", o.table, o.scopeColumn, o.keyColumn)
+
+	var position string
+	if err := tx.QueryRow(query, scopeId, keyId).Scan(&position); err != nil {
+		if err == sql.ErrNoRows {
+			return "", utils.ErrMoveAnchorNotFound
+		}
+		return "", err
+	}
+
+	return position, nil
+}
+
+// neighbour retrieves the closest position before or after the anchor, ignoring the moved row,
+// an empty position is returned at the ends of the order
+func (o order) neighbour(tx *sqlx.Tx, aggregate, comparison string, scopeId, keyId int, anchor string) (string, error) {
+	query := fmt.Sprintf(
+		"SELECT %s(position) FROM %s WHERE %s = $1 AND %s <> $2 AND position %s $3",
+		aggregate, o.table, o.scopeColumn, o.keyColumn, comparison,
+	)
+
+	var position sql.NullString
+	if err := tx.QueryRow(query, scopeId, keyId, anchor).Scan(&position); err != nil {
+		return "", err
+	}
+
+	return position.String, nil
+}
@@ -0,0 +1,42 @@
+package repository_test
+
+import (
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// Advisory lock namespaces of the item and list orders
+const (
+	lockSpaceListItems = 1
+	lockSpaceUserLists = 2
+)
+
+const queryLockOrder = "SELECT pg_advisory_xact_lock\\(\\$1, \\$2\\)"
+
+// queryLastPosition matches the lookup of the last position of an order
+func queryLastPosition(table, scopeColumn string) string {
+	return fmt.Sprintf("SELECT MAX\\(position\\) FROM %s WHERE %s = \\$1", table, scopeColumn)
+}
+
+// queryAnchorPosition matches the lookup of the position of an anchor
+func queryAnchorPosition(table, scopeColumn, keyColumn string) string {
+	return fmt.Sprintf("SELECT position FROM %s WHERE %s = \\$1 AND %s = \\$2", table, scopeColumn, keyColumn)
+}
+
+// queryNeighbourPosition matches the lookup of the closest position before or after an anchor
+func queryNeighbourPosition(aggregate, table, scopeColumn, keyColumn, comparison string) string {
+	return fmt.Sprintf("SELECT %s\\(position\\) FROM %s WHERE %s = \\$1 AND %s <> \\$2 AND position %s \\$3", aggregate, table, scopeColumn, keyColumn, comparison)
+}
+
+// queryUpdatePosition matches the update of the position of a row
+func queryUpdatePosition(table, scopeColumn, keyColumn string) string {
+	return fmt.Sprintf("UPDATE %s SET position = \\$1 WHERE %s = \\$2 AND %s = \\$3", table, scopeColumn, keyColumn)
+}
+
+// expectAppendPosition expects the lock of an order and the lookup of its last position, last is nil for an empty order
+func expectAppendPosition(sqlMock sqlmock.Sqlmock, lockSpace int, table, scopeColumn string, scopeId int, last interface{}) {
+	sqlMock.ExpectExec(queryLockOrder).WithArgs(lockSpace, scopeId).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectQuery(queryLastPosition(table, scopeColumn)).WithArgs(scopeId).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(last))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/pkg/cache"
@@ -11,38 +12,174 @@ import (
 
 type Auth interface {
 	CreateUser(ctx context.Context, user entity.User) (int, error)
-	GetUserByCredentials(ctx context.Context, username string, password string) (entity.User, error)
+	GetUserByUsername(ctx context.Context, username string) (entity.User, error)
+	GetUserRole(ctx context.Context, userId int) (string, error)
+	UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error
+}
+
+type Token interface {
+	CreateRefreshToken(ctx context.Context, refreshToken *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldTokenId int, newToken *entity.RefreshToken) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string) error
+	RevokeAccessToken(ctx context.Context, tokenId string, ttl time.Duration) error
+	IsAccessTokenRevoked(ctx context.Context, tokenId string) (bool, error)
+}
+
+type PersonalAccessToken interface {
+	Create(ctx context.Context, accessToken *entity.PersonalAccessToken) (int, error)
+	GetByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error)
+	GetAllByUserId(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userId, tokenId int) error
+	UpdateLastUsed(ctx context.Context, tokenId int) error
+}
+
+type App interface {
+	Create(ctx context.Context, app *entity.App) (int, error)
+	CreateIfNotExists(ctx context.Context, app *entity.App) error
+	GetAll(ctx context.Context) ([]entity.App, error)
+	GetOneByAppId(ctx context.Context, appId string) (entity.App, error)
+	UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error
+	RotateKey(ctx context.Context, appId, newKeyHash string, previousKeyExpiresAt time.Time) error
+	DeleteOneByAppId(ctx context.Context, appId string) error
+	UpdateLastUsed(ctx context.Context, appId string) error
 }
 
 type User interface {
 	DeleteOneById(ctx context.Context, userId int) error
+	UpdateRole(ctx context.Context, userId int, role string) error
 }
 
 type List interface {
 	CreateUserList(ctx context.Context, userId int, list *entity.List) (int, error)
-	GetAllUserLists(ctx context.Context, userId int) ([]entity.List, error)
-	IsUserOwnerOfList(ctx context.Context, userId, listId int) error
+	CloneUserList(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error)
+	CreateUserListFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error)
+	GetUserListsPage(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error)
+	GetUserListRole(ctx context.Context, userId, listId int) (string, error)
+	GetUserListIds(ctx context.Context, userId int) ([]int, error)
 	GetOneById(ctx context.Context, listId int) (entity.List, error)
 	GetManyByIds(ctx context.Context, ids []int) ([]entity.List, error)
-	UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput) error
-	DeleteOneById(ctx context.Context, userId *int, listId int) error
+	UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput, version *int) error
+	RevertOneById(ctx context.Context, userId *int, listId int, state entity.ListState) error
+	DeleteOneById(ctx context.Context, userId *int, listId int, version *int) error
+	GetCollaborators(ctx context.Context, listId int) ([]entity.ListCollaborator, error)
+	AddCollaborator(ctx context.Context, listId int, username, role string) (entity.ListCollaborator, error)
+	UpdateCollaboratorRole(ctx context.Context, listId, userId int, role string) error
+	RemoveCollaborator(ctx context.Context, listId, userId int) error
+	MoveUserList(ctx context.Context, userId, listId int, input entity.MoveInput) error
+	GetTrashedUserLists(ctx context.Context, userId int) ([]entity.TrashedList, error)
+	RestoreUserList(ctx context.Context, userId, listId int) error
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error)
+	CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error)
+	GetUserTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error)
+	GetUserTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error)
+	DeleteUserTemplate(ctx context.Context, userId, templateId int) error
 }
 
 type Item interface {
-	CreateListItem(ctx context.Context, listId int, item *entity.Item) (int, error)
-	GetAllListItems(ctx context.Context, listId int) ([]entity.Item, error)
-	IsUserOwnerOfItem(ctx context.Context, userId, itemId int) error
+	CreateListItem(ctx context.Context, userId, listId int, item *entity.Item) (int, error)
+	GetListItemsPage(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error)
+	GetUserItemRole(ctx context.Context, userId, itemId int) (string, error)
+	GetUserItemsAccess(ctx context.Context, userId int, itemIds []int) ([]entity.ItemAccess, error)
 	GetOneById(ctx context.Context, itemId int) (entity.Item, error)
 	GetManyByIds(ctx context.Context, itemIds []int) ([]entity.Item, error)
-	UpdateOneById(ctx context.Context, listId *int, itemId int, input entity.UpdateItemInput) error
-	DeleteOneById(ctx context.Context, listId *int, itemId int) error
+	UpdateOneById(ctx context.Context, userId int, listId *int, itemId int, input entity.UpdateItemInput, series *entity.ItemSeriesChange, version *int) error
+	RevertOneById(ctx context.Context, userId, listId, itemId int, state entity.ItemState) error
+	UpdateManyByIds(ctx context.Context, userId int, itemIds []int, input entity.UpdateItemInput) ([]int, error)
+	DeleteOneById(ctx context.Context, itemId int, version *int) ([]int, error)
+	DeleteManyByIds(ctx context.Context, itemIds []int) ([]int, error)
+	ClaimDueReminders(ctx context.Context, limit int) ([]entity.ItemReminderDueEvent, error)
+	GetSeries(ctx context.Context, seriesId int) (entity.ItemSeries, error)
+	CreateNextOccurrence(ctx context.Context, userId, previousItemId int, item *entity.Item) (int, int, error)
+	GetSubtasks(ctx context.Context, parentId int) ([]entity.Item, error)
+	SetParent(ctx context.Context, userId, listId, itemId int, parentId *int) error
+	CompleteSubtasks(ctx context.Context, userId, listId, itemId int) ([]int, error)
+	MoveItem(ctx context.Context, listId, itemId int, input entity.MoveInput) error
+	MoveToList(ctx context.Context, userId, listId int, itemIds []int) ([]int, error)
+	CopyToList(ctx context.Context, userId, listId int, itemIds []int) ([]entity.ItemCopy, error)
+	GetTrashedListItems(ctx context.Context, listId int) ([]entity.TrashedItem, error)
+	RestoreItem(ctx context.Context, userId, listId, itemId int) ([]int, error)
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]int, error)
+}
+
+type Tag interface {
+	Create(ctx context.Context, tag *entity.Tag) (int, error)
+	GetAllByUserId(ctx context.Context, userId int) ([]entity.Tag, error)
+	GetOneById(ctx context.Context, userId, tagId int) (entity.Tag, error)
+	GetAllByItemId(ctx context.Context, userId, itemId int) ([]entity.Tag, error)
+	GetIdsByItemId(ctx context.Context, itemId int) ([]int, error)
+	GetIdsByNames(ctx context.Context, userId int, names []string) ([]int, error)
+	UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error
+	DeleteOneById(ctx context.Context, userId, tagId int) error
+	AttachToItem(ctx context.Context, userId, listId, tagId, itemId int) error
+	DetachFromItem(ctx context.Context, userId, listId, tagId, itemId int) error
+}
+
+type Invitation interface {
+	Create(ctx context.Context, invitation *entity.Invitation) (int, error)
+	GetOneById(ctx context.Context, invitationId int) (entity.Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error)
+	GetPendingByListId(ctx context.Context, listId int) ([]entity.Invitation, error)
+	GetPendingByInviteeId(ctx context.Context, userId int) ([]entity.Invitation, error)
+	Accept(ctx context.Context, invitationId, userId int) error
+	UpdateStatus(ctx context.Context, invitationId int, status string) error
+}
+
+type Revision interface {
+	GetItemRevisions(ctx context.Context, itemId int) ([]entity.ItemRevision, error)
+	GetItemRevision(ctx context.Context, itemId, revisionId int) (entity.ItemRevision, error)
+	GetListRevisions(ctx context.Context, listId int) ([]entity.ListRevision, error)
+	GetListRevision(ctx context.Context, listId, revisionId int) (entity.ListRevision, error)
+}
+
+type Comment interface {
+	Create(ctx context.Context, comment *entity.Comment) (int, error)
+	GetOneById(ctx context.Context, itemId, commentId int) (entity.Comment, error)
+	GetPage(ctx context.Context, itemId int, query entity.CommentQuery) (entity.CommentPage, error)
+	UpdateBody(ctx context.Context, itemId, commentId int, body string) error
+	DeleteOneById(ctx context.Context, itemId, commentId int) error
+}
+
+type Attachment interface {
+	Create(ctx context.Context, attachment *entity.Attachment) (int, error)
+	GetAllByItemId(ctx context.Context, itemId int) ([]entity.Attachment, error)
+	GetOneById(ctx context.Context, itemId, attachmentId int) (entity.Attachment, error)
+	Detach(ctx context.Context, itemId, attachmentId int) (string, error)
+	GetDetached(ctx context.Context, limit int) ([]entity.Attachment, error)
+	DeleteDetached(ctx context.Context, attachmentId int) error
+}
+
+type Idempotency interface {
+	Reserve(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, userId int, key string) (entity.IdempotentRequest, bool, error)
+	Save(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) error
+	Release(ctx context.Context, userId int, key string) error
+}
+
+type Outbox interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error)
+	MarkSent(ctx context.Context, eventIds []int) error
+	MarkFailed(ctx context.Context, eventId int, lastError string, delay time.Duration) error
+	Release(ctx context.Context, eventIds []int, delay time.Duration) error
+	GetBacklog(ctx context.Context) (entity.OutboxBacklog, error)
+	PurgeSent(ctx context.Context, sentBefore time.Time) (int, error)
 }
 
 type Repository struct {
 	Auth
+	Token
+	PersonalAccessToken
+	App
 	User
 	List
 	Item
+	Invitation
+	Tag
+	Revision
+	Comment
+	Attachment
+	Idempotency
+	Outbox
 }
 
 // NewRepository creates a new instance of Repository with initialized repositories
@@ -52,9 +189,19 @@ func NewRepository(
 	logger logger.Interface,
 ) *Repository {
 	return &Repository{
-		Auth: NewAuthRepo(db),
-		User: NewUserRepo(db),
-		List: NewListRepo(db, cache, logger),
-		Item: NewItemRepo(db, cache, logger),
+		Auth:                NewAuthRepo(db),
+		Token:               NewTokenRepo(db, cache, logger),
+		PersonalAccessToken: NewPersonalAccessTokenRepo(db),
+		App:                 NewAppRepo(db, cache, logger),
+		User:                NewUserRepo(db),
+		List:                NewListRepo(db, cache, logger),
+		Item:                NewItemRepo(db, cache, logger),
+		Invitation:          NewInvitationRepo(db, cache, logger),
+		Tag:                 NewTagRepo(db, cache, logger),
+		Revision:            NewRevisionRepo(db),
+		Comment:             NewCommentRepo(db),
+		Attachment:          NewAttachmentRepo(db),
+		Idempotency:         NewIdempotencyRepo(cache),
+		Outbox:              NewOutboxRepo(db),
 	}
 }
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RevisionRepo handles reading the revisions recorded when items and lists are updated,
+// revisions are written by ItemRepo and ListRepo in the transaction of the update
+type RevisionRepo struct {
+	db *database.Database
+}
+
+// NewRevisionRepo creates a new instance of RevisionRepo
+func NewRevisionRepo(db *database.Database) *RevisionRepo {
+	return &RevisionRepo{db}
+}
+
+// GetItemRevisions retrieves the revisions of an item, the most recent first
+func (r *RevisionRepo) GetItemRevisions(ctx context.Context, itemId int) ([]entity.ItemRevision, error) {
+	revisions := []entity.ItemRevision{}
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, user_id, before, after, created_at
+		FROM %s
+		WHERE item_id = $1
+		ORDER BY id DESC`, ItemRevisionsTable)
+
+	rows, err := r.db.Querier.Query(query, itemId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision entity.ItemRevision
+		if err := scanItemRevision(rows, &revision); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetItemRevision retrieves a revision of an item, ErrRevisionNotFound is returned when it belongs to another item
+func (r *RevisionRepo) GetItemRevision(ctx context.Context, itemId, revisionId int) (entity.ItemRevision, error) {
+	var revision entity.ItemRevision
+
+	query := fmt.Sprintf(`
+		SELECT id, item_id, user_id, before, after, created_at
+		FROM %s
+		WHERE id = $1 AND item_id = $2`, ItemRevisionsTable)
+
+	if err := scanItemRevision(r.db.Querier.QueryRow(query, revisionId, itemId), &revision); err != nil {
+		if err == sql.ErrNoRows {
+			return revision, utils.ErrRevisionNotFound
+		}
+
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+// GetListRevisions retrieves the revisions of a list, the most recent first
+func (r *RevisionRepo) GetListRevisions(ctx context.Context, listId int) ([]entity.ListRevision, error) {
+	revisions := []entity.ListRevision{}
+
+	query := fmt.Sprintf(`
+		SELECT id, list_id, user_id, before, after, created_at
+		FROM %s
+		WHERE list_id = $1
+		ORDER BY id DESC`, ListRevisionsTable)
+
+	rows, err := r.db.Querier.Query(query, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision entity.ListRevision
+		if err := scanListRevision(rows, &revision); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetListRevision retrieves a revision of a list, ErrRevisionNotFound is returned when it belongs to another list
+func (r *RevisionRepo) GetListRevision(ctx context.Context, listId, revisionId int) (entity.ListRevision, error) {
+	var revision entity.ListRevision
+
+	query := fmt.Sprintf(`
+		SELECT id, list_id, user_id, before, after, created_at
+		FROM %s
+		WHERE id = $1 AND list_id = $2`, ListRevisionsTable)
+
+	if err := scanListRevision(r.db.Querier.QueryRow(query, revisionId, listId), &revision); err != nil {
+		if err == sql.ErrNoRows {
+			return revision, utils.ErrRevisionNotFound
+		}
+
+		return revision, err
+	}
+
+	return revision, nil
+}
+
+// insertRevision records the states of an item or a list around an update made by the user,
+// column names the reference to the item or the list in the revisions table
+func insertRevision(tx *sqlx.Tx, table, column string, id int, userId *int, before, after interface{}) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, user_id, before, after) VALUES ($1, $2, $3, $4)", table, column)
+
+	_, err = tx.Exec(query, id, userId, beforeJSON, afterJSON)
+	return err
+}
+
+// insertItemRevisions records a revision made by the user for every item whose state in after differs from its state
+// in before, in the order of the item Ids. The Ids of the changed items are returned
+func insertItemRevisions(tx *sqlx.Tx, userId int, before, after map[int]entity.ItemState) ([]int, error) {
+	itemIds := make([]int, 0, len(after))
+	for itemId := range after {
+		itemIds = append(itemIds, itemId)
+	}
+	sort.Ints(itemIds)
+
+	changedIds := []int{}
+	for _, itemId := range itemIds {
+		state, ok := before[itemId]
+		if !ok || len(state.Diff(after[itemId])) == 0 {
+			continue
+		}
+
+		if err := insertRevision(tx, ItemRevisionsTable, "item_id", itemId, &userId, state, after[itemId]); err != nil {
+			return nil, err
+		}
+		changedIds = append(changedIds, itemId)
+	}
+
+	return changedIds, nil
+}
+
+// scanItemRevision scans a row into an item revision and computes its changes
+func scanItemRevision(row rowScanner, revision *entity.ItemRevision) error {
+	var before, after []byte
+
+	err := row.Scan(&revision.Id, &revision.ItemId, &revision.UserId, &before, &after, &revision.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(before, &revision.Before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(after, &revision.After); err != nil {
+		return err
+	}
+
+	revision.Changes = revision.Before.Diff(revision.After)
+
+	return nil
+}
+
+// scanListRevision scans a row into a list revision and computes its changes
+func scanListRevision(row rowScanner, revision *entity.ListRevision) error {
+	var before, after []byte
+
+	err := row.Scan(&revision.Id, &revision.ListId, &revision.UserId, &before, &after, &revision.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(before, &revision.Before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(after, &revision.After); err != nil {
+		return err
+	}
+
+	revision.Changes = revision.Before.Diff(revision.After)
+
+	return nil
+}
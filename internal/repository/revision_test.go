@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	queryGetItemRevisions = fmt.Sprintf("SELECT id, item_id, user_id, before, after, created_at FROM %s WHERE item_id = \\$1 ORDER BY id DESC", repository.ItemRevisionsTable)
+	queryGetItemRevision  = fmt.Sprintf("SELECT id, item_id, user_id, before, after, created_at FROM %s WHERE id = \\$1 AND item_id = \\$2", repository.ItemRevisionsTable)
+	queryGetListRevisions = fmt.Sprintf("SELECT id, list_id, user_id, before, after, created_at FROM %s WHERE list_id = \\$1 ORDER BY id DESC", repository.ListRevisionsTable)
+	queryGetListRevision  = fmt.Sprintf("SELECT id, list_id, user_id, before, after, created_at FROM %s WHERE id = \\$1 AND list_id = \\$2", repository.ListRevisionsTable)
+)
+
+// setupRevisionRepoTest initializes the database and repository for RevisionRepo tests
+func setupRevisionRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.RevisionRepo) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	return sqlxDB, mock, repository.NewRevisionRepo(database.New(sqlxDB))
+}
+
+// TestGetItemRevisions tests retrieving the revisions of an item with their changes
+func TestGetItemRevisions(t *testing.T) {
+	sqlxDB, mock, revisionRepo := setupRevisionRepoTest(t)
+	defer sqlxDB.Close()
+
+	createdAt := time.Date(2024, 11, 20, 9, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2024, 11, 21, 18, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(queryGetItemRevisions).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "user_id", "before", "after", "created_at"}).
+			AddRow(4, 1, 2,
+				[]byte(`{"title":"Milk","description":"","done":false,"due_at":null,"remind_at":null}`),
+				[]byte(`{"title":"Oat milk","description":"","done":false,"due_at":"2024-11-21T18:00:00Z","remind_at":null}`),
+				createdAt).
+			AddRow(3, 1, nil,
+				[]byte(`{"title":"Milk","description":"","done":true,"due_at":null,"remind_at":null}`),
+				[]byte(`{"title":"Milk","description":"","done":false,"due_at":null,"remind_at":null}`),
+				createdAt))
+
+	revisions, err := revisionRepo.GetItemRevisions(context.Background(), 1)
+
+	userId := 2
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, &userId, revisions[0].UserId)
+	assert.Equal(t, []entity.FieldChange{
+		{Field: "title", Before: "Milk", After: "Oat milk"},
+		{Field: "due_at", Before: (*time.Time)(nil), After: revisions[0].After.DueAt},
+	}, revisions[0].Changes)
+	assert.True(t, dueAt.Equal(*revisions[0].After.DueAt))
+	assert.Nil(t, revisions[1].UserId)
+	assert.Equal(t, []entity.FieldChange{{Field: "done", Before: true, After: false}}, revisions[1].Changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetItemRevision tests retrieving a single revision of an item
+func TestGetItemRevision(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemRevision).WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "user_id", "before", "after", "created_at"}).
+						AddRow(4, 1, 2, []byte(`{"title":"Milk"}`), []byte(`{"title":"Oat milk"}`), time.Now()))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "RevisionOfAnotherItem",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemRevision).WithArgs(4, 1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: utils.ErrRevisionNotFound,
+		},
+		{
+			name: "DatabaseError",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetItemRevision).WithArgs(4, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, revisionRepo := setupRevisionRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			revision, err := revisionRepo.GetItemRevision(context.Background(), 1, 4)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, "Oat milk", revision.After.Title)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetListRevisions tests retrieving the revisions of a list with their changes
+func TestGetListRevisions(t *testing.T) {
+	sqlxDB, mock, revisionRepo := setupRevisionRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetListRevisions).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "user_id", "before", "after", "created_at"}).
+			AddRow(2, 1, 2,
+				[]byte(`{"title":"Groceries","description":""}`),
+				[]byte(`{"title":"Groceries","description":"Weekly"}`),
+				time.Now()))
+
+	revisions, err := revisionRepo.GetListRevisions(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, []entity.FieldChange{{Field: "description", Before: "", After: "Weekly"}}, revisions[0].Changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestGetListRevision tests that a revision of another list is not found
+func TestGetListRevision(t *testing.T) {
+	sqlxDB, mock, revisionRepo := setupRevisionRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetListRevision).WithArgs(2, 1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := revisionRepo.GetListRevision(context.Background(), 1, 2)
+
+	assert.Equal(t, utils.ErrRevisionNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -7,4 +7,19 @@ const (
 	ItemsTable      = "items"
 	UsersListsTable = "users_lists"
 	ListsItemsTable = "lists_items"
+
+	RefreshTokensTable        = "refresh_tokens"
+	PersonalAccessTokensTable = "personal_access_tokens"
+	AppsTable                 = "apps"
+	ListInvitationsTable      = "list_invitations"
+	ItemSeriesTable           = "item_series"
+	TagsTable                 = "tags"
+	ItemTagsTable             = "item_tags"
+	ItemRevisionsTable        = "item_revisions"
+	ListRevisionsTable        = "list_revisions"
+	CommentsTable             = "comments"
+	AttachmentsTable          = "attachments"
+	ListTemplatesTable        = "list_templates"
+	ListTemplateItemsTable    = "list_template_items"
+	OutboxEventsTable         = "outbox_events"
 )
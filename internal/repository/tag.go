@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres error code of unique constraint violations
+const uniqueViolation = "23505"
+
+// TagRepo handles persistence of tags and their attachment to items
+type TagRepo struct {
+	db     *database.Database
+	cache  *cache.Cache
+	logger logger.Interface
+}
+
+// NewTagRepo creates a new instance of TagRepo
+func NewTagRepo(db *database.Database, cache *cache.Cache, logger logger.Interface) *TagRepo {
+	return &TagRepo{db, cache, logger}
+}
+
+// Create stores a new tag and fills in its Id and creation time, ErrTagExists is returned
+// when the user already has a tag with the same name
+func (r *TagRepo) Create(ctx context.Context, tag *entity.Tag) (int, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, name, color)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING id, created_at`, TagsTable)
+
+	err := r.db.Querier.QueryRow(query, tag.UserId, tag.Name, tag.Color).Scan(&tag.Id, &tag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, utils.ErrTagExists
+		}
+
+		return 0, err
+	}
+
+	return tag.Id, nil
+}
+
+// GetAllByUserId retrieves the tags of a user ordered by name
+func (r *TagRepo) GetAllByUserId(ctx context.Context, userId int) ([]entity.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT id, user_id, name, color, created_at
+		FROM %s
+		WHERE user_id = $1
+		ORDER BY name`, TagsTable)
+
+	return r.getMany(query, userId)
+}
+
+// GetOneById retrieves a tag of a user by its Id
+func (r *TagRepo) GetOneById(ctx context.Context, userId, tagId int) (entity.Tag, error) {
+	var tag entity.Tag
+
+	query := fmt.Sprintf("SELECT id, user_id, name, color, created_at FROM %s WHERE id = $1 AND user_id = $2", TagsTable)
+
+	err := scanTag(r.db.Querier.QueryRow(query, tagId, userId), &tag)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tag, utils.ErrTagNotFound
+		}
+
+		return tag, err
+	}
+
+	return tag, nil
+}
+
+// GetAllByItemId retrieves the tags of a user attached to an item ordered by name
+func (r *TagRepo) GetAllByItemId(ctx context.Context, userId, itemId int) ([]entity.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.color, t.created_at
+		FROM %s t
+		JOIN %s it ON it.tag_id = t.id
+		WHERE t.user_id = $1 AND it.item_id = $2
+		ORDER BY t.name`, TagsTable, ItemTagsTable)
+
+	return r.getMany(query, userId, itemId)
+}
+
+// GetIdsByItemId retrieves the Ids of the tags of every user attached to an item
+func (r *TagRepo) GetIdsByItemId(ctx context.Context, itemId int) ([]int, error) {
+	query := fmt.Sprintf("SELECT tag_id FROM %s WHERE item_id = $1 ORDER BY tag_id", ItemTagsTable)
+
+	return queryIds(r.db.Querier, query, itemId)
+}
+
+// GetIdsByNames retrieves the Ids of the tags of a user with the given names, unknown names are skipped
+func (r *TagRepo) GetIdsByNames(ctx context.Context, userId int, names []string) ([]int, error) {
+	tagIds := []int{}
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE user_id = $1 AND name = ANY($2)", TagsTable)
+
+	rows, err := r.db.Querier.Query(query, userId, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagId int
+		if err := rows.Scan(&tagId); err != nil {
+			return nil, err
+		}
+		tagIds = append(tagIds, tagId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tagIds, nil
+}
+
+// UpdateOneById updates the name or color of a tag of a user
+func (r *TagRepo) UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error {
+	query := fmt.Sprintf("UPDATE %s SET ", TagsTable)
+	args := []interface{}{}
+
+	setClauses := []string{}
+	argIndex := 1
+
+	if input.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *input.Name)
+		argIndex++
+	}
+	if input.Color != nil {
+		setClauses = append(setClauses, fmt.Sprintf("color = $%d", argIndex))
+		args = append(args, *input.Color)
+		argIndex++
+	}
+
+	if len(setClauses) == 0 {
+		return utils.ErrTagEmptyRequest
+	}
+
+	query += strings.Join(setClauses, ", ")
+
+	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d", argIndex, argIndex+1)
+	args = append(args, tagId, userId)
+
+	result, err := r.db.Executer.Exec(query, args...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return utils.ErrTagExists
+		}
+
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// DeleteOneById deletes a tag of a user, detaching it from every item
+func (r *TagRepo) DeleteOneById(ctx context.Context, userId, tagId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2", TagsTable)
+
+	result, err := r.db.Executer.Exec(query, tagId, userId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// AttachToItem attaches a tag to an item of the list, bumps the version of the item and records an updated event made
+// by the user, attaching a tag twice has no effect
+func (r *TagRepo) AttachToItem(ctx context.Context, userId, listId, tagId, itemId int) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (item_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (item_id, tag_id) DO NOTHING`, ItemTagsTable)
+
+	result, err := tx.Exec(query, itemId, tagId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return nil
+	}
+
+	return r.tagsChanged(ctx, tx, userId, listId, itemId)
+}
+
+// DetachFromItem detaches a tag of a user from an item of the list, bumps the version of the item and records
+// an updated event made by the user
+func (r *TagRepo) DetachFromItem(ctx context.Context, userId, listId, tagId, itemId int) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %s it
+		USING %s t
+		WHERE t.id = it.tag_id AND t.user_id = $1 AND it.tag_id = $2 AND it.item_id = $3`, ItemTagsTable, TagsTable)
+
+	result, err := tx.Exec(query, userId, tagId, itemId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return utils.ErrTagNotFound
+	}
+
+	return r.tagsChanged(ctx, tx, userId, listId, itemId)
+}
+
+// tagsChanged completes the transaction of a change of the tags of an item of the list, the version of the item
+// is bumped and an updated event made by the user is recorded before the commit, the item is dropped from the cache after it
+func (r *TagRepo) tagsChanged(ctx context.Context, tx *sqlx.Tx, userId, listId, itemId int) error {
+	if err := bumpVersions(tx, ItemsTable, []int{itemId}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := insertItemUpdatedEvent(tx, userId, listId, itemId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateItemCache(ctx, r.cache, r.logger, listId, itemId)
+
+	return nil
+}
+
+// getMany runs a query returning a list of tags
+func (r *TagRepo) getMany(query string, args ...interface{}) ([]entity.Tag, error) {
+	tags := []entity.Tag{}
+
+	rows, err := r.db.Querier.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag entity.Tag
+		if err := scanTag(rows, &tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// scanTag scans a row holding the id, user_id, name, color and created_at of a tag
+func scanTag(row rowScanner, tag *entity.Tag) error {
+	return row.Scan(&tag.Id, &tag.UserId, &tag.Name, &tag.Color, &tag.CreatedAt)
+}
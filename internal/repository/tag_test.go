@@ -0,0 +1,304 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	queryCreateTag        = fmt.Sprintf("INSERT INTO %s \\(user_id, name, color\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT \\(user_id, name\\) DO NOTHING RETURNING id, created_at", repository.TagsTable)
+	queryGetTagIdsByItem  = fmt.Sprintf("SELECT tag_id FROM %s WHERE item_id = \\$1 ORDER BY tag_id", repository.ItemTagsTable)
+	queryGetTagIdsByNames = fmt.Sprintf("SELECT id FROM %s WHERE user_id = \\$1 AND name = ANY\\(\\$2\\)", repository.TagsTable)
+	queryUpdateTagName    = fmt.Sprintf("UPDATE %s SET name = \\$1 WHERE id = \\$2 AND user_id = \\$3", repository.TagsTable)
+	queryAttachTag        = fmt.Sprintf("INSERT INTO %s \\(item_id, tag_id\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(item_id, tag_id\\) DO NOTHING", repository.ItemTagsTable)
+	queryDetachTag        = fmt.Sprintf("DELETE FROM %s it USING %s t WHERE t.id = it.tag_id AND t.user_id = \\$1 AND it.tag_id = \\$2 AND it.item_id = \\$3", repository.ItemTagsTable, repository.TagsTable)
+)
+
+// setupTagRepoTest initializes the database and repository for TagRepo tests
+func setupTagRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.TagRepo, *MockCache) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	mockCache := new(MockCache)
+
+	return sqlxDB, mock, repository.NewTagRepo(database.New(sqlxDB), cache.New(mockCache, mockCache), &logger.NoOpLogger{}), mockCache
+}
+
+// TestCreateTag tests storing a tag
+func TestCreateTag(t *testing.T) {
+	createdAt := time.Now()
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedId  int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreateTag).
+					WithArgs(1, "work", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+			},
+			expectedId:  3,
+			expectedErr: nil,
+		},
+		{
+			name: "TagExists",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreateTag).
+					WithArgs(1, "work", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+			},
+			expectedId:  0,
+			expectedErr: utils.ErrTagExists,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, tagRepo, _ := setupTagRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			id, err := tagRepo.Create(context.Background(), &entity.Tag{UserId: 1, Name: "work"})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedId, id)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetTagIdsByNames tests resolving tag names of a user to their Ids
+func TestGetTagIdsByNames(t *testing.T) {
+	sqlxDB, mock, tagRepo, _ := setupTagRepoTest(t)
+	defer sqlxDB.Close()
+
+	names := []string{"work", "urgent"}
+	mock.ExpectQuery(queryGetTagIdsByNames).
+		WithArgs(1, pq.Array(names)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+
+	tagIds, err := tagRepo.GetIdsByNames(context.Background(), 1, names)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, tagIds)
+
+	assertExpectations(t, mock)
+}
+
+// TestGetTagIdsByItemId tests retrieving the Ids of the tags of every user on an item
+func TestGetTagIdsByItemId(t *testing.T) {
+	sqlxDB, mock, tagRepo, _ := setupTagRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectQuery(queryGetTagIdsByItem).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(3).AddRow(8))
+
+	tagIds, err := tagRepo.GetIdsByItemId(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 8}, tagIds)
+
+	assertExpectations(t, mock)
+}
+
+// TestUpdateTag tests renaming a tag
+func TestUpdateTag(t *testing.T) {
+	name := "home"
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateTagName).WithArgs("home", 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateTagName).WithArgs("home", 3, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrTagNotFound,
+		},
+		{
+			name: "NameTaken",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(queryUpdateTagName).WithArgs("home", 3, 1).WillReturnError(&pq.Error{Code: "23505"})
+			},
+			expectedErr: utils.ErrTagExists,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, tagRepo, _ := setupTagRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			err := tagRepo.UpdateOneById(context.Background(), 1, 3, entity.UpdateTagInput{Name: &name})
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestAttachTagToItem tests attaching a tag to an item, the version of the item changes only when the tag is attached
+func TestAttachTagToItem(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mockQuery func(sqlmock.Sqlmock)
+		mockCache func(*MockCache)
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryAttachTag).WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(mock, repository.ItemsTable, 5)
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:5").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:2").Return(nil)
+			},
+		},
+		{
+			name: "AlreadyAttached",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryAttachTag).WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache: func(mockCache *MockCache) {},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, tagRepo, mockCache := setupTagRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			err := tagRepo.AttachToItem(context.Background(), 1, 2, 3, 5)
+
+			assert.NoError(t, err)
+
+			assertExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDetachTagFromItem tests detaching a tag of a user from an item
+func TestDetachTagFromItem(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		mockCache   func(*MockCache)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryDetachTag).WithArgs(1, 3, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectBumpVersions(mock, repository.ItemsTable, 5)
+				expectOutboxEvents(mock, messagebroker.ItemUpdatedTopic, 1)
+				mock.ExpectCommit()
+			},
+			mockCache: func(mockCache *MockCache) {
+				mockCache.On("Delete", mock.Anything, "item_by_id:5").Return(nil)
+				mockCache.On("Delete", mock.Anything, "list_items:2").Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "NotAttached",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryDetachTag).WithArgs(1, 3, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: utils.ErrTagNotFound,
+		},
+		{
+			name: "SQLConnectionFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryDetachTag).WithArgs(1, 3, 5).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			mockCache:   func(mockCache *MockCache) {},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, sqlMock, tagRepo, mockCache := setupTagRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(sqlMock)
+			testCase.mockCache(mockCache)
+
+			err := tagRepo.DetachFromItem(context.Background(), 1, 2, 3, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, sqlMock)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// TokenRepo handles persistence of refresh tokens and the access token denylist
+type TokenRepo struct {
+	db     *database.Database
+	cache  *cache.Cache
+	logger logger.Interface
+}
+
+// NewTokenRepo creates a new instance of TokenRepo
+func NewTokenRepo(db *database.Database, cache *cache.Cache, logger logger.Interface) *TokenRepo {
+	return &TokenRepo{db, cache, logger}
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, refreshToken *entity.RefreshToken) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`, RefreshTokensTable)
+
+	return r.db.Querier.QueryRow(query, refreshToken.UserId, refreshToken.FamilyId, refreshToken.TokenHash, refreshToken.ExpiresAt).
+		Scan(&refreshToken.Id)
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by the hash of its value
+func (r *TokenRepo) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error) {
+	var refreshToken entity.RefreshToken
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at
+		FROM %s
+		WHERE token_hash = $1`, RefreshTokensTable)
+
+	err := r.db.Querier.QueryRow(query, tokenHash).Scan(
+		&refreshToken.Id,
+		&refreshToken.UserId,
+		&refreshToken.FamilyId,
+		&refreshToken.TokenHash,
+		&refreshToken.ExpiresAt,
+		&refreshToken.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return refreshToken, utils.ErrInvalidRefreshToken
+		}
+
+		return refreshToken, err
+	}
+
+	return refreshToken, nil
+}
+
+// RotateRefreshToken revokes the used refresh token and stores its replacement in a single transaction.
+// It returns ErrRefreshTokenReused when the old token has already been revoked by a concurrent rotation.
+func (r *TokenRepo) RotateRefreshToken(ctx context.Context, oldTokenId int, newToken *entity.RefreshToken) error {
+	tx, err := r.db.Transaction.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	revokeQuery := fmt.Sprintf("UPDATE %s SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", RefreshTokensTable)
+
+	result, err := tx.Exec(revokeQuery, oldTokenId)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_ = tx.Rollback()
+		return utils.ErrRefreshTokenReused
+	}
+
+	createQuery := fmt.Sprintf(`
+		INSERT INTO %s (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`, RefreshTokensTable)
+
+	err = tx.QueryRow(createQuery, newToken.UserId, newToken.FamilyId, newToken.TokenHash, newToken.ExpiresAt).Scan(&newToken.Id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeRefreshTokenFamily revokes every active refresh token descending from the same sign in
+func (r *TokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyId string) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL", RefreshTokensTable)
+
+	_, err := r.db.Executer.Exec(query, familyId)
+
+	return err
+}
+
+// RevokeAccessToken adds the access token id to the denylist until the token would expire anyway
+func (r *TokenRepo) RevokeAccessToken(ctx context.Context, tokenId string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	revokedTokenCacheKey := fmt.Sprintf(patternRevokedToken, tokenId)
+
+	return r.cache.Master.Set(ctx, revokedTokenCacheKey, true, ttl)
+}
+
+// IsAccessTokenRevoked checks whether the access token id is on the denylist.
+// The master is queried so that a sign out takes effect immediately regardless of replication lag.
+func (r *TokenRepo) IsAccessTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	revokedTokenCacheKey := fmt.Sprintf(patternRevokedToken, tokenId)
+
+	return r.cache.Master.Exists(ctx, revokedTokenCacheKey)
+}
@@ -0,0 +1,272 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/cache"
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	queryCreateRefreshToken    = fmt.Sprintf("INSERT INTO %s \\(user_id, family_id, token_hash, expires_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4\\) RETURNING id", repository.RefreshTokensTable)
+	queryGetRefreshTokenByHash = fmt.Sprintf("SELECT id, user_id, family_id, token_hash, expires_at, revoked_at FROM %s WHERE token_hash = \\$1", repository.RefreshTokensTable)
+	queryRevokeRefreshToken    = fmt.Sprintf("UPDATE %s SET revoked_at = NOW\\(\\) WHERE id = \\$1 AND revoked_at IS NULL", repository.RefreshTokensTable)
+	queryRevokeTokenFamily     = fmt.Sprintf("UPDATE %s SET revoked_at = NOW\\(\\) WHERE family_id = \\$1 AND revoked_at IS NULL", repository.RefreshTokensTable)
+)
+
+// setupTokenRepoTest initializes the database and repository for TokenRepo tests
+func setupTokenRepoTest(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, *repository.TokenRepo, *MockCache) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	database := database.New(sqlxDB)
+	mockCache := new(MockCache)
+	caches := cache.New(mockCache, mockCache)
+
+	tokenRepo := repository.NewTokenRepo(database, caches, &logger.NoOpLogger{})
+
+	return sqlxDB, mock, tokenRepo, mockCache
+}
+
+// TestCreateRefreshToken tests storing a refresh token
+func TestCreateRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedId  int
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreateRefreshToken).
+					WithArgs(1, "family", "hash", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+			},
+			expectedId:  10,
+			expectedErr: nil,
+		},
+		{
+			name: "SQLConnectionFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryCreateRefreshToken).
+					WithArgs(1, "family", "hash", expiresAt).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedId:  0,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, tokenRepo, _ := setupTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			refreshToken := &entity.RefreshToken{UserId: 1, FamilyId: "family", TokenHash: "hash", ExpiresAt: expiresAt}
+			err := tokenRepo.CreateRefreshToken(context.Background(), refreshToken)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedId, refreshToken.Id)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestGetRefreshTokenByHash tests retrieving a refresh token by its hash
+func TestGetRefreshTokenByHash(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name          string
+		mockQuery     func(sqlmock.Sqlmock)
+		expectedToken entity.RefreshToken
+		expectedErr   error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetRefreshTokenByHash).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at"}).
+						AddRow(1, 2, "family", "hash", expiresAt, nil))
+			},
+			expectedToken: entity.RefreshToken{Id: 1, UserId: 2, FamilyId: "family", TokenHash: "hash", ExpiresAt: expiresAt},
+			expectedErr:   nil,
+		},
+		{
+			name: "NotFound",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(queryGetRefreshTokenByHash).
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedToken: entity.RefreshToken{},
+			expectedErr:   utils.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, tokenRepo, _ := setupTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			refreshToken, err := tokenRepo.GetRefreshTokenByHash(context.Background(), "hash")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedToken, refreshToken)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestRotateRefreshToken tests revoking a refresh token and storing its replacement
+func TestRotateRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		mockQuery   func(sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRevokeRefreshToken).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryCreateRefreshToken).
+					WithArgs(2, "family", "newHash", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "AlreadyRevoked",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRevokeRefreshToken).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: utils.ErrRefreshTokenReused,
+		},
+		{
+			name: "InsertFailure",
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(queryRevokeRefreshToken).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(queryCreateRefreshToken).
+					WithArgs(2, "family", "newHash", expiresAt).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, mock, tokenRepo, _ := setupTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			testCase.mockQuery(mock)
+
+			newToken := &entity.RefreshToken{UserId: 2, FamilyId: "family", TokenHash: "newHash", ExpiresAt: expiresAt}
+			err := tokenRepo.RotateRefreshToken(context.Background(), 1, newToken)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			assertExpectations(t, mock)
+		})
+	}
+}
+
+// TestRevokeRefreshTokenFamily tests revoking all tokens of a family
+func TestRevokeRefreshTokenFamily(t *testing.T) {
+	sqlxDB, mock, tokenRepo, _ := setupTokenRepoTest(t)
+	defer sqlxDB.Close()
+
+	mock.ExpectExec(queryRevokeTokenFamily).WithArgs("family").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := tokenRepo.RevokeRefreshTokenFamily(context.Background(), "family")
+
+	assert.NoError(t, err)
+	assertExpectations(t, mock)
+}
+
+// TestRevokeAccessToken tests adding an access token to the denylist
+func TestRevokeAccessToken(t *testing.T) {
+	testCases := []struct {
+		name      string
+		ttl       time.Duration
+		expectSet bool
+	}{
+		{
+			name:      "Active token is denylisted",
+			ttl:       time.Minute,
+			expectSet: true,
+		},
+		{
+			name:      "Expired token is skipped",
+			ttl:       -time.Minute,
+			expectSet: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sqlxDB, _, tokenRepo, mockCache := setupTokenRepoTest(t)
+			defer sqlxDB.Close()
+
+			mockCache.On("Set", mock.Anything, "revoked_token:jti", true, testCase.ttl).Return(nil)
+
+			err := tokenRepo.RevokeAccessToken(context.Background(), "jti", testCase.ttl)
+
+			assert.NoError(t, err)
+			if testCase.expectSet {
+				mockCache.AssertCalled(t, "Set", mock.Anything, "revoked_token:jti", true, testCase.ttl)
+			} else {
+				mockCache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
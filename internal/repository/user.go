@@ -38,3 +38,24 @@ func (r *UserRepo) DeleteOneById(ctx context.Context, userId int) error {
 
 	return nil
 }
+
+// UpdateRole assigns a role to the user and returns an error if the user is not found
+func (r *UserRepo) UpdateRole(ctx context.Context, userId int, role string) error {
+	query := fmt.Sprintf("UPDATE %s SET role = $1 WHERE id = $2", UsersTable)
+
+	result, err := r.db.Executer.Exec(query, role, userId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utils.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -88,3 +88,63 @@ func TestUserRepo_DeleteUserById(t *testing.T) {
 		})
 	}
 }
+
+// TestUserRepo_UpdateRole tests the UpdateRole function in UserRepo
+func TestUserRepo_UpdateRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		userId      int
+		role        string
+		mockExec    func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:   "Success case",
+			userId: 1,
+			role:   "support",
+			mockExec: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE users SET role = \\$1 WHERE id = \\$2").
+					WithArgs("support", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "User not found",
+			userId: 1,
+			role:   "admin",
+			mockExec: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE users SET role = \\$1 WHERE id = \\$2").
+					WithArgs("admin", 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: utils.ErrUserNotFound,
+		},
+		{
+			name:   "Database error",
+			userId: 1,
+			role:   "user",
+			mockExec: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE users SET role = \\$1 WHERE id = \\$2").
+					WithArgs("user", 1).
+					WillReturnError(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlxDB, mock, userRepo := setupUserRepoTest(t)
+			defer sqlxDB.Close()
+
+			tc.mockExec(mock)
+
+			err := userRepo.UpdateRole(context.Background(), tc.userId, tc.role)
+
+			assert.Equal(t, tc.expectedErr, err)
+
+			assertUserRepoExpectations(t, mock)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/berikulyBeket/todo-plus/pkg/database"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// lockVersion locks a row that is not in the trash and compares its version with the expected version,
+// sql.ErrNoRows is returned when there is no such row and ErrVersionMismatch when the versions differ
+func lockVersion(tx *sqlx.Tx, table string, id, version int) error {
+	query := fmt.Sprintf("SELECT version FROM %s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", table)
+
+	var current int
+	if err := tx.QueryRow(query, id).Scan(&current); err != nil {
+		return err
+	}
+
+	if current != version {
+		return utils.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// versionConflict tells why a write conditioned on the version of a row matched nothing, ErrVersionMismatch is
+// returned when the row is still there and notFound when it is missing or in the trash
+func versionConflict(querier database.Querier, table string, id int, notFound error) error {
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)", table)
+
+	var exists bool
+	if err := querier.QueryRow(query, id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if exists {
+		return utils.ErrVersionMismatch
+	}
+
+	return notFound
+}
+
+// bumpVersions increments the versions of rows for writes that change a row outside of the columns watched by the
+// version triggers, like its tags, its position or its series, so that the ETag of the row changes as well
+func bumpVersions(tx *sqlx.Tx, table string, ids []int) error {
+	query := fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id = ANY($1)", table)
+
+	_, err := tx.Exec(query, pq.Array(ids))
+
+	return err
+}
@@ -0,0 +1,18 @@
+package repository_test
+
+import (
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// queryBumpVersions matches the increment of the versions of rows whose ETag changes without a change of their columns
+func queryBumpVersions(table string) string {
+	return fmt.Sprintf("UPDATE %s SET version = version \\+ 1 WHERE id = ANY\\(\\$1\\)", table)
+}
+
+// expectBumpVersions expects the versions of the rows of the table to be bumped
+func expectBumpVersions(sqlMock sqlmock.Sqlmock, table string, ids ...int) {
+	sqlMock.ExpectExec(queryBumpVersions(table)).WithArgs(pq.Array(ids)).WillReturnResult(sqlmock.NewResult(0, int64(len(ids))))
+}
@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/metrics"
+)
+
+// attachmentPurgeBatchSize is the number of detached attachments whose blobs are deleted at once
+const attachmentPurgeBatchSize = 100
+
+// outboxPurgeInterval is the interval between deletions of the outbox events sent before the retention period
+const outboxPurgeInterval = time.Hour
+
+// Scheduler runs the periodic background jobs of the application
+type Scheduler struct {
+	usecases           *usecase.UseCase
+	reminderInterval   time.Duration
+	reminderBatchSize  int
+	trashPurgeInterval time.Duration
+	trashRetention     time.Duration
+	outboxInterval     time.Duration
+	outboxBatchSize    int
+	outboxRetention    time.Duration
+	metrics            metrics.Interface
+	logger             logger.Interface
+	cancel             context.CancelFunc
+	wg                 sync.WaitGroup
+}
+
+// New creates a new Scheduler instance
+func New(
+	usecases *usecase.UseCase,
+	reminderInterval time.Duration,
+	reminderBatchSize int,
+	trashPurgeInterval time.Duration,
+	trashRetention time.Duration,
+	outboxInterval time.Duration,
+	outboxBatchSize int,
+	outboxRetention time.Duration,
+	metrics metrics.Interface,
+	logger logger.Interface,
+) *Scheduler {
+	return &Scheduler{
+		usecases:           usecases,
+		reminderInterval:   reminderInterval,
+		reminderBatchSize:  reminderBatchSize,
+		trashPurgeInterval: trashPurgeInterval,
+		trashRetention:     trashRetention,
+		outboxInterval:     outboxInterval,
+		outboxBatchSize:    outboxBatchSize,
+		outboxRetention:    outboxRetention,
+		metrics:            metrics,
+		logger:             logger,
+	}
+}
+
+// Start runs the jobs in the background until Stop is called
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(4)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, s.reminderInterval, s.dispatchReminders)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, s.trashPurgeInterval, s.purgeTrash)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, s.outboxInterval, s.relayOutbox)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, outboxPurgeInterval, s.purgeOutbox)
+	}()
+}
+
+// Stop stops the jobs and waits for the running ones to finish
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run calls job every interval until the context is canceled
+func (s *Scheduler) run(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
+
+// dispatchReminders records an event for each of the reminders that are due, draining full batches before waiting for the next tick
+func (s *Scheduler) dispatchReminders(ctx context.Context) {
+	for ctx.Err() == nil {
+		dispatched, err := s.usecases.Item.DispatchDueReminders(ctx, s.reminderBatchSize)
+		if err != nil {
+			s.logger.Errorf("failed to dispatch due reminders: %v", err)
+			return
+		}
+
+		if dispatched > 0 {
+			s.logger.WithFields(map[string]interface{}{
+				"dispatched": dispatched,
+			}).Info("due reminders dispatched")
+		}
+
+		if dispatched < s.reminderBatchSize {
+			return
+		}
+	}
+}
+
+// purgeTrash permanently deletes the lists and items that have been in the trash for longer than the retention period,
+// then the blobs of the attachments of the purged items
+func (s *Scheduler) purgeTrash(ctx context.Context) {
+	defer s.purgeAttachments(ctx)
+
+	purged, err := s.usecases.Trash.Purge(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		s.logger.Errorf("failed to purge trash: %v", err)
+		return
+	}
+
+	if len(purged.ListIds) > 0 || len(purged.ItemIds) > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"lists": len(purged.ListIds),
+			"items": len(purged.ItemIds),
+		}).Info("trash purged")
+	}
+}
+
+// purgeAttachments deletes the blobs of the attachments that were deleted or whose item was purged,
+// draining full batches before waiting for the next tick
+func (s *Scheduler) purgeAttachments(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := s.usecases.Attachment.PurgeDetached(ctx, attachmentPurgeBatchSize)
+		if err != nil {
+			s.logger.Errorf("failed to purge attachments: %v", err)
+			return
+		}
+
+		if purged > 0 {
+			s.logger.WithFields(map[string]interface{}{
+				"purged": purged,
+			}).Info("attachments purged")
+		}
+
+		if purged < attachmentPurgeBatchSize {
+			return
+		}
+	}
+}
+
+// relayOutbox publishes the pending events of the outbox, draining full batches before waiting for the next tick,
+// and reports the remaining backlog
+func (s *Scheduler) relayOutbox(ctx context.Context) {
+	defer s.reportOutboxBacklog(ctx)
+
+	for ctx.Err() == nil {
+		published, err := s.usecases.Outbox.Relay(ctx, s.outboxBatchSize)
+		s.metrics.AddPublishedOutboxEvents(published)
+		if err != nil {
+			s.metrics.IncrementFailedOutboxRelays()
+			s.logger.Errorf("failed to relay outbox events: %v", err)
+			return
+		}
+
+		if published < s.outboxBatchSize {
+			return
+		}
+	}
+}
+
+// reportOutboxBacklog reports the number of pending outbox events and the age of the oldest of them
+func (s *Scheduler) reportOutboxBacklog(ctx context.Context) {
+	backlog, err := s.usecases.Outbox.GetBacklog(ctx)
+	if err != nil {
+		s.logger.Errorf("failed to get outbox backlog: %v", err)
+		return
+	}
+
+	s.metrics.SetOutboxBacklog(backlog.Pending, backlog.OldestAge.Seconds())
+}
+
+// purgeOutbox permanently deletes the outbox events that were sent longer than the retention period ago
+func (s *Scheduler) purgeOutbox(ctx context.Context) {
+	purged, err := s.usecases.Outbox.PurgeSent(ctx, time.Now().Add(-s.outboxRetention))
+	if err != nil {
+		s.logger.Errorf("failed to purge outbox: %v", err)
+		return
+	}
+
+	if purged > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"purged": purged,
+		}).Info("outbox purged")
+	}
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// appValidationTimeout bounds the registry lookup done for every request
+const appValidationTimeout = 2 * time.Second
+
+// AppUseCase manages the application registry and validates app credentials for middleware.AppAuth
+type AppUseCase struct {
+	repo           repository.App
+	defaultOverlap time.Duration
+	lastUsed       *lastUsedThrottle[string]
+	logger         logger.Interface
+}
+
+// NewAppUseCase creates a new instance of AppUseCase
+func NewAppUseCase(r repository.App, defaultOverlap time.Duration, l logger.Interface) *AppUseCase {
+	return &AppUseCase{
+		repo:           r,
+		defaultOverlap: defaultOverlap,
+		lastUsed:       newLastUsedThrottle[string](lastUsedInterval),
+		logger:         l,
+	}
+}
+
+// Create registers a new enabled app with a generated app id and key, the raw key is returned only once
+func (uc *AppUseCase) Create(ctx context.Context, input entity.CreateAppInput) (entity.CreatedApp, error) {
+	if err := input.Validate(); err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	appId, err := appauth.NewAppId()
+	if err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	appKey, err := appauth.NewAppKey()
+	if err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	app := entity.App{
+		AppId:       appId,
+		Name:        input.Name,
+		AccessTypes: input.AccessTypes,
+		Enabled:     true,
+		KeyHash:     appauth.HashKey(appKey),
+	}
+
+	if _, err := uc.repo.Create(ctx, &app); err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	return entity.CreatedApp{App: app, AppKey: appKey}, nil
+}
+
+// EnsureApp registers an app with known credentials unless it already exists, used to seed apps from configuration
+func (uc *AppUseCase) EnsureApp(ctx context.Context, appId, appKey, name string, accessTypes []string) error {
+	return uc.repo.CreateIfNotExists(ctx, &entity.App{
+		AppId:       appId,
+		Name:        name,
+		AccessTypes: accessTypes,
+		Enabled:     true,
+		KeyHash:     appauth.HashKey(appKey),
+	})
+}
+
+// GetAll retrieves every registered app
+func (uc *AppUseCase) GetAll(ctx context.Context) ([]entity.App, error) {
+	return uc.repo.GetAll(ctx)
+}
+
+// GetOneByAppId retrieves an app by its app id
+func (uc *AppUseCase) GetOneByAppId(ctx context.Context, appId string) (entity.App, error) {
+	return uc.repo.GetOneByAppId(ctx, appId)
+}
+
+// UpdateOneByAppId updates the name, access types or enabled flag of an app
+func (uc *AppUseCase) UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateOneByAppId(ctx, appId, input)
+}
+
+// RotateKey issues a new key for an app, the previous key keeps working for the overlap window
+func (uc *AppUseCase) RotateKey(ctx context.Context, appId string, input entity.RotateAppKeyInput) (entity.CreatedApp, error) {
+	overlap := uc.defaultOverlap
+	if input.Overlap != "" {
+		parsed, err := time.ParseDuration(input.Overlap)
+		if err != nil || parsed < 0 {
+			return entity.CreatedApp{}, utils.ErrInvalidOverlap
+		}
+		overlap = parsed
+	}
+
+	appKey, err := appauth.NewAppKey()
+	if err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	if err := uc.repo.RotateKey(ctx, appId, appauth.HashKey(appKey), time.Now().Add(overlap)); err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	app, err := uc.repo.GetOneByAppId(ctx, appId)
+	if err != nil {
+		return entity.CreatedApp{}, err
+	}
+
+	return entity.CreatedApp{App: app, AppKey: appKey}, nil
+}
+
+// DeleteOneByAppId removes an app from the registry
+func (uc *AppUseCase) DeleteOneByAppId(ctx context.Context, appId string) error {
+	return uc.repo.DeleteOneByAppId(ctx, appId)
+}
+
+// Validate implements appauth.Interface, the app must exist, be enabled, be allowed the access type
+// and present either its current key or its previous key within the rotation overlap window
+func (uc *AppUseCase) Validate(appId, appKey, accessType string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), appValidationTimeout)
+	defer cancel()
+
+	app, err := uc.repo.GetOneByAppId(ctx, appId)
+	if err != nil {
+		if err != utils.ErrAppNotFound {
+			uc.logger.WithFields(map[string]interface{}{
+				"app_id": appId,
+			}).Errorf("failed to load app: %v", err)
+		}
+		return false
+	}
+
+	if !app.Enabled || !app.HasAccess(accessType) {
+		return false
+	}
+
+	if !appauth.CompareKey(appKey, app.KeyHash) && !uc.isPreviousKey(app, appKey) {
+		return false
+	}
+
+	if uc.lastUsed.allow(appId, time.Now()) {
+		go uc.updateLastUsed(appId)
+	}
+
+	return true
+}
+
+// isPreviousKey checks the key against the previous key while the overlap window is open
+func (uc *AppUseCase) isPreviousKey(app entity.App, appKey string) bool {
+	if app.PreviousKeyHash == nil || app.PreviousKeyExpiresAt == nil {
+		return false
+	}
+
+	return time.Now().Before(*app.PreviousKeyExpiresAt) && appauth.CompareKey(appKey, *app.PreviousKeyHash)
+}
+
+// updateLastUsed records the app usage, failures are only logged. Validate writes it at most once per
+// lastUsedInterval for each app, so that the registry is not written on every request
+func (uc *AppUseCase) updateLastUsed(appId string) {
+	ctx, cancel := context.WithTimeout(context.Background(), appValidationTimeout)
+	defer cancel()
+
+	if err := uc.repo.UpdateLastUsed(ctx, appId); err != nil {
+		uc.logger.WithFields(map[string]interface{}{
+			"app_id": appId,
+		}).Errorf("failed to update app last use: %v", err)
+	}
+}
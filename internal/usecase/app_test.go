@@ -0,0 +1,278 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// setupAppUseCase initializes the AppUseCase with a mocked repository
+func setupAppUseCase(mockRepo *MockAppRepo) *usecase.AppUseCase {
+	return usecase.NewAppUseCase(mockRepo, time.Hour, &logger.NoOpLogger{})
+}
+
+// TestValidateApp tests the Validate function in the AppUseCase
+func TestValidateApp(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Minute)
+	previousKeyHash := appauth.HashKey("previousKey")
+
+	activeApp := entity.App{
+		AppId:       "app",
+		AccessTypes: []string{appauth.PublicAccess},
+		Enabled:     true,
+		KeyHash:     appauth.HashKey("currentKey"),
+	}
+
+	withPreviousKey := func(expiresAt time.Time) entity.App {
+		app := activeApp
+		app.PreviousKeyHash = &previousKeyHash
+		app.PreviousKeyExpiresAt = &expiresAt
+		return app
+	}
+
+	disabledApp := activeApp
+	disabledApp.Enabled = false
+
+	testCases := []struct {
+		name       string
+		appKey     string
+		accessType string
+		mockApp    entity.App
+		mockErr    error
+		expected   bool
+	}{
+		{
+			name:       "Current key",
+			appKey:     "currentKey",
+			accessType: appauth.PublicAccess,
+			mockApp:    activeApp,
+			expected:   true,
+		},
+		{
+			name:       "Previous key within overlap",
+			appKey:     "previousKey",
+			accessType: appauth.PublicAccess,
+			mockApp:    withPreviousKey(future),
+			expected:   true,
+		},
+		{
+			name:       "Previous key after overlap",
+			appKey:     "previousKey",
+			accessType: appauth.PublicAccess,
+			mockApp:    withPreviousKey(past),
+			expected:   false,
+		},
+		{
+			name:       "Wrong key",
+			appKey:     "wrongKey",
+			accessType: appauth.PublicAccess,
+			mockApp:    activeApp,
+			expected:   false,
+		},
+		{
+			name:       "Access type not granted",
+			appKey:     "currentKey",
+			accessType: appauth.PrivateAccess,
+			mockApp:    activeApp,
+			expected:   false,
+		},
+		{
+			name:       "Disabled app",
+			appKey:     "currentKey",
+			accessType: appauth.PublicAccess,
+			mockApp:    disabledApp,
+			expected:   false,
+		},
+		{
+			name:       "Unknown app",
+			appKey:     "currentKey",
+			accessType: appauth.PublicAccess,
+			mockErr:    utils.ErrAppNotFound,
+			expected:   false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAppRepo)
+			appUseCase := setupAppUseCase(mockRepo)
+
+			mockRepo.On("GetOneByAppId", mock.Anything, "app").Return(testCase.mockApp, testCase.mockErr)
+			mockRepo.On("UpdateLastUsed", mock.Anything, "app").Return(nil).Maybe()
+
+			assert.Equal(t, testCase.expected, appUseCase.Validate("app", testCase.appKey, testCase.accessType))
+		})
+	}
+}
+
+// TestValidateAppLastUsed tests that an app validated on every request updates its last use only once per interval
+func TestValidateAppLastUsed(t *testing.T) {
+	mockRepo := new(MockAppRepo)
+	appUseCase := setupAppUseCase(mockRepo)
+
+	app := entity.App{
+		AppId:       "app",
+		AccessTypes: []string{appauth.PublicAccess},
+		Enabled:     true,
+		KeyHash:     appauth.HashKey("currentKey"),
+	}
+
+	updated := make(chan struct{})
+	mockRepo.On("GetOneByAppId", mock.Anything, "app").Return(app, nil)
+	mockRepo.On("UpdateLastUsed", mock.Anything, "app").Return(nil).Once().Run(func(args mock.Arguments) {
+		close(updated)
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, appUseCase.Validate("app", "currentKey", appauth.PublicAccess))
+	}
+
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("last use of the app was not updated")
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCreateApp tests the Create function in the AppUseCase
+func TestCreateApp(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        entity.CreateAppInput
+		mockBehavior func(mockRepo *MockAppRepo)
+		expectedErr  error
+	}{
+		{
+			name:  "Success",
+			input: entity.CreateAppInput{Name: "cli", AccessTypes: []string{appauth.PublicAccess}},
+			mockBehavior: func(mockRepo *MockAppRepo) {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(app *entity.App) bool {
+					return app.Name == "cli" && app.Enabled && app.AppId != "" && app.KeyHash != ""
+				})).Return(1, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Unknown access type",
+			input:        entity.CreateAppInput{Name: "cli", AccessTypes: []string{"internal"}},
+			mockBehavior: func(mockRepo *MockAppRepo) {},
+			expectedErr:  utils.ErrInvalidAccessType,
+		},
+		{
+			name:  "Repository failure",
+			input: entity.CreateAppInput{Name: "cli", AccessTypes: []string{appauth.PublicAccess}},
+			mockBehavior: func(mockRepo *MockAppRepo) {
+				mockRepo.On("Create", mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAppRepo)
+			appUseCase := setupAppUseCase(mockRepo)
+
+			testCase.mockBehavior(mockRepo)
+
+			created, err := appUseCase.Create(context.Background(), testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.True(t, appauth.CompareKey(created.AppKey, created.KeyHash))
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRotateAppKey tests the RotateKey function in the AppUseCase
+func TestRotateAppKey(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        entity.RotateAppKeyInput
+		mockBehavior func(mockRepo *MockAppRepo)
+		expectedErr  error
+	}{
+		{
+			name:  "Default overlap",
+			input: entity.RotateAppKeyInput{},
+			mockBehavior: func(mockRepo *MockAppRepo) {
+				mockRepo.On("RotateKey", mock.Anything, "app", mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+					return time.Until(expiresAt) > 59*time.Minute
+				})).Return(nil)
+				mockRepo.On("GetOneByAppId", mock.Anything, "app").Return(entity.App{AppId: "app"}, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Custom overlap",
+			input: entity.RotateAppKeyInput{Overlap: "0s"},
+			mockBehavior: func(mockRepo *MockAppRepo) {
+				mockRepo.On("RotateKey", mock.Anything, "app", mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+					return time.Until(expiresAt) <= 0
+				})).Return(nil)
+				mockRepo.On("GetOneByAppId", mock.Anything, "app").Return(entity.App{AppId: "app"}, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Invalid overlap",
+			input:        entity.RotateAppKeyInput{Overlap: "tomorrow"},
+			mockBehavior: func(mockRepo *MockAppRepo) {},
+			expectedErr:  utils.ErrInvalidOverlap,
+		},
+		{
+			name:  "Unknown app",
+			input: entity.RotateAppKeyInput{},
+			mockBehavior: func(mockRepo *MockAppRepo) {
+				mockRepo.On("RotateKey", mock.Anything, "app", mock.Anything, mock.Anything).Return(utils.ErrAppNotFound)
+			},
+			expectedErr: utils.ErrAppNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAppRepo)
+			appUseCase := setupAppUseCase(mockRepo)
+
+			testCase.mockBehavior(mockRepo)
+
+			created, err := appUseCase.RotateKey(context.Background(), "app", testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.NotEmpty(t, created.AppKey)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/blobstore"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// sniffLength is the number of leading bytes used to detect the content type of an upload
+const sniffLength = 512
+
+// AttachmentUseCase handles uploading, downloading and deleting the files attached to items,
+// access to the attachments follows the role of the user on the list holding the item
+type AttachmentUseCase struct {
+	repo         repository.Attachment
+	itemRepo     repository.Item
+	store        blobstore.Interface
+	maxSize      int64
+	allowedTypes map[string]bool
+	logger       logger.Interface
+}
+
+// NewAttachmentUseCase creates a new instance of AttachmentUseCase, uploads are limited to maxSize bytes
+// and to the allowed content types
+func NewAttachmentUseCase(
+	r repository.Attachment,
+	ir repository.Item,
+	s blobstore.Interface,
+	maxSize int64,
+	allowedTypes []string,
+	l logger.Interface,
+) *AttachmentUseCase {
+	allowed := make(map[string]bool, len(allowedTypes))
+	for _, contentType := range allowedTypes {
+		allowed[contentType] = true
+	}
+
+	return &AttachmentUseCase{
+		repo:         r,
+		itemRepo:     ir,
+		store:        s,
+		maxSize:      maxSize,
+		allowedTypes: allowed,
+		logger:       l,
+	}
+}
+
+// Upload stores a file and attaches it to an item if the user is an editor of the list holding the item.
+// The content type is detected from the content rather than trusted from the client
+func (uc *AttachmentUseCase) Upload(ctx context.Context, userId, itemId int, input entity.UploadAttachmentInput) (entity.Attachment, error) {
+	if err := input.Validate(); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	if input.Size > uc.maxSize {
+		return entity.Attachment{}, utils.ErrAttachmentTooLarge
+	}
+
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleEditor); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(input.Content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return entity.Attachment{}, err
+	}
+	head = head[:n]
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || !uc.allowedTypes[contentType] {
+		return entity.Attachment{}, utils.ErrAttachmentTypeNotAllowed
+	}
+
+	blobKey, err := newBlobKey(itemId)
+	if err != nil {
+		return entity.Attachment{}, err
+	}
+
+	content := io.MultiReader(bytes.NewReader(head), input.Content)
+	if err := uc.store.Put(ctx, blobKey, content, input.Size, contentType); err != nil {
+		return entity.Attachment{}, err
+	}
+
+	attachment := entity.Attachment{
+		ItemId:      itemId,
+		UserId:      &userId,
+		Name:        input.Name,
+		ContentType: contentType,
+		Size:        input.Size,
+		BlobKey:     blobKey,
+	}
+
+	if _, err := uc.repo.Create(ctx, &attachment); err != nil {
+		uc.deleteBlob(ctx, blobKey)
+		return entity.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// GetAll retrieves the attachments of an item if the list holding the item is shared with the user
+func (uc *AttachmentUseCase) GetAll(ctx context.Context, userId, itemId int) ([]entity.Attachment, error) {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return []entity.Attachment{}, err
+	}
+
+	return uc.repo.GetAllByItemId(ctx, itemId)
+}
+
+// Download retrieves an attachment with its content if the list holding the item is shared with the user,
+// the caller closes the content
+func (uc *AttachmentUseCase) Download(ctx context.Context, userId, itemId, attachmentId int) (entity.Attachment, io.ReadCloser, error) {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return entity.Attachment{}, nil, err
+	}
+
+	attachment, err := uc.repo.GetOneById(ctx, itemId, attachmentId)
+	if err != nil {
+		return entity.Attachment{}, nil, err
+	}
+
+	content, err := uc.store.Get(ctx, attachment.BlobKey)
+	if err != nil {
+		if err == blobstore.ErrNotFound {
+			return entity.Attachment{}, nil, utils.ErrAttachmentNotFound
+		}
+
+		return entity.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteOneById deletes an attachment of an item if the user is an editor of the list holding the item.
+// A blob that cannot be deleted right away is deleted by the next PurgeDetached
+func (uc *AttachmentUseCase) DeleteOneById(ctx context.Context, userId, itemId, attachmentId int) error {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleEditor); err != nil {
+		return err
+	}
+
+	blobKey, err := uc.repo.Detach(ctx, itemId, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.store.Delete(ctx, blobKey); err != nil {
+		uc.logger.WithFields(map[string]interface{}{
+			"attachment_id": attachmentId,
+		}).Errorf("failed to delete attachment blob: %v", err)
+		return nil
+	}
+
+	if err := uc.repo.DeleteDetached(ctx, attachmentId); err != nil {
+		uc.logger.WithFields(map[string]interface{}{
+			"attachment_id": attachmentId,
+		}).Errorf("failed to delete detached attachment: %v", err)
+	}
+
+	return nil
+}
+
+// PurgeDetached deletes the blobs of up to limit attachments that were deleted or whose item was purged,
+// and returns how many were purged. Attachments whose blob cannot be deleted are kept for a later call
+func (uc *AttachmentUseCase) PurgeDetached(ctx context.Context, limit int) (int, error) {
+	attachments, err := uc.repo.GetDetached(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, attachment := range attachments {
+		if err := uc.store.Delete(ctx, attachment.BlobKey); err != nil {
+			uc.logger.WithFields(map[string]interface{}{
+				"attachment_id": attachment.Id,
+			}).Errorf("failed to delete attachment blob: %v", err)
+			continue
+		}
+
+		if err := uc.repo.DeleteDetached(ctx, attachment.Id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// MaxSize returns the largest file size in bytes that can be uploaded
+func (uc *AttachmentUseCase) MaxSize() int64 {
+	return uc.maxSize
+}
+
+// deleteBlob deletes a blob that is not referenced by any attachment, failures are only logged
+func (uc *AttachmentUseCase) deleteBlob(ctx context.Context, blobKey string) {
+	if err := uc.store.Delete(ctx, blobKey); err != nil {
+		uc.logger.WithFields(map[string]interface{}{
+			"blob_key": blobKey,
+		}).Errorf("failed to delete unreferenced blob: %v", err)
+	}
+}
+
+// newBlobKey generates a random blob key grouped under the item
+func newBlobKey(itemId int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("items/%d/%s", itemId, hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,272 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/blobstore"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pngHeader is the signature that identifies PNG content
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// newAttachmentUseCase creates an AttachmentUseCase accepting files of up to 1 KiB of PNG images and PDFs
+func newAttachmentUseCase(repo *MockAttachmentRepo, itemRepo *MockItemRepo, store *MockBlobStore) *usecase.AttachmentUseCase {
+	return usecase.NewAttachmentUseCase(repo, itemRepo, store, 1024, []string{"image/png", "application/pdf"}, &logger.NoOpLogger{})
+}
+
+// TestUploadAttachment tests the Upload function in the AttachmentUseCase
+func TestUploadAttachment(t *testing.T) {
+	png := pngHeader + "image data"
+
+	testCases := []struct {
+		name         string
+		input        entity.UploadAttachmentInput
+		mockBehavior func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore)
+		expectedType string
+		expectedErr  error
+	}{
+		{
+			name:  "Success",
+			input: entity.UploadAttachmentInput{Name: "C:\\shots\\screenshot.png", Size: int64(len(png)), Content: strings.NewReader(png)},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockStore.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "items/5/")
+				}), png, int64(len(png)), "image/png").Return(nil)
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(attachment *entity.Attachment) bool {
+					return attachment.ItemId == 5 && *attachment.UserId == 1 && attachment.Name == "screenshot.png"
+				})).Return(3, nil)
+			},
+			expectedType: "image/png",
+		},
+		{
+			name:         "Too large",
+			input:        entity.UploadAttachmentInput{Name: "big.png", Size: 2048, Content: strings.NewReader(png)},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {},
+			expectedErr:  utils.ErrAttachmentTooLarge,
+		},
+		{
+			name:         "Empty file",
+			input:        entity.UploadAttachmentInput{Name: "empty.png", Size: 0, Content: strings.NewReader("")},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {},
+			expectedErr:  utils.ErrAttachmentEmpty,
+		},
+		{
+			name:  "Type detected from the content",
+			input: entity.UploadAttachmentInput{Name: "fake.png", Size: 11, Content: strings.NewReader("plain text.")},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+			},
+			expectedErr: utils.ErrAttachmentTypeNotAllowed,
+		},
+		{
+			name:  "Viewer cannot upload",
+			input: entity.UploadAttachmentInput{Name: "screenshot.png", Size: int64(len(png)), Content: strings.NewReader(png)},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:  "Blob deleted when the attachment is not stored",
+			input: entity.UploadAttachmentInput{Name: "screenshot.png", Size: int64(len(png)), Content: strings.NewReader(png)},
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockStore.On("Put", mock.Anything, mock.Anything, png, int64(len(png)), "image/png").Return(nil)
+				mockRepo.On("Create", mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+				mockStore.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAttachmentRepo)
+			mockItemRepo := new(MockItemRepo)
+			mockStore := new(MockBlobStore)
+			attachmentUseCase := newAttachmentUseCase(mockRepo, mockItemRepo, mockStore)
+
+			testCase.mockBehavior(mockRepo, mockItemRepo, mockStore)
+
+			attachment, err := attachmentUseCase.Upload(context.Background(), 1, 5, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedType, attachment.ContentType)
+
+			mockRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDownloadAttachment tests the Download function in the AttachmentUseCase
+func TestDownloadAttachment(t *testing.T) {
+	attachment := entity.Attachment{Id: 3, ItemId: 5, Name: "spec.pdf", BlobKey: "items/5/abc"}
+
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore)
+		expectedBody string
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockRepo.On("GetOneById", mock.Anything, 5, 3).Return(attachment, nil)
+				mockStore.On("Get", mock.Anything, "items/5/abc").Return(io.NopCloser(strings.NewReader("%PDF")), nil)
+			},
+			expectedBody: "%PDF",
+		},
+		{
+			name: "Blob missing",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockRepo.On("GetOneById", mock.Anything, 5, 3).Return(attachment, nil)
+				mockStore.On("Get", mock.Anything, "items/5/abc").Return(nil, blobstore.ErrNotFound)
+			},
+			expectedErr: utils.ErrAttachmentNotFound,
+		},
+		{
+			name: "Item not shared with the user",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return("", utils.ErrUserNotOwner)
+			},
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAttachmentRepo)
+			mockItemRepo := new(MockItemRepo)
+			mockStore := new(MockBlobStore)
+			attachmentUseCase := newAttachmentUseCase(mockRepo, mockItemRepo, mockStore)
+
+			testCase.mockBehavior(mockRepo, mockItemRepo, mockStore)
+
+			_, content, err := attachmentUseCase.Download(context.Background(), 1, 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				body, _ := io.ReadAll(content)
+				assert.Equal(t, testCase.expectedBody, string(body))
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDeleteAttachment tests the DeleteOneById function in the AttachmentUseCase
+func TestDeleteAttachment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockRepo.On("Detach", mock.Anything, 5, 3).Return("items/5/abc", nil)
+				mockStore.On("Delete", mock.Anything, "items/5/abc").Return(nil)
+				mockRepo.On("DeleteDetached", mock.Anything, 3).Return(nil)
+			},
+		},
+		{
+			name: "Blob left for the purge",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockRepo.On("Detach", mock.Anything, 5, 3).Return("items/5/abc", nil)
+				mockStore.On("Delete", mock.Anything, "items/5/abc").Return(errors.New("storage unavailable"))
+			},
+		},
+		{
+			name: "Attachment not found",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockRepo.On("Detach", mock.Anything, 5, 3).Return("", utils.ErrAttachmentNotFound)
+			},
+			expectedErr: utils.ErrAttachmentNotFound,
+		},
+		{
+			name: "Viewer cannot delete",
+			mockBehavior: func(mockRepo *MockAttachmentRepo, mockItemRepo *MockItemRepo, mockStore *MockBlobStore) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAttachmentRepo)
+			mockItemRepo := new(MockItemRepo)
+			mockStore := new(MockBlobStore)
+			attachmentUseCase := newAttachmentUseCase(mockRepo, mockItemRepo, mockStore)
+
+			testCase.mockBehavior(mockRepo, mockItemRepo, mockStore)
+
+			err := attachmentUseCase.DeleteOneById(context.Background(), 1, 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPurgeDetachedAttachments tests the PurgeDetached function in the AttachmentUseCase
+func TestPurgeDetachedAttachments(t *testing.T) {
+	mockRepo := new(MockAttachmentRepo)
+	mockStore := new(MockBlobStore)
+	attachmentUseCase := newAttachmentUseCase(mockRepo, new(MockItemRepo), mockStore)
+
+	mockRepo.On("GetDetached", mock.Anything, 10).Return([]entity.Attachment{
+		{Id: 3, BlobKey: "items/5/abc"},
+		{Id: 4, BlobKey: "items/5/def"},
+		{Id: 8, BlobKey: "items/6/ghi"},
+	}, nil)
+	mockStore.On("Delete", mock.Anything, "items/5/abc").Return(nil)
+	mockStore.On("Delete", mock.Anything, "items/5/def").Return(errors.New("storage unavailable"))
+	mockStore.On("Delete", mock.Anything, "items/6/ghi").Return(nil)
+	mockRepo.On("DeleteDetached", mock.Anything, 3).Return(nil)
+	mockRepo.On("DeleteDetached", mock.Anything, 8).Return(nil)
+
+	purged, err := attachmentUseCase.PurgeDetached(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	mockRepo.AssertExpectations(t)
+	mockStore.AssertExpectations(t)
+}
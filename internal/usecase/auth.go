@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/berikulyBeket/todo-plus/pkg/hash"
 	"github.com/berikulyBeket/todo-plus/pkg/token"
@@ -13,36 +16,275 @@ import (
 // AuthUseCase handles the authentication logic
 type AuthUseCase struct {
 	repo       repository.Auth
+	tokenRepo  repository.Token
+	patRepo    repository.PersonalAccessToken
 	hasher     hash.Hasher
 	tokenMaker token.TokenMaker
+	patUsed    *lastUsedThrottle[int]
+	logger     logger.Interface
 }
 
 // NewAuthUseCase creates a new instance of AuthUseCase
-func NewAuthUseCase(r repository.Auth, hasher hash.Hasher, tokenMaker token.TokenMaker) *AuthUseCase {
+func NewAuthUseCase(
+	r repository.Auth,
+	t repository.Token,
+	p repository.PersonalAccessToken,
+	hasher hash.Hasher,
+	tokenMaker token.TokenMaker,
+	l logger.Interface,
+) *AuthUseCase {
 	return &AuthUseCase{
 		repo:       r,
+		tokenRepo:  t,
+		patRepo:    p,
 		hasher:     hasher,
 		tokenMaker: tokenMaker,
+		patUsed:    newLastUsedThrottle[int](lastUsedInterval),
+		logger:     l,
 	}
 }
 
-// CreateUser hashes the user's password and creates a new user in the repository
+// CreateUser hashes the user's password and creates a new user with the default role in the repository
 func (uc *AuthUseCase) CreateUser(ctx context.Context, user entity.User) (int, error) {
-	user.Password = uc.hasher.HashPassword(user.Password)
+	user.Role = entity.RoleUser
+
+	return uc.createUser(ctx, user)
+}
+
+// EnsureAdmin creates an admin user with known credentials unless the username is already taken,
+// used to seed the first admin from configuration
+func (uc *AuthUseCase) EnsureAdmin(ctx context.Context, name, username, password string) error {
+	_, err := uc.repo.GetUserByUsername(ctx, username)
+	if err == nil {
+		return nil
+	}
+	if err != utils.ErrUserNotFound {
+		return err
+	}
+
+	_, err = uc.createUser(ctx, entity.User{
+		Name:     name,
+		Username: username,
+		Password: password,
+		Role:     entity.RoleAdmin,
+	})
+
+	return err
+}
+
+// createUser hashes the user's password and stores the user
+func (uc *AuthUseCase) createUser(ctx context.Context, user entity.User) (int, error) {
+	passwordHash, err := uc.hasher.Hash(user.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	user.Password = passwordHash
 	return uc.repo.CreateUser(ctx, user)
 }
 
-// AuthenticateUser checks the provided credentials by hashing the password and querying the repository
+// AuthenticateUser fetches the user by username, verifies the password against the stored hash
+// and transparently upgrades the hash when it was produced by an outdated algorithm or parameters
 func (uc *AuthUseCase) AuthenticateUser(ctx context.Context, username, password string) (entity.User, error) {
-	return uc.repo.GetUserByCredentials(ctx, username, uc.hasher.HashPassword(password))
+	user, err := uc.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	ok, err := uc.hasher.Verify(password, user.Password)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if !ok {
+		return entity.User{}, utils.ErrUserNotFound
+	}
+
+	if uc.hasher.NeedsRehash(user.Password) {
+		uc.rehashPassword(ctx, user.Id, password)
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+// rehashPassword stores a fresh hash of the password, failures are logged and never block the sign in
+func (uc *AuthUseCase) rehashPassword(ctx context.Context, userId int, password string) {
+	passwordHash, err := uc.hasher.Hash(password)
+	if err != nil {
+		uc.logger.Errorf("failed to rehash password for user %d: %v", userId, err)
+		return
+	}
+
+	if err := uc.repo.UpdatePasswordHash(ctx, userId, passwordHash); err != nil {
+		uc.logger.Errorf("failed to update password hash for user %d: %v", userId, err)
+	}
+}
+
+// GenerateTokens issues a short-lived access token carrying the user's role and starts a new refresh token family for the user
+func (uc *AuthUseCase) GenerateTokens(ctx context.Context, userId int, role string) (entity.TokenPair, error) {
+	familyId, err := token.NewTokenId()
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	return uc.issueTokens(ctx, userId, role, func(refreshToken *entity.RefreshToken) error {
+		refreshToken.FamilyId = familyId
+		return uc.tokenRepo.CreateRefreshToken(ctx, refreshToken)
+	})
+}
+
+// RefreshTokens exchanges a valid refresh token for a new token pair and revokes the used refresh token.
+// The role is read again so role changes take effect on the next refresh.
+// Presenting an already used refresh token revokes its whole family since the token was likely stolen.
+func (uc *AuthUseCase) RefreshTokens(ctx context.Context, refreshToken string) (entity.TokenPair, error) {
+	stored, err := uc.tokenRepo.GetRefreshTokenByHash(ctx, token.HashToken(refreshToken))
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	if stored.RevokedAt != nil {
+		uc.revokeFamily(ctx, stored)
+		return entity.TokenPair{}, utils.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return entity.TokenPair{}, utils.ErrRefreshTokenExpired
+	}
+
+	role, err := uc.repo.GetUserRole(ctx, stored.UserId)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	tokens, err := uc.issueTokens(ctx, stored.UserId, role, func(newToken *entity.RefreshToken) error {
+		newToken.FamilyId = stored.FamilyId
+		return uc.tokenRepo.RotateRefreshToken(ctx, stored.Id, newToken)
+	})
+	if err == utils.ErrRefreshTokenReused {
+		uc.revokeFamily(ctx, stored)
+	}
+
+	return tokens, err
 }
 
-// GenerateToken generates a token for the provided user Id
-func (uc *AuthUseCase) GenerateToken(ctx context.Context, userId int) (string, error) {
-	return uc.tokenMaker.CreateToken(userId)
+// SignOut revokes the access token and, when provided, the refresh token family of the session
+func (uc *AuthUseCase) SignOut(ctx context.Context, accessToken, refreshToken string) error {
+	payload, err := uc.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.tokenRepo.RevokeAccessToken(ctx, payload.TokenId, time.Until(payload.ExpiresAt)); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := uc.tokenRepo.GetRefreshTokenByHash(ctx, token.HashToken(refreshToken))
+	if err != nil {
+		if err == utils.ErrInvalidRefreshToken {
+			return nil
+		}
+
+		return err
+	}
+
+	if stored.UserId != payload.UserId {
+		return nil
+	}
+
+	return uc.tokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyId)
+}
+
+// ParsePersonalAccessToken looks up a personal access token by its hash, rejects revoked and expired tokens
+// and returns the owning user Id together with the granted scopes. The last use of a token is written at most
+// once per lastUsedInterval so that a token presented on every request does not write on every request
+func (uc *AuthUseCase) ParsePersonalAccessToken(ctx context.Context, accessToken string) (int, []string, error) {
+	stored, err := uc.patRepo.GetByHash(ctx, token.HashToken(accessToken))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		return 0, nil, utils.ErrTokenRevoked
+	}
+
+	if stored.ExpiresAt != nil && time.Now().After(*stored.ExpiresAt) {
+		return 0, nil, utils.ErrPersonalAccessTokenExpired
+	}
+
+	if uc.patUsed.allow(stored.Id, time.Now()) {
+		if err := uc.patRepo.UpdateLastUsed(ctx, stored.Id); err != nil {
+			uc.logger.WithFields(map[string]interface{}{
+				"token_id": stored.Id,
+			}).Errorf("failed to update personal access token last use: %v", err)
+		}
+	}
+
+	return stored.UserId, stored.Scopes, nil
+}
+
+// GetJWKS returns the public keys that verify issued access tokens
+func (uc *AuthUseCase) GetJWKS() token.JWKS {
+	return uc.tokenMaker.JWKS()
+}
+
+// ParseToken verifies the provided access token, rejects revoked tokens and returns the associated user Id and role
+func (uc *AuthUseCase) ParseToken(ctx context.Context, accessToken string) (int, string, error) {
+	payload, err := uc.tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return 0, "", err
+	}
+
+	revoked, err := uc.tokenRepo.IsAccessTokenRevoked(ctx, payload.TokenId)
+	if err != nil {
+		return 0, "", err
+	}
+	if revoked {
+		return 0, "", utils.ErrTokenRevoked
+	}
+
+	return payload.UserId, payload.Role, nil
 }
 
-// ParseToken verifies the provided access token and returns the associated user Id
-func (uc *AuthUseCase) ParseToken(ctx context.Context, accessToken string) (int, error) {
-	return uc.tokenMaker.VerifyToken(accessToken)
+// issueTokens creates an access token and a refresh token, persisting the latter with the given store function
+func (uc *AuthUseCase) issueTokens(ctx context.Context, userId int, role string, store func(refreshToken *entity.RefreshToken) error) (entity.TokenPair, error) {
+	accessToken, _, err := uc.tokenMaker.CreateToken(userId, role)
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	refreshToken, expiresAt, err := uc.tokenMaker.CreateRefreshToken()
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	err = store(&entity.RefreshToken{
+		UserId:    userId,
+		TokenHash: token.HashToken(refreshToken),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return entity.TokenPair{}, err
+	}
+
+	return entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// revokeFamily revokes the refresh token family, failures are only logged
+func (uc *AuthUseCase) revokeFamily(ctx context.Context, refreshToken entity.RefreshToken) {
+	uc.logger.WithFields(map[string]interface{}{
+		"user_id":   refreshToken.UserId,
+		"family_id": refreshToken.FamilyId,
+	}).Warn("refresh token reuse detected, revoking token family")
+
+	if err := uc.tokenRepo.RevokeRefreshTokenFamily(ctx, refreshToken.FamilyId); err != nil {
+		uc.logger.Errorf("failed to revoke refresh token family %s: %v", refreshToken.FamilyId, err)
+	}
 }
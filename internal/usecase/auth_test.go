@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,7 +18,7 @@ import (
 
 // Helper function to initialize mocks and usecase
 func setupAuthUseCase(mockRepo *MockAuthRepo, mockHasher *MockHasher) *usecase.AuthUseCase {
-	return usecase.NewAuthUseCase(mockRepo, mockHasher, nil)
+	return usecase.NewAuthUseCase(mockRepo, nil, nil, mockHasher, nil, &logger.NoOpLogger{})
 }
 
 // Helper function to assert error and user equality
@@ -64,9 +68,9 @@ func TestCreateUser(t *testing.T) {
 			mockHasher := new(MockHasher)
 			authUseCase := setupAuthUseCase(mockRepo, mockHasher)
 
-			mockHasher.On("HashPassword", testCase.user.Password).Return(testCase.mockHash)
+			mockHasher.On("Hash", testCase.user.Password).Return(testCase.mockHash, nil)
 			mockRepo.On("CreateUser", mock.Anything, mock.MatchedBy(func(user entity.User) bool {
-				return user.Password == testCase.mockHash
+				return user.Password == testCase.mockHash && user.Role == entity.RoleUser
 			})).Return(testCase.mockResult, testCase.mockError)
 
 			_, err := authUseCase.CreateUser(context.Background(), testCase.user)
@@ -77,7 +81,7 @@ func TestCreateUser(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			mockHasher.AssertCalled(t, "HashPassword", testCase.user.Password)
+			mockHasher.AssertCalled(t, "Hash", testCase.user.Password)
 			mockRepo.AssertCalled(t, "CreateUser", mock.Anything, mock.MatchedBy(func(user entity.User) bool {
 				return user.Password == testCase.mockHash
 			}))
@@ -85,41 +89,132 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
-// TestGetUser tests the GetUser function in the AuthUseCase
-func TestGetUser(t *testing.T) {
+// TestEnsureAdmin tests the EnsureAdmin function in the AuthUseCase
+func TestEnsureAdmin(t *testing.T) {
 	testCases := []struct {
 		name         string
-		username     string
-		password     string
-		mockHash     string
-		mockUser     entity.User
-		mockError    error
-		expectedUser entity.User
+		existingUser entity.User
+		getErr       error
+		expectCreate bool
 		expectedErr  error
 	}{
 		{
-			name:         "Successful user retrieval",
+			name:         "Admin is created when the username is free",
+			getErr:       utils.ErrUserNotFound,
+			expectCreate: true,
+			expectedErr:  nil,
+		},
+		{
+			name:         "Existing user is left untouched",
+			existingUser: entity.User{Id: 1, Username: "admin", Role: entity.RoleUser},
+			expectCreate: false,
+			expectedErr:  nil,
+		},
+		{
+			name:         "Repository failure",
+			getErr:       errors.New("db error"),
+			expectCreate: false,
+			expectedErr:  errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAuthRepo)
+			mockHasher := new(MockHasher)
+			authUseCase := setupAuthUseCase(mockRepo, mockHasher)
+
+			mockRepo.On("GetUserByUsername", mock.Anything, "admin").Return(testCase.existingUser, testCase.getErr)
+			mockHasher.On("Hash", "secret").Return("hashedSecret", nil)
+			mockRepo.On("CreateUser", mock.Anything, entity.User{
+				Name:     "Administrator",
+				Username: "admin",
+				Password: "hashedSecret",
+				Role:     entity.RoleAdmin,
+			}).Return(1, nil)
+
+			err := authUseCase.EnsureAdmin(context.Background(), "Administrator", "admin", "secret")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectCreate {
+				mockRepo.AssertCalled(t, "CreateUser", mock.Anything, mock.Anything)
+			} else {
+				mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestAuthenticateUser tests the AuthenticateUser function in the AuthUseCase
+func TestAuthenticateUser(t *testing.T) {
+	testCases := []struct {
+		name          string
+		username      string
+		password      string
+		mockUser      entity.User
+		mockRepoErr   error
+		mockVerify    bool
+		mockVerifyErr error
+		needsRehash   bool
+		expectedUser  entity.User
+		expectedErr   error
+	}{
+		{
+			name:         "Successful authentication",
 			username:     "testuser",
 			password:     "password",
-			mockHash:     "hashedPassword",
-			mockUser:     entity.User{Username: "testuser", Password: "hashedPassword"},
-			mockError:    nil,
-			expectedUser: entity.User{Username: "testuser", Password: "hashedPassword"},
+			mockUser:     entity.User{Id: 1, Username: "testuser", Password: "$argon2id$hash"},
+			mockVerify:   true,
+			needsRehash:  false,
+			expectedUser: entity.User{Id: 1, Username: "testuser"},
 			expectedErr:  nil,
 		},
+		{
+			name:         "Successful authentication with legacy hash upgrade",
+			username:     "testuser",
+			password:     "password",
+			mockUser:     entity.User{Id: 1, Username: "testuser", Password: "legacysha1hash"},
+			mockVerify:   true,
+			needsRehash:  true,
+			expectedUser: entity.User{Id: 1, Username: "testuser"},
+			expectedErr:  nil,
+		},
+		{
+			name:         "Wrong password",
+			username:     "testuser",
+			password:     "wrong",
+			mockUser:     entity.User{Id: 1, Username: "testuser", Password: "$argon2id$hash"},
+			mockVerify:   false,
+			expectedUser: entity.User{},
+			expectedErr:  utils.ErrUserNotFound,
+		},
+		{
+			name:          "Malformed stored hash",
+			username:      "testuser",
+			password:      "password",
+			mockUser:      entity.User{Id: 1, Username: "testuser", Password: "$argon2id$broken"},
+			mockVerifyErr: errors.New("encoded hash is not in the correct format"),
+			expectedUser:  entity.User{},
+			expectedErr:   errors.New("encoded hash is not in the correct format"),
+		},
 		{
 			name:         "Failed user retrieval",
 			username:     "testuser",
 			password:     "password",
-			mockHash:     "hashedPassword",
 			mockUser:     entity.User{},
-			mockError:    errors.New("repository error"),
+			mockRepoErr:  errors.New("repository error"),
 			expectedUser: entity.User{},
 			expectedErr:  errors.New("repository error"),
 		},
 	}
 
 	for _, testCase := range testCases {
+		testCase := testCase
+
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
@@ -127,64 +222,245 @@ func TestGetUser(t *testing.T) {
 			mockHasher := new(MockHasher)
 			authUseCase := setupAuthUseCase(mockRepo, mockHasher)
 
-			mockHasher.On("HashPassword", testCase.password).Return(testCase.mockHash)
-			mockRepo.On("GetUserByCredentials", mock.Anything, testCase.username, testCase.mockHash).Return(testCase.mockUser, testCase.mockError)
+			mockRepo.On("GetUserByUsername", mock.Anything, testCase.username).Return(testCase.mockUser, testCase.mockRepoErr)
+			mockHasher.On("Verify", testCase.password, testCase.mockUser.Password).Return(testCase.mockVerify, testCase.mockVerifyErr)
+			mockHasher.On("NeedsRehash", testCase.mockUser.Password).Return(testCase.needsRehash)
+			mockHasher.On("Hash", testCase.password).Return("$argon2id$newhash", nil)
+			mockRepo.On("UpdatePasswordHash", mock.Anything, testCase.mockUser.Id, "$argon2id$newhash").Return(nil)
 
 			actualUser, actualErr := authUseCase.AuthenticateUser(context.Background(), testCase.username, testCase.password)
 
 			assertUserResult(t, testCase.expectedUser, actualUser, testCase.expectedErr, actualErr)
 
-			mockHasher.AssertCalled(t, "HashPassword", testCase.password)
-			mockRepo.AssertCalled(t, "GetUserByCredentials", mock.Anything, testCase.username, testCase.mockHash)
+			mockRepo.AssertCalled(t, "GetUserByUsername", mock.Anything, testCase.username)
+			if testCase.needsRehash {
+				mockRepo.AssertCalled(t, "UpdatePasswordHash", mock.Anything, testCase.mockUser.Id, "$argon2id$newhash")
+			} else {
+				mockRepo.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+			}
 		})
 	}
 }
 
-// TestGenerateToken tests the GenerateToken function in the AuthUseCase
-func TestGenerateToken(t *testing.T) {
+// setupTokenUseCase initializes the AuthUseCase with token related mocks
+func setupTokenUseCase(mockTokenRepo *MockTokenRepo, mockTokenMaker *MockTokenMaker) *usecase.AuthUseCase {
+	return usecase.NewAuthUseCase(nil, mockTokenRepo, nil, nil, mockTokenMaker, &logger.NoOpLogger{})
+}
+
+// TestGenerateTokens tests the GenerateTokens function in the AuthUseCase
+func TestGenerateTokens(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
 	testCases := []struct {
 		name           string
 		userId         int
-		mockError      error
-		expectedToken  string
+		createErr      error
+		storeErr       error
+		expectedTokens entity.TokenPair
 		expectedErrMsg string
 	}{
 		{
 			name:           "Successful token generation",
 			userId:         123,
-			mockError:      nil,
-			expectedToken:  "generatedToken123",
-			expectedErrMsg: "",
+			expectedTokens: entity.TokenPair{AccessToken: "accessToken123", RefreshToken: "refreshToken123"},
 		},
 		{
-			name:           "Failed token generation",
+			name:           "Failed access token generation",
 			userId:         456,
-			mockError:      errors.New("token generation error"),
-			expectedToken:  "",
+			createErr:      errors.New("token generation error"),
+			expectedTokens: entity.TokenPair{},
 			expectedErrMsg: "token generation error",
 		},
+		{
+			name:           "Failed refresh token storing",
+			userId:         789,
+			storeErr:       errors.New("database error"),
+			expectedTokens: entity.TokenPair{},
+			expectedErrMsg: "database error",
+		},
 	}
 
 	for _, testCase := range testCases {
+		testCase := testCase
+
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
 			mockTokenMaker := new(MockTokenMaker)
-			authUseCase := usecase.NewAuthUseCase(nil, nil, mockTokenMaker)
+			mockTokenRepo := new(MockTokenRepo)
+			authUseCase := setupTokenUseCase(mockTokenRepo, mockTokenMaker)
 
-			mockTokenMaker.On("CreateToken", testCase.userId).Return(testCase.expectedToken, testCase.mockError)
+			mockTokenMaker.On("CreateToken", testCase.userId, entity.RoleUser).Return("accessToken123", &token.Payload{}, testCase.createErr)
+			mockTokenMaker.On("CreateRefreshToken").Return("refreshToken123", expiresAt, nil)
+			mockTokenRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(refreshToken *entity.RefreshToken) bool {
+				return refreshToken.UserId == testCase.userId &&
+					refreshToken.TokenHash == token.HashToken("refreshToken123") &&
+					refreshToken.FamilyId != ""
+			})).Return(testCase.storeErr)
 
-			actualToken, actualErr := authUseCase.GenerateToken(context.Background(), testCase.userId)
+			actualTokens, actualErr := authUseCase.GenerateTokens(context.Background(), testCase.userId, entity.RoleUser)
 
-			assert.Equal(t, testCase.expectedToken, actualToken)
+			assert.Equal(t, testCase.expectedTokens, actualTokens)
 			if testCase.expectedErrMsg != "" {
-				assert.Error(t, actualErr)
 				assert.EqualError(t, actualErr, testCase.expectedErrMsg)
 			} else {
 				assert.NoError(t, actualErr)
 			}
+		})
+	}
+}
+
+// TestRefreshTokens tests the RefreshTokens function in the AuthUseCase
+func TestRefreshTokens(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+	activeToken := entity.RefreshToken{Id: 1, UserId: 7, FamilyId: "family", ExpiresAt: time.Now().Add(time.Hour)}
+	revokedToken := entity.RefreshToken{Id: 1, UserId: 7, FamilyId: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+	expiredToken := entity.RefreshToken{Id: 1, UserId: 7, FamilyId: "family", ExpiresAt: time.Now().Add(-time.Hour)}
+
+	testCases := []struct {
+		name             string
+		storedToken      entity.RefreshToken
+		getErr           error
+		rotateErr        error
+		expectFamilyDrop bool
+		expectedTokens   entity.TokenPair
+		expectedErr      error
+	}{
+		{
+			name:           "Successful rotation",
+			storedToken:    activeToken,
+			expectedTokens: entity.TokenPair{AccessToken: "newAccess", RefreshToken: "newRefresh"},
+		},
+		{
+			name:        "Unknown refresh token",
+			getErr:      utils.ErrInvalidRefreshToken,
+			expectedErr: utils.ErrInvalidRefreshToken,
+		},
+		{
+			name:        "Expired refresh token",
+			storedToken: expiredToken,
+			expectedErr: utils.ErrRefreshTokenExpired,
+		},
+		{
+			name:             "Reused refresh token revokes family",
+			storedToken:      revokedToken,
+			expectFamilyDrop: true,
+			expectedErr:      utils.ErrRefreshTokenReused,
+		},
+		{
+			name:             "Concurrent rotation revokes family",
+			storedToken:      activeToken,
+			rotateErr:        utils.ErrRefreshTokenReused,
+			expectFamilyDrop: true,
+			expectedErr:      utils.ErrRefreshTokenReused,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockAuthRepo)
+			mockTokenMaker := new(MockTokenMaker)
+			mockTokenRepo := new(MockTokenRepo)
+			authUseCase := usecase.NewAuthUseCase(mockRepo, mockTokenRepo, nil, nil, mockTokenMaker, &logger.NoOpLogger{})
+
+			mockRepo.On("GetUserRole", mock.Anything, 7).Return(entity.RoleSupport, nil)
+			mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, token.HashToken("oldRefresh")).Return(testCase.storedToken, testCase.getErr)
+			mockTokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+			mockTokenRepo.On("RotateRefreshToken", mock.Anything, 1, mock.MatchedBy(func(newToken *entity.RefreshToken) bool {
+				return newToken.FamilyId == "family" && newToken.UserId == 7
+			})).Return(testCase.rotateErr)
+			mockTokenMaker.On("CreateToken", 7, entity.RoleSupport).Return("newAccess", &token.Payload{}, nil)
+			mockTokenMaker.On("CreateRefreshToken").Return("newRefresh", time.Now().Add(time.Hour), nil)
+
+			actualTokens, actualErr := authUseCase.RefreshTokens(context.Background(), "oldRefresh")
+
+			assert.Equal(t, testCase.expectedErr, actualErr)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, testCase.expectedTokens, actualTokens)
+			}
+
+			if testCase.expectFamilyDrop {
+				mockTokenRepo.AssertCalled(t, "RevokeRefreshTokenFamily", mock.Anything, "family")
+			} else {
+				mockTokenRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// TestSignOut tests the SignOut function in the AuthUseCase
+func TestSignOut(t *testing.T) {
+	payload := &token.Payload{TokenId: "jti", UserId: 7, ExpiresAt: time.Now().Add(time.Hour)}
+
+	testCases := []struct {
+		name               string
+		refreshToken       string
+		verifyErr          error
+		storedToken        entity.RefreshToken
+		getErr             error
+		expectFamilyRevoke bool
+		expectedErr        error
+	}{
+		{
+			name:        "Sign out without refresh token",
+			expectedErr: nil,
+		},
+		{
+			name:               "Sign out with refresh token",
+			refreshToken:       "refresh",
+			storedToken:        entity.RefreshToken{Id: 1, UserId: 7, FamilyId: "family"},
+			expectFamilyRevoke: true,
+			expectedErr:        nil,
+		},
+		{
+			name:         "Refresh token of another user is ignored",
+			refreshToken: "refresh",
+			storedToken:  entity.RefreshToken{Id: 1, UserId: 8, FamilyId: "family"},
+			expectedErr:  nil,
+		},
+		{
+			name:         "Unknown refresh token is ignored",
+			refreshToken: "refresh",
+			getErr:       utils.ErrInvalidRefreshToken,
+			expectedErr:  nil,
+		},
+		{
+			name:        "Invalid access token",
+			verifyErr:   errors.New("invalid token"),
+			expectedErr: errors.New("invalid token"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
 
-			mockTokenMaker.AssertCalled(t, "CreateToken", testCase.userId)
+			mockTokenMaker := new(MockTokenMaker)
+			mockTokenRepo := new(MockTokenRepo)
+			authUseCase := setupTokenUseCase(mockTokenRepo, mockTokenMaker)
+
+			mockTokenMaker.On("VerifyToken", "access").Return(payload, testCase.verifyErr)
+			mockTokenRepo.On("RevokeAccessToken", mock.Anything, "jti", mock.Anything).Return(nil)
+			mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, token.HashToken("refresh")).Return(testCase.storedToken, testCase.getErr)
+			mockTokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+			actualErr := authUseCase.SignOut(context.Background(), "access", testCase.refreshToken)
+
+			assert.Equal(t, testCase.expectedErr, actualErr)
+			if testCase.verifyErr == nil {
+				mockTokenRepo.AssertCalled(t, "RevokeAccessToken", mock.Anything, "jti", mock.Anything)
+			}
+			if testCase.expectFamilyRevoke {
+				mockTokenRepo.AssertCalled(t, "RevokeRefreshTokenFamily", mock.Anything, "family")
+			} else {
+				mockTokenRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything)
+			}
 		})
 	}
 }
@@ -194,38 +470,57 @@ func TestParseToken(t *testing.T) {
 	testCases := []struct {
 		name           string
 		accessToken    string
+		mockPayload    *token.Payload
 		mockError      error
+		revoked        bool
 		expectedUserId int
+		expectedRole   string
 		expectedErrMsg string
 	}{
 		{
 			name:           "Successful token parsing",
 			accessToken:    "validToken123",
+			mockPayload:    &token.Payload{TokenId: "jti", UserId: 123, Role: entity.RoleAdmin},
 			mockError:      nil,
 			expectedUserId: 123,
+			expectedRole:   entity.RoleAdmin,
 			expectedErrMsg: "",
 		},
 		{
 			name:           "Failed token parsing",
 			accessToken:    "invalidToken456",
+			mockPayload:    nil,
 			mockError:      errors.New("invalid token"),
 			expectedUserId: 0,
 			expectedErrMsg: "invalid token",
 		},
+		{
+			name:           "Revoked token",
+			accessToken:    "revokedToken789",
+			mockPayload:    &token.Payload{TokenId: "jti", UserId: 123},
+			revoked:        true,
+			expectedUserId: 0,
+			expectedErrMsg: utils.ErrTokenRevoked.Error(),
+		},
 	}
 
 	for _, testCase := range testCases {
+		testCase := testCase
+
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
 			mockTokenMaker := new(MockTokenMaker)
-			authUseCase := usecase.NewAuthUseCase(nil, nil, mockTokenMaker)
+			mockTokenRepo := new(MockTokenRepo)
+			authUseCase := setupTokenUseCase(mockTokenRepo, mockTokenMaker)
 
-			mockTokenMaker.On("VerifyToken", testCase.accessToken).Return(testCase.expectedUserId, testCase.mockError)
+			mockTokenMaker.On("VerifyToken", testCase.accessToken).Return(testCase.mockPayload, testCase.mockError)
+			mockTokenRepo.On("IsAccessTokenRevoked", mock.Anything, "jti").Return(testCase.revoked, nil)
 
-			actualUserId, actualErr := authUseCase.ParseToken(context.Background(), testCase.accessToken)
+			actualUserId, actualRole, actualErr := authUseCase.ParseToken(context.Background(), testCase.accessToken)
 
 			assert.Equal(t, testCase.expectedUserId, actualUserId)
+			assert.Equal(t, testCase.expectedRole, actualRole)
 			if testCase.expectedErrMsg != "" {
 				assert.Error(t, actualErr)
 				assert.EqualError(t, actualErr, testCase.expectedErrMsg)
@@ -237,3 +532,92 @@ func TestParseToken(t *testing.T) {
 		})
 	}
 }
+
+// TestParsePersonalAccessToken tests the ParsePersonalAccessToken function in the AuthUseCase
+func TestParsePersonalAccessToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	scopes := []string{entity.ScopeListsRead}
+
+	testCases := []struct {
+		name           string
+		mockToken      entity.PersonalAccessToken
+		mockError      error
+		expectTouch    bool
+		expectedUserId int
+		expectedScopes []string
+		expectedErr    error
+	}{
+		{
+			name:           "Valid token",
+			mockToken:      entity.PersonalAccessToken{Id: 5, UserId: 1, Scopes: scopes, ExpiresAt: &future},
+			expectTouch:    true,
+			expectedUserId: 1,
+			expectedScopes: scopes,
+		},
+		{
+			name:        "Unknown token",
+			mockError:   utils.ErrPersonalAccessTokenNotFound,
+			expectedErr: utils.ErrPersonalAccessTokenNotFound,
+		},
+		{
+			name:        "Revoked token",
+			mockToken:   entity.PersonalAccessToken{Id: 5, UserId: 1, Scopes: scopes, RevokedAt: &past},
+			expectedErr: utils.ErrTokenRevoked,
+		},
+		{
+			name:        "Expired token",
+			mockToken:   entity.PersonalAccessToken{Id: 5, UserId: 1, Scopes: scopes, ExpiresAt: &past},
+			expectedErr: utils.ErrPersonalAccessTokenExpired,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockPatRepo := new(MockPersonalAccessTokenRepo)
+			authUseCase := usecase.NewAuthUseCase(nil, nil, mockPatRepo, nil, nil, &logger.NoOpLogger{})
+
+			mockPatRepo.On("GetByHash", mock.Anything, token.HashToken("tdp_secret")).Return(testCase.mockToken, testCase.mockError)
+			if testCase.expectTouch {
+				mockPatRepo.On("UpdateLastUsed", mock.Anything, testCase.mockToken.Id).Return(nil)
+			}
+
+			userId, scopes, err := authUseCase.ParsePersonalAccessToken(context.Background(), "tdp_secret")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedUserId, userId)
+			assert.Equal(t, testCase.expectedScopes, scopes)
+
+			mockPatRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestParsePersonalAccessTokenLastUsed tests that a token presented on every request updates its last use once per interval
+func TestParsePersonalAccessTokenLastUsed(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+
+	mockPatRepo := new(MockPersonalAccessTokenRepo)
+	authUseCase := usecase.NewAuthUseCase(nil, nil, mockPatRepo, nil, nil, &logger.NoOpLogger{})
+
+	mockPatRepo.On("GetByHash", mock.Anything, token.HashToken("tdp_first")).
+		Return(entity.PersonalAccessToken{Id: 5, UserId: 1, ExpiresAt: &future}, nil)
+	mockPatRepo.On("GetByHash", mock.Anything, token.HashToken("tdp_second")).
+		Return(entity.PersonalAccessToken{Id: 6, UserId: 1, ExpiresAt: &future}, nil)
+	mockPatRepo.On("UpdateLastUsed", mock.Anything, 5).Return(nil).Once()
+	mockPatRepo.On("UpdateLastUsed", mock.Anything, 6).Return(nil).Once()
+
+	for i := 0; i < 3; i++ {
+		_, _, err := authUseCase.ParsePersonalAccessToken(context.Background(), "tdp_first")
+		assert.NoError(t, err)
+	}
+
+	_, _, err := authUseCase.ParsePersonalAccessToken(context.Background(), "tdp_second")
+	assert.NoError(t, err)
+
+	mockPatRepo.AssertExpectations(t)
+}
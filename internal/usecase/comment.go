@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// CommentUseCase handles the business logic related to the comments of items,
+// access to the comments follows the role of the user on the list holding the item
+type CommentUseCase struct {
+	repo     repository.Comment
+	itemRepo repository.Item
+}
+
+// NewCommentUseCase creates a new instance of CommentUseCase
+func NewCommentUseCase(r repository.Comment, ir repository.Item) *CommentUseCase {
+	return &CommentUseCase{
+		repo:     r,
+		itemRepo: ir,
+	}
+}
+
+// Create adds a comment of the user to an item, or a reply when the input has a parent, if the user is an editor
+// of the list holding the item. The repository records the comment created event in the outbox
+func (uc *CommentUseCase) Create(ctx context.Context, userId, itemId int, input entity.CreateCommentInput) (entity.Comment, error) {
+	if err := input.Validate(); err != nil {
+		return entity.Comment{}, err
+	}
+
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleEditor); err != nil {
+		return entity.Comment{}, err
+	}
+
+	comment := entity.Comment{
+		ItemId:   itemId,
+		ParentId: input.ParentId,
+		Author:   entity.CommentAuthor{Id: userId},
+		Body:     input.Body,
+	}
+
+	commentId, err := uc.repo.Create(ctx, &comment)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	comment, err = uc.repo.GetOneById(ctx, itemId, commentId)
+	if err != nil {
+		return entity.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// GetAll retrieves a page of the comments of an item, or of the replies to a comment,
+// if the list holding the item is shared with the user
+func (uc *CommentUseCase) GetAll(ctx context.Context, userId, itemId int, query entity.CommentQuery) (entity.CommentPage, error) {
+	if err := query.Validate(); err != nil {
+		return entity.CommentPage{}, err
+	}
+
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return entity.CommentPage{}, err
+	}
+
+	return uc.repo.GetPage(ctx, itemId, query)
+}
+
+// UpdateOneById edits the body of a comment, only its author can edit a comment while the item is shared with them
+func (uc *CommentUseCase) UpdateOneById(ctx context.Context, userId, itemId, commentId int, input entity.UpdateCommentInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return err
+	}
+
+	comment, err := uc.repo.GetOneById(ctx, itemId, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.Author.Id != userId {
+		return utils.ErrCommentNotAuthor
+	}
+
+	return uc.repo.UpdateBody(ctx, itemId, commentId, input.Body)
+}
+
+// DeleteOneById deletes a comment and its replies, a comment can be deleted by its author
+// or by an owner of the list holding the item
+func (uc *CommentUseCase) DeleteOneById(ctx context.Context, userId, itemId, commentId int) error {
+	role, err := uc.itemRepo.GetUserItemRole(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	comment, err := uc.repo.GetOneById(ctx, itemId, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.Author.Id != userId && !entity.ListRoleAllows(role, entity.ListRoleOwner) {
+		return utils.ErrCommentNotAuthor
+	}
+
+	return uc.repo.DeleteOneById(ctx, itemId, commentId)
+}
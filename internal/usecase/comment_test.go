@@ -0,0 +1,279 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateComment tests the Create function in the CommentUseCase
+func TestCreateComment(t *testing.T) {
+	parentId := 7
+	created := entity.Comment{Id: 9, ItemId: 5, ParentId: &parentId, Author: entity.CommentAuthor{Id: 1, Username: "ann"}, Body: "Done?"}
+
+	testCases := []struct {
+		name            string
+		input           entity.CreateCommentInput
+		mockBehavior    func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo)
+		expectedComment entity.Comment
+		expectedErr     error
+	}{
+		{
+			name:  "Success",
+			input: entity.CreateCommentInput{Body: "  Done?  ", ParentId: &parentId},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockCommentRepo.On("Create", mock.Anything, &entity.Comment{
+					ItemId:   5,
+					ParentId: &parentId,
+					Author:   entity.CommentAuthor{Id: 1},
+					Body:     "Done?",
+				}).Return(9, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(created, nil)
+			},
+			expectedComment: created,
+		},
+		{
+			name:            "Empty body",
+			input:           entity.CreateCommentInput{Body: "   "},
+			mockBehavior:    func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {},
+			expectedComment: entity.Comment{},
+			expectedErr:     utils.ErrInvalidCommentBody,
+		},
+		{
+			name:            "Body too long",
+			input:           entity.CreateCommentInput{Body: strings.Repeat("a", entity.MaxCommentLength+1)},
+			mockBehavior:    func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {},
+			expectedComment: entity.Comment{},
+			expectedErr:     utils.ErrInvalidCommentBody,
+		},
+		{
+			name:  "Viewer cannot comment",
+			input: entity.CreateCommentInput{Body: "Done?"},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+			},
+			expectedComment: entity.Comment{},
+			expectedErr:     utils.ErrListPermissionDenied,
+		},
+		{
+			name:  "Parent of another item",
+			input: entity.CreateCommentInput{Body: "Done?", ParentId: &parentId},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+				mockCommentRepo.On("Create", mock.Anything, mock.Anything).Return(0, utils.ErrCommentParentNotFound)
+			},
+			expectedComment: entity.Comment{},
+			expectedErr:     utils.ErrCommentParentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockCommentRepo := new(MockCommentRepo)
+			commentUseCase := usecase.NewCommentUseCase(mockCommentRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockCommentRepo)
+
+			comment, err := commentUseCase.Create(context.Background(), 1, 5, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedComment, comment)
+
+			mockItemRepo.AssertExpectations(t)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetAllComments tests the GetAll function in the CommentUseCase
+func TestGetAllComments(t *testing.T) {
+	nextCursor := 4
+	page := entity.CommentPage{Comments: []entity.Comment{{Id: 4, ItemId: 5}}, NextCursor: &nextCursor}
+
+	testCases := []struct {
+		name         string
+		query        entity.CommentQuery
+		mockBehavior func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo)
+		expectedPage entity.CommentPage
+		expectedErr  error
+	}{
+		{
+			name:  "Default limit",
+			query: entity.CommentQuery{After: 2},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockCommentRepo.On("GetPage", mock.Anything, 5, entity.CommentQuery{After: 2, Limit: entity.DefaultCommentPageLimit}).
+					Return(page, nil)
+			},
+			expectedPage: page,
+		},
+		{
+			name:         "Limit too large",
+			query:        entity.CommentQuery{Limit: entity.MaxCommentPageLimit + 1},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {},
+			expectedPage: entity.CommentPage{},
+			expectedErr:  utils.ErrInvalidCommentPageLimit,
+		},
+		{
+			name:  "Item not shared with the user",
+			query: entity.CommentQuery{Limit: 10},
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return("", utils.ErrUserNotOwner)
+			},
+			expectedPage: entity.CommentPage{},
+			expectedErr:  utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockCommentRepo := new(MockCommentRepo)
+			commentUseCase := usecase.NewCommentUseCase(mockCommentRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockCommentRepo)
+
+			page, err := commentUseCase.GetAll(context.Background(), 1, 5, testCase.query)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedPage, page)
+
+			mockItemRepo.AssertExpectations(t)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateComment tests the UpdateOneById function in the CommentUseCase
+func TestUpdateComment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{Id: 9, Author: entity.CommentAuthor{Id: 1}}, nil)
+				mockCommentRepo.On("UpdateBody", mock.Anything, 5, 9, "Edited").Return(nil)
+			},
+		},
+		{
+			name: "Not the author",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{Id: 9, Author: entity.CommentAuthor{Id: 2}}, nil)
+			},
+			expectedErr: utils.ErrCommentNotAuthor,
+		},
+		{
+			name: "Comment not found",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{}, utils.ErrCommentNotFound)
+			},
+			expectedErr: utils.ErrCommentNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockCommentRepo := new(MockCommentRepo)
+			commentUseCase := usecase.NewCommentUseCase(mockCommentRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockCommentRepo)
+
+			err := commentUseCase.UpdateOneById(context.Background(), 1, 5, 9, entity.UpdateCommentInput{Body: " Edited "})
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockItemRepo.AssertExpectations(t)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDeleteComment tests the DeleteOneById function in the CommentUseCase
+func TestDeleteComment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Author deletes",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{Id: 9, Author: entity.CommentAuthor{Id: 1}}, nil)
+				mockCommentRepo.On("DeleteOneById", mock.Anything, 5, 9).Return(nil)
+			},
+		},
+		{
+			name: "Owner deletes",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{Id: 9, Author: entity.CommentAuthor{Id: 2}}, nil)
+				mockCommentRepo.On("DeleteOneById", mock.Anything, 5, 9).Return(nil)
+			},
+		},
+		{
+			name: "Editor cannot delete others",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockCommentRepo.On("GetOneById", mock.Anything, 5, 9).Return(entity.Comment{Id: 9, Author: entity.CommentAuthor{Id: 2}}, nil)
+			},
+			expectedErr: utils.ErrCommentNotAuthor,
+		},
+		{
+			name: "Item not shared with the user",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockCommentRepo *MockCommentRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return("", utils.ErrUserNotOwner)
+			},
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockCommentRepo := new(MockCommentRepo)
+			commentUseCase := usecase.NewCommentUseCase(mockCommentRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockCommentRepo)
+
+			err := commentUseCase.DeleteOneById(context.Background(), 1, 5, 9)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockItemRepo.AssertExpectations(t)
+			mockCommentRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// idempotencyReservationTTL bounds how long a key stays reserved by a request that never completes,
+// e.g. because the server stopped while handling it
+const idempotencyReservationTTL = time.Minute
+
+// maxIdempotencyKeyLength is the maximum number of characters of an Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyUseCase handles the business logic related to idempotent requests
+type IdempotencyUseCase struct {
+	repo repository.Idempotency
+	ttl  time.Duration
+}
+
+// NewIdempotencyUseCase creates a new instance of IdempotencyUseCase, responses are replayed for ttl
+func NewIdempotencyUseCase(r repository.Idempotency, ttl time.Duration) *IdempotencyUseCase {
+	return &IdempotencyUseCase{
+		repo: r,
+		ttl:  ttl,
+	}
+}
+
+// Begin reserves the key of the user for the request with the fingerprint. The stored response is returned when
+// the request was already completed, nil is returned when the request has to be handled
+func (uc *IdempotencyUseCase) Begin(ctx context.Context, userId int, key, fingerprint string) (*entity.IdempotentResponse, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, utils.ErrInvalidIdempotencyKey
+	}
+
+	reserved, err := uc.repo.Reserve(ctx, userId, key, entity.IdempotentRequest{Fingerprint: fingerprint}, idempotencyReservationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if reserved {
+		return nil, nil
+	}
+
+	request, found, err := uc.repo.Get(ctx, userId, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reservation expired between both calls, the request that held it is treated as still running
+	if !found {
+		return nil, utils.ErrIdempotencyKeyInProgress
+	}
+
+	if request.Fingerprint != fingerprint {
+		return nil, utils.ErrIdempotencyKeyReused
+	}
+
+	if !request.Completed() {
+		return nil, utils.ErrIdempotencyKeyInProgress
+	}
+
+	return request.Response, nil
+}
+
+// Complete stores the response of the request reserved by Begin so that it is replayed on retries
+func (uc *IdempotencyUseCase) Complete(ctx context.Context, userId int, key, fingerprint string, response entity.IdempotentResponse) error {
+	request := entity.IdempotentRequest{
+		Fingerprint: fingerprint,
+		Response:    &response,
+	}
+
+	return uc.repo.Save(ctx, userId, key, request, uc.ttl)
+}
+
+// Abort releases the key reserved by Begin without a response, a retry then handles the request again
+func (uc *IdempotencyUseCase) Abort(ctx context.Context, userId int, key string) error {
+	return uc.repo.Release(ctx, userId, key)
+}
@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestBeginIdempotentRequest tests the Begin function in the IdempotencyUseCase
+func TestBeginIdempotentRequest(t *testing.T) {
+	response := entity.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"status":"ok"}`)}
+
+	testCases := []struct {
+		name             string
+		key              string
+		mockBehavior     func(mockRepo *MockIdempotencyRepo)
+		expectedResponse *entity.IdempotentResponse
+		expectedErr      error
+	}{
+		{
+			name: "First request reserves the key",
+			key:  "key",
+			mockBehavior: func(mockRepo *MockIdempotencyRepo) {
+				mockRepo.On("Reserve", mock.Anything, 1, "key", entity.IdempotentRequest{Fingerprint: "fp"}, time.Minute).Return(true, nil)
+			},
+			expectedResponse: nil,
+			expectedErr:      nil,
+		},
+		{
+			name: "Completed request is replayed",
+			key:  "key",
+			mockBehavior: func(mockRepo *MockIdempotencyRepo) {
+				mockRepo.On("Reserve", mock.Anything, 1, "key", mock.Anything, mock.Anything).Return(false, nil)
+				mockRepo.On("Get", mock.Anything, 1, "key").Return(entity.IdempotentRequest{Fingerprint: "fp", Response: &response}, true, nil)
+			},
+			expectedResponse: &response,
+			expectedErr:      nil,
+		},
+		{
+			name: "Key reused with another request",
+			key:  "key",
+			mockBehavior: func(mockRepo *MockIdempotencyRepo) {
+				mockRepo.On("Reserve", mock.Anything, 1, "key", mock.Anything, mock.Anything).Return(false, nil)
+				mockRepo.On("Get", mock.Anything, 1, "key").Return(entity.IdempotentRequest{Fingerprint: "other", Response: &response}, true, nil)
+			},
+			expectedResponse: nil,
+			expectedErr:      utils.ErrIdempotencyKeyReused,
+		},
+		{
+			name: "Request still in progress",
+			key:  "key",
+			mockBehavior: func(mockRepo *MockIdempotencyRepo) {
+				mockRepo.On("Reserve", mock.Anything, 1, "key", mock.Anything, mock.Anything).Return(false, nil)
+				mockRepo.On("Get", mock.Anything, 1, "key").Return(entity.IdempotentRequest{Fingerprint: "fp"}, true, nil)
+			},
+			expectedResponse: nil,
+			expectedErr:      utils.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name:             "Key too long",
+			key:              strings.Repeat("k", 256),
+			mockBehavior:     func(mockRepo *MockIdempotencyRepo) {},
+			expectedResponse: nil,
+			expectedErr:      utils.ErrInvalidIdempotencyKey,
+		},
+		{
+			name: "Repository failure",
+			key:  "key",
+			mockBehavior: func(mockRepo *MockIdempotencyRepo) {
+				mockRepo.On("Reserve", mock.Anything, 1, "key", mock.Anything, mock.Anything).Return(false, errors.New("redis error"))
+			},
+			expectedResponse: nil,
+			expectedErr:      errors.New("redis error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockIdempotencyRepo)
+			idempotencyUseCase := usecase.NewIdempotencyUseCase(mockRepo, 24*time.Hour)
+
+			testCase.mockBehavior(mockRepo)
+
+			storedResponse, err := idempotencyUseCase.Begin(context.Background(), 1, testCase.key, "fp")
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedResponse, storedResponse)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCompleteIdempotentRequest tests that the Complete function in the IdempotencyUseCase stores the response for the ttl
+func TestCompleteIdempotentRequest(t *testing.T) {
+	mockRepo := new(MockIdempotencyRepo)
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(mockRepo, 24*time.Hour)
+
+	response := entity.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"status":"ok"}`)}
+	mockRepo.On("Save", mock.Anything, 1, "key", entity.IdempotentRequest{Fingerprint: "fp", Response: &response}, 24*time.Hour).Return(nil)
+
+	err := idempotencyUseCase.Complete(context.Background(), 1, "key", "fp", response)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// InvitationUseCase handles the business logic related to list invitations
+type InvitationUseCase struct {
+	repo     repository.Invitation
+	listRepo repository.List
+	authRepo repository.Auth
+	ttl      time.Duration
+}
+
+// NewInvitationUseCase creates a new instance of InvitationUseCase, invitations expire after ttl
+func NewInvitationUseCase(r repository.Invitation, lr repository.List, ar repository.Auth, ttl time.Duration) *InvitationUseCase {
+	return &InvitationUseCase{
+		repo:     r,
+		listRepo: lr,
+		authRepo: ar,
+		ttl:      ttl,
+	}
+}
+
+// Create invites a user to a list if the user is an owner of the list. Without a username a link
+// invitation is created and its raw token is returned only once
+func (uc *InvitationUseCase) Create(ctx context.Context, userId, listId int, input entity.CreateInvitationInput) (entity.CreatedInvitation, error) {
+	if err := input.Validate(); err != nil {
+		return entity.CreatedInvitation{}, err
+	}
+
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleOwner); err != nil {
+		return entity.CreatedInvitation{}, err
+	}
+
+	invitation := entity.Invitation{
+		ListId:    listId,
+		InviterId: userId,
+		Role:      input.Role,
+		ExpiresAt: time.Now().Add(uc.ttl),
+	}
+
+	var rawToken string
+	if input.Username != "" {
+		invitee, err := uc.authRepo.GetUserByUsername(ctx, input.Username)
+		if err != nil {
+			return entity.CreatedInvitation{}, err
+		}
+
+		if _, err := uc.listRepo.GetUserListRole(ctx, invitee.Id, listId); err == nil {
+			return entity.CreatedInvitation{}, utils.ErrCollaboratorExists
+		} else if err != utils.ErrUserNotOwner {
+			return entity.CreatedInvitation{}, err
+		}
+
+		invitation.InviteeId = &invitee.Id
+	} else {
+		var err error
+		rawToken, err = token.NewInvitationToken()
+		if err != nil {
+			return entity.CreatedInvitation{}, err
+		}
+
+		tokenHash := token.HashToken(rawToken)
+		invitation.TokenHash = &tokenHash
+	}
+
+	if _, err := uc.repo.Create(ctx, &invitation); err != nil {
+		return entity.CreatedInvitation{}, err
+	}
+
+	return entity.CreatedInvitation{
+		Invitation: invitation,
+		Token:      rawToken,
+	}, nil
+}
+
+// GetAllForList retrieves the pending invitations of a list if the user is an owner of the list
+func (uc *InvitationUseCase) GetAllForList(ctx context.Context, userId, listId int) ([]entity.Invitation, error) {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleOwner); err != nil {
+		return []entity.Invitation{}, err
+	}
+
+	return uc.repo.GetPendingByListId(ctx, listId)
+}
+
+// GetAllForUser retrieves the pending invitations addressed to the user
+func (uc *InvitationUseCase) GetAllForUser(ctx context.Context, userId int) ([]entity.Invitation, error) {
+	return uc.repo.GetPendingByInviteeId(ctx, userId)
+}
+
+// Accept accepts an invitation addressed to the user and shares the list with them
+func (uc *InvitationUseCase) Accept(ctx context.Context, userId, invitationId int) error {
+	invitation, err := uc.getAddressedTo(ctx, userId, invitationId)
+	if err != nil {
+		return err
+	}
+
+	return uc.accept(ctx, userId, invitation)
+}
+
+// AcceptLink redeems a link invitation for the user and shares the list with them
+func (uc *InvitationUseCase) AcceptLink(ctx context.Context, userId int, input entity.AcceptInvitationLinkInput) (entity.Invitation, error) {
+	invitation, err := uc.repo.GetByTokenHash(ctx, token.HashToken(input.Token))
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	if err := uc.accept(ctx, userId, invitation); err != nil {
+		return entity.Invitation{}, err
+	}
+
+	invitation.InviteeId = &userId
+	invitation.Status = entity.InvitationStatusAccepted
+
+	return invitation, nil
+}
+
+// Decline declines an invitation addressed to the user
+func (uc *InvitationUseCase) Decline(ctx context.Context, userId, invitationId int) error {
+	invitation, err := uc.getAddressedTo(ctx, userId, invitationId)
+	if err != nil {
+		return err
+	}
+
+	if err := ensurePending(invitation); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateStatus(ctx, invitationId, entity.InvitationStatusDeclined)
+}
+
+// Revoke withdraws a pending invitation if the user is an owner of its list
+func (uc *InvitationUseCase) Revoke(ctx context.Context, userId, invitationId int) error {
+	invitation, err := uc.repo.GetOneById(ctx, invitationId)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeList(ctx, uc.listRepo, userId, invitation.ListId, entity.ListRoleOwner); err != nil {
+		if err == utils.ErrUserNotOwner {
+			return utils.ErrInvitationNotFound
+		}
+
+		return err
+	}
+
+	if err := ensurePending(invitation); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateStatus(ctx, invitationId, entity.InvitationStatusRevoked)
+}
+
+// getAddressedTo retrieves an invitation addressed to the user, invitations of other users
+// and link invitations are reported as not found
+func (uc *InvitationUseCase) getAddressedTo(ctx context.Context, userId, invitationId int) (entity.Invitation, error) {
+	invitation, err := uc.repo.GetOneById(ctx, invitationId)
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	if invitation.IsLink() || *invitation.InviteeId != userId {
+		return entity.Invitation{}, utils.ErrInvitationNotFound
+	}
+
+	return invitation, nil
+}
+
+// accept checks that the invitation can still be accepted and shares its list with the user
+func (uc *InvitationUseCase) accept(ctx context.Context, userId int, invitation entity.Invitation) error {
+	if err := ensurePending(invitation); err != nil {
+		return err
+	}
+
+	return uc.repo.Accept(ctx, invitation.Id, userId)
+}
+
+// ensurePending returns ErrInvitationExpired or ErrInvitationNotPending unless the invitation is still pending
+func ensurePending(invitation entity.Invitation) error {
+	switch invitation.Status {
+	case entity.InvitationStatusPending:
+		return nil
+	case entity.InvitationStatusExpired:
+		return utils.ErrInvitationExpired
+	default:
+		return utils.ErrInvitationNotPending
+	}
+}
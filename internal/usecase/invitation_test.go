@@ -0,0 +1,297 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateInvitation tests the Create function in the InvitationUseCase
+func TestCreateInvitation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		input        entity.CreateInvitationInput
+		mockBehavior func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo)
+		expectToken  bool
+		expectedErr  error
+	}{
+		{
+			name:  "Invite by username",
+			input: entity.CreateInvitationInput{Username: "bob", Role: entity.ListRoleEditor},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(entity.ListRoleOwner, nil)
+				mockAuthRepo.On("GetUserByUsername", mock.Anything, "bob").Return(entity.User{Id: 2}, nil)
+				mockListRepo.On("GetUserListRole", mock.Anything, 2, 3).Return("", utils.ErrUserNotOwner)
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(invitation *entity.Invitation) bool {
+					return invitation.InviteeId != nil && *invitation.InviteeId == 2 && invitation.TokenHash == nil &&
+						invitation.ListId == 3 && invitation.InviterId == 1 && invitation.ExpiresAt.After(time.Now())
+				})).Return(5, nil)
+			},
+			expectToken: false,
+			expectedErr: nil,
+		},
+		{
+			name:  "Invite link",
+			input: entity.CreateInvitationInput{Role: entity.ListRoleViewer},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(entity.ListRoleOwner, nil)
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(invitation *entity.Invitation) bool {
+					return invitation.InviteeId == nil && invitation.TokenHash != nil && len(*invitation.TokenHash) == 64
+				})).Return(5, nil)
+			},
+			expectToken: true,
+			expectedErr: nil,
+		},
+		{
+			name:         "Unknown role",
+			input:        entity.CreateInvitationInput{Role: "admin"},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {},
+			expectedErr:  utils.ErrInvalidListRole,
+		},
+		{
+			name:  "Editor cannot invite",
+			input: entity.CreateInvitationInput{Role: entity.ListRoleViewer},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(entity.ListRoleEditor, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:  "Invitee already a collaborator",
+			input: entity.CreateInvitationInput{Username: "bob", Role: entity.ListRoleEditor},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(entity.ListRoleOwner, nil)
+				mockAuthRepo.On("GetUserByUsername", mock.Anything, "bob").Return(entity.User{Id: 2}, nil)
+				mockListRepo.On("GetUserListRole", mock.Anything, 2, 3).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrCollaboratorExists,
+		},
+		{
+			name:  "Invitee not found",
+			input: entity.CreateInvitationInput{Username: "bob", Role: entity.ListRoleEditor},
+			mockBehavior: func(mockRepo *MockInvitationRepo, mockListRepo *MockListRepo, mockAuthRepo *MockAuthRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(entity.ListRoleOwner, nil)
+				mockAuthRepo.On("GetUserByUsername", mock.Anything, "bob").Return(entity.User{}, utils.ErrUserNotFound)
+			},
+			expectedErr: utils.ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockInvitationRepo)
+			mockListRepo := new(MockListRepo)
+			mockAuthRepo := new(MockAuthRepo)
+			testCase.mockBehavior(mockRepo, mockListRepo, mockAuthRepo)
+
+			invitationUseCase := usecase.NewInvitationUseCase(mockRepo, mockListRepo, mockAuthRepo, time.Hour)
+
+			invitation, err := invitationUseCase.Create(context.Background(), 1, 3, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectToken {
+				assert.True(t, strings.HasPrefix(invitation.Token, token.InvitationTokenPrefix))
+			} else {
+				assert.Empty(t, invitation.Token)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockListRepo.AssertExpectations(t)
+			mockAuthRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAcceptInvitation tests the Accept function in the InvitationUseCase
+func TestAcceptInvitation(t *testing.T) {
+	inviteeId := 2
+	otherId := 4
+
+	testCases := []struct {
+		name         string
+		invitation   entity.Invitation
+		mockBehavior func(mockRepo *MockInvitationRepo)
+		expectedErr  error
+	}{
+		{
+			name:       "Success",
+			invitation: entity.Invitation{Id: 5, InviteeId: &inviteeId, Status: entity.InvitationStatusPending},
+			mockBehavior: func(mockRepo *MockInvitationRepo) {
+				mockRepo.On("Accept", mock.Anything, 5, 2).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Addressed to another user",
+			invitation:   entity.Invitation{Id: 5, InviteeId: &otherId, Status: entity.InvitationStatusPending},
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrInvitationNotFound,
+		},
+		{
+			name:         "Link invitation",
+			invitation:   entity.Invitation{Id: 5, Status: entity.InvitationStatusPending},
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrInvitationNotFound,
+		},
+		{
+			name:         "Expired",
+			invitation:   entity.Invitation{Id: 5, InviteeId: &inviteeId, Status: entity.InvitationStatusExpired},
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrInvitationExpired,
+		},
+		{
+			name:         "Already declined",
+			invitation:   entity.Invitation{Id: 5, InviteeId: &inviteeId, Status: entity.InvitationStatusDeclined},
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrInvitationNotPending,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockInvitationRepo)
+			mockRepo.On("GetOneById", mock.Anything, 5).Return(testCase.invitation, nil)
+			testCase.mockBehavior(mockRepo)
+
+			invitationUseCase := usecase.NewInvitationUseCase(mockRepo, new(MockListRepo), new(MockAuthRepo), time.Hour)
+
+			err := invitationUseCase.Accept(context.Background(), 2, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAcceptInvitationLink tests the AcceptLink function in the InvitationUseCase
+func TestAcceptInvitationLink(t *testing.T) {
+	rawToken := token.InvitationTokenPrefix + "secret"
+
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockRepo *MockInvitationRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRepo *MockInvitationRepo) {
+				mockRepo.On("GetByTokenHash", mock.Anything, token.HashToken(rawToken)).
+					Return(entity.Invitation{Id: 5, ListId: 3, Status: entity.InvitationStatusPending}, nil)
+				mockRepo.On("Accept", mock.Anything, 5, 2).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Unknown token",
+			mockBehavior: func(mockRepo *MockInvitationRepo) {
+				mockRepo.On("GetByTokenHash", mock.Anything, token.HashToken(rawToken)).
+					Return(entity.Invitation{}, utils.ErrInvitationNotFound)
+			},
+			expectedErr: utils.ErrInvitationNotFound,
+		},
+		{
+			name: "Revoked",
+			mockBehavior: func(mockRepo *MockInvitationRepo) {
+				mockRepo.On("GetByTokenHash", mock.Anything, token.HashToken(rawToken)).
+					Return(entity.Invitation{Id: 5, ListId: 3, Status: entity.InvitationStatusRevoked}, nil)
+			},
+			expectedErr: utils.ErrInvitationNotPending,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockInvitationRepo)
+			testCase.mockBehavior(mockRepo)
+
+			invitationUseCase := usecase.NewInvitationUseCase(mockRepo, new(MockListRepo), new(MockAuthRepo), time.Hour)
+
+			invitation, err := invitationUseCase.AcceptLink(context.Background(), 2, entity.AcceptInvitationLinkInput{Token: rawToken})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, entity.InvitationStatusAccepted, invitation.Status)
+				assert.Equal(t, 2, *invitation.InviteeId)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRevokeInvitation tests the Revoke function in the InvitationUseCase
+func TestRevokeInvitation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         string
+		roleErr      error
+		mockBehavior func(mockRepo *MockInvitationRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			role: entity.ListRoleOwner,
+			mockBehavior: func(mockRepo *MockInvitationRepo) {
+				mockRepo.On("UpdateStatus", mock.Anything, 5, entity.InvitationStatusRevoked).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Editor cannot revoke",
+			role:         entity.ListRoleEditor,
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrListPermissionDenied,
+		},
+		{
+			name:         "List not shared with user",
+			roleErr:      utils.ErrUserNotOwner,
+			mockBehavior: func(mockRepo *MockInvitationRepo) {},
+			expectedErr:  utils.ErrInvitationNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockInvitationRepo)
+			mockListRepo := new(MockListRepo)
+			mockRepo.On("GetOneById", mock.Anything, 5).
+				Return(entity.Invitation{Id: 5, ListId: 3, Status: entity.InvitationStatusPending}, nil)
+			mockListRepo.On("GetUserListRole", mock.Anything, 1, 3).Return(testCase.role, testCase.roleErr)
+			testCase.mockBehavior(mockRepo)
+
+			invitationUseCase := usecase.NewInvitationUseCase(mockRepo, mockListRepo, new(MockAuthRepo), time.Hour)
+
+			err := invitationUseCase.Revoke(context.Background(), 1, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
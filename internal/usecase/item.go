@@ -2,144 +2,402 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
-	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
 	"github.com/berikulyBeket/todo-plus/pkg/search"
+	"github.com/berikulyBeket/todo-plus/utils"
 )
 
 // ItemUseCase manages the item-related use cases
 type ItemUseCase struct {
-	repo           repository.Item
-	listRepo       repository.List
-	search         search.Item
-	brokerProducer messagebroker.Producer
-	logger         logger.Interface
+	repo     repository.Item
+	listRepo repository.List
+	tagRepo  repository.Tag
+	search   search.Item
+	logger   logger.Interface
 }
 
 // NewItemUseCase creates a new instance of ItemUseCase
 func NewItemUseCase(
 	r repository.Item,
 	l repository.List,
+	t repository.Tag,
 	s search.Item,
-	p messagebroker.Producer,
 	logger logger.Interface,
 ) *ItemUseCase {
 	return &ItemUseCase{
-		repo:           r,
-		listRepo:       l,
-		search:         s,
-		brokerProducer: p,
-		logger:         logger,
+		repo:     r,
+		listRepo: l,
+		tagRepo:  t,
+		search:   s,
+		logger:   logger,
 	}
 }
 
-// Create creates a new item in a list if the user is the owner, and publishes a created event
+// Create creates a new item in a list if the user is an editor of the list, the repository records the created event
+// in the outbox within its transaction. Items with a recurrence start a new series and need a due date
 func (uc *ItemUseCase) Create(ctx context.Context, userId, listId int, item *entity.Item) (int, error) {
-	if err := uc.listRepo.IsUserOwnerOfList(ctx, userId, listId); err != nil {
-		return 0, err
+	if item.Recurrence != nil {
+		if err := item.Recurrence.Validate(); err != nil {
+			return 0, err
+		}
+
+		if item.DueAt == nil {
+			return 0, utils.ErrRecurrenceWithoutDueDate
+		}
 	}
 
-	itemId, err := uc.repo.CreateListItem(ctx, listId, item)
-	if err != nil {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleEditor); err != nil {
 		return 0, err
 	}
 
-	go uc.brokerProducer.PublishItemCreatedEvent(userId, listId, item)
-
-	return itemId, nil
+	return uc.repo.CreateListItem(ctx, userId, listId, item)
 }
 
-// GetAll retrieves all items from a list if the user is the owner
-func (uc *ItemUseCase) GetAll(ctx context.Context, userId, listId int) ([]entity.Item, error) {
-	if err := uc.listRepo.IsUserOwnerOfList(ctx, userId, listId); err != nil {
-		return make([]entity.Item, 0), err
+// GetAll retrieves a page of the items of a list if the list is shared with the user, optionally only the items
+// with the done state of the query, with tags only the items having every one of the tags of the user are returned
+func (uc *ItemUseCase) GetAll(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error) {
+	if err := query.Validate(entity.ItemSorts); err != nil {
+		return entity.ItemPage{}, err
 	}
 
-	return uc.repo.GetAllListItems(ctx, listId)
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleViewer); err != nil {
+		return entity.ItemPage{}, err
+	}
+
+	return uc.repo.GetListItemsPage(ctx, userId, listId, query)
 }
 
-// GetOneById retrieves a single item by its ID if the user is the owner
+// GetOneById retrieves a single item by its ID if its list is shared with the user
 func (uc *ItemUseCase) GetOneById(ctx context.Context, userId, itemId int) (entity.Item, error) {
-	if err := uc.repo.IsUserOwnerOfItem(ctx, userId, itemId); err != nil {
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleViewer); err != nil {
 		return entity.Item{}, err
 	}
 
 	return uc.repo.GetOneById(ctx, itemId)
 }
 
-// UpdateOneById updates an item by its ID if the user is the owner, and publishes an updated event
-func (uc *ItemUseCase) UpdateOneById(ctx context.Context, userId int, listId int, itemId int, input entity.UpdateItemInput) error {
-	if err := uc.repo.IsUserOwnerOfItem(ctx, userId, itemId); err != nil {
+// UpdateOneById updates an item by its ID if the user is an editor of its list, the updated event is recorded in the
+// outbox by the repository in the transaction of the update.
+// For recurring items the future scope also changes the series, and completing an occurrence creates the next one.
+// Completing an item with cascade also completes all of its subtasks. With a version the item and its series are
+// only updated while the item still has that version
+func (uc *ItemUseCase) UpdateOneById(ctx context.Context, userId int, listId int, itemId int, input entity.UpdateItemInput, version *int) error {
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleEditor); err != nil {
 		return err
 	}
 
-	if err := uc.repo.UpdateOneById(ctx, &listId, itemId, input); err != nil {
+	item, err := uc.repo.GetOneById(ctx, itemId)
+	if err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishItemUpdatedEvent(userId, listId, itemId)
+	series, err := seriesChange(item, input)
+	if err != nil {
+		return err
+	}
+
+	if input.HasItemFields() || series != nil {
+		if err := uc.repo.UpdateOneById(ctx, userId, &listId, itemId, input, series, version); err != nil {
+			return err
+		}
+	}
+
+	if input.Cascade && input.Done != nil && *input.Done {
+		if err := uc.completeSubtasks(ctx, userId, listId, itemId); err != nil {
+			return err
+		}
+	}
+
+	stopped := input.Recurrence != nil && input.Recurrence.Rule == ""
+	if item.SeriesId != nil && !stopped && !item.Done && input.Done != nil && *input.Done {
+		if err := uc.createNextOccurrence(ctx, userId, applyItemInput(item, input)); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-// DeleteOneById deletes an item by its ID if the user is the owner, and publishes a deleted event
-func (uc *ItemUseCase) DeleteOneById(ctx context.Context, userId int, listId int, itemId int) error {
-	if err := uc.repo.IsUserOwnerOfItem(ctx, userId, itemId); err != nil {
+// CreateSubtask creates a new item in the list as a subtask of the parent item if the user is an editor of the list
+func (uc *ItemUseCase) CreateSubtask(ctx context.Context, userId, listId, parentId int, item *entity.Item) (int, error) {
+	item.ParentId = &parentId
+
+	return uc.Create(ctx, userId, listId, item)
+}
+
+// GetSubtasks retrieves the direct subtasks of an item and the progress computed from them
+// if the list of the item is shared with the user
+func (uc *ItemUseCase) GetSubtasks(ctx context.Context, userId, itemId int) (entity.Subtasks, error) {
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return entity.Subtasks{}, err
+	}
+
+	subtasks, err := uc.repo.GetSubtasks(ctx, itemId)
+	if err != nil {
+		return entity.Subtasks{}, err
+	}
+
+	return entity.Subtasks{
+		ParentId: itemId,
+		Progress: entity.NewItemProgress(subtasks),
+		Items:    subtasks,
+	}, nil
+}
+
+// SetParent moves an item under another item of its list, or back to the top level without a parent,
+// if the user is an editor of the list. The repository records the updated event in the outbox within its transaction
+func (uc *ItemUseCase) SetParent(ctx context.Context, userId, listId, itemId int, input entity.SetItemParentInput) error {
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleEditor); err != nil {
 		return err
 	}
 
-	if err := uc.repo.DeleteOneById(ctx, &listId, itemId); err != nil {
+	return uc.repo.SetParent(ctx, userId, listId, itemId, input.ParentId)
+}
+
+// Move places an item between the anchors of the input if the user is an editor of its list,
+// the order of the items is shared by every collaborator of the list
+func (uc *ItemUseCase) Move(ctx context.Context, userId, listId, itemId int, input entity.MoveInput) error {
+	if err := input.Validate(itemId); err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishItemDeletedEvent(itemId)
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleEditor); err != nil {
+		return err
+	}
 
-	return nil
+	return uc.repo.MoveItem(ctx, listId, itemId, input)
 }
 
-// DeleteOneByAdmin deletes an item by its ID as an admin, and publishes a deleted event
-func (uc *ItemUseCase) DeleteOneByAdmin(ctx context.Context, itemId int) error {
-	if err := uc.repo.DeleteOneById(ctx, nil, itemId); err != nil {
+// MoveToList moves items together with their subtasks to the end of another list, the user must be an editor of
+// the target list and of the lists holding the items. The repository records an updated event in the outbox for every
+// moved item so that the search index follows the new list
+func (uc *ItemUseCase) MoveToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) (entity.MovedItems, error) {
+	if err := input.Validate(); err != nil {
+		return entity.MovedItems{}, err
+	}
+
+	if err := uc.authorizeTransfer(ctx, userId, listId, input.ItemIds, entity.ListRoleEditor); err != nil {
+		return entity.MovedItems{}, err
+	}
+
+	movedIds, err := uc.repo.MoveToList(ctx, userId, listId, input.ItemIds)
+	if err != nil {
+		return entity.MovedItems{}, err
+	}
+
+	return entity.MovedItems{ListId: listId, ItemIds: movedIds}, nil
+}
+
+// CopyToList copies items together with their subtasks to the end of another list, the user must be an editor of
+// the target list and have access to the lists holding the items. The repository records a created event in the
+// outbox for every copy
+func (uc *ItemUseCase) CopyToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) ([]entity.ItemCopy, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorizeTransfer(ctx, userId, listId, input.ItemIds, entity.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.CopyToList(ctx, userId, listId, input.ItemIds)
+}
+
+// DeleteOneById moves an item and its subtasks to the trash if the user is an editor of its list, the repository records
+// a deleted event for each of them in the outbox so that they are removed from search. With a version the item must
+// still have it
+func (uc *ItemUseCase) DeleteOneById(ctx context.Context, userId int, listId int, itemId int, version *int) error {
+	if err := authorizeItem(ctx, uc.repo, userId, itemId, entity.ListRoleEditor); err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishItemDeletedEvent(itemId)
+	return uc.moveToTrash(ctx, itemId, version)
+}
 
-	return nil
+// DeleteOneByAdmin moves an item and its subtasks to the trash as an admin, the repository records a deleted event for
+// each of them in the outbox
+func (uc *ItemUseCase) DeleteOneByAdmin(ctx context.Context, itemId int) error {
+	return uc.moveToTrash(ctx, itemId, nil)
+}
+
+// Bulk applies an update, delete or move to many items at once. The roles of the user on the items are checked
+// with a single query and the operation is applied in a single transaction to the items of lists the user is an
+// editor of, the other items are reported in the result without failing the operation. Moving also needs the user
+// to be an editor of the target list. The repository records an event in the outbox for every affected item
+func (uc *ItemUseCase) Bulk(ctx context.Context, userId int, input entity.BulkItemsInput) (entity.BulkItemsResult, error) {
+	if err := input.Validate(); err != nil {
+		return entity.BulkItemsResult{}, err
+	}
+
+	if input.Operation == entity.BulkOperationMove {
+		if err := authorizeList(ctx, uc.listRepo, userId, *input.ListId, entity.ListRoleEditor); err != nil {
+			return entity.BulkItemsResult{}, err
+		}
+	}
+
+	accesses, err := uc.repo.GetUserItemsAccess(ctx, userId, input.ItemIds)
+	if err != nil {
+		return entity.BulkItemsResult{}, err
+	}
+
+	roles := make(map[int]string, len(accesses))
+	for _, access := range accesses {
+		roles[access.ItemId] = access.Role
+	}
+
+	result := entity.BulkItemsResult{
+		Operation:   input.Operation,
+		Results:     make([]entity.BulkItemResult, 0, len(input.ItemIds)),
+		AffectedIds: []int{},
+	}
+
+	allowedIds := []int{}
+	for _, itemId := range input.ItemIds {
+		role, ok := roles[itemId]
+		switch {
+		case !ok:
+			result.Results = append(result.Results, bulkItemFailure(itemId, entity.BulkItemStatusNotFound, utils.ErrItemNotFound))
+		case !entity.ListRoleAllows(role, entity.ListRoleEditor):
+			result.Results = append(result.Results, bulkItemFailure(itemId, entity.BulkItemStatusForbidden, utils.ErrListPermissionDenied))
+		default:
+			allowedIds = append(allowedIds, itemId)
+			result.Results = append(result.Results, entity.BulkItemResult{ItemId: itemId, Status: entity.BulkItemStatusOk})
+		}
+	}
+
+	if len(allowedIds) == 0 {
+		return result, nil
+	}
+
+	switch input.Operation {
+	case entity.BulkOperationUpdate:
+		result.AffectedIds, err = uc.bulkUpdate(ctx, userId, allowedIds, input.UpdateInput())
+	case entity.BulkOperationDelete:
+		result.AffectedIds, err = uc.repo.DeleteManyByIds(ctx, allowedIds)
+	case entity.BulkOperationMove:
+		result.AffectedIds, err = uc.repo.MoveToList(ctx, userId, *input.ListId, allowedIds)
+	}
+	if err != nil {
+		return entity.BulkItemsResult{}, err
+	}
+
+	if input.Operation == entity.BulkOperationUpdate {
+		markBulkItemsNotFound(result.Results, result.AffectedIds)
+	}
+
+	return result, nil
 }
 
-// Search performs a search for items based on various filters and search text
-func (uc *ItemUseCase) Search(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]entity.Item, error) {
-	itemIds, err := uc.search.SearchIds(ctx, userId, listId, done, searchText)
+// Search performs a search for items of the lists shared with the user based on various filters and search text,
+// with tags only the items having every one of the tags of the user are returned. Items are returned a page at a time
+// from the best match
+func (uc *ItemUseCase) Search(ctx context.Context, userId int, listId *int, done *bool, tags []string, searchText string, query entity.PageQuery) (entity.ItemPage, error) {
+	if err := query.Validate(entity.SearchSorts); err != nil {
+		return entity.ItemPage{}, err
+	}
+
+	page, err := searchPage(query)
+	if err != nil {
+		return entity.ItemPage{}, err
+	}
+
+	listIds, err := uc.searchListIds(ctx, userId, listId)
 	if err != nil {
-		return []entity.Item{}, err
+		return entity.ItemPage{}, err
+	}
+
+	if len(listIds) == 0 {
+		return entity.ItemPage{Items: []entity.Item{}}, nil
+	}
+
+	var tagIds []int
+	if len(tags) > 0 {
+		tagIds, err = uc.tagRepo.GetIdsByNames(ctx, userId, tags)
+		if err != nil {
+			return entity.ItemPage{}, err
+		}
+
+		if len(tagIds) < len(tags) {
+			return entity.ItemPage{Items: []entity.Item{}}, nil
+		}
 	}
 
-	return uc.repo.GetManyByIds(ctx, itemIds)
+	hits, err := uc.search.Search(ctx, listIds, done, tagIds, searchText, page)
+	if err != nil {
+		return entity.ItemPage{}, err
+	}
+
+	itemIds, nextCursor := hitIds(query, hits)
+
+	items, err := uc.repo.GetManyByIds(ctx, itemIds)
+	if err != nil {
+		return entity.ItemPage{}, err
+	}
+
+	return entity.ItemPage{
+		Items:      orderByIds(items, itemIds, func(item entity.Item) int { return item.Id }),
+		NextCursor: nextCursor,
+	}, nil
 }
 
-// HandleCreated handles the event when a new item is created by indexing it in the search service
+// HandleCreated handles the event when a new item is created by indexing it in the search service. The item is read
+// again, so a created event delivered after the deleted event of the item does not bring it back to the search service
 func (uc *ItemUseCase) HandleCreated(ctx context.Context, message entity.ItemCreatedEvent) error {
-	if err := uc.search.Index(ctx, message.UserId, message.ListId, message.Item); err != nil {
-		uc.logger.Errorf("failed to index document in Elasticsearch: %v", err)
+	return uc.reindex(ctx, message.ListId, message.Item.Id)
+}
+
+// HandleUpdated handles the event when an item or its tags are updated by indexing it in the search service
+func (uc *ItemUseCase) HandleUpdated(ctx context.Context, message entity.ItemUpdatedEvent) error {
+	return uc.reindex(ctx, message.ListId, message.ItemId)
+}
+
+// HandleDeleted handles the event when an item is deleted by removing it from the search service
+func (uc *ItemUseCase) HandleDeleted(ctx context.Context, message entity.ItemDeletedEvent) error {
+	if err := uc.search.Delete(ctx, message.ItemId); err != nil {
+		uc.logger.Errorf("failed to delete document in Elasticsearch: %v", err)
 		return err
 	}
 
 	return nil
 }
 
-// HandleUpdated handles the event when an item is updated by indexing it in the search service
-func (uc *ItemUseCase) HandleUpdated(ctx context.Context, message entity.ItemUpdatedEvent) error {
-	item, err := uc.repo.GetOneById(ctx, message.ItemId)
+// DispatchDueReminders claims up to limit due reminders, the repository records a reminder due event for each of them
+// in the outbox while claiming them and the relay publishes the events. The number of dispatched reminders is returned
+func (uc *ItemUseCase) DispatchDueReminders(ctx context.Context, limit int) (int, error) {
+	reminders, err := uc.repo.ClaimDueReminders(ctx, limit)
 	if err != nil {
-		uc.logger.Errorf("error fetching updated item: %v", err)
+		return 0, err
+	}
+
+	return len(reminders), nil
+}
+
+// reindex indexes the current state of an item in the search service with the tags of every user, tag Ids are unique
+// so a search by the tags of one user only matches the tags of that user. An item deleted since the event is skipped,
+// its deleted event removes it from the search service
+func (uc *ItemUseCase) reindex(ctx context.Context, listId, itemId int) error {
+	item, err := uc.repo.GetOneById(ctx, itemId)
+	if err != nil {
+		if errors.Is(err, utils.ErrItemNotFound) {
+			uc.logger.Info("skipping item %d, it is in the trash or purged", itemId)
+			return nil
+		}
+
+		uc.logger.Errorf("error fetching item to index: %v", err)
 		return err
 	}
 
-	if err := uc.search.Index(ctx, message.UserId, message.ListId, item); err != nil {
+	tagIds, err := uc.tagRepo.GetIdsByItemId(ctx, itemId)
+	if err != nil {
+		uc.logger.Errorf("error fetching tags of item to index: %v", err)
+		return err
+	}
+
+	if err := uc.search.Index(ctx, listId, item, tagIds); err != nil {
 		uc.logger.Errorf("failed to index document in Elasticsearch: %v", err)
 		return err
 	}
@@ -147,12 +405,226 @@ func (uc *ItemUseCase) HandleUpdated(ctx context.Context, message entity.ItemUpd
 	return nil
 }
 
-// HandleDeleted handles the event when an item is deleted by removing it from the search service
-func (uc *ItemUseCase) HandleDeleted(ctx context.Context, message entity.ItemDeletedEvent) error {
-	if err := uc.search.Delete(ctx, message.ItemId); err != nil {
-		uc.logger.Errorf("failed to delete document in Elasticsearch: %v", err)
+// moveToTrash moves an item and its subtasks to the trash, the repository records a deleted event for each of them
+// in the outbox. With a version the item must still have it
+func (uc *ItemUseCase) moveToTrash(ctx context.Context, itemId int, version *int) error {
+	_, err := uc.repo.DeleteOneById(ctx, itemId, version)
+
+	return err
+}
+
+// bulkUpdate updates the items, the repository records an updated event for each of them in the outbox. Completing an
+// occurrence of a series creates the next one. The Ids of the updated items are returned
+func (uc *ItemUseCase) bulkUpdate(ctx context.Context, userId int, itemIds []int, input entity.UpdateItemInput) ([]int, error) {
+	var items []entity.Item
+	if input.Done != nil && *input.Done {
+		var err error
+		if items, err = uc.repo.GetManyByIds(ctx, itemIds); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedIds, err := uc.repo.UpdateManyByIds(ctx, userId, itemIds, input)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[int]bool, len(updatedIds))
+	for _, itemId := range updatedIds {
+		updated[itemId] = true
+	}
+
+	for _, item := range items {
+		if item.SeriesId == nil || item.Done || !updated[item.Id] {
+			continue
+		}
+
+		if err := uc.createNextOccurrence(ctx, userId, applyItemInput(item, input)); err != nil {
+			uc.logger.Errorf("failed to create the next occurrence of item %d: %v", item.Id, err)
+		}
+	}
+
+	return updatedIds, nil
+}
+
+// seriesChange returns the change an update makes to the series of an item, nil when it leaves the series alone.
+// Changes of a series are only allowed for all future occurrences, and the other fields of such updates are applied
+// to the series as well
+func seriesChange(item entity.Item, input entity.UpdateItemInput) (*entity.ItemSeriesChange, error) {
+	if item.SeriesId == nil {
+		if input.Recurrence == nil || input.Recurrence.Rule == "" {
+			return nil, nil
+		}
+
+		first := applyItemInput(item, input)
+		if first.DueAt == nil {
+			return nil, utils.ErrRecurrenceWithoutDueDate
+		}
+		first.Recurrence = input.Recurrence
+
+		return &entity.ItemSeriesChange{Action: entity.ItemSeriesStart, First: first}, nil
+	}
+
+	if input.Scope != entity.ItemEditScopeFuture {
+		if input.Recurrence != nil {
+			return nil, utils.ErrRecurrenceChangeNeedsFuture
+		}
+
+		return nil, nil
+	}
+
+	if input.Recurrence != nil && input.Recurrence.Rule == "" {
+		return &entity.ItemSeriesChange{Action: entity.ItemSeriesStop}, nil
+	}
+
+	if !input.HasSeriesFields() {
+		return nil, nil
+	}
+
+	if input.Recurrence != nil && input.DueAt == nil {
+		input.DueAt = item.DueAt
+	}
+
+	return &entity.ItemSeriesChange{Action: entity.ItemSeriesUpdate, SeriesId: *item.SeriesId, Input: input}, nil
+}
+
+// completeSubtasks completes the open subtasks of an item, the repository records an updated event for each of them
+// in the outbox
+func (uc *ItemUseCase) completeSubtasks(ctx context.Context, userId, listId, itemId int) error {
+	_, err := uc.repo.CompleteSubtasks(ctx, userId, listId, itemId)
+
+	return err
+}
+
+// createNextOccurrence creates the occurrence of the series following a completed item, the repository records its
+// created event in the outbox. Nothing is created when the series has ended
+func (uc *ItemUseCase) createNextOccurrence(ctx context.Context, userId int, item entity.Item) error {
+	if item.DueAt == nil {
+		return nil
+	}
+
+	series, err := uc.repo.GetSeries(ctx, *item.SeriesId)
+	if err != nil {
 		return err
 	}
 
+	rule, loc, err := series.Recurrence.Parse()
+	if err != nil {
+		return err
+	}
+
+	occurrence := 1
+	if item.Occurrence != nil {
+		occurrence = *item.Occurrence
+	}
+
+	dueAt, ok := rule.Next(series.StartsAt.In(loc), *item.DueAt, occurrence)
+	if !ok {
+		return nil
+	}
+
+	nextOccurrence := occurrence + 1
+	next := entity.Item{
+		Title:       series.Title,
+		Description: series.Description,
+		DueAt:       &dueAt,
+		Recurrence:  &series.Recurrence,
+		SeriesId:    item.SeriesId,
+		Occurrence:  &nextOccurrence,
+		ParentId:    item.ParentId,
+	}
+
+	if item.RemindAt != nil {
+		remindAt := dueAt.Add(item.RemindAt.Sub(*item.DueAt))
+		next.RemindAt = &remindAt
+	}
+
+	_, _, err = uc.repo.CreateNextOccurrence(ctx, userId, item.Id, &next)
+
+	return err
+}
+
+// applyItemInput returns a copy of the item with the fields of the update input applied
+func applyItemInput(item entity.Item, input entity.UpdateItemInput) entity.Item {
+	if input.Title != nil {
+		item.Title = *input.Title
+	}
+	if input.Description != nil {
+		item.Description = *input.Description
+	}
+	if input.Done != nil {
+		item.Done = *input.Done
+	}
+	if input.DueAt != nil {
+		item.DueAt = input.DueAt
+	}
+	if input.RemindAt != nil {
+		item.RemindAt = input.RemindAt
+	}
+
+	return item
+}
+
+// bulkItemFailure returns the result of an item a bulk operation was not applied to
+func bulkItemFailure(itemId int, status string, err error) entity.BulkItemResult {
+	return entity.BulkItemResult{ItemId: itemId, Status: status, Error: err.Error()}
+}
+
+// markBulkItemsNotFound marks the successful results of items missing from the affected items as not found,
+// such items were moved to the trash while the operation was applied
+func markBulkItemsNotFound(results []entity.BulkItemResult, affectedIds []int) {
+	affected := make(map[int]bool, len(affectedIds))
+	for _, itemId := range affectedIds {
+		affected[itemId] = true
+	}
+
+	for i := range results {
+		if results[i].Status == entity.BulkItemStatusOk && !affected[results[i].ItemId] {
+			results[i] = bulkItemFailure(results[i].ItemId, entity.BulkItemStatusNotFound, utils.ErrItemNotFound)
+		}
+	}
+}
+
+// searchListIds returns the lists searched for the user, the given list if it is shared with the user
+// and every list shared with the user otherwise
+func (uc *ItemUseCase) searchListIds(ctx context.Context, userId int, listId *int) ([]int, error) {
+	if listId == nil {
+		return uc.listRepo.GetUserListIds(ctx, userId)
+	}
+
+	if err := authorizeList(ctx, uc.listRepo, userId, *listId, entity.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return []int{*listId}, nil
+}
+
+// authorizeTransfer checks that the user is an editor of the target list and has the source role on the lists
+// holding the items
+func (uc *ItemUseCase) authorizeTransfer(ctx context.Context, userId, listId int, itemIds []int, sourceRole string) error {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleEditor); err != nil {
+		return err
+	}
+
+	for _, itemId := range itemIds {
+		if err := authorizeItem(ctx, uc.repo, userId, itemId, sourceRole); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authorizeItem checks that the list holding the item is shared with the user with at least the required role
+func authorizeItem(ctx context.Context, repo repository.Item, userId, itemId int, required string) error {
+	role, err := repo.GetUserItemRole(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if !entity.ListRoleAllows(role, required) {
+		return utils.ErrListPermissionDenied
+	}
+
 	return nil
 }
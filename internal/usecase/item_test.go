@@ -2,12 +2,15 @@ package usecase_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/search"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +25,7 @@ func TestCreateItem(t *testing.T) {
 		listId           int
 		item             entity.Item
 		expectedId       int
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -31,15 +35,27 @@ func TestCreateItem(t *testing.T) {
 			listId:           1,
 			item:             entity.Item{Title: "New Item"},
 			expectedId:       101,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Insufficient role",
+			userId:           1,
+			listId:           1,
+			item:             entity.Item{Title: "New Item"},
+			expectedId:       0,
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: utils.ErrListPermissionDenied,
+			expectedErr:      nil,
+		},
 		{
 			name:             "List not belongs to user",
 			userId:           1,
 			listId:           1,
 			item:             entity.Item{Title: "New Item"},
 			expectedId:       0,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -49,25 +65,45 @@ func TestCreateItem(t *testing.T) {
 			listId:           1,
 			item:             entity.Item{Title: "New Item"},
 			expectedId:       0,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      errors.New("failed to create item"),
 		},
+		{
+			name:             "Recurrence without due date",
+			userId:           1,
+			listId:           1,
+			item:             entity.Item{Title: "New Item", Recurrence: &entity.Recurrence{Rule: "FREQ=DAILY"}},
+			expectedId:       0,
+			role:             entity.ListRoleEditor,
+			expectedOwnerErr: nil,
+			expectedErr:      utils.ErrRecurrenceWithoutDueDate,
+		},
+		{
+			name:             "Invalid recurrence",
+			userId:           1,
+			listId:           1,
+			item:             entity.Item{Title: "New Item", Recurrence: &entity.Recurrence{Rule: "FREQ=HOURLY"}},
+			expectedId:       0,
+			role:             entity.ListRoleEditor,
+			expectedOwnerErr: nil,
+			expectedErr:      utils.ErrInvalidRecurrence,
+		},
 	}
 
 	for _, testCase := range testCases {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockListRepo.On("IsUserOwnerOfList", mock.Anything, testCase.userId, testCase.listId).Return(testCase.expectedOwnerErr)
-			mockItemRepo.On("CreateListItem", mock.Anything, testCase.listId, &testCase.item).Return(testCase.expectedId, testCase.expectedErr)
+			mockListRepo.On("GetUserListRole", mock.Anything, testCase.userId, testCase.listId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockItemRepo.On("CreateListItem", mock.Anything, testCase.userId, testCase.listId, &testCase.item).Return(testCase.expectedId, testCase.expectedErr)
 
 			actualId, err := itemUseCase.Create(context.Background(), testCase.userId, testCase.listId, &testCase.item)
 
@@ -87,11 +123,18 @@ func TestCreateItem(t *testing.T) {
 
 // TestGetAllItems tests the GetAllItems function in the ItemUseCase
 func TestGetAllItems(t *testing.T) {
+	done := false
+	defaultQuery := entity.ItemQuery{PageQuery: entity.PageQuery{Limit: entity.DefaultPageLimit, Sort: entity.SortPosition, Order: entity.SortOrderAsc}}
+	nextCursor := defaultQuery.NextCursor("2", 2)
+
 	testCases := []struct {
 		name             string
 		userId           int
 		listId           int
-		expectedItems    []entity.Item
+		query            entity.ItemQuery
+		repoQuery        entity.ItemQuery
+		expectedPage     entity.ItemPage
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -99,15 +142,54 @@ func TestGetAllItems(t *testing.T) {
 			name:             "Successful retrieval",
 			userId:           1,
 			listId:           1,
-			expectedItems:    []entity.Item{{Title: "Item 1"}, {Title: "Item 2"}},
+			repoQuery:        defaultQuery,
+			expectedPage:     entity.ItemPage{Items: []entity.Item{{Title: "Item 1"}, {Title: "Item 2"}}, NextCursor: nextCursor},
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: nil,
+			expectedErr:      nil,
+		},
+		{
+			name:   "Successful retrieval by tags and done state",
+			userId: 1,
+			listId: 1,
+			query:  entity.ItemQuery{PageQuery: entity.PageQuery{Limit: 10, Sort: entity.SortDue, Order: entity.SortOrderDesc}, Done: &done, Tags: []string{"work", "urgent"}},
+			repoQuery: entity.ItemQuery{
+				PageQuery: entity.PageQuery{Limit: 10, Sort: entity.SortDue, Order: entity.SortOrderDesc},
+				Done:      &done,
+				Tags:      []string{"work", "urgent"},
+			},
+			expectedPage:     entity.ItemPage{Items: []entity.Item{{Title: "Item 2"}}},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Unsupported sort",
+			userId:           1,
+			listId:           1,
+			query:            entity.ItemQuery{PageQuery: entity.PageQuery{Sort: entity.SortRelevance}},
+			expectedPage:     entity.ItemPage{},
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: nil,
+			expectedErr:      utils.ErrInvalidSort,
+		},
+		{
+			name:             "Cursor of another sort",
+			userId:           1,
+			listId:           1,
+			query:            entity.ItemQuery{PageQuery: entity.PageQuery{Sort: entity.SortTitle, Cursor: *nextCursor}},
+			expectedPage:     entity.ItemPage{},
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: nil,
+			expectedErr:      utils.ErrInvalidPageCursor,
+		},
 		{
 			name:             "List not belongs to user",
 			userId:           1,
 			listId:           1,
-			expectedItems:    make([]entity.Item, 0),
+			repoQuery:        defaultQuery,
+			expectedPage:     entity.ItemPage{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -115,7 +197,9 @@ func TestGetAllItems(t *testing.T) {
 			name:             "Failed retrieval",
 			userId:           1,
 			listId:           1,
-			expectedItems:    make([]entity.Item, 0),
+			repoQuery:        defaultQuery,
+			expectedPage:     entity.ItemPage{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      errors.New("failed to retrieve items"),
 		},
@@ -125,20 +209,19 @@ func TestGetAllItems(t *testing.T) {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockListRepo.On("IsUserOwnerOfList", mock.Anything, testCase.userId, testCase.listId).Return(testCase.expectedOwnerErr)
-			mockItemRepo.On("GetAllListItems", mock.Anything, testCase.listId).Return(testCase.expectedItems, testCase.expectedErr)
+			mockListRepo.On("GetUserListRole", mock.Anything, testCase.userId, testCase.listId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockItemRepo.On("GetListItemsPage", mock.Anything, testCase.userId, testCase.listId, testCase.repoQuery).Return(testCase.expectedPage, testCase.expectedErr)
 
-			actualItems, err := itemUseCase.GetAll(context.Background(), testCase.userId, testCase.listId)
+			actualPage, err := itemUseCase.GetAll(context.Background(), testCase.userId, testCase.listId, testCase.query)
 
-			assert.Equal(t, testCase.expectedItems, actualItems)
+			assert.Equal(t, testCase.expectedPage, actualPage)
 			if testCase.expectedOwnerErr != nil {
 				assert.Error(t, err)
 				assert.Equal(t, testCase.expectedOwnerErr, err)
@@ -159,6 +242,7 @@ func TestGetItemById(t *testing.T) {
 		userId           int
 		itemId           int
 		expectedItem     entity.Item
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -167,6 +251,7 @@ func TestGetItemById(t *testing.T) {
 			userId:           1,
 			itemId:           1,
 			expectedItem:     entity.Item{Title: "Item 1"},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
@@ -175,6 +260,7 @@ func TestGetItemById(t *testing.T) {
 			userId:           1,
 			itemId:           99,
 			expectedItem:     entity.Item{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -183,6 +269,7 @@ func TestGetItemById(t *testing.T) {
 			userId:           1,
 			itemId:           99,
 			expectedItem:     entity.Item{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrItemNotFound,
 		},
@@ -192,15 +279,14 @@ func TestGetItemById(t *testing.T) {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockItemRepo.On("IsUserOwnerOfItem", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.expectedOwnerErr)
+			mockItemRepo.On("GetUserItemRole", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.role, testCase.expectedOwnerErr)
 			mockItemRepo.On("GetOneById", mock.Anything, testCase.itemId).Return(testCase.expectedItem, testCase.expectedErr)
 
 			actualItem, err := itemUseCase.GetOneById(context.Background(), testCase.userId, testCase.itemId)
@@ -229,6 +315,7 @@ func TestUpdateItemById(t *testing.T) {
 		listId           int
 		itemId           int
 		input            entity.UpdateItemInput
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -238,15 +325,27 @@ func TestUpdateItemById(t *testing.T) {
 			listId:           3,
 			itemId:           1,
 			input:            entity.UpdateItemInput{Title: &title},
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Insufficient role",
+			userId:           1,
+			listId:           3,
+			itemId:           1,
+			input:            entity.UpdateItemInput{Title: &title},
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: utils.ErrListPermissionDenied,
+			expectedErr:      nil,
+		},
 		{
 			name:             "Item not belongs to user",
 			userId:           1,
 			listId:           3,
 			itemId:           99,
 			input:            entity.UpdateItemInput{Title: &title},
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -256,6 +355,7 @@ func TestUpdateItemById(t *testing.T) {
 			listId:           3,
 			itemId:           99,
 			input:            entity.UpdateItemInput{Title: &title},
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrItemNotFound,
 		},
@@ -265,18 +365,18 @@ func TestUpdateItemById(t *testing.T) {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockItemRepo.On("IsUserOwnerOfItem", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.expectedOwnerErr)
-			mockItemRepo.On("UpdateOneById", mock.Anything, &testCase.listId, testCase.itemId, testCase.input).Return(testCase.expectedErr)
+			mockItemRepo.On("GetUserItemRole", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockItemRepo.On("GetOneById", mock.Anything, testCase.itemId).Return(entity.Item{Id: testCase.itemId}, nil)
+			mockItemRepo.On("UpdateOneById", mock.Anything, testCase.userId, &testCase.listId, testCase.itemId, testCase.input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(testCase.expectedErr)
 
-			err := itemUseCase.UpdateOneById(context.Background(), testCase.userId, testCase.listId, testCase.itemId, testCase.input)
+			err := itemUseCase.UpdateOneById(context.Background(), testCase.userId, testCase.listId, testCase.itemId, testCase.input, nil)
 
 			if testCase.expectedOwnerErr != nil {
 				assert.Error(t, err)
@@ -291,6 +391,205 @@ func TestUpdateItemById(t *testing.T) {
 	}
 }
 
+// TestUpdateItemByIdVersion tests that the UpdateOneById function in the ItemUseCase honours the expected version
+func TestUpdateItemByIdVersion(t *testing.T) {
+	title := "Updated Item"
+	input := entity.UpdateItemInput{Title: &title}
+
+	testCases := []struct {
+		name        string
+		version     int
+		expectedErr error
+	}{
+		{
+			name:        "Version matches",
+			version:     3,
+			expectedErr: nil,
+		},
+		{
+			name:        "Version mismatch",
+			version:     2,
+			expectedErr: utils.ErrVersionMismatch,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(entity.ListRoleEditor, nil)
+			mockItemRepo.On("GetOneById", mock.Anything, 1).Return(entity.Item{Id: 1, Version: 3}, nil)
+			mockItemRepo.On("UpdateOneById", mock.Anything, 1, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), &testCase.version).Return(testCase.expectedErr)
+
+			err := itemUseCase.UpdateOneById(context.Background(), 1, 3, 1, input, &testCase.version)
+
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+// TestUpdateRecurringItem tests the series handling of the UpdateOneById function in the ItemUseCase
+func TestUpdateRecurringItem(t *testing.T) {
+	done := true
+	title := "Water the plants"
+	seriesId := 5
+	occurrence := 1
+	dueAt := time.Date(2024, 10, 21, 9, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+	nextDueAt := dueAt.AddDate(0, 0, 7)
+	nextRemindAt := nextDueAt.Add(-time.Hour)
+	nextOccurrence := 2
+
+	weekly := entity.Recurrence{Rule: "FREQ=WEEKLY", Timezone: "UTC"}
+	stop := entity.Recurrence{}
+	recurringItem := entity.Item{Id: 1, Title: title, DueAt: &dueAt, RemindAt: &remindAt, Recurrence: &weekly, SeriesId: &seriesId, Occurrence: &occurrence}
+	series := entity.ItemSeries{Id: seriesId, Recurrence: weekly, Title: title, StartsAt: dueAt}
+
+	testCases := []struct {
+		name        string
+		item        entity.Item
+		input       entity.UpdateItemInput
+		mockRepo    func(*MockItemRepo, entity.UpdateItemInput)
+		expectedErr error
+	}{
+		{
+			name:  "Completing an occurrence creates the next one",
+			item:  recurringItem,
+			input: entity.UpdateItemInput{Done: &done, Scope: entity.ItemEditScopeThis},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(nil)
+				repo.On("GetSeries", mock.Anything, seriesId).Return(series, nil)
+				repo.On("CreateNextOccurrence", mock.Anything, 1, 1, &entity.Item{
+					Title:      title,
+					DueAt:      &nextDueAt,
+					RemindAt:   &nextRemindAt,
+					Recurrence: &weekly,
+					SeriesId:   &seriesId,
+					Occurrence: &nextOccurrence,
+				}).Return(2, 3, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Completing the last occurrence ends the series",
+			item:  recurringItem,
+			input: entity.UpdateItemInput{Done: &done, Scope: entity.ItemEditScopeThis},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(nil)
+				repo.On("GetSeries", mock.Anything, seriesId).Return(entity.ItemSeries{
+					Id:         seriesId,
+					Recurrence: entity.Recurrence{Rule: "FREQ=WEEKLY;COUNT=1", Timezone: "UTC"},
+					Title:      title,
+					StartsAt:   dueAt,
+				}, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Future scope updates the series",
+			item:  recurringItem,
+			input: entity.UpdateItemInput{Title: &title, Scope: entity.ItemEditScopeFuture},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, &entity.ItemSeriesChange{
+					Action:   entity.ItemSeriesUpdate,
+					SeriesId: seriesId,
+					Input:    input,
+				}, (*int)(nil)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Recurrence change of this occurrence",
+			item:        recurringItem,
+			input:       entity.UpdateItemInput{Recurrence: &weekly, Scope: entity.ItemEditScopeThis},
+			mockRepo:    func(repo *MockItemRepo, input entity.UpdateItemInput) {},
+			expectedErr: utils.ErrRecurrenceChangeNeedsFuture,
+		},
+		{
+			name:  "Empty rule stops the series",
+			item:  recurringItem,
+			input: entity.UpdateItemInput{Recurrence: &stop, Scope: entity.ItemEditScopeFuture},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, &entity.ItemSeriesChange{
+					Action: entity.ItemSeriesStop,
+				}, (*int)(nil)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Recurrence starts a series",
+			item:  entity.Item{Id: 1, Title: title, DueAt: &dueAt},
+			input: entity.UpdateItemInput{Recurrence: &weekly, Scope: entity.ItemEditScopeThis},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, &entity.ItemSeriesChange{
+					Action: entity.ItemSeriesStart,
+					First:  entity.Item{Id: 1, Title: title, DueAt: &dueAt, Recurrence: &weekly},
+				}, (*int)(nil)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Recurrence without due date",
+			item:        entity.Item{Id: 1, Title: title},
+			input:       entity.UpdateItemInput{Recurrence: &weekly, Scope: entity.ItemEditScopeThis},
+			mockRepo:    func(repo *MockItemRepo, input entity.UpdateItemInput) {},
+			expectedErr: utils.ErrRecurrenceWithoutDueDate,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		mockListRepo := new(MockListRepo)
+		mockItemSearch := new(MockItemSearch)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(entity.ListRoleEditor, nil)
+			mockItemRepo.On("GetOneById", mock.Anything, 1).Return(testCase.item, nil)
+			testCase.mockRepo(mockItemRepo, testCase.input)
+
+			err := itemUseCase.UpdateOneById(context.Background(), 1, 3, 1, testCase.input, nil)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateRecurringItemVersion tests that the change of the series of an item is made under the expected version
+// together with the update of its fields
+func TestUpdateRecurringItemVersion(t *testing.T) {
+	seriesId := 5
+	title := "Water the garden"
+	version := 4
+
+	mockItemRepo := new(MockItemRepo)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+	input := entity.UpdateItemInput{Title: &title, Scope: entity.ItemEditScopeFuture}
+	mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(entity.ListRoleEditor, nil)
+	mockItemRepo.On("GetOneById", mock.Anything, 1).Return(entity.Item{Id: 1, SeriesId: &seriesId, Version: version}, nil)
+	mockItemRepo.On("UpdateOneById", mock.Anything, 1, mock.Anything, 1, input, &entity.ItemSeriesChange{
+		Action:   entity.ItemSeriesUpdate,
+		SeriesId: seriesId,
+		Input:    input,
+	}, &version).Return(utils.ErrVersionMismatch)
+
+	err := itemUseCase.UpdateOneById(context.Background(), 1, 3, 1, input, &version)
+
+	assert.Equal(t, utils.ErrVersionMismatch, err)
+	mockItemRepo.AssertExpectations(t)
+}
+
 // TestDeleteItemById tests the DeleteItemById function in the ItemUseCase
 func TestDeleteItemById(t *testing.T) {
 	testCases := []struct {
@@ -298,6 +597,7 @@ func TestDeleteItemById(t *testing.T) {
 		userId           int
 		listId           int
 		itemId           int
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -306,14 +606,25 @@ func TestDeleteItemById(t *testing.T) {
 			userId:           1,
 			listId:           3,
 			itemId:           1,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Insufficient role",
+			userId:           1,
+			listId:           3,
+			itemId:           1,
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: utils.ErrListPermissionDenied,
+			expectedErr:      nil,
+		},
 		{
 			name:             "Item not belongs to user",
 			userId:           1,
 			listId:           3,
 			itemId:           99,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -322,6 +633,7 @@ func TestDeleteItemById(t *testing.T) {
 			userId:           1,
 			listId:           3,
 			itemId:           99,
+			role:             entity.ListRoleEditor,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrItemNotFound,
 		},
@@ -331,18 +643,17 @@ func TestDeleteItemById(t *testing.T) {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockItemRepo.On("IsUserOwnerOfItem", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.expectedOwnerErr)
-			mockItemRepo.On("DeleteOneById", mock.Anything, &testCase.listId, testCase.itemId).Return(testCase.expectedErr)
+			mockItemRepo.On("GetUserItemRole", mock.Anything, testCase.userId, testCase.itemId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockItemRepo.On("DeleteOneById", mock.Anything, testCase.itemId, (*int)(nil)).Return([]int{testCase.itemId}, testCase.expectedErr)
 
-			err := itemUseCase.DeleteOneById(context.Background(), testCase.userId, testCase.listId, testCase.itemId)
+			err := itemUseCase.DeleteOneById(context.Background(), testCase.userId, testCase.listId, testCase.itemId, nil)
 
 			if testCase.expectedOwnerErr != nil {
 				assert.Error(t, err)
@@ -380,16 +691,14 @@ func TestDeleteItemByAdmin(t *testing.T) {
 		mockItemRepo := new(MockItemRepo)
 		mockListRepo := new(MockListRepo)
 		mockItemSearch := new(MockItemSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockItemSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			var listId *int
-			mockItemRepo.On("DeleteOneById", mock.Anything, listId, testCase.itemId).Return(testCase.expectedErr)
+			mockItemRepo.On("DeleteOneById", mock.Anything, testCase.itemId, (*int)(nil)).Return([]int{testCase.itemId}, testCase.expectedErr)
 
 			err := itemUseCase.DeleteOneByAdmin(context.Background(), testCase.itemId)
 
@@ -402,3 +711,803 @@ func TestDeleteItemByAdmin(t *testing.T) {
 		})
 	}
 }
+
+// TestDispatchDueReminders tests the DispatchDueReminders function in the ItemUseCase
+func TestDispatchDueReminders(t *testing.T) {
+	remindAt := time.Date(2024, 11, 5, 12, 0, 0, 0, time.UTC)
+	reminders := []entity.ItemReminderDueEvent{
+		{ListId: 1, ItemId: 1, Title: "First", RemindAt: remindAt},
+		{ListId: 1, ItemId: 2, Title: "Second", RemindAt: remindAt},
+	}
+
+	testCases := []struct {
+		name               string
+		reminders          []entity.ItemReminderDueEvent
+		claimErr           error
+		expectedDispatched int
+		expectedErr        error
+	}{
+		{
+			name:               "All reminders dispatched",
+			reminders:          reminders,
+			expectedDispatched: 2,
+		},
+		{
+			name:               "No due reminders",
+			reminders:          []entity.ItemReminderDueEvent{},
+			expectedDispatched: 0,
+		},
+		{
+			name:        "Claim failure",
+			reminders:   []entity.ItemReminderDueEvent(nil),
+			claimErr:    sql.ErrConnDone,
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo.On("ClaimDueReminders", mock.Anything, 10).Return(testCase.reminders, testCase.claimErr)
+
+			dispatched, err := itemUseCase.DispatchDueReminders(context.Background(), 10)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedDispatched, dispatched)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSearchItems tests the Search function in the ItemUseCase
+func TestSearchItems(t *testing.T) {
+	testCases := []struct {
+		name         string
+		tags         []string
+		tagIds       []int
+		searchTagIds []int
+		hits         []search.Hit
+		itemIds      []int
+		repoItems    []entity.Item
+		expectedPage entity.ItemPage
+		expectedErr  error
+	}{
+		{
+			name:         "Search without tags",
+			hits:         []search.Hit{{Id: 2, Score: 3.5}, {Id: 1, Score: 1.25}},
+			itemIds:      []int{2, 1},
+			repoItems:    []entity.Item{{Id: 1}, {Id: 2}},
+			expectedPage: entity.ItemPage{Items: []entity.Item{{Id: 2}, {Id: 1}}},
+		},
+		{
+			name:         "Search by tags",
+			tags:         []string{"work", "urgent"},
+			tagIds:       []int{3, 4},
+			searchTagIds: []int{3, 4},
+			hits:         []search.Hit{{Id: 2, Score: 2}},
+			itemIds:      []int{2},
+			repoItems:    []entity.Item{{Id: 2}},
+			expectedPage: entity.ItemPage{Items: []entity.Item{{Id: 2}}},
+		},
+		{
+			name:         "Unknown tag matches nothing",
+			tags:         []string{"work", "unknown"},
+			tagIds:       []int{3},
+			expectedPage: entity.ItemPage{Items: []entity.Item{}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		mockListRepo := new(MockListRepo)
+		mockTagRepo := new(MockTagRepo)
+		mockItemSearch := new(MockItemSearch)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockTagRepo, mockItemSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo.On("GetUserListIds", mock.Anything, 1).Return([]int{2, 7}, nil)
+			if len(testCase.tags) > 0 {
+				mockTagRepo.On("GetIdsByNames", mock.Anything, 1, testCase.tags).Return(testCase.tagIds, nil)
+			}
+			if testCase.hits != nil {
+				page := search.Page{Size: entity.DefaultPageLimit + 1, Descending: true}
+				mockItemSearch.On("Search", mock.Anything, []int{2, 7}, (*bool)(nil), testCase.searchTagIds, "report", page).Return(testCase.hits, nil)
+				mockItemRepo.On("GetManyByIds", mock.Anything, testCase.itemIds).Return(testCase.repoItems, nil)
+			}
+
+			page, err := itemUseCase.Search(context.Background(), 1, nil, nil, testCase.tags, "report", entity.PageQuery{})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedPage, page)
+			mockListRepo.AssertExpectations(t)
+			mockTagRepo.AssertExpectations(t)
+			mockItemSearch.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSearchItemsOfList tests that the Search function in the ItemUseCase only searches a list shared with the user
+func TestSearchItemsOfList(t *testing.T) {
+	listId := 2
+
+	testCases := []struct {
+		name         string
+		role         string
+		roleErr      error
+		expectSearch bool
+		expectedPage entity.ItemPage
+		expectedErr  error
+	}{
+		{
+			name:         "Shared list is searched",
+			role:         entity.ListRoleViewer,
+			expectSearch: true,
+			expectedPage: entity.ItemPage{Items: []entity.Item{{Id: 5}}},
+		},
+		{
+			name:        "List not shared with user",
+			roleErr:     utils.ErrUserNotOwner,
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		mockListRepo := new(MockListRepo)
+		mockItemSearch := new(MockItemSearch)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo.On("GetUserListRole", mock.Anything, 1, listId).Return(testCase.role, testCase.roleErr)
+			if testCase.expectSearch {
+				page := search.Page{Size: entity.DefaultPageLimit + 1, Descending: true}
+				mockItemSearch.On("Search", mock.Anything, []int{listId}, (*bool)(nil), []int(nil), "report", page).Return([]search.Hit{{Id: 5, Score: 1}}, nil)
+				mockItemRepo.On("GetManyByIds", mock.Anything, []int{5}).Return([]entity.Item{{Id: 5}}, nil)
+			}
+
+			page, err := itemUseCase.Search(context.Background(), 1, &listId, nil, nil, "report", entity.PageQuery{})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedPage, page)
+			mockListRepo.AssertExpectations(t)
+			mockItemSearch.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSearchSharedItemAfterCollaboratorUpdate tests that an item updated by a collaborator is still found by the owner
+func TestSearchSharedItemAfterCollaboratorUpdate(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockListRepo := new(MockListRepo)
+	mockTagRepo := new(MockTagRepo)
+	itemSearch := newFakeItemSearch()
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockTagRepo, itemSearch, &logger.NoOpLogger{})
+
+	ownerId, collaboratorId, listId := 1, 2, 3
+	item := entity.Item{Id: 5, Title: "Quarterly report"}
+
+	mockItemRepo.On("GetOneById", mock.Anything, item.Id).Return(item, nil)
+	mockTagRepo.On("GetIdsByItemId", mock.Anything, item.Id).Return([]int{}, nil)
+	mockListRepo.On("GetUserListIds", mock.Anything, ownerId).Return([]int{listId}, nil)
+	mockItemRepo.On("GetManyByIds", mock.Anything, []int{item.Id}).Return([]entity.Item{item}, nil)
+
+	err := itemUseCase.HandleCreated(context.Background(), entity.ItemCreatedEvent{UserId: ownerId, ListId: listId, Item: item})
+	assert.NoError(t, err)
+
+	err = itemUseCase.HandleUpdated(context.Background(), entity.ItemUpdatedEvent{UserId: collaboratorId, ListId: listId, ItemId: item.Id})
+	assert.NoError(t, err)
+
+	page, err := itemUseCase.Search(context.Background(), ownerId, nil, nil, nil, "report", entity.PageQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Item{item}, page.Items)
+}
+
+// TestSearchItemsPage tests that the Search function in the ItemUseCase continues after the cursor and returns the next cursor
+func TestSearchItemsPage(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockItemSearch := new(MockItemSearch)
+	mockListRepo := new(MockListRepo)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
+
+	query := entity.PageQuery{Limit: 1, Sort: entity.SortRelevance, Order: entity.SortOrderDesc}
+	query.Cursor = *query.NextCursor("4.5", 9)
+
+	page := search.Page{Size: 2, Descending: true, After: []interface{}{4.5, 9}}
+	mockListRepo.On("GetUserListIds", mock.Anything, 1).Return([]int{2}, nil)
+	mockItemSearch.On("Search", mock.Anything, []int{2}, (*bool)(nil), []int(nil), "report", page).
+		Return([]search.Hit{{Id: 3, Score: 2.75}, {Id: 5, Score: 1}}, nil)
+	mockItemRepo.On("GetManyByIds", mock.Anything, []int{3}).Return([]entity.Item{{Id: 3}}, nil)
+
+	itemPage, err := itemUseCase.Search(context.Background(), 1, nil, nil, nil, "report", query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ItemPage{Items: []entity.Item{{Id: 3}}, NextCursor: query.NextCursor("2.75", 3)}, itemPage)
+	mockItemSearch.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+}
+
+// TestHandleCreatedItem tests that the HandleCreated function in the ItemUseCase indexes the current state of the item
+func TestHandleCreatedItem(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockTagRepo := new(MockTagRepo)
+	mockItemSearch := new(MockItemSearch)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), mockTagRepo, mockItemSearch, &logger.NoOpLogger{})
+
+	item := entity.Item{Id: 5, Title: "Renamed report"}
+	mockItemRepo.On("GetOneById", mock.Anything, 5).Return(item, nil)
+	mockTagRepo.On("GetIdsByItemId", mock.Anything, 5).Return([]int{3}, nil)
+	mockItemSearch.On("Index", mock.Anything, 2, item, []int{3}).Return(nil)
+
+	err := itemUseCase.HandleCreated(context.Background(), entity.ItemCreatedEvent{UserId: 1, ListId: 2, Item: entity.Item{Id: 5, Title: "Report"}})
+
+	assert.NoError(t, err)
+	mockItemSearch.AssertExpectations(t)
+}
+
+// TestHandleCreatedDeletedItem tests that the HandleCreated function in the ItemUseCase skips items deleted since the event,
+// so that a created event retried after the deleted event does not index the item again
+func TestHandleCreatedDeletedItem(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockItemSearch := new(MockItemSearch)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
+
+	mockItemRepo.On("GetOneById", mock.Anything, 5).Return(entity.Item{}, utils.ErrItemNotFound)
+
+	err := itemUseCase.HandleCreated(context.Background(), entity.ItemCreatedEvent{UserId: 1, ListId: 2, Item: entity.Item{Id: 5}})
+
+	assert.NoError(t, err)
+	mockItemSearch.AssertNotCalled(t, "Index", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestHandleUpdatedItem tests that the HandleUpdated function in the ItemUseCase indexes items with their tags
+func TestHandleUpdatedItem(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockTagRepo := new(MockTagRepo)
+	mockItemSearch := new(MockItemSearch)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), mockTagRepo, mockItemSearch, &logger.NoOpLogger{})
+
+	item := entity.Item{Id: 5, Title: "Report"}
+	mockItemRepo.On("GetOneById", mock.Anything, 5).Return(item, nil)
+	mockTagRepo.On("GetIdsByItemId", mock.Anything, 5).Return([]int{3, 4, 8}, nil)
+	mockItemSearch.On("Index", mock.Anything, 2, item, []int{3, 4, 8}).Return(nil)
+
+	err := itemUseCase.HandleUpdated(context.Background(), entity.ItemUpdatedEvent{UserId: 1, ListId: 2, ItemId: 5})
+
+	assert.NoError(t, err)
+	mockItemSearch.AssertExpectations(t)
+}
+
+// TestHandleUpdatedDeletedItem tests that the HandleUpdated function in the ItemUseCase skips items deleted since the event
+func TestHandleUpdatedDeletedItem(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockItemSearch := new(MockItemSearch)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), mockItemSearch, &logger.NoOpLogger{})
+
+	mockItemRepo.On("GetOneById", mock.Anything, 5).Return(entity.Item{}, utils.ErrItemNotFound)
+
+	err := itemUseCase.HandleUpdated(context.Background(), entity.ItemUpdatedEvent{UserId: 1, ListId: 2, ItemId: 5})
+
+	assert.NoError(t, err)
+	mockItemSearch.AssertNotCalled(t, "Index", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestSearchItemByTagAfterCollaboratorTagging tests that the tags of the owner are kept in the search service
+// when a collaborator tags a shared item
+func TestSearchItemByTagAfterCollaboratorTagging(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockListRepo := new(MockListRepo)
+	mockTagRepo := new(MockTagRepo)
+	itemSearch := newFakeItemSearch()
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, mockTagRepo, itemSearch, &logger.NoOpLogger{})
+
+	ownerId, collaboratorId, listId := 1, 2, 3
+	ownerTagId, collaboratorTagId := 4, 9
+	item := entity.Item{Id: 5, Title: "Quarterly report"}
+
+	mockItemRepo.On("GetOneById", mock.Anything, item.Id).Return(item, nil)
+	mockTagRepo.On("GetIdsByItemId", mock.Anything, item.Id).Return([]int{ownerTagId}, nil).Once()
+	mockTagRepo.On("GetIdsByItemId", mock.Anything, item.Id).Return([]int{ownerTagId, collaboratorTagId}, nil).Once()
+	mockListRepo.On("GetUserListIds", mock.Anything, ownerId).Return([]int{listId}, nil)
+	mockTagRepo.On("GetIdsByNames", mock.Anything, ownerId, []string{"urgent"}).Return([]int{ownerTagId}, nil)
+	mockItemRepo.On("GetManyByIds", mock.Anything, []int{item.Id}).Return([]entity.Item{item}, nil)
+
+	err := itemUseCase.HandleUpdated(context.Background(), entity.ItemUpdatedEvent{UserId: ownerId, ListId: listId, ItemId: item.Id})
+	assert.NoError(t, err)
+
+	err = itemUseCase.HandleUpdated(context.Background(), entity.ItemUpdatedEvent{UserId: collaboratorId, ListId: listId, ItemId: item.Id})
+	assert.NoError(t, err)
+
+	page, err := itemUseCase.Search(context.Background(), ownerId, nil, nil, []string{"urgent"}, "report", entity.PageQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Item{item}, page.Items)
+	mockTagRepo.AssertExpectations(t)
+}
+
+// TestCompleteItemWithSubtasks tests the cascading completion of the UpdateOneById function in the ItemUseCase
+func TestCompleteItemWithSubtasks(t *testing.T) {
+	done := true
+
+	testCases := []struct {
+		name        string
+		input       entity.UpdateItemInput
+		mockRepo    func(*MockItemRepo, entity.UpdateItemInput)
+		expectedErr error
+	}{
+		{
+			name:  "Cascade completes the subtasks",
+			input: entity.UpdateItemInput{Done: &done, Cascade: true},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(nil)
+				repo.On("CompleteSubtasks", mock.Anything, 1, 3, 1).Return([]int{2, 4}, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Without cascade the subtasks are kept",
+			input: entity.UpdateItemInput{Done: &done},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Completing the subtasks fails",
+			input: entity.UpdateItemInput{Done: &done, Cascade: true},
+			mockRepo: func(repo *MockItemRepo, input entity.UpdateItemInput) {
+				repo.On("UpdateOneById", mock.Anything, mock.Anything, mock.Anything, 1, input, (*entity.ItemSeriesChange)(nil), (*int)(nil)).Return(nil)
+				repo.On("CompleteSubtasks", mock.Anything, 1, 3, 1).Return([]int{}, sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(entity.ListRoleEditor, nil)
+			mockItemRepo.On("GetOneById", mock.Anything, 1).Return(entity.Item{Id: 1}, nil)
+			testCase.mockRepo(mockItemRepo, testCase.input)
+
+			err := itemUseCase.UpdateOneById(context.Background(), 1, 3, 1, testCase.input, nil)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestGetSubtasks tests the GetSubtasks function in the ItemUseCase
+func TestGetSubtasks(t *testing.T) {
+	parentId := 1
+	subtasks := []entity.Item{
+		{Id: 2, Title: "Subtask 1", Done: true, ParentId: &parentId},
+		{Id: 3, Title: "Subtask 2", ParentId: &parentId},
+		{Id: 4, Title: "Subtask 3", ParentId: &parentId},
+	}
+
+	testCases := []struct {
+		name             string
+		role             string
+		roleErr          error
+		mockRepo         func(*MockItemRepo)
+		expectedSubtasks entity.Subtasks
+		expectedErr      error
+	}{
+		{
+			name: "Progress is computed from the subtasks",
+			role: entity.ListRoleViewer,
+			mockRepo: func(repo *MockItemRepo) {
+				repo.On("GetSubtasks", mock.Anything, 1).Return(subtasks, nil)
+			},
+			expectedSubtasks: entity.Subtasks{
+				ParentId: 1,
+				Progress: entity.ItemProgress{Total: 3, Done: 1, Percent: 33},
+				Items:    subtasks,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Item without subtasks",
+			role: entity.ListRoleViewer,
+			mockRepo: func(repo *MockItemRepo) {
+				repo.On("GetSubtasks", mock.Anything, 1).Return([]entity.Item{}, nil)
+			},
+			expectedSubtasks: entity.Subtasks{ParentId: 1, Items: []entity.Item{}},
+			expectedErr:      nil,
+		},
+		{
+			name:             "Item not shared with the user",
+			roleErr:          utils.ErrUserNotOwner,
+			mockRepo:         func(repo *MockItemRepo) {},
+			expectedSubtasks: entity.Subtasks{},
+			expectedErr:      utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(testCase.role, testCase.roleErr)
+			testCase.mockRepo(mockItemRepo)
+
+			result, err := itemUseCase.GetSubtasks(context.Background(), 1, 1)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedSubtasks, result)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSetItemParent tests the SetParent function in the ItemUseCase
+func TestSetItemParent(t *testing.T) {
+	parentId := 4
+
+	testCases := []struct {
+		name        string
+		role        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:        "Successful move",
+			role:        entity.ListRoleEditor,
+			repoErr:     nil,
+			expectedErr: nil,
+		},
+		{
+			name:        "Insufficient role",
+			role:        entity.ListRoleViewer,
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:        "Cycle",
+			role:        entity.ListRoleEditor,
+			repoErr:     utils.ErrItemParentCycle,
+			expectedErr: utils.ErrItemParentCycle,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 2).Return(testCase.role, nil)
+			if testCase.role == entity.ListRoleEditor {
+				mockItemRepo.On("SetParent", mock.Anything, 1, 3, 2, &parentId).Return(testCase.repoErr)
+			}
+
+			err := itemUseCase.SetParent(context.Background(), 1, 3, 2, entity.SetItemParentInput{ParentId: &parentId})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestMoveItem tests moving an item within its list
+func TestMoveItem(t *testing.T) {
+	afterId := 4
+	itemId := 2
+
+	testCases := []struct {
+		name        string
+		input       entity.MoveInput
+		role        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:        "Successful move",
+			input:       entity.MoveInput{AfterId: &afterId},
+			role:        entity.ListRoleEditor,
+			expectedErr: nil,
+		},
+		{
+			name:        "Missing anchor",
+			input:       entity.MoveInput{},
+			expectedErr: utils.ErrMoveAnchorRequired,
+		},
+		{
+			name:        "Anchored to itself",
+			input:       entity.MoveInput{BeforeId: &itemId},
+			expectedErr: utils.ErrMoveAnchorIsSelf,
+		},
+		{
+			name:        "Insufficient role",
+			input:       entity.MoveInput{AfterId: &afterId},
+			role:        entity.ListRoleViewer,
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:        "Anchor in another list",
+			input:       entity.MoveInput{AfterId: &afterId},
+			role:        entity.ListRoleEditor,
+			repoErr:     utils.ErrMoveAnchorNotFound,
+			expectedErr: utils.ErrMoveAnchorNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, new(MockListRepo), new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if testCase.role != "" {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, itemId).Return(testCase.role, nil)
+			}
+			if testCase.role == entity.ListRoleEditor {
+				mockItemRepo.On("MoveItem", mock.Anything, 3, itemId, testCase.input).Return(testCase.repoErr)
+			}
+
+			err := itemUseCase.Move(context.Background(), 1, 3, itemId, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestMoveItemsToList tests moving items to another list
+func TestMoveItemsToList(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         entity.TransferItemsInput
+		targetRole    string
+		sourceRole    string
+		expected      entity.MovedItems
+		expectedErr   error
+		expectRepoHit bool
+	}{
+		{
+			name:          "Successful move",
+			input:         entity.TransferItemsInput{ItemIds: []int{1, 2}},
+			targetRole:    entity.ListRoleEditor,
+			sourceRole:    entity.ListRoleOwner,
+			expected:      entity.MovedItems{ListId: 5, ItemIds: []int{1, 2, 7}},
+			expectRepoHit: true,
+		},
+		{
+			name:        "No items",
+			input:       entity.TransferItemsInput{ItemIds: []int{}},
+			expectedErr: utils.ErrTransferItemsEmpty,
+		},
+		{
+			name:        "Viewer of the target list",
+			input:       entity.TransferItemsInput{ItemIds: []int{1, 2}},
+			targetRole:  entity.ListRoleViewer,
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:        "Viewer of a source list",
+			input:       entity.TransferItemsInput{ItemIds: []int{1, 2}},
+			targetRole:  entity.ListRoleEditor,
+			sourceRole:  entity.ListRoleViewer,
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		mockListRepo := new(MockListRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo.On("GetUserListRole", mock.Anything, 1, 5).Return(testCase.targetRole, nil)
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, mock.Anything).Return(testCase.sourceRole, nil)
+			if testCase.expectRepoHit {
+				mockItemRepo.On("MoveToList", mock.Anything, 1, 5, testCase.input.ItemIds).Return(testCase.expected.ItemIds, nil)
+			}
+
+			moved, err := itemUseCase.MoveToList(context.Background(), 1, 5, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expected, moved)
+		})
+	}
+}
+
+// TestCopyItemsToList tests copying items to another list
+func TestCopyItemsToList(t *testing.T) {
+	mockItemRepo := new(MockItemRepo)
+	mockListRepo := new(MockListRepo)
+	itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+	copies := []entity.ItemCopy{{SourceId: 1, Item: entity.Item{Id: 20, Title: "Item"}}}
+
+	mockListRepo.On("GetUserListRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+	mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 1).Return(entity.ListRoleViewer, nil)
+	mockItemRepo.On("CopyToList", mock.Anything, 1, 5, []int{1}).Return(copies, nil)
+
+	result, err := itemUseCase.CopyToList(context.Background(), 1, 5, entity.TransferItemsInput{ItemIds: []int{1}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, copies, result)
+	mockItemRepo.AssertExpectations(t)
+	mockListRepo.AssertExpectations(t)
+}
+
+// TestBulkItems tests applying an operation to many items with a single role check
+func TestBulkItems(t *testing.T) {
+	done := true
+	listId := 5
+
+	accesses := []entity.ItemAccess{
+		{ItemId: 1, ListId: 2, Role: entity.ListRoleEditor},
+		{ItemId: 2, ListId: 3, Role: entity.ListRoleViewer},
+		{ItemId: 4, ListId: 2, Role: entity.ListRoleOwner},
+	}
+	results := []entity.BulkItemResult{
+		{ItemId: 1, Status: entity.BulkItemStatusOk},
+		{ItemId: 2, Status: entity.BulkItemStatusForbidden, Error: utils.ErrListPermissionDenied.Error()},
+		{ItemId: 3, Status: entity.BulkItemStatusNotFound, Error: utils.ErrItemNotFound.Error()},
+		{ItemId: 4, Status: entity.BulkItemStatusOk},
+	}
+
+	testCases := []struct {
+		name        string
+		input       entity.BulkItemsInput
+		mockRepo    func(*MockItemRepo, *MockListRepo)
+		expected    entity.BulkItemsResult
+		expectedErr error
+	}{
+		{
+			name:  "Update",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationUpdate, ItemIds: []int{1, 2, 3, 4, 1}, Update: &entity.BulkItemUpdate{Done: &done}},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{1, 2, 3, 4}).Return(accesses, nil)
+				itemRepo.On("GetManyByIds", mock.Anything, []int{1, 4}).Return([]entity.Item{{Id: 1}, {Id: 4}}, nil)
+				itemRepo.On("UpdateManyByIds", mock.Anything, 1, []int{1, 4},
+					entity.UpdateItemInput{Done: &done, Scope: entity.ItemEditScopeThis}).Return([]int{1, 4}, nil)
+			},
+			expected: entity.BulkItemsResult{Operation: entity.BulkOperationUpdate, Results: results, AffectedIds: []int{1, 4}},
+		},
+		{
+			name:  "Update of an item trashed meanwhile",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationUpdate, ItemIds: []int{1, 4}, Update: &entity.BulkItemUpdate{Done: &done}},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{1, 4}).Return(accesses, nil)
+				itemRepo.On("GetManyByIds", mock.Anything, []int{1, 4}).Return([]entity.Item{{Id: 1}}, nil)
+				itemRepo.On("UpdateManyByIds", mock.Anything, 1, []int{1, 4}, mock.Anything).Return([]int{1}, nil)
+			},
+			expected: entity.BulkItemsResult{
+				Operation: entity.BulkOperationUpdate,
+				Results: []entity.BulkItemResult{
+					{ItemId: 1, Status: entity.BulkItemStatusOk},
+					{ItemId: 4, Status: entity.BulkItemStatusNotFound, Error: utils.ErrItemNotFound.Error()},
+				},
+				AffectedIds: []int{1},
+			},
+		},
+		{
+			name:  "Delete",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationDelete, ItemIds: []int{1, 2, 3, 4}},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{1, 2, 3, 4}).Return(accesses, nil)
+				itemRepo.On("DeleteManyByIds", mock.Anything, []int{1, 4}).Return([]int{1, 4, 6}, nil)
+			},
+			expected: entity.BulkItemsResult{Operation: entity.BulkOperationDelete, Results: results, AffectedIds: []int{1, 4, 6}},
+		},
+		{
+			name:  "Move",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationMove, ItemIds: []int{1, 2, 3, 4}, ListId: &listId},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				listRepo.On("GetUserListRole", mock.Anything, 1, listId).Return(entity.ListRoleEditor, nil)
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{1, 2, 3, 4}).Return(accesses, nil)
+				itemRepo.On("MoveToList", mock.Anything, 1, listId, []int{1, 4}).Return([]int{1, 4}, nil)
+			},
+			expected: entity.BulkItemsResult{Operation: entity.BulkOperationMove, Results: results, AffectedIds: []int{1, 4}},
+		},
+		{
+			name:  "Nothing the user can change",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationDelete, ItemIds: []int{2, 3}},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{2, 3}).Return(accesses, nil)
+			},
+			expected: entity.BulkItemsResult{Operation: entity.BulkOperationDelete, Results: results[1:3], AffectedIds: []int{}},
+		},
+		{
+			name:  "Move to a list the user only views",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationMove, ItemIds: []int{1}, ListId: &listId},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				listRepo.On("GetUserListRole", mock.Anything, 1, listId).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:        "Move without a list",
+			input:       entity.BulkItemsInput{Operation: entity.BulkOperationMove, ItemIds: []int{1}},
+			mockRepo:    func(itemRepo *MockItemRepo, listRepo *MockListRepo) {},
+			expectedErr: utils.ErrBulkListRequired,
+		},
+		{
+			name:        "Update without fields",
+			input:       entity.BulkItemsInput{Operation: entity.BulkOperationUpdate, ItemIds: []int{1}, Update: &entity.BulkItemUpdate{}},
+			mockRepo:    func(itemRepo *MockItemRepo, listRepo *MockListRepo) {},
+			expectedErr: utils.ErrItemEmptyRequest,
+		},
+		{
+			name:        "Unknown operation",
+			input:       entity.BulkItemsInput{Operation: "archive", ItemIds: []int{1}},
+			mockRepo:    func(itemRepo *MockItemRepo, listRepo *MockListRepo) {},
+			expectedErr: utils.ErrInvalidBulkOperation,
+		},
+		{
+			name:        "No items",
+			input:       entity.BulkItemsInput{Operation: entity.BulkOperationDelete, ItemIds: []int{}},
+			mockRepo:    func(itemRepo *MockItemRepo, listRepo *MockListRepo) {},
+			expectedErr: utils.ErrBulkItemsEmpty,
+		},
+		{
+			name:  "Database error",
+			input: entity.BulkItemsInput{Operation: entity.BulkOperationDelete, ItemIds: []int{1}},
+			mockRepo: func(itemRepo *MockItemRepo, listRepo *MockListRepo) {
+				itemRepo.On("GetUserItemsAccess", mock.Anything, 1, []int{1}).Return(accesses, nil)
+				itemRepo.On("DeleteManyByIds", mock.Anything, []int{1}).Return([]int(nil), sql.ErrConnDone)
+			},
+			expectedErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockItemRepo := new(MockItemRepo)
+		mockListRepo := new(MockListRepo)
+		itemUseCase := usecase.NewItemUseCase(mockItemRepo, mockListRepo, new(MockTagRepo), new(MockItemSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			testCase.mockRepo(mockItemRepo, mockListRepo)
+
+			result, err := itemUseCase.Bulk(context.Background(), 1, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expected, result)
+			mockItemRepo.AssertExpectations(t)
+			mockListRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+// lastUsedInterval is the least time between two writes of the last use of the same app or token
+const lastUsedInterval = time.Minute
+
+// lastUsedThrottle remembers in memory when the last use of each key was written, so that an app or token
+// presented on every request updates its last use at most once per interval
+type lastUsedThrottle[K comparable] struct {
+	mu       sync.Mutex
+	interval time.Duration
+	written  map[K]time.Time
+	swept    time.Time
+}
+
+// newLastUsedThrottle creates a lastUsedThrottle allowing one write per key and interval
+func newLastUsedThrottle[K comparable](interval time.Duration) *lastUsedThrottle[K] {
+	return &lastUsedThrottle[K]{
+		interval: interval,
+		written:  map[K]time.Time{},
+	}
+}
+
+// allow reports whether the last use of the key is due to be written at now and if so remembers the write.
+// Keys whose write is older than the interval are forgotten once per interval so that the map stays small
+func (t *lastUsedThrottle[K]) allow(key K, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.swept) >= t.interval {
+		for k, writtenAt := range t.written {
+			if now.Sub(writtenAt) >= t.interval {
+				delete(t.written, k)
+			}
+		}
+		t.swept = now
+	}
+
+	if writtenAt, ok := t.written[key]; ok && now.Sub(writtenAt) < t.interval {
+		return false
+	}
+
+	t.written[key] = now
+	return true
+}
@@ -2,52 +2,49 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
-	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
 	"github.com/berikulyBeket/todo-plus/pkg/search"
+	"github.com/berikulyBeket/todo-plus/utils"
 )
 
 // ListUseCase handles the business logic related to lists
 type ListUseCase struct {
-	repo           repository.List
-	search         search.List
-	brokerProducer messagebroker.Producer
-	logger         logger.Interface
+	repo   repository.List
+	search search.List
+	logger logger.Interface
 }
 
 // NewListUseCase creates a new instance of ListUseCase
-func NewListUseCase(r repository.List, s search.List, p messagebroker.Producer, l logger.Interface) *ListUseCase {
+func NewListUseCase(r repository.List, s search.List, l logger.Interface) *ListUseCase {
 	return &ListUseCase{
-		repo:           r,
-		search:         s,
-		brokerProducer: p,
-		logger:         l,
+		repo:   r,
+		search: s,
+		logger: l,
 	}
 }
 
-// Create creates a new list for a user and publishes a list created event
+// Create creates a new list for a user, the repository records a list created event in the outbox within the
+// transaction creating the list
 func (uc *ListUseCase) Create(ctx context.Context, userId int, list *entity.List) (int, error) {
-	listId, err := uc.repo.CreateUserList(ctx, userId, list)
-	if err != nil {
-		return 0, err
-	}
-
-	go uc.brokerProducer.PublishListCreatedEvent(userId, list)
-
-	return listId, nil
+	return uc.repo.CreateUserList(ctx, userId, list)
 }
 
-// GetAll retrieves all lists for a given user
-func (uc *ListUseCase) GetAll(ctx context.Context, userId int) ([]entity.List, error) {
-	return uc.repo.GetAllUserLists(ctx, userId)
+// GetAll retrieves a page of the lists of a given user in the sort order of the query
+func (uc *ListUseCase) GetAll(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error) {
+	if err := query.Validate(entity.ListSorts); err != nil {
+		return entity.ListPage{}, err
+	}
+
+	return uc.repo.GetUserListsPage(ctx, userId, query)
 }
 
-// GetOneById retrieves a list by its ID if the user is the owner
+// GetOneById retrieves a list by its ID if the list is shared with the user
 func (uc *ListUseCase) GetOneById(ctx context.Context, userId, listId int) (entity.List, error) {
-	err := uc.repo.IsUserOwnerOfList(ctx, userId, listId)
+	err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleViewer)
 	if err != nil {
 		return entity.List{}, err
 	}
@@ -55,83 +52,265 @@ func (uc *ListUseCase) GetOneById(ctx context.Context, userId, listId int) (enti
 	return uc.repo.GetOneById(ctx, listId)
 }
 
-// UpdateOneById updates a list's details if the user is the owner and publishes a list updated event
-func (uc *ListUseCase) UpdateOneById(ctx context.Context, userId, listId int, newTodoInput entity.UpdateListInput) error {
-	if err := uc.repo.IsUserOwnerOfList(ctx, userId, listId); err != nil {
+// UpdateOneById updates a list's details if the user is an owner, the list updated event is recorded in the outbox by
+// the repository along with the update. With a version the list is only updated while it still has that version
+func (uc *ListUseCase) UpdateOneById(ctx context.Context, userId, listId int, newTodoInput entity.UpdateListInput, version *int) error {
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleOwner); err != nil {
 		return err
 	}
 
-	err := uc.repo.UpdateOneById(ctx, &userId, listId, newTodoInput)
-	if err != nil {
+	return uc.repo.UpdateOneById(ctx, &userId, listId, newTodoInput, version)
+}
+
+// DeleteOneById moves a list to the trash if the user is an owner, the list deleted event is recorded in the outbox by
+// the repository along with the move. With a version the list is only trashed while it still has that version
+func (uc *ListUseCase) DeleteOneById(ctx context.Context, userId, listId int, version *int) error {
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleOwner); err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishListUpdatedEvent(userId, listId)
+	return uc.repo.DeleteOneById(ctx, &userId, listId, version)
+}
 
-	return nil
+// DeleteOneByAdmin moves a list to the trash by an admin, the repository records the list deleted event in the outbox
+func (uc *ListUseCase) DeleteOneByAdmin(ctx context.Context, listId int) error {
+	return uc.repo.DeleteOneById(ctx, nil, listId, nil)
+}
+
+// GetCollaborators retrieves the users a list is shared with if the list is shared with the user
+func (uc *ListUseCase) GetCollaborators(ctx context.Context, userId, listId int) ([]entity.ListCollaborator, error) {
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleViewer); err != nil {
+		return []entity.ListCollaborator{}, err
+	}
+
+	return uc.repo.GetCollaborators(ctx, listId)
+}
+
+// AddCollaborator shares a list with another user if the user is an owner of the list
+func (uc *ListUseCase) AddCollaborator(ctx context.Context, userId, listId int, input entity.AddCollaboratorInput) (entity.ListCollaborator, error) {
+	if err := input.Validate(); err != nil {
+		return entity.ListCollaborator{}, err
+	}
+
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleOwner); err != nil {
+		return entity.ListCollaborator{}, err
+	}
+
+	return uc.repo.AddCollaborator(ctx, listId, input.Username, input.Role)
 }
 
-// DeleteOneById deletes a list if the user is the owner and publishes a list deleted event
-func (uc *ListUseCase) DeleteOneById(ctx context.Context, userId, listId int) error {
-	if err := uc.repo.IsUserOwnerOfList(ctx, userId, listId); err != nil {
+// UpdateCollaborator changes the role of a collaborator if the user is an owner of the list,
+// the last owner of a list cannot be demoted
+func (uc *ListUseCase) UpdateCollaborator(ctx context.Context, userId, listId, collaboratorId int, input entity.UpdateCollaboratorInput) error {
+	if err := input.Validate(); err != nil {
 		return err
 	}
 
-	err := uc.repo.DeleteOneById(ctx, &userId, listId)
-	if err != nil {
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleOwner); err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishListDeletedEvent(listId)
+	if input.Role != entity.ListRoleOwner {
+		if err := uc.ensureAnotherOwner(ctx, listId, collaboratorId); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return uc.repo.UpdateCollaboratorRole(ctx, listId, collaboratorId, input.Role)
 }
 
-// DeleteOneByAdmin deletes a list by an admin and publishes a list deleted event
-func (uc *ListUseCase) DeleteOneByAdmin(ctx context.Context, listId int) error {
-	err := uc.repo.DeleteOneById(ctx, nil, listId)
+// RemoveCollaborator stops sharing a list with a collaborator if the user is an owner of the list,
+// any collaborator can remove themselves but the last owner of a list cannot leave it
+func (uc *ListUseCase) RemoveCollaborator(ctx context.Context, userId, listId, collaboratorId int) error {
+	required := entity.ListRoleOwner
+	if userId == collaboratorId {
+		required = entity.ListRoleViewer
+	}
+
+	if err := authorizeList(ctx, uc.repo, userId, listId, required); err != nil {
+		return err
+	}
+
+	if err := uc.ensureAnotherOwner(ctx, listId, collaboratorId); err != nil {
+		return err
+	}
+
+	return uc.repo.RemoveCollaborator(ctx, listId, collaboratorId)
+}
+
+// Move places a list between the anchors of the input among the lists of the user, any collaborator can
+// order the lists shared with them since the order is personal
+func (uc *ListUseCase) Move(ctx context.Context, userId, listId int, input entity.MoveInput) error {
+	if err := input.Validate(listId); err != nil {
+		return err
+	}
+
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleViewer); err != nil {
+		return err
+	}
+
+	return uc.repo.MoveUserList(ctx, userId, listId, input)
+}
+
+// Clone copies a list shared with the user together with its items into a new list owned by the user,
+// the repository records created events for the new list and its items in the outbox of the same transaction
+func (uc *ListUseCase) Clone(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error) {
+	if err := input.Validate(); err != nil {
+		return entity.ListCopy{}, err
+	}
+
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleViewer); err != nil {
+		return entity.ListCopy{}, err
+	}
+
+	return uc.repo.CloneUserList(ctx, userId, listId, input)
+}
+
+// CreateTemplate saves a list shared with the user as a template of the user
+func (uc *ListUseCase) CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return entity.ListTemplate{}, err
+	}
+
+	if err := authorizeList(ctx, uc.repo, userId, listId, entity.ListRoleViewer); err != nil {
+		return entity.ListTemplate{}, err
+	}
+
+	return uc.repo.CreateTemplate(ctx, userId, listId, input)
+}
+
+// GetTemplates retrieves the templates of the user
+func (uc *ListUseCase) GetTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error) {
+	return uc.repo.GetUserTemplates(ctx, userId)
+}
+
+// GetTemplate retrieves a template of the user with its items
+func (uc *ListUseCase) GetTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error) {
+	return uc.repo.GetUserTemplate(ctx, userId, templateId)
+}
+
+// DeleteTemplate deletes a template of the user
+func (uc *ListUseCase) DeleteTemplate(ctx context.Context, userId, templateId int) error {
+	return uc.repo.DeleteUserTemplate(ctx, userId, templateId)
+}
+
+// CreateFromTemplate creates a new list owned by the user from one of their templates, the repository records
+// created events for the new list and its items in the outbox of the same transaction
+func (uc *ListUseCase) CreateFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error) {
+	if err := input.Validate(); err != nil {
+		return entity.ListCopy{}, err
+	}
+
+	return uc.repo.CreateUserListFromTemplate(ctx, userId, templateId, input)
+}
+
+// ensureAnotherOwner returns ErrLastListOwner when the collaborator is the only owner of the list
+func (uc *ListUseCase) ensureAnotherOwner(ctx context.Context, listId, collaboratorId int) error {
+	collaborators, err := uc.repo.GetCollaborators(ctx, listId)
 	if err != nil {
 		return err
 	}
 
-	go uc.brokerProducer.PublishListDeletedEvent(listId)
+	isOwner := false
+	owners := 0
+	for _, collaborator := range collaborators {
+		if collaborator.Role != entity.ListRoleOwner {
+			continue
+		}
+
+		owners++
+		if collaborator.UserId == collaboratorId {
+			isOwner = true
+		}
+	}
+
+	if isOwner && owners == 1 {
+		return utils.ErrLastListOwner
+	}
 
 	return nil
 }
 
-// Search performs a search for lists based on the search text for a given user
-func (uc *ListUseCase) Search(ctx context.Context, userId int, searchText string) ([]entity.List, error) {
-	listIds, err := uc.search.SearchIds(ctx, userId, searchText)
+// Search performs a search for the lists shared with a given user based on the search text,
+// lists are returned a page at a time from the best match
+func (uc *ListUseCase) Search(ctx context.Context, userId int, searchText string, query entity.PageQuery) (entity.ListPage, error) {
+	if err := query.Validate(entity.SearchSorts); err != nil {
+		return entity.ListPage{}, err
+	}
+
+	page, err := searchPage(query)
+	if err != nil {
+		return entity.ListPage{}, err
+	}
+
+	listIds, err := uc.repo.GetUserListIds(ctx, userId)
+	if err != nil {
+		return entity.ListPage{}, err
+	}
+
+	if len(listIds) == 0 {
+		return entity.ListPage{Lists: []entity.List{}}, nil
+	}
+
+	hits, err := uc.search.Search(ctx, listIds, searchText, page)
 	if err != nil {
-		return []entity.List{}, err
+		return entity.ListPage{}, err
 	}
 
-	return uc.repo.GetManyByIds(ctx, listIds)
+	listIds, nextCursor := hitIds(query, hits)
+
+	lists, err := uc.repo.GetManyByIds(ctx, listIds)
+	if err != nil {
+		return entity.ListPage{}, err
+	}
+
+	return entity.ListPage{
+		Lists:      orderByIds(lists, listIds, func(list entity.List) int { return list.Id }),
+		NextCursor: nextCursor,
+	}, nil
 }
 
-// HandleCreated handles the list created event by indexing the list in the search service
+// HandleCreated handles the list created event by indexing the list in the search service. The list is read again,
+// so a created event delivered after the deleted event of the list does not bring it back to the search service
 func (uc *ListUseCase) HandleCreated(ctx context.Context, message entity.ListCreatedEvent) error {
 	uc.logger.Info("handling list created event in use case layer")
 
-	if err := uc.search.Index(ctx, message.UserId, &message.List); err != nil {
-		uc.logger.Errorf("failed to index document in Elasticsearch: %v", err)
-		return err
-	}
-
-	return nil
+	return uc.reindex(ctx, message.List.Id)
 }
 
 // HandleUpdated handles the list updated event by re-indexing the list in the search service
 func (uc *ListUseCase) HandleUpdated(ctx context.Context, message entity.ListUpdatedEvent) error {
 	uc.logger.Info("handling list updated event in use case layer")
 
-	list, err := uc.repo.GetOneById(ctx, message.ListId)
+	return uc.reindex(ctx, message.ListId)
+}
+
+// HandleDeleted handles the list deleted event by removing the list from the search service
+func (uc *ListUseCase) HandleDeleted(ctx context.Context, message entity.ListDeletedEvent) error {
+	uc.logger.Info("handling list deleted event in use case layer")
+
+	if err := uc.search.Delete(ctx, message.ListId); err != nil {
+		uc.logger.Errorf("failed to delete document in Elasticsearch: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// reindex indexes the current state of a list in the search service, a list deleted since the event is skipped
+func (uc *ListUseCase) reindex(ctx context.Context, listId int) error {
+	list, err := uc.repo.GetOneById(ctx, listId)
 	if err != nil {
-		uc.logger.Errorf("error fetching updated list: %v", err)
+		if errors.Is(err, utils.ErrListNotFound) {
+			uc.logger.Info("skipping list %d, it is in the trash or purged", listId)
+			return nil
+		}
+
+		uc.logger.Errorf("error fetching list to index: %v", err)
 		return err
 	}
 
-	if err := uc.search.Index(ctx, message.UserId, &list); err != nil {
+	if err := uc.search.Index(ctx, &list); err != nil {
 		uc.logger.Errorf("failed to index document in Elasticsearch: %v", err)
 		return err
 	}
@@ -139,14 +318,16 @@ func (uc *ListUseCase) HandleUpdated(ctx context.Context, message entity.ListUpd
 	return nil
 }
 
-// HandleDeleted handles the list deleted event by removing the list from the search service
-func (uc *ListUseCase) HandleDeleted(ctx context.Context, message entity.ListDeletedEvent) error {
-	uc.logger.Info("handling list deleted event in use case layer")
-
-	if err := uc.search.Delete(ctx, message.ListId); err != nil {
-		uc.logger.Errorf("failed to delete document in Elasticsearch: %v", err)
+// authorizeList checks that the list is shared with the user with at least the required role
+func authorizeList(ctx context.Context, repo repository.List, userId, listId int, required string) error {
+	role, err := repo.GetUserListRole(ctx, userId, listId)
+	if err != nil {
 		return err
 	}
 
+	if !entity.ListRoleAllows(role, required) {
+		return utils.ErrListPermissionDenied
+	}
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
+	"github.com/berikulyBeket/todo-plus/pkg/search"
 	"github.com/berikulyBeket/todo-plus/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -42,8 +43,7 @@ func TestCreateList(t *testing.T) {
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
@@ -67,42 +67,69 @@ func TestCreateList(t *testing.T) {
 
 // TestGetAllLists tests the GetAllLists function in the ListUseCase
 func TestGetAllLists(t *testing.T) {
+	defaultQuery := entity.PageQuery{Limit: entity.DefaultPageLimit, Sort: entity.SortPosition, Order: entity.SortOrderAsc}
+
 	testCases := []struct {
-		name          string
-		userId        int
-		expectedLists []entity.List
-		expectedErr   error
+		name         string
+		userId       int
+		query        entity.PageQuery
+		repoQuery    entity.PageQuery
+		expectedPage entity.ListPage
+		expectedErr  error
 	}{
 		{
-			name:          "Successful retrieval",
-			userId:        1,
-			expectedLists: []entity.List{{Title: "List1"}, {Title: "List2"}},
-			expectedErr:   nil,
+			name:         "Successful retrieval",
+			userId:       1,
+			repoQuery:    defaultQuery,
+			expectedPage: entity.ListPage{Lists: []entity.List{{Title: "List1"}, {Title: "List2"}}},
+			expectedErr:  nil,
+		},
+		{
+			name:         "Sorted by title in descending order",
+			userId:       1,
+			query:        entity.PageQuery{Limit: 5, Sort: entity.SortTitle, Order: entity.SortOrderDesc},
+			repoQuery:    entity.PageQuery{Limit: 5, Sort: entity.SortTitle, Order: entity.SortOrderDesc},
+			expectedPage: entity.ListPage{Lists: []entity.List{{Title: "List2"}}},
+			expectedErr:  nil,
+		},
+		{
+			name:         "Limit out of range",
+			userId:       1,
+			query:        entity.PageQuery{Limit: entity.MaxPageLimit + 1},
+			expectedPage: entity.ListPage{},
+			expectedErr:  utils.ErrInvalidPageLimit,
+		},
+		{
+			name:         "Unknown order",
+			userId:       1,
+			query:        entity.PageQuery{Order: "sideways"},
+			expectedPage: entity.ListPage{},
+			expectedErr:  utils.ErrInvalidSortOrder,
 		},
 		{
-			name:          "No lists found",
-			userId:        1,
-			expectedLists: nil,
-			expectedErr:   errors.New("no lists found"),
+			name:         "No lists found",
+			userId:       1,
+			repoQuery:    defaultQuery,
+			expectedPage: entity.ListPage{},
+			expectedErr:  errors.New("no lists found"),
 		},
 	}
 
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockRepo.On("GetAllUserLists", mock.Anything, testCase.userId).Return(testCase.expectedLists, testCase.expectedErr)
+			mockRepo.On("GetUserListsPage", mock.Anything, testCase.userId, testCase.repoQuery).Return(testCase.expectedPage, testCase.expectedErr)
 
-			actualLists, err := listUseCase.GetAll(context.Background(), testCase.userId)
+			actualPage, err := listUseCase.GetAll(context.Background(), testCase.userId, testCase.query)
 
-			assert.Equal(t, testCase.expectedLists, actualLists)
+			assert.Equal(t, testCase.expectedPage, actualPage)
 			if testCase.expectedErr != nil {
 				assert.Error(t, err)
 				assert.Equal(t, testCase.expectedErr, err)
@@ -113,6 +140,55 @@ func TestGetAllLists(t *testing.T) {
 	}
 }
 
+// TestSearchLists tests that the Search function in the ListUseCase keeps the order of the search hits
+func TestSearchLists(t *testing.T) {
+	mockRepo := new(MockListRepo)
+	mockListSearch := new(MockListSearch)
+	listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+	query := entity.PageQuery{Limit: 2, Sort: entity.SortRelevance, Order: entity.SortOrderDesc}
+
+	mockRepo.On("GetUserListIds", mock.Anything, 1).Return([]int{1, 4, 7}, nil)
+	mockListSearch.On("Search", mock.Anything, []int{1, 4, 7}, "groceries", search.Page{Size: 3, Descending: true}).
+		Return([]search.Hit{{Id: 4, Score: 3}, {Id: 1, Score: 2}, {Id: 7, Score: 1}}, nil)
+	mockRepo.On("GetManyByIds", mock.Anything, []int{4, 1}).Return([]entity.List{{Id: 1}, {Id: 4}}, nil)
+
+	page, err := listUseCase.Search(context.Background(), 1, "groceries", entity.PageQuery{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ListPage{Lists: []entity.List{{Id: 4}, {Id: 1}}, NextCursor: query.NextCursor("2", 1)}, page)
+	mockListSearch.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestHandleCreatedDeletedList tests that the HandleCreated function in the ListUseCase skips lists deleted since the event
+func TestHandleCreatedDeletedList(t *testing.T) {
+	mockRepo := new(MockListRepo)
+	mockListSearch := new(MockListSearch)
+	listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+	mockRepo.On("GetOneById", mock.Anything, 4).Return(entity.List{}, utils.ErrListNotFound)
+
+	err := listUseCase.HandleCreated(context.Background(), entity.ListCreatedEvent{UserId: 1, List: entity.List{Id: 4}})
+
+	assert.NoError(t, err)
+	mockListSearch.AssertNotCalled(t, "Index", mock.Anything, mock.Anything)
+}
+
+// TestHandleUpdatedDeletedList tests that the HandleUpdated function in the ListUseCase skips lists deleted since the event
+func TestHandleUpdatedDeletedList(t *testing.T) {
+	mockRepo := new(MockListRepo)
+	mockListSearch := new(MockListSearch)
+	listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+	mockRepo.On("GetOneById", mock.Anything, 4).Return(entity.List{}, utils.ErrListNotFound)
+
+	err := listUseCase.HandleUpdated(context.Background(), entity.ListUpdatedEvent{UserId: 1, ListId: 4})
+
+	assert.NoError(t, err)
+	mockListSearch.AssertNotCalled(t, "Index", mock.Anything, mock.Anything)
+}
+
 // TestGetListById tests the GetListById function in the ListUseCase
 func TestGetListById(t *testing.T) {
 	testCases := []struct {
@@ -120,6 +196,7 @@ func TestGetListById(t *testing.T) {
 		userId           int
 		listId           int
 		expectedList     entity.List
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -128,6 +205,7 @@ func TestGetListById(t *testing.T) {
 			userId:           1,
 			listId:           1,
 			expectedList:     entity.List{Title: "List1"},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
@@ -136,6 +214,7 @@ func TestGetListById(t *testing.T) {
 			userId:           1,
 			listId:           99,
 			expectedList:     entity.List{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      nil,
 		},
@@ -144,6 +223,7 @@ func TestGetListById(t *testing.T) {
 			userId:           1,
 			listId:           99,
 			expectedList:     entity.List{},
+			role:             entity.ListRoleViewer,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrListNotFound,
 		},
@@ -152,15 +232,14 @@ func TestGetListById(t *testing.T) {
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockRepo.On("IsUserOwnerOfList", mock.Anything, testCase.userId, testCase.listId).Return(testCase.expectedOwnerErr)
+			mockRepo.On("GetUserListRole", mock.Anything, testCase.userId, testCase.listId).Return(testCase.role, testCase.expectedOwnerErr)
 			mockRepo.On("GetOneById", mock.Anything, testCase.listId).Return(testCase.expectedList, testCase.expectedErr)
 
 			actualList, err := listUseCase.GetOneById(context.Background(), testCase.userId, testCase.listId)
@@ -188,6 +267,7 @@ func TestUpdateListById(t *testing.T) {
 		userId           int
 		listId           int
 		newTodoInput     entity.UpdateListInput
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -196,14 +276,25 @@ func TestUpdateListById(t *testing.T) {
 			userId:           1,
 			listId:           1,
 			newTodoInput:     entity.UpdateListInput{Title: &title},
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Insufficient role",
+			userId:           1,
+			listId:           1,
+			newTodoInput:     entity.UpdateListInput{Title: &title},
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: utils.ErrListPermissionDenied,
+			expectedErr:      nil,
+		},
 		{
 			name:             "List not belongs to user",
 			userId:           1,
 			listId:           99,
 			newTodoInput:     entity.UpdateListInput{Title: &title},
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: utils.ErrUserNotOwner,
 			expectedErr:      utils.ErrListNotFound,
 		},
@@ -212,6 +303,7 @@ func TestUpdateListById(t *testing.T) {
 			userId:           1,
 			listId:           99,
 			newTodoInput:     entity.UpdateListInput{Title: &title},
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrListNotFound,
 		},
@@ -220,18 +312,17 @@ func TestUpdateListById(t *testing.T) {
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockRepo.On("IsUserOwnerOfList", mock.Anything, testCase.userId, testCase.listId).Return(testCase.expectedOwnerErr)
-			mockRepo.On("UpdateOneById", mock.Anything, &testCase.userId, testCase.listId, testCase.newTodoInput).Return(testCase.expectedErr)
+			mockRepo.On("GetUserListRole", mock.Anything, testCase.userId, testCase.listId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockRepo.On("UpdateOneById", mock.Anything, &testCase.userId, testCase.listId, testCase.newTodoInput, (*int)(nil)).Return(testCase.expectedErr)
 
-			err := listUseCase.UpdateOneById(context.Background(), testCase.userId, testCase.listId, testCase.newTodoInput)
+			err := listUseCase.UpdateOneById(context.Background(), testCase.userId, testCase.listId, testCase.newTodoInput, nil)
 
 			if testCase.expectedOwnerErr != nil {
 				assert.Error(t, err)
@@ -252,6 +343,7 @@ func TestDeleteListById(t *testing.T) {
 		name             string
 		userId           int
 		listId           int
+		role             string
 		expectedOwnerErr error
 		expectedErr      error
 	}{
@@ -259,13 +351,23 @@ func TestDeleteListById(t *testing.T) {
 			name:             "Successful deletion",
 			userId:           1,
 			listId:           1,
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: nil,
 			expectedErr:      nil,
 		},
+		{
+			name:             "Insufficient role",
+			userId:           1,
+			listId:           1,
+			role:             entity.ListRoleViewer,
+			expectedOwnerErr: utils.ErrListPermissionDenied,
+			expectedErr:      nil,
+		},
 		{
 			name:             "List not belongs to user",
 			userId:           1,
 			listId:           99,
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: utils.ErrUserNotFound,
 			expectedErr:      utils.ErrListNotFound,
 		},
@@ -273,6 +375,7 @@ func TestDeleteListById(t *testing.T) {
 			name:             "List not found",
 			userId:           1,
 			listId:           99,
+			role:             entity.ListRoleOwner,
 			expectedOwnerErr: nil,
 			expectedErr:      utils.ErrListNotFound,
 		},
@@ -281,18 +384,17 @@ func TestDeleteListById(t *testing.T) {
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockRepo.On("IsUserOwnerOfList", mock.Anything, testCase.userId, testCase.listId).Return(testCase.expectedOwnerErr)
-			mockRepo.On("DeleteOneById", mock.Anything, &testCase.userId, testCase.listId).Return(testCase.expectedErr)
+			mockRepo.On("GetUserListRole", mock.Anything, testCase.userId, testCase.listId).Return(testCase.role, testCase.expectedOwnerErr)
+			mockRepo.On("DeleteOneById", mock.Anything, &testCase.userId, testCase.listId, (*int)(nil)).Return(testCase.expectedErr)
 
-			err := listUseCase.DeleteOneById(context.Background(), testCase.userId, testCase.listId)
+			err := listUseCase.DeleteOneById(context.Background(), testCase.userId, testCase.listId, nil)
 
 			if testCase.expectedOwnerErr != nil {
 				assert.Error(t, err)
@@ -329,8 +431,7 @@ func TestDeleteOneByAdmin(t *testing.T) {
 	for _, testCase := range testCases {
 		mockRepo := new(MockListRepo)
 		mockListSearch := new(MockListSearch)
-		mockBrokerProducer := new(MockBrokerProducer)
-		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, mockBrokerProducer, &logger.NoOpLogger{})
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
 
 		testCase := testCase
 
@@ -338,7 +439,7 @@ func TestDeleteOneByAdmin(t *testing.T) {
 			t.Parallel()
 
 			var userId *int
-			mockRepo.On("DeleteOneById", mock.Anything, userId, testCase.listId).Return(testCase.expectedErr)
+			mockRepo.On("DeleteOneById", mock.Anything, userId, testCase.listId, (*int)(nil)).Return(testCase.expectedErr)
 
 			err := listUseCase.DeleteOneByAdmin(context.Background(), testCase.listId)
 
@@ -351,3 +452,410 @@ func TestDeleteOneByAdmin(t *testing.T) {
 		})
 	}
 }
+
+// TestAddCollaborator tests the AddCollaborator function in the ListUseCase
+func TestAddCollaborator(t *testing.T) {
+	collaborator := entity.ListCollaborator{UserId: 2, Name: "Bob", Username: "bob", Role: entity.ListRoleEditor}
+
+	testCases := []struct {
+		name                 string
+		input                entity.AddCollaboratorInput
+		role                 string
+		expectedOwnerErr     error
+		expectedCollaborator entity.ListCollaborator
+		expectedErr          error
+	}{
+		{
+			name:                 "Successful share",
+			input:                entity.AddCollaboratorInput{Username: "bob", Role: entity.ListRoleEditor},
+			role:                 entity.ListRoleOwner,
+			expectedCollaborator: collaborator,
+		},
+		{
+			name:        "Unknown role",
+			input:       entity.AddCollaboratorInput{Username: "bob", Role: "admin"},
+			role:        entity.ListRoleOwner,
+			expectedErr: utils.ErrInvalidListRole,
+		},
+		{
+			name:        "Editor cannot share",
+			input:       entity.AddCollaboratorInput{Username: "bob", Role: entity.ListRoleEditor},
+			role:        entity.ListRoleEditor,
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name:             "List not shared with user",
+			input:            entity.AddCollaboratorInput{Username: "bob", Role: entity.ListRoleEditor},
+			expectedOwnerErr: utils.ErrUserNotOwner,
+			expectedErr:      utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockRepo := new(MockListRepo)
+		mockListSearch := new(MockListSearch)
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo.On("GetUserListRole", mock.Anything, 1, 1).Return(testCase.role, testCase.expectedOwnerErr)
+			mockRepo.On("AddCollaborator", mock.Anything, 1, testCase.input.Username, testCase.input.Role).Return(collaborator, nil)
+
+			actualCollaborator, err := listUseCase.AddCollaborator(context.Background(), 1, 1, testCase.input)
+
+			assert.Equal(t, testCase.expectedCollaborator, actualCollaborator)
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+// TestUpdateCollaborator tests the UpdateCollaborator function in the ListUseCase
+func TestUpdateCollaborator(t *testing.T) {
+	testCases := []struct {
+		name           string
+		collaboratorId int
+		input          entity.UpdateCollaboratorInput
+		role           string
+		collaborators  []entity.ListCollaborator
+		expectedErr    error
+	}{
+		{
+			name:           "Successful update",
+			collaboratorId: 2,
+			input:          entity.UpdateCollaboratorInput{Role: entity.ListRoleViewer},
+			role:           entity.ListRoleOwner,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleEditor},
+			},
+		},
+		{
+			name:           "Viewer cannot change roles",
+			collaboratorId: 2,
+			input:          entity.UpdateCollaboratorInput{Role: entity.ListRoleEditor},
+			role:           entity.ListRoleViewer,
+			expectedErr:    utils.ErrListPermissionDenied,
+		},
+		{
+			name:           "Last owner cannot be demoted",
+			collaboratorId: 1,
+			input:          entity.UpdateCollaboratorInput{Role: entity.ListRoleEditor},
+			role:           entity.ListRoleOwner,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleEditor},
+			},
+			expectedErr: utils.ErrLastListOwner,
+		},
+		{
+			name:           "Owner can be demoted when another owner remains",
+			collaboratorId: 1,
+			input:          entity.UpdateCollaboratorInput{Role: entity.ListRoleEditor},
+			role:           entity.ListRoleOwner,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleOwner},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockRepo := new(MockListRepo)
+		mockListSearch := new(MockListSearch)
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo.On("GetUserListRole", mock.Anything, 1, 1).Return(testCase.role, nil)
+			mockRepo.On("GetCollaborators", mock.Anything, 1).Return(testCase.collaborators, nil)
+			mockRepo.On("UpdateCollaboratorRole", mock.Anything, 1, testCase.collaboratorId, testCase.input.Role).Return(nil)
+
+			err := listUseCase.UpdateCollaborator(context.Background(), 1, 1, testCase.collaboratorId, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+// TestRemoveCollaborator tests the RemoveCollaborator function in the ListUseCase
+func TestRemoveCollaborator(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userId         int
+		collaboratorId int
+		role           string
+		collaborators  []entity.ListCollaborator
+		expectedErr    error
+	}{
+		{
+			name:           "Owner removes collaborator",
+			userId:         1,
+			collaboratorId: 2,
+			role:           entity.ListRoleOwner,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleViewer},
+			},
+		},
+		{
+			name:           "Viewer leaves list",
+			userId:         2,
+			collaboratorId: 2,
+			role:           entity.ListRoleViewer,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleViewer},
+			},
+		},
+		{
+			name:           "Editor cannot remove others",
+			userId:         2,
+			collaboratorId: 3,
+			role:           entity.ListRoleEditor,
+			expectedErr:    utils.ErrListPermissionDenied,
+		},
+		{
+			name:           "Last owner cannot leave",
+			userId:         1,
+			collaboratorId: 1,
+			role:           entity.ListRoleOwner,
+			collaborators: []entity.ListCollaborator{
+				{UserId: 1, Role: entity.ListRoleOwner},
+				{UserId: 2, Role: entity.ListRoleEditor},
+			},
+			expectedErr: utils.ErrLastListOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockRepo := new(MockListRepo)
+		mockListSearch := new(MockListSearch)
+		listUseCase := usecase.NewListUseCase(mockRepo, mockListSearch, &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo.On("GetUserListRole", mock.Anything, testCase.userId, 1).Return(testCase.role, nil)
+			mockRepo.On("GetCollaborators", mock.Anything, 1).Return(testCase.collaborators, nil)
+			mockRepo.On("RemoveCollaborator", mock.Anything, 1, testCase.collaboratorId).Return(nil)
+
+			err := listUseCase.RemoveCollaborator(context.Background(), testCase.userId, 1, testCase.collaboratorId)
+
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+// TestMoveList tests moving a list among the lists of the user
+func TestMoveList(t *testing.T) {
+	beforeId := 4
+
+	testCases := []struct {
+		name        string
+		input       entity.MoveInput
+		role        string
+		roleErr     error
+		expectedErr error
+	}{
+		{
+			name:  "Viewer orders shared list",
+			input: entity.MoveInput{BeforeId: &beforeId},
+			role:  entity.ListRoleViewer,
+		},
+		{
+			name:        "Missing anchor",
+			input:       entity.MoveInput{},
+			expectedErr: utils.ErrMoveAnchorRequired,
+		},
+		{
+			name:        "List not shared with the user",
+			input:       entity.MoveInput{BeforeId: &beforeId},
+			roleErr:     utils.ErrUserNotOwner,
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mockRepo := new(MockListRepo)
+		listUseCase := usecase.NewListUseCase(mockRepo, new(MockListSearch), &logger.NoOpLogger{})
+
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if testCase.role != "" || testCase.roleErr != nil {
+				mockRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(testCase.role, testCase.roleErr)
+			}
+			if testCase.role != "" {
+				mockRepo.On("MoveUserList", mock.Anything, 1, 2, testCase.input).Return(nil)
+			}
+
+			err := listUseCase.Move(context.Background(), 1, 2, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCloneList tests the Clone function in the ListUseCase
+func TestCloneList(t *testing.T) {
+	blank := "  "
+	title := " Release 2.0 "
+	trimmed := "Release 2.0"
+	listCopy := entity.ListCopy{
+		List:  entity.List{Id: 9, Title: "Release 2.0"},
+		Items: []entity.Item{{Id: 20, Title: "Build", Done: true}},
+	}
+
+	testCases := []struct {
+		name         string
+		input        entity.CloneListInput
+		mockBehavior func(mockRepo *MockListRepo)
+		expectedCopy entity.ListCopy
+		expectedErr  error
+	}{
+		{
+			name:  "Viewer clones shared list",
+			input: entity.CloneListInput{Title: &title},
+			mockBehavior: func(mockRepo *MockListRepo) {
+				mockRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleViewer, nil)
+				mockRepo.On("CloneUserList", mock.Anything, 1, 2, entity.CloneListInput{Title: &trimmed}).Return(listCopy, nil)
+			},
+			expectedCopy: listCopy,
+		},
+		{
+			name:         "Blank title",
+			input:        entity.CloneListInput{Title: &blank},
+			mockBehavior: func(mockRepo *MockListRepo) {},
+			expectedErr:  utils.ErrInvalidListTitle,
+		},
+		{
+			name: "List not shared with the user",
+			mockBehavior: func(mockRepo *MockListRepo) {
+				mockRepo.On("GetUserListRole", mock.Anything, 1, 2).Return("", utils.ErrUserNotOwner)
+			},
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockListRepo)
+			listUseCase := usecase.NewListUseCase(mockRepo, new(MockListSearch), &logger.NoOpLogger{})
+
+			testCase.mockBehavior(mockRepo)
+
+			result, err := listUseCase.Clone(context.Background(), 1, 2, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedCopy, result)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateListTemplate tests the CreateTemplate function in the ListUseCase
+func TestCreateListTemplate(t *testing.T) {
+	template := entity.ListTemplate{Id: 3, UserId: 1, Title: "Release", ItemCount: 2}
+
+	testCases := []struct {
+		name             string
+		mockBehavior     func(mockRepo *MockListRepo)
+		expectedTemplate entity.ListTemplate
+		expectedErr      error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRepo *MockListRepo) {
+				mockRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleViewer, nil)
+				mockRepo.On("CreateTemplate", mock.Anything, 1, 2, entity.CreateListTemplateInput{}).Return(template, nil)
+			},
+			expectedTemplate: template,
+		},
+		{
+			name: "List not shared with the user",
+			mockBehavior: func(mockRepo *MockListRepo) {
+				mockRepo.On("GetUserListRole", mock.Anything, 1, 2).Return("", utils.ErrUserNotOwner)
+			},
+			expectedErr: utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockListRepo)
+			listUseCase := usecase.NewListUseCase(mockRepo, new(MockListSearch), &logger.NoOpLogger{})
+
+			testCase.mockBehavior(mockRepo)
+
+			result, err := listUseCase.CreateTemplate(context.Background(), 1, 2, entity.CreateListTemplateInput{})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedTemplate, result)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCreateListFromTemplate tests the CreateFromTemplate function in the ListUseCase
+func TestCreateListFromTemplate(t *testing.T) {
+	listCopy := entity.ListCopy{
+		List:  entity.List{Id: 9, Title: "Onboarding"},
+		Items: []entity.Item{{Id: 20, Title: "Laptop"}},
+	}
+
+	testCases := []struct {
+		name         string
+		repoErr      error
+		expectedCopy entity.ListCopy
+		expectedErr  error
+	}{
+		{
+			name:         "Success",
+			expectedCopy: listCopy,
+		},
+		{
+			name:        "Template of another user",
+			repoErr:     utils.ErrListTemplateNotFound,
+			expectedErr: utils.ErrListTemplateNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockListRepo)
+			listUseCase := usecase.NewListUseCase(mockRepo, new(MockListSearch), &logger.NoOpLogger{})
+
+			mockRepo.On("CreateUserListFromTemplate", mock.Anything, 1, 3, entity.UseListTemplateInput{}).
+				Return(testCase.expectedCopy, testCase.repoErr)
+
+			result, err := listUseCase.CreateFromTemplate(context.Background(), 1, 3, entity.UseListTemplateInput{})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedCopy, result)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
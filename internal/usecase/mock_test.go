@@ -2,8 +2,14 @@ package usecase_test
 
 import (
 	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/search"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -19,21 +25,45 @@ func (m *MockAuthRepo) CreateUser(ctx context.Context, user entity.User) (int, e
 	return args.Int(0), args.Error(1)
 }
 
-// GetUserByCredentials mocks retrieving a user by credentials
-func (m *MockAuthRepo) GetUserByCredentials(ctx context.Context, username string, password string) (entity.User, error) {
-	args := m.Called(ctx, username, password)
+// GetUserByUsername mocks retrieving a user by username
+func (m *MockAuthRepo) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
+	args := m.Called(ctx, username)
 	return args.Get(0).(entity.User), args.Error(1)
 }
 
+// GetUserRole mocks retrieving the current role of a user
+func (m *MockAuthRepo) GetUserRole(ctx context.Context, userId int) (string, error) {
+	args := m.Called(ctx, userId)
+	return args.String(0), args.Error(1)
+}
+
+// UpdatePasswordHash mocks replacing the stored password hash of a user
+func (m *MockAuthRepo) UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error {
+	args := m.Called(ctx, userId, passwordHash)
+	return args.Error(0)
+}
+
 // Mocking the hasher
 type MockHasher struct {
 	mock.Mock
 }
 
-// HashPassword mocks hashing a password
-func (m *MockHasher) HashPassword(password string) string {
+// Hash mocks hashing a password
+func (m *MockHasher) Hash(password string) (string, error) {
 	args := m.Called(password)
-	return args.String(0)
+	return args.String(0), args.Error(1)
+}
+
+// Verify mocks verifying a password against an encoded hash
+func (m *MockHasher) Verify(password, encodedHash string) (bool, error) {
+	args := m.Called(password, encodedHash)
+	return args.Bool(0), args.Error(1)
+}
+
+// NeedsRehash mocks checking whether an encoded hash is outdated
+func (m *MockHasher) NeedsRehash(encodedHash string) bool {
+	args := m.Called(encodedHash)
+	return args.Bool(0)
 }
 
 // Mocking the token maker
@@ -42,17 +72,158 @@ type MockTokenMaker struct {
 }
 
 // CreateToken mocks creating a token for a user
-func (m *MockTokenMaker) CreateToken(userId int) (string, error) {
-	args := m.Called(userId)
-	return args.String(0), args.Error(1)
+func (m *MockTokenMaker) CreateToken(userId int, role string) (string, *token.Payload, error) {
+	args := m.Called(userId, role)
+	return args.String(0), args.Get(1).(*token.Payload), args.Error(2)
 }
 
-// VerifyToken mocks verifying a token and returning the associated user Id
-func (m *MockTokenMaker) VerifyToken(tokenString string) (int, error) {
+// VerifyToken mocks verifying a token and returning its payload
+func (m *MockTokenMaker) VerifyToken(tokenString string) (*token.Payload, error) {
 	args := m.Called(tokenString)
+	return args.Get(0).(*token.Payload), args.Error(1)
+}
+
+// CreateRefreshToken mocks creating a refresh token
+func (m *MockTokenMaker) CreateRefreshToken() (string, time.Time, error) {
+	args := m.Called()
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+// JWKS mocks returning the public signing keys
+func (m *MockTokenMaker) JWKS() token.JWKS {
+	args := m.Called()
+	return args.Get(0).(token.JWKS)
+}
+
+// MockTokenRepo mocks the repository.Token interface
+type MockTokenRepo struct {
+	mock.Mock
+}
+
+// CreateRefreshToken mocks storing a refresh token
+func (m *MockTokenRepo) CreateRefreshToken(ctx context.Context, refreshToken *entity.RefreshToken) error {
+	args := m.Called(ctx, refreshToken)
+	return args.Error(0)
+}
+
+// GetRefreshTokenByHash mocks retrieving a refresh token by its hash
+func (m *MockTokenRepo) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (entity.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(entity.RefreshToken), args.Error(1)
+}
+
+// RotateRefreshToken mocks replacing a refresh token with a new one
+func (m *MockTokenRepo) RotateRefreshToken(ctx context.Context, oldTokenId int, newToken *entity.RefreshToken) error {
+	args := m.Called(ctx, oldTokenId, newToken)
+	return args.Error(0)
+}
+
+// RevokeRefreshTokenFamily mocks revoking a refresh token family
+func (m *MockTokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyId string) error {
+	args := m.Called(ctx, familyId)
+	return args.Error(0)
+}
+
+// RevokeAccessToken mocks adding an access token to the denylist
+func (m *MockTokenRepo) RevokeAccessToken(ctx context.Context, tokenId string, ttl time.Duration) error {
+	args := m.Called(ctx, tokenId, ttl)
+	return args.Error(0)
+}
+
+// IsAccessTokenRevoked mocks checking the access token denylist
+func (m *MockTokenRepo) IsAccessTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	args := m.Called(ctx, tokenId)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockPersonalAccessTokenRepo mocks the repository.PersonalAccessToken interface
+type MockPersonalAccessTokenRepo struct {
+	mock.Mock
+}
+
+// Create mocks storing a personal access token
+func (m *MockPersonalAccessTokenRepo) Create(ctx context.Context, accessToken *entity.PersonalAccessToken) (int, error) {
+	args := m.Called(ctx, accessToken)
 	return args.Int(0), args.Error(1)
 }
 
+// GetByHash mocks retrieving a personal access token by its hash
+func (m *MockPersonalAccessTokenRepo) GetByHash(ctx context.Context, tokenHash string) (entity.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(entity.PersonalAccessToken), args.Error(1)
+}
+
+// GetAllByUserId mocks listing the personal access tokens of a user
+func (m *MockPersonalAccessTokenRepo) GetAllByUserId(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.PersonalAccessToken), args.Error(1)
+}
+
+// Revoke mocks revoking a personal access token
+func (m *MockPersonalAccessTokenRepo) Revoke(ctx context.Context, userId, tokenId int) error {
+	args := m.Called(ctx, userId, tokenId)
+	return args.Error(0)
+}
+
+// UpdateLastUsed mocks recording the last use of a personal access token
+func (m *MockPersonalAccessTokenRepo) UpdateLastUsed(ctx context.Context, tokenId int) error {
+	args := m.Called(ctx, tokenId)
+	return args.Error(0)
+}
+
+// MockAppRepo mocks the repository.App interface
+type MockAppRepo struct {
+	mock.Mock
+}
+
+// Create mocks registering an app
+func (m *MockAppRepo) Create(ctx context.Context, app *entity.App) (int, error) {
+	args := m.Called(ctx, app)
+	return args.Int(0), args.Error(1)
+}
+
+// CreateIfNotExists mocks registering an app unless it exists
+func (m *MockAppRepo) CreateIfNotExists(ctx context.Context, app *entity.App) error {
+	args := m.Called(ctx, app)
+	return args.Error(0)
+}
+
+// GetAll mocks listing every app
+func (m *MockAppRepo) GetAll(ctx context.Context) ([]entity.App, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.App), args.Error(1)
+}
+
+// GetOneByAppId mocks retrieving an app by its app id
+func (m *MockAppRepo) GetOneByAppId(ctx context.Context, appId string) (entity.App, error) {
+	args := m.Called(ctx, appId)
+	return args.Get(0).(entity.App), args.Error(1)
+}
+
+// UpdateOneByAppId mocks updating an app
+func (m *MockAppRepo) UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error {
+	args := m.Called(ctx, appId, input)
+	return args.Error(0)
+}
+
+// RotateKey mocks replacing the key of an app
+func (m *MockAppRepo) RotateKey(ctx context.Context, appId, newKeyHash string, previousKeyExpiresAt time.Time) error {
+	args := m.Called(ctx, appId, newKeyHash, previousKeyExpiresAt)
+	return args.Error(0)
+}
+
+// DeleteOneByAppId mocks deleting an app
+func (m *MockAppRepo) DeleteOneByAppId(ctx context.Context, appId string) error {
+	args := m.Called(ctx, appId)
+	return args.Error(0)
+}
+
+// UpdateLastUsed mocks recording the last use of an app
+func (m *MockAppRepo) UpdateLastUsed(ctx context.Context, appId string) error {
+	args := m.Called(ctx, appId)
+	return args.Error(0)
+}
+
 // Mocking the repository.List interface
 type MockListRepo struct {
 	mock.Mock
@@ -64,15 +235,22 @@ func (m *MockListRepo) CreateUserList(ctx context.Context, userId int, list *ent
 	return args.Int(0), args.Error(1)
 }
 
-// Implementing the GetAll method
-func (m *MockListRepo) GetAllUserLists(ctx context.Context, userId int) ([]entity.List, error) {
-	args := m.Called(ctx, userId)
-	return args.Get(0).([]entity.List), args.Error(1)
+// Implementing the GetUserListsPage method
+func (m *MockListRepo) GetUserListsPage(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error) {
+	args := m.Called(ctx, userId, query)
+	return args.Get(0).(entity.ListPage), args.Error(1)
 }
 
-func (m *MockListRepo) IsUserOwnerOfList(ctx context.Context, userId, listId int) error {
+// GetUserListRole mocks retrieving the role of a user on a list
+func (m *MockListRepo) GetUserListRole(ctx context.Context, userId, listId int) (string, error) {
 	args := m.Called(ctx, userId, listId)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
+}
+
+// GetUserListIds mocks retrieving the ids of the lists shared with a user
+func (m *MockListRepo) GetUserListIds(ctx context.Context, userId int) ([]int, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]int), args.Error(1)
 }
 
 // Implementing the GetOneById method
@@ -82,44 +260,140 @@ func (m *MockListRepo) GetOneById(ctx context.Context, listId int) (entity.List,
 }
 
 // Implementing the UpdateOneById method
-func (m *MockListRepo) UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput) error {
-	args := m.Called(ctx, userId, listId, newTodoInput)
+func (m *MockListRepo) UpdateOneById(ctx context.Context, userId *int, listId int, newTodoInput entity.UpdateListInput, version *int) error {
+	args := m.Called(ctx, userId, listId, newTodoInput, version)
+	return args.Error(0)
+}
+
+// RevertOneById mocks setting a list back to the state of a revision
+func (m *MockListRepo) RevertOneById(ctx context.Context, userId *int, listId int, state entity.ListState) error {
+	args := m.Called(ctx, userId, listId, state)
 	return args.Error(0)
 }
 
 // Implementing the DeleteOneById method
-func (m *MockListRepo) DeleteOneById(ctx context.Context, userId *int, listId int) error {
+func (m *MockListRepo) DeleteOneById(ctx context.Context, userId *int, listId int, version *int) error {
+	args := m.Called(ctx, userId, listId, version)
+	return args.Error(0)
+}
+
+// GetTrashedUserLists mocks retrieving the lists of a user in the trash
+func (m *MockListRepo) GetTrashedUserLists(ctx context.Context, userId int) ([]entity.TrashedList, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.TrashedList), args.Error(1)
+}
+
+// RestoreUserList mocks restoring a list from the trash
+func (m *MockListRepo) RestoreUserList(ctx context.Context, userId, listId int) error {
 	args := m.Called(ctx, userId, listId)
 	return args.Error(0)
 }
 
+// PurgeTrash mocks purging the lists in the trash
+func (m *MockListRepo) PurgeTrash(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(entity.PurgedTrash), args.Error(1)
+}
+
+// CloneUserList mocks copying a list with its items into a new list of the user
+func (m *MockListRepo) CloneUserList(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.ListCopy), args.Error(1)
+}
+
+// CreateUserListFromTemplate mocks creating a list of the user from a template
+func (m *MockListRepo) CreateUserListFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error) {
+	args := m.Called(ctx, userId, templateId, input)
+	return args.Get(0).(entity.ListCopy), args.Error(1)
+}
+
+// CreateTemplate mocks saving a list as a template
+func (m *MockListRepo) CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error) {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Get(0).(entity.ListTemplate), args.Error(1)
+}
+
+// GetUserTemplates mocks retrieving the templates of a user
+func (m *MockListRepo) GetUserTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.ListTemplate), args.Error(1)
+}
+
+// GetUserTemplate mocks retrieving a template of a user
+func (m *MockListRepo) GetUserTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error) {
+	args := m.Called(ctx, userId, templateId)
+	return args.Get(0).(entity.ListTemplate), args.Error(1)
+}
+
+// DeleteUserTemplate mocks deleting a template of a user
+func (m *MockListRepo) DeleteUserTemplate(ctx context.Context, userId, templateId int) error {
+	args := m.Called(ctx, userId, templateId)
+	return args.Error(0)
+}
+
 // GetManyByIds mocks retrieving multiple lists by their Ids
 func (m *MockListRepo) GetManyByIds(ctx context.Context, ids []int) ([]entity.List, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]entity.List), args.Error(1)
 }
 
+// GetCollaborators mocks retrieving the users a list is shared with
+func (m *MockListRepo) GetCollaborators(ctx context.Context, listId int) ([]entity.ListCollaborator, error) {
+	args := m.Called(ctx, listId)
+	return args.Get(0).([]entity.ListCollaborator), args.Error(1)
+}
+
+// AddCollaborator mocks sharing a list with a user
+func (m *MockListRepo) AddCollaborator(ctx context.Context, listId int, username, role string) (entity.ListCollaborator, error) {
+	args := m.Called(ctx, listId, username, role)
+	return args.Get(0).(entity.ListCollaborator), args.Error(1)
+}
+
+// UpdateCollaboratorRole mocks changing the role of a user on a list
+func (m *MockListRepo) UpdateCollaboratorRole(ctx context.Context, listId, userId int, role string) error {
+	args := m.Called(ctx, listId, userId, role)
+	return args.Error(0)
+}
+
+// RemoveCollaborator mocks removing a user from a list
+func (m *MockListRepo) RemoveCollaborator(ctx context.Context, listId, userId int) error {
+	args := m.Called(ctx, listId, userId)
+	return args.Error(0)
+}
+
+// MoveUserList mocks moving a list among the lists of a user
+func (m *MockListRepo) MoveUserList(ctx context.Context, userId, listId int, input entity.MoveInput) error {
+	args := m.Called(ctx, userId, listId, input)
+	return args.Error(0)
+}
+
 // Mocking the repository.Item interface
 type MockItemRepo struct {
 	mock.Mock
 }
 
 // CreateListItem mocks creating an item in a list
-func (m *MockItemRepo) CreateListItem(ctx context.Context, listId int, item *entity.Item) (int, error) {
-	args := m.Called(ctx, listId, item)
+func (m *MockItemRepo) CreateListItem(ctx context.Context, userId, listId int, item *entity.Item) (int, error) {
+	args := m.Called(ctx, userId, listId, item)
 	return args.Int(0), args.Error(1)
 }
 
-// GetAllListItems mocks retrieving all items in a list
-func (m *MockItemRepo) GetAllListItems(ctx context.Context, listId int) ([]entity.Item, error) {
-	args := m.Called(ctx, listId)
-	return args.Get(0).([]entity.Item), args.Error(1)
+// GetListItemsPage mocks retrieving a page of the items in a list
+func (m *MockItemRepo) GetListItemsPage(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error) {
+	args := m.Called(ctx, userId, listId, query)
+	return args.Get(0).(entity.ItemPage), args.Error(1)
 }
 
-// IsUserOwnerOfItem mocks checking if a user owns a specific item
-func (m *MockItemRepo) IsUserOwnerOfItem(ctx context.Context, userId, itemId int) error {
+// GetUserItemRole mocks retrieving the role of a user on the list holding an item
+func (m *MockItemRepo) GetUserItemRole(ctx context.Context, userId, itemId int) (string, error) {
 	args := m.Called(ctx, userId, itemId)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
+}
+
+// GetUserItemsAccess mocks retrieving the roles of a user on the lists holding items
+func (m *MockItemRepo) GetUserItemsAccess(ctx context.Context, userId int, itemIds []int) ([]entity.ItemAccess, error) {
+	args := m.Called(ctx, userId, itemIds)
+	return args.Get(0).([]entity.ItemAccess), args.Error(1)
 }
 
 // GetOneById mocks retrieving an item by its Id
@@ -129,23 +403,361 @@ func (m *MockItemRepo) GetOneById(ctx context.Context, itemId int) (entity.Item,
 }
 
 // UpdateOneById mocks updating an item by its Id
-func (m *MockItemRepo) UpdateOneById(ctx context.Context, listId *int, itemId int, input entity.UpdateItemInput) error {
-	args := m.Called(ctx, listId, itemId, input)
+func (m *MockItemRepo) UpdateOneById(ctx context.Context, userId int, listId *int, itemId int, input entity.UpdateItemInput, series *entity.ItemSeriesChange, version *int) error {
+	args := m.Called(ctx, userId, listId, itemId, input, series, version)
 	return args.Error(0)
 }
 
-// DeleteOneById mocks deleting an item by its Id
-func (m *MockItemRepo) DeleteOneById(ctx context.Context, listId *int, itemId int) error {
-	args := m.Called(ctx, listId, itemId)
+// UpdateManyByIds mocks updating many items by their Ids
+func (m *MockItemRepo) UpdateManyByIds(ctx context.Context, userId int, itemIds []int, input entity.UpdateItemInput) ([]int, error) {
+	args := m.Called(ctx, userId, itemIds, input)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// RevertOneById mocks setting an item back to the state of a revision
+func (m *MockItemRepo) RevertOneById(ctx context.Context, userId, listId, itemId int, state entity.ItemState) error {
+	args := m.Called(ctx, userId, listId, itemId, state)
 	return args.Error(0)
 }
 
+// DeleteOneById mocks moving an item to the trash
+func (m *MockItemRepo) DeleteOneById(ctx context.Context, itemId int, version *int) ([]int, error) {
+	args := m.Called(ctx, itemId, version)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// DeleteManyByIds mocks moving many items to the trash
+func (m *MockItemRepo) DeleteManyByIds(ctx context.Context, itemIds []int) ([]int, error) {
+	args := m.Called(ctx, itemIds)
+	return args.Get(0).([]int), args.Error(1)
+}
+
 // GetManyByIds mocks retrieving multiple items by their Ids
 func (m *MockItemRepo) GetManyByIds(ctx context.Context, itemIds []int) ([]entity.Item, error) {
 	args := m.Called(ctx, itemIds)
 	return args.Get(0).([]entity.Item), args.Error(1)
 }
 
+// ClaimDueReminders mocks claiming the due reminders
+func (m *MockItemRepo) ClaimDueReminders(ctx context.Context, limit int) ([]entity.ItemReminderDueEvent, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]entity.ItemReminderDueEvent), args.Error(1)
+}
+
+// GetSeries mocks retrieving the series of a recurring item
+func (m *MockItemRepo) GetSeries(ctx context.Context, seriesId int) (entity.ItemSeries, error) {
+	args := m.Called(ctx, seriesId)
+	return args.Get(0).(entity.ItemSeries), args.Error(1)
+}
+
+// CreateNextOccurrence mocks creating the next occurrence of a series
+func (m *MockItemRepo) CreateNextOccurrence(ctx context.Context, userId, previousItemId int, item *entity.Item) (int, int, error) {
+	args := m.Called(ctx, userId, previousItemId, item)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+// GetSubtasks mocks retrieving the direct subtasks of an item
+func (m *MockItemRepo) GetSubtasks(ctx context.Context, parentId int) ([]entity.Item, error) {
+	args := m.Called(ctx, parentId)
+	return args.Get(0).([]entity.Item), args.Error(1)
+}
+
+// SetParent mocks moving an item under another item
+func (m *MockItemRepo) SetParent(ctx context.Context, userId, listId, itemId int, parentId *int) error {
+	args := m.Called(ctx, userId, listId, itemId, parentId)
+	return args.Error(0)
+}
+
+// MoveItem mocks moving an item within its list
+func (m *MockItemRepo) MoveItem(ctx context.Context, listId, itemId int, input entity.MoveInput) error {
+	args := m.Called(ctx, listId, itemId, input)
+	return args.Error(0)
+}
+
+// MoveToList mocks moving items to another list
+func (m *MockItemRepo) MoveToList(ctx context.Context, userId, listId int, itemIds []int) ([]int, error) {
+	args := m.Called(ctx, userId, listId, itemIds)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// CopyToList mocks copying items to another list
+func (m *MockItemRepo) CopyToList(ctx context.Context, userId, listId int, itemIds []int) ([]entity.ItemCopy, error) {
+	args := m.Called(ctx, userId, listId, itemIds)
+	return args.Get(0).([]entity.ItemCopy), args.Error(1)
+}
+
+// CompleteSubtasks mocks completing the subtasks of an item
+func (m *MockItemRepo) CompleteSubtasks(ctx context.Context, userId, listId, itemId int) ([]int, error) {
+	args := m.Called(ctx, userId, listId, itemId)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// GetTrashedListItems mocks retrieving the items of a list in the trash
+func (m *MockItemRepo) GetTrashedListItems(ctx context.Context, listId int) ([]entity.TrashedItem, error) {
+	args := m.Called(ctx, listId)
+	return args.Get(0).([]entity.TrashedItem), args.Error(1)
+}
+
+// RestoreItem mocks restoring an item from the trash
+func (m *MockItemRepo) RestoreItem(ctx context.Context, userId, listId, itemId int) ([]int, error) {
+	args := m.Called(ctx, userId, listId, itemId)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// PurgeTrash mocks purging the items in the trash
+func (m *MockItemRepo) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// MockInvitationRepo mocks the repository.Invitation interface
+type MockInvitationRepo struct {
+	mock.Mock
+}
+
+// Create mocks storing an invitation
+func (m *MockInvitationRepo) Create(ctx context.Context, invitation *entity.Invitation) (int, error) {
+	args := m.Called(ctx, invitation)
+	return args.Int(0), args.Error(1)
+}
+
+// GetOneById mocks retrieving an invitation by its Id
+func (m *MockInvitationRepo) GetOneById(ctx context.Context, invitationId int) (entity.Invitation, error) {
+	args := m.Called(ctx, invitationId)
+	return args.Get(0).(entity.Invitation), args.Error(1)
+}
+
+// GetByTokenHash mocks retrieving a link invitation by the hash of its token
+func (m *MockInvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (entity.Invitation, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(entity.Invitation), args.Error(1)
+}
+
+// GetPendingByListId mocks retrieving the pending invitations of a list
+func (m *MockInvitationRepo) GetPendingByListId(ctx context.Context, listId int) ([]entity.Invitation, error) {
+	args := m.Called(ctx, listId)
+	return args.Get(0).([]entity.Invitation), args.Error(1)
+}
+
+// GetPendingByInviteeId mocks retrieving the pending invitations addressed to a user
+func (m *MockInvitationRepo) GetPendingByInviteeId(ctx context.Context, userId int) ([]entity.Invitation, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.Invitation), args.Error(1)
+}
+
+// Accept mocks accepting an invitation
+func (m *MockInvitationRepo) Accept(ctx context.Context, invitationId, userId int) error {
+	args := m.Called(ctx, invitationId, userId)
+	return args.Error(0)
+}
+
+// UpdateStatus mocks moving an invitation to a final status
+func (m *MockInvitationRepo) UpdateStatus(ctx context.Context, invitationId int, status string) error {
+	args := m.Called(ctx, invitationId, status)
+	return args.Error(0)
+}
+
+// MockTagRepo mocks the repository.Tag interface
+type MockTagRepo struct {
+	mock.Mock
+}
+
+// Create mocks storing a tag
+func (m *MockTagRepo) Create(ctx context.Context, tag *entity.Tag) (int, error) {
+	args := m.Called(ctx, tag)
+	return args.Int(0), args.Error(1)
+}
+
+// GetAllByUserId mocks retrieving the tags of a user
+func (m *MockTagRepo) GetAllByUserId(ctx context.Context, userId int) ([]entity.Tag, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+// GetOneById mocks retrieving a tag of a user by its Id
+func (m *MockTagRepo) GetOneById(ctx context.Context, userId, tagId int) (entity.Tag, error) {
+	args := m.Called(ctx, userId, tagId)
+	return args.Get(0).(entity.Tag), args.Error(1)
+}
+
+// GetAllByItemId mocks retrieving the tags of a user attached to an item
+func (m *MockTagRepo) GetAllByItemId(ctx context.Context, userId, itemId int) ([]entity.Tag, error) {
+	args := m.Called(ctx, userId, itemId)
+	return args.Get(0).([]entity.Tag), args.Error(1)
+}
+
+// GetIdsByItemId mocks retrieving the Ids of the tags of every user attached to an item
+func (m *MockTagRepo) GetIdsByItemId(ctx context.Context, itemId int) ([]int, error) {
+	args := m.Called(ctx, itemId)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// GetIdsByNames mocks resolving tag names of a user to their Ids
+func (m *MockTagRepo) GetIdsByNames(ctx context.Context, userId int, names []string) ([]int, error) {
+	args := m.Called(ctx, userId, names)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// UpdateOneById mocks updating a tag of a user
+func (m *MockTagRepo) UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error {
+	args := m.Called(ctx, userId, tagId, input)
+	return args.Error(0)
+}
+
+// DeleteOneById mocks deleting a tag of a user
+func (m *MockTagRepo) DeleteOneById(ctx context.Context, userId, tagId int) error {
+	args := m.Called(ctx, userId, tagId)
+	return args.Error(0)
+}
+
+// AttachToItem mocks attaching a tag to an item
+func (m *MockTagRepo) AttachToItem(ctx context.Context, userId, listId, tagId, itemId int) error {
+	args := m.Called(ctx, userId, listId, tagId, itemId)
+	return args.Error(0)
+}
+
+// DetachFromItem mocks detaching a tag of a user from an item
+func (m *MockTagRepo) DetachFromItem(ctx context.Context, userId, listId, tagId, itemId int) error {
+	args := m.Called(ctx, userId, listId, tagId, itemId)
+	return args.Error(0)
+}
+
+// MockRevisionRepo mocks the repository.Revision interface for revision operations
+type MockRevisionRepo struct {
+	mock.Mock
+}
+
+// GetItemRevisions mocks retrieving the revisions of an item
+func (m *MockRevisionRepo) GetItemRevisions(ctx context.Context, itemId int) ([]entity.ItemRevision, error) {
+	args := m.Called(ctx, itemId)
+	return args.Get(0).([]entity.ItemRevision), args.Error(1)
+}
+
+// GetItemRevision mocks retrieving a revision of an item
+func (m *MockRevisionRepo) GetItemRevision(ctx context.Context, itemId, revisionId int) (entity.ItemRevision, error) {
+	args := m.Called(ctx, itemId, revisionId)
+	return args.Get(0).(entity.ItemRevision), args.Error(1)
+}
+
+// GetListRevisions mocks retrieving the revisions of a list
+func (m *MockRevisionRepo) GetListRevisions(ctx context.Context, listId int) ([]entity.ListRevision, error) {
+	args := m.Called(ctx, listId)
+	return args.Get(0).([]entity.ListRevision), args.Error(1)
+}
+
+// GetListRevision mocks retrieving a revision of a list
+func (m *MockRevisionRepo) GetListRevision(ctx context.Context, listId, revisionId int) (entity.ListRevision, error) {
+	args := m.Called(ctx, listId, revisionId)
+	return args.Get(0).(entity.ListRevision), args.Error(1)
+}
+
+// MockCommentRepo mocks the repository.Comment interface for comment operations
+type MockCommentRepo struct {
+	mock.Mock
+}
+
+// Create mocks storing a new comment
+func (m *MockCommentRepo) Create(ctx context.Context, comment *entity.Comment) (int, error) {
+	args := m.Called(ctx, comment)
+	return args.Int(0), args.Error(1)
+}
+
+// GetOneById mocks retrieving a comment of an item
+func (m *MockCommentRepo) GetOneById(ctx context.Context, itemId, commentId int) (entity.Comment, error) {
+	args := m.Called(ctx, itemId, commentId)
+	return args.Get(0).(entity.Comment), args.Error(1)
+}
+
+// GetPage mocks retrieving a page of the comments of an item
+func (m *MockCommentRepo) GetPage(ctx context.Context, itemId int, query entity.CommentQuery) (entity.CommentPage, error) {
+	args := m.Called(ctx, itemId, query)
+	return args.Get(0).(entity.CommentPage), args.Error(1)
+}
+
+// UpdateBody mocks replacing the body of a comment
+func (m *MockCommentRepo) UpdateBody(ctx context.Context, itemId, commentId int, body string) error {
+	args := m.Called(ctx, itemId, commentId, body)
+	return args.Error(0)
+}
+
+// DeleteOneById mocks deleting a comment
+func (m *MockCommentRepo) DeleteOneById(ctx context.Context, itemId, commentId int) error {
+	args := m.Called(ctx, itemId, commentId)
+	return args.Error(0)
+}
+
+// MockAttachmentRepo mocks the repository.Attachment interface for attachment operations
+type MockAttachmentRepo struct {
+	mock.Mock
+}
+
+// Create mocks storing a new attachment
+func (m *MockAttachmentRepo) Create(ctx context.Context, attachment *entity.Attachment) (int, error) {
+	args := m.Called(ctx, attachment)
+	return args.Int(0), args.Error(1)
+}
+
+// GetAllByItemId mocks retrieving the attachments of an item
+func (m *MockAttachmentRepo) GetAllByItemId(ctx context.Context, itemId int) ([]entity.Attachment, error) {
+	args := m.Called(ctx, itemId)
+	return args.Get(0).([]entity.Attachment), args.Error(1)
+}
+
+// GetOneById mocks retrieving an attachment of an item
+func (m *MockAttachmentRepo) GetOneById(ctx context.Context, itemId, attachmentId int) (entity.Attachment, error) {
+	args := m.Called(ctx, itemId, attachmentId)
+	return args.Get(0).(entity.Attachment), args.Error(1)
+}
+
+// Detach mocks taking an attachment off its item
+func (m *MockAttachmentRepo) Detach(ctx context.Context, itemId, attachmentId int) (string, error) {
+	args := m.Called(ctx, itemId, attachmentId)
+	return args.String(0), args.Error(1)
+}
+
+// GetDetached mocks retrieving the detached attachments
+func (m *MockAttachmentRepo) GetDetached(ctx context.Context, limit int) ([]entity.Attachment, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]entity.Attachment), args.Error(1)
+}
+
+// DeleteDetached mocks removing a detached attachment
+func (m *MockAttachmentRepo) DeleteDetached(ctx context.Context, attachmentId int) error {
+	args := m.Called(ctx, attachmentId)
+	return args.Error(0)
+}
+
+// MockBlobStore mocks the blobstore.Interface for storing attachment content
+type MockBlobStore struct {
+	mock.Mock
+}
+
+// Put mocks storing a blob, the content is read so that tests can check what was stored
+func (m *MockBlobStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	args := m.Called(ctx, key, string(data), size, contentType)
+	return args.Error(0)
+}
+
+// Get mocks opening a blob
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+// Delete mocks deleting a blob
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
 // MockUserRepo mocks the repository.User interface for user operations
 type MockUserRepo struct {
 	mock.Mock
@@ -163,20 +775,96 @@ func (m *MockUserRepo) DeleteOneById(ctx context.Context, userId int) error {
 	return args.Error(0)
 }
 
+// UpdateRole mocks assigning a role to a user
+func (m *MockUserRepo) UpdateRole(ctx context.Context, userId int, role string) error {
+	args := m.Called(ctx, userId, role)
+	return args.Error(0)
+}
+
+// MockIdempotencyRepo mocks the repository.Idempotency interface for idempotent requests
+type MockIdempotencyRepo struct {
+	mock.Mock
+}
+
+// Reserve mocks reserving an idempotency key for a request
+func (m *MockIdempotencyRepo) Reserve(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, userId, key, request, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+// Get mocks retrieving the request stored under an idempotency key
+func (m *MockIdempotencyRepo) Get(ctx context.Context, userId int, key string) (entity.IdempotentRequest, bool, error) {
+	args := m.Called(ctx, userId, key)
+	return args.Get(0).(entity.IdempotentRequest), args.Bool(1), args.Error(2)
+}
+
+// Save mocks storing a request under an idempotency key
+func (m *MockIdempotencyRepo) Save(ctx context.Context, userId int, key string, request entity.IdempotentRequest, ttl time.Duration) error {
+	args := m.Called(ctx, userId, key, request, ttl)
+	return args.Error(0)
+}
+
+// Release mocks releasing an idempotency key
+func (m *MockIdempotencyRepo) Release(ctx context.Context, userId int, key string) error {
+	args := m.Called(ctx, userId, key)
+	return args.Error(0)
+}
+
+// MockOutboxRepo mocks the repository.Outbox interface for relaying recorded events
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+// ClaimPending mocks leasing the pending events of the outbox
+func (m *MockOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]entity.OutboxEvent), args.Error(1)
+}
+
+// MarkSent mocks marking events as published
+func (m *MockOutboxRepo) MarkSent(ctx context.Context, eventIds []int) error {
+	args := m.Called(ctx, eventIds)
+	return args.Error(0)
+}
+
+// MarkFailed mocks recording a failed attempt to publish an event
+func (m *MockOutboxRepo) MarkFailed(ctx context.Context, eventId int, lastError string, delay time.Duration) error {
+	args := m.Called(ctx, eventId, lastError, delay)
+	return args.Error(0)
+}
+
+// Release mocks making claimed events available again
+func (m *MockOutboxRepo) Release(ctx context.Context, eventIds []int, delay time.Duration) error {
+	args := m.Called(ctx, eventIds, delay)
+	return args.Error(0)
+}
+
+// GetBacklog mocks retrieving the backlog of the outbox
+func (m *MockOutboxRepo) GetBacklog(ctx context.Context) (entity.OutboxBacklog, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(entity.OutboxBacklog), args.Error(1)
+}
+
+// PurgeSent mocks deleting the events sent before the given time
+func (m *MockOutboxRepo) PurgeSent(ctx context.Context, sentBefore time.Time) (int, error) {
+	args := m.Called(ctx, sentBefore)
+	return args.Int(0), args.Error(1)
+}
+
 // MockListSearch mocks the search.List interface for searching lists
 type MockListSearch struct {
 	mock.Mock
 }
 
-// SearchIds mocks searching for list Ids based on user Id and search text
-func (m *MockListSearch) SearchIds(ctx context.Context, userId int, searchText string) ([]int, error) {
-	args := m.Called(ctx, userId, searchText)
-	return args.Get(0).([]int), args.Error(1)
+// Search mocks searching for a page of lists based on list Ids and search text
+func (m *MockListSearch) Search(ctx context.Context, listIds []int, searchText string, page search.Page) ([]search.Hit, error) {
+	args := m.Called(ctx, listIds, searchText, page)
+	return args.Get(0).([]search.Hit), args.Error(1)
 }
 
 // Index mocks indexing a list in the search service
-func (m *MockListSearch) Index(ctx context.Context, userId int, list *entity.List) error {
-	args := m.Called(ctx, userId, list)
+func (m *MockListSearch) Index(ctx context.Context, list *entity.List) error {
+	args := m.Called(ctx, list)
 	return args.Error(0)
 }
 
@@ -187,40 +875,30 @@ func (m *MockListSearch) Delete(ctx context.Context, listId int) error {
 }
 
 // MockBrokerProducer mocks the message broker producer for publishing events
-type MockBrokerProducer struct{}
-
-// PublishListCreatedEvent mocks publishing a list created event
-func (m *MockBrokerProducer) PublishListCreatedEvent(userId int, list *entity.List) {}
-
-// PublishListUpdatedEvent mocks publishing a list updated event
-func (m *MockBrokerProducer) PublishListUpdatedEvent(userId, listId int) {}
-
-// PublishListDeletedEvent mocks publishing a list deleted event
-func (m *MockBrokerProducer) PublishListDeletedEvent(listId int) {}
-
-// PublishItemCreatedEvent mocks publishing an item created event
-func (m *MockBrokerProducer) PublishItemCreatedEvent(userId, listId int, item *entity.Item) {}
-
-// PublishItemUpdatedEvent mocks publishing an item updated event
-func (m *MockBrokerProducer) PublishItemUpdatedEvent(userId, listId, itemId int) {}
+type MockBrokerProducer struct {
+	mock.Mock
+}
 
-// PublishItemDeletedEvent mocks publishing an item deleted event
-func (m *MockBrokerProducer) PublishItemDeletedEvent(itemId int) {}
+// Publish mocks publishing an encoded message to a topic
+func (m *MockBrokerProducer) Publish(topic string, message []byte) error {
+	args := m.Called(topic, message)
+	return args.Error(0)
+}
 
 // MockItemSearch mocks the search.Item interface for searching and indexing items
 type MockItemSearch struct {
 	mock.Mock
 }
 
-// SearchIds mocks searching for item IDs based on user Id, list Id, done status, and search text
-func (m *MockItemSearch) SearchIds(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]int, error) {
-	args := m.Called(ctx, userId, listId, done, searchText)
-	return args.Get(0).([]int), args.Error(1)
+// Search mocks searching for a page of items based on list Ids, done status, tags, and search text
+func (m *MockItemSearch) Search(ctx context.Context, listIds []int, done *bool, tagIds []int, searchText string, page search.Page) ([]search.Hit, error) {
+	args := m.Called(ctx, listIds, done, tagIds, searchText, page)
+	return args.Get(0).([]search.Hit), args.Error(1)
 }
 
 // Index mocks indexing an item in the search service
-func (m *MockItemSearch) Index(ctx context.Context, userId int, listId int, item entity.Item) error {
-	args := m.Called(ctx, userId, listId, item)
+func (m *MockItemSearch) Index(ctx context.Context, listId int, item entity.Item, tagIds []int) error {
+	args := m.Called(ctx, listId, item, tagIds)
 	return args.Error(0)
 }
 
@@ -229,3 +907,64 @@ func (m *MockItemSearch) Delete(ctx context.Context, itemId int) error {
 	args := m.Called(ctx, itemId)
 	return args.Error(0)
 }
+
+// fakeItemSearch keeps the indexed items in memory and matches the items of the searched lists having every
+// one of the searched tags, it lets tests follow an item from the events that index it to the searches that find it
+type fakeItemSearch struct {
+	mu      sync.Mutex
+	listIds map[int]int
+	tagIds  map[int][]int
+}
+
+// newFakeItemSearch creates an empty fakeItemSearch
+func newFakeItemSearch() *fakeItemSearch {
+	return &fakeItemSearch{listIds: map[int]int{}, tagIds: map[int][]int{}}
+}
+
+// Search returns a hit for every indexed item of the given lists having every one of the given tags
+func (f *fakeItemSearch) Search(ctx context.Context, listIds []int, done *bool, tagIds []int, searchText string, page search.Page) ([]search.Hit, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	hits := []search.Hit{}
+	for itemId, listId := range f.listIds {
+		if !slices.Contains(listIds, listId) {
+			continue
+		}
+
+		matches := true
+		for _, tagId := range tagIds {
+			if !slices.Contains(f.tagIds[itemId], tagId) {
+				matches = false
+			}
+		}
+
+		if matches {
+			hits = append(hits, search.Hit{Id: itemId, Score: 1})
+		}
+	}
+
+	return hits, nil
+}
+
+// Index records the list and the tags of the item
+func (f *fakeItemSearch) Index(ctx context.Context, listId int, item entity.Item, tagIds []int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listIds[item.Id] = listId
+	f.tagIds[item.Id] = tagIds
+
+	return nil
+}
+
+// Delete forgets the item
+func (f *fakeItemSearch) Delete(ctx context.Context, itemId int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.listIds, itemId)
+	delete(f.tagIds, itemId)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
+)
+
+// outboxLease bounds how long claimed events stay hidden from other relays, events of a relay that stops
+// before marking them sent are published again once it runs out
+const outboxLease = time.Minute
+
+// Bounds of the delay before a failed event is retried, the delay doubles with every failed attempt
+const (
+	outboxRetryBaseDelay = time.Second
+	outboxRetryMaxDelay  = 5 * time.Minute
+)
+
+// OutboxUseCase handles relaying the events recorded in the outbox to the message broker
+type OutboxUseCase struct {
+	repo           repository.Outbox
+	brokerProducer messagebroker.Producer
+}
+
+// NewOutboxUseCase creates a new instance of OutboxUseCase
+func NewOutboxUseCase(r repository.Outbox, p messagebroker.Producer) *OutboxUseCase {
+	return &OutboxUseCase{
+		repo:           r,
+		brokerProducer: p,
+	}
+}
+
+// Relay claims up to limit pending events and publishes them in the order they were recorded, the published events
+// are marked sent and their number is returned. Publishing stops at the first failure, the failed event and the rest
+// of the batch are retried after a backoff. Events recorded later are not held back meanwhile, so a retried event may
+// arrive after later events of the same entity and consumers index the current state of an entity instead of the
+// payload of its event. Events are delivered at least once, consumers may see an event again after a failure to mark it sent
+func (uc *OutboxUseCase) Relay(ctx context.Context, limit int) (int, error) {
+	events, err := uc.repo.ClaimPending(ctx, limit, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sentIds := make([]int, 0, len(events))
+	for i, event := range events {
+		if publishErr := uc.brokerProducer.Publish(event.Topic, event.Payload); publishErr != nil {
+			if err := uc.markSent(ctx, sentIds); err != nil {
+				return 0, err
+			}
+
+			if err := uc.retryLater(ctx, event, events[i+1:], publishErr); err != nil {
+				return len(sentIds), err
+			}
+
+			return len(sentIds), fmt.Errorf("failed to publish outbox event %d to %s: %w", event.Id, event.Topic, publishErr)
+		}
+
+		sentIds = append(sentIds, event.Id)
+	}
+
+	if err := uc.markSent(ctx, sentIds); err != nil {
+		return 0, err
+	}
+
+	return len(sentIds), nil
+}
+
+// GetBacklog retrieves the number of events waiting to be published and the age of the oldest of them
+func (uc *OutboxUseCase) GetBacklog(ctx context.Context) (entity.OutboxBacklog, error) {
+	return uc.repo.GetBacklog(ctx)
+}
+
+// PurgeSent permanently deletes the events sent before the given time and returns their number
+func (uc *OutboxUseCase) PurgeSent(ctx context.Context, sentBefore time.Time) (int, error) {
+	return uc.repo.PurgeSent(ctx, sentBefore)
+}
+
+// markSent marks the published events as sent, nothing is done when there are none
+func (uc *OutboxUseCase) markSent(ctx context.Context, eventIds []int) error {
+	if len(eventIds) == 0 {
+		return nil
+	}
+
+	return uc.repo.MarkSent(ctx, eventIds)
+}
+
+// retryLater records the failed attempt of the event and releases the events claimed after it, all of them become
+// available again after the backoff of the event. Pending events outside of the batch can be claimed before that
+func (uc *OutboxUseCase) retryLater(ctx context.Context, failed entity.OutboxEvent, rest []entity.OutboxEvent, publishErr error) error {
+	delay := outboxRetryDelay(failed.Attempts + 1)
+
+	if err := uc.repo.MarkFailed(ctx, failed.Id, publishErr.Error(), delay); err != nil {
+		return err
+	}
+
+	if len(rest) == 0 {
+		return nil
+	}
+
+	restIds := make([]int, 0, len(rest))
+	for _, event := range rest {
+		restIds = append(restIds, event.Id)
+	}
+
+	return uc.repo.Release(ctx, restIds, delay)
+}
+
+// outboxRetryDelay returns the delay before the next attempt to publish an event that failed the given number of times
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > outboxRetryMaxDelay {
+		return outboxRetryMaxDelay
+	}
+
+	return delay
+}
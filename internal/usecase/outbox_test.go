@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRelayOutbox tests publishing the pending events of the outbox
+func TestRelayOutbox(t *testing.T) {
+	brokerErr := errors.New("broker unavailable")
+
+	events := []entity.OutboxEvent{
+		{Id: 3, Topic: "item_created", Payload: []byte(`{"item_id":1}`)},
+		{Id: 4, Topic: "item_updated", Payload: []byte(`{"item_id":1}`), Attempts: 2},
+		{Id: 5, Topic: "item_updated", Payload: []byte(`{"item_id":2}`)},
+	}
+
+	testCases := []struct {
+		name          string
+		mockBehavior  func(mockRepo *MockOutboxRepo, mockProducer *MockBrokerProducer)
+		expectedCount int
+		expectedErr   error
+	}{
+		{
+			name: "Every event is published",
+			mockBehavior: func(mockRepo *MockOutboxRepo, mockProducer *MockBrokerProducer) {
+				mockRepo.On("ClaimPending", mock.Anything, 10, time.Minute).Return(events, nil)
+				mockProducer.On("Publish", mock.Anything, mock.Anything).Return(nil).Times(3)
+				mockRepo.On("MarkSent", mock.Anything, []int{3, 4, 5}).Return(nil)
+			},
+			expectedCount: 3,
+		},
+		{
+			name: "Nothing pending",
+			mockBehavior: func(mockRepo *MockOutboxRepo, mockProducer *MockBrokerProducer) {
+				mockRepo.On("ClaimPending", mock.Anything, 10, time.Minute).Return([]entity.OutboxEvent{}, nil)
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "Failed event holds back the rest of the batch",
+			mockBehavior: func(mockRepo *MockOutboxRepo, mockProducer *MockBrokerProducer) {
+				mockRepo.On("ClaimPending", mock.Anything, 10, time.Minute).Return(events, nil)
+				mockProducer.On("Publish", "item_created", events[0].Payload).Return(nil).Once()
+				mockProducer.On("Publish", "item_updated", events[1].Payload).Return(brokerErr).Once()
+				mockRepo.On("MarkSent", mock.Anything, []int{3}).Return(nil)
+				mockRepo.On("MarkFailed", mock.Anything, 4, "broker unavailable", 4*time.Second).Return(nil)
+				mockRepo.On("Release", mock.Anything, []int{5}, 4*time.Second).Return(nil)
+			},
+			expectedCount: 1,
+			expectedErr:   brokerErr,
+		},
+		{
+			name: "Claim failure",
+			mockBehavior: func(mockRepo *MockOutboxRepo, mockProducer *MockBrokerProducer) {
+				mockRepo.On("ClaimPending", mock.Anything, 10, time.Minute).Return([]entity.OutboxEvent(nil), errors.New("db error"))
+			},
+			expectedCount: 0,
+			expectedErr:   errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockOutboxRepo)
+			mockProducer := new(MockBrokerProducer)
+			outboxUseCase := usecase.NewOutboxUseCase(mockRepo, mockProducer)
+
+			testCase.mockBehavior(mockRepo, mockProducer)
+
+			count, err := outboxUseCase.Relay(context.Background(), 10)
+
+			assert.Equal(t, testCase.expectedCount, count)
+			if testCase.expectedErr == brokerErr {
+				assert.ErrorIs(t, err, brokerErr)
+			} else {
+				assert.Equal(t, testCase.expectedErr, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockProducer.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRelayOutboxBackoff tests that the retry delay of a failed event grows up to its bound
+func TestRelayOutboxBackoff(t *testing.T) {
+	event := entity.OutboxEvent{Id: 7, Topic: "list_updated", Payload: []byte(`{"list_id":1}`), Attempts: 20}
+
+	mockRepo := new(MockOutboxRepo)
+	mockProducer := new(MockBrokerProducer)
+	outboxUseCase := usecase.NewOutboxUseCase(mockRepo, mockProducer)
+
+	mockRepo.On("ClaimPending", mock.Anything, 10, time.Minute).Return([]entity.OutboxEvent{event}, nil)
+	mockProducer.On("Publish", "list_updated", event.Payload).Return(errors.New("timeout"))
+	mockRepo.On("MarkFailed", mock.Anything, 7, "timeout", 5*time.Minute).Return(nil)
+
+	count, err := outboxUseCase.Relay(context.Background(), 10)
+
+	assert.Equal(t, 0, count)
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+	mockProducer.AssertExpectations(t)
+}
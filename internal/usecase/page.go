@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"strconv"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/pkg/search"
+	"github.com/berikulyBeket/todo-plus/utils"
+)
+
+// searchPage returns the page of search hits selected by the query, one hit more than the limit is requested
+// to find out whether a next page exists
+func searchPage(query entity.PageQuery) (search.Page, error) {
+	page := search.Page{Size: query.Limit + 1, Descending: query.Descending()}
+
+	after, err := query.After()
+	if err != nil {
+		return search.Page{}, err
+	}
+
+	if after != nil {
+		score, err := strconv.ParseFloat(after.Key, 64)
+		if err != nil {
+			return search.Page{}, utils.ErrInvalidPageCursor
+		}
+		page.After = []interface{}{score, after.Id}
+	}
+
+	return page, nil
+}
+
+// hitIds returns the Ids of the hits on the page selected by the query and the cursor of the next page
+func hitIds(query entity.PageQuery, hits []search.Hit) ([]int, *string) {
+	var nextCursor *string
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+		last := hits[len(hits)-1]
+		nextCursor = query.NextCursor(strconv.FormatFloat(last.Score, 'g', -1, 64), last.Id)
+	}
+
+	ids := make([]int, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Id)
+	}
+
+	return ids, nextCursor
+}
+
+// orderByIds returns the rows in the order of the Ids, rows missing from the Ids are left out
+func orderByIds[T any](rows []T, ids []int, id func(T) int) []T {
+	byId := make(map[int]T, len(rows))
+	for _, row := range rows {
+		byId[id(row)] = row
+	}
+
+	ordered := make([]T, 0, len(rows))
+	for _, rowId := range ids {
+		if row, ok := byId[rowId]; ok {
+			ordered = append(ordered, row)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+)
+
+// PersonalAccessTokenUseCase handles the business logic related to personal access tokens
+type PersonalAccessTokenUseCase struct {
+	repo repository.PersonalAccessToken
+}
+
+// NewPersonalAccessTokenUseCase creates a new instance of PersonalAccessTokenUseCase
+func NewPersonalAccessTokenUseCase(r repository.PersonalAccessToken) *PersonalAccessTokenUseCase {
+	return &PersonalAccessTokenUseCase{
+		repo: r,
+	}
+}
+
+// Create mints a new personal access token for the user, the raw token is returned only once
+func (uc *PersonalAccessTokenUseCase) Create(ctx context.Context, userId int, input entity.CreatePersonalAccessTokenInput) (entity.CreatedPersonalAccessToken, error) {
+	if err := input.Validate(); err != nil {
+		return entity.CreatedPersonalAccessToken{}, err
+	}
+
+	rawToken, err := token.NewPersonalAccessToken()
+	if err != nil {
+		return entity.CreatedPersonalAccessToken{}, err
+	}
+
+	accessToken := entity.PersonalAccessToken{
+		UserId:    userId,
+		Name:      input.Name,
+		Scopes:    input.Scopes,
+		TokenHash: token.HashToken(rawToken),
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	if _, err := uc.repo.Create(ctx, &accessToken); err != nil {
+		return entity.CreatedPersonalAccessToken{}, err
+	}
+
+	return entity.CreatedPersonalAccessToken{
+		PersonalAccessToken: accessToken,
+		Token:               rawToken,
+	}, nil
+}
+
+// GetAll retrieves the active personal access tokens of a user
+func (uc *PersonalAccessTokenUseCase) GetAll(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error) {
+	return uc.repo.GetAllByUserId(ctx, userId)
+}
+
+// Revoke revokes a personal access token owned by the user
+func (uc *PersonalAccessTokenUseCase) Revoke(ctx context.Context, userId, tokenId int) error {
+	return uc.repo.Revoke(ctx, userId, tokenId)
+}
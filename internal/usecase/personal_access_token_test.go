@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreatePersonalAccessToken tests the Create function in the PersonalAccessTokenUseCase
+func TestCreatePersonalAccessToken(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name         string
+		input        entity.CreatePersonalAccessTokenInput
+		mockBehavior func(mockRepo *MockPersonalAccessTokenRepo)
+		expectedErr  error
+	}{
+		{
+			name:  "Success",
+			input: entity.CreatePersonalAccessTokenInput{Name: "ci", Scopes: []string{entity.ScopeItemsWrite}},
+			mockBehavior: func(mockRepo *MockPersonalAccessTokenRepo) {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(accessToken *entity.PersonalAccessToken) bool {
+					return accessToken.UserId == 1 && accessToken.Name == "ci" && len(accessToken.TokenHash) == 64
+				})).Return(7, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Unknown scope",
+			input:        entity.CreatePersonalAccessTokenInput{Name: "ci", Scopes: []string{"users:write"}},
+			mockBehavior: func(mockRepo *MockPersonalAccessTokenRepo) {},
+			expectedErr:  utils.ErrInvalidScope,
+		},
+		{
+			name:         "Expiration in the past",
+			input:        entity.CreatePersonalAccessTokenInput{Name: "ci", Scopes: []string{entity.ScopeListsRead}, ExpiresAt: &past},
+			mockBehavior: func(mockRepo *MockPersonalAccessTokenRepo) {},
+			expectedErr:  utils.ErrInvalidExpiration,
+		},
+		{
+			name:  "Repository failure",
+			input: entity.CreatePersonalAccessTokenInput{Name: "ci", Scopes: []string{entity.ScopeListsRead}},
+			mockBehavior: func(mockRepo *MockPersonalAccessTokenRepo) {
+				mockRepo.On("Create", mock.Anything, mock.Anything).Return(0, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockPersonalAccessTokenRepo)
+			patUseCase := usecase.NewPersonalAccessTokenUseCase(mockRepo)
+
+			testCase.mockBehavior(mockRepo)
+
+			created, err := patUseCase.Create(context.Background(), 1, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.True(t, strings.HasPrefix(created.Token, token.PersonalAccessTokenPrefix))
+				assert.Equal(t, token.HashToken(created.Token), created.TokenHash)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRevokePersonalAccessToken tests the Revoke function in the PersonalAccessTokenUseCase
+func TestRevokePersonalAccessToken(t *testing.T) {
+	mockRepo := new(MockPersonalAccessTokenRepo)
+	patUseCase := usecase.NewPersonalAccessTokenUseCase(mockRepo)
+
+	mockRepo.On("Revoke", mock.Anything, 1, 7).Return(utils.ErrPersonalAccessTokenNotFound)
+
+	err := patUseCase.Revoke(context.Background(), 1, 7)
+
+	assert.Equal(t, utils.ErrPersonalAccessTokenNotFound, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+)
+
+// RevisionUseCase handles the revision history of items and lists and reverting them to a prior revision
+type RevisionUseCase struct {
+	itemRepo     repository.Item
+	listRepo     repository.List
+	revisionRepo repository.Revision
+}
+
+// NewRevisionUseCase creates a new instance of RevisionUseCase
+func NewRevisionUseCase(i repository.Item, l repository.List, r repository.Revision) *RevisionUseCase {
+	return &RevisionUseCase{
+		itemRepo:     i,
+		listRepo:     l,
+		revisionRepo: r,
+	}
+}
+
+// GetItemHistory retrieves the revisions of an item, the most recent first, if its list is shared with the user
+func (uc *RevisionUseCase) GetItemHistory(ctx context.Context, userId, itemId int) ([]entity.ItemRevision, error) {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return []entity.ItemRevision{}, err
+	}
+
+	return uc.revisionRepo.GetItemRevisions(ctx, itemId)
+}
+
+// GetListHistory retrieves the revisions of a list, the most recent first, if the list is shared with the user
+func (uc *RevisionUseCase) GetListHistory(ctx context.Context, userId, listId int) ([]entity.ListRevision, error) {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleViewer); err != nil {
+		return []entity.ListRevision{}, err
+	}
+
+	return uc.revisionRepo.GetListRevisions(ctx, listId)
+}
+
+// RevertItem brings an item back to its state right after the revision if the user is an editor of its list,
+// the repository records the updated event in the outbox. The revert is recorded as a new revision so that it can be
+// reverted in turn
+func (uc *RevisionUseCase) RevertItem(ctx context.Context, userId, listId, itemId, revisionId int) error {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleEditor); err != nil {
+		return err
+	}
+
+	revision, err := uc.revisionRepo.GetItemRevision(ctx, itemId, revisionId)
+	if err != nil {
+		return err
+	}
+
+	return uc.itemRepo.RevertOneById(ctx, userId, listId, itemId, revision.After)
+}
+
+// RevertList brings a list back to its state right after the revision if the user is an owner of the list,
+// the repository records the list updated event in the outbox. The revert is recorded as a new revision so that it
+// can be reverted in turn
+func (uc *RevisionUseCase) RevertList(ctx context.Context, userId, listId, revisionId int) error {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleOwner); err != nil {
+		return err
+	}
+
+	revision, err := uc.revisionRepo.GetListRevision(ctx, listId, revisionId)
+	if err != nil {
+		return err
+	}
+
+	return uc.listRepo.RevertOneById(ctx, &userId, listId, revision.After)
+}
@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetItemHistory tests the GetItemHistory function in the RevisionUseCase
+func TestGetItemHistory(t *testing.T) {
+	testCases := []struct {
+		name              string
+		mockBehavior      func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo)
+		expectedRevisions []entity.ItemRevision
+		expectedErr       error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockRevisionRepo.On("GetItemRevisions", mock.Anything, 5).Return([]entity.ItemRevision{{Id: 3, ItemId: 5}}, nil)
+			},
+			expectedRevisions: []entity.ItemRevision{{Id: 3, ItemId: 5}},
+		},
+		{
+			name: "Item not shared with the user",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return("", utils.ErrUserNotOwner)
+			},
+			expectedRevisions: []entity.ItemRevision{},
+			expectedErr:       utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockRevisionRepo := new(MockRevisionRepo)
+			revisionUseCase := usecase.NewRevisionUseCase(mockItemRepo, new(MockListRepo), mockRevisionRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockRevisionRepo)
+
+			revisions, err := revisionUseCase.GetItemHistory(context.Background(), 1, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedRevisions, revisions)
+
+			mockItemRepo.AssertExpectations(t)
+			mockRevisionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRevertItem tests the RevertItem function in the RevisionUseCase
+func TestRevertItem(t *testing.T) {
+	state := entity.ItemState{Title: "Milk"}
+
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockRevisionRepo.On("GetItemRevision", mock.Anything, 5, 3).Return(entity.ItemRevision{Id: 3, ItemId: 5, After: state}, nil)
+				mockItemRepo.On("RevertOneById", mock.Anything, 1, 2, 5, state).Return(nil)
+			},
+		},
+		{
+			name: "Viewer cannot revert",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name: "Revision not found",
+			mockBehavior: func(mockItemRepo *MockItemRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+				mockRevisionRepo.On("GetItemRevision", mock.Anything, 5, 3).Return(entity.ItemRevision{}, utils.ErrRevisionNotFound)
+			},
+			expectedErr: utils.ErrRevisionNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockItemRepo := new(MockItemRepo)
+			mockRevisionRepo := new(MockRevisionRepo)
+			revisionUseCase := usecase.NewRevisionUseCase(mockItemRepo, new(MockListRepo), mockRevisionRepo)
+
+			testCase.mockBehavior(mockItemRepo, mockRevisionRepo)
+
+			err := revisionUseCase.RevertItem(context.Background(), 1, 2, 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockItemRepo.AssertExpectations(t)
+			mockRevisionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRevertList tests the RevertList function in the RevisionUseCase
+func TestRevertList(t *testing.T) {
+	state := entity.ListState{Title: "Groceries"}
+
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockListRepo *MockListRepo, mockRevisionRepo *MockRevisionRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockListRepo *MockListRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleOwner, nil)
+				mockRevisionRepo.On("GetListRevision", mock.Anything, 2, 3).Return(entity.ListRevision{Id: 3, ListId: 2, After: state}, nil)
+				mockListRepo.On("RevertOneById", mock.Anything, mock.Anything, 2, state).Return(nil)
+			},
+		},
+		{
+			name: "Editor cannot revert",
+			mockBehavior: func(mockListRepo *MockListRepo, mockRevisionRepo *MockRevisionRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleEditor, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo := new(MockListRepo)
+			mockRevisionRepo := new(MockRevisionRepo)
+			revisionUseCase := usecase.NewRevisionUseCase(new(MockItemRepo), mockListRepo, mockRevisionRepo)
+
+			testCase.mockBehavior(mockListRepo, mockRevisionRepo)
+
+			err := revisionUseCase.RevertList(context.Background(), 1, 2, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockListRepo.AssertExpectations(t)
+			mockRevisionRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+)
+
+// TagUseCase handles the business logic related to tags and their attachment to items
+type TagUseCase struct {
+	repo     repository.Tag
+	itemRepo repository.Item
+}
+
+// NewTagUseCase creates a new instance of TagUseCase
+func NewTagUseCase(r repository.Tag, ir repository.Item) *TagUseCase {
+	return &TagUseCase{
+		repo:     r,
+		itemRepo: ir,
+	}
+}
+
+// Create creates a new tag for the user
+func (uc *TagUseCase) Create(ctx context.Context, userId int, input entity.CreateTagInput) (entity.Tag, error) {
+	if err := input.Validate(); err != nil {
+		return entity.Tag{}, err
+	}
+
+	tag := entity.Tag{
+		UserId: userId,
+		Name:   input.Name,
+		Color:  input.Color,
+	}
+
+	if _, err := uc.repo.Create(ctx, &tag); err != nil {
+		return entity.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// GetAll retrieves the tags of the user
+func (uc *TagUseCase) GetAll(ctx context.Context, userId int) ([]entity.Tag, error) {
+	return uc.repo.GetAllByUserId(ctx, userId)
+}
+
+// UpdateOneById renames or recolors a tag of the user
+func (uc *TagUseCase) UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateOneById(ctx, userId, tagId, input)
+}
+
+// DeleteOneById deletes a tag of the user and detaches it from every item
+func (uc *TagUseCase) DeleteOneById(ctx context.Context, userId, tagId int) error {
+	return uc.repo.DeleteOneById(ctx, userId, tagId)
+}
+
+// GetAllForItem retrieves the tags of the user attached to an item if its list is shared with the user
+func (uc *TagUseCase) GetAllForItem(ctx context.Context, userId, itemId int) ([]entity.Tag, error) {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return []entity.Tag{}, err
+	}
+
+	return uc.repo.GetAllByItemId(ctx, userId, itemId)
+}
+
+// AttachToItem attaches a tag of the user to an item of a list shared with the user, the repository records an
+// item updated event in the outbox so that the item is reindexed with its tags. Tags are private, so viewers can tag items too
+func (uc *TagUseCase) AttachToItem(ctx context.Context, userId, listId, itemId, tagId int) error {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return err
+	}
+
+	if _, err := uc.repo.GetOneById(ctx, userId, tagId); err != nil {
+		return err
+	}
+
+	return uc.repo.AttachToItem(ctx, userId, listId, tagId, itemId)
+}
+
+// DetachFromItem detaches a tag of the user from an item of a list shared with the user,
+// the repository records an item updated event in the outbox so that the item is reindexed with its tags
+func (uc *TagUseCase) DetachFromItem(ctx context.Context, userId, listId, itemId, tagId int) error {
+	if err := authorizeItem(ctx, uc.itemRepo, userId, itemId, entity.ListRoleViewer); err != nil {
+		return err
+	}
+
+	return uc.repo.DetachFromItem(ctx, userId, listId, tagId, itemId)
+}
@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestCreateTag tests the Create function in the TagUseCase
+func TestCreateTag(t *testing.T) {
+	color := "#ff8800"
+	invalidColor := "orange"
+
+	testCases := []struct {
+		name         string
+		input        entity.CreateTagInput
+		mockBehavior func(mockRepo *MockTagRepo)
+		expectedTag  entity.Tag
+		expectedErr  error
+	}{
+		{
+			name:  "Success",
+			input: entity.CreateTagInput{Name: "  work ", Color: &color},
+			mockBehavior: func(mockRepo *MockTagRepo) {
+				mockRepo.On("Create", mock.Anything, &entity.Tag{UserId: 1, Name: "work", Color: &color}).Return(3, nil)
+			},
+			expectedTag: entity.Tag{UserId: 1, Name: "work", Color: &color},
+			expectedErr: nil,
+		},
+		{
+			name:         "Name with a comma",
+			input:        entity.CreateTagInput{Name: "work,home"},
+			mockBehavior: func(mockRepo *MockTagRepo) {},
+			expectedErr:  utils.ErrInvalidTagName,
+		},
+		{
+			name:         "Invalid color",
+			input:        entity.CreateTagInput{Name: "work", Color: &invalidColor},
+			mockBehavior: func(mockRepo *MockTagRepo) {},
+			expectedErr:  utils.ErrInvalidTagColor,
+		},
+		{
+			name:  "Tag exists",
+			input: entity.CreateTagInput{Name: "work"},
+			mockBehavior: func(mockRepo *MockTagRepo) {
+				mockRepo.On("Create", mock.Anything, mock.Anything).Return(0, utils.ErrTagExists)
+			},
+			expectedErr: utils.ErrTagExists,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockTagRepo)
+			tagUseCase := usecase.NewTagUseCase(mockRepo, new(MockItemRepo))
+
+			testCase.mockBehavior(mockRepo)
+
+			tag, err := tagUseCase.Create(context.Background(), 1, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, testCase.expectedTag, tag)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUpdateTag tests the UpdateOneById function in the TagUseCase
+func TestUpdateTag(t *testing.T) {
+	name := " home "
+	trimmedName := "home"
+
+	testCases := []struct {
+		name         string
+		input        entity.UpdateTagInput
+		mockBehavior func(mockRepo *MockTagRepo)
+		expectedErr  error
+	}{
+		{
+			name:  "Success",
+			input: entity.UpdateTagInput{Name: &name},
+			mockBehavior: func(mockRepo *MockTagRepo) {
+				mockRepo.On("UpdateOneById", mock.Anything, 1, 3, entity.UpdateTagInput{Name: &trimmedName}).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "Empty request",
+			input:        entity.UpdateTagInput{},
+			mockBehavior: func(mockRepo *MockTagRepo) {},
+			expectedErr:  utils.ErrTagEmptyRequest,
+		},
+		{
+			name:  "Tag not found",
+			input: entity.UpdateTagInput{Name: &name},
+			mockBehavior: func(mockRepo *MockTagRepo) {
+				mockRepo.On("UpdateOneById", mock.Anything, 1, 3, mock.Anything).Return(utils.ErrTagNotFound)
+			},
+			expectedErr: utils.ErrTagNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockTagRepo)
+			tagUseCase := usecase.NewTagUseCase(mockRepo, new(MockItemRepo))
+
+			testCase.mockBehavior(mockRepo)
+
+			err := tagUseCase.UpdateOneById(context.Background(), 1, 3, testCase.input)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAttachTagToItem tests the AttachToItem function in the TagUseCase
+func TestAttachTagToItem(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockRepo *MockTagRepo, mockItemRepo *MockItemRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockRepo *MockTagRepo, mockItemRepo *MockItemRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+				mockRepo.On("GetOneById", mock.Anything, 1, 3).Return(entity.Tag{Id: 3, UserId: 1}, nil)
+				mockRepo.On("AttachToItem", mock.Anything, 1, 2, 3, 5).Return(nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Item not shared with the user",
+			mockBehavior: func(mockRepo *MockTagRepo, mockItemRepo *MockItemRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return("", utils.ErrUserNotOwner)
+			},
+			expectedErr: utils.ErrUserNotOwner,
+		},
+		{
+			name: "Tag of another user",
+			mockBehavior: func(mockRepo *MockTagRepo, mockItemRepo *MockItemRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleEditor, nil)
+				mockRepo.On("GetOneById", mock.Anything, 1, 3).Return(entity.Tag{}, utils.ErrTagNotFound)
+			},
+			expectedErr: utils.ErrTagNotFound,
+		},
+		{
+			name: "Repository failure",
+			mockBehavior: func(mockRepo *MockTagRepo, mockItemRepo *MockItemRepo) {
+				mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleOwner, nil)
+				mockRepo.On("GetOneById", mock.Anything, 1, 3).Return(entity.Tag{Id: 3, UserId: 1}, nil)
+				mockRepo.On("AttachToItem", mock.Anything, 1, 2, 3, 5).Return(errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockTagRepo)
+			mockItemRepo := new(MockItemRepo)
+			tagUseCase := usecase.NewTagUseCase(mockRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockRepo, mockItemRepo)
+
+			err := tagUseCase.AttachToItem(context.Background(), 1, 2, 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestDetachTagFromItem tests the DetachFromItem function in the TagUseCase
+func TestDetachTagFromItem(t *testing.T) {
+	testCases := []struct {
+		name        string
+		detachErr   error
+		expectedErr error
+	}{
+		{
+			name:        "Success",
+			detachErr:   nil,
+			expectedErr: nil,
+		},
+		{
+			name:        "Tag not attached",
+			detachErr:   utils.ErrTagNotFound,
+			expectedErr: utils.ErrTagNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockTagRepo)
+			mockItemRepo := new(MockItemRepo)
+			tagUseCase := usecase.NewTagUseCase(mockRepo, mockItemRepo)
+
+			mockItemRepo.On("GetUserItemRole", mock.Anything, 1, 5).Return(entity.ListRoleViewer, nil)
+			mockRepo.On("DetachFromItem", mock.Anything, 1, 2, 3, 5).Return(testCase.detachErr)
+
+			err := tagUseCase.DetachFromItem(context.Background(), 1, 2, 5, 3)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/repository"
+)
+
+// TrashUseCase handles browsing, restoring and purging the lists and items in the trash
+type TrashUseCase struct {
+	listRepo repository.List
+	itemRepo repository.Item
+}
+
+// NewTrashUseCase creates a new instance of TrashUseCase
+func NewTrashUseCase(l repository.List, i repository.Item) *TrashUseCase {
+	return &TrashUseCase{
+		listRepo: l,
+		itemRepo: i,
+	}
+}
+
+// GetLists retrieves the lists in the trash that the user owns
+func (uc *TrashUseCase) GetLists(ctx context.Context, userId int) ([]entity.TrashedList, error) {
+	return uc.listRepo.GetTrashedUserLists(ctx, userId)
+}
+
+// GetItems retrieves the items of a list that are in the trash if the list is shared with the user
+func (uc *TrashUseCase) GetItems(ctx context.Context, userId, listId int) ([]entity.TrashedItem, error) {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleViewer); err != nil {
+		return []entity.TrashedItem{}, err
+	}
+
+	return uc.itemRepo.GetTrashedListItems(ctx, listId)
+}
+
+// RestoreList takes a list out of the trash if the user owns it, the repository records a list updated event in the
+// outbox so that the list is indexed for search again
+func (uc *TrashUseCase) RestoreList(ctx context.Context, userId, listId int) error {
+	return uc.listRepo.RestoreUserList(ctx, userId, listId)
+}
+
+// RestoreItem takes an item and the subtasks trashed along with it out of the trash if the user is an editor
+// of the list, the repository records an updated event for each of them in the outbox so that they are indexed for
+// search again
+func (uc *TrashUseCase) RestoreItem(ctx context.Context, userId, listId, itemId int) error {
+	if err := authorizeList(ctx, uc.listRepo, userId, listId, entity.ListRoleEditor); err != nil {
+		return err
+	}
+
+	_, err := uc.itemRepo.RestoreItem(ctx, userId, listId, itemId)
+
+	return err
+}
+
+// Purge permanently deletes the lists and items that were moved to the trash before the given time.
+// The items of a purged list were still indexed for search, the repository records a deleted event in the outbox for
+// each of them
+func (uc *TrashUseCase) Purge(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error) {
+	purged, err := uc.listRepo.PurgeTrash(ctx, deletedBefore)
+	if err != nil {
+		return entity.PurgedTrash{}, err
+	}
+
+	itemIds, err := uc.itemRepo.PurgeTrash(ctx, deletedBefore)
+	if err != nil {
+		return entity.PurgedTrash{}, err
+	}
+
+	purged.ItemIds = append(purged.ItemIds, itemIds...)
+
+	return purged, nil
+}
@@ -0,0 +1,220 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/internal/entity"
+	"github.com/berikulyBeket/todo-plus/internal/usecase"
+	"github.com/berikulyBeket/todo-plus/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestGetTrashedItems tests the GetItems function in the TrashUseCase
+func TestGetTrashedItems(t *testing.T) {
+	deletedAt := time.Date(2024, 11, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		mockBehavior  func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo)
+		expectedItems []entity.TrashedItem
+		expectedErr   error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleViewer, nil)
+				mockItemRepo.On("GetTrashedListItems", mock.Anything, 2).Return([]entity.TrashedItem{
+					{Item: entity.Item{Id: 5, Title: "Item"}, ListId: 2, DeletedAt: deletedAt},
+				}, nil)
+			},
+			expectedItems: []entity.TrashedItem{
+				{Item: entity.Item{Id: 5, Title: "Item"}, ListId: 2, DeletedAt: deletedAt},
+			},
+		},
+		{
+			name: "List not shared with the user",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return("", utils.ErrUserNotOwner)
+			},
+			expectedItems: []entity.TrashedItem{},
+			expectedErr:   utils.ErrUserNotOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo := new(MockListRepo)
+			mockItemRepo := new(MockItemRepo)
+			trashUseCase := usecase.NewTrashUseCase(mockListRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockListRepo, mockItemRepo)
+
+			items, err := trashUseCase.GetItems(context.Background(), 1, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			assert.Equal(t, testCase.expectedItems, items)
+
+			mockListRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRestoreList tests the RestoreList function in the TrashUseCase
+func TestRestoreList(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name: "Success",
+		},
+		{
+			name:        "List not in the trash",
+			repoErr:     utils.ErrListNotFound,
+			expectedErr: utils.ErrListNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo := new(MockListRepo)
+			trashUseCase := usecase.NewTrashUseCase(mockListRepo, new(MockItemRepo))
+
+			mockListRepo.On("RestoreUserList", mock.Anything, 1, 2).Return(testCase.repoErr)
+
+			err := trashUseCase.RestoreList(context.Background(), 1, 2)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockListRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRestoreItem tests the RestoreItem function in the TrashUseCase
+func TestRestoreItem(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockBehavior func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleEditor, nil)
+				mockItemRepo.On("RestoreItem", mock.Anything, 1, 2, 5).Return([]int{5, 6}, nil)
+			},
+		},
+		{
+			name: "Insufficient role",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleViewer, nil)
+			},
+			expectedErr: utils.ErrListPermissionDenied,
+		},
+		{
+			name: "Item not in the trash",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("GetUserListRole", mock.Anything, 1, 2).Return(entity.ListRoleOwner, nil)
+				mockItemRepo.On("RestoreItem", mock.Anything, 1, 2, 5).Return([]int(nil), utils.ErrItemNotFound)
+			},
+			expectedErr: utils.ErrItemNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo := new(MockListRepo)
+			mockItemRepo := new(MockItemRepo)
+			trashUseCase := usecase.NewTrashUseCase(mockListRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockListRepo, mockItemRepo)
+
+			err := trashUseCase.RestoreItem(context.Background(), 1, 2, 5)
+
+			assert.Equal(t, testCase.expectedErr, err)
+
+			mockListRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPurgeTrash tests the Purge function in the TrashUseCase
+func TestPurgeTrash(t *testing.T) {
+	deletedBefore := time.Date(2024, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		mockBehavior   func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo)
+		expectedPurged entity.PurgedTrash
+		expectedErr    error
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("PurgeTrash", mock.Anything, deletedBefore).Return(entity.PurgedTrash{ListIds: []int{2}, ItemIds: []int{5, 6}}, nil)
+				mockItemRepo.On("PurgeTrash", mock.Anything, deletedBefore).Return([]int{9}, nil)
+			},
+			expectedPurged: entity.PurgedTrash{ListIds: []int{2}, ItemIds: []int{5, 6, 9}},
+		},
+		{
+			name: "List purge failure",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("PurgeTrash", mock.Anything, deletedBefore).Return(entity.PurgedTrash{}, errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+		{
+			name: "Item purge failure",
+			mockBehavior: func(mockListRepo *MockListRepo, mockItemRepo *MockItemRepo) {
+				mockListRepo.On("PurgeTrash", mock.Anything, deletedBefore).Return(entity.PurgedTrash{ListIds: []int{}, ItemIds: []int{}}, nil)
+				mockItemRepo.On("PurgeTrash", mock.Anything, deletedBefore).Return([]int(nil), errors.New("db error"))
+			},
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockListRepo := new(MockListRepo)
+			mockItemRepo := new(MockItemRepo)
+			trashUseCase := usecase.NewTrashUseCase(mockListRepo, mockItemRepo)
+
+			testCase.mockBehavior(mockListRepo, mockItemRepo)
+
+			purged, err := trashUseCase.Purge(context.Background(), deletedBefore)
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, testCase.expectedPurged, purged)
+			}
+
+			mockListRepo.AssertExpectations(t)
+			mockItemRepo.AssertExpectations(t)
+		})
+	}
+}
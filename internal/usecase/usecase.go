@@ -2,9 +2,13 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
+	appauth "github.com/berikulyBeket/todo-plus/pkg/app_auth"
+	"github.com/berikulyBeket/todo-plus/pkg/blobstore"
 	"github.com/berikulyBeket/todo-plus/pkg/hash"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
 	messagebroker "github.com/berikulyBeket/todo-plus/pkg/message_broker"
@@ -14,23 +18,57 @@ import (
 
 type Auth interface {
 	CreateUser(ctx context.Context, user entity.User) (int, error)
+	EnsureAdmin(ctx context.Context, name, username, password string) error
 	AuthenticateUser(ctx context.Context, username, password string) (entity.User, error)
-	GenerateToken(ctx context.Context, userId int) (string, error)
-	ParseToken(ctx context.Context, token string) (int, error)
+	GenerateTokens(ctx context.Context, userId int, role string) (entity.TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (entity.TokenPair, error)
+	SignOut(ctx context.Context, accessToken, refreshToken string) error
+	ParseToken(ctx context.Context, token string) (int, string, error)
+	ParsePersonalAccessToken(ctx context.Context, token string) (int, []string, error)
+	GetJWKS() token.JWKS
+}
+
+type PersonalAccessToken interface {
+	Create(ctx context.Context, userId int, input entity.CreatePersonalAccessTokenInput) (entity.CreatedPersonalAccessToken, error)
+	GetAll(ctx context.Context, userId int) ([]entity.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userId, tokenId int) error
+}
+
+type App interface {
+	appauth.Interface
+	Create(ctx context.Context, input entity.CreateAppInput) (entity.CreatedApp, error)
+	EnsureApp(ctx context.Context, appId, appKey, name string, accessTypes []string) error
+	GetAll(ctx context.Context) ([]entity.App, error)
+	GetOneByAppId(ctx context.Context, appId string) (entity.App, error)
+	UpdateOneByAppId(ctx context.Context, appId string, input entity.UpdateAppInput) error
+	RotateKey(ctx context.Context, appId string, input entity.RotateAppKeyInput) (entity.CreatedApp, error)
+	DeleteOneByAppId(ctx context.Context, appId string) error
 }
 
 type User interface {
 	DeleteOneByAdmin(ctx context.Context, userId int) error
+	UpdateRole(ctx context.Context, userId int, input entity.UpdateUserRoleInput) error
 }
 
 type List interface {
 	Create(ctx context.Context, userId int, list *entity.List) (int, error)
-	GetAll(ctx context.Context, userId int) ([]entity.List, error)
+	GetAll(ctx context.Context, userId int, query entity.PageQuery) (entity.ListPage, error)
 	GetOneById(ctx context.Context, userId, listId int) (entity.List, error)
-	UpdateOneById(ctx context.Context, userId, listId int, newTodoInput entity.UpdateListInput) error
-	DeleteOneById(ctx context.Context, userId, listId int) error
+	UpdateOneById(ctx context.Context, userId, listId int, newTodoInput entity.UpdateListInput, version *int) error
+	DeleteOneById(ctx context.Context, userId, listId int, version *int) error
 	DeleteOneByAdmin(ctx context.Context, listId int) error
-	Search(ctx context.Context, userId int, searchText string) ([]entity.List, error)
+	GetCollaborators(ctx context.Context, userId, listId int) ([]entity.ListCollaborator, error)
+	AddCollaborator(ctx context.Context, userId, listId int, input entity.AddCollaboratorInput) (entity.ListCollaborator, error)
+	UpdateCollaborator(ctx context.Context, userId, listId, collaboratorId int, input entity.UpdateCollaboratorInput) error
+	RemoveCollaborator(ctx context.Context, userId, listId, collaboratorId int) error
+	Move(ctx context.Context, userId, listId int, input entity.MoveInput) error
+	Clone(ctx context.Context, userId, listId int, input entity.CloneListInput) (entity.ListCopy, error)
+	CreateTemplate(ctx context.Context, userId, listId int, input entity.CreateListTemplateInput) (entity.ListTemplate, error)
+	GetTemplates(ctx context.Context, userId int) ([]entity.ListTemplate, error)
+	GetTemplate(ctx context.Context, userId, templateId int) (entity.ListTemplate, error)
+	DeleteTemplate(ctx context.Context, userId, templateId int) error
+	CreateFromTemplate(ctx context.Context, userId, templateId int, input entity.UseListTemplateInput) (entity.ListCopy, error)
+	Search(ctx context.Context, userId int, searchText string, query entity.PageQuery) (entity.ListPage, error)
 	HandleCreated(ctx context.Context, message entity.ListCreatedEvent) error
 	HandleUpdated(ctx context.Context, message entity.ListUpdatedEvent) error
 	HandleDeleted(ctx context.Context, message entity.ListDeletedEvent) error
@@ -38,22 +76,103 @@ type List interface {
 
 type Item interface {
 	Create(ctx context.Context, userId, listId int, item *entity.Item) (int, error)
-	GetAll(ctx context.Context, userId, listId int) ([]entity.Item, error)
+	GetAll(ctx context.Context, userId, listId int, query entity.ItemQuery) (entity.ItemPage, error)
 	GetOneById(ctx context.Context, userId, itemId int) (entity.Item, error)
-	UpdateOneById(ctx context.Context, userId, listId, itemId int, input entity.UpdateItemInput) error
-	DeleteOneById(ctx context.Context, userId, listId, itemId int) error
+	UpdateOneById(ctx context.Context, userId, listId, itemId int, input entity.UpdateItemInput, version *int) error
+	DeleteOneById(ctx context.Context, userId, listId, itemId int, version *int) error
 	DeleteOneByAdmin(ctx context.Context, itemId int) error
-	Search(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]entity.Item, error)
+	CreateSubtask(ctx context.Context, userId, listId, parentId int, item *entity.Item) (int, error)
+	GetSubtasks(ctx context.Context, userId, itemId int) (entity.Subtasks, error)
+	SetParent(ctx context.Context, userId, listId, itemId int, input entity.SetItemParentInput) error
+	Move(ctx context.Context, userId, listId, itemId int, input entity.MoveInput) error
+	MoveToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) (entity.MovedItems, error)
+	CopyToList(ctx context.Context, userId, listId int, input entity.TransferItemsInput) ([]entity.ItemCopy, error)
+	Bulk(ctx context.Context, userId int, input entity.BulkItemsInput) (entity.BulkItemsResult, error)
+	Search(ctx context.Context, userId int, listId *int, done *bool, tags []string, searchText string, query entity.PageQuery) (entity.ItemPage, error)
 	HandleCreated(ctx context.Context, message entity.ItemCreatedEvent) error
 	HandleUpdated(ctx context.Context, message entity.ItemUpdatedEvent) error
 	HandleDeleted(ctx context.Context, message entity.ItemDeletedEvent) error
+	DispatchDueReminders(ctx context.Context, limit int) (int, error)
+}
+
+type Trash interface {
+	GetLists(ctx context.Context, userId int) ([]entity.TrashedList, error)
+	GetItems(ctx context.Context, userId, listId int) ([]entity.TrashedItem, error)
+	RestoreList(ctx context.Context, userId, listId int) error
+	RestoreItem(ctx context.Context, userId, listId, itemId int) error
+	Purge(ctx context.Context, deletedBefore time.Time) (entity.PurgedTrash, error)
+}
+
+type Tag interface {
+	Create(ctx context.Context, userId int, input entity.CreateTagInput) (entity.Tag, error)
+	GetAll(ctx context.Context, userId int) ([]entity.Tag, error)
+	UpdateOneById(ctx context.Context, userId, tagId int, input entity.UpdateTagInput) error
+	DeleteOneById(ctx context.Context, userId, tagId int) error
+	GetAllForItem(ctx context.Context, userId, itemId int) ([]entity.Tag, error)
+	AttachToItem(ctx context.Context, userId, listId, itemId, tagId int) error
+	DetachFromItem(ctx context.Context, userId, listId, itemId, tagId int) error
+}
+
+type Invitation interface {
+	Create(ctx context.Context, userId, listId int, input entity.CreateInvitationInput) (entity.CreatedInvitation, error)
+	GetAllForList(ctx context.Context, userId, listId int) ([]entity.Invitation, error)
+	GetAllForUser(ctx context.Context, userId int) ([]entity.Invitation, error)
+	Accept(ctx context.Context, userId, invitationId int) error
+	AcceptLink(ctx context.Context, userId int, input entity.AcceptInvitationLinkInput) (entity.Invitation, error)
+	Decline(ctx context.Context, userId, invitationId int) error
+	Revoke(ctx context.Context, userId, invitationId int) error
+}
+
+type Revision interface {
+	GetItemHistory(ctx context.Context, userId, itemId int) ([]entity.ItemRevision, error)
+	GetListHistory(ctx context.Context, userId, listId int) ([]entity.ListRevision, error)
+	RevertItem(ctx context.Context, userId, listId, itemId, revisionId int) error
+	RevertList(ctx context.Context, userId, listId, revisionId int) error
+}
+
+type Comment interface {
+	Create(ctx context.Context, userId, itemId int, input entity.CreateCommentInput) (entity.Comment, error)
+	GetAll(ctx context.Context, userId, itemId int, query entity.CommentQuery) (entity.CommentPage, error)
+	UpdateOneById(ctx context.Context, userId, itemId, commentId int, input entity.UpdateCommentInput) error
+	DeleteOneById(ctx context.Context, userId, itemId, commentId int) error
+}
+
+type Attachment interface {
+	Upload(ctx context.Context, userId, itemId int, input entity.UploadAttachmentInput) (entity.Attachment, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]entity.Attachment, error)
+	Download(ctx context.Context, userId, itemId, attachmentId int) (entity.Attachment, io.ReadCloser, error)
+	DeleteOneById(ctx context.Context, userId, itemId, attachmentId int) error
+	PurgeDetached(ctx context.Context, limit int) (int, error)
+	MaxSize() int64
+}
+
+type Idempotency interface {
+	Begin(ctx context.Context, userId int, key, fingerprint string) (*entity.IdempotentResponse, error)
+	Complete(ctx context.Context, userId int, key, fingerprint string, response entity.IdempotentResponse) error
+	Abort(ctx context.Context, userId int, key string) error
+}
+
+type Outbox interface {
+	Relay(ctx context.Context, limit int) (int, error)
+	GetBacklog(ctx context.Context) (entity.OutboxBacklog, error)
+	PurgeSent(ctx context.Context, sentBefore time.Time) (int, error)
 }
 
 type UseCase struct {
 	Auth
+	PersonalAccessToken
+	App
 	User
 	List
 	Item
+	Invitation
+	Tag
+	Trash
+	Revision
+	Comment
+	Attachment
+	Idempotency
+	Outbox
 }
 
 // NewUseCase creates a new instance of UseCase with initialized use cases
@@ -63,12 +182,28 @@ func NewUseCase(
 	brokerProducer messagebroker.Producer,
 	hasher hash.Hasher,
 	tokenMaker token.TokenMaker,
+	appKeyRotationOverlap time.Duration,
+	invitationTTL time.Duration,
+	blobStore blobstore.Interface,
+	attachmentMaxSize int64,
+	attachmentTypes []string,
+	idempotencyTTL time.Duration,
 	logger logger.Interface,
 ) *UseCase {
 	return &UseCase{
-		Auth: NewAuthUseCase(repos.Auth, hasher, tokenMaker),
-		User: NewUserUseCase(repos.User),
-		List: NewListUseCase(repos.List, searchService.List, brokerProducer, logger),
-		Item: NewItemUseCase(repos.Item, repos.List, searchService.Item, brokerProducer, logger),
+		Auth:                NewAuthUseCase(repos.Auth, repos.Token, repos.PersonalAccessToken, hasher, tokenMaker, logger),
+		PersonalAccessToken: NewPersonalAccessTokenUseCase(repos.PersonalAccessToken),
+		App:                 NewAppUseCase(repos.App, appKeyRotationOverlap, logger),
+		User:                NewUserUseCase(repos.User),
+		List:                NewListUseCase(repos.List, searchService.List, logger),
+		Item:                NewItemUseCase(repos.Item, repos.List, repos.Tag, searchService.Item, logger),
+		Invitation:          NewInvitationUseCase(repos.Invitation, repos.List, repos.Auth, invitationTTL),
+		Tag:                 NewTagUseCase(repos.Tag, repos.Item),
+		Trash:               NewTrashUseCase(repos.List, repos.Item),
+		Revision:            NewRevisionUseCase(repos.Item, repos.List, repos.Revision),
+		Comment:             NewCommentUseCase(repos.Comment, repos.Item),
+		Attachment:          NewAttachmentUseCase(repos.Attachment, repos.Item, blobStore, attachmentMaxSize, attachmentTypes, logger),
+		Idempotency:         NewIdempotencyUseCase(repos.Idempotency, idempotencyTTL),
+		Outbox:              NewOutboxUseCase(repos.Outbox, brokerProducer),
 	}
 }
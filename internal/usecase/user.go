@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/repository"
 )
 
@@ -22,3 +23,12 @@ func NewUserUseCase(r repository.User) *UserUseCase {
 func (uc *UserUseCase) DeleteOneByAdmin(ctx context.Context, userId int) error {
 	return uc.repo.DeleteOneById(ctx, userId)
 }
+
+// UpdateRole validates and assigns a role to the user, the new role is carried by tokens issued after the change
+func (uc *UserUseCase) UpdateRole(ctx context.Context, userId int, input entity.UpdateUserRoleInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateRole(ctx, userId, input.Role)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/internal/usecase"
 	"github.com/berikulyBeket/todo-plus/utils"
 
@@ -52,3 +53,60 @@ func TestDeleteUserById(t *testing.T) {
 		})
 	}
 }
+
+// TestUpdateRole tests the UpdateRole function in the UserUseCase
+func TestUpdateRole(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userId      int
+		role        string
+		repoErr     error
+		expectRepo  bool
+		expectedErr error
+	}{
+		{
+			name:        "Successful role update",
+			userId:      1,
+			role:        entity.RoleSupport,
+			expectRepo:  true,
+			expectedErr: nil,
+		},
+		{
+			name:        "Unknown role",
+			userId:      1,
+			role:        "superuser",
+			expectRepo:  false,
+			expectedErr: utils.ErrInvalidRole,
+		},
+		{
+			name:        "User not found",
+			userId:      2,
+			role:        entity.RoleAdmin,
+			repoErr:     utils.ErrUserNotFound,
+			expectRepo:  true,
+			expectedErr: utils.ErrUserNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockUserRepo)
+			userUseCase := usecase.NewUserUseCase(mockRepo)
+
+			mockRepo.On("UpdateRole", mock.Anything, testCase.userId, testCase.role).Return(testCase.repoErr)
+
+			err := userUseCase.UpdateRole(context.Background(), testCase.userId, entity.UpdateUserRoleInput{Role: testCase.role})
+
+			assert.Equal(t, testCase.expectedErr, err)
+			if testCase.expectRepo {
+				mockRepo.AssertCalled(t, "UpdateRole", mock.Anything, testCase.userId, testCase.role)
+			} else {
+				mockRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
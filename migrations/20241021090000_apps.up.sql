@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS apps (
+    id serial PRIMARY KEY,
+    app_id varchar(64) NOT NULL UNIQUE,
+    name varchar(100) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    previous_key_hash varchar(64),
+    previous_key_expires_at timestamptz,
+    access_types text[] NOT NULL DEFAULT '{}',
+    enabled boolean NOT NULL DEFAULT TRUE,
+    last_used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_lists_user_id_list_id;
+
+ALTER TABLE users_lists DROP COLUMN IF EXISTS role;
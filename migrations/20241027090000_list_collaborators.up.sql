@@ -0,0 +1,4 @@
+ALTER TABLE users_lists ADD COLUMN IF NOT EXISTS role varchar(20) NOT NULL DEFAULT 'owner'
+    CHECK (role IN ('owner', 'editor', 'viewer'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_lists_user_id_list_id ON users_lists(user_id, list_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS list_invitations (
+    id serial PRIMARY KEY,
+    list_id int REFERENCES lists (id) ON DELETE CASCADE NOT NULL,
+    inviter_id int REFERENCES users (id) ON DELETE CASCADE NOT NULL,
+    invitee_id int REFERENCES users (id) ON DELETE CASCADE,
+    role varchar(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    token_hash varchar(64) UNIQUE,
+    status varchar(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    expires_at timestamptz NOT NULL,
+    responded_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    CHECK (invitee_id IS NOT NULL OR token_hash IS NOT NULL)
+);
+
+CREATE INDEX idx_list_invitations_list_id ON list_invitations(list_id);
+CREATE INDEX idx_list_invitations_invitee_id ON list_invitations(invitee_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_items_pending_reminders;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS reminder_sent_at,
+    DROP COLUMN IF EXISTS remind_at,
+    DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,6 @@
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS due_at timestamptz,
+    ADD COLUMN IF NOT EXISTS remind_at timestamptz,
+    ADD COLUMN IF NOT EXISTS reminder_sent_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_items_pending_reminders ON items (remind_at) WHERE reminder_sent_at IS NULL;
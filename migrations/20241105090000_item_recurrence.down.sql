@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_items_series_occurrence;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS occurrence,
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS item_series;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS item_series (
+    id serial PRIMARY KEY,
+    rule varchar(255) NOT NULL,
+    timezone varchar(64) NOT NULL DEFAULT 'UTC',
+    title varchar(255) NOT NULL,
+    description varchar(255),
+    starts_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS series_id int REFERENCES item_series (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS occurrence int;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_series_occurrence ON items (series_id, occurrence);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id serial PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name varchar(64) NOT NULL,
+    color varchar(7),
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS item_tags (
+    item_id int NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    tag_id int NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag_id ON item_tags (tag_id);
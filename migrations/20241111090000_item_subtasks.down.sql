@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_items_parent_id;
+
+ALTER TABLE items DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,3 @@
+ALTER TABLE items ADD COLUMN IF NOT EXISTS parent_id int REFERENCES items (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_items_parent_id ON items (parent_id);
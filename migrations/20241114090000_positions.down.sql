@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_lists_user_id_position;
+DROP INDEX IF EXISTS idx_lists_items_list_id_position;
+
+ALTER TABLE users_lists DROP COLUMN IF EXISTS position;
+ALTER TABLE lists_items DROP COLUMN IF EXISTS position;
@@ -0,0 +1,24 @@
+ALTER TABLE lists_items ADD COLUMN IF NOT EXISTS position varchar(255) COLLATE "C";
+ALTER TABLE users_lists ADD COLUMN IF NOT EXISTS position varchar(255) COLLATE "C";
+
+-- existing rows keep their creation order, "a0" is the integer part of the first rank and the fixed-width
+-- fraction never ends with 0
+UPDATE lists_items li SET position = ranked.position
+FROM (
+    SELECT id, 'a0' || lpad(ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY item_id)::text, 10, '0') || '1' AS position
+    FROM lists_items
+) ranked
+WHERE ranked.id = li.id;
+
+UPDATE users_lists ul SET position = ranked.position
+FROM (
+    SELECT id, 'a0' || lpad(ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY list_id)::text, 10, '0') || '1' AS position
+    FROM users_lists
+) ranked
+WHERE ranked.id = ul.id;
+
+ALTER TABLE lists_items ALTER COLUMN position SET NOT NULL;
+ALTER TABLE users_lists ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_lists_items_list_id_position ON lists_items (list_id, position);
+CREATE INDEX IF NOT EXISTS idx_users_lists_user_id_position ON users_lists (user_id, position);
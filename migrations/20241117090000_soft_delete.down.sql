@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_items_deleted_at;
+DROP INDEX IF EXISTS idx_lists_deleted_at;
+
+ALTER TABLE items DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE lists DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_lists_deleted_at ON lists (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_items_deleted_at ON items (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_list_revisions_list_id;
+DROP INDEX IF EXISTS idx_item_revisions_item_id;
+
+DROP TABLE IF EXISTS list_revisions;
+
+DROP TABLE IF EXISTS item_revisions;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS item_revisions (
+    id serial PRIMARY KEY,
+    item_id int NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    user_id int REFERENCES users (id) ON DELETE SET NULL,
+    before jsonb NOT NULL,
+    after jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS list_revisions (
+    id serial PRIMARY KEY,
+    list_id int NOT NULL REFERENCES lists (id) ON DELETE CASCADE,
+    user_id int REFERENCES users (id) ON DELETE SET NULL,
+    before jsonb NOT NULL,
+    after jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_revisions_item_id ON item_revisions (item_id, id);
+CREATE INDEX IF NOT EXISTS idx_list_revisions_list_id ON list_revisions (list_id, id);
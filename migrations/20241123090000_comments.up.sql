@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id serial PRIMARY KEY,
+    item_id int NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    parent_id int REFERENCES comments (id) ON DELETE CASCADE,
+    user_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_item_id ON comments (item_id, parent_id, id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id);
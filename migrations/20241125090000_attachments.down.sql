@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_attachments_detached;
+DROP INDEX IF EXISTS idx_attachments_item_id;
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id serial PRIMARY KEY,
+    item_id int REFERENCES items (id) ON DELETE SET NULL,
+    user_id int REFERENCES users (id) ON DELETE SET NULL,
+    name varchar(255) NOT NULL,
+    content_type varchar(255) NOT NULL,
+    size bigint NOT NULL,
+    blob_key varchar(255) NOT NULL UNIQUE,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_item_id ON attachments (item_id, id);
+CREATE INDEX IF NOT EXISTS idx_attachments_detached ON attachments (id) WHERE item_id IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_list_template_items_template_id;
+DROP INDEX IF EXISTS idx_list_templates_user_id;
+
+DROP TABLE IF EXISTS list_template_items;
+DROP TABLE IF EXISTS list_templates;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS list_templates (
+    id serial PRIMARY KEY,
+    user_id int NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title varchar(255) NOT NULL,
+    description varchar(255),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS list_template_items (
+    id serial PRIMARY KEY,
+    template_id int NOT NULL REFERENCES list_templates (id) ON DELETE CASCADE,
+    parent_id int REFERENCES list_template_items (id) ON DELETE CASCADE,
+    title varchar(255) NOT NULL,
+    description varchar(255),
+    position int NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_list_templates_user_id ON list_templates (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_list_template_items_template_id ON list_template_items (template_id, position);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS items_set_updated_at ON items;
+DROP TRIGGER IF EXISTS lists_set_updated_at ON lists;
+
+DROP FUNCTION IF EXISTS set_updated_at();
+
+ALTER TABLE items DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE items DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE lists DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE lists DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,26 @@
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT NOW();
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT NOW();
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT NOW();
+ALTER TABLE items ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT NOW();
+
+-- updated_at follows the changes of the content of a row, changes to trash and reminder bookkeeping leave it untouched
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lists_set_updated_at
+    BEFORE UPDATE OF title, description ON lists
+    FOR EACH ROW
+    WHEN (ROW(OLD.title, OLD.description) IS DISTINCT FROM ROW(NEW.title, NEW.description))
+    EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER items_set_updated_at
+    BEFORE UPDATE OF title, description, done, due_at, remind_at, parent_id ON items
+    FOR EACH ROW
+    WHEN (ROW(OLD.title, OLD.description, OLD.done, OLD.due_at, OLD.remind_at, OLD.parent_id)
+        IS DISTINCT FROM ROW(NEW.title, NEW.description, NEW.done, NEW.due_at, NEW.remind_at, NEW.parent_id))
+    EXECUTE FUNCTION set_updated_at();
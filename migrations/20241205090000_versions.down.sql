@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS items_increment_version ON items;
+DROP TRIGGER IF EXISTS lists_increment_version ON lists;
+
+DROP FUNCTION IF EXISTS increment_version();
+
+ALTER TABLE items DROP COLUMN IF EXISTS version;
+ALTER TABLE lists DROP COLUMN IF EXISTS version;
@@ -0,0 +1,23 @@
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE items ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- version is the ETag of a row, it changes with the content of the row and when the row is trashed or restored
+CREATE OR REPLACE FUNCTION increment_version() RETURNS trigger AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER lists_increment_version
+    BEFORE UPDATE OF title, description, deleted_at ON lists
+    FOR EACH ROW
+    WHEN (ROW(OLD.title, OLD.description, OLD.deleted_at) IS DISTINCT FROM ROW(NEW.title, NEW.description, NEW.deleted_at))
+    EXECUTE FUNCTION increment_version();
+
+CREATE TRIGGER items_increment_version
+    BEFORE UPDATE OF title, description, done, due_at, remind_at, parent_id, deleted_at ON items
+    FOR EACH ROW
+    WHEN (ROW(OLD.title, OLD.description, OLD.done, OLD.due_at, OLD.remind_at, OLD.parent_id, OLD.deleted_at)
+        IS DISTINCT FROM ROW(NEW.title, NEW.description, NEW.done, NEW.due_at, NEW.remind_at, NEW.parent_id, NEW.deleted_at))
+    EXECUTE FUNCTION increment_version();
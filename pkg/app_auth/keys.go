@@ -0,0 +1,45 @@
+package appauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	appIdBytes  = 12
+	appKeyBytes = 32
+)
+
+// NewAppId generates a random public application identifier
+func NewAppId() (string, error) {
+	b := make([]byte, appIdBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// NewAppKey generates a random application secret
+func NewAppKey() (string, error) {
+	b := make([]byte, appKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashKey returns the SHA256 hex digest of an application key, only digests are persisted
+func HashKey(appKey string) string {
+	sum := sha256.Sum256([]byte(appKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareKey checks an application key against a stored digest in constant time
+func CompareKey(appKey, keyHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashKey(appKey)), []byte(keyHash)) == 1
+}
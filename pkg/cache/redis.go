@@ -47,6 +47,17 @@ func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl
 	return c.client.Set(ctx, key, jsonValue, ttl).Err()
 }
 
+// SetNX stores a value in Redis as a JSON string with a specified time-to-live (ttl) only if the key does not exist,
+// it reports whether the value was stored
+func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return c.client.SetNX(ctx, key, jsonValue, ttl).Result()
+}
+
 // Delete removes a value from Redis by key
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
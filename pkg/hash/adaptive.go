@@ -0,0 +1,62 @@
+package hash
+
+// AdaptiveHasher hashes new passwords with the configured algorithm and verifies
+// hashes produced by any supported algorithm, including legacy SHA1 hashes
+type AdaptiveHasher struct {
+	primary  Hasher
+	bcrypt   *BcryptHasher
+	argon2id *Argon2idHasher
+	legacy   *SHA1Hasher
+}
+
+// New creates a new AdaptiveHasher that hashes with the given algorithm and recognizes legacy SHA1 hashes salted with legacySalt
+func New(algorithm string, bcryptCost int, legacySalt string) (Hasher, error) {
+	h := &AdaptiveHasher{
+		bcrypt:   NewBcryptHasher(bcryptCost),
+		argon2id: NewArgon2idHasher(DefaultArgon2idParams),
+		legacy:   NewSHA1Hasher(legacySalt),
+	}
+
+	switch algorithm {
+	case AlgorithmBcrypt:
+		h.primary = h.bcrypt
+	case AlgorithmArgon2id:
+		h.primary = h.argon2id
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	return h, nil
+}
+
+// Hash hashes the provided password with the primary algorithm
+func (h *AdaptiveHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+// Verify checks the password against the encoded hash using the algorithm that produced it
+func (h *AdaptiveHasher) Verify(password, encodedHash string) (bool, error) {
+	return h.hasherFor(encodedHash).Verify(password, encodedHash)
+}
+
+// NeedsRehash reports whether the encoded hash was not produced by the primary algorithm with its current parameters
+func (h *AdaptiveHasher) NeedsRehash(encodedHash string) bool {
+	hasher := h.hasherFor(encodedHash)
+	if hasher != h.primary {
+		return true
+	}
+
+	return hasher.NeedsRehash(encodedHash)
+}
+
+// hasherFor selects the hasher matching the format of the encoded hash
+func (h *AdaptiveHasher) hasherFor(encodedHash string) Hasher {
+	switch {
+	case isArgon2idHash(encodedHash):
+		return h.argon2id
+	case isBcryptHash(encodedHash):
+		return h.bcrypt
+	default:
+		return h.legacy
+	}
+}
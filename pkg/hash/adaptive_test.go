@@ -0,0 +1,124 @@
+package hash_test
+
+import (
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/hash"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestNewAdaptiveHasher tests selecting the primary algorithm of the hasher
+func TestNewAdaptiveHasher(t *testing.T) {
+	for _, algorithm := range []string{hash.AlgorithmBcrypt, hash.AlgorithmArgon2id} {
+		hasher, err := hash.New(algorithm, bcrypt.MinCost, "salt")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, hasher)
+	}
+
+	hasher, err := hash.New("md5", bcrypt.MinCost, "salt")
+
+	assert.Nil(t, hasher)
+	assert.Equal(t, hash.ErrUnsupportedAlgorithm, err)
+}
+
+// TestAdaptiveHasher tests verifying hashes of every supported algorithm and upgrading those not produced
+// by the primary algorithm with its current parameters
+func TestAdaptiveHasher(t *testing.T) {
+	hasher, err := hash.New(hash.AlgorithmArgon2id, bcrypt.MinCost, "salt")
+	assert.NoError(t, err)
+
+	argon2idHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+
+	weakArgon2idHash, err := hash.NewArgon2idHasher(testArgon2idParams).Hash("password")
+	assert.NoError(t, err)
+
+	bcryptHash, err := hash.NewBcryptHasher(bcrypt.MinCost).Hash("password")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name                string
+		password            string
+		encodedHash         string
+		expectedOk          bool
+		expectedNeedsRehash bool
+	}{
+		{
+			name:                "Primary hash",
+			password:            "password",
+			encodedHash:         argon2idHash,
+			expectedOk:          true,
+			expectedNeedsRehash: false,
+		},
+		{
+			name:                "Argon2id hash with other parameters",
+			password:            "password",
+			encodedHash:         weakArgon2idHash,
+			expectedOk:          true,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Bcrypt hash",
+			password:            "password",
+			encodedHash:         bcryptHash,
+			expectedOk:          true,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Legacy SHA1 hash",
+			password:            "password",
+			encodedHash:         legacyHash,
+			expectedOk:          true,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Wrong password for a legacy SHA1 hash",
+			password:            "Password",
+			encodedHash:         legacyHash,
+			expectedOk:          false,
+			expectedNeedsRehash: true,
+		},
+		{
+			name:                "Garbage",
+			password:            "password",
+			encodedHash:         "not a hash",
+			expectedOk:          false,
+			expectedNeedsRehash: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := hasher.Verify(testCase.password, testCase.encodedHash)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expectedNeedsRehash, hasher.NeedsRehash(testCase.encodedHash))
+		})
+	}
+}
+
+// TestAdaptiveHasherBcryptPrimary tests that argon2id hashes are upgraded when bcrypt is the primary algorithm
+func TestAdaptiveHasherBcryptPrimary(t *testing.T) {
+	hasher, err := hash.New(hash.AlgorithmBcrypt, bcrypt.MinCost, "salt")
+	assert.NoError(t, err)
+
+	bcryptHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+	assert.False(t, hasher.NeedsRehash(bcryptHash))
+
+	argon2idHash, err := hash.NewArgon2idHasher(testArgon2idParams).Hash("password")
+	assert.NoError(t, err)
+
+	ok, err := hasher.Verify("password", argon2idHash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(argon2idHash))
+}
@@ -0,0 +1,202 @@
+package hash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/hash"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testArgon2idParams keeps the argon2id tests fast, the format does not depend on the cost
+var testArgon2idParams = hash.Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// TestArgon2idHashAndVerify tests that a password verifies against its argon2id hash and other passwords do not
+func TestArgon2idHashAndVerify(t *testing.T) {
+	hasher := hash.NewArgon2idHasher(testArgon2idParams)
+
+	encodedHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encodedHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	otherHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encodedHash, otherHash, "every hash has its own salt")
+
+	testCases := []struct {
+		name     string
+		password string
+		expected bool
+	}{
+		{
+			name:     "Same password",
+			password: "password",
+			expected: true,
+		},
+		{
+			name:     "Other password",
+			password: "Password",
+			expected: false,
+		},
+		{
+			name:     "Empty password",
+			password: "",
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := hasher.Verify(testCase.password, encodedHash)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, ok)
+		})
+	}
+}
+
+// TestArgon2idVerifyInvalidHash tests that tampered and malformed argon2id hashes are rejected
+func TestArgon2idVerifyInvalidHash(t *testing.T) {
+	hasher := hash.NewArgon2idHasher(testArgon2idParams)
+
+	encodedHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+
+	parts := strings.Split(encodedHash, "$")
+	tamperedKey := strings.Join(append(parts[:5:5], strings.Repeat("A", len(parts[5]))), "$")
+
+	testCases := []struct {
+		name        string
+		encodedHash string
+		expectedErr error
+	}{
+		{
+			name:        "Tampered key",
+			encodedHash: tamperedKey,
+			expectedErr: nil,
+		},
+		{
+			name:        "Garbage",
+			encodedHash: "not a hash",
+			expectedErr: hash.ErrInvalidHash,
+		},
+		{
+			name:        "Missing key",
+			encodedHash: strings.Join(parts[:5], "$"),
+			expectedErr: hash.ErrInvalidHash,
+		},
+		{
+			name:        "Malformed parameters",
+			encodedHash: strings.Replace(encodedHash, "m=1024,t=1,p=1", "m=x,t=1,p=1", 1),
+			expectedErr: hash.ErrInvalidHash,
+		},
+		{
+			name:        "Salt not base64",
+			encodedHash: strings.Replace(encodedHash, parts[4], "!!!", 1),
+			expectedErr: hash.ErrInvalidHash,
+		},
+		{
+			name:        "Other version",
+			encodedHash: strings.Replace(encodedHash, "v=19", "v=16", 1),
+			expectedErr: hash.ErrIncompatibleVersion,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := hasher.Verify("password", testCase.encodedHash)
+
+			assert.False(t, ok)
+			assert.Equal(t, testCase.expectedErr, err)
+		})
+	}
+}
+
+// TestArgon2idNeedsRehash tests that a hash needs a rehash once the parameters of the hasher changed
+func TestArgon2idNeedsRehash(t *testing.T) {
+	encodedHash, err := hash.NewArgon2idHasher(testArgon2idParams).Hash("password")
+	assert.NoError(t, err)
+
+	withParams := func(change func(params *hash.Argon2idParams)) hash.Argon2idParams {
+		params := testArgon2idParams
+		change(&params)
+		return params
+	}
+
+	testCases := []struct {
+		name        string
+		params      hash.Argon2idParams
+		encodedHash string
+		expected    bool
+	}{
+		{
+			name:        "Same parameters",
+			params:      testArgon2idParams,
+			encodedHash: encodedHash,
+			expected:    false,
+		},
+		{
+			name:        "More memory",
+			params:      withParams(func(params *hash.Argon2idParams) { params.Memory = 2048 }),
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "More iterations",
+			params:      withParams(func(params *hash.Argon2idParams) { params.Iterations = 2 }),
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "More parallelism",
+			params:      withParams(func(params *hash.Argon2idParams) { params.Parallelism = 2 }),
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "Longer salt",
+			params:      withParams(func(params *hash.Argon2idParams) { params.SaltLength = 32 }),
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "Longer key",
+			params:      withParams(func(params *hash.Argon2idParams) { params.KeyLength = 64 }),
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "Garbage",
+			params:      testArgon2idParams,
+			encodedHash: "not a hash",
+			expected:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			hasher := hash.NewArgon2idHasher(testCase.params)
+
+			assert.Equal(t, testCase.expected, hasher.NeedsRehash(testCase.encodedHash))
+		})
+	}
+}
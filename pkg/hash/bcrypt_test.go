@@ -0,0 +1,152 @@
+package hash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/hash"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestBcryptHashAndVerify tests that a password verifies against its bcrypt hash and other passwords do not
+func TestBcryptHashAndVerify(t *testing.T) {
+	hasher := hash.NewBcryptHasher(bcrypt.MinCost)
+
+	encodedHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encodedHash, "$2a$04$"))
+
+	testCases := []struct {
+		name     string
+		password string
+		expected bool
+	}{
+		{
+			name:     "Same password",
+			password: "password",
+			expected: true,
+		},
+		{
+			name:     "Other password",
+			password: "Password",
+			expected: false,
+		},
+		{
+			name:     "Empty password",
+			password: "",
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := hasher.Verify(testCase.password, encodedHash)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, ok)
+		})
+	}
+}
+
+// TestBcryptVerifyInvalidHash tests that tampered and malformed bcrypt hashes are rejected
+func TestBcryptVerifyInvalidHash(t *testing.T) {
+	hasher := hash.NewBcryptHasher(bcrypt.MinCost)
+
+	encodedHash, err := hasher.Hash("password")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		encodedHash string
+		expectErr   bool
+	}{
+		{
+			name:        "Tampered hash",
+			encodedHash: encodedHash[:len(encodedHash)-10] + strings.Repeat(".", 10),
+			expectErr:   false,
+		},
+		{
+			name:        "Garbage",
+			encodedHash: "not a hash",
+			expectErr:   true,
+		},
+		{
+			name:        "Truncated hash",
+			encodedHash: encodedHash[:20],
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := hasher.Verify("password", testCase.encodedHash)
+
+			assert.False(t, ok)
+			if testCase.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestBcryptNeedsRehash tests that a hash needs a rehash once the cost of the hasher changed
+func TestBcryptNeedsRehash(t *testing.T) {
+	encodedHash, err := hash.NewBcryptHasher(bcrypt.MinCost).Hash("password")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		cost        int
+		encodedHash string
+		expected    bool
+	}{
+		{
+			name:        "Same cost",
+			cost:        bcrypt.MinCost,
+			encodedHash: encodedHash,
+			expected:    false,
+		},
+		{
+			name:        "Higher cost",
+			cost:        bcrypt.MinCost + 1,
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "Out of range cost falls back to the default",
+			cost:        bcrypt.MaxCost + 1,
+			encodedHash: encodedHash,
+			expected:    true,
+		},
+		{
+			name:        "Garbage",
+			cost:        bcrypt.MinCost,
+			encodedHash: "not a hash",
+			expected:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			hasher := hash.NewBcryptHasher(testCase.cost)
+
+			assert.Equal(t, testCase.expected, hasher.NeedsRehash(testCase.encodedHash))
+		})
+	}
+}
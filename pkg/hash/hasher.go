@@ -1,6 +1,22 @@
 package hash
 
+import "errors"
+
 // Hasher interface for password hashing logic
 type Hasher interface {
-	HashPassword(password string) string
+	Hash(password string) (string, error)
+	Verify(password, encodedHash string) (bool, error)
+	NeedsRehash(encodedHash string) bool
 }
+
+// Constants representing supported hashing algorithms
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported hashing algorithm")
+	ErrInvalidHash          = errors.New("encoded hash is not in the correct format")
+	ErrIncompatibleVersion  = errors.New("incompatible version of argon2")
+)
@@ -2,22 +2,39 @@ package hash
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
 	"fmt"
 )
 
-// SHA1Hasher provides password hashing functionality using SHA1 with a salt
+// SHA1Hasher provides legacy password hashing functionality using SHA1 with a salt.
+// It is kept only to verify hashes created before adaptive hashing was introduced.
 type SHA1Hasher struct {
 	salt string
 }
 
-// New creates a new SHA1Hasher with the provided salt
-func New(salt string) Hasher {
+// NewSHA1Hasher creates a new SHA1Hasher with the provided salt
+func NewSHA1Hasher(salt string) *SHA1Hasher {
 	return &SHA1Hasher{salt: salt}
 }
 
-// HashPassword hashes the provided password using SHA1 and appends the salt
-func (h *SHA1Hasher) HashPassword(password string) string {
+// Hash hashes the provided password using SHA1 and appends the salt
+func (h *SHA1Hasher) Hash(password string) (string, error) {
 	hash := sha1.New()
 	hash.Write([]byte(password))
-	return fmt.Sprintf("%x", hash.Sum([]byte(h.salt)))
+	return fmt.Sprintf("%x", hash.Sum([]byte(h.salt))), nil
+}
+
+// Verify checks whether the password matches the legacy SHA1 hash
+func (h *SHA1Hasher) Verify(password, encodedHash string) (bool, error) {
+	hash, err := h.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(encodedHash)) == 1, nil
+}
+
+// NeedsRehash always reports true since SHA1 hashes must be upgraded
+func (h *SHA1Hasher) NeedsRehash(encodedHash string) bool {
+	return true
 }
@@ -0,0 +1,75 @@
+package hash_test
+
+import (
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/hash"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyHash is the legacy SHA1 hash of "password" salted with "salt", the hex encoded salt followed by the digest
+const legacyHash = "73616c745baa61e4c9b93f3f0682250b6cf8331b7ee68fd8"
+
+// TestSHA1Verify tests verifying passwords against a legacy SHA1 hash
+func TestSHA1Verify(t *testing.T) {
+	testCases := []struct {
+		name        string
+		salt        string
+		password    string
+		encodedHash string
+		expected    bool
+	}{
+		{
+			name:        "Same password",
+			salt:        "salt",
+			password:    "password",
+			encodedHash: legacyHash,
+			expected:    true,
+		},
+		{
+			name:        "Other password",
+			salt:        "salt",
+			password:    "Password",
+			encodedHash: legacyHash,
+			expected:    false,
+		},
+		{
+			name:        "Other salt",
+			salt:        "pepper",
+			password:    "password",
+			encodedHash: legacyHash,
+			expected:    false,
+		},
+		{
+			name:        "Tampered hash",
+			salt:        "salt",
+			password:    "password",
+			encodedHash: legacyHash[:len(legacyHash)-1] + "0",
+			expected:    false,
+		},
+		{
+			name:        "Garbage",
+			salt:        "salt",
+			password:    "password",
+			encodedHash: "not a hash",
+			expected:    false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			hasher := hash.NewSHA1Hasher(testCase.salt)
+
+			ok, err := hasher.Verify(testCase.password, testCase.encodedHash)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, ok)
+			assert.True(t, hasher.NeedsRehash(testCase.encodedHash))
+		})
+	}
+}
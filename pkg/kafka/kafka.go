@@ -4,37 +4,38 @@ import (
 	"github.com/IBM/sarama"
 )
 
-// KafkaClient wraps a Kafka producer and consumer for message publishing and consuming
+// KafkaClient wraps a Kafka producer and a consumer group for message publishing and consuming
 type KafkaClient struct {
-	Producer sarama.SyncProducer
-	Consumer sarama.Consumer
+	Producer      sarama.SyncProducer
+	ConsumerGroup sarama.ConsumerGroup
 }
 
-// New creates a new KafkaClient with the given brokers
-func New(brokers []string, config *sarama.Config) (*KafkaClient, error) {
+// New creates a new KafkaClient with the given brokers, the consumer group shares the consumed
+// partitions and the committed offsets with the other members of the group
+func New(brokers []string, groupId string, config *sarama.Config) (*KafkaClient, error) {
 	producer, err := sarama.NewSyncProducer(brokers, config)
 	if err != nil {
 		return nil, err
 	}
 
-	consumer, err := sarama.NewConsumer(brokers, config)
+	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupId, config)
 	if err != nil {
 		producer.Close()
 		return nil, err
 	}
 
 	return &KafkaClient{
-		Producer: producer,
-		Consumer: consumer,
+		Producer:      producer,
+		ConsumerGroup: consumerGroup,
 	}, nil
 }
 
-// Close closes both the Kafka producer and consumer
+// Close closes both the Kafka producer and the consumer group
 func (kc *KafkaClient) Close() error {
 	if err := kc.Producer.Close(); err != nil {
 		return err
 	}
-	if err := kc.Consumer.Close(); err != nil {
+	if err := kc.ConsumerGroup.Close(); err != nil {
 		return err
 	}
 	return nil
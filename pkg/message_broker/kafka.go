@@ -1,9 +1,13 @@
 package messagebroker
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 
-	"github.com/berikulyBeket/todo-plus/internal/entity"
 	"github.com/berikulyBeket/todo-plus/pkg/kafka"
 	"github.com/berikulyBeket/todo-plus/pkg/logger"
 
@@ -18,8 +22,23 @@ const (
 	ItemCreatedTopic = "item_created"
 	ItemUpdatedTopic = "item_updated"
 	ItemDeletedTopic = "item_deleted"
+
+	ItemReminderDueTopic = "item_reminder_due"
+
+	CommentCreatedTopic = "comment_created"
+)
+
+// Bounds of the delay before a message whose handling failed is handled again, the delay doubles with every failure.
+// A message still failing after handleMaxAttempts attempts is logged and skipped so that it does not block its partition
+const (
+	handleRetryBaseDelay = time.Second
+	handleRetryMaxDelay  = 30 * time.Second
+	handleMaxAttempts    = 10
 )
 
+// joinRetryDelay is the delay before joining the consumer group again after a failed session
+const joinRetryDelay = 5 * time.Second
+
 // KafkaBroker wraps both Kafka producer and consumer
 type KafkaBroker struct {
 	Producer
@@ -32,10 +51,12 @@ type KafkaProducer struct {
 	logger logger.Interface
 }
 
-// KafkaConsumer implements the Consumer interface for consuming events from Kafka
+// KafkaConsumer implements the Consumer interface for consuming events from Kafka with a consumer group
 type KafkaConsumer struct {
-	client *kafka.KafkaClient
-	logger logger.Interface
+	client   *kafka.KafkaClient
+	logger   logger.Interface
+	mu       sync.Mutex
+	handlers map[string]Handler
 }
 
 // New initializes a new KafkaBroker with a Kafka client and logger
@@ -53,154 +74,160 @@ func NewKafkaProducer(client *kafka.KafkaClient, logger logger.Interface) Produc
 
 // NewKafkaConsumer creates a new KafkaConsumer instance
 func NewKafkaConsumer(client *kafka.KafkaClient, logger logger.Interface) Consumer {
-	return &KafkaConsumer{client, logger}
+	return &KafkaConsumer{
+		client:   client,
+		logger:   logger,
+		handlers: map[string]Handler{},
+	}
 }
 
-// PublishListCreatedEvent publishes a list created event to the Kafka topic
-func (p *KafkaProducer) PublishListCreatedEvent(userId int, list *entity.List) {
-	message := entity.ListCreatedEvent{
-		UserId: userId,
-		List:   *list,
+// Publish sends a message to the specified Kafka topic
+func (p *KafkaProducer) Publish(topic string, message []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(message),
 	}
 
-	msgBytes, err := json.Marshal(message)
+	_, _, err := p.client.Producer.SendMessage(msg)
 	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ListCreatedTopic, err)
-		return
+		return err
 	}
 
-	if err := p.Publish(ListCreatedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ListCreatedTopic, err)
-	}
+	return nil
 }
 
-// PublishListUpdatedEvent publishes an event to notify that a list was updated
-func (p *KafkaProducer) PublishListUpdatedEvent(userId, listId int) {
-	message := entity.ListUpdatedEvent{
-		UserId: userId,
-		ListId: listId,
-	}
+// Subscribe registers the handler of the messages of a Kafka topic, a topic has a single handler
+func (c *KafkaConsumer) Subscribe(topic string, handler Handler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	msgBytes, err := json.Marshal(message)
-	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ListUpdatedTopic, err)
-		return
+	if _, ok := c.handlers[topic]; ok {
+		return fmt.Errorf("topic %s already has a handler", topic)
 	}
 
-	if err := p.Publish(ListUpdatedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ListUpdatedTopic, err)
-	}
+	c.handlers[topic] = handler
+
+	return nil
 }
 
-// PublishListDeletedEvent publishes an event to notify that a list was deleted
-func (p *KafkaProducer) PublishListDeletedEvent(listId int) {
-	message := entity.ListDeletedEvent{
-		ListId: listId,
+// Run consumes the subscribed topics as a member of the consumer group until the context is canceled.
+// The group is joined again after every rebalance, Run returns once the claimed partitions are released
+// and the offsets of the handled messages are committed
+func (c *KafkaConsumer) Run(ctx context.Context) error {
+	c.mu.Lock()
+	handlers := make(map[string]Handler, len(c.handlers))
+	topics := make([]string, 0, len(c.handlers))
+	for topic, handler := range c.handlers {
+		handlers[topic] = handler
+		topics = append(topics, topic)
 	}
+	c.mu.Unlock()
 
-	msgBytes, err := json.Marshal(message)
-	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ListDeletedTopic, err)
-		return
+	if len(topics) == 0 {
+		return errors.New("no topics are subscribed")
 	}
+	sort.Strings(topics)
 
-	if err := p.Publish(ListDeletedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ListDeletedTopic, err)
+	groupHandler := &consumerGroupHandler{
+		handlers:       handlers,
+		logger:         c.logger,
+		retryBaseDelay: handleRetryBaseDelay,
+		retryMaxDelay:  handleRetryMaxDelay,
+		maxAttempts:    handleMaxAttempts,
 	}
-}
 
-// PublishItemCreatedEvent publishes an event to notify that an item was created
-func (p *KafkaProducer) PublishItemCreatedEvent(userId, listId int, item *entity.Item) {
-	message := entity.ItemCreatedEvent{
-		UserId: userId,
-		ListId: listId,
-		Item:   *item,
-	}
+	for ctx.Err() == nil {
+		if err := c.client.ConsumerGroup.Consume(ctx, topics, groupHandler); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
 
-	msgBytes, err := json.Marshal(message)
-	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ItemCreatedTopic, err)
-		return
-	}
+			c.logger.Errorf("consumer group session failed, joining again in %s: %v", joinRetryDelay, err)
 
-	if err := p.Publish(ItemCreatedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ItemCreatedTopic, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(joinRetryDelay):
+			}
+		}
 	}
+
+	return nil
 }
 
-// PublishItemUpdatedEvent publishes an event to notify that an item was updated
-func (p *KafkaProducer) PublishItemUpdatedEvent(userId, listId, itemId int) {
-	message := entity.ItemUpdatedEvent{
-		UserId: userId,
-		ListId: listId,
-		ItemId: itemId,
-	}
+// consumerGroupHandler implements sarama.ConsumerGroupHandler, it passes the messages of the claimed partitions
+// to the handlers of their topics and marks them for commit once they are handled
+type consumerGroupHandler struct {
+	handlers       map[string]Handler
+	logger         logger.Interface
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	maxAttempts    int
+}
 
-	msgBytes, err := json.Marshal(message)
-	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ItemUpdatedTopic, err)
-		return
-	}
+// Setup is called when the partitions are assigned to the consumer after a rebalance
+func (h *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	h.logger.Info("consumer group session %d started with partitions %v", session.GenerationID(), session.Claims())
+	return nil
+}
 
-	if err := p.Publish(ItemUpdatedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ItemUpdatedTopic, err)
-	}
+// Cleanup is called when the partitions are revoked, before the offsets marked in the session are committed
+func (h *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	h.logger.Info("consumer group session %d ended", session.GenerationID())
+	return nil
 }
 
-// PublishItemDeletedEvent publishes an event to notify that an item was deleted
-func (p *KafkaProducer) PublishItemDeletedEvent(itemId int) {
-	message := entity.ItemDeletedEvent{
-		ItemId: itemId,
+// ConsumeClaim handles the messages of a partition in order until the partition is revoked.
+// A message is marked only after it is handled or skipped, so a message being retried when the partition
+// is revoked is delivered again to the next owner of the partition
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	handler, ok := h.handlers[claim.Topic()]
+	if !ok {
+		return fmt.Errorf("no handler for topic %s", claim.Topic())
 	}
 
-	msgBytes, err := json.Marshal(message)
-	if err != nil {
-		p.logger.Errorf("failed to marshal %s message: %v", ItemDeletedTopic, err)
-		return
-	}
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
 
-	if err := p.Publish(ItemDeletedTopic, msgBytes); err != nil {
-		p.logger.Errorf("failed to publish event to %s topic: %v", ItemDeletedTopic, err)
-	}
-}
+			if !h.handle(session.Context(), handler, message) {
+				return nil
+			}
 
-// Publish sends a message to the specified Kafka topic
-func (p *KafkaProducer) Publish(topic string, message []byte) error {
-	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.ByteEncoder(message),
+			session.MarkMessage(message, "")
+		case <-session.Context().Done():
+			return nil
+		}
 	}
+}
 
-	_, _, err := p.client.Producer.SendMessage(msg)
-	if err != nil {
-		return err
-	}
+// handle calls the handler until it succeeds or fails maxAttempts times, backing off between the attempts.
+// It reports false when the context is canceled before the message is handled or skipped
+func (h *consumerGroupHandler) handle(ctx context.Context, handler Handler, message *sarama.ConsumerMessage) bool {
+	delay := h.retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, message.Value)
+		if err == nil {
+			return true
+		}
 
-	return nil
-}
+		if attempt >= h.maxAttempts {
+			h.logger.Errorf("skipping message %d of topic %s partition %d after %d attempts: %v",
+				message.Offset, message.Topic, message.Partition, attempt, err)
+			return true
+		}
 
-// Subscribe subscribes to a Kafka topic and handles incoming messages using the provided handler function
-func (p *KafkaConsumer) Subscribe(topic string, handler func(message []byte) error) error {
-	partitionList, err := p.client.Consumer.Partitions(topic)
-	if err != nil {
-		return err
-	}
+		h.logger.Errorf("error handling message %d of topic %s partition %d, retrying in %s: %v",
+			message.Offset, message.Topic, message.Partition, delay, err)
 
-	for _, partition := range partitionList {
-		pc, err := p.client.Consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
-		if err != nil {
-			return err
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
 		}
 
-		go func(pc sarama.PartitionConsumer) {
-			defer pc.Close()
-			for message := range pc.Messages() {
-				if err := handler(message.Value); err != nil {
-					p.logger.Errorf("error handling message from topic %s: %v", topic, err)
-				}
-			}
-		}(pc)
+		delay = min(delay*2, h.retryMaxDelay)
 	}
-
-	return nil
 }
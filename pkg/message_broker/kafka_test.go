@@ -0,0 +1,183 @@
+package messagebroker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/pkg/logger"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSession implements sarama.ConsumerGroupSession and records the offsets of the marked messages
+type fakeSession struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Claims() map[string][]int32                                        { return nil }
+func (s *fakeSession) MemberID() string                                                  { return "member" }
+func (s *fakeSession) GenerationID() int32                                               { return 1 }
+func (s *fakeSession) MarkOffset(topic string, partition int32, offset int64, _ string)  {}
+func (s *fakeSession) Commit()                                                           {}
+func (s *fakeSession) ResetOffset(topic string, partition int32, offset int64, _ string) {}
+func (s *fakeSession) Context() context.Context                                          { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.marked = append(s.marked, msg.Offset)
+}
+
+// markedOffsets returns the offsets of the messages marked so far
+func (s *fakeSession) markedOffsets() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]int64{}, s.marked...)
+}
+
+// fakeClaim implements sarama.ConsumerGroupClaim over a channel of messages
+type fakeClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Topic() string                            { return ItemUpdatedTopic }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// newFakeClaim creates a claim delivering messages holding their offsets, the claim is closed after them
+func newFakeClaim(offsets ...int64) *fakeClaim {
+	messages := make(chan *sarama.ConsumerMessage, len(offsets))
+	for _, offset := range offsets {
+		messages <- &sarama.ConsumerMessage{Topic: ItemUpdatedTopic, Offset: offset, Value: []byte(strconv.FormatInt(offset, 10))}
+	}
+	close(messages)
+
+	return &fakeClaim{messages: messages}
+}
+
+// newTestGroupHandler creates a consumerGroupHandler with short retry delays for the handler of ItemUpdatedTopic
+func newTestGroupHandler(handler Handler, retryBaseDelay time.Duration, maxAttempts int) *consumerGroupHandler {
+	return &consumerGroupHandler{
+		handlers:       map[string]Handler{ItemUpdatedTopic: handler},
+		logger:         &logger.NoOpLogger{},
+		retryBaseDelay: retryBaseDelay,
+		retryMaxDelay:  retryBaseDelay,
+		maxAttempts:    maxAttempts,
+	}
+}
+
+// TestConsumeClaim tests that a message is marked only once it is handled or skipped after its last attempt
+func TestConsumeClaim(t *testing.T) {
+	testCases := []struct {
+		name             string
+		failures         map[int64]int
+		maxAttempts      int
+		expectedAttempts map[int64]int
+		expectedMarked   []int64
+	}{
+		{
+			name:             "Every message is handled",
+			failures:         map[int64]int{},
+			maxAttempts:      3,
+			expectedAttempts: map[int64]int{4: 1, 5: 1},
+			expectedMarked:   []int64{4, 5},
+		},
+		{
+			name:             "Failed message is marked after it succeeds",
+			failures:         map[int64]int{4: 2},
+			maxAttempts:      3,
+			expectedAttempts: map[int64]int{4: 3, 5: 1},
+			expectedMarked:   []int64{4, 5},
+		},
+		{
+			name:             "Message is skipped after its last attempt",
+			failures:         map[int64]int{4: 10},
+			maxAttempts:      3,
+			expectedAttempts: map[int64]int{4: 3, 5: 1},
+			expectedMarked:   []int64{4, 5},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			session := &fakeSession{ctx: context.Background()}
+			claim := newFakeClaim(4, 5)
+
+			attempts := map[int64]int{}
+			groupHandler := newTestGroupHandler(func(ctx context.Context, message []byte) error {
+				offset, err := strconv.ParseInt(string(message), 10, 64)
+				assert.NoError(t, err)
+				assert.NotContains(t, session.markedOffsets(), offset, "message marked before it was handled")
+
+				attempts[offset]++
+				if attempts[offset] <= testCase.failures[offset] {
+					return errors.New("search unavailable")
+				}
+
+				return nil
+			}, time.Millisecond, testCase.maxAttempts)
+
+			err := groupHandler.ConsumeClaim(session, claim)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedAttempts, attempts)
+			assert.Equal(t, testCase.expectedMarked, session.markedOffsets())
+		})
+	}
+}
+
+// TestConsumeClaimCanceled tests that a message being retried is not marked when the context is canceled
+func TestConsumeClaimCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := &fakeSession{ctx: ctx}
+	claim := newFakeClaim(4, 5)
+
+	attempts := 0
+	groupHandler := newTestGroupHandler(func(ctx context.Context, message []byte) error {
+		attempts++
+		cancel()
+		return errors.New("search unavailable")
+	}, time.Hour, 10)
+
+	done := make(chan error)
+	go func() {
+		done <- groupHandler.ConsumeClaim(session, claim)
+	}()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry did not stop when the context was canceled")
+	}
+
+	assert.Equal(t, 1, attempts)
+	assert.Empty(t, session.markedOffsets())
+}
+
+// TestSubscribe tests that a topic accepts a single handler
+func TestSubscribe(t *testing.T) {
+	consumer := NewKafkaConsumer(nil, &logger.NoOpLogger{})
+	handler := func(ctx context.Context, message []byte) error { return nil }
+
+	assert.NoError(t, consumer.Subscribe(ItemUpdatedTopic, handler))
+	assert.Error(t, consumer.Subscribe(ItemUpdatedTopic, handler))
+	assert.NoError(t, consumer.Subscribe(ItemDeletedTopic, handler))
+}
@@ -1,18 +1,20 @@
 package messagebroker
 
-import "github.com/berikulyBeket/todo-plus/internal/entity"
+import "context"
 
-// Producer defines the methods for publishing events related to lists and items
+// Producer defines the method for publishing events, the events are recorded in the outbox
+// and published as encoded messages by its relay
 type Producer interface {
-	PublishListCreatedEvent(userId int, list *entity.List)
-	PublishListUpdatedEvent(userId, listId int)
-	PublishListDeletedEvent(listId int)
-	PublishItemCreatedEvent(userId, listId int, item *entity.Item)
-	PublishItemUpdatedEvent(userId, listId, itemId int)
-	PublishItemDeletedEvent(itemId int)
+	Publish(topic string, message []byte) error
 }
 
-// Consumer defines the method for subscribing to Kafka topics
+// Consumer defines the methods for consuming Kafka topics as a member of a consumer group, the handlers
+// of the topics are subscribed before Run joins the group
 type Consumer interface {
-	Subscribe(topic string, handler func(message []byte) error) error
+	Subscribe(topic string, handler Handler) error
+	Run(ctx context.Context) error
 }
+
+// Handler handles a message of a topic, a failed message is handled again until the handler succeeds or runs out
+// of attempts and only then its offset is committed. The context is canceled when the partition of the message is revoked
+type Handler func(ctx context.Context, message []byte) error
@@ -13,4 +13,7 @@ type Interface interface {
 	IncrementCreatedItems()
 	IncrementDeletedItems()
 	IncrementSearchedItems()
+	AddPublishedOutboxEvents(count int)
+	IncrementFailedOutboxRelays()
+	SetOutboxBacklog(pending int, oldestAge float64)
 }
@@ -14,3 +14,6 @@ func (n *NoOpMetrics) IncrementSearchedLists()                                {}
 func (n *NoOpMetrics) IncrementCreatedItems()                                 {}
 func (n *NoOpMetrics) IncrementDeletedItems()                                 {}
 func (n *NoOpMetrics) IncrementSearchedItems()                                {}
+func (n *NoOpMetrics) AddPublishedOutboxEvents(count int)                     {}
+func (n *NoOpMetrics) IncrementFailedOutboxRelays()                           {}
+func (n *NoOpMetrics) SetOutboxBacklog(pending int, oldestAge float64)        {}
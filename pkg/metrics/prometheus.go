@@ -17,6 +17,11 @@ type PromotheusMetrics struct {
 	createdItemsTotal  prometheus.Counter
 	deletedItemsTotal  prometheus.Counter
 	searchedItemsTotal prometheus.Counter
+
+	outboxPublishedTotal     prometheus.Counter
+	outboxRelayFailuresTotal prometheus.Counter
+	outboxPendingEvents      prometheus.Gauge
+	outboxOldestEventAge     prometheus.Gauge
 }
 
 // New initializes and returns a PromotheusMetrics instance with counters and histograms for tracking metrics
@@ -73,6 +78,22 @@ func New() Interface {
 			Name: "searched_items_total",
 			Help: "Total number of successful searched items",
 		}),
+		outboxPublishedTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "outbox_published_events_total",
+			Help: "Total number of outbox events published to the message broker",
+		}),
+		outboxRelayFailuresTotal: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "outbox_relay_failures_total",
+			Help: "Total number of outbox relay runs stopped by an error",
+		}),
+		outboxPendingEvents: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "outbox_pending_events",
+			Help: "Number of outbox events waiting to be published",
+		}),
+		outboxOldestEventAge: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "outbox_oldest_pending_event_age_seconds",
+			Help: "Age in seconds of the oldest outbox event waiting to be published",
+		}),
 	}
 }
 
@@ -130,3 +151,19 @@ func (m *PromotheusMetrics) IncrementDeletedItems() {
 func (m *PromotheusMetrics) IncrementSearchedItems() {
 	m.searchedItemsTotal.Inc()
 }
+
+// AddPublishedOutboxEvents adds to the counter of outbox events published to the message broker
+func (m *PromotheusMetrics) AddPublishedOutboxEvents(count int) {
+	m.outboxPublishedTotal.Add(float64(count))
+}
+
+// IncrementFailedOutboxRelays increments the counter for outbox relay runs stopped by an error
+func (m *PromotheusMetrics) IncrementFailedOutboxRelays() {
+	m.outboxRelayFailuresTotal.Inc()
+}
+
+// SetOutboxBacklog sets the number of pending outbox events and the age in seconds of the oldest of them
+func (m *PromotheusMetrics) SetOutboxBacklog(pending int, oldestAge float64) {
+	m.outboxPendingEvents.Set(float64(pending))
+	m.outboxOldestEventAge.Set(oldestAge)
+}
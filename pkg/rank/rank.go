@@ -0,0 +1,234 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// A rank is made of an integer part followed by an optional fraction. The head of the integer part tells its
+// length, 'a' to 'z' start integers of 1 to 26 digits and 'A' to 'Z' negative integers of 26 to 1 digits, so that
+// appending to or prepending to an order only increments or decrements the integer part and ranks grow
+// logarithmically. Inserting between two ranks with the same integer part extends the fraction.
+// Ranks must be compared byte-wise, such as with the "C" collation in Postgres.
+
+// digits are the digits of a rank in ascending byte order
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const (
+	// zero is the rank of the first row of an empty order
+	zero = "a0"
+
+	// smallestInteger cannot be decremented, it is not a valid rank on its own so that
+	// a rank can always be generated ahead of any other rank
+	smallestInteger = "A00000000000000000000000000"
+)
+
+var (
+	// ErrInvalidRange is returned when the bounds are not valid ranks or are not in ascending order
+	ErrInvalidRange = errors.New("invalid rank range")
+
+	// ErrExhausted is returned when no integer is left past the end of the order
+	ErrExhausted = errors.New("rank space exhausted")
+)
+
+// Between returns a rank that sorts after before and ahead of after. An empty before stands for the start
+// and an empty after for the end of the order
+func Between(before, after string) (string, error) {
+	if !valid(before) || !valid(after) || (before != "" && after != "" && before >= after) {
+		return "", ErrInvalidRange
+	}
+
+	if before == "" {
+		if after == "" {
+			return zero, nil
+		}
+
+		integer := integerPart(after)
+		if integer == smallestInteger {
+			return integer + midpoint("", after[len(integer):]), nil
+		}
+		if integer < after {
+			return integer, nil
+		}
+
+		return decrement(integer)
+	}
+
+	integer := integerPart(before)
+	fraction := before[len(integer):]
+
+	if after == "" {
+		next, err := increment(integer)
+		if err == ErrExhausted {
+			return integer + midpoint(fraction, ""), nil
+		}
+
+		return next, err
+	}
+
+	afterInteger := integerPart(after)
+	if integer == afterInteger {
+		return integer + midpoint(fraction, after[len(afterInteger):]), nil
+	}
+
+	next, err := increment(integer)
+	if err != nil && err != ErrExhausted {
+		return "", err
+	}
+	if err == nil && next < after {
+		return next, nil
+	}
+
+	return integer + midpoint(fraction, ""), nil
+}
+
+// midpoint returns a fraction between a and b, an empty b stands for the end of the order.
+// Fractions never end with the smallest digit so that another fraction always fits between two of them
+func midpoint(a, b string) string {
+	if b != "" {
+		// copy the common prefix, a is padded with the smallest digit
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+
+	// the leading digits are consecutive
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(digits[digitA]) + midpoint(suffix(a, 1), "")
+}
+
+// increment returns the integer following integer, ErrExhausted is returned past the largest integer
+func increment(integer string) (string, error) {
+	head, value := integer[0], []byte(integer[1:])
+
+	for i := len(value) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, value[i]) + 1
+		if d < len(digits) {
+			value[i] = digits[d]
+			return string(head) + string(value), nil
+		}
+		value[i] = digits[0]
+	}
+
+	switch {
+	case head == 'Z':
+		return zero, nil
+	case head == 'z':
+		return "", ErrExhausted
+	case head+1 > 'a':
+		value = append(value, digits[0])
+	default:
+		value = value[:len(value)-1]
+	}
+
+	return string(head+1) + string(value), nil
+}
+
+// decrement returns the integer preceding integer, ErrExhausted is returned past the smallest integer
+func decrement(integer string) (string, error) {
+	head, value := integer[0], []byte(integer[1:])
+	largest := digits[len(digits)-1]
+
+	for i := len(value) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, value[i]) - 1
+		if d >= 0 {
+			value[i] = digits[d]
+			return string(head) + string(value), nil
+		}
+		value[i] = largest
+	}
+
+	switch {
+	case head == 'a':
+		return "Z" + string(largest), nil
+	case head == 'A':
+		return "", ErrExhausted
+	case head-1 < 'Z':
+		value = append(value, largest)
+	default:
+		value = value[:len(value)-1]
+	}
+
+	return string(head-1) + string(value), nil
+}
+
+// integerPart returns the integer part of a rank with a valid head
+func integerPart(value string) string {
+	length := integerLength(value[0])
+	if length > len(value) {
+		return value
+	}
+
+	return value[:length]
+}
+
+// integerLength returns the length of an integer part including its head, 0 for an invalid head
+func integerLength(head byte) int {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2
+	default:
+		return 0
+	}
+}
+
+// valid reports whether value is empty or a well-formed rank
+func valid(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	length := integerLength(value[0])
+	if length == 0 || length > len(value) || value[:length] == smallestInteger {
+		return false
+	}
+
+	for i := 1; i < len(value); i++ {
+		if strings.IndexByte(digits, value[i]) < 0 {
+			return false
+		}
+	}
+
+	return len(value) == length || value[len(value)-1] != digits[0]
+}
+
+// digitAt returns the digit at index i of value, the smallest digit past its end
+func digitAt(value string, i int) byte {
+	if i < len(value) {
+		return value[i]
+	}
+
+	return digits[0]
+}
+
+// suffix returns value without its first n bytes
+func suffix(value string, n int) string {
+	if n < len(value) {
+		return value[n:]
+	}
+
+	return ""
+}
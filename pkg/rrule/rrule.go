@@ -0,0 +1,275 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a recurrence rule
+type Frequency string
+
+// Supported frequencies
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds the number of periods walked when looking for the next occurrence
+const maxPeriods = 100000
+
+// ErrInvalidRule is returned for rules that are malformed or use unsupported parts
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// Rule is a subset of the iCalendar RRULE (RFC 5545) supporting FREQ, INTERVAL, COUNT, UNTIL,
+// BYDAY for weekly rules and BYMONTHDAY for monthly rules
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", an optional "RRULE:" prefix is accepted
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(val)
+		case "COUNT":
+			rule.Count, err = parsePositive(val)
+		case "UNTIL":
+			rule.Until, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(val)
+		case "WKST":
+			if _, ok := weekdays[strings.ToUpper(val)]; !ok {
+				err = fmt.Errorf("unknown weekday %q", val)
+			}
+		default:
+			err = fmt.Errorf("unsupported part %q", key)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	return rule, nil
+}
+
+// Next returns the first occurrence after the given time of the series starting at dtstart, occurrence is the
+// 1-based number of the occurrence at after and is checked against COUNT. It reports false when the series has
+// ended. Occurrences keep the wall clock time of dtstart in its location
+func (r Rule) Next(dtstart, after time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	after = after.In(dtstart.Location())
+
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.period(dtstart, period) {
+			if candidate.Before(dtstart) || !candidate.After(after) {
+				continue
+			}
+
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+
+			return candidate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// period returns the sorted occurrences of the n-th period of the series starting at dtstart
+func (r Rule) period(dtstart time.Time, n int) []time.Time {
+	year, month, day := dtstart.Date()
+	hour, min, sec := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, dtstart.Nanosecond(), loc)
+	}
+
+	step := n * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(year, month, day+step)}
+	case Weekly:
+		byDay := r.ByDay
+		if len(byDay) == 0 {
+			byDay = []time.Weekday{dtstart.Weekday()}
+		}
+
+		weekStart := day - mondayOffset(dtstart.Weekday()) + step*7
+
+		occurrences := make([]time.Time, 0, len(byDay))
+		for _, weekday := range byDay {
+			occurrences = append(occurrences, at(year, month, weekStart+mondayOffset(weekday)))
+		}
+		sortTimes(occurrences)
+
+		return occurrences
+	case Monthly:
+		byMonthDay := r.ByMonthDay
+		if len(byMonthDay) == 0 {
+			byMonthDay = []int{day}
+		}
+
+		first := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, loc)
+		daysInMonth := first.AddDate(0, 1, -1).Day()
+
+		occurrences := make([]time.Time, 0, len(byMonthDay))
+		for _, monthDay := range byMonthDay {
+			if monthDay < 0 {
+				monthDay = daysInMonth + monthDay + 1
+			}
+			if monthDay < 1 || monthDay > daysInMonth {
+				continue
+			}
+			occurrences = append(occurrences, at(first.Year(), first.Month(), monthDay))
+		}
+		sortTimes(occurrences)
+
+		return occurrences
+	case Yearly:
+		occurrence := at(year+step, month, day)
+		if occurrence.Month() != month {
+			return nil
+		}
+
+		return []time.Time{occurrence}
+	}
+
+	return nil
+}
+
+// validate checks that the parts of the rule fit together
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return errors.New("FREQ is required")
+	default:
+		return fmt.Errorf("unsupported frequency %q", r.Freq)
+	}
+
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT and UNTIL cannot be combined")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return errors.New("BYDAY is only supported for weekly rules")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly {
+		return errors.New("BYMONTHDAY is only supported for monthly rules")
+	}
+
+	return nil
+}
+
+// parsePositive parses a positive integer
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive integer", value)
+	}
+
+	return n, nil
+}
+
+// parseUntil parses an UNTIL date or date-time, values without a zone are read as UTC
+func parseUntil(value string) (*time.Time, error) {
+	for _, layout := range untilLayouts {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				until = until.Add(24*time.Hour - time.Nanosecond)
+			}
+			return &until, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+// parseByDay parses a comma separated list of weekdays such as "MO,WE,FR"
+func parseByDay(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := weekdays[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY value %q", name)
+		}
+		days = append(days, weekday)
+	}
+
+	return days, nil
+}
+
+// parseByMonthDay parses a comma separated list of month days, negative days count from the end of the month
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY value %q", item)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+// mondayOffset returns the number of days between Monday and the weekday
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// sortTimes sorts times in ascending order
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
 
@@ -23,8 +24,9 @@ func NewItemSearch(client *elasticsearch.Client) Item {
 	return &ItemSearch{client: client}
 }
 
-// SearchIds performs a search with optional filtering by listId and done status, and a full-text search on items
-func (ls *ItemSearch) SearchIds(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]int, error) {
+// Search performs a full-text search on the items of the given lists with optional filtering by done status and tags.
+// Items must have every one of the given tags, a page of the hits is returned
+func (ls *ItemSearch) Search(ctx context.Context, listIds []int, done *bool, tagIds []int, searchText string, page Page) ([]Hit, error) {
 	query := map[string]interface{}{
 		"bool": map[string]interface{}{
 			"must": []interface{}{
@@ -38,26 +40,26 @@ func (ls *ItemSearch) SearchIds(ctx context.Context, userId int, listId *int, do
 			},
 			"filter": []interface{}{
 				map[string]interface{}{
-					"term": map[string]interface{}{
-						"userId": userId,
+					"terms": map[string]interface{}{
+						"listId": listIds,
 					},
 				},
 			},
 		},
 	}
 
-	if listId != nil {
+	if done != nil {
 		query["bool"].(map[string]interface{})["filter"] = append(query["bool"].(map[string]interface{})["filter"].([]interface{}), map[string]interface{}{
 			"term": map[string]interface{}{
-				"listId": *listId,
+				"done": *done,
 			},
 		})
 	}
 
-	if done != nil {
+	for _, tagId := range tagIds {
 		query["bool"].(map[string]interface{})["filter"] = append(query["bool"].(map[string]interface{})["filter"].([]interface{}), map[string]interface{}{
 			"term": map[string]interface{}{
-				"done": *done,
+				"tags": tagId,
 			},
 		})
 	}
@@ -66,6 +68,7 @@ func (ls *ItemSearch) SearchIds(ctx context.Context, userId int, listId *int, do
 		"query":   query,
 		"_source": []string{"id"},
 	}
+	applyPage(body, page)
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(body); err != nil {
@@ -97,7 +100,7 @@ func (ls *ItemSearch) SearchIds(ctx context.Context, userId int, listId *int, do
 		return nil, fmt.Errorf("error parsing hits from search response")
 	}
 
-	var ids []int
+	var results []Hit
 	for _, hit := range hits {
 		source, ok := hit.(map[string]interface{})["_source"].(map[string]interface{})
 		if !ok {
@@ -109,22 +112,23 @@ func (ls *ItemSearch) SearchIds(ctx context.Context, userId int, listId *int, do
 			continue
 		}
 
-		id := int(idFloat)
-		ids = append(ids, id)
+		results = append(results, Hit{Id: int(idFloat), Score: hitScore(hit.(map[string]interface{}))})
 	}
 
-	return ids, nil
+	return results, nil
 }
 
-// Index indexes a new item in Elasticsearch with the given userId, listId, and item details
-func (ls *ItemSearch) Index(ctx context.Context, userId int, listId int, item entity.Item) error {
+// Index indexes a new item in Elasticsearch with the given listId, item details and the Ids of the tags of every user on it
+func (ls *ItemSearch) Index(ctx context.Context, listId int, item entity.Item, tagIds []int) error {
 	document := map[string]interface{}{
 		"id":          item.Id,
-		"userId":      userId,
 		"listId":      listId,
 		"title":       item.Title,
 		"description": item.Description,
 		"done":        item.Done,
+		"dueAt":       item.DueAt,
+		"remindAt":    item.RemindAt,
+		"tags":        tagIds,
 	}
 
 	data, err := json.Marshal(document)
@@ -152,7 +156,7 @@ func (ls *ItemSearch) Index(ctx context.Context, userId int, listId int, item en
 	return nil
 }
 
-// Delete removes an item from Elasticsearch by its itemId
+// Delete removes an item from Elasticsearch by its itemId, a missing document is already removed
 func (ls *ItemSearch) Delete(ctx context.Context, itemId int) error {
 	itemIdStr := fmt.Sprintf("%d", itemId)
 
@@ -166,6 +170,10 @@ func (ls *ItemSearch) Delete(ctx context.Context, itemId int) error {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
 	if res.IsError() {
 		return fmt.Errorf("error response from Elasticsearch: %s", res.String())
 	}
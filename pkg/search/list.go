@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/berikulyBeket/todo-plus/internal/entity"
@@ -24,8 +25,9 @@ func NewListSearch(client *elasticsearch.Client) List {
 	return &ListSearch{client: client}
 }
 
-// SearchIds performs a search for lists owned by a user, with optional filtering by a search term
-func (ls *ListSearch) SearchIds(ctx context.Context, userId int, searchText string) ([]int, error) {
+// Search performs a search among the given lists, with optional filtering by a search term,
+// a page of the hits is returned
+func (ls *ListSearch) Search(ctx context.Context, listIds []int, searchText string, page Page) ([]Hit, error) {
 	searchQuery := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
@@ -40,8 +42,8 @@ func (ls *ListSearch) SearchIds(ctx context.Context, userId int, searchText stri
 				},
 				"filter": []interface{}{
 					map[string]interface{}{
-						"term": map[string]interface{}{
-							"userId": userId,
+						"terms": map[string]interface{}{
+							"id": listIds,
 						},
 					},
 				},
@@ -49,6 +51,7 @@ func (ls *ListSearch) SearchIds(ctx context.Context, userId int, searchText stri
 		},
 		"_source": []string{"id"},
 	}
+	applyPage(searchQuery, page)
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
@@ -80,31 +83,31 @@ func (ls *ListSearch) SearchIds(ctx context.Context, userId int, searchText stri
 		return nil, fmt.Errorf("error parsing hits from search response")
 	}
 
-	var ids []int
+	var results []Hit
 	for _, hit := range hits {
 		source, ok := hit.(map[string]interface{})["_source"].(map[string]interface{})
 		if !ok {
 			continue
 		}
+		score := hitScore(hit.(map[string]interface{}))
 		idStr := source["id"]
 		switch v := idStr.(type) {
 		case float64:
-			ids = append(ids, int(v))
+			results = append(results, Hit{Id: int(v), Score: score})
 		case string:
 			id, err := strconv.Atoi(v)
 			if err == nil {
-				ids = append(ids, id)
+				results = append(results, Hit{Id: id, Score: score})
 			}
 		}
 	}
-	return ids, nil
+	return results, nil
 }
 
-// Index indexes a new list in Elasticsearch with the given userId and list details
-func (ls *ListSearch) Index(ctx context.Context, userId int, list *entity.List) error {
+// Index indexes a new list in Elasticsearch with the given list details
+func (ls *ListSearch) Index(ctx context.Context, list *entity.List) error {
 	document := map[string]interface{}{
 		"id":          list.Id,
-		"userId":      userId,
 		"title":       list.Title,
 		"description": list.Description,
 	}
@@ -135,7 +138,7 @@ func (ls *ListSearch) Index(ctx context.Context, userId int, list *entity.List)
 	return nil
 }
 
-// Delete removes a list from Elasticsearch by its listId
+// Delete removes a list from Elasticsearch by its listId, a missing document is already removed
 func (ls *ListSearch) Delete(ctx context.Context, listId int) error {
 	listIdStr := fmt.Sprintf("%d", listId)
 
@@ -149,6 +152,10 @@ func (ls *ListSearch) Delete(ctx context.Context, listId int) error {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
 	if res.IsError() {
 		return fmt.Errorf("error response from Elasticsearch: %s", res.String())
 	}
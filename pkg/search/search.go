@@ -10,18 +10,32 @@ import (
 
 // List defines the interface for searching, indexing, and deleting lists in Elasticsearch
 type List interface {
-	SearchIds(ctx context.Context, userId int, searchText string) ([]int, error)
-	Index(ctx context.Context, userId int, list *entity.List) error
+	Search(ctx context.Context, listIds []int, searchText string, page Page) ([]Hit, error)
+	Index(ctx context.Context, list *entity.List) error
 	Delete(ctx context.Context, listId int) error
 }
 
 // Item defines the interface for searching, indexing, and deleting items in Elasticsearch
 type Item interface {
-	SearchIds(ctx context.Context, userId int, listId *int, done *bool, searchText string) ([]int, error)
-	Index(ctx context.Context, userId int, listId int, item entity.Item) error
+	Search(ctx context.Context, listIds []int, done *bool, tagIds []int, searchText string, page Page) ([]Hit, error)
+	Index(ctx context.Context, listId int, item entity.Item, tagIds []int) error
 	Delete(ctx context.Context, itemId int) error
 }
 
+// Page selects a page of hits sorted by relevance with the Id breaking ties, After holds the score and the Id
+// of the last hit of the previous page
+type Page struct {
+	Size       int
+	Descending bool
+	After      []interface{}
+}
+
+// Hit represents a document matching a search together with its relevance score
+type Hit struct {
+	Id    int
+	Score float64
+}
+
 // SearchServices aggregates the List and Item search services into one struct
 type SearchServices struct {
 	List
@@ -35,3 +49,33 @@ func New(client *elasticsearch.Client) *SearchServices {
 		Item: NewItemSearch(client),
 	}
 }
+
+// applyPage adds the size, the sort and the position of the page to the body of a search request
+func applyPage(body map[string]interface{}, page Page) {
+	order := "asc"
+	if page.Descending {
+		order = "desc"
+	}
+
+	body["size"] = page.Size
+	body["sort"] = []interface{}{
+		map[string]interface{}{"_score": order},
+		map[string]interface{}{"id": order},
+	}
+
+	if page.After != nil {
+		body["search_after"] = page.After
+	}
+}
+
+// hitScore reads the relevance score from the sort values of a hit
+func hitScore(hit map[string]interface{}) float64 {
+	sort, ok := hit["sort"].([]interface{})
+	if !ok || len(sort) == 0 {
+		return 0
+	}
+
+	score, _ := sort[0].(float64)
+
+	return score
+}
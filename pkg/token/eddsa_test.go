@@ -0,0 +1,78 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEdDSASignAndVerify tests signing with an Ed25519 private key and verifying with its public key
+func TestEdDSASignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, token.EdDSA, jwt.GetSigningMethod("EdDSA"))
+
+	signature, err := token.EdDSA.Sign("header.claims", privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		signingString string
+		key           interface{}
+		expectErr     bool
+	}{
+		{
+			name:          "Valid signature",
+			signingString: "header.claims",
+			key:           publicKey,
+			expectErr:     false,
+		},
+		{
+			name:          "Tampered claims",
+			signingString: "header.tampered",
+			key:           publicKey,
+			expectErr:     true,
+		},
+		{
+			name:          "Other public key",
+			signingString: "header.claims",
+			key:           otherPublicKey,
+			expectErr:     true,
+		},
+		{
+			name:          "Not an Ed25519 key",
+			signingString: "header.claims",
+			key:           []byte("secret"),
+			expectErr:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := token.EdDSA.Verify(testCase.signingString, signature, testCase.key)
+
+			if testCase.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err = token.EdDSA.Sign("header.claims", publicKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+const (
+	// InvitationTokenPrefix marks list invitation link tokens
+	InvitationTokenPrefix = "tdi_"
+
+	invitationTokenBytes = 32
+)
+
+// NewInvitationToken generates a random prefixed token for an invitation link
+func NewInvitationToken() (string, error) {
+	b := make([]byte, invitationTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return InvitationTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,64 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJWKS tests that the public keys of a key set are published ordered by kid
+func TestJWKS(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaKeyPEM(t)
+	edPrivate, edPublic := ed25519KeyPEM(t)
+
+	keySet, err := token.NewKeySet("b-rsa",
+		parseKey(t, "b-rsa", jwt.SigningMethodRS256, rsaPrivate),
+		parseKey(t, "a-ed", token.EdDSA, edPrivate),
+	)
+	require.NoError(t, err)
+
+	rsaBlock, _ := pem.Decode(rsaPublic)
+	rsaKey, err := x509.ParsePKIXPublicKey(rsaBlock.Bytes)
+	require.NoError(t, err)
+
+	edBlock, _ := pem.Decode(edPublic)
+	edKey, err := x509.ParsePKIXPublicKey(edBlock.Bytes)
+	require.NoError(t, err)
+
+	expected := token.JWKS{Keys: []token.JWK{
+		{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: "EdDSA",
+			Kid: "a-ed",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edKey.(ed25519.PublicKey)),
+		},
+		{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: "b-rsa",
+			N:   base64.RawURLEncoding.EncodeToString(rsaKey.(*rsa.PublicKey).N.Bytes()),
+			E:   "AQAB",
+		},
+	}}
+
+	assert.Equal(t, expected, keySet.JWKS())
+}
+
+// TestJWKSSymmetricKey tests that a shared secret is never published
+func TestJWKSSymmetricKey(t *testing.T) {
+	keySet := token.NewHMACKeySet("secret")
+
+	assert.Equal(t, token.JWKS{Keys: []token.JWK{}}, keySet.JWKS())
+}
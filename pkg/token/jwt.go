@@ -9,55 +9,116 @@ import (
 
 // JWTMaker is responsible for creating and verifying JWT tokens.
 type JWTMaker struct {
-	signingKey string
-	tokenTTL   time.Duration
+	keySet          *KeySet
+	tokenTTL        time.Duration
+	refreshTokenTTL time.Duration
 }
 
 // tokenClaims holds the custom claims for the JWT token, embedding the StandardClaims from the jwt package.
 type tokenClaims struct {
 	jwt.StandardClaims
-	UserId int `json:"user_id"`
+	UserId int    `json:"user_id"`
+	Role   string `json:"role"`
 }
 
-// NewJWTMaker creates a new JWTMaker with the given signing key and token time-to-live (TTL).
-func New(signingKey string, tokenTTL time.Duration) TokenMaker {
+// New creates a new JWTMaker signing with a shared HS256 key, access token and refresh token time-to-live (TTL).
+func New(signingKey string, tokenTTL, refreshTokenTTL time.Duration) TokenMaker {
+	return NewWithKeySet(NewHMACKeySet(signingKey), tokenTTL, refreshTokenTTL)
+}
+
+// NewWithKeySet creates a new JWTMaker signing with the active key of the key set and verifying with any of its keys.
+func NewWithKeySet(keySet *KeySet, tokenTTL, refreshTokenTTL time.Duration) TokenMaker {
 	return &JWTMaker{
-		signingKey: signingKey,
-		tokenTTL:   tokenTTL,
+		keySet:          keySet,
+		tokenTTL:        tokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
 	}
 }
 
-// CreateToken generates a new JWT token with a user ID, signed using the provided signing key.
-func (j *JWTMaker) CreateToken(userId int) (string, error) {
+// CreateToken generates a new JWT token with a user ID, role and a unique token ID, signed using the active key and tagged with its kid.
+func (j *JWTMaker) CreateToken(userId int, role string) (string, *Payload, error) {
+	tokenId, err := NewTokenId()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	payload := &Payload{
+		TokenId:   tokenId,
+		UserId:    userId,
+		Role:      role,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(j.tokenTTL),
+	}
+
 	claims := &tokenClaims{
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(j.tokenTTL).Unix(),
-			IssuedAt:  time.Now().Unix(),
+			Id:        payload.TokenId,
+			ExpiresAt: payload.ExpiresAt.Unix(),
+			IssuedAt:  payload.IssuedAt.Unix(),
 		},
 		UserId: userId,
+		Role:   role,
+	}
+
+	key := j.keySet.signingKey()
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.Id != "" {
+		token.Header["kid"] = key.Id
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.signingKey))
+	signed, err := token.SignedString(key.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, payload, nil
 }
 
-// VerifyToken parses and validates a given JWT token string, returning the user ID if valid.
-func (j *JWTMaker) VerifyToken(tokenString string) (int, error) {
+// VerifyToken parses and validates a given JWT token string, returning its payload if valid.
+func (j *JWTMaker) VerifyToken(tokenString string) (*Payload, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, err := j.keySet.verificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("invalid signing method")
 		}
-		return []byte(j.signingKey), nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok || !token.Valid {
-		return 0, errors.New("invalid token")
+		return nil, errors.New("invalid token")
+	}
+
+	return &Payload{
+		TokenId:   claims.Id,
+		UserId:    claims.UserId,
+		Role:      claims.Role,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// CreateRefreshToken generates a new opaque refresh token and its expiration time.
+func (j *JWTMaker) CreateRefreshToken() (string, time.Time, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
-	return claims.UserId, nil
+	return refreshToken, time.Now().Add(j.refreshTokenTTL), nil
+}
+
+// JWKS returns the public keys that verify tokens issued by this maker
+func (j *JWTMaker) JWKS() JWKS {
+	return j.keySet.JWKS()
 }
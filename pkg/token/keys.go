@@ -0,0 +1,197 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownKeyId         = errors.New("unknown signing key id")
+	ErrNoSigningKey         = errors.New("active key has no private key to sign with")
+	ErrInvalidKeyFile       = errors.New("invalid key file")
+)
+
+// Key is a single signing or verification key identified by its kid
+type Key struct {
+	Id        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the key used for signing new tokens and every key still accepted for verification
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// NewKeySet creates a key set that signs with the key identified by activeKeyId
+func NewKeySet(activeKeyId string, keys ...*Key) (*KeySet, error) {
+	keySet := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		keySet.keys[key.Id] = key
+	}
+
+	active, ok := keySet.keys[activeKeyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyId, activeKeyId)
+	}
+
+	if active.signKey == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	keySet.active = active
+
+	return keySet, nil
+}
+
+// NewHMACKeySet creates a key set with a single shared HS256 secret and an empty kid
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+
+	return &KeySet{active: key, keys: map[string]*Key{key.Id: key}}
+}
+
+// LoadKeySet reads PEM encoded keys from a comma separated list of "kid:path" pairs.
+// Files holding a private key can sign and verify, files holding a public key only verify,
+// which allows an old key to stay trusted until the tokens it signed have expired
+func LoadKeySet(algorithm, activeKeyId, keyFiles string) (*KeySet, error) {
+	method, err := signingMethod(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, entry := range strings.Split(keyFiles, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("%w: expected kid:path, got %q", ErrInvalidKeyFile, entry)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %q: %w", kid, err)
+		}
+
+		key, err := ParseKey(kid, method, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %q: %w", kid, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(activeKeyId, keys...)
+}
+
+// ParseKey parses a PEM encoded private or public key for the given signing method
+func ParseKey(kid string, method jwt.SigningMethod, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKeyFile
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidKeyFile, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{Id: kid, Method: method}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.signKey, key.verifyKey = k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.verifyKey = k
+	case ed25519.PrivateKey:
+		key.signKey, key.verifyKey = k, k.Public().(ed25519.PublicKey)
+	case ed25519.PublicKey:
+		key.verifyKey = k
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidKeyFile, parsed)
+	}
+
+	if !keyMatchesMethod(key.verifyKey, method) {
+		return nil, fmt.Errorf("%w: key type does not match %s", ErrInvalidKeyFile, method.Alg())
+	}
+
+	return key, nil
+}
+
+// signingKey returns the active key, used to sign new tokens
+func (s *KeySet) signingKey() *Key {
+	return s.active
+}
+
+// verificationKey looks up the key that signed a token by its kid
+func (s *KeySet) verificationKey(kid string) (*Key, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyId
+	}
+
+	return key, nil
+}
+
+// signingMethod maps an algorithm name to its asymmetric jwt signing method
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return EdDSA, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// keyMatchesMethod reports whether a public key can be used with the signing method
+func keyMatchesMethod(publicKey crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return method.Alg() == AlgorithmRS256
+	case ed25519.PublicKey:
+		return method.Alg() == AlgorithmEdDSA
+	default:
+		return false
+	}
+}
@@ -0,0 +1,317 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/berikulyBeket/todo-plus/pkg/token"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rsaKeyPEM generates an RSA key pair and returns its PEM encoded private and public keys
+func rsaKeyPEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+}
+
+// ed25519KeyPEM generates an Ed25519 key pair and returns its PEM encoded private and public keys
+func ed25519KeyPEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+}
+
+// parseKey parses a PEM encoded key and fails the test on error
+func parseKey(t *testing.T, kid string, method jwt.SigningMethod, data []byte) *token.Key {
+	t.Helper()
+
+	key, err := token.ParseKey(kid, method, data)
+	require.NoError(t, err)
+
+	return key
+}
+
+// newMaker creates a JWTMaker signing with the active key of the given keys
+func newMaker(t *testing.T, activeKeyId string, keys ...*token.Key) token.TokenMaker {
+	t.Helper()
+
+	keySet, err := token.NewKeySet(activeKeyId, keys...)
+	require.NoError(t, err)
+
+	return token.NewWithKeySet(keySet, time.Minute, time.Hour)
+}
+
+// TestSignAndVerify tests that tokens signed with a key of the set are verified with its public key
+func TestSignAndVerify(t *testing.T) {
+	rsaPrivate, _ := rsaKeyPEM(t)
+	edPrivate, _ := ed25519KeyPEM(t)
+
+	testCases := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    []byte
+	}{
+		{
+			name:   "RS256",
+			method: jwt.SigningMethodRS256,
+			key:    rsaPrivate,
+		},
+		{
+			name:   "EdDSA",
+			method: token.EdDSA,
+			key:    edPrivate,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			maker := newMaker(t, "key-1", parseKey(t, "key-1", testCase.method, testCase.key))
+
+			signed, payload, err := maker.CreateToken(7, "admin")
+			require.NoError(t, err)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(signed, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.method.Alg(), parsed.Header["alg"])
+			assert.Equal(t, "key-1", parsed.Header["kid"])
+
+			verified, err := maker.VerifyToken(signed)
+			require.NoError(t, err)
+			assert.Equal(t, payload.TokenId, verified.TokenId)
+			assert.Equal(t, 7, verified.UserId)
+			assert.Equal(t, "admin", verified.Role)
+		})
+	}
+}
+
+// TestVerifyAlgorithmMismatch tests that a token is rejected when its alg is not the algorithm of the key of its kid
+func TestVerifyAlgorithmMismatch(t *testing.T) {
+	rsaPrivate, rsaPublic := rsaKeyPEM(t)
+	edPrivate, _ := ed25519KeyPEM(t)
+
+	block, _ := pem.Decode(edPrivate)
+	edKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	maker := newMaker(t, "rsa", parseKey(t, "rsa", jwt.SigningMethodRS256, rsaPrivate))
+
+	testCases := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    interface{}
+	}{
+		{
+			name:   "EdDSA token for an RS256 key",
+			method: token.EdDSA,
+			key:    edKey,
+		},
+		{
+			name:   "HS256 token keyed with the RSA public key",
+			method: jwt.SigningMethodHS256,
+			key:    rsaPublic,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			forged := jwt.NewWithClaims(testCase.method, jwt.MapClaims{"user_id": 7, "exp": time.Now().Add(time.Minute).Unix()})
+			forged.Header["kid"] = "rsa"
+			signed, err := forged.SignedString(testCase.key)
+			require.NoError(t, err)
+
+			_, err = maker.VerifyToken(signed)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestVerifyUnknownKeyId tests that a token signed with a key outside of the set is rejected
+func TestVerifyUnknownKeyId(t *testing.T) {
+	firstPrivate, _ := ed25519KeyPEM(t)
+	secondPrivate, _ := ed25519KeyPEM(t)
+
+	issuer := newMaker(t, "other", parseKey(t, "other", token.EdDSA, firstPrivate))
+	verifier := newMaker(t, "key-1", parseKey(t, "key-1", token.EdDSA, secondPrivate))
+
+	signed, _, err := issuer.CreateToken(7, "user")
+	require.NoError(t, err)
+
+	_, err = verifier.VerifyToken(signed)
+
+	var validationErr *jwt.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, token.ErrUnknownKeyId, validationErr.Inner)
+}
+
+// TestVerifyRetiredKey tests that tokens signed with a retired key verify while its public key stays in the set
+func TestVerifyRetiredKey(t *testing.T) {
+	oldPrivate, oldPublic := ed25519KeyPEM(t)
+	newPrivate, _ := ed25519KeyPEM(t)
+
+	oldMaker := newMaker(t, "2024-01", parseKey(t, "2024-01", token.EdDSA, oldPrivate))
+	signed, _, err := oldMaker.CreateToken(7, "user")
+	require.NoError(t, err)
+
+	overlapMaker := newMaker(t, "2024-02",
+		parseKey(t, "2024-01", token.EdDSA, oldPublic),
+		parseKey(t, "2024-02", token.EdDSA, newPrivate),
+	)
+
+	payload, err := overlapMaker.VerifyToken(signed)
+	require.NoError(t, err)
+	assert.Equal(t, 7, payload.UserId)
+
+	rotatedMaker := newMaker(t, "2024-02", parseKey(t, "2024-02", token.EdDSA, newPrivate))
+
+	_, err = rotatedMaker.VerifyToken(signed)
+	assert.Error(t, err)
+}
+
+// TestNewKeySet tests the validation of the active key of a key set
+func TestNewKeySet(t *testing.T) {
+	edPrivate, edPublic := ed25519KeyPEM(t)
+
+	testCases := []struct {
+		name        string
+		activeKeyId string
+		keys        []*token.Key
+		expectedErr error
+	}{
+		{
+			name:        "Active key signs",
+			activeKeyId: "key-1",
+			keys:        []*token.Key{parseKey(t, "key-1", token.EdDSA, edPrivate)},
+			expectedErr: nil,
+		},
+		{
+			name:        "Unknown active key",
+			activeKeyId: "key-2",
+			keys:        []*token.Key{parseKey(t, "key-1", token.EdDSA, edPrivate)},
+			expectedErr: token.ErrUnknownKeyId,
+		},
+		{
+			name:        "Active key without private key",
+			activeKeyId: "key-1",
+			keys:        []*token.Key{parseKey(t, "key-1", token.EdDSA, edPublic)},
+			expectedErr: token.ErrNoSigningKey,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := token.NewKeySet(testCase.activeKeyId, testCase.keys...)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+// TestParseKey tests that keys are rejected when they are not PEM encoded or do not match the signing method
+func TestParseKey(t *testing.T) {
+	rsaPrivate, _ := rsaKeyPEM(t)
+	edPrivate, _ := ed25519KeyPEM(t)
+
+	testCases := []struct {
+		name   string
+		method jwt.SigningMethod
+		data   []byte
+	}{
+		{
+			name:   "Ed25519 key for RS256",
+			method: jwt.SigningMethodRS256,
+			data:   edPrivate,
+		},
+		{
+			name:   "RSA key for EdDSA",
+			method: token.EdDSA,
+			data:   rsaPrivate,
+		},
+		{
+			name:   "Not PEM encoded",
+			method: token.EdDSA,
+			data:   []byte("not a key"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := token.ParseKey("key-1", testCase.method, testCase.data)
+
+			assert.ErrorIs(t, err, token.ErrInvalidKeyFile)
+		})
+	}
+}
+
+// TestLoadKeySet tests loading keys from kid:path pairs
+func TestLoadKeySet(t *testing.T) {
+	oldPrivate, oldPublic := ed25519KeyPEM(t)
+	newPrivate, _ := ed25519KeyPEM(t)
+
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.pem")
+	newPath := filepath.Join(dir, "new.pem")
+	require.NoError(t, os.WriteFile(oldPath, oldPublic, 0o600))
+	require.NoError(t, os.WriteFile(newPath, newPrivate, 0o600))
+
+	keySet, err := token.LoadKeySet(token.AlgorithmEdDSA, "new", "old:"+oldPath+", new:"+newPath)
+	require.NoError(t, err)
+
+	oldMaker := newMaker(t, "old", parseKey(t, "old", token.EdDSA, oldPrivate))
+	signed, _, err := oldMaker.CreateToken(7, "user")
+	require.NoError(t, err)
+
+	_, err = token.NewWithKeySet(keySet, time.Minute, time.Hour).VerifyToken(signed)
+	assert.NoError(t, err)
+
+	_, err = token.LoadKeySet(token.AlgorithmHS256, "new", "new:"+newPath)
+	assert.ErrorIs(t, err, token.ErrUnsupportedAlgorithm)
+
+	_, err = token.LoadKeySet(token.AlgorithmEdDSA, "new", "new")
+	assert.ErrorIs(t, err, token.ErrInvalidKeyFile)
+}
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	// PersonalAccessTokenPrefix marks personal access tokens so they can be told apart from JWTs
+	PersonalAccessTokenPrefix = "tdp_"
+
+	personalAccessTokenBytes = 32
+)
+
+// NewPersonalAccessToken generates a random prefixed personal access token
+func NewPersonalAccessToken() (string, error) {
+	b := make([]byte, personalAccessTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IsPersonalAccessToken reports whether the bearer token is a personal access token
+func IsPersonalAccessToken(tokenString string) bool {
+	return strings.HasPrefix(tokenString, PersonalAccessTokenPrefix)
+}
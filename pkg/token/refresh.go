@@ -0,0 +1,39 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	refreshTokenBytes = 32
+	tokenIdBytes      = 16
+)
+
+// HashToken returns the SHA256 hex digest of an opaque token, only digests are persisted
+func HashToken(opaqueToken string) string {
+	sum := sha256.Sum256([]byte(opaqueToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewTokenId generates a random identifier suitable for token ids and token families
+func NewTokenId() (string, error) {
+	b := make([]byte, tokenIdBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// newRefreshToken generates a random opaque refresh token
+func newRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
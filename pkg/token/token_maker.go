@@ -1,7 +1,20 @@
 package token
 
+import "time"
+
 // TokenMaker defines an interface for managing token creation and validation
 type TokenMaker interface {
-	CreateToken(userId int) (string, error)
-	VerifyToken(tokenString string) (int, error)
+	CreateToken(userId int, role string) (string, *Payload, error)
+	VerifyToken(tokenString string) (*Payload, error)
+	CreateRefreshToken() (string, time.Time, error)
+	JWKS() JWKS
+}
+
+// Payload holds the data carried by a verified access token
+type Payload struct {
+	TokenId   string
+	UserId    int
+	Role      string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
 }
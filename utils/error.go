@@ -9,4 +9,88 @@ var (
 	ErrListEmptyRequest = errors.New("list update structure has no values")
 	ErrItemNotFound     = errors.New("item not found")
 	ErrItemEmptyRequest = errors.New("item update structure has no values")
+
+	ErrInvalidRecurrence           = errors.New("invalid or unsupported recurrence rule")
+	ErrInvalidTimezone             = errors.New("unknown timezone")
+	ErrInvalidItemEditScope        = errors.New("scope must be this or future")
+	ErrRecurrenceWithoutDueDate    = errors.New("recurring items need a due date")
+	ErrRecurrenceChangeNeedsFuture = errors.New("the recurrence of a series can only be changed for all future occurrences")
+
+	ErrItemParentNotInList = errors.New("the parent item must belong to the same list")
+	ErrItemParentCycle     = errors.New("an item cannot be a subtask of itself or of one of its subtasks")
+
+	ErrMoveAnchorRequired = errors.New("after_id or before_id is required")
+	ErrMoveAnchorIsSelf   = errors.New("a row cannot be moved next to itself")
+	ErrMoveAnchorNotFound = errors.New("the anchors must belong to the same order as the moved row")
+	ErrMoveAnchorsOrder   = errors.New("after_id must precede before_id")
+
+	ErrTransferItemsEmpty   = errors.New("item_ids must not be empty")
+	ErrTooManyTransferItems = errors.New("at most 100 items can be moved or copied at once")
+
+	ErrInvalidBulkOperation = errors.New("operation must be update, delete or move")
+	ErrBulkItemsEmpty       = errors.New("item_ids must not be empty")
+	ErrTooManyBulkItems     = errors.New("at most 100 items can be changed at once")
+	ErrBulkListRequired     = errors.New("list_id is required to move items")
+
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+	ErrPersonalAccessTokenExpired  = errors.New("personal access token has expired")
+	ErrInvalidScope                = errors.New("unknown scope")
+	ErrInvalidExpiration           = errors.New("expiration must be in the future")
+
+	ErrAppNotFound       = errors.New("app not found")
+	ErrAppEmptyRequest   = errors.New("app update structure has no values")
+	ErrInvalidAccessType = errors.New("unknown access type")
+	ErrInvalidOverlap    = errors.New("invalid key rotation overlap")
+
+	ErrInvalidRole = errors.New("unknown role")
+
+	ErrListPermissionDenied = errors.New("user does not have the required role on the list")
+	ErrInvalidListRole      = errors.New("unknown list role")
+	ErrCollaboratorExists   = errors.New("user is already a collaborator of the list")
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrLastListOwner        = errors.New("list must keep at least one owner")
+
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationExpired    = errors.New("invitation has expired")
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagExists       = errors.New("tag with this name already exists")
+	ErrTagEmptyRequest = errors.New("tag update structure has no values")
+	ErrInvalidTagName  = errors.New("tag name must have 1 to 64 characters and no commas")
+	ErrInvalidTagColor = errors.New("tag color must be a hex color such as #ff8800")
+
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	ErrCommentNotFound         = errors.New("comment not found")
+	ErrCommentParentNotFound   = errors.New("parent comment does not belong to the item")
+	ErrCommentNotAuthor        = errors.New("comment can only be changed by its author")
+	ErrInvalidCommentBody      = errors.New("comment body must have 1 to 5000 characters")
+	ErrInvalidCommentPageLimit = errors.New("limit must be between 1 and 100")
+	ErrInvalidCommentCursor    = errors.New("after must be a valid comment Id")
+
+	ErrAttachmentNotFound        = errors.New("attachment not found")
+	ErrAttachmentTooLarge        = errors.New("attachment exceeds the maximum size")
+	ErrAttachmentEmpty           = errors.New("attachment is empty")
+	ErrAttachmentTypeNotAllowed  = errors.New("attachment type is not allowed")
+	ErrInvalidAttachmentFileName = errors.New("attachment file name must have 1 to 255 characters")
+
+	ErrListTemplateNotFound = errors.New("list template not found")
+	ErrInvalidListTitle     = errors.New("title must have 1 to 255 characters")
+
+	ErrInvalidPageLimit  = errors.New("limit must be between 1 and 100")
+	ErrInvalidPageCursor = errors.New("cursor is invalid or was issued for another sort")
+	ErrInvalidSort       = errors.New("sort is not supported by this collection")
+	ErrInvalidSortOrder  = errors.New("order must be asc or desc")
+
+	ErrVersionMismatch = errors.New("resource was modified since the version given in If-Match")
+
+	ErrInvalidIdempotencyKey    = errors.New("Idempotency-Key must have 1 to 255 characters")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyReused     = errors.New("Idempotency-Key was already used with a different request")
 )
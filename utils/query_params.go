@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -61,3 +62,21 @@ func ParseOptionalParamAsBool(c *gin.Context, key string, optionalParam **bool)
 
 	return nil
 }
+
+// ParseOptionalParamAsList parses an optional comma separated query parameter into its distinct non-empty values
+func ParseOptionalParamAsList(c *gin.Context, key string) []string {
+	values := []string{}
+	seen := map[string]bool{}
+
+	for _, value := range strings.Split(c.Query(key), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		values = append(values, value)
+	}
+
+	return values
+}
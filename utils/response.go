@@ -23,6 +23,14 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Page response structure, NextCursor is null on the last page
+type PageResponse struct {
+	Status     string      `json:"status"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	NextCursor *string     `json:"next_cursor"`
+}
+
 // Sends an error response
 func NewErrorResponse(c *gin.Context, statusCode int, message string, errors map[string]string) {
 	c.AbortWithStatusJSON(statusCode, ErrorResponse{
@@ -40,3 +48,13 @@ func NewSuccessResponse(c *gin.Context, statusCode int, message string, data int
 		Data:    data,
 	})
 }
+
+// Sends a page of a collection with the cursor of the next page
+func NewPageResponse(c *gin.Context, statusCode int, message string, data interface{}, nextCursor *string) {
+	c.JSON(statusCode, PageResponse{
+		Status:     statusOk,
+		Message:    message,
+		Data:       data,
+		NextCursor: nextCursor,
+	})
+}